@@ -1,11 +1,11 @@
 package cmd
 
 import (
-	"context"
 	"errors"
 	"fmt"
 
 	"cowpoke/internal/commands"
+	"cowpoke/internal/domain"
 
 	"github.com/spf13/cobra"
 )
@@ -14,20 +14,39 @@ import (
 var addCmd = &cobra.Command{
 	Use:   "add",
 	Short: "Add a new Rancher server to the configuration",
-	Long:  `Add a new Rancher server with the specified URL, username, and authentication type.`,
-	RunE:  runAdd,
+	Long: `Add a new Rancher server with the specified URL, username, and authentication type.
+
+With --from-file, add every server listed in a YAML or CSV file instead. Each entry is checked and
+added on its own; entries that fail are reported without undoing the ones that were added.`,
+	RunE: runAdd,
 }
 
 //nolint:gochecknoinits // Cobra CLI pattern for command registration
 func init() {
 	rootCmd.AddCommand(addCmd)
 
-	addCmd.Flags().StringP("url", "u", "", "Rancher server URL (required)")
-	addCmd.Flags().StringP("username", "n", "", "Username for authentication (required)")
-	addCmd.Flags().StringP("authtype", "a", "local", "Authentication type")
+	addCmd.Flags().StringP("url", "u", "", "Rancher server URL (required unless --from-file is used)")
+	addCmd.Flags().StringP("username", "n", "", "Username for authentication (required unless --from-file is used)")
+	addCmd.Flags().StringP("authtype", "a", "",
+		`Authentication type (detected from the server when omitted, "local" with --no-verify)`)
+	addCmd.Flags().Bool("insecure", false, "Skip TLS certificate verification when checking the server version")
+	addCmd.Flags().Bool("skip-version-check", false, "Add the server without checking its Rancher version")
+	addCmd.Flags().Bool("no-verify", false,
+		"Add the server without checking that it is reachable and offers the authentication type")
+	addCmd.Flags().String("client-cert", "", "Client certificate (PEM) for a gateway requiring mutual TLS")
+	addCmd.Flags().String("client-key", "", "Client certificate key (PEM) for a gateway requiring mutual TLS")
+	addCmd.Flags().String("tunnel", "",
+		`Reach the server through a SOCKS5 proxy ("socks5://host:port") or SSH ("ssh user@bastion")`)
+	addCmd.Flags().StringSlice("tag", nil, "Label the server with a tag (can be repeated)")
+	addCmd.Flags().String("alias", "", "Short name for the server that its contexts can be grouped under")
+	addCmd.Flags().Int("priority", 0, "Sync priority group; lower priorities are synced first, servers without one last")
+	addCmd.Flags().String("from-file", "", "Add all servers listed in a YAML or CSV file")
 
-	_ = addCmd.MarkFlagRequired("url")
-	_ = addCmd.MarkFlagRequired("username")
+	for _, flag := range []string{
+		"url", "username", "authtype", "client-cert", "client-key", "tunnel", "tag", "alias", "priority",
+	} {
+		addCmd.MarkFlagsMutuallyExclusive("from-file", flag)
+	}
 }
 
 func runAdd(cmd *cobra.Command, _ []string) error {
@@ -39,20 +58,118 @@ func runAdd(cmd *cobra.Command, _ []string) error {
 	url, _ := cmd.Flags().GetString("url")
 	username, _ := cmd.Flags().GetString("username")
 	authType, _ := cmd.Flags().GetString("authtype")
+	noVerify, _ := cmd.Flags().GetBool("no-verify")
+	clientCert, _ := cmd.Flags().GetString("client-cert")
+	clientKey, _ := cmd.Flags().GetString("client-key")
+	tunnel, _ := cmd.Flags().GetString("tunnel")
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	alias, _ := cmd.Flags().GetString("alias")
+	priority, _ := cmd.Flags().GetInt("priority")
+	fromFile, _ := cmd.Flags().GetString("from-file")
+
+	if fromFile != "" {
+		return runAddFromFile(cmd, fromFile)
+	}
+	if url == "" || username == "" {
+		return errors.New("--url and --username are required unless --from-file is used")
+	}
+
+	// Probe the normalized URL so the checks hit the same address that is stored.
+	if normalized, normalizeErr := domain.NormalizeServerURL(url); normalizeErr == nil {
+		url = normalized
+	}
 
-	addCommand := commands.NewAddCommand(
-		app.ConfigRepo,
-		app.Logger,
-	)
-	err := addCommand.Execute(context.Background(), commands.AddRequest{
-		URL:      url,
-		Username: username,
-		AuthType: authType,
+	if authType == "" && noVerify {
+		authType = "local"
+	}
+
+	server := domain.ConfigServer{URL: url, ClientCert: clientCert, ClientKey: clientKey, Tunnel: tunnel}
+	addCommand := newAddCommand(cmd, server)
+	err := addCommand.Execute(cmd.Context(), commands.AddRequest{
+		URL:        url,
+		Username:   username,
+		AuthType:   authType,
+		ClientCert: clientCert,
+		ClientKey:  clientKey,
+		Tunnel:     tunnel,
+		Tags:       tags,
+		Alias:      alias,
+		Priority:   priority,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to add server: %w", err)
 	}
 
-	fmt.Fprintf(cmd.OutOrStdout(), "Successfully added Rancher server: %s\n", url)
+	fmt.Fprintf(cmd.OutOrStdout(), "Successfully added Rancher server: %s%s\n", url,
+		profileSuffix(app.ConfigRepo.Profile()))
+	return nil
+}
+
+// runAddFromFile adds the servers listed in path and prints one line per entry.
+func runAddFromFile(cmd *cobra.Command, path string) error {
+	app := GetApp()
+	servers, err := app.ReadServerList(path)
+	if err != nil {
+		return err
+	}
+
+	noVerify, _ := cmd.Flags().GetBool("no-verify")
+	reqs := make([]commands.AddRequest, len(servers))
+	for i, server := range servers {
+		if server.AuthType == "" && noVerify {
+			server.AuthType = "local"
+		}
+		reqs[i] = commands.AddRequest{
+			URL:        server.URL,
+			Username:   server.Username,
+			AuthType:   server.AuthType,
+			ClientCert: server.ClientCert,
+			ClientKey:  server.ClientKey,
+			Tunnel:     server.Tunnel,
+			Tags:       server.Tags,
+			Alias:      server.Alias,
+			Priority:   server.Priority,
+		}
+	}
+
+	results := newAddCommand(cmd, servers...).ExecuteBatch(cmd.Context(), reqs)
+
+	out := cmd.OutOrStdout()
+	style := newStyle(out)
+	var failed int
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			fmt.Fprintf(out, "%s %d. %s: %v\n", style.failure("✗"), result.Entry, result.URL, result.Err)
+			continue
+		}
+		fmt.Fprintf(out, "%s %d. %s\n", style.success("✓"), result.Entry, result.URL)
+	}
+
+	fmt.Fprintf(out, "\nAdded %d of %d servers%s\n", len(results)-failed, len(results),
+		profileSuffix(app.ConfigRepo.Profile()))
+	if failed > 0 {
+		return fmt.Errorf("failed to add %d of %d servers from %s", failed, len(results), path)
+	}
 	return nil
 }
+
+// newAddCommand creates the add command with the checks selected by the flags. The client certificates and
+// tunnels of servers apply to the checks of those servers.
+func newAddCommand(cmd *cobra.Command, servers ...domain.ConfigServer) *commands.AddCommand {
+	app := GetApp()
+	insecureSkipTLS, _ := cmd.Flags().GetBool("insecure")
+	skipVersionCheck, _ := cmd.Flags().GetBool("skip-version-check")
+	noVerify, _ := cmd.Flags().GetBool("no-verify")
+
+	var opts []commands.AddOption
+	if !noVerify {
+		client := app.CreateRancherClient(insecureSkipTLS, servers...)
+		opts = append(opts, commands.WithVerification(client), commands.WithAuthTypeChooser(app.Chooser))
+		if !skipVersionCheck {
+			opts = append(opts, commands.WithVersionCheck(client))
+		}
+	}
+
+	return commands.NewAddCommand(app.ConfigRepo, app.Logger, opts...)
+}
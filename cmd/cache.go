@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"cowpoke/internal/commands"
+
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage cached kubeconfig fragments",
+	Long:  `Inspect and clean the per-cluster kubeconfigs cached by sync in ~/.config/cowpoke/kubeconfigs.`,
+}
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var cacheCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove stale cached kubeconfig fragments",
+	Long: `Remove cached kubeconfig fragments belonging to servers that are no longer configured
+or that have not been refreshed within the retention period (settings.fragments.retentionDays).`,
+	RunE: runCacheClean,
+}
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List cached kubeconfig fragments",
+	Long:  `List cached kubeconfig fragments with the server they came from, their age, and their size.`,
+	RunE:  runCacheList,
+}
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var cachePathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the kubeconfig cache directory",
+	RunE:  runCachePath,
+}
+
+//nolint:gochecknoinits // Cobra CLI pattern for command registration
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheCleanCmd)
+	cacheCmd.AddCommand(cacheListCmd)
+	cacheCmd.AddCommand(cachePathCmd)
+
+	cacheCleanCmd.Flags().Bool("all", false, "Remove every cached fragment")
+	cacheCleanCmd.Flags().
+		Duration("max-age", 0, "Remove fragments older than this duration (default: configured retention)")
+}
+
+func runCacheClean(cmd *cobra.Command, _ []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	all, _ := cmd.Flags().GetBool("all")
+	maxAge, _ := cmd.Flags().GetDuration("max-age")
+
+	cleanCommand := commands.NewCacheCleanCommand(
+		app.ConfigRepo,
+		app.FragmentCache,
+		app.Logger,
+	)
+	result, err := cleanCommand.Execute(cmd.Context(), commands.CacheCleanRequest{
+		All:    all,
+		MaxAge: maxAge,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clean cache: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Removed %d cached kubeconfig(s), kept %d\n", len(result.Removed), result.Kept)
+	return nil
+}
+
+func runCacheList(cmd *cobra.Command, _ []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	listCommand := commands.NewCacheListCommand(app.FragmentCache, app.Logger)
+	result, err := listCommand.Execute(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("failed to list cache: %w", err)
+	}
+
+	if len(result.Entries) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "No cached kubeconfigs in %s\n", result.Dir)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0) //nolint:mnd // Column padding
+	fmt.Fprintln(w, "CLUSTER\tTYPE\tSERVER\tAGE\tSIZE\tFILE")
+	for _, entry := range result.Entries {
+		server := entry.ServerURL
+		if server == "" {
+			server = entry.ServerID
+		}
+		clusterType := entry.ClusterType
+		if clusterType == "" {
+			clusterType = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			entry.ClusterName,
+			clusterType,
+			server,
+			formatAge(time.Since(entry.DownloadedAt)),
+			formatBytes(entry.Size),
+			entry.File)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "\n%d cached kubeconfig(s), %s total in %s\n",
+		len(result.Entries), formatBytes(result.TotalSize), result.Dir)
+	return nil
+}
+
+func runCachePath(cmd *cobra.Command, _ []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), app.FragmentCache.Dir())
+	return nil
+}
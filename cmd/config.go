@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"cowpoke/internal/commands"
+
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and protect the cowpoke configuration file",
+}
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration file against the current schema",
+	Long: `Check the configuration file for unknown fields, invalid values, malformed server URLs,
+unsupported authentication types and duplicate servers. Problems are reported with their line and column.`,
+	RunE: runConfigValidate,
+}
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var configEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt the configuration file at rest",
+	Long: `Encrypt the configuration file with AES-256-GCM so server URLs and usernames are not stored in plain text.
+Cowpoke decrypts it transparently on every run.
+
+The key is derived from the passphrase in COWPOKE_CONFIG_KEY, or read from the file named by
+COWPOKE_CONFIG_KEY_FILE. Without either, a random key is generated and stored in the OS keychain.`,
+	RunE: runConfigEncrypt,
+}
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var configDecryptCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Store the configuration file in plain text again",
+	RunE:  runConfigEncrypt,
+}
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var configHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List the backups taken before each change to the configuration file",
+	Long: `List the backups of the configuration file, newest first. A backup of the file is taken before every
+change to it, such as adding or removing a server, and the oldest are removed beyond settings.backups.keep.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigHistory,
+}
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var configRestoreCmd = &cobra.Command{
+	Use:   "restore <timestamp>",
+	Short: "Roll the configuration file back to a backup",
+	Long: `Replace the configuration file with the backup taken at the timestamp listed by cowpoke config history,
+or with the only backup whose timestamp starts with the one given. The file replaced is backed up first, so
+a restore can be undone the same way.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigRestore,
+}
+
+//nolint:gochecknoinits // Cobra CLI pattern for command registration
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configEncryptCmd)
+	configCmd.AddCommand(configDecryptCmd)
+	configCmd.AddCommand(configHistoryCmd)
+	configCmd.AddCommand(configRestoreCmd)
+
+	configValidateCmd.Flags().String("file", "", "Configuration file to validate (default: the active config file)")
+}
+
+func runConfigValidate(cmd *cobra.Command, _ []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	file, _ := cmd.Flags().GetString("file")
+
+	validateCommand := commands.NewConfigValidateCommand(
+		app.FileSystem,
+		app.ConfigProvider,
+		app.ConfigValidator,
+		app.Logger,
+		commands.WithConfigEncryptor(app.ConfigEncryptor),
+	)
+	result, err := validateCommand.Execute(cmd.Context(), commands.ConfigValidateRequest{Path: file})
+	if err != nil {
+		return fmt.Errorf("failed to validate config: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	for _, issue := range result.Issues {
+		fmt.Fprintf(out, "%s:%d:%d: %s: %s\n", result.Path, issue.Line, issue.Column, issue.Severity, issue.Message)
+	}
+
+	if result.HasErrors() {
+		return fmt.Errorf("%s is invalid: %d issue(s) found", result.Path, len(result.Issues))
+	}
+	if len(result.Issues) > 0 {
+		fmt.Fprintf(out, "%s is valid with %d warning(s)\n", result.Path, len(result.Issues))
+		return nil
+	}
+	fmt.Fprintf(out, "%s is valid\n", result.Path)
+	return nil
+}
+
+func runConfigEncrypt(cmd *cobra.Command, _ []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	decrypt := cmd.Name() == "decrypt"
+	encryptCommand := commands.NewConfigEncryptCommand(app.ConfigRepo, app.ConfigProvider, app.Logger)
+	result, err := encryptCommand.Execute(cmd.Context(), commands.ConfigEncryptRequest{Decrypt: decrypt})
+	if err != nil {
+		return fmt.Errorf("failed to %s config: %w", cmd.Name(), err)
+	}
+
+	state := "encrypted"
+	if !result.Encrypted {
+		state = "stored in plain text"
+	}
+	if !result.Changed {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s is already %s\n", result.Path, state)
+		return nil
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s is now %s\n", result.Path, newStyle(cmd.OutOrStdout()).success(state))
+	return nil
+}
+
+func runConfigHistory(cmd *cobra.Command, _ []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	result, err := commands.NewConfigHistoryCommand(app.ConfigRepo, app.Logger).Execute(cmd.Context())
+	if err != nil {
+		return err
+	}
+	if len(result.Backups) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No configuration backups yet")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0) //nolint:mnd // Column padding
+	fmt.Fprintln(w, "TIMESTAMP\tAGE\tSIZE\tFILE")
+	for _, backup := range result.Backups {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			backup.Timestamp,
+			formatAge(time.Since(backup.Time)),
+			formatBytes(backup.Size),
+			backup.Path)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
+
+func runConfigRestore(cmd *cobra.Command, args []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	restoreCommand := commands.NewConfigRestoreCommand(app.ConfigRepo, app.Logger)
+	result, err := restoreCommand.Execute(cmd.Context(), commands.ConfigRestoreRequest{Timestamp: args[0]})
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	style := newStyle(out)
+	fmt.Fprintln(out, style.success(fmt.Sprintf("Restored the configuration backed up at %s",
+		result.Backup.Time.Local().Format(time.DateTime))))
+	for _, server := range result.Added {
+		fmt.Fprintf(out, "  + %s\n", server.URL)
+	}
+	for _, server := range result.Removed {
+		fmt.Fprintf(out, "  - %s\n", server.URL)
+	}
+	return nil
+}
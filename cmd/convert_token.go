@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"cowpoke/internal/commands"
+
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var convertToTokenCmd = &cobra.Command{
+	Use:   "convert-to-token <url|id>",
+	Short: "Switch a server to a long-lived API token so syncs never ask for its password",
+	Long: `Log in to a configured Rancher server with your password once, create a Rancher API token that does
+not expire (unless the server caps token lifetimes), store it in the OS keychain and switch the server's
+auth type to "token". Later syncs use the token and never prompt for the server's password.
+
+To replace the token of a server that already uses one, pass --authtype with the provider to log in with.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConvertToToken,
+}
+
+//nolint:gochecknoinits // Cobra CLI pattern for command registration
+func init() {
+	rootCmd.AddCommand(convertToTokenCmd)
+
+	convertToTokenCmd.Flags().String("authtype", "", "Authentication type to log in with (default: the server's)")
+	convertToTokenCmd.Flags().Bool("insecure", false, "Skip TLS certificate verification")
+}
+
+func runConvertToToken(cmd *cobra.Command, args []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	authType, _ := cmd.Flags().GetString("authtype")
+	insecureSkipTLS, _ := cmd.Flags().GetBool("insecure")
+
+	convertCommand := commands.NewConvertToTokenCommand(
+		app.ConfigRepo,
+		app.PasswordReader,
+		app.CreateRancherClient(insecureSkipTLS),
+		app.TokenCache,
+		app.Logger,
+	)
+
+	result, err := convertCommand.Execute(cmd.Context(), commands.ConvertToTokenRequest{
+		Server:   args[0],
+		AuthType: authType,
+	})
+	if err != nil {
+		return fmt.Errorf("conversion failed: %w", err)
+	}
+
+	if result.ExpiresAt.IsZero() {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s now authenticates with an API token that never expires\n",
+			result.ServerURL)
+		return nil
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s now authenticates with an API token; it expires %s (in %s)\n",
+		result.ServerURL, result.ExpiresAt.Local().Format(time.DateTime), formatAge(time.Until(result.ExpiresAt)))
+	return nil
+}
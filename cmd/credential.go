@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"cowpoke/internal/commands"
+
+	"github.com/spf13/cobra"
+)
+
+// execCredentialAPIVersion is the client.authentication.k8s.io version of the ExecCredential printed.
+const execCredentialAPIVersion = "client.authentication.k8s.io/v1"
+
+// execCredential is the ExecCredential object client-go reads from a credential plugin's stdout.
+type execCredential struct {
+	APIVersion string               `json:"apiVersion"`
+	Kind       string               `json:"kind"`
+	Status     execCredentialStatus `json:"status"`
+}
+
+type execCredentialStatus struct {
+	Token               string `json:"token"`
+	ExpirationTimestamp string `json:"expirationTimestamp,omitempty"`
+}
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var credentialCmd = &cobra.Command{
+	Use:   "credential",
+	Short: "Print a cluster's token as a client-go exec credential",
+	Long: `Print an ExecCredential holding a valid token for a cluster, for the exec credential plugins of
+kubeconfigs synced with settings.sync.execCredentials. The token of the cached kubeconfig is used while it
+remains valid; otherwise a fresh kubeconfig is downloaded from Rancher, logging in if no token is cached.`,
+	Args: cobra.NoArgs,
+	RunE: runCredential,
+}
+
+//nolint:gochecknoinits // Cobra CLI pattern for command registration
+func init() {
+	rootCmd.AddCommand(credentialCmd)
+	credentialCmd.Flags().
+		String("server", "", "URL or ID of the cluster's server")
+	credentialCmd.Flags().
+		String("cluster", "", "Rancher ID of the cluster")
+	credentialCmd.Flags().
+		Bool("insecure", false, "Skip TLS certificate verification for Rancher servers")
+	_ = credentialCmd.MarkFlagRequired("server")
+	_ = credentialCmd.MarkFlagRequired("cluster")
+}
+
+func runCredential(cmd *cobra.Command, _ []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	server, _ := cmd.Flags().GetString("server")
+	cluster, _ := cmd.Flags().GetString("cluster")
+	insecureSkipTLS, _ := cmd.Flags().GetBool("insecure")
+
+	rancherClient := app.CreateRancherClient(insecureSkipTLS)
+	syncOrchestrator := app.CreateSyncOrchestrator(rancherClient, 0, false, false, false)
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), syncTimeout)
+	defer cancel()
+	credentialCommand := commands.NewCredentialCommand(app.ConfigRepo, app.FragmentCache, app.TokenCache,
+		app.PasswordReader, app.KubeconfigHandler, app.Clock, app.Logger)
+	result, err := credentialCommand.Execute(ctx, commands.CredentialRequest{Server: server, ClusterID: cluster},
+		syncOrchestrator)
+	if err != nil {
+		return fmt.Errorf("failed to get credential: %w", err)
+	}
+
+	credential := execCredential{
+		APIVersion: execCredentialAPIVersion,
+		Kind:       "ExecCredential",
+		Status:     execCredentialStatus{Token: result.Token},
+	}
+	if !result.ExpiresAt.IsZero() {
+		credential.Status.ExpirationTimestamp = result.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	return json.NewEncoder(cmd.OutOrStdout()).Encode(credential)
+}
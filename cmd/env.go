@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cowpoke/internal/commands"
+
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Print the KUBECONFIG that adds cowpoke's kubeconfig to your own",
+	Long: `Print a shell command setting KUBECONFIG to cowpoke's kubeconfig followed by the kubeconfigs KUBECONFIG
+already names, or ~/.kube/config if it is not set.
+
+This is for settings.sync.separateKubeconfig, which makes cowpoke write ~/.kube/cowpoke.config and leave
+~/.kube/config alone. Add this to your shell profile so kubectl sees both:
+
+  eval "$(cowpoke env)"                  # bash, zsh
+  cowpoke env --shell fish | source      # fish
+
+Running it again in a shell where KUBECONFIG is already set changes nothing.`,
+	Args: cobra.NoArgs,
+	RunE: runEnv,
+}
+
+//nolint:gochecknoinits // Cobra CLI pattern for command registration
+func init() {
+	rootCmd.AddCommand(envCmd)
+	envCmd.Flags().
+		String("kubeconfig", "", "Kubeconfig cowpoke writes (default: ~/.kube/cowpoke.config with "+
+			"settings.sync.separateKubeconfig, otherwise ~/.kube/config)")
+	envCmd.Flags().
+		String("shell", "sh", "Shell syntax to print: sh, fish or powershell")
+}
+
+func runEnv(cmd *cobra.Command, _ []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	kubeconfig, _ := cmd.Flags().GetString("kubeconfig")
+	shell, _ := cmd.Flags().GetString("shell")
+
+	envCommand := commands.NewEnvCommand(app.ConfigRepo, app.ConfigProvider, app.Logger)
+	result, err := envCommand.Execute(cmd.Context(), commands.EnvRequest{
+		Kubeconfig: kubeconfig,
+		Current:    os.Getenv("KUBECONFIG"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resolve KUBECONFIG: %w", err)
+	}
+
+	value := strings.Join(result.Paths, string(filepath.ListSeparator))
+	switch shell {
+	case "sh", "bash", "zsh":
+		fmt.Fprintf(cmd.OutOrStdout(), "export KUBECONFIG=%s\n", quoteShell(value, `'\''`))
+	case "fish":
+		fmt.Fprintf(cmd.OutOrStdout(), "set -gx KUBECONFIG %s\n",
+			quoteShell(strings.ReplaceAll(value, `\`, `\\`), `\'`))
+	case "powershell", "pwsh":
+		fmt.Fprintf(cmd.OutOrStdout(), "$env:KUBECONFIG = %s\n", quoteShell(value, `''`))
+	default:
+		return fmt.Errorf("unsupported --shell %q: use sh, fish or powershell", shell)
+	}
+	return nil
+}
+
+// quoteShell single-quotes value, writing each single quote in it as escaped.
+func quoteShell(value, escaped string) string {
+	return "'" + strings.ReplaceAll(value, "'", escaped) + "'"
+}
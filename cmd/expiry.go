@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"cowpoke/internal/commands"
+
+	"github.com/spf13/cobra"
+)
+
+// maxExpiringNamed is how many expiring contexts the warning names before summarizing the rest.
+const maxExpiringNamed = 5
+
+// expiringContexts returns the contexts of kubeconfig whose credentials expire within the configured window.
+func expiringContexts(ctx context.Context, kubeconfig string) (*commands.ExpiryResult, error) {
+	app := GetApp()
+	expiryCommand := commands.NewExpiryCommand(app.ConfigRepo, app.ConfigProvider, app.KubeconfigHandler,
+		app.Clock, app.Logger)
+	result, err := expiryCommand.Execute(ctx, commands.ExpiryRequest{
+		Kubeconfig: kubeconfig,
+		Within:     app.Settings.Expiry.Window(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check credential expiry: %w", err)
+	}
+	return result, nil
+}
+
+// printExpiryWarning warns about contexts whose credentials expire soon, unless the warning is disabled.
+// Failures are only logged, so they never affect the command's outcome.
+func printExpiryWarning(executed *cobra.Command) {
+	if application == nil || application.Settings.Expiry.Window() == 0 {
+		return
+	}
+	switch executed.Name() {
+	case versionCmd.Name(), credentialCmd.Name(), cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return
+	}
+
+	result, err := expiringContexts(context.Background(), "")
+	if err != nil {
+		application.Logger.Debug("Could not check credential expiry", "error", err)
+		return
+	}
+	if len(result.Contexts) == 0 {
+		return
+	}
+
+	now := application.Clock.Now()
+	names := make([]string, 0, maxExpiringNamed)
+	for _, managed := range result.Contexts[:min(len(result.Contexts), maxExpiringNamed)] {
+		remaining := managed.Owner.ExpiresAt.Sub(now)
+		if remaining <= 0 {
+			names = append(names, managed.Name+" (expired)")
+			continue
+		}
+		names = append(names, fmt.Sprintf("%s (in %s)", managed.Name, formatAge(remaining)))
+	}
+	if more := len(result.Contexts) - len(names); more > 0 {
+		names = append(names, fmt.Sprintf("and %d more", more))
+	}
+
+	out := rootCmd.ErrOrStderr()
+	fmt.Fprintf(out, "%s: %s. Run 'cowpoke sync --refresh-expiring' to refresh them.\n",
+		newStyle(out).warning("Credentials expire soon"), strings.Join(names, ", "))
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"cowpoke/internal/commands"
+	"cowpoke/internal/domain"
+
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var findCmd = &cobra.Command{
+	Use:   "find <pattern>",
+	Short: "Search the contexts cowpoke manages",
+	Long: `Search the contexts cowpoke manages by context or cluster name, showing the server each belongs to
+and when it was last synced. The pattern matches exactly, as a prefix or substring, or as an abbreviation,
+and the closest matches are listed first.
+
+With --exec, a shell command is run once per match with {} replaced by the context name:
+
+  cowpoke find prod --exec 'kubectl --context {} get nodes'`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runFind,
+	ValidArgsFunction: completeContexts,
+}
+
+//nolint:gochecknoinits // Cobra CLI pattern for command registration
+func init() {
+	rootCmd.AddCommand(findCmd)
+
+	findCmd.Flags().String("kubeconfig", "", "Merged kubeconfig to search (default: ~/.kube/config)")
+	findCmd.Flags().String("exec", "", "Shell command to run for each match, with {} replaced by the context name")
+}
+
+func runFind(cmd *cobra.Command, args []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	kubeconfig, _ := cmd.Flags().GetString("kubeconfig")
+	command, _ := cmd.Flags().GetString("exec")
+
+	findCommand := commands.NewFindCommand(app.KubeconfigHandler, app.ConfigProvider, app.Logger)
+	result, err := findCommand.Execute(cmd.Context(), commands.FindRequest{
+		Pattern:    args[0],
+		Kubeconfig: kubeconfig,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to search contexts: %w", err)
+	}
+
+	if len(result.Matches) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), newStyle(cmd.OutOrStdout()).warning(
+			fmt.Sprintf("No cowpoke context matches %q.", args[0])))
+		return nil
+	}
+	if command == "" {
+		printMatches(cmd, result.Matches)
+		return nil
+	}
+	return execForMatches(cmd, command, result)
+}
+
+// printMatches lists matching contexts with the server they belong to and when they were last synced.
+func printMatches(cmd *cobra.Command, matches []domain.ManagedContext) {
+	style := newStyle(cmd.OutOrStdout())
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0) //nolint:mnd // Column padding
+	fmt.Fprintln(w, "CONTEXT\tCLUSTER\tSERVER\tSYNCED")
+	for _, match := range matches {
+		name := style.plain(match.Name)
+		if match.Current {
+			name = style.success(match.Name)
+		}
+		synced := "unknown"
+		if !match.Owner.SyncedAt.IsZero() {
+			synced = formatAge(time.Since(match.Owner.SyncedAt)) + " ago"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, match.ClusterName, match.Owner.ServerURL, synced)
+	}
+	_ = w.Flush()
+}
+
+// execForMatches runs command through the shell once per match, in order, with {} replaced by the context
+// name. Every match is attempted; the error reports how many failed.
+func execForMatches(cmd *cobra.Command, command string, result *commands.FindResult) error {
+	style := newStyle(cmd.ErrOrStderr())
+	var failed []string
+	for _, match := range result.Matches {
+		fmt.Fprintln(cmd.ErrOrStderr(), style.success("==> "+match.Name))
+
+		shell := exec.Command("sh", "-c", strings.ReplaceAll(command, "{}", match.Name))
+		shell.Stdin = cmd.InOrStdin()
+		shell.Stdout = cmd.OutOrStdout()
+		shell.Stderr = cmd.ErrOrStderr()
+		shell.Env = append(os.Environ(), "KUBECONFIG="+result.Kubeconfig)
+		if err := shell.Run(); err != nil {
+			fmt.Fprintln(cmd.ErrOrStderr(), style.failure(fmt.Sprintf("%s: %v", match.Name, err)))
+			failed = append(failed, match.Name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("command failed for %d of %d contexts: %s",
+			len(failed), len(result.Matches), strings.Join(failed, ", "))
+	}
+	return nil
+}
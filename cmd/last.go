@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"cowpoke/internal/commands"
+	"cowpoke/internal/domain"
+
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var lastCmd = &cobra.Command{
+	Use:   "last",
+	Short: "Show what the most recent sync did",
+	Long: `Show the report of the most recent sync: when it ran, where the kubeconfig was written, the
+outcome for each server, and the timing breakdown.`,
+	RunE: runLast,
+}
+
+//nolint:gochecknoinits // Cobra CLI pattern for command registration
+func init() {
+	rootCmd.AddCommand(lastCmd)
+	lastCmd.Flags().Bool("json", false, "Print the report as JSON")
+}
+
+func runLast(cmd *cobra.Command, _ []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	lastCommand := commands.NewLastCommand(app.StateStore, app.Logger)
+	report, err := lastCommand.Execute(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("failed to show last sync: %w", err)
+	}
+
+	if report == nil {
+		fmt.Fprintln(cmd.OutOrStdout(), "No sync has been recorded yet. Run 'cowpoke sync' first.")
+		return nil
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	printReport(cmd.OutOrStdout(), report)
+	return nil
+}
+
+// printCorrelationID prints the ID sent with the sync's API requests, if the report has one.
+func printCorrelationID(out io.Writer, report *commands.SyncReport) {
+	if report.CorrelationID != "" {
+		fmt.Fprintf(out, "Request ID: %s\n", report.CorrelationID)
+	}
+}
+
+// printFailures lists the kubeconfig downloads that failed.
+func printFailures(out io.Writer, failures []domain.ClusterFailure) {
+	if len(failures) == 0 {
+		return
+	}
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0) //nolint:mnd // Column padding
+	fmt.Fprintln(w, "SERVER\tCLUSTER\tCATEGORY\tERROR")
+	for _, failure := range failures {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", failure.ServerURL, failure.Cluster, failure.Category, failure.Error)
+	}
+	_ = w.Flush()
+}
+
+// printReport prints a persisted sync report.
+func printReport(out io.Writer, report *commands.SyncReport) {
+	started := report.StartedAt.Local().Format(time.DateTime)
+	ago := formatAge(time.Since(report.StartedAt))
+	took := formatDuration(report.FinishedAt.Sub(report.StartedAt))
+
+	if report.Error != "" {
+		fmt.Fprintf(out, "Last sync at %s (%s ago) failed after %s: %s\n", started, ago, took, report.Error)
+		printCorrelationID(out, report)
+		if summary := commands.ClusterFailureSummary(report.Failures); summary != "" {
+			fmt.Fprintln(out, summary)
+		}
+		printFailures(out, report.Failures)
+		return
+	}
+
+	fmt.Fprintf(out, "Last sync at %s (%s ago) took %s\n", started, ago, took)
+	printCorrelationID(out, report)
+	fmt.Fprintf(out, "Output: %s\n", report.Output)
+	fmt.Fprintf(out, "Clusters found: %d, kubeconfigs downloaded: %d\n",
+		report.ClustersFound, report.KubeconfigsDownloaded)
+
+	if len(report.Servers) > 0 {
+		fmt.Fprintln(out)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0) //nolint:mnd // Column padding
+		fmt.Fprintln(w, "SERVER\tID\tVERSION\tCLUSTERS\tSTATUS")
+		for _, server := range report.Servers {
+			status := "ok"
+			if server.Error != "" {
+				status = "failed: " + server.Error
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n",
+				server.ServerURL, server.ServerID, cmp.Or(server.Version, "-"), server.Clusters, status)
+		}
+		_ = w.Flush()
+	}
+	if summary := commands.ServerFailureSummary(report.Servers); summary != "" {
+		fmt.Fprintln(out, summary)
+	}
+
+	printChanges(out, report.Changes)
+	printSkippedServers(out, style{}, report.Skipped)
+
+	printTiming(out, report)
+}
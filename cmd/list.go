@@ -1,11 +1,14 @@
 package cmd
 
 import (
-	"context"
 	"errors"
 	"fmt"
+	"io"
+	"strings"
+	"time"
 
 	"cowpoke/internal/commands"
+	"cowpoke/internal/domain"
 
 	"github.com/spf13/cobra"
 )
@@ -32,27 +35,45 @@ func runList(cmd *cobra.Command, _ []string) error {
 	listCommand := commands.NewListCommand(
 		app.ConfigRepo,
 		app.Logger,
+		commands.WithSyncState(app.HealthTracker, app.StateStore, app.TokenCache),
 	)
 
-	result, err := listCommand.Execute(context.Background(), commands.ListRequest{})
+	result, err := listCommand.Execute(cmd.Context(), commands.ListRequest{})
 	if err != nil {
 		return fmt.Errorf("failed to list servers: %w", err)
 	}
 
+	now := time.Now()
+	style := newStyle(cmd.OutOrStdout())
 	if result.Count == 0 {
-		fmt.Fprintln(
-			cmd.OutOrStdout(),
-			"No Rancher servers configured. Use 'cowpoke add' to add servers.",
-		)
+		fmt.Fprintln(cmd.OutOrStdout(), style.warning(fmt.Sprintf(
+			"No Rancher servers configured%s. Use 'cowpoke add' to add servers.",
+			profileSuffix(app.ConfigRepo.Profile()),
+		)))
 		return nil
 	}
 
-	fmt.Fprintf(cmd.OutOrStdout(), "Configured Rancher servers (%d):\n\n", result.Count)
+	fmt.Fprintf(cmd.OutOrStdout(), "Configured Rancher servers%s (%d):\n\n", profileSuffix(app.ConfigRepo.Profile()), result.Count)
 	for i, server := range result.Servers {
 		fmt.Fprintf(cmd.OutOrStdout(), "%d. %s\n", i+1, server.URL)
-		fmt.Fprintf(cmd.OutOrStdout(), "   ID: %s\n", server.ID())
+		fmt.Fprintf(cmd.OutOrStdout(), "   ID: %s (short: %s)\n", server.ID(), domain.ShortID(result.Servers, server))
 		fmt.Fprintf(cmd.OutOrStdout(), "   Username: %s\n", server.Username)
 		fmt.Fprintf(cmd.OutOrStdout(), "   Auth Type: %s\n", server.AuthType)
+		if server.Alias != "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "   Alias: %s\n", server.Alias)
+		}
+		if server.Priority > 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "   Priority: %d\n", server.Priority)
+		}
+		if len(server.Tags) > 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "   Tags: %s\n", strings.Join(server.Tags, ", "))
+		}
+		if server.Managed {
+			fmt.Fprintln(cmd.OutOrStdout(), "   Managed: "+style.warning("yes (system configuration)"))
+		}
+		if i < len(result.States) {
+			printSyncState(cmd.OutOrStdout(), style, result.States[i], now)
+		}
 		if i < len(result.Servers)-1 {
 			fmt.Fprintln(cmd.OutOrStdout())
 		}
@@ -60,3 +81,24 @@ func runList(cmd *cobra.Command, _ []string) error {
 
 	return nil
 }
+
+// printSyncState prints what previous syncs recorded about a server.
+func printSyncState(out io.Writer, style style, state commands.ServerSyncState, now time.Time) {
+	lastSync := style.warning("never")
+	if !state.LastSuccess.IsZero() {
+		lastSync = formatAge(now.Sub(state.LastSuccess)) + " ago"
+	}
+	if !state.ClustersAt.IsZero() {
+		lastSync += fmt.Sprintf(" (%d clusters found %s ago)", state.Clusters, formatAge(now.Sub(state.ClustersAt)))
+	}
+	fmt.Fprintf(out, "   Last Sync: %s\n", lastSync)
+	if state.LastError != "" {
+		fmt.Fprintf(out, "   Last Error: %s\n", style.failure(state.LastError))
+	}
+
+	token := style.warning("none cached")
+	if !state.TokenExpiresAt.IsZero() {
+		token = style.success("valid") + " for " + formatAge(state.TokenExpiresAt.Sub(now))
+	}
+	fmt.Fprintf(out, "   Token: %s\n", token)
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"cowpoke/internal/commands"
+
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var loginCmd = &cobra.Command{
+	Use:   "login <url|id>",
+	Short: "Log in to a Rancher server and cache its token",
+	Long: `Authenticate with a single configured Rancher server and store the token in the OS keychain.
+Later syncs reuse the token until it expires, so they can run without prompting (see sync --cached-only).`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLogin,
+}
+
+//nolint:gochecknoinits // Cobra CLI pattern for command registration
+func init() {
+	rootCmd.AddCommand(loginCmd)
+
+	loginCmd.Flags().Bool("insecure", false, "Skip TLS certificate verification")
+}
+
+func runLogin(cmd *cobra.Command, args []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	insecureSkipTLS, _ := cmd.Flags().GetBool("insecure")
+
+	loginCommand := commands.NewLoginCommand(
+		app.ConfigRepo,
+		app.PasswordReader,
+		app.CreateRancherClient(insecureSkipTLS),
+		app.TokenCache,
+		app.Logger,
+	)
+
+	result, err := loginCommand.Execute(cmd.Context(), commands.LoginRequest{Server: args[0]})
+	if err != nil {
+		return fmt.Errorf("login failed: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Logged in to %s; token expires %s (in %s)\n", result.ServerURL,
+		result.ExpiresAt.Local().Format(time.DateTime), formatAge(time.Until(result.ExpiresAt)))
+	return nil
+}
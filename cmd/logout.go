@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"cowpoke/internal/commands"
+
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var logoutCmd = &cobra.Command{
+	Use:   "logout [<url|id>]",
+	Short: "Discard cached Rancher tokens",
+	Long: `Discard the cached token of one server, or of every configured server with --all.
+With --revoke, the tokens are also deleted in Rancher so they can no longer be used.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLogout,
+}
+
+//nolint:gochecknoinits // Cobra CLI pattern for command registration
+func init() {
+	rootCmd.AddCommand(logoutCmd)
+
+	logoutCmd.Flags().Bool("all", false, "Log out of every configured server")
+	logoutCmd.Flags().Bool("revoke", false, "Revoke the tokens in Rancher before discarding them")
+	logoutCmd.Flags().Bool("insecure", false, "Skip TLS certificate verification when revoking tokens")
+}
+
+func runLogout(cmd *cobra.Command, args []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	all, _ := cmd.Flags().GetBool("all")
+	revoke, _ := cmd.Flags().GetBool("revoke")
+	insecureSkipTLS, _ := cmd.Flags().GetBool("insecure")
+
+	req := commands.LogoutRequest{All: all, Revoke: revoke}
+	if len(args) > 0 {
+		req.Server = args[0]
+	}
+	if req.Server == "" && !req.All {
+		return errors.New("either a server or --all must be specified")
+	}
+
+	logoutCommand := commands.NewLogoutCommand(
+		app.ConfigRepo,
+		app.TokenCache,
+		app.CreateRancherClient(insecureSkipTLS),
+		app.Logger,
+	)
+
+	loggedOut, err := logoutCommand.Execute(cmd.Context(), req)
+	for _, serverURL := range loggedOut {
+		fmt.Fprintf(cmd.OutOrStdout(), "Logged out of %s\n", serverURL)
+	}
+	if err != nil {
+		return fmt.Errorf("logout failed: %w", err)
+	}
+	return nil
+}
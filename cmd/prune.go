@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"cowpoke/internal/commands"
+
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove contexts of servers and clusters that no longer exist",
+	Long: `Remove the contexts cowpoke wrote for servers that are no longer configured in any profile, and for
+clusters their server did not report when a sync last discovered it. Nothing is downloaded and Rancher is
+not contacted; contexts of servers no sync has discovered yet are kept.`,
+	RunE: runPrune,
+}
+
+//nolint:gochecknoinits // Cobra CLI pattern for command registration
+func init() {
+	rootCmd.AddCommand(pruneCmd)
+
+	pruneCmd.Flags().String("kubeconfig", "", "Merged kubeconfig to prune (default: ~/.kube/config)")
+	pruneCmd.Flags().Bool("dry-run", false, "List the contexts that would be removed without removing them")
+}
+
+func runPrune(cmd *cobra.Command, _ []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	kubeconfig, _ := cmd.Flags().GetString("kubeconfig")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	pruneCommand := commands.NewPruneCommand(app.ConfigRepo, app.ConfigProvider, app.KubeconfigHandler,
+		app.StateStore, app.Logger)
+	result, err := pruneCommand.Execute(cmd.Context(), commands.PruneRequest{
+		Kubeconfig: kubeconfig,
+		DryRun:     dryRun,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to prune contexts: %w", err)
+	}
+
+	if len(result.Contexts) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "Nothing to prune in %s\n", result.Kubeconfig)
+		return nil
+	}
+	style := newStyle(cmd.OutOrStdout())
+	verb := "Removed"
+	if result.DryRun {
+		verb = "Would remove"
+	}
+	for _, pruned := range result.Contexts {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s %s (%s: %s)\n",
+			verb, style.warning(pruned.Name), pruned.ServerURL, pruned.Reason)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s %d context(s) from %s\n", verb, len(result.Contexts), result.Kubeconfig)
+	return nil
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cowpoke/internal/commands"
+
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var refreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Download fresh kubeconfigs for the clusters already in the merged kubeconfig",
+	Long: `Download new kubeconfigs, and so new credentials, for the clusters whose contexts are already in the
+merged kubeconfig. Clusters are not discovered, so new clusters are not added, and contexts of clusters
+that no longer exist or could not be downloaded are kept as they are.`,
+	RunE: runRefresh,
+}
+
+//nolint:gochecknoinits // Cobra CLI pattern for command registration
+func init() {
+	rootCmd.AddCommand(refreshCmd)
+	refreshCmd.Flags().
+		StringP("output", "o", "", "Merged kubeconfig to refresh (default: ~/.kube/config)")
+	refreshCmd.Flags().
+		Bool("insecure", false, "Skip TLS certificate verification for Rancher servers")
+	refreshCmd.Flags().
+		Bool("ignore-backoff", false, "Refresh servers even if they are backing off after repeated failures")
+	refreshCmd.Flags().
+		Bool("cached-only", false, "Never prompt for passwords; skip servers without a valid cached token")
+	refreshCmd.Flags().
+		Duration("kubeconfig-ttl", 0, "Lifetime of the tokens in downloaded kubeconfigs, e.g. 720h (default: server setting)")
+	refreshCmd.Flags().
+		Bool("via-rancher", false, "Reach every cluster through Rancher's proxy, even those with their own endpoints")
+	refreshCmd.Flags().
+		Bool("force", false, "Write the merged kubeconfig even if it holds more contexts than settings.limits allow")
+	refreshCmd.Flags().
+		BoolP("quiet", "q", false, "Print no summary after a successful refresh")
+}
+
+func runRefresh(cmd *cobra.Command, _ []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	output, _ := cmd.Flags().GetString("output")
+	insecureSkipTLS, _ := cmd.Flags().GetBool("insecure")
+	ignoreBackoff, _ := cmd.Flags().GetBool("ignore-backoff")
+	cachedOnly, _ := cmd.Flags().GetBool("cached-only")
+	force, _ := cmd.Flags().GetBool("force")
+	viaRancher, _ := cmd.Flags().GetBool("via-rancher")
+	kubeconfigTTL, _ := cmd.Flags().GetDuration("kubeconfig-ttl")
+	if kubeconfigTTL != 0 && kubeconfigTTL < time.Minute {
+		return errors.New("--kubeconfig-ttl must be at least 1m")
+	}
+
+	refreshCommand := commands.NewRefreshCommand(app.ConfigRepo, app.ConfigProvider, app.KubeconfigHandler,
+		app.Logger)
+	refresh, err := refreshCommand.Execute(cmd.Context(), commands.RefreshRequest{Kubeconfig: output})
+	if err != nil {
+		printSkippedOnFailure(cmd.ErrOrStderr(), err)
+		return fmt.Errorf("refresh failed: %w", err)
+	}
+
+	style := newStyle(cmd.OutOrStdout())
+	for _, skipped := range refresh.Skipped {
+		fmt.Fprintln(cmd.OutOrStdout(), style.warning(fmt.Sprintf("Keeping %s: it cannot be refreshed", skipped.Name)))
+	}
+	if len(refresh.Contexts) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "No contexts in %s can be refreshed (run cowpoke sync to add clusters)\n",
+			refresh.Kubeconfig)
+		return nil
+	}
+
+	req := commands.SyncRequest{
+		Output:          refresh.Output,
+		InsecureSkipTLS: insecureSkipTLS,
+		Verbose:         app.Config.Verbose,
+		IgnoreBackoff:   ignoreBackoff,
+		CachedOnly:      cachedOnly,
+		Clusters:        refresh.Clusters(),
+		KeepExisting:    true,
+		Force:           force,
+		AuthRetries:     commands.DefaultAuthRetries,
+	}
+
+	rancherClient := app.CreateRancherClient(insecureSkipTLS)
+	syncOrchestrator := app.CreateSyncOrchestrator(rancherClient, kubeconfigTTL, false, viaRancher, false)
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), syncTimeout)
+	defer cancel()
+	report, err := app.CreateSyncCommand().Execute(ctx, req, syncOrchestrator, app.KubeconfigHandler)
+	if err != nil {
+		return fmt.Errorf("refresh failed: %w", err)
+	}
+
+	if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+		return nil
+	}
+	printSyncReport(cmd.OutOrStdout(), "Refresh completed successfully", report)
+	return nil
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"cowpoke/internal/commands"
+
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var releaseManifestsCmd = &cobra.Command{
+	Use:   "release-manifests",
+	Short: "Generate the Scoop manifest of a release",
+	Long: `Generate the Scoop manifest (cowpoke.json) installing a release's archives, from the checksums file
+GoReleaser writes. For maintainers and the release workflow. Homebrew installs the cask GoReleaser publishes
+to the tap.
+
+The archives are named and downloaded as self-update expects them, and every one must be listed in the
+checksums file. The version defaults to this binary's.`,
+	Args: cobra.NoArgs,
+	RunE: runReleaseManifests,
+}
+
+//nolint:gochecknoinits // Cobra CLI pattern for command registration
+func init() {
+	rootCmd.AddCommand(releaseManifestsCmd)
+	releaseManifestsCmd.Flags().
+		String("version", "", "Release version, such as v1.5.0 (default: this binary's version)")
+	releaseManifestsCmd.Flags().
+		String("checksums", "dist/checksums.txt", "Checksums file of the release")
+	releaseManifestsCmd.Flags().
+		String("output-dir", "dist", "Directory to write the manifests to")
+}
+
+func runReleaseManifests(cmd *cobra.Command, _ []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	version, _ := cmd.Flags().GetString("version")
+	checksums, _ := cmd.Flags().GetString("checksums")
+	outputDir, _ := cmd.Flags().GetString("output-dir")
+	if version == "" {
+		version = GetVersionInfo().Version
+	}
+
+	releaseManifestsCommand := commands.NewReleaseManifestsCommand(app.CreateUpdater(), app.FileSystem, app.Logger)
+	result, err := releaseManifestsCommand.Execute(cmd.Context(), commands.ReleaseManifestsRequest{
+		Version:   version,
+		Checksums: checksums,
+		OutputDir: outputDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate release manifests: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	style := newStyle(out)
+	fmt.Fprintf(out, "%s %s\n", style.success("Wrote"), result.Scoop)
+	return nil
+}
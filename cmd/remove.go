@@ -1,9 +1,9 @@
 package cmd
 
 import (
-	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"cowpoke/internal/commands"
 
@@ -12,10 +12,12 @@ import (
 
 //nolint:gochecknoglobals // Cobra CLI pattern for subcommand
 var removeCmd = &cobra.Command{
-	Use:   "remove",
+	Use:   "remove [<url|id>]",
 	Short: "Remove a Rancher server from the configuration",
-	Long:  `Remove a Rancher server by its URL or ID from the configuration.`,
-	RunE:  runRemove,
+	Long: `Remove a Rancher server by its URL or ID from the configuration. A unique prefix of the ID of at
+least 4 characters, as shown by 'cowpoke list', is accepted in place of the whole ID.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRemove,
 }
 
 //nolint:gochecknoinits // Cobra CLI pattern for command registration
@@ -24,9 +26,11 @@ func init() {
 
 	removeCmd.Flags().StringP("url", "u", "", "Rancher server URL to remove")
 	removeCmd.Flags().StringP("id", "i", "", "Rancher server ID to remove")
+	removeCmd.Flags().Bool("revoke", false, "Revoke the server's cached token in Rancher before removing it")
+	removeCmd.Flags().Bool("insecure", false, "Skip TLS certificate verification when revoking the token")
 }
 
-func runRemove(cmd *cobra.Command, _ []string) error {
+func runRemove(cmd *cobra.Command, args []string) error {
 	app := GetApp()
 	if app == nil {
 		return errors.New("application not initialized")
@@ -34,9 +38,21 @@ func runRemove(cmd *cobra.Command, _ []string) error {
 
 	removeURL, _ := cmd.Flags().GetString("url")
 	removeID, _ := cmd.Flags().GetString("id")
+	revoke, _ := cmd.Flags().GetBool("revoke")
+	insecureSkipTLS, _ := cmd.Flags().GetBool("insecure")
 
+	if len(args) == 1 {
+		if removeURL != "" || removeID != "" {
+			return errors.New("a server argument cannot be combined with --url or --id")
+		}
+		if strings.Contains(args[0], "://") {
+			removeURL = args[0]
+		} else {
+			removeID = args[0]
+		}
+	}
 	if removeURL == "" && removeID == "" {
-		return errors.New("either --url or --id must be specified")
+		return errors.New("a server URL or ID must be specified")
 	}
 	if removeURL != "" && removeID != "" {
 		return errors.New("only one of --url or --id can be specified")
@@ -45,19 +61,23 @@ func runRemove(cmd *cobra.Command, _ []string) error {
 	removeCommand := commands.NewRemoveCommand(
 		app.ConfigRepo,
 		app.Logger,
+		commands.WithTokenCleanup(app.TokenCache, app.CreateRancherClient(insecureSkipTLS)),
 	)
 
-	err := removeCommand.Execute(context.Background(), commands.RemoveRequest{
+	err := removeCommand.Execute(cmd.Context(), commands.RemoveRequest{
 		ServerURL: removeURL,
 		ServerID:  removeID,
+		Revoke:    revoke,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to remove server: %w", err)
 	}
 	if removeURL != "" {
-		fmt.Fprintf(cmd.OutOrStdout(), "Successfully removed Rancher server: %s\n", removeURL)
+		fmt.Fprintf(cmd.OutOrStdout(), "Successfully removed Rancher server: %s%s\n", removeURL,
+			profileSuffix(app.ConfigRepo.Profile()))
 	} else {
-		fmt.Fprintf(cmd.OutOrStdout(), "Successfully removed Rancher server with ID: %s\n", removeID)
+		fmt.Fprintf(cmd.OutOrStdout(), "Successfully removed Rancher server with ID: %s%s\n", removeID,
+			profileSuffix(app.ConfigRepo.Profile()))
 	}
 	return nil
 }
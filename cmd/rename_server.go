@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"cowpoke/internal/commands"
+
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var renameServerCmd = &cobra.Command{
+	Use:   "rename-server",
+	Short: "Change a Rancher server's URL without losing its kubeconfig contexts",
+	Long: `Update a server's URL in the configuration after a migration or rename.
+
+Because a server's ID is derived from its URL, its contexts would otherwise get new names on the next sync.
+This command rewrites the contexts it owns in the merged kubeconfig to the new naming immediately.`,
+	RunE: runRenameServer,
+}
+
+//nolint:gochecknoinits // Cobra CLI pattern for command registration
+func init() {
+	rootCmd.AddCommand(renameServerCmd)
+
+	renameServerCmd.Flags().String("from", "", "Current Rancher server URL (required)")
+	renameServerCmd.Flags().String("to", "", "New Rancher server URL (required)")
+	renameServerCmd.Flags().String("kubeconfig", "", "Merged kubeconfig to rewrite (default: ~/.kube/config)")
+
+	_ = renameServerCmd.MarkFlagRequired("from")
+	_ = renameServerCmd.MarkFlagRequired("to")
+}
+
+func runRenameServer(cmd *cobra.Command, _ []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+	kubeconfig, _ := cmd.Flags().GetString("kubeconfig")
+
+	renameCommand := commands.NewRenameServerCommand(
+		app.ConfigRepo,
+		app.ConfigProvider,
+		app.KubeconfigHandler,
+		app.Logger,
+		commands.WithRenamedContextIndex(app.StateStore),
+	)
+	result, err := renameCommand.Execute(cmd.Context(), commands.RenameServerRequest{
+		From:       from,
+		To:         to,
+		Kubeconfig: kubeconfig,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to rename server: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Renamed server %s to %s (ID %s -> %s)\n", from, to, result.OldID, result.NewID)
+	fmt.Fprintf(cmd.OutOrStdout(), "Rewrote %d context(s) in %s\n", result.RenamedContexts, result.Kubeconfig)
+	return nil
+}
@@ -1,20 +1,35 @@
 package cmd
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
 
 	"cowpoke/internal/app"
+	"cowpoke/internal/commands"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+// updateCheckTimeout bounds the daily update check so a slow GitHub never delays a command noticeably.
+const updateCheckTimeout = 3 * time.Second
+
 //nolint:gochecknoglobals // Cobra CLI pattern for persistent flag variables
 var (
-	cfgFile string
-	verbose bool
+	cfgFile   string
+	verbose   bool
+	strict    bool
+	profile   string
+	stateDir  string
+	debugHTTP bool
+	noColor   bool
 
 	application *app.App
 )
@@ -59,15 +74,76 @@ var rootCmd = &cobra.Command{
 	Short: "A CLI tool for managing Rancher servers and downloading kubeconfigs",
 	Long: `Cowpoke is a CLI tool that helps you manage multiple Rancher servers
 and download kubeconfigs from all clusters across all servers.`,
+	// Errors are printed by Execute, which explains them instead of printing the raw error chain.
+	SilenceErrors: true,
 }
 
+// Execute runs the root command. SIGINT and SIGTERM cancel the context commands run in, so that a sync stops
+// cleanly instead of exiting abruptly; a second signal exits immediately.
 func Execute() {
-	err := rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	// Restore the default handling once interrupted, so that the next signal terminates the process
+	context.AfterFunc(ctx, stop)
+
+	executed, err := rootCmd.ExecuteContextC(ctx)
+	if err == nil {
+		printExpiryWarning(executed)
+		printUpdateNotice(executed)
+	}
+	if application != nil {
+		if shutdownErr := application.Shutdown(context.Background()); shutdownErr != nil {
+			application.Logger.Warn("Failed to shut down cleanly", "error", shutdownErr)
+		}
+	}
 	if err != nil {
+		printError(rootCmd.ErrOrStderr(), err)
 		os.Exit(1)
 	}
 }
 
+// printError explains a failed command, with a hint where one is known. The full error chain is logged
+// at debug level.
+func printError(out io.Writer, err error) {
+	if application != nil {
+		application.Logger.Debug("Command failed", "error", err)
+	}
+
+	explanation := commands.Explain(err)
+	fmt.Fprintf(out, "%s %s\n", newStyle(out).failure("Error:"), explanation.Message)
+	if explanation.Hint != "" {
+		fmt.Fprintf(out, "Hint: %s\n", explanation.Hint)
+	}
+	if explanation.Message != err.Error() && !verbose {
+		fmt.Fprintln(out, "Run with --verbose to see the full error.")
+	}
+}
+
+// printUpdateNotice prints a one-line notice when a newer release exists and update checks are enabled.
+// Failed checks are only logged, so they never affect the command's outcome.
+func printUpdateNotice(executed *cobra.Command) {
+	if application == nil || !application.Settings.Updates.Check {
+		return
+	}
+	switch executed.Name() {
+	case selfUpdateCmd.Name(), versionCmd.Name(), credentialCmd.Name(), releaseManifestsCmd.Name(),
+		cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), updateCheckTimeout)
+	defer cancel()
+	release, err := application.CreateUpdateChecker().NewerRelease(ctx, versionInfo.Version)
+	if err != nil {
+		application.Logger.Debug("Could not check for updates", "error", err)
+		return
+	}
+	if release != nil {
+		out := rootCmd.ErrOrStderr()
+		fmt.Fprintf(out, "%s (running %s), run 'cowpoke self-update' to install it.\n",
+			newStyle(out).warning("cowpoke "+release.Version+" is available"), versionInfo.Version)
+	}
+}
+
 //nolint:gochecknoinits // Cobra CLI pattern for flag initialization
 func init() {
 	cobra.OnInitialize(initConfig)
@@ -77,6 +153,17 @@ func init() {
 		StringVar(&cfgFile, "config", "", "config file (default is $HOME/.config/cowpoke/config.yaml)")
 	rootCmd.PersistentFlags().
 		BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging")
+	rootCmd.PersistentFlags().
+		BoolVar(&strict, "strict", false, "Fail on unknown fields in the config file instead of warning")
+	rootCmd.PersistentFlags().
+		StringVar(&profile, "profile", "", "Config profile to use (default is $COWPOKE_PROFILE or \"default\")")
+	rootCmd.PersistentFlags().
+		StringVar(&stateDir, "state-dir", "",
+			"Directory for the kubeconfig cache and runtime state (default is $COWPOKE_STATE_DIR or $HOME/.config/cowpoke)")
+	rootCmd.PersistentFlags().
+		BoolVar(&debugHTTP, "debug-http", false, "Log every Rancher API call to a debug file in the state directory")
+	rootCmd.PersistentFlags().
+		BoolVar(&noColor, "no-color", false, "Disable colored output (also disabled by NO_COLOR and when not a terminal)")
 }
 
 func initConfig() {
@@ -97,15 +184,30 @@ func initConfig() {
 	_ = viper.ReadInConfig()
 
 	// Initialize the application with dependency injection.
-	var opts []app.Option
+	opts := []app.Option{app.WithVersion(versionInfo.Version)}
 	if verbose {
 		opts = append(opts, app.WithVerbose(true))
 	}
+	if strict {
+		opts = append(opts, app.WithStrictConfig(true))
+	}
+	if debugHTTP {
+		opts = append(opts, app.WithDebugHTTP(true))
+	}
+	if name := cmp.Or(profile, os.Getenv("COWPOKE_PROFILE")); name != "" {
+		opts = append(opts, app.WithProfile(name))
+	}
+	if dir := cmp.Or(stateDir, os.Getenv("COWPOKE_STATE_DIR")); dir != "" {
+		// Absolute, so that exec credential plugins find it from wherever kubectl runs.
+		absDir, err := filepath.Abs(dir)
+		cobra.CheckErr(err)
+		opts = append(opts, app.WithStateDir(absDir))
+	}
 
 	var err error
 	application, err = app.NewApp(context.Background(), opts...)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to initialize application: %v\n", err)
+		printError(os.Stderr, fmt.Errorf("failed to initialize application: %w", err))
 		os.Exit(1)
 	}
 }
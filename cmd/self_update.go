@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"cowpoke/internal/commands"
+
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Update cowpoke to the latest release",
+	Long: `Check GitHub for the latest cowpoke release and, if it is newer, replace the running binary with it.
+
+The release archive for this platform is verified against the release's checksums before the binary is
+replaced. Installations managed by Homebrew should be updated with 'brew upgrade cowpoke' instead.`,
+	RunE: runSelfUpdate,
+}
+
+//nolint:gochecknoinits // Cobra CLI pattern for command registration
+func init() {
+	rootCmd.AddCommand(selfUpdateCmd)
+
+	selfUpdateCmd.Flags().Bool("check-only", false, "Only report whether a newer release exists")
+}
+
+func runSelfUpdate(cmd *cobra.Command, _ []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	checkOnly, _ := cmd.Flags().GetBool("check-only")
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the cowpoke binary: %w", err)
+	}
+
+	selfUpdateCommand := commands.NewSelfUpdateCommand(app.CreateUpdater(), app.Logger)
+	result, err := selfUpdateCommand.Execute(cmd.Context(), commands.SelfUpdateRequest{
+		CurrentVersion: GetVersionInfo().Version,
+		Executable:     executable,
+		CheckOnly:      checkOnly,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update cowpoke: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	style := newStyle(out)
+	switch {
+	case result.Updated:
+		fmt.Fprintln(out, style.success(fmt.Sprintf("Updated cowpoke %s to %s", result.CurrentVersion,
+			result.LatestVersion)))
+	case result.UpdateAvailable:
+		fmt.Fprintln(out, style.warning(fmt.Sprintf("cowpoke %s is available (running %s)", result.LatestVersion,
+			result.CurrentVersion)))
+		fmt.Fprintln(out, "Run 'cowpoke self-update' to install it.")
+	default:
+		fmt.Fprintf(out, "cowpoke %s is the latest release (running %s)\n", result.LatestVersion,
+			result.CurrentVersion)
+		return nil
+	}
+	if result.ReleaseURL != "" {
+		fmt.Fprintf(out, "Release notes: %s\n", result.ReleaseURL)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cowpoke/internal/commands"
+
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var showCmd = &cobra.Command{
+	Use:   "show <cluster>",
+	Short: "Print the kubeconfig of a single cluster",
+	Long: `Print the kubeconfig of a single cluster, found by name or ID, to stdout. It is served from the cache
+of downloaded kubeconfigs if there, or downloaded from Rancher, in which case clusters are looked up by ID.
+The merged kubeconfig is not touched.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runShow,
+}
+
+//nolint:gochecknoinits // Cobra CLI pattern for command registration
+func init() {
+	rootCmd.AddCommand(showCmd)
+	showCmd.Flags().
+		String("server", "", "URL or ID of the server the cluster belongs to")
+	showCmd.Flags().
+		Bool("refresh", false, "Download the kubeconfig even if it is cached")
+	showCmd.Flags().
+		Bool("redact", false, "Replace tokens, passwords and client keys with a placeholder")
+	showCmd.Flags().
+		Bool("insecure", false, "Skip TLS certificate verification for Rancher servers")
+}
+
+func runShow(cmd *cobra.Command, args []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	server, _ := cmd.Flags().GetString("server")
+	refresh, _ := cmd.Flags().GetBool("refresh")
+	redact, _ := cmd.Flags().GetBool("redact")
+	insecureSkipTLS, _ := cmd.Flags().GetBool("insecure")
+
+	rancherClient := app.CreateRancherClient(insecureSkipTLS)
+	syncOrchestrator := app.CreateSyncOrchestrator(rancherClient, 0, false, false, false)
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), syncTimeout)
+	defer cancel()
+	showCommand := commands.NewShowCommand(app.ConfigRepo, app.FragmentCache, app.TokenCache,
+		app.PasswordReader, app.KubeconfigHandler, app.Logger)
+	result, err := showCommand.Execute(ctx, commands.ShowRequest{
+		Cluster: args[0],
+		Server:  server,
+		Refresh: refresh,
+		Redact:  redact,
+	}, syncOrchestrator)
+	if err != nil {
+		return fmt.Errorf("failed to show kubeconfig: %w", err)
+	}
+
+	_, err = cmd.OutOrStdout().Write(result.Kubeconfig)
+	return err
+}
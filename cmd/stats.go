@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"cowpoke/internal/commands"
+
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize what cowpoke maintains in the merged kubeconfig",
+	Long: `Summarize the merged kubeconfig: its size, how many contexts cowpoke manages and which contexts it
+does not, how many contexts each server contributes, when their credentials expire, and which endpoints
+several contexts point at.`,
+	RunE: runStats,
+}
+
+//nolint:gochecknoinits // Cobra CLI pattern for command registration
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().String("kubeconfig", "", "Merged kubeconfig to inspect (default: ~/.kube/config)")
+	statsCmd.Flags().Bool("json", false, "Print the stats as JSON")
+}
+
+func runStats(cmd *cobra.Command, _ []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	kubeconfig, _ := cmd.Flags().GetString("kubeconfig")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	statsCommand := commands.NewStatsCommand(app.ConfigRepo, app.ConfigProvider, app.KubeconfigHandler,
+		app.FileSystem, app.Clock, app.Logger)
+	result, err := statsCommand.Execute(cmd.Context(), commands.StatsRequest{Kubeconfig: kubeconfig})
+	if err != nil {
+		return fmt.Errorf("failed to collect stats: %w", err)
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	out := cmd.OutOrStdout()
+	style := newStyle(out)
+	fmt.Fprintf(out, "Kubeconfig: %s (%s)\n", result.Kubeconfig, formatBytes(result.Size))
+	fmt.Fprintf(out, "Contexts: %d managed by cowpoke, %d foreign\n", result.Managed, len(result.Foreign))
+	if len(result.Foreign) > 0 {
+		fmt.Fprintf(out, "Foreign contexts: %s\n", strings.Join(result.Foreign, ", "))
+	}
+
+	if len(result.Servers) > 0 {
+		fmt.Fprintln(out)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0) //nolint:mnd // Column padding
+		fmt.Fprintln(w, "SERVER\tID\tCONTEXTS")
+		for _, server := range result.Servers {
+			fmt.Fprintf(w, "%s\t%s\t%d\n", server.ServerURL, server.ServerID, server.Contexts)
+		}
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Credential expiry:")
+		w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0) //nolint:mnd // Column padding
+		for _, bucket := range result.Expiry {
+			count := fmt.Sprint(bucket.Contexts)
+			if bucket.Label == "expired" && bucket.Contexts > 0 {
+				count = style.failure(count)
+			}
+			fmt.Fprintf(w, "  %s\t%s\n", bucket.Label, count)
+		}
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+	}
+
+	if len(result.Duplicates) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, style.warning("Endpoints shared by several contexts:"))
+		for _, duplicate := range result.Duplicates {
+			fmt.Fprintf(out, "  %s: %s\n", duplicate.Endpoint, strings.Join(duplicate.Contexts, ", "))
+		}
+	}
+	return nil
+}
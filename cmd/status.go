@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"cowpoke/internal/commands"
+
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the sync health of configured Rancher servers",
+	Long: `Show the last sync outcome of each configured Rancher server, including servers that are
+backing off after repeated failures and when they will next be attempted.`,
+	RunE: runStatus,
+}
+
+//nolint:gochecknoinits // Cobra CLI pattern for command registration
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
+
+func runStatus(cmd *cobra.Command, _ []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	statusCommand := commands.NewStatusCommand(app.ConfigRepo, app.HealthTracker, app.Logger)
+	result, err := statusCommand.Execute(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("failed to get status: %w", err)
+	}
+
+	if len(result.Servers) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No Rancher servers configured. Use 'cowpoke add' to add servers.")
+		return nil
+	}
+
+	now := time.Now()
+	style := newStyle(cmd.OutOrStdout())
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0) //nolint:mnd // Column padding
+	fmt.Fprintln(w, "SERVER\tID\tSTATE\tLAST SUCCESS\tFAILURES\tNEXT ATTEMPT")
+	for _, status := range result.Servers {
+		health := status.Health
+
+		state := style.success("ok")
+		nextAttempt := "-"
+		switch {
+		case health.BackingOff(now):
+			state = style.failure("backing off")
+			nextAttempt = "in " + formatAge(health.NextAttempt.Sub(now))
+		case health.ConsecutiveFailures > 0:
+			state = style.failure("failing")
+			nextAttempt = "next sync"
+		case health.LastSuccess.IsZero():
+			state = style.warning("never synced")
+		}
+
+		lastSuccess := "-"
+		if !health.LastSuccess.IsZero() {
+			lastSuccess = formatAge(now.Sub(health.LastSuccess)) + " ago"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n",
+			status.Server.URL, status.Server.ID(), state, lastSuccess, health.ConsecutiveFailures, nextAttempt)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+
+	for _, status := range result.Servers {
+		if status.Health.LastError != "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "\n%s last error: %s\n",
+				status.Server.URL, style.failure(status.Health.LastError))
+		}
+	}
+	return nil
+}
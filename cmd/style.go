@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// ANSI color codes. All have the same length, so a table column colored row by row stays aligned.
+const (
+	colorReset   = "\x1b[0m"
+	colorRed     = "\x1b[31m"
+	colorGreen   = "\x1b[32m"
+	colorYellow  = "\x1b[33m"
+	colorDefault = "\x1b[39m"
+)
+
+// style colors terminal output: green for success, yellow for warnings and red for failures.
+type style struct {
+	enabled bool
+}
+
+// newStyle returns the style for out. Colors are disabled by NO_COLOR, by --no-color and when out is not
+// a terminal.
+func newStyle(out io.Writer) style {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		return style{}
+	}
+	file, ok := out.(*os.File)
+	return style{enabled: ok && term.IsTerminal(int(file.Fd()))}
+}
+
+func (s style) success(text string) string { return s.color(colorGreen, text) }
+func (s style) warning(text string) string { return s.color(colorYellow, text) }
+func (s style) failure(text string) string { return s.color(colorRed, text) }
+
+// plain leaves text uncolored, taking the same width as colored text in a table column.
+func (s style) plain(text string) string { return s.color(colorDefault, text) }
+
+func (s style) color(code, text string) string {
+	if !s.enabled {
+		return text
+	}
+	return code + text + colorReset
+}
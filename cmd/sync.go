@@ -2,11 +2,17 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"cowpoke/internal/commands"
+	"cowpoke/internal/domain"
 
 	"github.com/spf13/cobra"
 )
@@ -22,7 +28,10 @@ var syncCmd = &cobra.Command{
 	Short: "Sync kubeconfigs from all Rancher servers",
 	Long: `Download kubeconfigs from all configured Rancher servers and merge them into a kubeconfig file.
 	
-By default, the merged kubeconfig is written to ~/.kube/config. Use the --output flag to specify a different location.`,
+By default, the merged kubeconfig is written to ~/.kube/config. Use the --output flag to specify a different location,
+secret://namespace/name to write it into a Kubernetes Secret through a context of your kubeconfig, or
+s3://bucket/path or gs://bucket/path to upload it to object storage, or git:///path/in/working/tree to
+commit it to a git repository.`,
 	RunE: runSync,
 }
 
@@ -30,13 +39,58 @@ By default, the merged kubeconfig is written to ~/.kube/config. Use the --output
 func init() {
 	rootCmd.AddCommand(syncCmd)
 	syncCmd.Flags().
-		StringP("output", "o", "", "Output directory or file path for merged kubeconfig (default: ~/.kube/config)")
+		StringP("output", "o", "",
+			"Output file path for merged kubeconfig, secret://namespace/name for a Kubernetes Secret, "+
+				"s3:// or gs:// for object storage, or git:// for a git repository (default: ~/.kube/config)")
 	syncCmd.Flags().
 		Bool("cleanup-temp-files", false, "Remove temporary kubeconfig files after merging")
 	syncCmd.Flags().
 		Bool("insecure", false, "Skip TLS certificate verification for Rancher servers")
 	syncCmd.Flags().
 		StringSlice("exclude", []string{}, "Exclude clusters matching regex pattern (can be specified multiple times)")
+	syncCmd.Flags().
+		StringSlice("exclude-type", []string{}, "Exclude clusters by provider type, e.g. harvester or k3s (repeatable)")
+	syncCmd.Flags().
+		StringSlice("exclude-server", []string{}, "Leave out a configured server by URL or ID for this sync (repeatable)")
+	syncCmd.Flags().
+		String("from-file", "", "Sync only the clusters listed in a YAML file, without discovering clusters")
+	syncCmd.Flags().
+		Bool("ignore-backoff", false, "Sync servers even if they are backing off after repeated failures")
+	syncCmd.Flags().
+		Bool("cached-only", false, "Never prompt for passwords; skip servers without a valid cached token")
+	syncCmd.Flags().
+		Bool("offline", false, "Rebuild the kubeconfig from previously downloaded kubeconfigs, without network calls")
+	syncCmd.Flags().
+		Bool("include-local", false, "Sync the local cluster each Rancher server runs on, which is skipped by default")
+	syncCmd.Flags().
+		Bool("owned-only", false, "Sync only the clusters you created or hold the cluster-owner role on")
+	syncCmd.Flags().
+		Bool("force", false, "Write the merged kubeconfig even if it holds more contexts than settings.limits allow")
+	syncCmd.Flags().
+		Bool("json", false, "Print the sync report, including the timing breakdown, as JSON")
+	syncCmd.Flags().
+		BoolP("quiet", "q", false, "Print no summary after a successful sync")
+	syncCmd.Flags().
+		Bool("refresh-expiring", false, "Sync only the clusters whose contexts have credentials about to expire")
+	syncCmd.Flags().
+		Duration("kubeconfig-ttl", 0, "Lifetime of the tokens in downloaded kubeconfigs, e.g. 720h (default: server setting)")
+	syncCmd.Flags().
+		Duration("max-duration", 0, "Stop downloading after this long, e.g. 2m, and merge the kubeconfigs downloaded")
+	syncCmd.Flags().
+		Bool("via-rancher", false, "Reach every cluster through Rancher's proxy, even those with their own endpoints")
+	syncCmd.Flags().
+		Bool("check-endpoints", false, "Warn about cluster endpoints whose host does not resolve from this machine")
+	syncCmd.Flags().
+		Int("auth-retries", commands.DefaultAuthRetries, "Times to ask again for a password Rancher rejects")
+
+	syncCmd.MarkFlagsMutuallyExclusive("from-file", "refresh-expiring")
+	syncCmd.MarkFlagsMutuallyExclusive("offline", "cached-only")
+	syncCmd.MarkFlagsMutuallyExclusive("offline", "max-duration")
+	syncCmd.MarkFlagsMutuallyExclusive("offline", "check-endpoints")
+	syncCmd.MarkFlagsMutuallyExclusive("offline", "via-rancher")
+	syncCmd.MarkFlagsMutuallyExclusive("offline", "owned-only")
+	syncCmd.MarkFlagsMutuallyExclusive("offline", "auth-retries")
+	syncCmd.MarkFlagsMutuallyExclusive("from-file", "owned-only")
 }
 
 func runSync(cmd *cobra.Command, _ []string) error {
@@ -45,10 +99,81 @@ func runSync(cmd *cobra.Command, _ []string) error {
 		return errors.New("application not initialized")
 	}
 
+	insecureSkipTLS, _ := cmd.Flags().GetBool("insecure")
+	kubeconfigTTL, _ := cmd.Flags().GetDuration("kubeconfig-ttl")
+	if kubeconfigTTL != 0 && kubeconfigTTL < time.Minute {
+		return errors.New("--kubeconfig-ttl must be at least 1m")
+	}
+	includeLocal, _ := cmd.Flags().GetBool("include-local")
+	viaRancher, _ := cmd.Flags().GetBool("via-rancher")
+	ownedOnly, _ := cmd.Flags().GetBool("owned-only")
+
+	req, err := syncRequest(cmd)
+	if err != nil {
+		return err
+	}
+	if refreshExpiring, _ := cmd.Flags().GetBool("refresh-expiring"); refreshExpiring {
+		expiring, expiringErr := expiringContexts(cmd.Context(), req.Output)
+		if expiringErr != nil {
+			return expiringErr
+		}
+		if len(expiring.Contexts) == 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "No credentials in %s expire within %s\n", expiring.Kubeconfig,
+				formatAge(app.Settings.Expiry.Window()))
+			return nil
+		}
+		req.Clusters = expiring.Clusters()
+		req.KeepExisting = true
+	}
+
+	rancherClient := app.CreateRancherClient(insecureSkipTLS)
+	syncOrchestrator := app.CreateSyncOrchestrator(rancherClient, kubeconfigTTL, includeLocal, viaRancher, ownedOnly)
+
+	syncCommand := app.CreateSyncCommand()
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), syncTimeout)
+	defer cancel()
+	report, err := syncCommand.Execute(ctx, req, syncOrchestrator, app.KubeconfigHandler)
+	if err != nil {
+		printSkippedOnFailure(cmd.ErrOrStderr(), err)
+		return fmt.Errorf("sync failed: %w", err)
+	}
+
+	// Downloads that failed are reported after the kubeconfigs of the other clusters are merged
+	var downloadErr error
+	if report != nil && len(report.Failures) > 0 {
+		downloadErr = fmt.Errorf("sync incomplete: %w",
+			&domain.DownloadError{Failures: report.Failures, Total: report.ClustersFound})
+	}
+	if printErr := printSyncOutcome(cmd, report, downloadErr); printErr != nil {
+		return printErr
+	}
+	return downloadErr
+}
+
+// syncRequest builds the request of the sync command from its flags.
+func syncRequest(cmd *cobra.Command) (commands.SyncRequest, error) {
+	app := GetApp()
 	output, _ := cmd.Flags().GetString("output")
 	cleanupTempFiles, _ := cmd.Flags().GetBool("cleanup-temp-files")
 	insecureSkipTLS, _ := cmd.Flags().GetBool("insecure")
 	excludePatterns, _ := cmd.Flags().GetStringSlice("exclude")
+	excludeTypes, _ := cmd.Flags().GetStringSlice("exclude-type")
+	excludeServers, _ := cmd.Flags().GetStringSlice("exclude-server")
+	fromFile, _ := cmd.Flags().GetString("from-file")
+	ignoreBackoff, _ := cmd.Flags().GetBool("ignore-backoff")
+	cachedOnly, _ := cmd.Flags().GetBool("cached-only")
+	offline, _ := cmd.Flags().GetBool("offline")
+	force, _ := cmd.Flags().GetBool("force")
+	maxDuration, _ := cmd.Flags().GetDuration("max-duration")
+	if maxDuration < 0 {
+		return commands.SyncRequest{}, errors.New("--max-duration must be positive")
+	}
+	authRetries, _ := cmd.Flags().GetInt("auth-retries")
+	if authRetries < 0 {
+		return commands.SyncRequest{}, errors.New("--auth-retries must not be negative")
+	}
+	checkEndpoints, _ := cmd.Flags().GetBool("check-endpoints")
 
 	// Debug logging for exclude patterns
 	if len(excludePatterns) > 0 {
@@ -59,29 +184,216 @@ func runSync(cmd *cobra.Command, _ []string) error {
 		app.Logger.Info("No exclude patterns specified")
 	}
 
-	rancherClient := app.CreateRancherClient(insecureSkipTLS)
-	syncOrchestrator := app.CreateSyncOrchestrator(rancherClient)
-
-	syncCommand := commands.NewSyncCommand(
-		app.ConfigRepo,
-		app.ConfigProvider,
-		app.PasswordReader,
-		app.Logger,
-	)
-
-	ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
-	defer cancel()
-	err := syncCommand.Execute(ctx, commands.SyncRequest{
+	req := commands.SyncRequest{
 		Output:           output,
 		InsecureSkipTLS:  insecureSkipTLS,
 		CleanupTempFiles: cleanupTempFiles,
 		Verbose:          app.Config.Verbose,
 		ExcludePatterns:  excludePatterns,
-	}, syncOrchestrator, app.KubeconfigHandler)
-	if err != nil {
-		return fmt.Errorf("sync failed: %w", err)
+		ExcludeTypes:     excludeTypes,
+		IgnoreBackoff:    ignoreBackoff,
+		CachedOnly:       cachedOnly,
+		ExcludeServers:   excludeServers,
+		Offline:          offline,
+		Force:            force,
+		MaxDuration:      maxDuration,
+		CheckEndpoints:   checkEndpoints,
+		AuthRetries:      authRetries,
+	}
+	if fromFile != "" {
+		clusters, err := app.ReadClusterList(fromFile)
+		if err != nil {
+			return commands.SyncRequest{}, err
+		}
+		req.Clusters = clusters
+	}
+	return req, nil
+}
+
+// printSyncOutcome prints the report of the sync as JSON or text, unless --quiet is set. downloadErr is
+// set if some downloads failed.
+func printSyncOutcome(cmd *cobra.Command, report *commands.SyncReport, downloadErr error) error {
+	if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
 	}
 
-	fmt.Fprintln(cmd.OutOrStdout(), "Sync completed successfully")
+	if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+		return nil
+	}
+
+	heading := "Sync completed successfully"
+	switch {
+	case report != nil && report.Offline:
+		heading = "Rebuilt kubeconfig from cached kubeconfigs (offline)"
+	case report != nil && report.Truncated:
+		heading = "Sync stopped at --max-duration, merged the kubeconfigs downloaded"
+	case downloadErr != nil:
+		heading = "Sync completed with failed downloads, kept the existing contexts of their clusters"
+	}
+	printSyncReport(cmd.OutOrStdout(), heading, report)
 	return nil
 }
+
+// printSyncReport prints the outcome of a successful sync: the server summary, kubeconfig changes, skipped
+// servers, failures, name collisions and the timing breakdown.
+func printSyncReport(out io.Writer, heading string, report *commands.SyncReport) {
+	style := newStyle(out)
+	fmt.Fprintln(out, style.success(heading))
+	if report == nil {
+		return
+	}
+	printServerSummary(out, style, report.Servers)
+	printChanges(out, report.Changes)
+	if report.Truncated {
+		fmt.Fprintln(out, style.warning(fmt.Sprintf(
+			"Downloaded %d of %d kubeconfigs in time; contexts of the other clusters were kept as they were",
+			report.KubeconfigsDownloaded, report.ClustersFound)))
+	}
+	printSkippedServers(out, style, report.Skipped)
+	for _, server := range report.Servers {
+		if server.SkippedLocal {
+			fmt.Fprintf(out, "Skipped the local cluster of %s (use --include-local to sync it)\n", server.ServerURL)
+		}
+	}
+	for _, warning := range report.Warnings {
+		fmt.Fprintln(out, style.warning(warning))
+	}
+	for _, endpoint := range report.Unresolved {
+		fmt.Fprintln(out, style.warning(fmt.Sprintf(
+			"%s does not resolve from this machine; kubectl cannot reach %d context(s) using it, e.g. %s",
+			endpoint.Host, len(endpoint.Contexts), endpoint.Contexts[0])))
+	}
+	if summary := commands.ServerFailureSummary(report.Servers); summary != "" {
+		fmt.Fprintln(out, style.failure(summary))
+	}
+	for _, fragment := range report.Invalid {
+		fmt.Fprintln(out, style.failure(fmt.Sprintf("Invalid kubeconfig for %s on %s, not merged: %s",
+			fragment.Cluster, fragment.ServerURL, strings.Join(fragment.Problems, "; "))))
+		if fragment.Quarantine != "" {
+			fmt.Fprintf(out, "  Quarantined to %s\n", fragment.Quarantine)
+		}
+	}
+	printCollisions(out, report.Collisions)
+	if len(report.Generated) > 0 {
+		fmt.Fprintf(out, "Generated %d files from templates\n", len(report.Generated))
+	}
+	printTiming(out, report)
+}
+
+// printChanges summarises what the sync changed in the merged kubeconfig, listing the contexts added,
+// removed and pointed at a new endpoint. Refreshed tokens are only counted.
+func printChanges(out io.Writer, changes *commands.KubeconfigChanges) {
+	switch {
+	case changes == nil:
+		return
+	case changes.Empty():
+		fmt.Fprintln(out, "No contexts changed in the kubeconfig")
+		return
+	}
+	fmt.Fprintf(out, "Kubeconfig changes: %d added, %d removed, %d token(s) refreshed, %d endpoint(s) changed\n",
+		len(changes.Added), len(changes.Removed), len(changes.TokenRefreshed), len(changes.EndpointChanged))
+	for _, name := range changes.Added {
+		fmt.Fprintf(out, "  + %s\n", name)
+	}
+	for _, name := range changes.Removed {
+		fmt.Fprintf(out, "  - %s\n", name)
+	}
+	for _, name := range changes.EndpointChanged {
+		fmt.Fprintf(out, "  ~ %s (endpoint changed)\n", name)
+	}
+}
+
+// printSkippedServers lists the servers a sync did not contact, with the reason for each.
+func printSkippedServers(out io.Writer, style style, skipped []commands.SkippedServer) {
+	if len(skipped) == 0 {
+		return
+	}
+	fmt.Fprintln(out, style.warning(fmt.Sprintf("Skipped %d server(s):", len(skipped))))
+	for _, server := range skipped {
+		fmt.Fprintf(out, "  %s: %s\n", server.ServerURL, server.Reason)
+	}
+}
+
+// printSkippedOnFailure lists why each server was skipped when a sync failed because none was left.
+func printSkippedOnFailure(out io.Writer, err error) {
+	var noServersErr *commands.NoServersError
+	if errors.As(err, &noServersErr) {
+		printSkippedServers(out, newStyle(out), noServersErr.Skipped)
+	}
+}
+
+// printServerSummary prints a table of each server's clusters by outcome and how long the server took.
+func printServerSummary(out io.Writer, style style, servers []commands.ServerReport) {
+	if len(servers) == 0 {
+		return
+	}
+
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0) //nolint:mnd // Column padding
+	fmt.Fprintln(w, "SERVER\tFOUND\tDOWNLOADED\tEXCLUDED\tFAILED\tDURATION")
+	for _, server := range servers {
+		if server.Error != "" {
+			fmt.Fprintf(w, "%s\t-\t-\t-\t%s\t%s\n",
+				server.ServerURL, style.failure(fmt.Sprintf("server (%s)", server.Category)),
+				formatDuration(server.Duration))
+			continue
+		}
+		// Invalid kubeconfigs are listed after the table, and count as failed in it
+		failedCount := server.Failed + server.Invalid
+		failed := style.plain(strconv.Itoa(failedCount))
+		if failedCount > 0 {
+			failed = style.failure(strconv.Itoa(failedCount))
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%s\n",
+			server.ServerURL, server.Clusters, server.Downloaded, server.Excluded, failed,
+			formatDuration(server.Duration))
+	}
+	_ = w.Flush()
+}
+
+// printCollisions prints the clusters whose names exist on several servers, with a suggested alias
+// for each of their contexts.
+func printCollisions(out io.Writer, collisions []commands.ContextCollision) {
+	for _, collision := range collisions {
+		fmt.Fprintf(out, "\nClusters named %q exist on %d servers:\n", collision.Name, len(collision.Contexts))
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0) //nolint:mnd // Column padding
+		fmt.Fprintln(w, "  CONTEXT\tSERVER\tSUGGESTED ALIAS")
+		for _, context := range collision.Contexts {
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", context.Context, context.ServerURL, context.SuggestedAlias)
+		}
+		_ = w.Flush()
+	}
+}
+
+// printTiming prints the timing breakdown of a completed sync.
+func printTiming(out io.Writer, report *commands.SyncReport) {
+	timing := report.Timing
+	download := timing.Download
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0) //nolint:mnd // Column padding
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Total\t%s\n", formatDuration(timing.Total))
+	fmt.Fprintf(w, "Discovery\t%s\n", formatDuration(timing.Discovery))
+	for _, auth := range timing.Auth {
+		status := ""
+		if auth.Failed {
+			status = " (failed)"
+		}
+		fmt.Fprintf(w, "  auth %s\t%s%s\n", auth.ServerURL, formatDuration(auth.Duration), status)
+	}
+	fmt.Fprintf(w, "Downloads\t%d in %s, %s/s (p50 %s, p90 %s, p99 %s, max %s)\n",
+		download.Count,
+		formatDuration(download.Wall),
+		formatBytes(int64(download.BytesPerSecond)),
+		formatDuration(download.P50),
+		formatDuration(download.P90),
+		formatDuration(download.P99),
+		formatDuration(download.Max))
+	if download.Failed > 0 {
+		fmt.Fprintf(w, "  failed\t%d\n", download.Failed)
+	}
+	fmt.Fprintf(w, "Merge\t%s\n", formatDuration(timing.Merge))
+	_ = w.Flush()
+}
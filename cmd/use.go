@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"cowpoke/internal/commands"
+
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals // Cobra CLI pattern for subcommand
+var useCmd = &cobra.Command{
+	Use:   "use <context-or-cluster>",
+	Short: "Switch the current context of the merged kubeconfig",
+	Long: `Set the current context of the merged kubeconfig to one of the contexts cowpoke manages.
+
+The argument is matched against context and cluster names: an exact name wins, otherwise a unique prefix,
+substring or abbreviation (e.g. "preu" for "prod-eu") is enough. If a cluster name exists on several
+servers, use the full context name instead.`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runUse,
+	ValidArgsFunction: completeContexts,
+}
+
+//nolint:gochecknoinits // Cobra CLI pattern for command registration
+func init() {
+	rootCmd.AddCommand(useCmd)
+
+	useCmd.Flags().String("kubeconfig", "", "Merged kubeconfig to update (default: ~/.kube/config)")
+}
+
+func runUse(cmd *cobra.Command, args []string) error {
+	app := GetApp()
+	if app == nil {
+		return errors.New("application not initialized")
+	}
+
+	kubeconfig, _ := cmd.Flags().GetString("kubeconfig")
+
+	useCommand := commands.NewUseCommand(app.KubeconfigHandler, app.ConfigProvider, app.Logger)
+	result, err := useCommand.Execute(cmd.Context(), commands.UseRequest{
+		Query:      args[0],
+		Kubeconfig: kubeconfig,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to switch context: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Switched to context %s (server %s)\n",
+		newStyle(cmd.OutOrStdout()).success(result.Context.Name), result.Context.Owner.ServerURL)
+	return nil
+}
+
+// completeContexts completes the names of cowpoke-managed contexts and their clusters.
+func completeContexts(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	app := GetApp()
+	if app == nil || len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	kubeconfig, _ := cmd.Flags().GetString("kubeconfig")
+
+	useCommand := commands.NewUseCommand(app.KubeconfigHandler, app.ConfigProvider, app.Logger)
+	contexts, err := useCommand.Contexts(cmd.Context(), kubeconfig)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	completions := make([]string, 0, 2*len(contexts)) //nolint:mnd // Context and cluster name each
+	seen := make(map[string]bool)
+	for _, kubeContext := range contexts {
+		for _, name := range []string{kubeContext.ClusterName, kubeContext.Name} {
+			if !seen[name] {
+				seen[name] = true
+				completions = append(completions, name)
+			}
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
@@ -2,6 +2,7 @@ package filesystem
 
 import (
 	"os"
+	"time"
 )
 
 // Adapter provides file system operations.
@@ -17,6 +18,11 @@ func (a *Adapter) ReadFile(path string) ([]byte, error) {
 	return os.ReadFile(path)
 }
 
+// ReadDir lists the entries of a directory.
+func (a *Adapter) ReadDir(path string) ([]os.DirEntry, error) {
+	return os.ReadDir(path)
+}
+
 // WriteFile writes data to a file.
 func (a *Adapter) WriteFile(path string, data []byte, perm os.FileMode) error {
 	return os.WriteFile(path, data, perm)
@@ -32,6 +38,11 @@ func (a *Adapter) Remove(path string) error {
 	return os.Remove(path)
 }
 
+// Rename moves a file, replacing the destination if it exists.
+func (a *Adapter) Rename(oldPath, newPath string) error {
+	return os.Rename(oldPath, newPath)
+}
+
 // Stat returns file info.
 func (a *Adapter) Stat(path string) (os.FileInfo, error) {
 	return os.Stat(path)
@@ -42,6 +53,16 @@ func (a *Adapter) Chmod(path string, perm os.FileMode) error {
 	return os.Chmod(path, perm)
 }
 
+// Chown changes the file owner and group. An ID of -1 leaves that one unchanged.
+func (a *Adapter) Chown(path string, uid, gid int) error {
+	return os.Chown(path, uid, gid)
+}
+
+// Chtimes changes the file access and modification times.
+func (a *Adapter) Chtimes(path string, atime, mtime time.Time) error {
+	return os.Chtimes(path, atime, mtime)
+}
+
 // UserHomeDir returns the user's home directory.
 func (a *Adapter) UserHomeDir() (string, error) {
 	return os.UserHomeDir()
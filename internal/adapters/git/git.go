@@ -0,0 +1,37 @@
+// Package git runs the git command-line tool.
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Adapter runs git commands through the git binary on the PATH.
+type Adapter struct{}
+
+// New creates a new git adapter.
+func New() *Adapter {
+	return &Adapter{}
+}
+
+// Run runs git with args in dir and returns its standard output.
+func (a *Adapter) Run(ctx context.Context, dir string, args []string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+		}
+		return "", fmt.Errorf("failed to run git: %w", err)
+	}
+	return stdout.String(), nil
+}
@@ -9,23 +9,34 @@ import (
 	"strings"
 	"time"
 
+	"cowpoke/internal/domain"
+	"cowpoke/internal/logging"
+
 	"github.com/go-resty/resty/v2"
 	"golang.org/x/time/rate"
 )
 
 const (
-	// HTTP client retry configuration.
+	// HTTP client retry configuration. The wait doubles after each attempt, up to the maximum.
 	defaultRetryCount       = 3
+	defaultRetryWaitTime    = time.Second
 	defaultRetryMaxWaitTime = 5 * time.Second
 
 	// Rate limiting configuration.
 	rateLimitRequestsPerSecond = 10
 	rateLimitBurst             = 20
+
+	// Connection pool configuration.
+	defaultMaxIdleConnsPerHost = 16
+	idleConnTimeout            = 90 * time.Second
 )
 
 const (
 	// Standard HTTP content types.
 	contentTypeJSON = "application/json"
+
+	// correlationHeader carries the correlation ID so cowpoke requests can be found in Rancher's audit log.
+	correlationHeader = "X-Request-ID"
 )
 
 // Adapter is an HTTP client adapter using resty with rate limiting.
@@ -33,19 +44,74 @@ type Adapter struct {
 	client  *resty.Client
 	limiter *rate.Limiter
 	logger  *slog.Logger
+	// clock is waited on between retries.
+	clock domain.Clock
+
+	// debugLogger receives a record of every round trip when HTTP debugging is enabled.
+	debugLogger *slog.Logger
+	// recordDir and replayDir enable recording exchanges as fixtures and serving them back.
+	recordDir string
+	replayDir string
+	userAgent string
+	// routes holds per-host client certificates and tunnels; hosts routes them once the adapter is built.
+	routes map[string]*hostRoute
+	hosts  *hostTransport
+
+	maxIdleConnsPerHost int
+}
+
+// Option configures an Adapter.
+type Option func(*Adapter)
+
+// WithMaxIdleConnsPerHost sets how many idle keep-alive connections are kept open to each host.
+func WithMaxIdleConnsPerHost(n int) Option {
+	return func(a *Adapter) {
+		a.maxIdleConnsPerHost = n
+	}
+}
+
+// WithClock sets the clock waited on between retries. Defaults to the system clock.
+func WithClock(clock domain.Clock) Option {
+	return func(a *Adapter) {
+		a.clock = clock
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(a *Adapter) {
+		a.userAgent = userAgent
+	}
 }
 
+// WithQuietRetries sends resty's own warnings about retried and failed requests to the debug log instead of
+// stderr, for requests whose failure the caller reports, or deliberately ignores, itself.
+func WithQuietRetries() Option {
+	return func(a *Adapter) {
+		a.client.SetLogger(restyLogger{logger: a.logger})
+	}
+}
+
+// restyLogger adapts a slog.Logger to resty's logger, logging everything at debug level.
+type restyLogger struct {
+	logger *slog.Logger
+}
+
+func (l restyLogger) Errorf(format string, v ...any) { l.logger.Debug(fmt.Sprintf(format, v...)) }
+func (l restyLogger) Warnf(format string, v ...any)  { l.logger.Debug(fmt.Sprintf(format, v...)) }
+func (l restyLogger) Debugf(format string, v ...any) { l.logger.Debug(fmt.Sprintf(format, v...)) }
+
 // NewAdapter creates a new HTTP adapter with rate limiting and retry capabilities.
-// Rate limit: 10 requests per second with burst of 20.
-func NewAdapter(timeout time.Duration, insecureSkipVerify bool, logger *slog.Logger) *Adapter {
+// Rate limit: 10 requests per second with burst of 20. Connections are kept alive and reused per host,
+// HTTP/2 is negotiated where the server supports it, and gzip responses are decompressed transparently.
+func NewAdapter(timeout time.Duration, insecureSkipVerify bool, logger *slog.Logger, opts ...Option) *Adapter {
 	client := resty.New().
 		SetTimeout(timeout).
 		SetRetryCount(defaultRetryCount).
-		SetRetryWaitTime(time.Second).
-		SetRetryMaxWaitTime(defaultRetryMaxWaitTime).
-		SetTLSClientConfig(&tls.Config{
-			InsecureSkipVerify: insecureSkipVerify, //nolint:gosec // User-configurable for self-signed certificates
-		})
+		// Resty sleeps on the wall clock, so its own wait is reduced to nothing and waitToRetry waits instead.
+		SetRetryWaitTime(0).
+		SetRetryMaxWaitTime(0).
+		AddRetryCondition(retryUnavailable)
 
 	// Rate limiter: 10 requests/second with burst of 20
 	limiter := rate.NewLimiter(rate.Limit(rateLimitRequestsPerSecond), rateLimitBurst)
@@ -55,6 +121,14 @@ func NewAdapter(timeout time.Duration, insecureSkipVerify bool, logger *slog.Log
 		return limiter.Wait(req.Context())
 	})
 
+	// Tag requests with the operation's correlation ID
+	client.OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+		if id := logging.CorrelationID(req.Context()); id != "" {
+			req.SetHeader(correlationHeader, id)
+		}
+		return nil
+	})
+
 	// Add logging middleware
 	client.OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
 		logger.DebugContext(req.Context(), "HTTP request",
@@ -74,18 +148,107 @@ func NewAdapter(timeout time.Duration, insecureSkipVerify bool, logger *slog.Log
 		return nil
 	})
 
-	return &Adapter{
-		client:  client,
-		limiter: limiter,
-		logger:  logger,
+	adapter := &Adapter{
+		client:              client,
+		limiter:             limiter,
+		logger:              logger,
+		clock:               domain.SystemClock{},
+		maxIdleConnsPerHost: defaultMaxIdleConnsPerHost,
+	}
+	for _, opt := range opts {
+		opt(adapter)
+	}
+	client.AddRetryHook(adapter.waitToRetry)
+
+	if adapter.userAgent != "" {
+		client.SetHeader("User-Agent", adapter.userAgent)
+	}
+
+	var transport http.RoundTripper = newTransport(insecureSkipVerify, adapter.maxIdleConnsPerHost)
+	if len(adapter.routes) > 0 {
+		adapter.hosts = &hostTransport{
+			next:   transport,
+			routes: adapter.routes,
+			newTransport: func() *http.Transport {
+				return newTransport(insecureSkipVerify, adapter.maxIdleConnsPerHost)
+			},
+		}
+		transport = adapter.hosts
+	}
+	if adapter.replayDir != "" {
+		logger.Info("Replaying recorded HTTP fixtures instead of contacting Rancher", "dir", adapter.replayDir)
+		transport = &replayTransport{dir: adapter.replayDir}
+	}
+	if adapter.recordDir != "" {
+		logger.Info("Recording HTTP fixtures", "dir", adapter.recordDir)
+		transport = &recordingTransport{next: transport, dir: adapter.recordDir}
+	}
+	if adapter.debugLogger != nil {
+		transport = &debugTransport{next: transport, logger: adapter.debugLogger}
+	}
+	client.SetTransport(transport)
+
+	return adapter
+}
+
+// retryUnavailable retries idempotent requests that failed in transport and those that a gateway answered
+// because Rancher is temporarily unavailable. Other methods are not retried, because a POST that reached
+// Rancher before the connection dropped may already have created a token. Certificate errors and other
+// failures are not retried either, because repeating the request cannot change the outcome.
+func retryUnavailable(resp *resty.Response, err error) bool {
+	if resp == nil || resp.Request == nil || !idempotent(resp.Request.Method) {
+		return false
+	}
+	if err != nil {
+		return domain.IsNetworkError(err) && !domain.IsCertificateError(err)
+	}
+	switch resp.StatusCode() {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// waitToRetry waits on the adapter's clock before a request that retryUnavailable retries is made again,
+// for a second after the first attempt and twice as long after each later one, up to the maximum wait. It
+// returns early if the request's context is canceled.
+func (a *Adapter) waitToRetry(resp *resty.Response, _ error) {
+	if resp == nil || resp.Request == nil || resp.Request.Attempt > defaultRetryCount {
+		return
 	}
+	wait := min(defaultRetryWaitTime<<(resp.Request.Attempt-1), defaultRetryMaxWaitTime)
+	select {
+	case <-a.clock.After(wait):
+	case <-resp.Request.Context().Done():
+	}
+}
+
+// idempotent reports whether repeating a request with method cannot change its effect.
+func idempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// newTransport creates the pooled transport shared by all requests to Rancher.
+func newTransport(insecureSkipVerify bool, maxIdleConnsPerHost int) *http.Transport {
+	transport, _ := http.DefaultTransport.(*http.Transport)
+	transport = transport.Clone()
+	transport.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: insecureSkipVerify, //nolint:gosec // User-configurable for self-signed certificates
+	}
+	transport.ForceAttemptHTTP2 = true
+	transport.DisableCompression = false
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	transport.MaxIdleConns = 0 // No global limit; each host is capped by MaxIdleConnsPerHost.
+	transport.IdleConnTimeout = idleConnTimeout
+	return transport
 }
 
 // Get performs a GET request.
 func (a *Adapter) Get(ctx context.Context, url string) (*http.Response, error) {
 	resp, err := a.client.R().SetContext(ctx).SetDoNotParseResponse(true).Get(url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute GET request: %w", err)
+		return nil, fmt.Errorf("failed to execute GET request: %w", domain.WrapNetworkError(err))
 	}
 	return resp.RawResponse, nil
 }
@@ -98,7 +261,7 @@ func (a *Adapter) GetWithAuth(ctx context.Context, url, token string) (*http.Res
 		SetDoNotParseResponse(true).
 		Get(url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute authenticated GET request: %w", err)
+		return nil, fmt.Errorf("failed to execute authenticated GET request: %w", domain.WrapNetworkError(err))
 	}
 	return resp.RawResponse, nil
 }
@@ -121,7 +284,7 @@ func (a *Adapter) Post(
 		if strings.Contains(err.Error(), "unsupported 'Body' type/value") {
 			return nil, fmt.Errorf("failed to prepare POST payload: %w", err)
 		}
-		return nil, fmt.Errorf("failed to execute POST request: %w", err)
+		return nil, fmt.Errorf("failed to execute POST request: %w", domain.WrapNetworkError(err))
 	}
 	return resp.RawResponse, nil
 }
@@ -147,7 +310,20 @@ func (a *Adapter) PostWithAuth(
 		if strings.Contains(err.Error(), "unsupported 'Body' type/value") {
 			return nil, fmt.Errorf("failed to prepare POST payload: %w", err)
 		}
-		return nil, fmt.Errorf("failed to execute authenticated POST request: %w", err)
+		return nil, fmt.Errorf("failed to execute authenticated POST request: %w", domain.WrapNetworkError(err))
+	}
+	return resp.RawResponse, nil
+}
+
+// DeleteWithAuth performs a DELETE request with authentication.
+func (a *Adapter) DeleteWithAuth(ctx context.Context, url, token string) (*http.Response, error) {
+	resp, err := a.client.R().
+		SetContext(ctx).
+		SetAuthToken(token).
+		SetDoNotParseResponse(true).
+		Delete(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute authenticated DELETE request: %w", domain.WrapNetworkError(err))
 	}
 	return resp.RawResponse, nil
 }
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unavailableServer answers the first failures requests with 503 and the rest with 200, counting them.
+func unavailableServer(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if requests.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestAdapter_Get_WaitsOnClockBetweenRetries(t *testing.T) {
+	// Arrange
+	server, requests := unavailableServer(t, 2)
+	clock := testutil.NewClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	adapter := NewAdapter(5*time.Second, false, testutil.Logger(), WithClock(clock), WithQuietRetries())
+	done := make(chan *http.Response, 1)
+
+	// Act
+	go func() {
+		resp, err := adapter.Get(context.Background(), server.URL)
+		assert.NoError(t, err)
+		done <- resp
+	}()
+
+	// Assert
+	require.Eventually(t, func() bool { return clock.Waiters() == 1 }, 5*time.Second, time.Millisecond)
+	assert.Equal(t, int32(1), requests.Load(), "no retry before the clock moves")
+	clock.Advance(time.Second)
+	require.Eventually(t, func() bool { return clock.Waiters() == 1 && requests.Load() == 2 },
+		5*time.Second, time.Millisecond)
+	clock.Advance(time.Second)
+	assert.Equal(t, 1, clock.Waiters(), "the second retry waits twice as long")
+	clock.Advance(time.Second)
+	resp := <-done
+	require.NotNil(t, resp)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestAdapter_Get_StopsWaitingToRetryWhenCanceled(t *testing.T) {
+	// Arrange
+	server, requests := unavailableServer(t, 1)
+	clock := testutil.NewClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	adapter := NewAdapter(5*time.Second, false, testutil.Logger(), WithClock(clock), WithQuietRetries())
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	// Act
+	go func() {
+		resp, err := adapter.Get(ctx, server.URL)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		done <- err
+	}()
+	require.Eventually(t, func() bool { return clock.Waiters() == 1 }, 5*time.Second, time.Millisecond)
+	cancel()
+
+	// Assert
+	require.ErrorIs(t, <-done, context.Canceled)
+	assert.Equal(t, int32(1), requests.Load())
+}
@@ -0,0 +1,165 @@
+package http
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// maxDebugBody caps how much of each request and response body is written to the debug log.
+	maxDebugBody = 64 * 1024
+
+	// redacted replaces sensitive values in logged bodies.
+	redacted = "[REDACTED]"
+)
+
+//nolint:gochecknoglobals // Read-only lookup tables
+var (
+	// sensitiveKeys are JSON fields whose values are never logged or recorded. Rancher returns tokens
+	// in login responses and embeds them in generated kubeconfigs.
+	sensitiveKeys = map[string]bool{
+		"password": true,
+		"token":    true,
+		"config":   true,
+		"secret":   true,
+	}
+
+	// kubeconfigCredential matches credential lines in a kubeconfig, capturing everything up to the value.
+	kubeconfigCredential = regexp.MustCompile(
+		`(?m)^(\s*(?:token|password):\s*).+$`)
+
+	// kubeconfigCredentialData matches base64 credential lines in a kubeconfig. Their replacement is
+	// base64 too, so that the kubeconfig still loads.
+	kubeconfigCredentialData = regexp.MustCompile(
+		`(?m)^(\s*(?:client-key-data|client-certificate-data):\s*).+$`)
+)
+
+// WithDebugLogger logs every request and response, with sensitive fields redacted, to logger.
+func WithDebugLogger(logger *slog.Logger) Option {
+	return func(a *Adapter) {
+		a.debugLogger = logger
+	}
+}
+
+// debugTransport logs each round trip, including retries, to a debug logger.
+type debugTransport struct {
+	next   http.RoundTripper
+	logger *slog.Logger
+}
+
+// RoundTrip performs the request and logs it together with its response.
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			requestBody, _ = io.ReadAll(io.LimitReader(body, maxDebugBody))
+			body.Close()
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	latency := time.Since(start)
+
+	attrs := []any{
+		"method", req.Method,
+		"url", req.URL.String(),
+		"latency", latency,
+		"request_body", redactBody(requestBody),
+	}
+	if err != nil {
+		t.logger.DebugContext(req.Context(), "HTTP request failed", append(attrs, "error", err)...)
+		return nil, err
+	}
+
+	responseBody, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+	if readErr != nil {
+		t.logger.DebugContext(req.Context(), "HTTP response body unreadable",
+			append(attrs, "status", resp.StatusCode, "error", readErr)...)
+		return nil, readErr
+	}
+
+	t.logger.DebugContext(req.Context(), "HTTP exchange",
+		append(attrs,
+			"status", resp.StatusCode,
+			"response_bytes", len(responseBody),
+			"response_body", redactBody(truncate(responseBody)))...)
+	return resp, nil
+}
+
+// truncate limits body to maxDebugBody bytes.
+func truncate(body []byte) []byte {
+	if len(body) > maxDebugBody {
+		return body[:maxDebugBody]
+	}
+	return body
+}
+
+// redactBody returns body as a string with sensitive JSON fields replaced. Bodies that are not JSON
+// are summarized by length only, since they cannot be redacted reliably.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	redactedBody, ok := redactJSON(body, false)
+	if !ok {
+		return summarize(body)
+	}
+	return string(redactedBody)
+}
+
+// summarize describes body by its length and detected content type only.
+func summarize(body []byte) string {
+	return fmt.Sprintf("<%d bytes of %s>", len(body), http.DetectContentType(body))
+}
+
+// redactJSON returns body with sensitive fields replaced, or false if body is not JSON. With
+// keepKubeconfigs, generated kubeconfigs keep their structure and only their credentials are replaced.
+func redactJSON(body []byte, keepKubeconfigs bool) ([]byte, bool) {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil, false
+	}
+
+	redactValue(value, keepKubeconfigs)
+	data, err := json.Marshal(value)
+	if err != nil {
+		return []byte(redacted), true
+	}
+	return data, true
+}
+
+// redactValue replaces sensitive fields in a decoded JSON value in place.
+func redactValue(value any, keepKubeconfigs bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if !sensitiveKeys[strings.ToLower(key)] {
+				redactValue(field, keepKubeconfigs)
+				continue
+			}
+			if kubeconfig, ok := field.(string); ok && keepKubeconfigs && strings.EqualFold(key, "config") {
+				kubeconfig = kubeconfigCredential.ReplaceAllString(kubeconfig, `${1}"`+redacted+`"`)
+				v[key] = kubeconfigCredentialData.ReplaceAllString(kubeconfig,
+					"${1}"+base64.StdEncoding.EncodeToString([]byte(redacted)))
+				continue
+			}
+			v[key] = redacted
+		}
+	case []any:
+		for _, item := range v {
+			redactValue(item, keepKubeconfigs)
+		}
+	}
+}
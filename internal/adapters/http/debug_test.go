@@ -0,0 +1,77 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "empty body",
+			body:     "",
+			expected: "",
+		},
+		{
+			name:     "login request",
+			body:     `{"username":"admin","password":"hunter2","responseType":"token"}`,
+			expected: `{"password":"[REDACTED]","responseType":"token","username":"admin"}`,
+		},
+		{
+			name:     "token response",
+			body:     `{"token":"token-abc:secret","ttl":57600000,"userId":"u-1"}`,
+			expected: `{"token":"[REDACTED]","ttl":57600000,"userId":"u-1"}`,
+		},
+		{
+			name:     "sensitive keys in any case",
+			body:     `{"Password":"hunter2","SECRET":"s3cret"}`,
+			expected: `{"Password":"[REDACTED]","SECRET":"[REDACTED]"}`,
+		},
+		{
+			name: "nested generated kubeconfig",
+			body: `{"data":[{"id":"c-m-prod","action":{"type":"generateKubeConfigOutput",` +
+				`"config":"users:\n- user:\n    token: kubeconfig-user-abc:secret\n"}}]}`,
+			expected: `{"data":[{"action":{"config":"[REDACTED]","type":"generateKubeConfigOutput"},"id":"c-m-prod"}]}`,
+		},
+		{
+			name:     "nested objects without secrets",
+			body:     `{"data":[{"id":"c-m-prod","name":"prod"}]}`,
+			expected: `{"data":[{"id":"c-m-prod","name":"prod"}]}`,
+		},
+		{
+			name:     "HTML",
+			body:     "<html><body>token=secret</body></html>",
+			expected: "<38 bytes of text/html; charset=utf-8>",
+		},
+		{
+			name:     "kubeconfig YAML",
+			body:     "apiVersion: v1\nusers:\n- user:\n    token: secret\n",
+			expected: "<48 bytes of text/plain; charset=utf-8>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, redactBody([]byte(tt.body)))
+		})
+	}
+}
+
+func TestRedactJSON_KeepsKubeconfigStructure(t *testing.T) {
+	// Arrange
+	body := `{"type":"generateKubeConfigOutput","config":"users:\n- name: prod\n  user:\n` +
+		`    token: kubeconfig-user-abc:secret\n    client-key-data: a2V5\n"}`
+
+	// Act
+	got, ok := redactJSON([]byte(body), true)
+
+	// Assert
+	assert.True(t, ok)
+	assert.JSONEq(t, `{"type":"generateKubeConfigOutput","config":"users:\n- name: prod\n  user:\n`+
+		`    token: \"[REDACTED]\"\n    client-key-data: W1JFREFDVEVEXQ==\n"}`, string(got))
+}
@@ -0,0 +1,248 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	// RecordEnvVar names a directory to record sanitized Rancher API exchanges into.
+	RecordEnvVar = "COWPOKE_RECORD"
+	// ReplayEnvVar names a directory of recorded exchanges to serve instead of contacting Rancher.
+	ReplayEnvVar = "COWPOKE_REPLAY"
+
+	// fixtureDirPerm and fixtureFilePerm restrict recordings to the current user.
+	fixtureDirPerm  = 0o700
+	fixtureFilePerm = 0o600
+)
+
+// fixture is one recorded request and its response.
+type fixture struct {
+	Method       string `json:"method"`
+	Host         string `json:"host,omitempty"`
+	URL          string `json:"url"`
+	RequestBody  string `json:"requestBody,omitempty"`
+	Status       int    `json:"status"`
+	ContentType  string `json:"contentType,omitempty"`
+	ResponseBody string `json:"responseBody"`
+}
+
+// key identifies the requests a fixture answers, so that a recording of several servers answers each
+// from its own responses. Fixtures recorded without a host answer requests to any server.
+func (f fixture) key() string {
+	return f.Method + " " + f.Host + f.URL
+}
+
+// requestKey returns the fixture key for req, or with anyHost the key of fixtures recorded without a host.
+func requestKey(req *http.Request, anyHost bool) string {
+	if anyHost {
+		return req.Method + " " + req.URL.RequestURI()
+	}
+	return req.Method + " " + req.URL.Host + req.URL.RequestURI()
+}
+
+// WithRecording writes every exchange, with credentials redacted, to a numbered file in dir.
+func WithRecording(dir string) Option {
+	return func(a *Adapter) {
+		a.recordDir = dir
+	}
+}
+
+// WithReplay answers requests from the exchanges recorded in dir instead of contacting Rancher.
+func WithReplay(dir string) Option {
+	return func(a *Adapter) {
+		a.replayDir = dir
+	}
+}
+
+// FixturesFromEnv returns the options for the recording or replay requested through RecordEnvVar and
+// ReplayEnvVar.
+func FixturesFromEnv() []Option {
+	var opts []Option
+	if dir := os.Getenv(ReplayEnvVar); dir != "" {
+		opts = append(opts, WithReplay(dir))
+	}
+	if dir := os.Getenv(RecordEnvVar); dir != "" {
+		opts = append(opts, WithRecording(dir))
+	}
+	return opts
+}
+
+// recordingTransport saves each exchange as a fixture.
+type recordingTransport struct {
+	next http.RoundTripper
+	dir  string
+
+	mu    sync.Mutex
+	count int
+}
+
+// RoundTrip performs the request and records it together with its response.
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			requestBody, _ = io.ReadAll(body)
+			body.Close()
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	if saveErr := t.save(fixture{
+		Method:       req.Method,
+		Host:         req.URL.Host,
+		URL:          req.URL.RequestURI(),
+		RequestBody:  string(sanitize(requestBody)),
+		Status:       resp.StatusCode,
+		ContentType:  resp.Header.Get("Content-Type"),
+		ResponseBody: string(sanitize(responseBody)),
+	}); saveErr != nil {
+		return nil, saveErr
+	}
+	return resp, nil
+}
+
+// save writes f to the next numbered file so replay sees exchanges in the order they happened.
+func (t *recordingTransport) save(f fixture) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.count == 0 {
+		if mkdirErr := os.MkdirAll(t.dir, fixtureDirPerm); mkdirErr != nil {
+			return fmt.Errorf("failed to create fixture directory: %w", mkdirErr)
+		}
+		// Continue numbering after fixtures from earlier runs so they are kept.
+		if existing, globErr := filepath.Glob(filepath.Join(t.dir, "*.json")); globErr == nil {
+			t.count = len(existing)
+		}
+	}
+	t.count++
+	path := filepath.Join(t.dir, fmt.Sprintf("%04d.json", t.count))
+	if writeErr := os.WriteFile(path, data, fixtureFilePerm); writeErr != nil {
+		return fmt.Errorf("failed to write fixture: %w", writeErr)
+	}
+	return nil
+}
+
+// sanitize redacts credentials from a recorded body. Generated kubeconfigs keep their structure so they
+// still merge on replay. Bodies that are not JSON cannot be redacted reliably, so only their length and
+// type are recorded.
+func sanitize(body []byte) []byte {
+	if len(body) == 0 {
+		return nil
+	}
+	if redactedBody, ok := redactJSON(body, true); ok {
+		return redactedBody
+	}
+	return []byte(summarize(body))
+}
+
+// replayTransport answers requests from recorded fixtures. Repeated requests receive the recorded
+// responses in order; the last one is reused once they run out.
+type replayTransport struct {
+	dir string
+
+	once     sync.Once
+	loadErr  error
+	mu       sync.Mutex
+	fixtures map[string][]fixture
+}
+
+// RoundTrip returns the next recorded response for req.
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.once.Do(func() { t.loadErr = t.load() })
+	if t.loadErr != nil {
+		return nil, t.loadErr
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := requestKey(req, false)
+	recorded := t.fixtures[key]
+	if len(recorded) == 0 {
+		key = requestKey(req, true)
+		recorded = t.fixtures[key]
+	}
+	if len(recorded) == 0 {
+		return nil, fmt.Errorf("no recorded response for %s in %s", requestKey(req, false), t.dir)
+	}
+	f := recorded[0]
+	if len(recorded) > 1 {
+		t.fixtures[key] = recorded[1:]
+	}
+
+	header := http.Header{}
+	if f.ContentType != "" {
+		header.Set("Content-Type", f.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Status, http.StatusText(f.Status)),
+		StatusCode:    f.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(f.ResponseBody)),
+		ContentLength: int64(len(f.ResponseBody)),
+		Request:       req,
+	}, nil
+}
+
+// load reads every fixture in the replay directory in file name order.
+func (t *replayTransport) load() error {
+	entries, err := os.ReadDir(t.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read fixture directory: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" {
+			names = append(names, entry.Name())
+		}
+	}
+	slices.Sort(names)
+
+	t.fixtures = map[string][]fixture{}
+	for _, name := range names {
+		data, readErr := os.ReadFile(filepath.Join(t.dir, name))
+		if readErr != nil {
+			return fmt.Errorf("failed to read fixture %s: %w", name, readErr)
+		}
+		var f fixture
+		if decodeErr := json.Unmarshal(data, &f); decodeErr != nil {
+			return fmt.Errorf("failed to decode fixture %s: %w", name, decodeErr)
+		}
+		t.fixtures[f.key()] = append(t.fixtures[f.key()], f)
+	}
+	if len(t.fixtures) == 0 {
+		return errors.New("no fixtures found in " + t.dir)
+	}
+	return nil
+}
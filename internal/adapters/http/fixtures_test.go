@@ -0,0 +1,185 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// generatedKubeconfig is a kubeconfig as Rancher generates it, with every credential kubectl accepts.
+const generatedKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://rancher.example.com/k8s/clusters/c-m-prod
+  name: prod
+contexts:
+- context:
+    cluster: prod
+    user: prod
+  name: prod
+users:
+- name: prod
+  user:
+    token: kubeconfig-user-abc:kubeconfig-secret
+    client-certificate-data: Y2xpZW50LWNlcnQtc2VjcmV0
+    client-key-data: Y2xpZW50LWtleS1zZWNyZXQ=
+- name: basic
+  user:
+    username: admin
+    password: basic-auth-secret
+`
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// respond answers every request with body, as Rancher would for the request's path.
+func respond(bodies map[string]string) roundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		body := bodies[req.URL.Host+req.URL.Path]
+		contentType := "application/json"
+		if !json.Valid([]byte(body)) {
+			contentType = "text/html"
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{contentType}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	}
+}
+
+func newRequest(t *testing.T, method, url, body string) *http.Request {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = bytes.NewReader([]byte(body))
+	}
+	req, err := http.NewRequest(method, url, reader)
+	require.NoError(t, err)
+	return req
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestRecordAndReplay_KeepsSecretsOffDisk(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	kubeconfigBody, err := json.Marshal(map[string]string{
+		"type":   "generateKubeConfigOutput",
+		"config": generatedKubeconfig,
+	})
+	require.NoError(t, err)
+	recorder := &recordingTransport{
+		next: respond(map[string]string{
+			"rancher.example.com/v3-public/localProviders/local": `{"token":"token-abc:login-secret","ttl":57600000}`,
+			"rancher.example.com/v3/clusters/c-m-prod":           string(kubeconfigBody),
+			"rancher.example.com/dashboard":                      "<html>session=html-secret</html>",
+		}),
+		dir: dir,
+	}
+	requests := []*http.Request{
+		newRequest(t, http.MethodPost, "https://rancher.example.com/v3-public/localProviders/local?action=login",
+			`{"username":"admin","password":"login-password"}`),
+		newRequest(t, http.MethodPost, "https://rancher.example.com/v3/clusters/c-m-prod?action=generateKubeconfig", ""),
+		newRequest(t, http.MethodGet, "https://rancher.example.com/dashboard", ""),
+	}
+
+	// Act
+	for _, req := range requests {
+		resp, rtErr := recorder.RoundTrip(req)
+		require.NoError(t, rtErr)
+		readBody(t, resp)
+	}
+
+	// Assert
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, len(requests))
+	for _, file := range files {
+		data, readErr := os.ReadFile(file)
+		require.NoError(t, readErr)
+		for _, secret := range []string{
+			"login-password", "login-secret", "kubeconfig-secret", "Y2xpZW50LWNlcnQtc2VjcmV0",
+			"Y2xpZW50LWtleS1zZWNyZXQ=", "basic-auth-secret", "html-secret",
+		} {
+			assert.NotContains(t, string(data), secret, "%s records a secret", filepath.Base(file))
+		}
+	}
+
+	replayer := &replayTransport{dir: dir}
+	resp, err := replayer.RoundTrip(
+		newRequest(t, http.MethodPost, "https://rancher.example.com/v3/clusters/c-m-prod?action=generateKubeconfig", ""))
+	require.NoError(t, err)
+	var replayed map[string]string
+	require.NoError(t, json.Unmarshal([]byte(readBody(t, resp)), &replayed))
+	config, err := clientcmd.Load([]byte(replayed["config"]))
+	require.NoError(t, err, "replayed kubeconfig must still load")
+	assert.Equal(t, "https://rancher.example.com/k8s/clusters/c-m-prod", config.Clusters["prod"].Server)
+	assert.Equal(t, redacted, config.AuthInfos["prod"].Token)
+}
+
+func TestReplay_AnswersEachServerFromItsOwnRecording(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	recorder := &recordingTransport{
+		next: respond(map[string]string{
+			"a.example.com/v3/clusters": `{"data":[{"id":"c-a"}]}`,
+			"b.example.com/v3/clusters": `{"data":[{"id":"c-b"}]}`,
+		}),
+		dir: dir,
+	}
+	for _, url := range []string{"https://a.example.com/v3/clusters", "https://b.example.com/v3/clusters"} {
+		resp, err := recorder.RoundTrip(newRequest(t, http.MethodGet, url, ""))
+		require.NoError(t, err)
+		readBody(t, resp)
+	}
+	replayer := &replayTransport{dir: dir}
+
+	// Act
+	respB, errB := replayer.RoundTrip(newRequest(t, http.MethodGet, "https://b.example.com/v3/clusters", ""))
+	respA, errA := replayer.RoundTrip(newRequest(t, http.MethodGet, "https://a.example.com/v3/clusters", ""))
+
+	// Assert
+	require.NoError(t, errB)
+	require.NoError(t, errA)
+	assert.JSONEq(t, `{"data":[{"id":"c-b"}]}`, readBody(t, respB))
+	assert.JSONEq(t, `{"data":[{"id":"c-a"}]}`, readBody(t, respA))
+}
+
+func TestReplay_AnswersAnyServerFromRecordingsWithoutHost(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	data, err := json.Marshal(fixture{Method: http.MethodGet, URL: "/v3/clusters", Status: http.StatusOK,
+		ResponseBody: `{"data":[]}`})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001.json"), data, fixtureFilePerm))
+	replayer := &replayTransport{dir: dir}
+
+	// Act
+	resp, err := replayer.RoundTrip(newRequest(t, http.MethodGet, "https://rancher.example.com/v3/clusters", ""))
+
+	// Assert
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data":[]}`, readBody(t, resp))
+}
@@ -0,0 +1,206 @@
+package http
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"cowpoke/internal/domain"
+)
+
+// hostRoute holds the connection settings of a single host.
+type hostRoute struct {
+	clientCert *clientCertificate
+	tunnel     string
+}
+
+// clientCertificate locates the PEM certificate and key presented to one host.
+type clientCertificate struct {
+	certFile string
+	keyFile  string
+}
+
+// WithClientCertificate presents the certificate and key in certFile and keyFile when connecting to host
+// ("name" or "name:port", as in the server URL), for gateways that require mutual TLS. The files are
+// read on the first request to host.
+func WithClientCertificate(host, certFile, keyFile string) Option {
+	return func(a *Adapter) {
+		a.route(host).clientCert = &clientCertificate{certFile: certFile, keyFile: keyFile}
+	}
+}
+
+// WithTunnel connects to host through tunnel, either a SOCKS5 proxy URL such as "socks5://bastion:1080"
+// or "ssh <destination>" such as "ssh user@bastion". SSH tunnels are started on the first request to
+// host and stopped by CloseHost or Close.
+func WithTunnel(host, tunnel string) Option {
+	return func(a *Adapter) {
+		a.route(host).tunnel = tunnel
+	}
+}
+
+// route returns the connection settings of host, creating them if needed.
+func (a *Adapter) route(host string) *hostRoute {
+	if a.routes == nil {
+		a.routes = make(map[string]*hostRoute)
+	}
+	if a.routes[host] == nil {
+		a.routes[host] = &hostRoute{}
+	}
+	return a.routes[host]
+}
+
+// CloseHost stops the SSH tunnel to the host of serverURL and closes its idle connections. Later requests
+// to the host open them again. Hosts without their own connection settings share a pool that is left open.
+func (a *Adapter) CloseHost(serverURL string) error {
+	if a.hosts == nil {
+		return nil
+	}
+	parsed, err := url.Parse(serverURL)
+	if err != nil {
+		return fmt.Errorf("invalid server URL %q: %w", serverURL, err)
+	}
+	return a.hosts.closeHost(parsed.Host)
+}
+
+// Close stops any SSH tunnels the adapter started.
+func (a *Adapter) Close() error {
+	if a.hosts == nil {
+		return nil
+	}
+	return a.hosts.Close()
+}
+
+// hostTransport sends requests to hosts with their own connection settings through a dedicated transport,
+// and all other requests through next.
+type hostTransport struct {
+	next   http.RoundTripper
+	routes map[string]*hostRoute
+	// newTransport creates the pooled transport for a host before its settings are applied.
+	newTransport func() *http.Transport
+
+	// mu guards conns only, so that a tunnel starting for one host does not hold up requests to others.
+	mu    sync.Mutex
+	conns map[string]*hostConn
+}
+
+// hostConn is the transport of a single host and the SSH tunnel it connects through, if any.
+type hostConn struct {
+	// mu is held while the transport is created, so that concurrent first requests start a single tunnel.
+	mu        sync.Mutex
+	transport *http.Transport
+	tunnel    *sshTunnel
+}
+
+func (t *hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, ok := t.routes[req.URL.Host]; !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	transport, err := t.transport(req)
+	if err != nil {
+		return nil, err
+	}
+	return transport.RoundTrip(req)
+}
+
+// transport returns the transport for the request's host, creating it on first use.
+func (t *hostTransport) transport(req *http.Request) (http.RoundTripper, error) {
+	host := req.URL.Host
+
+	t.mu.Lock()
+	conn, ok := t.conns[host]
+	if !ok {
+		if t.conns == nil {
+			t.conns = make(map[string]*hostConn)
+		}
+		conn = &hostConn{}
+		t.conns[host] = conn
+	}
+	t.mu.Unlock()
+
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if conn.transport != nil {
+		return conn.transport, nil
+	}
+
+	route := t.routes[host]
+	transport := t.newTransport()
+	if route.clientCert != nil {
+		cert, err := tls.LoadX509KeyPair(route.clientCert.certFile, route.clientCert.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate for %s: %w", host, err)
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
+	if route.tunnel != "" {
+		proxyURL, tunnel, err := proxy(req, route.tunnel)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open tunnel to %s: %w", host, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+		conn.tunnel = tunnel
+	}
+
+	conn.transport = transport
+	return transport, nil
+}
+
+// proxy returns the SOCKS5 proxy for tunnel, starting an SSH tunnel if it names an SSH destination.
+func proxy(req *http.Request, tunnel string) (*url.URL, *sshTunnel, error) {
+	proxyURL, destination, err := domain.ParseTunnel(tunnel)
+	if err != nil || proxyURL != nil {
+		return proxyURL, nil, err
+	}
+
+	ssh, err := startSSHTunnel(req.Context(), destination)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ssh.proxyURL(), ssh, nil
+}
+
+// closeHost stops the SSH tunnel of host and closes its idle connections, waiting for a tunnel that is
+// still starting.
+func (t *hostTransport) closeHost(host string) error {
+	t.mu.Lock()
+	conn, ok := t.conns[host]
+	delete(t.conns, host)
+	t.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return conn.close()
+}
+
+// close stops the connection's SSH tunnel and closes its idle connections.
+func (c *hostConn) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.transport != nil {
+		c.transport.CloseIdleConnections()
+	}
+	if c.tunnel == nil {
+		return nil
+	}
+	return c.tunnel.Close()
+}
+
+// Close stops the SSH tunnels started so far.
+func (t *hostTransport) Close() error {
+	t.mu.Lock()
+	conns := t.conns
+	t.conns = nil
+	t.mu.Unlock()
+
+	var errs []error
+	for _, conn := range conns {
+		errs = append(errs, conn.close())
+	}
+	return errors.Join(errs...)
+}
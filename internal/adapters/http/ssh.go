@@ -0,0 +1,109 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+const (
+	// sshStartTimeout bounds how long an SSH tunnel may take to start accepting connections.
+	sshStartTimeout = 15 * time.Second
+	// sshPollInterval is how often a starting SSH tunnel is checked for readiness.
+	sshPollInterval = 100 * time.Millisecond
+)
+
+// sshTunnel is an "ssh -D" process serving a SOCKS5 proxy on a local port.
+type sshTunnel struct {
+	cmd    *exec.Cmd
+	addr   string
+	exited chan struct{}
+}
+
+// startSSHTunnel starts a SOCKS5 proxy through destination and waits until it accepts connections. SSH
+// runs in batch mode, so it must be able to authenticate without prompting, e.g. with an agent.
+func startSSHTunnel(ctx context.Context, destination string) (*sshTunnel, error) {
+	port, err := freePort()
+	if err != nil {
+		return nil, err
+	}
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+
+	//nolint:gosec // The destination comes from the user's own configuration and cannot be an option.
+	cmd := exec.Command("ssh", "-N", "-D", addr,
+		"-o", "BatchMode=yes",
+		"-o", "ExitOnForwardFailure=yes",
+		"--", destination)
+	if startErr := cmd.Start(); startErr != nil {
+		return nil, fmt.Errorf("failed to start ssh: %w", startErr)
+	}
+
+	tunnel := &sshTunnel{cmd: cmd, addr: addr, exited: make(chan struct{})}
+	go func() {
+		_ = cmd.Wait()
+		close(tunnel.exited)
+	}()
+
+	if waitErr := tunnel.wait(ctx); waitErr != nil {
+		_ = tunnel.Close()
+		return nil, fmt.Errorf("ssh tunnel through %s: %w", destination, waitErr)
+	}
+	return tunnel, nil
+}
+
+// wait blocks until the tunnel accepts connections, ssh exits, or the start timeout passes.
+func (t *sshTunnel) wait(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, sshStartTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(sshPollInterval)
+	defer ticker.Stop()
+	for {
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", t.addr)
+		if err == nil {
+			return conn.Close()
+		}
+		select {
+		case <-t.exited:
+			return errors.New("ssh exited before the tunnel was ready")
+		case <-ctx.Done():
+			return fmt.Errorf("tunnel not ready: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// proxyURL returns the SOCKS5 proxy served by the tunnel.
+func (t *sshTunnel) proxyURL() *url.URL {
+	return &url.URL{Scheme: "socks5", Host: t.addr}
+}
+
+// Close stops the ssh process.
+func (t *sshTunnel) Close() error {
+	select {
+	case <-t.exited:
+		return nil
+	default:
+	}
+	if err := t.cmd.Process.Kill(); err != nil {
+		return fmt.Errorf("failed to stop ssh tunnel: %w", err)
+	}
+	<-t.exited
+	return nil
+}
+
+// freePort returns a local TCP port that is currently unused.
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("failed to find a free port: %w", err)
+	}
+	defer listener.Close()
+	addr, _ := listener.Addr().(*net.TCPAddr)
+	return addr.Port, nil
+}
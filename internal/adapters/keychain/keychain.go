@@ -0,0 +1,174 @@
+// Package keychain stores secrets in the operating system keychain using its native CLI tools.
+package keychain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"cowpoke/internal/domain"
+)
+
+const (
+	// defaultService is the keychain service name all cowpoke secrets are stored under.
+	defaultService = "cowpoke"
+
+	// securityItemNotFound is the exit status of `security` when the item does not exist
+	// (errSecItemNotFound).
+	securityItemNotFound = 44
+)
+
+// Adapter stores secrets via `security` on macOS and `secret-tool` (libsecret) on Linux.
+type Adapter struct {
+	service string
+	goos    string
+}
+
+// New creates a new keychain adapter for the current platform.
+func New() *Adapter {
+	return &Adapter{
+		service: defaultService,
+		goos:    runtime.GOOS,
+	}
+}
+
+// Get returns the secret stored for account, or domain.ErrSecretNotFound.
+func (a *Adapter) Get(ctx context.Context, account string) (string, error) {
+	var cmd *exec.Cmd
+	switch a.goos {
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "security", "find-generic-password",
+			"-s", a.service, "-a", account, "-w")
+	case "linux":
+		cmd = exec.CommandContext(ctx, "secret-tool", "lookup", "service", a.service, "account", account)
+	default:
+		return "", a.unsupported()
+	}
+
+	out, _, err := run(cmd, nil)
+	if err != nil {
+		if a.missing(err) {
+			return "", domain.ErrSecretNotFound
+		}
+		// A locked keychain or a denied prompt is not a missing secret: callers would replace it.
+		return "", fmt.Errorf("failed to read secret from keychain: %w", err)
+	}
+
+	secret := strings.TrimRight(out, "\n")
+	if secret == "" {
+		return "", domain.ErrSecretNotFound
+	}
+	return secret, nil
+}
+
+// Set stores secret for account, replacing any existing value.
+func (a *Adapter) Set(ctx context.Context, account, secret string) error {
+	var cmd *exec.Cmd
+	var stdin []byte
+	switch a.goos {
+	case "darwin":
+		// Read as a command from stdin, so that the secret never appears in the process list.
+		cmd = exec.CommandContext(ctx, "security", "-i")
+		stdin = []byte(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+			quote(a.service), quote(account), quote(secret)))
+	case "linux":
+		cmd = exec.CommandContext(ctx, "secret-tool", "store",
+			"--label", fmt.Sprintf("%s: %s", a.service, account),
+			"service", a.service, "account", account)
+		stdin = []byte(secret)
+	default:
+		return a.unsupported()
+	}
+
+	_, errOut, err := run(cmd, stdin)
+	if err == nil && a.goos == "darwin" && errOut != "" {
+		// `security -i` exits zero when a command it reads fails.
+		err = errors.New(errOut)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store secret in keychain: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the secret for account. Deleting a missing secret is not an error.
+func (a *Adapter) Delete(ctx context.Context, account string) error {
+	var cmd *exec.Cmd
+	switch a.goos {
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "security", "delete-generic-password", "-s", a.service, "-a", account)
+	case "linux":
+		cmd = exec.CommandContext(ctx, "secret-tool", "clear", "service", a.service, "account", account)
+	default:
+		return a.unsupported()
+	}
+
+	if _, _, err := run(cmd, nil); err != nil {
+		if a.missing(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete secret from keychain: %w", err)
+	}
+	return nil
+}
+
+// unsupported returns the error used on platforms without a supported keychain tool.
+func (a *Adapter) unsupported() error {
+	return fmt.Errorf("keychain is not supported on %s", a.goos)
+}
+
+// missing reports whether err is the keychain tool exiting because the item does not exist. `secret-tool`
+// exits 1 without output then, and with an error message when it cannot reach the keychain.
+func (a *Adapter) missing(err error) bool {
+	var toolErr *toolError
+	if !errors.As(err, &toolErr) {
+		return false
+	}
+	if a.goos == "darwin" {
+		return toolErr.exitErr.ExitCode() == securityItemNotFound
+	}
+	return toolErr.exitErr.ExitCode() == 1 && toolErr.stderr == ""
+}
+
+// quote quotes s as a single argument of a `security -i` command.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// toolError is a keychain tool exiting non-zero.
+type toolError struct {
+	path    string
+	exitErr *exec.ExitError
+	stderr  string
+}
+
+func (e *toolError) Error() string {
+	return fmt.Sprintf("%s: %v: %s", e.path, e.exitErr, e.stderr)
+}
+
+func (e *toolError) Unwrap() error {
+	return e.exitErr
+}
+
+// run executes cmd, feeding stdin if provided, and returns its stdout and stderr.
+func run(cmd *exec.Cmd, stdin []byte) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", "", &toolError{path: cmd.Path, exitErr: exitErr, stderr: strings.TrimSpace(stderr.String())}
+		}
+		return "", "", fmt.Errorf("failed to run %s: %w", cmd.Path, err)
+	}
+	return stdout.String(), strings.TrimSpace(stderr.String()), nil
+}
@@ -0,0 +1,55 @@
+// Package shell runs user-configured commands through the platform's shell.
+package shell
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// Adapter runs commands with `sh -c`, or `cmd /C` on Windows.
+type Adapter struct {
+	stdout io.Writer
+	stderr io.Writer
+	goos   string
+}
+
+// New creates a new shell adapter that copies the output of commands to stdout and stderr.
+func New(stdout, stderr io.Writer) *Adapter {
+	return &Adapter{
+		stdout: stdout,
+		stderr: stderr,
+		goos:   runtime.GOOS,
+	}
+}
+
+// RunHook runs command with the shell, feeding it stdin and adding env to its environment.
+func (a *Adapter) RunHook(ctx context.Context, command string, env []string, stdin []byte) error {
+	var cmd *exec.Cmd
+	if a.goos == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = a.stdout
+	cmd.Stderr = a.stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("exited with status %d", exitErr.ExitCode())
+		}
+		return fmt.Errorf("failed to run command: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package terminal
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Choose lists options on the terminal and reads the user's pick, given either as a number or as the
+// option itself.
+func (a *Adapter) Choose(ctx context.Context, prompt string, options []string) (string, error) {
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	default:
+	}
+
+	if !a.IsInteractive() {
+		return "", errors.New("cannot prompt for a choice: non-interactive terminal")
+	}
+
+	fmt.Fprintf(a.stderr, "%s:\n", prompt)
+	for i, option := range options {
+		fmt.Fprintf(a.stderr, "  %d) %s\n", i+1, option)
+	}
+
+	reader := bufio.NewReader(a.stdin)
+	for {
+		fmt.Fprintf(a.stderr, "Choose [1-%d]: ", len(options))
+		line, err := reader.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if number, convErr := strconv.Atoi(answer); convErr == nil && number >= 1 && number <= len(options) {
+			return options[number-1], nil
+		}
+		if slices.Contains(options, answer) {
+			return answer, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read choice: %w", err)
+		}
+		fmt.Fprintf(a.stderr, "%q is not one of the options\n", answer)
+	}
+}
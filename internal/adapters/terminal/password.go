@@ -6,11 +6,20 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
+	"strings"
 
 	"golang.org/x/term"
 )
 
-// Adapter handles secure password input from terminal.
+const (
+	// askpassEnv names a program that reads passwords instead of the terminal, such as a GUI prompt.
+	askpassEnv = "COWPOKE_ASKPASS"
+	// sshAskpassEnv names such a program too, but is used as ssh uses it: only without a terminal.
+	sshAskpassEnv = "SSH_ASKPASS"
+)
+
+// Adapter handles secure password input and prompts on the terminal.
 type Adapter struct {
 	stdin  io.Reader
 	stderr io.Writer
@@ -24,7 +33,8 @@ func NewAdapter(stdin io.Reader, stderr io.Writer) *Adapter {
 	}
 }
 
-// ReadPassword reads a password from the terminal with echo disabled.
+// ReadPassword reads a password from the terminal with echo disabled, or from the askpass program named
+// by COWPOKE_ASKPASS, or by SSH_ASKPASS without a terminal.
 func (a *Adapter) ReadPassword(ctx context.Context, prompt string) (string, error) {
 	// Check if context is cancelled
 	select {
@@ -38,6 +48,10 @@ func (a *Adapter) ReadPassword(ctx context.Context, prompt string) (string, erro
 		return envPassword, nil
 	}
 
+	if program := a.askpassProgram(); program != "" {
+		return a.askpass(ctx, program, prompt)
+	}
+
 	if !a.IsInteractive() {
 		// For non-interactive environments (e.g., CI/CD), return empty string
 		// The caller should handle this appropriately (e.g., use token auth)
@@ -48,17 +62,71 @@ func (a *Adapter) ReadPassword(ctx context.Context, prompt string) (string, erro
 
 	// Type assertion to check if stdin is a file
 	if file, ok := a.stdin.(*os.File); ok {
-		password, err := term.ReadPassword(int(file.Fd()))
-		fmt.Fprintln(a.stderr) // Print newline after password input
-		if err != nil {
-			return "", fmt.Errorf("failed to read password: %w", err)
-		}
-		return string(password), nil
+		return a.readTerminal(ctx, int(file.Fd()))
 	}
 
 	return "", errors.New("cannot read password from non-terminal input")
 }
 
+// askpassProgram returns the askpass program to read passwords with, or "" to use the terminal.
+func (a *Adapter) askpassProgram() string {
+	if program := os.Getenv(askpassEnv); program != "" {
+		return program
+	}
+	if !a.IsInteractive() {
+		return os.Getenv(sshAskpassEnv)
+	}
+	return ""
+}
+
+// askpass runs program with the prompt as its argument, like ssh runs SSH_ASKPASS, and returns the first
+// line it prints. A program exiting with an error, as when its prompt is cancelled, reads no password.
+func (a *Adapter) askpass(ctx context.Context, program, prompt string) (string, error) {
+	cmd := exec.CommandContext(ctx, program, prompt)
+	cmd.Stderr = a.stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+		return "", fmt.Errorf("failed to read password with askpass program %s: %w", program, err)
+	}
+	password, _, _ := strings.Cut(string(output), "\n")
+	return strings.TrimSuffix(password, "\r"), nil
+}
+
+// readTerminal reads a password from the terminal fd, giving up when ctx is canceled, as it is by Ctrl-C.
+// The terminal's echo is restored either way.
+func (a *Adapter) readTerminal(ctx context.Context, fd int) (string, error) {
+	state, err := term.GetState(fd)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+
+	type readResult struct {
+		password []byte
+		err      error
+	}
+	read := make(chan readResult, 1)
+	go func() {
+		password, readErr := term.ReadPassword(fd)
+		read <- readResult{password: password, err: readErr}
+	}()
+
+	select {
+	case result := <-read:
+		fmt.Fprintln(a.stderr) // Print newline after password input
+		if result.err != nil {
+			return "", fmt.Errorf("failed to read password: %w", result.err)
+		}
+		return string(result.password), nil
+	case <-ctx.Done():
+		_ = term.Restore(fd, state)
+		fmt.Fprintln(a.stderr)
+		return "", ctx.Err()
+	}
+}
+
 // IsInteractive returns true if the terminal is interactive.
 func (a *Adapter) IsInteractive() bool {
 	if file, ok := a.stdin.(*os.File); ok {
@@ -0,0 +1,237 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"cowpoke/internal/domain"
+)
+
+const (
+	exportTimeout      = 10 * time.Second
+	defaultServiceName = "cowpoke"
+	tracesPath         = "/v1/traces"
+
+	// OTLP span kind and status codes.
+	spanKindInternal = 1
+	statusCodeError  = 2
+)
+
+// NewFromEnv returns a Tracer configured from the standard OpenTelemetry environment variables,
+// or a domain.NoopTracer if tracing is not enabled.
+func NewFromEnv(version string, logger *slog.Logger) domain.Tracer {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") ||
+		strings.EqualFold(os.Getenv("OTEL_TRACES_EXPORTER"), "none") {
+		return domain.NoopTracer{}
+	}
+
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	if endpoint == "" {
+		base := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+		if base == "" {
+			return domain.NoopTracer{}
+		}
+		endpoint = strings.TrimSuffix(base, "/") + tracesPath
+	}
+
+	protocol := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+	if protocol == "" {
+		protocol = os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+	if protocol != "" && protocol != "http/json" {
+		logger.Warn("Unsupported OTLP protocol, tracing disabled (only http/json is supported)",
+			"protocol", protocol)
+		return domain.NoopTracer{}
+	}
+
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+
+	headers := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_HEADERS")
+	if headers == "" {
+		headers = os.Getenv("OTEL_EXPORTER_OTLP_HEADERS")
+	}
+
+	logger.Debug("Tracing enabled", "endpoint", endpoint, "service", serviceName)
+
+	return &Tracer{
+		exporter: &exporter{
+			endpoint:    endpoint,
+			headers:     parseHeaders(headers),
+			serviceName: serviceName,
+			version:     version,
+			client:      &http.Client{Timeout: exportTimeout},
+		},
+		logger: logger,
+	}
+}
+
+// exporter sends spans to an OTLP/HTTP endpoint encoded as JSON.
+type exporter struct {
+	endpoint    string
+	headers     map[string]string
+	serviceName string
+	version     string
+	client      *http.Client
+}
+
+// export posts spans as a single OTLP ExportTraceServiceRequest.
+func (e *exporter) export(ctx context.Context, spans []*span) error {
+	body, err := json.Marshal(e.request(spans))
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create export request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send spans: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024)) //nolint:mnd // Enough for an error message
+		return fmt.Errorf("collector returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// request builds the OTLP JSON payload for spans.
+func (e *exporter) request(spans []*span) otlpRequest {
+	out := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		s.mu.Lock()
+		encoded := otlpSpan{
+			TraceID:           s.traceID,
+			SpanID:            s.spanID,
+			ParentSpanID:      s.parentID,
+			Name:              s.name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+			Attributes:        attributes(s.attributes),
+		}
+		if s.err != nil {
+			encoded.Status = &otlpStatus{Code: statusCodeError, Message: s.err.Error()}
+		}
+		s.mu.Unlock()
+		out = append(out, encoded)
+	}
+
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: attributes(map[string]any{
+			"service.name":    e.serviceName,
+			"service.version": e.version,
+		})},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "cowpoke", Version: e.version},
+			Spans: out,
+		}},
+	}}}
+}
+
+// parseHeaders parses the OTEL "key1=value1,key2=value2" header format.
+func parseHeaders(raw string) map[string]string {
+	headers := make(map[string]string)
+	for pair := range strings.SplitSeq(raw, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return headers
+}
+
+// attributes converts a map into OTLP key/value attributes.
+func attributes(m map[string]any) []otlpKeyValue {
+	out := make([]otlpKeyValue, 0, len(m))
+	for key, value := range m {
+		var v otlpAnyValue
+		switch typed := value.(type) {
+		case string:
+			v.StringValue = &typed
+		case bool:
+			v.BoolValue = &typed
+		case int:
+			s := strconv.Itoa(typed)
+			v.IntValue = &s
+		case int64:
+			s := strconv.FormatInt(typed, 10)
+			v.IntValue = &s
+		case float64:
+			v.DoubleValue = &typed
+		default:
+			s := fmt.Sprint(typed)
+			v.StringValue = &s
+		}
+		out = append(out, otlpKeyValue{Key: key, Value: v})
+	}
+	return out
+}
+
+// OTLP JSON wire types (opentelemetry-proto, JSON mapping).
+type (
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+	}
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              int            `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		Status            *otlpStatus    `json:"status,omitempty"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	}
+	otlpKeyValue struct {
+		Key   string       `json:"key"`
+		Value otlpAnyValue `json:"value"`
+	}
+	otlpAnyValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+	}
+)
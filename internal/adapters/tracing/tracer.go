@@ -0,0 +1,142 @@
+// Package tracing records spans and exports them to an OpenTelemetry collector using OTLP/HTTP JSON.
+//
+// Tracing is enabled through the standard OpenTelemetry environment variables
+// (OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT).
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"cowpoke/internal/domain"
+)
+
+const (
+	// maxBufferedSpans bounds memory use; the buffer is exported early when full.
+	maxBufferedSpans = 2048
+
+	traceIDSize = 16
+	spanIDSize  = 8
+)
+
+// Tracer records spans in memory and exports them in batches.
+type Tracer struct {
+	exporter *exporter
+	logger   *slog.Logger
+
+	mu    sync.Mutex
+	spans []*span
+}
+
+// spanContextKey is the context key for the active span.
+type spanContextKey struct{}
+
+// Start begins a span as a child of any span already in ctx.
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, domain.Span) {
+	s := &span{
+		tracer:     t,
+		name:       name,
+		start:      time.Now(),
+		spanID:     randomHex(spanIDSize),
+		attributes: map[string]any{},
+	}
+
+	if parent, ok := ctx.Value(spanContextKey{}).(*span); ok {
+		s.traceID = parent.traceID
+		s.parentID = parent.spanID
+	} else {
+		s.traceID = randomHex(traceIDSize)
+	}
+
+	return context.WithValue(ctx, spanContextKey{}, s), s
+}
+
+// Shutdown exports all buffered spans.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	t.mu.Lock()
+	spans := t.spans
+	t.spans = nil
+	t.mu.Unlock()
+
+	if len(spans) == 0 {
+		return nil
+	}
+	if err := t.exporter.export(ctx, spans); err != nil {
+		return fmt.Errorf("failed to export %d spans: %w", len(spans), err)
+	}
+	t.logger.DebugContext(ctx, "Exported trace spans", "count", len(spans))
+	return nil
+}
+
+// finish buffers an ended span, exporting early if the buffer is full.
+func (t *Tracer) finish(s *span) {
+	t.mu.Lock()
+	t.spans = append(t.spans, s)
+	full := len(t.spans) >= maxBufferedSpans
+	t.mu.Unlock()
+
+	if full {
+		if err := t.Shutdown(context.Background()); err != nil {
+			t.logger.Warn("Failed to export trace spans", "error", err)
+		}
+	}
+}
+
+// span implements domain.Span.
+type span struct {
+	tracer   *Tracer
+	name     string
+	traceID  string
+	spanID   string
+	parentID string
+	start    time.Time
+	end      time.Time
+
+	mu         sync.Mutex
+	attributes map[string]any
+	err        error
+	ended      bool
+}
+
+// SetAttribute records a key/value pair on the span.
+func (s *span) SetAttribute(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attributes[key] = value
+}
+
+// RecordError marks the span as failed.
+func (s *span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// End completes the span. Calling End more than once has no effect.
+func (s *span) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.mu.Unlock()
+
+	s.tracer.finish(s)
+}
+
+// randomHex returns n random bytes hex encoded.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
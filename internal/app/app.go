@@ -2,6 +2,8 @@ package app
 
 import (
 	"context"
+	"errors"
+	"io"
 	"log/slog"
 
 	"cowpoke/internal/domain"
@@ -10,31 +12,62 @@ import (
 // App contains all application dependencies.
 type App struct {
 	// Core configuration dependencies (always needed).
-	ConfigRepo     domain.ConfigRepository
-	ConfigProvider domain.ConfigProvider
+	ConfigRepo      domain.ConfigRepository
+	ConfigProvider  domain.ConfigProvider
+	ConfigValidator domain.ConfigValidator
+	ConfigEncryptor domain.Encryptor
 
 	// Core services (created once with appropriate TLS settings).
 	RancherClient     domain.RancherClient
 	KubeconfigHandler domain.KubeconfigHandler
+	FragmentValidator domain.FragmentValidator
+	OutputWriters     []domain.OutputWriter
+	TemplateRenderer  domain.TemplateRenderer
+	HookRunner        domain.HookRunner
 	SyncOrchestrator  domain.SyncOrchestrator
+	FragmentCache     domain.FragmentCache
+	HealthTracker     domain.HealthTracker
+	TokenCache        domain.TokenCache
+
+	// Persisted runtime state.
+	StateStore domain.StateStore
+
+	// Time source for token expiry and backoff.
+	Clock domain.Clock
 
 	// File operations (needed by multiple commands).
 	FileSystem domain.FileSystemAdapter
 
 	// I/O dependencies.
 	PasswordReader domain.PasswordReader
+	Chooser        domain.Chooser
+	SecretStore    domain.SecretStore
 
-	// Logging.
+	// Logging and tracing.
 	Logger *slog.Logger
+	Tracer domain.Tracer
+
+	// HTTPDebugLogger records every Rancher API call when --debug-http is set, otherwise nil.
+	HTTPDebugLogger *slog.Logger
+	httpDebugFile   io.Closer
+	// adapters holds the HTTP adapters created for Rancher clients, whose SSH tunnels are stopped on shutdown.
+	adapters []io.Closer
 
 	// Configuration.
-	Config *Config
+	Config   *Config
+	Settings domain.Settings
 }
 
 // Config holds application configuration.
 type Config struct {
-	LogLevel slog.Level
-	Verbose  bool
+	LogLevel     slog.Level
+	Verbose      bool
+	Version      string
+	StrictConfig bool
+	Profile      string
+	DebugHTTP    bool
+	// StateDir overrides the directory holding the kubeconfig cache and runtime state.
+	StateDir string
 }
 
 // Option is a functional option for configuring the App.
@@ -57,6 +90,41 @@ func WithVerbose(verbose bool) Option {
 	}
 }
 
+// WithStrictConfig rejects configuration files containing unknown fields.
+func WithStrictConfig(strict bool) Option {
+	return func(cfg *Config) {
+		cfg.StrictConfig = strict
+	}
+}
+
+// WithProfile selects the configuration profile to operate on.
+func WithProfile(name string) Option {
+	return func(cfg *Config) {
+		cfg.Profile = name
+	}
+}
+
+// WithStateDir keeps the kubeconfig cache and runtime state under dir.
+func WithStateDir(dir string) Option {
+	return func(cfg *Config) {
+		cfg.StateDir = dir
+	}
+}
+
+// WithDebugHTTP records every Rancher API call in a debug file under the state directory.
+func WithDebugHTTP(enabled bool) Option {
+	return func(cfg *Config) {
+		cfg.DebugHTTP = enabled
+	}
+}
+
+// WithVersion sets the build version reported in traces.
+func WithVersion(version string) Option {
+	return func(cfg *Config) {
+		cfg.Version = version
+	}
+}
+
 // NewApp creates a new App with the given options.
 func NewApp(ctx context.Context, opts ...Option) (*App, error) {
 	cfg := &Config{
@@ -71,3 +139,19 @@ func NewApp(ctx context.Context, opts ...Option) (*App, error) {
 
 	return NewAppWithConfig(ctx, cfg)
 }
+
+// Shutdown stops SSH tunnels, flushes any buffered telemetry and closes the HTTP debug log before the process
+// exits.
+func (app *App) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, adapter := range app.adapters {
+		errs = append(errs, adapter.Close())
+	}
+	if app.Tracer != nil {
+		errs = append(errs, app.Tracer.Shutdown(ctx))
+	}
+	if app.httpDebugFile != nil {
+		errs = append(errs, app.httpDebugFile.Close())
+	}
+	return errors.Join(errs...)
+}
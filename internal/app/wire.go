@@ -1,23 +1,59 @@
 package app
 
 import (
+	"cmp"
 	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
 	"time"
 
 	"cowpoke/internal/adapters/filesystem"
+	"cowpoke/internal/adapters/git"
 	"cowpoke/internal/adapters/http"
+	"cowpoke/internal/adapters/keychain"
+	"cowpoke/internal/adapters/shell"
 	"cowpoke/internal/adapters/terminal"
+	"cowpoke/internal/adapters/tracing"
+	"cowpoke/internal/commands"
+	"cowpoke/internal/domain"
 	"cowpoke/internal/logging"
+	"cowpoke/internal/services/cache"
 	"cowpoke/internal/services/config"
+	"cowpoke/internal/services/encryption"
+	"cowpoke/internal/services/health"
 	"cowpoke/internal/services/kubeconfig"
+	"cowpoke/internal/services/output"
 	"cowpoke/internal/services/rancher"
+	"cowpoke/internal/services/state"
 	"cowpoke/internal/services/sync"
+	"cowpoke/internal/services/templates"
+	"cowpoke/internal/services/tokens"
+	"cowpoke/internal/services/update"
 )
 
 const (
 	// defaultHTTPTimeout is the default timeout for HTTP requests to Rancher.
 	defaultHTTPTimeout = 30 * time.Second
+
+	// fragmentSaltFile stores the salt used to derive the fragment key from a passphrase.
+	fragmentSaltFile = ".fragment-salt"
+
+	// configSaltFile stores the salt used to derive the configuration key from a passphrase.
+	configSaltFile = ".config-salt"
+
+	// debugDir is the state subdirectory holding per-run HTTP debug logs.
+	debugDir = "debug"
+
+	// configBackupDir is the subdirectory of the configuration directory holding its backups.
+	configBackupDir = "backups"
+
+	// execCredentialCommand is the command exec credential plugins run.
+	execCredentialCommand = "cowpoke"
 )
 
 // NewAppWithConfig creates a new App with the given configuration, wiring all dependencies.
@@ -28,8 +64,11 @@ func NewAppWithConfig(ctx context.Context, cfg *Config) (*App, error) {
 	// Create filesystem adapter.
 	fs := filesystem.New()
 
-	// Create password reader with environment variable support.
-	passwordReader := terminal.NewAdapter(os.Stdin, os.Stderr)
+	// Create terminal prompter for passwords (with environment variable support) and choices.
+	prompter := terminal.NewAdapter(os.Stdin, os.Stderr)
+
+	// Create OS keychain adapter.
+	secretStore := keychain.New()
 
 	// Create config services.
 	configProvider := config.NewProvider(fs)
@@ -37,17 +76,47 @@ func NewAppWithConfig(ctx context.Context, cfg *Config) (*App, error) {
 	if err != nil {
 		return nil, err
 	}
-	configRepo, err := config.NewRepository(fs, configPath, logger)
+	configRepo, configCipher, err := newConfigRepository(cfg, fs, secretStore, configProvider, configPath, logger)
 	if err != nil {
 		return nil, err
 	}
 
+	settings, err := configRepo.GetSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stateNamespace, err := stateNamespace(settings, configRepo.Profile())
+	if err != nil {
+		return nil, err
+	}
+	if cfg.StateDir != "" || stateNamespace != "" || settings.Sync.SeparateKubeconfig {
+		configProvider = config.NewProvider(fs,
+			config.WithStateRoot(cfg.StateDir),
+			config.WithNamespace(stateNamespace),
+			config.WithSeparateKubeconfig(settings.Sync.SeparateKubeconfig))
+	}
+
 	// Create kubeconfig directory and handler.
 	kubeconfigDir, err := configProvider.GetKubeconfigDir()
 	if err != nil {
 		return nil, err
 	}
-	kubeconfigHandler, err := kubeconfig.NewHandler(fs, kubeconfigDir, logger)
+	kubeconfigHandler, err := newKubeconfigHandler(ctx, cfg, settings, stateNamespace, configRepo,
+		fs, prompter, secretStore, kubeconfigDir, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create state store for persisted runtime state.
+	stateDir, err := configProvider.GetStateDir()
+	if err != nil {
+		return nil, err
+	}
+	stateStore := state.NewStore(fs, stateDir, logger)
+
+	clock := domain.SystemClock{}
+
+	httpDebugLogger, httpDebugFile, err := newHTTPDebugLogger(ctx, cfg.DebugHTTP, stateDir, clock, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -56,28 +125,310 @@ func NewAppWithConfig(ctx context.Context, cfg *Config) (*App, error) {
 	logger.InfoContext(ctx, "Initializing cowpoke with configuration",
 		"logLevel", cfg.LogLevel.String(),
 		"verbose", cfg.Verbose,
-		"configPath", configPath)
+		"configPath", configPath,
+		"profile", configRepo.Profile())
 
 	// Note: RancherClient and SyncOrchestrator will be created on-demand with appropriate TLS settings.
 
 	return &App{
 		ConfigRepo:        configRepo,
 		ConfigProvider:    configProvider,
+		ConfigValidator:   config.NewValidator(logger),
+		ConfigEncryptor:   configCipher,
 		KubeconfigHandler: kubeconfigHandler,
-		PasswordReader:    passwordReader,
+		FragmentValidator: kubeconfigHandler,
+		OutputWriters:     newOutputWriters(fs, clock, logger),
+		TemplateRenderer:  templates.NewRenderer(fs, logger),
+		HookRunner:        shell.New(os.Stderr, os.Stderr),
+		FragmentCache:     cache.NewManager(fs, kubeconfigDir, clock, logger),
+		HealthTracker:     health.NewTracker(stateStore, clock, logger),
+		TokenCache:        tokens.NewCache(secretStore, clock, logger, tokens.WithNamespace(stateNamespace)),
+		StateStore:        stateStore,
+		Clock:             clock,
+		PasswordReader:    prompter,
+		Chooser:           prompter,
+		SecretStore:       secretStore,
 		FileSystem:        fs,
 		Logger:            logger,
+		Tracer:            tracing.NewFromEnv(cfg.Version, logger),
+		HTTPDebugLogger:   httpDebugLogger,
+		httpDebugFile:     httpDebugFile,
 		Config:            cfg,
+		Settings:          settings,
 	}, nil
 }
 
-// CreateRancherClient creates a rancher client with the specified TLS configuration.
-func (app *App) CreateRancherClient(insecureSkipTLS bool) *rancher.Client {
-	httpAdapter := http.NewAdapter(defaultHTTPTimeout, insecureSkipTLS, app.Logger)
-	return rancher.NewClient(httpAdapter, app.Logger)
+// newConfigRepository opens the configuration at configPath for the selected profile, layered over the
+// system configuration and decrypted with the configuration key. It returns the cipher it encrypts with.
+func newConfigRepository(
+	cfg *Config,
+	fs domain.FileSystemAdapter,
+	secretStore domain.SecretStore,
+	configProvider *config.Provider,
+	configPath string,
+	logger *slog.Logger,
+) (*config.Repository, *encryption.Cipher, error) {
+	configCipher := encryption.NewCipher(
+		encryption.ConfigKey(secretStore, fs, filepath.Join(filepath.Dir(configPath), configSaltFile)))
+	configRepo, err := config.NewRepository(fs, configPath, logger,
+		config.WithEncryptor(configCipher),
+		config.WithStrict(cfg.StrictConfig),
+		config.WithProfile(cfg.Profile),
+		config.WithSystemConfig(configProvider.GetSystemConfigPath()),
+		config.WithBackups(filepath.Join(filepath.Dir(configPath), configBackupDir), domain.SystemClock{}))
+	if err != nil {
+		return nil, nil, err
+	}
+	return configRepo, configCipher, nil
 }
 
-// CreateSyncOrchestrator creates a sync orchestrator with the given rancher client.
-func (app *App) CreateSyncOrchestrator(rancherClient *rancher.Client) *sync.Orchestrator {
-	return sync.NewOrchestrator(rancherClient, app.KubeconfigHandler, app.ConfigProvider, app.Logger)
+// newKubeconfigHandler creates the kubeconfig handler, configured by settings to name, group, encrypt and
+// authenticate the contexts it writes into kubeconfigDir.
+func newKubeconfigHandler(
+	ctx context.Context,
+	cfg *Config,
+	settings domain.Settings,
+	stateNamespace string,
+	configRepo domain.ConfigRepository,
+	fs domain.FileSystemAdapter,
+	passwordReader domain.PasswordReader,
+	secretStore domain.SecretStore,
+	kubeconfigDir string,
+	logger *slog.Logger,
+) (*kubeconfig.Handler, error) {
+	handlerOpts := []kubeconfig.Option{
+		kubeconfig.WithVersion(cfg.Version),
+		kubeconfig.WithNaming(settings.Naming),
+		kubeconfig.WithPermissions(settings.Permissions),
+		kubeconfig.WithEndpointRewrites(settings.Endpoints.Rewrites),
+		kubeconfig.WithSharedCAs(settings.Sync.ShareCAs),
+	}
+	if settings.Naming.Grouping.By != "" {
+		groups, err := contextGroups(ctx, configRepo, settings.Naming)
+		if err != nil {
+			return nil, err
+		}
+		handlerOpts = append(handlerOpts, kubeconfig.WithGroups(groups))
+	}
+	if settings.Fragments.Encrypt {
+		handlerOpts = append(handlerOpts, kubeconfig.WithEncryptor(
+			newFragmentCipher(settings.Fragments, passwordReader, secretStore, fs, kubeconfigDir)))
+	}
+	if settings.Sync.ExecCredentials {
+		// Found through PATH, so that the kubeconfig survives upgrades that move the binary.
+		handlerOpts = append(handlerOpts, kubeconfig.WithExecCredentials(execCredentialCommand,
+			execCredentialFlags(cfg, stateNamespace)...))
+	}
+	return kubeconfig.NewHandler(fs, kubeconfigDir, logger, handlerOpts...)
+}
+
+// newHTTPDebugLogger opens a new HTTP debug log under stateDir if enabled, returning nil loggers otherwise.
+func newHTTPDebugLogger(
+	ctx context.Context,
+	enabled bool,
+	stateDir string,
+	clock domain.Clock,
+	logger *slog.Logger,
+) (*slog.Logger, io.Closer, error) {
+	if !enabled {
+		return nil, nil, nil
+	}
+	debugPath := filepath.Join(stateDir, debugDir, "http-"+clock.Now().Format("20060102-150405")+".log")
+	httpDebugLogger, httpDebugFile, err := logging.NewFileLogger(debugPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	logger.InfoContext(ctx, "Writing HTTP debug log", "path", debugPath)
+	return httpDebugLogger, httpDebugFile, nil
+}
+
+// newOutputWriters creates the writers of the remote outputs a sync can publish to.
+func newOutputWriters(fs domain.FileSystemAdapter, clock domain.Clock, logger *slog.Logger) []domain.OutputWriter {
+	return []domain.OutputWriter{
+		output.NewSecretWriter(fs, logger),
+		output.NewS3Writer(fs, clock, logger),
+		output.NewGCSWriter(fs, clock, logger),
+		output.NewGitWriter(git.New(), fs, logger),
+	}
+}
+
+// CreateRancherClient creates a rancher client with the specified TLS configuration. Client certificates and
+// tunnels of the configured servers, and of any extra servers not yet configured, apply to their hosts.
+func (app *App) CreateRancherClient(insecureSkipTLS bool, extraServers ...domain.ConfigServer) *rancher.Client {
+	opts := append(http.FixturesFromEnv(),
+		http.WithUserAgent("cowpoke/"+cmp.Or(app.Config.Version, "dev")),
+		http.WithMaxIdleConnsPerHost(app.Settings.HTTP.IdleConnsPerHost()),
+		http.WithClock(app.Clock))
+	opts = append(opts, app.serverRoutes(extraServers)...)
+	if app.HTTPDebugLogger != nil {
+		opts = append(opts, http.WithDebugLogger(app.HTTPDebugLogger))
+	}
+	httpAdapter := http.NewAdapter(defaultHTTPTimeout, insecureSkipTLS, app.Logger, opts...)
+	app.adapters = append(app.adapters, httpAdapter)
+	return rancher.NewClient(httpAdapter, app.Clock, app.Logger)
+}
+
+// serverRoutes returns adapter options applying each server's client certificate and tunnel to its host.
+func (app *App) serverRoutes(extraServers []domain.ConfigServer) []http.Option {
+	servers, err := app.ConfigRepo.GetAllServers(context.Background())
+	if err != nil {
+		app.Logger.Warn("Could not read client certificates and tunnels from the configuration", "error", err)
+	}
+
+	var opts []http.Option
+	for _, server := range append(servers, extraServers...) {
+		if server.ClientCert == "" && server.Tunnel == "" {
+			continue
+		}
+		parsed, parseErr := url.Parse(server.URL)
+		if parseErr != nil || parsed.Host == "" {
+			continue
+		}
+		if server.ClientCert != "" {
+			opts = append(opts, http.WithClientCertificate(parsed.Host, server.ClientCert, server.ClientKey))
+		}
+		if server.Tunnel != "" {
+			opts = append(opts, http.WithTunnel(parsed.Host, server.Tunnel))
+		}
+	}
+	return opts
+}
+
+// CreateSyncOrchestrator creates a sync orchestrator with the given rancher client. A positive
+// kubeconfigTTL requests kubeconfig tokens with that lifetime, and includeLocal discovers the local cluster
+// even if settings.discovery.skipLocalCluster leaves it out. viaRancher routes every cluster through
+// Rancher's proxy, and ownedOnly keeps only the discovered clusters the user created or owns.
+func (app *App) CreateSyncOrchestrator(
+	rancherClient *rancher.Client,
+	kubeconfigTTL time.Duration,
+	includeLocal bool,
+	viaRancher bool,
+	ownedOnly bool,
+) *sync.Orchestrator {
+	return sync.NewOrchestrator(
+		rancherClient,
+		app.KubeconfigHandler,
+		app.ConfigProvider,
+		app.FragmentCache,
+		app.TokenCache,
+		app.Tracer,
+		app.Logger,
+		sync.WithKubeconfigTTL(kubeconfigTTL),
+		sync.WithNaming(app.Settings.Naming),
+		sync.WithSkipLocalCluster(app.Settings.Discovery.SkipLocal() && !includeLocal),
+		sync.WithViaRancher(viaRancher),
+		sync.WithOwnedOnly(ownedOnly),
+		sync.WithHostCloser(rancherClient),
+		sync.WithClock(app.Clock),
+	)
+}
+
+// CreateSyncCommand creates a sync command wired to the app's cache, health, token and report subsystems.
+func (app *App) CreateSyncCommand() *commands.SyncCommand {
+	return commands.NewSyncCommand(
+		app.ConfigRepo,
+		app.ConfigProvider,
+		app.PasswordReader,
+		app.Logger,
+		commands.WithFragmentCache(app.FragmentCache),
+		commands.WithHealthTracker(app.HealthTracker),
+		commands.WithClock(app.Clock),
+		commands.WithTokenCache(app.TokenCache),
+		commands.WithReportStore(app.StateStore),
+		commands.WithContextIndex(app.StateStore),
+		commands.WithFragmentValidator(app.FragmentValidator),
+		commands.WithOutputWriters(app.OutputWriters...),
+		commands.WithTemplates(app.TemplateRenderer, app.Settings.Templates),
+		commands.WithHooks(app.HookRunner, app.Settings.Hooks),
+		commands.WithTracer(app.Tracer),
+		commands.WithNaming(app.Settings.Naming),
+		commands.WithContextLimits(app.Settings.Limits),
+		commands.WithSyncSettings(app.Settings.Sync),
+		commands.WithEndpointCheck(net.DefaultResolver),
+	)
+}
+
+// CreateUpdater creates an updater that installs cowpoke releases published on GitHub.
+func (app *App) CreateUpdater() *update.Updater {
+	httpAdapter := http.NewAdapter(defaultHTTPTimeout, false, app.Logger,
+		http.WithUserAgent("cowpoke/"+cmp.Or(app.Config.Version, "dev")),
+		http.WithQuietRetries(),
+		http.WithClock(app.Clock))
+	app.adapters = append(app.adapters, httpAdapter)
+	return update.NewUpdater(httpAdapter, app.FileSystem, app.Logger)
+}
+
+// CreateUpdateChecker creates a checker that looks up newer releases at most once a day.
+func (app *App) CreateUpdateChecker() *update.Checker {
+	return update.NewChecker(app.CreateUpdater(), app.StateStore, app.Clock, app.Logger)
+}
+
+// ReadClusterList reads a cluster list file naming the clusters to sync without discovery.
+func (app *App) ReadClusterList(path string) ([]domain.ClusterRef, error) {
+	return config.ReadClusterList(app.FileSystem, path)
+}
+
+// ReadServerList reads a YAML or CSV file of servers to add at once.
+func (app *App) ReadServerList(path string) ([]domain.ConfigServer, error) {
+	return config.ReadServerList(app.FileSystem, path)
+}
+
+// newFragmentCipher creates the cipher used to encrypt cached kubeconfig fragments.
+func newFragmentCipher(
+	settings domain.FragmentSettings,
+	passwordReader domain.PasswordReader,
+	secretStore domain.SecretStore,
+	fs domain.FileSystemAdapter,
+	kubeconfigDir string,
+) *encryption.Cipher {
+	if settings.KeySource == "keychain" {
+		return encryption.NewCipher(encryption.KeychainKey(secretStore))
+	}
+	saltPath := filepath.Join(kubeconfigDir, fragmentSaltFile)
+	return encryption.NewCipher(encryption.PassphraseKey(passwordReader, fs, saltPath))
+}
+
+// stateNamespace returns the namespace keeping the active profile's kubeconfig cache, runtime state and
+// cached tokens apart when profiles are isolated, or "" when they are shared.
+func stateNamespace(settings domain.Settings, profile string) (string, error) {
+	if !settings.State.IsolateProfiles || profile == domain.DefaultProfile {
+		return "", nil
+	}
+	if profile != filepath.Base(profile) || profile == "." || profile == ".." {
+		return "", fmt.Errorf("profile %q cannot be isolated: its name must be usable as a directory name", profile)
+	}
+	return profile, nil
+}
+
+// execCredentialFlags returns the global flags exec credential plugins pass so that they find the state the
+// kubeconfig was synced with.
+func execCredentialFlags(cfg *Config, namespace string) []string {
+	var flags []string
+	if cfg.StateDir != "" {
+		flags = append(flags, "--state-dir", cfg.StateDir)
+	}
+	if namespace != "" {
+		flags = append(flags, "--profile", namespace)
+	}
+	return flags
+}
+
+// contextGroups returns the groups of the contexts of every configured server, keyed by server ID, leaving
+// out servers whose contexts are not grouped.
+func contextGroups(
+	ctx context.Context,
+	configRepo domain.ConfigRepository,
+	naming domain.NamingSettings,
+) (map[string]string, error) {
+	servers, err := configRepo.GetAllServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[string]string, len(servers))
+	for _, server := range servers {
+		if group := naming.Group(server); group != "" {
+			groups[server.ID()] = group
+		}
+	}
+	return groups, nil
 }
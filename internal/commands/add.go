@@ -2,51 +2,258 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 
 	"cowpoke/internal/domain"
 )
 
 // AddCommand handles adding new Rancher servers to the configuration.
 type AddCommand struct {
-	configRepo domain.ConfigRepository
-	logger     *slog.Logger
+	configRepo    domain.ConfigRepository
+	rancherClient domain.RancherClient
+	versionCheck  bool
+	verify        bool
+	chooser       domain.Chooser
+	logger        *slog.Logger
+}
+
+// AddOption is a functional option for wiring optional AddCommand dependencies.
+type AddOption func(*AddCommand)
+
+// WithVersionCheck detects the server's Rancher release before adding it and rejects incompatible releases.
+func WithVersionCheck(rancherClient domain.RancherClient) AddOption {
+	return func(c *AddCommand) {
+		c.rancherClient = rancherClient
+		c.versionCheck = true
+	}
+}
+
+// WithVerification rejects servers that cannot be reached or do not have the requested auth type enabled.
+func WithVerification(rancherClient domain.RancherClient) AddOption {
+	return func(c *AddCommand) {
+		c.rancherClient = rancherClient
+		c.verify = true
+	}
+}
+
+// WithAuthTypeChooser asks the user to pick the auth type when it was not given and the server has
+// several supported providers enabled. Detection requires WithVerification.
+func WithAuthTypeChooser(chooser domain.Chooser) AddOption {
+	return func(c *AddCommand) {
+		c.chooser = chooser
+	}
 }
 
 // NewAddCommand creates a new add command.
-func NewAddCommand(configRepo domain.ConfigRepository, logger *slog.Logger) *AddCommand {
-	return &AddCommand{
+func NewAddCommand(configRepo domain.ConfigRepository, logger *slog.Logger, opts ...AddOption) *AddCommand {
+	c := &AddCommand{
 		configRepo: configRepo,
 		logger:     logger,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // AddRequest contains the parameters for the add command.
 type AddRequest struct {
+	// URL is normalized before it is stored, unless it refers to environment variables.
 	URL      string
 	Username string
+	// AuthType is detected from the server's enabled auth providers when empty and verification is enabled.
 	AuthType string
+	// ClientCert and ClientKey are paths to a client certificate and key for mutual TLS gateways.
+	ClientCert string
+	ClientKey  string
+	// Tunnel is a SOCKS5 proxy URL or "ssh <destination>" through which the server is reached.
+	Tunnel string
+	Tags   []string
+	// Alias is a short name for the server that its contexts can be grouped under.
+	Alias string
+	// Priority is the server's sync priority group; zero syncs it after the servers that have one.
+	Priority int
+}
+
+// AddResult is the outcome of adding one entry of a batch.
+type AddResult struct {
+	// Entry is the 1-based position of the request in the batch.
+	Entry int
+	URL   string
+	Err   error
 }
 
 // Execute runs the add command.
 func (c *AddCommand) Execute(ctx context.Context, req AddRequest) error {
+	if (req.ClientCert == "") != (req.ClientKey == "") {
+		return errors.New("a client certificate and key must be given together")
+	}
+	if req.Tunnel != "" {
+		if _, _, err := domain.ParseTunnel(req.Tunnel); err != nil {
+			return err
+		}
+	}
+	if req.Priority < 0 {
+		return fmt.Errorf("invalid priority %d: must be 1 or more", req.Priority)
+	}
+
+	serverURL := req.URL
+	if !strings.Contains(serverURL, "${") {
+		normalized, err := domain.NormalizeServerURL(serverURL)
+		if err != nil {
+			return err
+		}
+		serverURL = normalized
+	}
+
 	server := domain.ConfigServer{
-		URL:      req.URL,
-		Username: req.Username,
-		AuthType: req.AuthType,
+		URL:        serverURL,
+		Username:   req.Username,
+		AuthType:   req.AuthType,
+		ClientCert: req.ClientCert,
+		ClientKey:  req.ClientKey,
+		Tunnel:     req.Tunnel,
+		Tags:       req.Tags,
+		Alias:      req.Alias,
+		Priority:   req.Priority,
+	}
+
+	server, err := c.verifyServer(ctx, server)
+	if err != nil {
+		return err
 	}
 
 	c.logger.InfoContext(ctx, "Adding new server",
 		"id", server.ID(),
-		"url", req.URL,
-		"username", req.Username,
-		"authType", req.AuthType)
+		"url", server.URL,
+		"username", server.Username,
+		"authType", server.AuthType)
+
+	if err := c.checkVersion(ctx, server); err != nil {
+		return err
+	}
+
+	if err = c.configRepo.AddServer(ctx, server); err != nil {
+		return fmt.Errorf("failed to add server: %w", err)
+	}
+
+	c.logger.InfoContext(ctx, "Successfully added server", "id", server.ID(), "url", server.URL)
+	return nil
+}
+
+// ExecuteBatch adds each server in turn. A failing entry does not stop the batch, and servers added before
+// it are kept, so the batch can be fixed and re-run for the failed entries only.
+func (c *AddCommand) ExecuteBatch(ctx context.Context, reqs []AddRequest) []AddResult {
+	results := make([]AddResult, len(reqs))
+	for i, req := range reqs {
+		results[i] = AddResult{Entry: i + 1, URL: req.URL}
+		if req.URL == "" || req.Username == "" {
+			results[i].Err = errors.New("url and username are required")
+			continue
+		}
+		if normalized, err := domain.NormalizeServerURL(req.URL); err == nil {
+			results[i].URL = normalized
+		}
+		results[i].Err = c.Execute(ctx, req)
+	}
+	return results
+}
+
+// checkVersion records the server's Rancher release and rejects releases cowpoke cannot sync from.
+// An unreachable server is only a warning so servers can be added while offline.
+func (c *AddCommand) checkVersion(ctx context.Context, server domain.ConfigServer) error {
+	if !c.versionCheck {
+		return nil
+	}
+
+	version, err := c.rancherClient.GetVersion(ctx, server)
+	if err != nil {
+		c.logger.WarnContext(ctx, "Could not detect Rancher version", "url", server.URL, "error", err)
+		return nil
+	}
 
-	if err := c.configRepo.AddServer(ctx, server); err != nil {
+	warning, err := version.Check()
+	if err != nil {
 		return fmt.Errorf("failed to add server: %w", err)
 	}
+	if warning != "" {
+		c.logger.WarnContext(ctx, warning, "url", server.URL)
+	}
 
-	c.logger.InfoContext(ctx, "Successfully added server", "id", server.ID(), "url", req.URL)
+	c.logger.InfoContext(ctx, "Detected Rancher version", "url", server.URL, "version", version.Version)
 	return nil
 }
+
+// verifyServer lists the server's enabled auth providers, so that a mistyped URL or a wrong auth type is
+// caught when the server is added rather than at its first sync. A missing auth type is detected from the
+// providers. URLs referring to environment variables are not verified, since they may only be set where
+// cowpoke syncs.
+func (c *AddCommand) verifyServer(ctx context.Context, server domain.ConfigServer) (domain.ConfigServer, error) {
+	if !c.verify {
+		return server, nil
+	}
+	if strings.Contains(server.URL, "${") {
+		if server.AuthType == "" {
+			return server, fmt.Errorf("cannot detect the auth type of %s, which refers to environment variables; "+
+				"specify it with --authtype", server.URL)
+		}
+		return server, nil
+	}
+
+	providers, err := c.rancherClient.AuthProviders(ctx, server)
+	if err != nil {
+		return server, fmt.Errorf("%w: %s: %w", domain.ErrServerVerification, server.URL, err)
+	}
+
+	enabled := make([]string, len(providers))
+	for i, provider := range providers {
+		enabled[i] = provider.ID
+	}
+
+	if server.AuthType == "" {
+		server.AuthType, err = c.detectAuthType(ctx, server.URL, enabled)
+		if err != nil {
+			return server, err
+		}
+	}
+	if !slices.Contains(enabled, server.AuthType) {
+		return server, fmt.Errorf("%w: auth type %q is not enabled on %s (enabled: %s)",
+			domain.ErrServerVerification, server.AuthType, server.URL, strings.Join(enabled, ", "))
+	}
+
+	c.logger.DebugContext(ctx, "Verified server", "url", server.URL, "auth_providers", enabled)
+	return server, nil
+}
+
+// detectAuthType picks the auth type among the enabled providers cowpoke can log in with. When there are
+// several, the user is asked to choose one if a chooser is available and the session is interactive.
+func (c *AddCommand) detectAuthType(ctx context.Context, serverURL string, enabled []string) (string, error) {
+	var candidates []string
+	for _, provider := range enabled {
+		if slices.Contains(domain.SupportedAuthTypes(), provider) {
+			candidates = append(candidates, provider)
+		}
+	}
+
+	switch {
+	case len(candidates) == 0:
+		return "", fmt.Errorf("%w: none of the auth providers enabled on %s are supported (enabled: %s)",
+			domain.ErrServerVerification, serverURL, strings.Join(enabled, ", "))
+	case len(candidates) == 1:
+		c.logger.InfoContext(ctx, "Detected auth type", "url", serverURL, "authType", candidates[0])
+		return candidates[0], nil
+	case c.chooser == nil || !c.chooser.IsInteractive():
+		return "", fmt.Errorf("%s has several auth providers enabled (%s); specify one with --authtype",
+			serverURL, strings.Join(candidates, ", "))
+	}
+
+	authType, err := c.chooser.Choose(ctx, fmt.Sprintf("Auth type for %s", serverURL), candidates)
+	if err != nil {
+		return "", fmt.Errorf("failed to choose auth type: %w", err)
+	}
+	return authType, nil
+}
@@ -188,7 +188,7 @@ func TestAddCommand_Execute_ServerIDGeneration(t *testing.T) {
 
 func TestAddCommand_Execute_EmptyFields(t *testing.T) {
 	// Test behavior with empty/minimal required fields
-	// Note: Field validation is typically done by the CLI layer,
+	// Note: Apart from the URL, field validation is done by the CLI layer,
 	// but we test the command handles empty values gracefully
 
 	tests := []struct {
@@ -212,7 +212,7 @@ func TestAddCommand_Execute_EmptyFields(t *testing.T) {
 				Username: "admin",
 				AuthType: "local",
 			},
-			wantErr: false, // Command doesn't validate, CLI layer does
+			wantErr: true, // URLs are validated before they are stored
 		},
 		{
 			name: "empty username",
@@ -277,3 +277,395 @@ func TestNewAddCommand(t *testing.T) {
 	assert.NotNil(t, cmd)
 	assert.Equal(t, mockConfigRepo, cmd.configRepo)
 }
+
+func TestAddCommand_Execute_VersionCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    domain.ServerVersion
+		versionErr error
+		wantAdd    bool
+		wantErr    error
+	}{
+		{
+			name:    "supported version",
+			version: domain.ServerVersion{Version: "v2.8.3"},
+			wantAdd: true,
+		},
+		{
+			name:    "old but usable version",
+			version: domain.ServerVersion{Version: "v2.4.17"},
+			wantAdd: true,
+		},
+		{
+			name:    "development build",
+			version: domain.ServerVersion{Version: "master-head"},
+			wantAdd: true,
+		},
+		{
+			name:       "version endpoint unreachable",
+			versionErr: errors.New("connection refused"),
+			wantAdd:    true,
+		},
+		{
+			name:    "rancher 1.x",
+			version: domain.ServerVersion{Version: "v1.6.30"},
+			wantErr: domain.ErrIncompatibleVersion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockConfigRepo := mocks.NewMockConfigRepository(t)
+			mockRancherClient := mocks.NewMockRancherClient(t)
+			server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+
+			mockRancherClient.On("GetVersion", mock.Anything, server).Return(tt.version, tt.versionErr)
+			if tt.wantAdd {
+				mockConfigRepo.On("AddServer", mock.Anything, server).Return(nil)
+			}
+
+			cmd := NewAddCommand(mockConfigRepo, testutil.Logger(), WithVersionCheck(mockRancherClient))
+
+			// Act
+			err := cmd.Execute(context.Background(), AddRequest{
+				URL:      server.URL,
+				Username: server.Username,
+				AuthType: server.AuthType,
+			})
+
+			// Assert
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			mockConfigRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAddCommand_Execute_ClientCertificate(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+
+	expectedServer := domain.ConfigServer{
+		URL:        "https://rancher.example.com",
+		Username:   "admin",
+		AuthType:   "local",
+		ClientCert: "/etc/cowpoke/client.pem",
+		ClientKey:  "/etc/cowpoke/client-key.pem",
+	}
+	mockConfigRepo.On("AddServer", mock.Anything, expectedServer).Return(nil)
+
+	cmd := newTestAddCommand(mockConfigRepo)
+
+	// Act
+	err := cmd.Execute(context.Background(), AddRequest{
+		URL:        "https://rancher.example.com",
+		Username:   "admin",
+		AuthType:   "local",
+		ClientCert: "/etc/cowpoke/client.pem",
+		ClientKey:  "/etc/cowpoke/client-key.pem",
+	})
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestAddCommand_Execute_ClientCertificateWithoutKey(t *testing.T) {
+	// Arrange
+	cmd := newTestAddCommand(mocks.NewMockConfigRepository(t))
+
+	// Act
+	err := cmd.Execute(context.Background(), AddRequest{
+		URL:        "https://rancher.example.com",
+		Username:   "admin",
+		AuthType:   "local",
+		ClientCert: "/etc/cowpoke/client.pem",
+	})
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "client certificate and key must be given together")
+}
+
+func TestAddCommand_Execute_Tunnel(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+
+	expectedServer := domain.ConfigServer{
+		URL:      "https://rancher.internal.example.com",
+		Username: "admin",
+		AuthType: "local",
+		Tunnel:   "ssh ops@bastion.example.com",
+	}
+	mockConfigRepo.On("AddServer", mock.Anything, expectedServer).Return(nil)
+
+	cmd := newTestAddCommand(mockConfigRepo)
+
+	// Act
+	err := cmd.Execute(context.Background(), AddRequest{
+		URL:      "https://rancher.internal.example.com",
+		Username: "admin",
+		AuthType: "local",
+		Tunnel:   "ssh ops@bastion.example.com",
+	})
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestAddCommand_Execute_InvalidTunnel(t *testing.T) {
+	// Arrange
+	cmd := newTestAddCommand(mocks.NewMockConfigRepository(t))
+
+	// Act
+	err := cmd.Execute(context.Background(), AddRequest{
+		URL:      "https://rancher.internal.example.com",
+		Username: "admin",
+		AuthType: "local",
+		Tunnel:   "http://proxy.example.com:3128",
+	})
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid tunnel "http://proxy.example.com:3128"`)
+}
+
+func TestAddCommand_Execute_InvalidPriority(t *testing.T) {
+	// Arrange
+	cmd := newTestAddCommand(mocks.NewMockConfigRepository(t))
+
+	// Act
+	err := cmd.Execute(context.Background(), AddRequest{
+		URL:      "https://rancher.example.com",
+		Username: "admin",
+		AuthType: "local",
+		Priority: -1,
+	})
+
+	// Assert
+	require.ErrorContains(t, err, "invalid priority -1")
+}
+
+func TestAddCommand_Execute_NormalizesURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    string
+		wantErr string
+	}{
+		{name: "trailing slash and case", url: " HTTPS://Rancher.Example.com/ ", want: "https://rancher.example.com"},
+		{name: "default port", url: "https://rancher.example.com:443/rancher/", want: "https://rancher.example.com/rancher"},
+		{name: "custom port", url: "https://rancher.example.com:8443", want: "https://rancher.example.com:8443"},
+		{name: "environment reference", url: "${RANCHER_URL}", want: "${RANCHER_URL}"},
+		{name: "missing scheme", url: "rancher.example.com", wantErr: "must start with https://"},
+		{name: "unsupported scheme", url: "ftp://rancher.example.com", wantErr: "must start with https://"},
+		{name: "query", url: "https://rancher.example.com/?x=1", wantErr: "must not include credentials"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockConfigRepo := mocks.NewMockConfigRepository(t)
+			if tt.wantErr == "" {
+				mockConfigRepo.On("AddServer", mock.Anything, domain.ConfigServer{
+					URL: tt.want, Username: "admin", AuthType: "local",
+				}).Return(nil)
+			}
+
+			cmd := newTestAddCommand(mockConfigRepo)
+
+			// Act
+			err := cmd.Execute(context.Background(), AddRequest{URL: tt.url, Username: "admin", AuthType: "local"})
+
+			// Assert
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAddCommand_Execute_Verification(t *testing.T) {
+	providers := []domain.AuthProvider{
+		{ID: "local", Type: "localProvider"},
+		{ID: "activedirectory", Type: "activeDirectoryProvider"},
+	}
+
+	tests := []struct {
+		name         string
+		authType     string
+		providers    []domain.AuthProvider
+		providersErr error
+		wantErr      string
+	}{
+		{name: "enabled auth type", authType: "activedirectory", providers: providers},
+		{
+			name:      "auth type not enabled",
+			authType:  "openldap",
+			providers: providers,
+			wantErr:   `auth type "openldap" is not enabled on https://rancher.example.com (enabled: local, activedirectory)`,
+		},
+		{
+			name:         "unreachable server",
+			authType:     "local",
+			providersErr: errors.New("connection refused"),
+			wantErr:      "server verification failed: https://rancher.example.com: connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockConfigRepo := mocks.NewMockConfigRepository(t)
+			mockRancherClient := mocks.NewMockRancherClient(t)
+
+			server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: tt.authType}
+			mockRancherClient.On("AuthProviders", mock.Anything, server).Return(tt.providers, tt.providersErr)
+			if tt.wantErr == "" {
+				mockConfigRepo.On("AddServer", mock.Anything, server).Return(nil)
+			}
+
+			cmd := NewAddCommand(mockConfigRepo, testutil.Logger(), WithVerification(mockRancherClient))
+
+			// Act
+			err := cmd.Execute(context.Background(), AddRequest{
+				URL:      "https://rancher.example.com/",
+				Username: "admin",
+				AuthType: tt.authType,
+			})
+
+			// Assert
+			if tt.wantErr != "" {
+				require.ErrorIs(t, err, domain.ErrServerVerification)
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAddCommand_Execute_DetectsAuthType(t *testing.T) {
+	local := domain.AuthProvider{ID: "local", Type: "localProvider"}
+	ad := domain.AuthProvider{ID: "activedirectory", Type: "activeDirectoryProvider"}
+	saml := domain.AuthProvider{ID: "adfs", Type: "adfsProvider"}
+
+	tests := []struct {
+		name        string
+		providers   []domain.AuthProvider
+		ambiguous   bool
+		interactive bool
+		chosen      string
+		want        string
+		wantErr     string
+	}{
+		{name: "single supported provider", providers: []domain.AuthProvider{local, saml}, want: "local"},
+		{
+			name:        "several providers prompt",
+			providers:   []domain.AuthProvider{local, ad},
+			ambiguous:   true,
+			interactive: true,
+			chosen:      "activedirectory",
+			want:        "activedirectory",
+		},
+		{
+			name:      "several providers without a terminal",
+			providers: []domain.AuthProvider{local, ad},
+			ambiguous: true,
+			wantErr:   "several auth providers enabled (local, activedirectory); specify one with --authtype",
+		},
+		{
+			name:      "no supported provider",
+			providers: []domain.AuthProvider{saml},
+			wantErr:   "none of the auth providers enabled on https://rancher.example.com are supported (enabled: adfs)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockConfigRepo := mocks.NewMockConfigRepository(t)
+			mockRancherClient := mocks.NewMockRancherClient(t)
+			mockChooser := mocks.NewMockChooser(t)
+
+			server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin"}
+			mockRancherClient.On("AuthProviders", mock.Anything, server).Return(tt.providers, nil)
+			if tt.ambiguous {
+				mockChooser.On("IsInteractive").Return(tt.interactive)
+			}
+			if tt.chosen != "" {
+				mockChooser.On("Choose", mock.Anything, "Auth type for https://rancher.example.com",
+					[]string{"local", "activedirectory"}).Return(tt.chosen, nil)
+			}
+			if tt.wantErr == "" {
+				server.AuthType = tt.want
+				mockConfigRepo.On("AddServer", mock.Anything, server).Return(nil)
+			}
+
+			cmd := NewAddCommand(mockConfigRepo, testutil.Logger(),
+				WithVerification(mockRancherClient), WithAuthTypeChooser(mockChooser))
+
+			// Act
+			err := cmd.Execute(context.Background(), AddRequest{URL: "https://rancher.example.com", Username: "admin"})
+
+			// Assert
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAddCommand_Execute_DetectionNeedsResolvableURL(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockRancherClient := mocks.NewMockRancherClient(t)
+
+	cmd := NewAddCommand(mockConfigRepo, testutil.Logger(), WithVerification(mockRancherClient))
+
+	// Act
+	err := cmd.Execute(context.Background(), AddRequest{URL: "${RANCHER_URL}", Username: "admin"})
+
+	// Assert
+	require.ErrorContains(t, err, "specify it with --authtype")
+}
+
+func TestAddCommand_ExecuteBatch(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigRepo.On("AddServer", mock.Anything, domain.ConfigServer{
+		URL: "https://rancher-a.example.com", Username: "admin", AuthType: "local", Tags: []string{"prod"},
+	}).Return(nil)
+	mockConfigRepo.On("AddServer", mock.Anything, domain.ConfigServer{
+		URL: "https://rancher-c.example.com", Username: "admin", AuthType: "local",
+	}).Return(errors.New("server https://rancher-c.example.com already exists in configuration"))
+	mockConfigRepo.On("AddServer", mock.Anything, domain.ConfigServer{
+		URL: "https://rancher-d.example.com", Username: "jdoe", AuthType: "openldap",
+	}).Return(nil)
+
+	cmd := newTestAddCommand(mockConfigRepo)
+
+	// Act
+	results := cmd.ExecuteBatch(context.Background(), []AddRequest{
+		{URL: "https://Rancher-A.example.com/", Username: "admin", AuthType: "local", Tags: []string{"prod"}},
+		{URL: "https://rancher-b.example.com", AuthType: "local"},
+		{URL: "https://rancher-c.example.com", Username: "admin", AuthType: "local"},
+		{URL: "https://rancher-d.example.com", Username: "jdoe", AuthType: "openldap"},
+	})
+
+	// Assert
+	require.Len(t, results, 4)
+	assert.Equal(t, AddResult{Entry: 1, URL: "https://rancher-a.example.com"}, results[0])
+	require.EqualError(t, results[1].Err, "url and username are required")
+	assert.Equal(t, 2, results[1].Entry)
+	require.ErrorContains(t, results[2].Err, "already exists in configuration")
+	assert.Equal(t, "https://rancher-c.example.com", results[2].URL)
+	assert.Equal(t, AddResult{Entry: 4, URL: "https://rancher-d.example.com"}, results[3])
+}
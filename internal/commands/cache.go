@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"cowpoke/internal/domain"
+)
+
+// CacheCleanCommand handles manual garbage collection of cached kubeconfig fragments.
+type CacheCleanCommand struct {
+	configRepo    domain.ConfigRepository
+	fragmentCache domain.FragmentCache
+	logger        *slog.Logger
+}
+
+// NewCacheCleanCommand creates a new cache clean command.
+func NewCacheCleanCommand(
+	configRepo domain.ConfigRepository,
+	fragmentCache domain.FragmentCache,
+	logger *slog.Logger,
+) *CacheCleanCommand {
+	return &CacheCleanCommand{
+		configRepo:    configRepo,
+		fragmentCache: fragmentCache,
+		logger:        logger,
+	}
+}
+
+// CacheCleanRequest contains the parameters for the cache clean command.
+type CacheCleanRequest struct {
+	// All removes every cached fragment.
+	All bool
+	// MaxAge overrides the configured retention period when non-zero.
+	MaxAge time.Duration
+}
+
+// Execute runs the cache clean command.
+func (c *CacheCleanCommand) Execute(ctx context.Context, req CacheCleanRequest) (*domain.CleanupResult, error) {
+	policy := domain.CleanupPolicy{All: req.All, MaxAge: req.MaxAge}
+
+	if !req.All {
+		servers, err := c.configRepo.GetAllServers(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get servers: %w", err)
+		}
+		policy.ConfiguredServerIDs = make([]string, 0, len(servers))
+		for _, server := range servers {
+			policy.ConfiguredServerIDs = append(policy.ConfiguredServerIDs, server.ID())
+		}
+
+		if policy.MaxAge == 0 {
+			settings, err := c.configRepo.GetSettings(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get settings: %w", err)
+			}
+			policy.MaxAge = settings.Fragments.Retention()
+		}
+	}
+
+	c.logger.DebugContext(ctx, "Cleaning fragment cache",
+		"all", policy.All,
+		"max_age", policy.MaxAge,
+		"configured_servers", len(policy.ConfiguredServerIDs))
+
+	result, err := c.fragmentCache.Clean(ctx, policy)
+	if err != nil {
+		return result, fmt.Errorf("failed to clean cache: %w", err)
+	}
+	return result, nil
+}
+
+// CacheListCommand handles listing cached kubeconfig fragments.
+type CacheListCommand struct {
+	fragmentCache domain.FragmentCache
+	logger        *slog.Logger
+}
+
+// NewCacheListCommand creates a new cache list command.
+func NewCacheListCommand(fragmentCache domain.FragmentCache, logger *slog.Logger) *CacheListCommand {
+	return &CacheListCommand{
+		fragmentCache: fragmentCache,
+		logger:        logger,
+	}
+}
+
+// CacheListResult contains the result of the cache list command.
+type CacheListResult struct {
+	Dir       string
+	Entries   []domain.CacheEntry
+	TotalSize int64
+}
+
+// Execute runs the cache list command.
+func (c *CacheListCommand) Execute(ctx context.Context) (*CacheListResult, error) {
+	entries, err := c.fragmentCache.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cache: %w", err)
+	}
+
+	result := &CacheListResult{
+		Dir:     c.fragmentCache.Dir(),
+		Entries: entries,
+	}
+	for _, entry := range entries {
+		result.TotalSize += entry.Size
+	}
+
+	c.logger.DebugContext(ctx, "Listed cached fragments", "count", len(entries), "bytes", result.TotalSize)
+	return result, nil
+}
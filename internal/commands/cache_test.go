@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheCleanCommand_Execute_UsesConfiguredServersAndRetention(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockCache := mocks.NewMockFragmentCache(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	mockConfigRepo.On("GetAllServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigRepo.On("GetSettings", mock.Anything).
+		Return(domain.Settings{Fragments: domain.FragmentSettings{RetentionDays: 7}}, nil)
+	mockCache.On("Clean", mock.Anything, domain.CleanupPolicy{
+		MaxAge:              7 * 24 * time.Hour,
+		ConfiguredServerIDs: []string{server.ID()},
+	}).Return(&domain.CleanupResult{Removed: []string{"a"}, Kept: 2}, nil)
+
+	cmd := NewCacheCleanCommand(mockConfigRepo, mockCache, testutil.Logger())
+
+	// Act
+	result, err := cmd.Execute(context.Background(), CacheCleanRequest{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, result.Removed, 1)
+	assert.Equal(t, 2, result.Kept)
+}
+
+func TestCacheCleanCommand_Execute_All(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockCache := mocks.NewMockFragmentCache(t)
+
+	mockCache.On("Clean", mock.Anything, domain.CleanupPolicy{All: true}).
+		Return(&domain.CleanupResult{}, nil)
+
+	cmd := NewCacheCleanCommand(mockConfigRepo, mockCache, testutil.Logger())
+
+	// Act
+	_, err := cmd.Execute(context.Background(), CacheCleanRequest{All: true})
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestCacheCleanCommand_Execute_GetServersFails(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockCache := mocks.NewMockFragmentCache(t)
+
+	mockConfigRepo.On("GetAllServers", mock.Anything).Return(nil, errors.New("boom"))
+
+	cmd := NewCacheCleanCommand(mockConfigRepo, mockCache, testutil.Logger())
+
+	// Act
+	_, err := cmd.Execute(context.Background(), CacheCleanRequest{})
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get servers")
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"context"
+
+	"cowpoke/internal/domain"
+)
+
+// KubeconfigChanges is what a sync changed in the merged kubeconfig, by context name. A context whose
+// endpoint changed along with its token is listed under both.
+type KubeconfigChanges struct {
+	Added           []string `json:"added,omitempty"`
+	Removed         []string `json:"removed,omitempty"`
+	TokenRefreshed  []string `json:"tokenRefreshed,omitempty"`
+	EndpointChanged []string `json:"endpointChanged,omitempty"`
+}
+
+// Empty reports whether the sync left every context as it was.
+func (c *KubeconfigChanges) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.TokenRefreshed) == 0 && len(c.EndpointChanged) == 0
+}
+
+// diffContexts compares the contexts of a kubeconfig before and after a sync. Both are sorted by name, and
+// so are the names in each list of changes.
+func diffContexts(before, after []domain.ContextSnapshot) *KubeconfigChanges {
+	previous := make(map[string]domain.ContextSnapshot, len(before))
+	for _, snapshot := range before {
+		previous[snapshot.Name] = snapshot
+	}
+
+	changes := &KubeconfigChanges{}
+	for _, snapshot := range after {
+		old, existed := previous[snapshot.Name]
+		delete(previous, snapshot.Name)
+		if !existed {
+			changes.Added = append(changes.Added, snapshot.Name)
+			continue
+		}
+		if old.Endpoint != snapshot.Endpoint {
+			changes.EndpointChanged = append(changes.EndpointChanged, snapshot.Name)
+		}
+		if old.Credential != snapshot.Credential {
+			changes.TokenRefreshed = append(changes.TokenRefreshed, snapshot.Name)
+		}
+	}
+	for _, snapshot := range before {
+		if _, removed := previous[snapshot.Name]; removed {
+			changes.Removed = append(changes.Removed, snapshot.Name)
+		}
+	}
+	return changes
+}
+
+// snapshotContexts snapshots the contexts of the kubeconfig at path. Failures are logged, and leave the
+// changes unknown.
+func (c *SyncCommand) snapshotContexts(
+	ctx context.Context,
+	kubeconfigHandler domain.KubeconfigHandler,
+	path string,
+) ([]domain.ContextSnapshot, bool) {
+	snapshots, err := kubeconfigHandler.SnapshotContexts(ctx, path)
+	if err != nil {
+		c.logger.WarnContext(ctx, "Failed to read kubeconfig contexts, not reporting changes", "path", path,
+			"error", err)
+		return nil, false
+	}
+	return snapshots, true
+}
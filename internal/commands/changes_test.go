@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"testing"
+
+	"cowpoke/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffContexts(t *testing.T) {
+	// Arrange
+	before := []domain.ContextSnapshot{
+		{Name: "dev", Endpoint: "https://dev.example.com", Credential: "a"},
+		{Name: "old", Endpoint: "https://old.example.com", Credential: "b"},
+		{Name: "prod", Endpoint: "https://prod.example.com", Credential: "c"},
+		{Name: "stage", Endpoint: "https://stage.example.com", Credential: "d"},
+	}
+	after := []domain.ContextSnapshot{
+		{Name: "dev", Endpoint: "https://dev.example.com", Credential: "a"},
+		{Name: "new", Endpoint: "https://new.example.com", Credential: "e"},
+		{Name: "prod", Endpoint: "https://prod.example.com", Credential: "f"},
+		{Name: "stage", Endpoint: "https://stage.vpn.example.com", Credential: "g"},
+	}
+
+	// Act
+	changes := diffContexts(before, after)
+
+	// Assert
+	assert.Equal(t, &KubeconfigChanges{
+		Added:           []string{"new"},
+		Removed:         []string{"old"},
+		TokenRefreshed:  []string{"prod", "stage"},
+		EndpointChanged: []string{"stage"},
+	}, changes)
+	assert.False(t, changes.Empty())
+	assert.True(t, diffContexts(before, before).Empty())
+}
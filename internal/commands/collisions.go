@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"cowpoke/internal/domain"
+)
+
+// ContextCollision is a cluster name shared by clusters on several servers. Their contexts only differ
+// by the server ID suffix, which makes them easy to confuse.
+type ContextCollision struct {
+	Name     string             `json:"name"`
+	Contexts []CollidingContext `json:"contexts"`
+}
+
+// CollidingContext is one merged context whose original name collides with another server's.
+type CollidingContext struct {
+	ServerURL      string `json:"serverUrl"`
+	Context        string `json:"context"`
+	SuggestedAlias string `json:"suggestedAlias"`
+}
+
+// findCollisions returns the cluster names merged from more than one server, sorted by name. Only
+// clusters whose fragment was merged and whose context the filter kept are considered. Suggested aliases
+// are sanitized under the naming policy.
+func findCollisions(
+	results []domain.ServerSyncResult,
+	mergedPaths []string,
+	clusterFilter domain.ClusterFilter,
+	naming domain.NamingSettings,
+) []ContextCollision {
+	merged := make(map[string]bool, len(mergedPaths))
+	for _, path := range mergedPaths {
+		merged[filepath.Base(path)] = true
+	}
+
+	var names []string
+	servers := make(map[string][]domain.ConfigServer)
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		serverID := result.Server.ID()
+		for _, cluster := range result.Clusters {
+			if !merged[domain.FragmentFileName(cluster.Name, serverID)] ||
+				clusterFilter.ShouldExclude(contextName(cluster.Name, serverID)) {
+				continue
+			}
+			if _, seen := servers[cluster.Name]; !seen {
+				names = append(names, cluster.Name)
+			}
+			if !slices.ContainsFunc(servers[cluster.Name], func(s domain.ConfigServer) bool {
+				return s.ID() == serverID
+			}) {
+				servers[cluster.Name] = append(servers[cluster.Name], result.Server)
+			}
+		}
+	}
+
+	var collisions []ContextCollision
+	for _, name := range names {
+		if len(servers[name]) < 2 { //nolint:mnd // A collision needs two servers
+			continue
+		}
+		collisions = append(collisions, ContextCollision{
+			Name:     name,
+			Contexts: collidingContexts(name, servers[name], naming),
+		})
+	}
+	slices.SortFunc(collisions, func(a, b ContextCollision) int { return cmp.Compare(a.Name, b.Name) })
+	return collisions
+}
+
+// collidingContexts suggests an alias for each server's context named after the server's host. The
+// first host label is used when it tells the servers apart, otherwise the whole host name, which
+// always does because server IDs are derived from it.
+func collidingContexts(name string, servers []domain.ConfigServer, naming domain.NamingSettings) []CollidingContext {
+	contexts := make([]CollidingContext, 0, len(servers))
+	for _, server := range servers {
+		contexts = append(contexts, CollidingContext{
+			ServerURL:      server.URL,
+			Context:        contextName(name, server.ID()),
+			SuggestedAlias: naming.Sanitize(name + "-" + hostLabel(server.URL, false)),
+		})
+	}
+	if !distinctAliases(contexts) {
+		for i := range contexts {
+			contexts[i].SuggestedAlias = naming.Sanitize(name + "-" + hostLabel(contexts[i].ServerURL, true))
+		}
+	}
+	slices.SortFunc(contexts, func(a, b CollidingContext) int { return cmp.Compare(a.ServerURL, b.ServerURL) })
+	return contexts
+}
+
+// hostLabel returns the first label of the server URL's host name, or the whole host name with dots
+// replaced by dashes.
+func hostLabel(serverURL string, full bool) string {
+	host := serverURL
+	if parsed, err := url.Parse(serverURL); err == nil && parsed.Hostname() != "" {
+		host = parsed.Hostname()
+	}
+	if full {
+		return strings.ReplaceAll(host, ".", "-")
+	}
+	label, _, _ := strings.Cut(host, ".")
+	return label
+}
+
+// distinctAliases reports whether no two contexts share a suggested alias.
+func distinctAliases(contexts []CollidingContext) bool {
+	seen := make(map[string]bool, len(contexts))
+	for _, context := range contexts {
+		if seen[context.SuggestedAlias] {
+			return false
+		}
+		seen[context.SuggestedAlias] = true
+	}
+	return true
+}
+
+// contextName returns the name of a cluster's context after the server ID suffix is appended.
+func contextName(clusterName, serverID string) string {
+	return fmt.Sprintf("%s-%s", clusterName, serverID)
+}
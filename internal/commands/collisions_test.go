@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"testing"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/services/filter"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindCollisions_SuggestsAliasesFromHosts(t *testing.T) {
+	tests := []struct {
+		name    string
+		urls    []string
+		naming  domain.NamingSettings
+		aliases []string
+	}{
+		{
+			name:    "first host label",
+			urls:    []string{"https://eu.example.com", "https://us.example.com"},
+			aliases: []string{"prod-eu", "prod-us"},
+		},
+		{
+			name:    "full host name when first labels match",
+			urls:    []string{"https://rancher.a.example.com", "https://rancher.b.example.com"},
+			aliases: []string{"prod-rancher-a-example-com", "prod-rancher-b-example-com"},
+		},
+		{
+			name:    "sanitized under the naming policy",
+			urls:    []string{"https://EU.example.com", "https://US.example.com"},
+			naming:  domain.NamingSettings{RFC1123: true},
+			aliases: []string{"prod-eu", "prod-us"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var results []domain.ServerSyncResult
+			var paths []string
+			for _, serverURL := range tt.urls {
+				server := domain.ConfigServer{URL: serverURL, Username: "admin", AuthType: "local"}
+				results = append(results, domain.ServerSyncResult{
+					Server:   server,
+					Clusters: []domain.Cluster{{ID: "c-1", Name: "prod"}, {ID: "c-2", Name: server.ID()}},
+				})
+				paths = append(paths,
+					"/tmp/"+domain.FragmentFileName("prod", server.ID()),
+					"/tmp/"+domain.FragmentFileName(server.ID(), server.ID()))
+			}
+
+			// Act
+			collisions := findCollisions(results, paths, filter.NewNoOpFilter(), tt.naming)
+
+			// Assert
+			require.Len(t, collisions, 1)
+			assert.Equal(t, "prod", collisions[0].Name)
+			for i, context := range collisions[0].Contexts {
+				assert.Equal(t, tt.aliases[i], context.SuggestedAlias)
+			}
+		})
+	}
+}
+
+func TestFindCollisions_IgnoresFailedServersAndUnmergedClusters(t *testing.T) {
+	// Arrange
+	a := domain.ConfigServer{URL: "https://a.example.com", Username: "admin", AuthType: "local"}
+	b := domain.ConfigServer{URL: "https://b.example.com", Username: "admin", AuthType: "local"}
+	c := domain.ConfigServer{URL: "https://c.example.com", Username: "admin", AuthType: "local"}
+	results := []domain.ServerSyncResult{
+		{Server: a, Clusters: []domain.Cluster{{ID: "c-1", Name: "prod"}}},
+		{Server: b, Clusters: []domain.Cluster{{ID: "c-1", Name: "prod"}}},
+		{Server: c, Clusters: []domain.Cluster{{ID: "c-1", Name: "prod"}}, Error: assert.AnError},
+	}
+	paths := []string{"/tmp/" + domain.FragmentFileName("prod", a.ID())}
+
+	// Act
+	collisions := findCollisions(results, paths, filter.NewNoOpFilter(), domain.NamingSettings{})
+
+	// Assert
+	assert.Empty(t, collisions)
+}
@@ -0,0 +1,242 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"cowpoke/internal/domain"
+)
+
+// ConfigValidateCommand handles checking the configuration file against the current schema.
+type ConfigValidateCommand struct {
+	fs             domain.FileSystemAdapter
+	configProvider domain.ConfigProvider
+	validator      domain.ConfigValidator
+	encryptor      domain.Encryptor
+	logger         *slog.Logger
+}
+
+// ConfigValidateOption configures a ConfigValidateCommand.
+type ConfigValidateOption func(*ConfigValidateCommand)
+
+// WithConfigEncryptor decrypts encrypted configuration files before validating them.
+func WithConfigEncryptor(encryptor domain.Encryptor) ConfigValidateOption {
+	return func(c *ConfigValidateCommand) {
+		c.encryptor = encryptor
+	}
+}
+
+// NewConfigValidateCommand creates a new config validate command.
+func NewConfigValidateCommand(
+	fs domain.FileSystemAdapter,
+	configProvider domain.ConfigProvider,
+	validator domain.ConfigValidator,
+	logger *slog.Logger,
+	opts ...ConfigValidateOption,
+) *ConfigValidateCommand {
+	c := &ConfigValidateCommand{
+		fs:             fs,
+		configProvider: configProvider,
+		validator:      validator,
+		logger:         logger,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ConfigValidateRequest contains the parameters for the config validate command.
+type ConfigValidateRequest struct {
+	// Path is the configuration file to validate; defaults to the active config file.
+	Path string
+}
+
+// ConfigValidateResult lists the issues found in a configuration file.
+type ConfigValidateResult struct {
+	Path   string
+	Issues []domain.ConfigIssue
+}
+
+// HasErrors reports whether any issue is an error rather than a warning.
+func (r *ConfigValidateResult) HasErrors() bool {
+	return slices.ContainsFunc(r.Issues, func(issue domain.ConfigIssue) bool {
+		return issue.Severity == domain.SeverityError
+	})
+}
+
+// Execute runs the config validate command.
+func (c *ConfigValidateCommand) Execute(ctx context.Context, req ConfigValidateRequest) (*ConfigValidateResult, error) {
+	path := req.Path
+	if path == "" {
+		var err error
+		path, err = c.configProvider.GetConfigPath()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get config path: %w", err)
+		}
+	}
+
+	data, err := c.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if c.encryptor != nil && c.encryptor.IsEncrypted(data) {
+		if data, err = c.encryptor.Decrypt(ctx, data); err != nil {
+			return nil, fmt.Errorf("%w %s: %w", domain.ErrConfigDecryption, path, err)
+		}
+	}
+
+	issues := c.validator.Validate(ctx, data)
+	c.logger.DebugContext(ctx, "Validated config file", "path", path, "issues", len(issues))
+
+	return &ConfigValidateResult{
+		Path:   path,
+		Issues: issues,
+	}, nil
+}
+
+// ConfigEncryptCommand handles encrypting and decrypting the configuration file at rest.
+type ConfigEncryptCommand struct {
+	configRepo     domain.ConfigRepository
+	configProvider domain.ConfigProvider
+	logger         *slog.Logger
+}
+
+// NewConfigEncryptCommand creates a new config encrypt command.
+func NewConfigEncryptCommand(
+	configRepo domain.ConfigRepository,
+	configProvider domain.ConfigProvider,
+	logger *slog.Logger,
+) *ConfigEncryptCommand {
+	return &ConfigEncryptCommand{
+		configRepo:     configRepo,
+		configProvider: configProvider,
+		logger:         logger,
+	}
+}
+
+// ConfigEncryptRequest contains the parameters for the config encrypt command.
+type ConfigEncryptRequest struct {
+	// Decrypt stores the configuration in plain text again instead of encrypting it.
+	Decrypt bool
+}
+
+// ConfigEncryptResult describes the configuration file after the command.
+type ConfigEncryptResult struct {
+	Path      string
+	Encrypted bool
+	// Changed is false if the file was already stored as requested.
+	Changed bool
+}
+
+// Execute runs the config encrypt command.
+func (c *ConfigEncryptCommand) Execute(ctx context.Context, req ConfigEncryptRequest) (*ConfigEncryptResult, error) {
+	path, err := c.configProvider.GetConfigPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	encrypt := !req.Decrypt
+	result := &ConfigEncryptResult{Path: path, Encrypted: encrypt}
+	if c.configRepo.Encrypted() == encrypt {
+		c.logger.DebugContext(ctx, "Configuration already stored as requested", "path", path, "encrypted", encrypt)
+		return result, nil
+	}
+
+	if setErr := c.configRepo.SetEncrypted(ctx, encrypt); setErr != nil {
+		return nil, fmt.Errorf("failed to rewrite config file: %w", setErr)
+	}
+	result.Changed = true
+	return result, nil
+}
+
+// ConfigHistoryCommand lists the backups of the configuration file.
+type ConfigHistoryCommand struct {
+	configRepo domain.ConfigRepository
+	logger     *slog.Logger
+}
+
+// NewConfigHistoryCommand creates a new config history command.
+func NewConfigHistoryCommand(configRepo domain.ConfigRepository, logger *slog.Logger) *ConfigHistoryCommand {
+	return &ConfigHistoryCommand{configRepo: configRepo, logger: logger}
+}
+
+// ConfigHistoryResult lists the backups of the configuration file, newest first.
+type ConfigHistoryResult struct {
+	Backups []domain.ConfigBackup
+}
+
+// Execute runs the config history command.
+func (c *ConfigHistoryCommand) Execute(ctx context.Context) (*ConfigHistoryResult, error) {
+	backups, err := c.configRepo.Backups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+	c.logger.DebugContext(ctx, "Listed configuration backups", "count", len(backups))
+	return &ConfigHistoryResult{Backups: backups}, nil
+}
+
+// ConfigRestoreCommand rolls the configuration file back to one of its backups.
+type ConfigRestoreCommand struct {
+	configRepo domain.ConfigRepository
+	logger     *slog.Logger
+}
+
+// NewConfigRestoreCommand creates a new config restore command.
+func NewConfigRestoreCommand(configRepo domain.ConfigRepository, logger *slog.Logger) *ConfigRestoreCommand {
+	return &ConfigRestoreCommand{configRepo: configRepo, logger: logger}
+}
+
+// ConfigRestoreRequest contains the parameters for the config restore command.
+type ConfigRestoreRequest struct {
+	// Timestamp is the timestamp of the backup to restore, or a unique prefix of it.
+	Timestamp string
+}
+
+// ConfigRestoreResult describes the backup restored and the servers, across all profiles, that the
+// restore brought back or removed.
+type ConfigRestoreResult struct {
+	Backup  domain.ConfigBackup
+	Added   []domain.ConfigServer
+	Removed []domain.ConfigServer
+}
+
+// Execute runs the config restore command.
+func (c *ConfigRestoreCommand) Execute(ctx context.Context, req ConfigRestoreRequest) (*ConfigRestoreResult, error) {
+	before, err := c.configRepo.GetAllServers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get servers: %w", err)
+	}
+	backup, err := c.configRepo.RestoreBackup(ctx, req.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore backup: %w", err)
+	}
+	after, err := c.configRepo.GetAllServers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get servers: %w", err)
+	}
+
+	result := &ConfigRestoreResult{
+		Backup:  backup,
+		Added:   missingServers(after, before),
+		Removed: missingServers(before, after),
+	}
+	c.logger.InfoContext(ctx, "Configuration restored",
+		"backup", backup.Timestamp,
+		"added", len(result.Added),
+		"removed", len(result.Removed))
+	return result, nil
+}
+
+// missingServers returns the servers of from that are not in to.
+func missingServers(from, to []domain.ConfigServer) []domain.ConfigServer {
+	var missing []domain.ConfigServer
+	for _, server := range from {
+		if !slices.ContainsFunc(to, func(other domain.ConfigServer) bool { return other.ID() == server.ID() }) {
+			missing = append(missing, server)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigValidateCommand_Execute_DefaultPath(t *testing.T) {
+	// Arrange
+	mockFS := mocks.NewMockFileSystemAdapter(t)
+	mockConfigProvider := mocks.NewMockConfigProvider(t)
+	mockValidator := mocks.NewMockConfigValidator(t)
+
+	data := []byte("version: \"2.0\"\n")
+	issues := []domain.ConfigIssue{
+		{Line: 1, Column: 1, Severity: domain.SeverityWarning, Message: "outdated"},
+	}
+
+	mockConfigProvider.On("GetConfigPath").Return("/home/user/.config/cowpoke/config.yaml", nil)
+	mockFS.On("ReadFile", "/home/user/.config/cowpoke/config.yaml").Return(data, nil)
+	mockValidator.On("Validate", mock.Anything, data).Return(issues)
+
+	cmd := NewConfigValidateCommand(mockFS, mockConfigProvider, mockValidator, testutil.Logger())
+
+	// Act
+	result, err := cmd.Execute(context.Background(), ConfigValidateRequest{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "/home/user/.config/cowpoke/config.yaml", result.Path)
+	assert.Equal(t, issues, result.Issues)
+	assert.False(t, result.HasErrors())
+}
+
+func TestConfigValidateCommand_Execute_ReadFails(t *testing.T) {
+	// Arrange
+	mockFS := mocks.NewMockFileSystemAdapter(t)
+	mockFS.On("ReadFile", "/tmp/config.yaml").Return(nil, errors.New("permission denied"))
+
+	cmd := NewConfigValidateCommand(mockFS, mocks.NewMockConfigProvider(t), mocks.NewMockConfigValidator(t),
+		testutil.Logger())
+
+	// Act
+	_, err := cmd.Execute(context.Background(), ConfigValidateRequest{Path: "/tmp/config.yaml"})
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read config file")
+}
+
+func TestConfigValidateResult_HasErrors(t *testing.T) {
+	result := &ConfigValidateResult{Issues: []domain.ConfigIssue{
+		{Severity: domain.SeverityWarning},
+		{Severity: domain.SeverityError},
+	}}
+
+	assert.True(t, result.HasErrors())
+}
+
+func TestConfigValidateCommand_Execute_DecryptsEncryptedConfig(t *testing.T) {
+	// Arrange
+	mockFS := mocks.NewMockFileSystemAdapter(t)
+	mockValidator := mocks.NewMockConfigValidator(t)
+	mockEncryptor := mocks.NewMockEncryptor(t)
+
+	encrypted := []byte("cowpoke-enc:v1\nsealed")
+	data := []byte("version: \"3.0\"\n")
+
+	mockFS.On("ReadFile", "/tmp/config.yaml").Return(encrypted, nil)
+	mockEncryptor.On("IsEncrypted", encrypted).Return(true)
+	mockEncryptor.On("Decrypt", mock.Anything, encrypted).Return(data, nil)
+	mockValidator.On("Validate", mock.Anything, data).Return(nil)
+
+	cmd := NewConfigValidateCommand(mockFS, mocks.NewMockConfigProvider(t), mockValidator, testutil.Logger(),
+		WithConfigEncryptor(mockEncryptor))
+
+	// Act
+	result, err := cmd.Execute(context.Background(), ConfigValidateRequest{Path: "/tmp/config.yaml"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, result.Issues)
+}
+
+func TestConfigEncryptCommand_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		decrypt     bool
+		encrypted   bool
+		wantChanged bool
+	}{
+		{name: "encrypt plain config", wantChanged: true},
+		{name: "encrypt encrypted config", encrypted: true},
+		{name: "decrypt encrypted config", decrypt: true, encrypted: true, wantChanged: true},
+		{name: "decrypt plain config", decrypt: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockConfigRepo := mocks.NewMockConfigRepository(t)
+			mockConfigProvider := mocks.NewMockConfigProvider(t)
+
+			mockConfigProvider.On("GetConfigPath").Return("/home/user/.config/cowpoke/config.yaml", nil)
+			mockConfigRepo.On("Encrypted").Return(tt.encrypted)
+			if tt.wantChanged {
+				mockConfigRepo.On("SetEncrypted", mock.Anything, !tt.decrypt).Return(nil)
+			}
+
+			cmd := NewConfigEncryptCommand(mockConfigRepo, mockConfigProvider, testutil.Logger())
+
+			// Act
+			result, err := cmd.Execute(context.Background(), ConfigEncryptRequest{Decrypt: tt.decrypt})
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, &ConfigEncryptResult{
+				Path:      "/home/user/.config/cowpoke/config.yaml",
+				Encrypted: !tt.decrypt,
+				Changed:   tt.wantChanged,
+			}, result)
+		})
+	}
+}
+
+func TestConfigRestoreCommand_Execute(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+
+	prod := domain.ConfigServer{URL: "https://prod.example.com", Username: "admin", AuthType: "local"}
+	staging := domain.ConfigServer{URL: "https://staging.example.com", Username: "admin", AuthType: "local"}
+	scratch := domain.ConfigServer{URL: "https://scratch.example.com", Username: "admin", AuthType: "local"}
+	backup := domain.ConfigBackup{Timestamp: "20261015T093002.000Z", Path: "/backups/config-20261015T093002.000Z.yaml"}
+
+	mockConfigRepo.On("GetAllServers", mock.Anything).Return([]domain.ConfigServer{staging, scratch}, nil).Once()
+	mockConfigRepo.On("RestoreBackup", mock.Anything, "20261015T0930").Return(backup, nil)
+	mockConfigRepo.On("GetAllServers", mock.Anything).Return([]domain.ConfigServer{prod, staging}, nil).Once()
+
+	cmd := NewConfigRestoreCommand(mockConfigRepo, testutil.Logger())
+
+	// Act
+	result, err := cmd.Execute(context.Background(), ConfigRestoreRequest{Timestamp: "20261015T0930"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, &ConfigRestoreResult{
+		Backup:  backup,
+		Added:   []domain.ConfigServer{prod},
+		Removed: []domain.ConfigServer{scratch},
+	}, result)
+}
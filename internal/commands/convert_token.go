@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"cowpoke/internal/domain"
+)
+
+// ConvertToTokenCommand switches a server to authenticating with a long-lived API token, so that syncs
+// never prompt for its password again.
+type ConvertToTokenCommand struct {
+	configRepo     domain.ConfigRepository
+	passwordReader domain.PasswordReader
+	rancherClient  domain.RancherClient
+	tokenCache     domain.TokenCache
+	logger         *slog.Logger
+}
+
+// NewConvertToTokenCommand creates a new convert-to-token command.
+func NewConvertToTokenCommand(
+	configRepo domain.ConfigRepository,
+	passwordReader domain.PasswordReader,
+	rancherClient domain.RancherClient,
+	tokenCache domain.TokenCache,
+	logger *slog.Logger,
+) *ConvertToTokenCommand {
+	return &ConvertToTokenCommand{
+		configRepo:     configRepo,
+		passwordReader: passwordReader,
+		rancherClient:  rancherClient,
+		tokenCache:     tokenCache,
+		logger:         logger,
+	}
+}
+
+// ConvertToTokenRequest contains the parameters for the convert-to-token command. The server is given by
+// URL or ID.
+type ConvertToTokenRequest struct {
+	Server string
+	// AuthType is the provider to log in with to create the token; defaults to the server's auth type. It
+	// is required to replace the token of a server that already authenticates with one.
+	AuthType string
+}
+
+// ConvertToTokenResult describes the API token created for a server.
+type ConvertToTokenResult struct {
+	ServerURL string
+	// ExpiresAt is zero if the token never expires.
+	ExpiresAt time.Time
+}
+
+// Execute logs in to the requested server with a password, creates an API token, stores it in the token
+// cache and switches the server's auth type to token. The login session is revoked once the token exists.
+func (c *ConvertToTokenCommand) Execute(ctx context.Context, req ConvertToTokenRequest) (*ConvertToTokenResult, error) {
+	if req.Server == "" {
+		return nil, errors.New("server must be specified")
+	}
+
+	servers, err := c.configRepo.GetServers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get servers: %w", err)
+	}
+	server, err := domain.FindServer(servers, req.Server)
+	if err != nil {
+		return nil, err
+	}
+	if server == nil {
+		return nil, fmt.Errorf("server %s is not configured", req.Server)
+	}
+
+	login := *server
+	login.AuthType = cmp.Or(req.AuthType, server.AuthType)
+	if login.AuthType == domain.AuthTypeToken {
+		return nil, fmt.Errorf("%s already authenticates with an API token (use --authtype to log in and replace it)",
+			server.URL)
+	}
+
+	password, err := c.passwordReader.ReadPassword(ctx, fmt.Sprintf("Password for %s: ", server.URL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read password for %s: %w", server.URL, err)
+	}
+
+	c.logger.InfoContext(ctx, "Logging in", "url", server.URL, "username", server.Username)
+	session, err := c.rancherClient.Authenticate(ctx, login, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate with %s: %w", server.URL, err)
+	}
+
+	apiToken, err := c.rancherClient.CreateAPIToken(ctx, session, login)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create an API token on %s: %w", server.URL, err)
+	}
+	if revokeErr := c.rancherClient.RevokeToken(ctx, session, login); revokeErr != nil {
+		c.logger.WarnContext(ctx, "Could not revoke login session", "url", server.URL, "error", revokeErr)
+	}
+
+	converted := *server
+	converted.AuthType = domain.AuthTypeToken
+	if putErr := c.tokenCache.Put(ctx, converted, apiToken); putErr != nil {
+		return nil, fmt.Errorf("failed to store API token: %w", putErr)
+	}
+	if updateErr := c.configRepo.UpdateServerAuthType(ctx, server.URL, domain.AuthTypeToken); updateErr != nil {
+		return nil, fmt.Errorf("failed to switch %s to token authentication: %w", server.URL, updateErr)
+	}
+
+	c.logger.InfoContext(ctx, "Converted server to token authentication",
+		"url", server.URL, "expires_at", apiToken.ExpiresAt())
+	return &ConvertToTokenResult{ServerURL: server.URL, ExpiresAt: apiToken.ExpiresAt()}, nil
+}
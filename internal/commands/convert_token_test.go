@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertToTokenCommand_Execute(t *testing.T) {
+	tests := []struct {
+		name      string
+		authType  string
+		requested string
+		loginWith string
+	}{
+		{name: "password server", authType: "local", loginWith: "local"},
+		{name: "replace token", authType: domain.AuthTypeToken, requested: "openldap", loginWith: "openldap"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockConfigRepo := mocks.NewMockConfigRepository(t)
+			mockPasswordReader := mocks.NewMockPasswordReader(t)
+			mockRancherClient := mocks.NewMockRancherClient(t)
+			mockTokenCache := mocks.NewMockTokenCache(t)
+			session := mocks.NewMockAuthToken(t)
+			apiToken := mocks.NewMockAuthToken(t)
+
+			server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: tt.authType}
+			login := server
+			login.AuthType = tt.loginWith
+			converted := server
+			converted.AuthType = domain.AuthTypeToken
+
+			mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+			mockPasswordReader.On("ReadPassword", mock.Anything, "Password for https://rancher.example.com: ").
+				Return("password123", nil)
+			mockRancherClient.On("Authenticate", mock.Anything, login, "password123").Return(session, nil)
+			mockRancherClient.On("CreateAPIToken", mock.Anything, session, login).Return(apiToken, nil)
+			mockRancherClient.On("RevokeToken", mock.Anything, session, login).Return(nil)
+			mockTokenCache.On("Put", mock.Anything, converted, apiToken).Return(nil)
+			mockConfigRepo.On("UpdateServerAuthType", mock.Anything, server.URL, domain.AuthTypeToken).Return(nil)
+			apiToken.On("ExpiresAt").Return(time.Time{})
+
+			cmd := NewConvertToTokenCommand(mockConfigRepo, mockPasswordReader, mockRancherClient, mockTokenCache,
+				testutil.Logger())
+
+			// Act
+			result, err := cmd.Execute(context.Background(), ConvertToTokenRequest{
+				Server:   server.ID(),
+				AuthType: tt.requested,
+			})
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, &ConvertToTokenResult{ServerURL: server.URL}, result)
+		})
+	}
+}
+
+func TestConvertToTokenCommand_Execute_AlreadyToken(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: domain.AuthTypeToken}
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+
+	cmd := NewConvertToTokenCommand(mockConfigRepo, mocks.NewMockPasswordReader(t), mocks.NewMockRancherClient(t),
+		mocks.NewMockTokenCache(t), testutil.Logger())
+
+	// Act
+	_, err := cmd.Execute(context.Background(), ConvertToTokenRequest{Server: server.URL})
+
+	// Assert
+	require.ErrorContains(t, err, "already authenticates with an API token")
+}
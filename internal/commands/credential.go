@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"cowpoke/internal/domain"
+)
+
+// credentialRenewal is how long before it expires a cached token is replaced, so that kubectl is not
+// handed a token that expires mid-request.
+const credentialRenewal = 5 * time.Minute
+
+// CredentialCommand handles providing the token of one cluster to the exec credential plugins of a
+// merged kubeconfig, serving it from the fragment cache or downloading a fresh kubeconfig from Rancher.
+type CredentialCommand struct {
+	configRepo        domain.ConfigRepository
+	fragmentCache     domain.FragmentCache
+	tokenCache        domain.TokenCache
+	passwordReader    domain.PasswordReader
+	kubeconfigHandler domain.KubeconfigHandler
+	clock             domain.Clock
+	logger            *slog.Logger
+}
+
+// NewCredentialCommand creates a new credential command.
+func NewCredentialCommand(
+	configRepo domain.ConfigRepository,
+	fragmentCache domain.FragmentCache,
+	tokenCache domain.TokenCache,
+	passwordReader domain.PasswordReader,
+	kubeconfigHandler domain.KubeconfigHandler,
+	clock domain.Clock,
+	logger *slog.Logger,
+) *CredentialCommand {
+	return &CredentialCommand{
+		configRepo:        configRepo,
+		fragmentCache:     fragmentCache,
+		tokenCache:        tokenCache,
+		passwordReader:    passwordReader,
+		kubeconfigHandler: kubeconfigHandler,
+		clock:             clock,
+		logger:            logger,
+	}
+}
+
+// CredentialRequest contains the parameters for the credential command.
+type CredentialRequest struct {
+	// Server is the URL or ID of the cluster's server, which may belong to any profile.
+	Server string
+	// ClusterID is the Rancher ID of the cluster.
+	ClusterID string
+}
+
+// CredentialResult contains the result of the credential command.
+type CredentialResult struct {
+	domain.ClusterCredential
+	// Refreshed reports whether the kubeconfig was downloaded because no valid token was cached.
+	Refreshed bool
+}
+
+// Execute runs the credential command. Downloads go through syncOrchestrator, which caches the fragment
+// as a sync would.
+func (c *CredentialCommand) Execute(
+	ctx context.Context,
+	req CredentialRequest,
+	syncOrchestrator domain.SyncOrchestrator,
+) (*CredentialResult, error) {
+	if req.Server == "" || req.ClusterID == "" {
+		return nil, errors.New("server and cluster ID are required")
+	}
+
+	servers, err := c.configRepo.GetAllServers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get servers: %w", err)
+	}
+	server, err := domain.FindServer(servers, req.Server)
+	if err != nil {
+		return nil, err
+	}
+	if server == nil {
+		return nil, fmt.Errorf("server %s is not configured", req.Server)
+	}
+
+	entries, err := c.fragmentCache.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cached kubeconfigs: %w", err)
+	}
+	cluster := domain.Cluster{ID: req.ClusterID, Name: req.ClusterID}
+	for _, entry := range entries {
+		if entry.ServerID != server.ID() || entry.ClusterID != req.ClusterID {
+			continue
+		}
+		cluster.Name = entry.ClusterName
+		credential, credErr := c.kubeconfigHandler.ClusterCredential(ctx,
+			filepath.Join(c.fragmentCache.Dir(), entry.File))
+		if credErr != nil {
+			c.logger.DebugContext(ctx, "Cached kubeconfig has no usable token", "file", entry.File, "error", credErr)
+			break
+		}
+		if !credential.ExpiresAt.IsZero() && credential.ExpiresAt.Before(c.clock.Now().Add(credentialRenewal)) {
+			c.logger.DebugContext(ctx, "Cached token expires soon", "file", entry.File,
+				"expires_at", credential.ExpiresAt)
+			break
+		}
+		return &CredentialResult{ClusterCredential: credential}, nil
+	}
+
+	path, err := downloadFragment(ctx, c.tokenCache, c.passwordReader, *server, cluster, syncOrchestrator, c.logger)
+	if err != nil {
+		return nil, err
+	}
+	credential, err := c.kubeconfigHandler.ClusterCredential(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	c.logger.DebugContext(ctx, "Refreshed cluster credential", "server", server.URL, "cluster", req.ClusterID)
+	return &CredentialResult{ClusterCredential: credential, Refreshed: true}, nil
+}
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialCommand_Execute(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		expiresAt     time.Time
+		wantRefreshed bool
+	}{
+		{name: "never expires", expiresAt: time.Time{}},
+		{name: "valid", expiresAt: now.Add(time.Hour)},
+		{name: "expires soon", expiresAt: now.Add(time.Minute), wantRefreshed: true},
+		{name: "expired", expiresAt: now.Add(-time.Hour), wantRefreshed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+			mockConfigRepo := mocks.NewMockConfigRepository(t)
+			mockConfigRepo.On("GetAllServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+			mockCache := mocks.NewMockFragmentCache(t)
+			mockCache.On("List", mock.Anything).Return([]domain.CacheEntry{{
+				File:        "prod-" + server.ID() + ".yaml",
+				ServerID:    server.ID(),
+				ClusterID:   "c-m-prod",
+				ClusterName: "prod",
+			}}, nil)
+			mockCache.On("Dir").Return("/cache")
+			cachedPath := filepath.Join("/cache", "prod-"+server.ID()+".yaml")
+			mockHandler := mocks.NewMockKubeconfigHandler(t)
+			mockHandler.On("ClusterCredential", mock.Anything, cachedPath).
+				Return(domain.ClusterCredential{Token: "cached", ExpiresAt: tt.expiresAt}, nil)
+
+			mockTokenCache := mocks.NewMockTokenCache(t)
+			mockSyncOrchestrator := mocks.NewMockSyncOrchestrator(t)
+			if tt.wantRefreshed {
+				mockTokenCache.On("Get", mock.Anything, server).Return(mocks.NewMockAuthToken(t), nil)
+				targets := []domain.ServerClusters{
+					{Server: server, Clusters: []domain.Cluster{{ID: "c-m-prod", Name: "prod"}}},
+				}
+				mockSyncOrchestrator.On("SyncClusters", mock.Anything, targets, map[string]string{}, mock.Anything).
+					Return(&domain.SyncResult{KubeconfigPaths: []string{"/cache/fresh.yaml"}}, nil)
+				mockHandler.On("ClusterCredential", mock.Anything, "/cache/fresh.yaml").
+					Return(domain.ClusterCredential{Token: "fresh", ExpiresAt: now.Add(24 * time.Hour)}, nil)
+			}
+
+			cmd := NewCredentialCommand(mockConfigRepo, mockCache, mockTokenCache, mocks.NewMockPasswordReader(t),
+				mockHandler, testutil.NewClock(now), testutil.Logger())
+
+			// Act
+			result, err := cmd.Execute(context.Background(),
+				CredentialRequest{Server: server.ID(), ClusterID: "c-m-prod"}, mockSyncOrchestrator)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRefreshed, result.Refreshed)
+			if tt.wantRefreshed {
+				assert.Equal(t, "fresh", result.Token)
+			} else {
+				assert.Equal(t, "cached", result.Token)
+			}
+		})
+	}
+}
+
+func TestCredentialCommand_Execute_UnknownServer(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigRepo.On("GetAllServers", mock.Anything).Return([]domain.ConfigServer{}, nil)
+
+	cmd := NewCredentialCommand(mockConfigRepo, mocks.NewMockFragmentCache(t), nil, nil,
+		mocks.NewMockKubeconfigHandler(t), testutil.NewClock(time.Now()), testutil.Logger())
+
+	// Act
+	_, err := cmd.Execute(context.Background(), CredentialRequest{Server: "abc12345", ClusterID: "c-m-prod"}, nil)
+
+	// Assert
+	require.ErrorContains(t, err, "server abc12345 is not configured")
+}
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"cowpoke/internal/domain"
+)
+
+const (
+	// endpointLookupTimeout bounds the DNS lookup of a single endpoint host.
+	endpointLookupTimeout = 3 * time.Second
+	// endpointLookupWorkers is how many endpoint hosts are looked up at once.
+	endpointLookupWorkers = 16
+)
+
+// UnresolvedEndpoint is a cluster endpoint host that does not resolve from this machine, so kubectl
+// cannot reach the contexts using it.
+type UnresolvedEndpoint struct {
+	Host     string   `json:"host"`
+	Contexts []string `json:"contexts"`
+	Error    string   `json:"error"`
+}
+
+// WithEndpointCheck looks up the endpoint hosts of the merged kubeconfig with resolver when a sync asks
+// for it, reporting those that do not resolve.
+func WithEndpointCheck(resolver domain.Resolver) SyncOption {
+	return func(c *SyncCommand) {
+		c.resolver = resolver
+	}
+}
+
+// checkEndpoints looks up the host of every cluster endpoint in the merged kubeconfig at path and returns
+// those that do not resolve, sorted by host. Endpoints given by IP address are not checked. Failing to
+// read the kubeconfig only skips the check.
+func (c *SyncCommand) checkEndpoints(
+	ctx context.Context,
+	kubeconfigHandler domain.KubeconfigHandler,
+	path string,
+) []UnresolvedEndpoint {
+	contexts, err := kubeconfigHandler.ListContexts(ctx, path)
+	if err != nil {
+		c.logger.WarnContext(ctx, "Could not read kubeconfig to check endpoints", "error", err)
+		return nil
+	}
+
+	hosts := make(map[string][]string)
+	for _, kubeContext := range contexts {
+		endpoint, parseErr := url.Parse(kubeContext.Endpoint)
+		if parseErr != nil || endpoint.Hostname() == "" || net.ParseIP(endpoint.Hostname()) != nil {
+			continue
+		}
+		hosts[endpoint.Hostname()] = append(hosts[endpoint.Hostname()], kubeContext.Name)
+	}
+
+	var (
+		mu         sync.Mutex
+		wg         sync.WaitGroup
+		unresolved []UnresolvedEndpoint
+	)
+	workers := make(chan struct{}, endpointLookupWorkers)
+	for host, names := range hosts {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func() {
+			defer func() { <-workers; wg.Done() }()
+			lookupCtx, cancel := context.WithTimeout(ctx, endpointLookupTimeout)
+			defer cancel()
+			if _, lookupErr := c.resolver.LookupHost(lookupCtx, host); lookupErr != nil {
+				c.logger.DebugContext(ctx, "Endpoint does not resolve", "host", host, "error", lookupErr)
+				mu.Lock()
+				unresolved = append(unresolved, UnresolvedEndpoint{Host: host, Contexts: names, Error: lookupErr.Error()})
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	slices.SortFunc(unresolved, func(a, b UnresolvedEndpoint) int { return strings.Compare(a.Host, b.Host) })
+	c.logger.InfoContext(ctx, "Checked cluster endpoints", "hosts", len(hosts), "unresolved", len(unresolved))
+	return unresolved
+}
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncCommand_Execute_CheckEndpoints(t *testing.T) {
+	// Arrange
+	output := "/home/user/.kube/config"
+	mockConfigRepo, mockPasswordReader, mockSyncOrchestrator, mockKubeconfigHandler := syncWithOneCluster(t, output)
+	mockResolver := mocks.NewMockResolver(t)
+
+	mockKubeconfigHandler.On("ListContexts", mock.Anything, output).Return([]domain.ManagedContext{
+		{Name: "prod-abc12345", Endpoint: "https://rancher.example.com/k8s/clusters/c-m-prod"},
+		{Name: "prod-fqdn-abc12345", Endpoint: "https://prod.internal.example.com:6443"},
+		{Name: "stage-fqdn-abc12345", Endpoint: "https://stage.internal.example.com"},
+		{Name: "stage-node-abc12345", Endpoint: "https://10.0.0.12:6443"},
+	}, nil)
+	mockResolver.On("LookupHost", mock.Anything, "rancher.example.com").Return([]string{"192.0.2.10"}, nil)
+	mockResolver.On("LookupHost", mock.Anything, "prod.internal.example.com").
+		Return(nil, errors.New("no such host"))
+	mockResolver.On("LookupHost", mock.Anything, "stage.internal.example.com").
+		Return(nil, errors.New("no such host"))
+
+	cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockPasswordReader, testutil.Logger(),
+		WithEndpointCheck(mockResolver))
+
+	// Act
+	report, err := cmd.Execute(context.Background(), SyncRequest{Output: output, CheckEndpoints: true},
+		mockSyncOrchestrator, mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []UnresolvedEndpoint{
+		{Host: "prod.internal.example.com", Contexts: []string{"prod-fqdn-abc12345"}, Error: "no such host"},
+		{Host: "stage.internal.example.com", Contexts: []string{"stage-fqdn-abc12345"}, Error: "no such host"},
+	}, report.Unresolved)
+}
+
+func TestSyncCommand_Execute_EndpointsNotCheckedByDefault(t *testing.T) {
+	// Arrange
+	output := "/home/user/.kube/config"
+	mockConfigRepo, mockPasswordReader, mockSyncOrchestrator, mockKubeconfigHandler := syncWithOneCluster(t, output)
+
+	cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockPasswordReader, testutil.Logger(),
+		WithEndpointCheck(mocks.NewMockResolver(t)))
+
+	// Act
+	report, err := cmd.Execute(context.Background(), SyncRequest{Output: output},
+		mockSyncOrchestrator, mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, report.Unresolved)
+	mockKubeconfigHandler.AssertNotCalled(t, "ListContexts", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+
+	"cowpoke/internal/domain"
+)
+
+// EnvCommand works out the KUBECONFIG that combines cowpoke's kubeconfig with the user's own.
+type EnvCommand struct {
+	configRepo     domain.ConfigRepository
+	configProvider domain.ConfigProvider
+	logger         *slog.Logger
+}
+
+// NewEnvCommand creates a new env command.
+func NewEnvCommand(
+	configRepo domain.ConfigRepository,
+	configProvider domain.ConfigProvider,
+	logger *slog.Logger,
+) *EnvCommand {
+	return &EnvCommand{
+		configRepo:     configRepo,
+		configProvider: configProvider,
+		logger:         logger,
+	}
+}
+
+// EnvRequest contains the parameters for the env command.
+type EnvRequest struct {
+	// Kubeconfig is the kubeconfig cowpoke writes; defaults to the profile's default output, then to the
+	// default kubeconfig.
+	Kubeconfig string
+	// Current is the KUBECONFIG already set, if any. Its files are kept; when empty, kubectl's own
+	// kubeconfig is used instead.
+	Current string
+}
+
+// EnvResult lists the kubeconfigs KUBECONFIG should name.
+type EnvResult struct {
+	// Paths starts with cowpoke's kubeconfig, so that cowpoke use and kubectl config use-context change
+	// the current context there rather than in the user's files.
+	Paths []string
+}
+
+// Execute runs the env command. Running it again with its own output as Current changes nothing.
+func (c *EnvCommand) Execute(ctx context.Context, req EnvRequest) (*EnvResult, error) {
+	defaults, err := c.configRepo.GetDefaults(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile defaults: %w", err)
+	}
+	kubeconfigPath, err := resolveKubeconfig(c.configProvider, cmp.Or(req.Kubeconfig, defaults.Output))
+	if err != nil {
+		return nil, err
+	}
+
+	others := filepath.SplitList(req.Current)
+	if len(others) == 0 {
+		kubectlPath, err := c.configProvider.GetKubectlKubeconfigPath()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get kubectl kubeconfig path: %w", err)
+		}
+		others = []string{kubectlPath}
+	}
+
+	paths := []string{kubeconfigPath}
+	for _, path := range others {
+		if path != "" && !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	c.logger.DebugContext(ctx, "Resolved KUBECONFIG", "paths", paths)
+	return &EnvResult{Paths: paths}, nil
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvCommand_Execute(t *testing.T) {
+	tests := []struct {
+		name       string
+		kubeconfig string
+		defaults   domain.ProfileDefaults
+		current    string
+		want       []string
+	}{
+		{
+			name: "KUBECONFIG not set",
+			want: []string{"/home/user/.kube/cowpoke.config", "/home/user/.kube/config"},
+		},
+		{
+			name:    "KUBECONFIG set",
+			current: "/home/user/.kube/work.yaml::/home/user/.kube/config",
+			want: []string{
+				"/home/user/.kube/cowpoke.config", "/home/user/.kube/work.yaml", "/home/user/.kube/config",
+			},
+		},
+		{
+			name:    "already includes cowpoke's kubeconfig",
+			current: "/home/user/.kube/cowpoke.config:/home/user/.kube/config",
+			want:    []string{"/home/user/.kube/cowpoke.config", "/home/user/.kube/config"},
+		},
+		{
+			name:     "profile default output",
+			defaults: domain.ProfileDefaults{Output: "/home/user/.kube/team.config"},
+			want:     []string{"/home/user/.kube/team.config", "/home/user/.kube/config"},
+		},
+		{
+			name:       "explicit kubeconfig",
+			defaults:   domain.ProfileDefaults{Output: "/home/user/.kube/team.config"},
+			kubeconfig: "/tmp/rancher.yaml",
+			current:    "/home/user/.kube/config",
+			want:       []string{"/tmp/rancher.yaml", "/home/user/.kube/config"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockConfigRepo := mocks.NewMockConfigRepository(t)
+			mockConfigProvider := mocks.NewMockConfigProvider(t)
+			mockConfigRepo.On("GetDefaults", mock.Anything).Return(tt.defaults, nil)
+			mockConfigProvider.On("GetDefaultKubeconfigPath").Return("/home/user/.kube/cowpoke.config", nil).Maybe()
+			mockConfigProvider.On("GetKubectlKubeconfigPath").Return("/home/user/.kube/config", nil).Maybe()
+
+			cmd := NewEnvCommand(mockConfigRepo, mockConfigProvider, testutil.Logger())
+
+			// Act
+			result, err := cmd.Execute(context.Background(), EnvRequest{Kubeconfig: tt.kubeconfig, Current: tt.current})
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.Paths)
+		})
+	}
+}
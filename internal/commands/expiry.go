@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"cowpoke/internal/domain"
+)
+
+// ExpiryCommand handles finding the contexts whose credentials are about to expire.
+type ExpiryCommand struct {
+	configRepo        domain.ConfigRepository
+	configProvider    domain.ConfigProvider
+	kubeconfigHandler domain.KubeconfigHandler
+	clock             domain.Clock
+	logger            *slog.Logger
+}
+
+// NewExpiryCommand creates a new expiry command.
+func NewExpiryCommand(
+	configRepo domain.ConfigRepository,
+	configProvider domain.ConfigProvider,
+	kubeconfigHandler domain.KubeconfigHandler,
+	clock domain.Clock,
+	logger *slog.Logger,
+) *ExpiryCommand {
+	return &ExpiryCommand{
+		configRepo:        configRepo,
+		configProvider:    configProvider,
+		kubeconfigHandler: kubeconfigHandler,
+		clock:             clock,
+		logger:            logger,
+	}
+}
+
+// ExpiryRequest contains the parameters for the expiry command.
+type ExpiryRequest struct {
+	// Kubeconfig is the merged kubeconfig to inspect, defaulting to the profile's or the default output.
+	Kubeconfig string
+	// Within is how far ahead credentials count as expiring. Expired credentials always do.
+	Within time.Duration
+}
+
+// ExpiryResult contains the result of the expiry command.
+type ExpiryResult struct {
+	Kubeconfig string
+	// Contexts are the expiring contexts, soonest first.
+	Contexts []domain.ManagedContext
+}
+
+// Clusters returns the clusters of the expiring contexts, for syncing only those.
+func (r *ExpiryResult) Clusters() []domain.ClusterRef {
+	return clusterRefs(r.Contexts)
+}
+
+// clusterRefs returns the clusters of contexts by server ID and cluster ID, named after their cluster.
+func clusterRefs(contexts []domain.ManagedContext) []domain.ClusterRef {
+	refs := make([]domain.ClusterRef, 0, len(contexts))
+	for _, managed := range contexts {
+		refs = append(refs, domain.ClusterRef{
+			Server:    managed.Owner.ServerID,
+			ClusterID: managed.Owner.ClusterID,
+			Name:      managed.ClusterName,
+		})
+	}
+	return refs
+}
+
+// Execute runs the expiry command. Contexts without a recorded expiry or cluster ID are never expiring,
+// since they could not be refreshed on their own.
+func (c *ExpiryCommand) Execute(ctx context.Context, req ExpiryRequest) (*ExpiryResult, error) {
+	defaults, err := c.configRepo.GetDefaults(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile defaults: %w", err)
+	}
+	kubeconfigPath, err := resolveKubeconfig(c.configProvider, cmp.Or(req.Kubeconfig, defaults.Output))
+	if err != nil {
+		return nil, err
+	}
+
+	contexts, err := c.kubeconfigHandler.ListContexts(ctx, kubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contexts: %w", err)
+	}
+
+	deadline := c.clock.Now().Add(req.Within)
+	result := &ExpiryResult{Kubeconfig: kubeconfigPath}
+	for _, managed := range contexts {
+		expiresAt := managed.Owner.ExpiresAt
+		if expiresAt.IsZero() || managed.Owner.ClusterID == "" || expiresAt.After(deadline) {
+			continue
+		}
+		result.Contexts = append(result.Contexts, managed)
+	}
+	slices.SortStableFunc(result.Contexts, func(a, b domain.ManagedContext) int {
+		return a.Owner.ExpiresAt.Compare(b.Owner.ExpiresAt)
+	})
+
+	c.logger.DebugContext(ctx, "Checked context credential expiry",
+		"path", kubeconfigPath,
+		"contexts", len(contexts),
+		"expiring", len(result.Contexts))
+	return result, nil
+}
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiryCommand_Execute(t *testing.T) {
+	// Arrange
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigProvider := mocks.NewMockConfigProvider(t)
+	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+
+	owner := func(clusterID string, expiresAt time.Time) domain.ContextOwner {
+		return domain.ContextOwner{ServerID: "1a2b3c4d", ClusterID: clusterID, ExpiresAt: expiresAt}
+	}
+	expired := domain.ManagedContext{Name: "dev-1a2b3c4d", ClusterName: "dev",
+		Owner: owner("c-m-dev", now.Add(-time.Hour))}
+	soon := domain.ManagedContext{Name: "prod-1a2b3c4d", ClusterName: "prod",
+		Owner: owner("c-m-prod", now.Add(48*time.Hour))}
+	later := domain.ManagedContext{Name: "qa-1a2b3c4d", ClusterName: "qa",
+		Owner: owner("c-m-qa", now.Add(30*24*time.Hour))}
+	neverExpires := domain.ManagedContext{Name: "local-1a2b3c4d", ClusterName: "local", Owner: owner("local", time.Time{})}
+	legacy := domain.ManagedContext{Name: "old-1a2b3c4d", ClusterName: "old", Owner: owner("", now)}
+
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockConfigProvider.On("GetDefaultKubeconfigPath").Return("/home/user/.kube/config", nil)
+	mockKubeconfigHandler.On("ListContexts", mock.Anything, "/home/user/.kube/config").
+		Return([]domain.ManagedContext{soon, later, neverExpires, legacy, expired}, nil)
+
+	cmd := NewExpiryCommand(mockConfigRepo, mockConfigProvider, mockKubeconfigHandler, testutil.NewClock(now),
+		testutil.Logger())
+
+	// Act
+	result, err := cmd.Execute(context.Background(), ExpiryRequest{Within: 7 * 24 * time.Hour})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "/home/user/.kube/config", result.Kubeconfig)
+	assert.Equal(t, []domain.ManagedContext{expired, soon}, result.Contexts)
+	assert.Equal(t, []domain.ClusterRef{
+		{Server: "1a2b3c4d", ClusterID: "c-m-dev", Name: "dev"},
+		{Server: "1a2b3c4d", ClusterID: "c-m-prod", Name: "prod"},
+	}, result.Clusters())
+}
+
+func TestExpiryCommand_Execute_UsesProfileOutput(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigProvider := mocks.NewMockConfigProvider(t)
+	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{Output: "/work/kubeconfig"}, nil)
+	mockKubeconfigHandler.On("ListContexts", mock.Anything, "/work/kubeconfig").Return(nil, nil)
+
+	cmd := NewExpiryCommand(mockConfigRepo, mockConfigProvider, mockKubeconfigHandler,
+		testutil.NewClock(time.Now()), testutil.Logger())
+
+	// Act
+	result, err := cmd.Execute(context.Background(), ExpiryRequest{Within: time.Hour})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "/work/kubeconfig", result.Kubeconfig)
+	assert.Empty(t, result.Contexts)
+}
+
+func TestExpiryCommand_Execute_ListFails(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigProvider := mocks.NewMockConfigProvider(t)
+	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockKubeconfigHandler.On("ListContexts", mock.Anything, "/tmp/kubeconfig").
+		Return(nil, errors.New("invalid kubeconfig"))
+
+	cmd := NewExpiryCommand(mockConfigRepo, mockConfigProvider, mockKubeconfigHandler,
+		testutil.NewClock(time.Now()), testutil.Logger())
+
+	// Act
+	_, err := cmd.Execute(context.Background(), ExpiryRequest{Kubeconfig: "/tmp/kubeconfig", Within: time.Hour})
+
+	// Assert
+	require.EqualError(t, err, "failed to list contexts: invalid kubeconfig")
+}
@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"errors"
+
+	"cowpoke/internal/domain"
+)
+
+// Explanation describes a failed command to the user.
+type Explanation struct {
+	// Message says what went wrong without the chain of wrapped errors.
+	Message string
+	// Hint suggests what to do next, if there is a known remedy.
+	Hint string
+}
+
+// Explain describes err for the command line, with a next step for common failures. Errors without a
+// known cause are described by their own message.
+func Explain(err error) Explanation {
+	var downloadErr *domain.DownloadError
+	var interruptedErr *domain.InterruptedError
+	switch {
+	case errors.Is(err, domain.ErrManagedServer):
+		return Explanation{
+			Message: err.Error(),
+			Hint:    "Ask the administrator of the system configuration to change it.",
+		}
+	case errors.Is(err, domain.ErrConfigDecryption):
+		return Explanation{
+			Message: err.Error(),
+			Hint: "Set COWPOKE_CONFIG_KEY or COWPOKE_CONFIG_KEY_FILE to the passphrase the configuration was " +
+				"encrypted with, or unlock the keychain holding its key.",
+		}
+	case errors.Is(err, domain.ErrServerVerification):
+		return Explanation{
+			Message: err.Error(),
+			Hint:    "Check the URL and --authtype, or add the server with --no-verify to skip this check.",
+		}
+	case errors.Is(err, domain.ErrIncompatibleVersion):
+		return Explanation{
+			Message: err.Error(),
+			Hint:    "Upgrade the Rancher server, or add it with --skip-version-check to try anyway.",
+		}
+	case errors.As(err, &downloadErr):
+		return Explanation{
+			Message: downloadErr.Error() + ": " + ClusterFailureSummary(downloadErr.Failures),
+			Hint:    "Run 'cowpoke last' to see why each cluster failed.",
+		}
+	case errors.As(err, &interruptedErr):
+		return Explanation{
+			Message: interruptedErr.Error(),
+			Hint: "The kubeconfigs downloaded so far are cached: run 'cowpoke sync --offline' to merge them, " +
+				"or sync again.",
+		}
+	case errors.Is(err, domain.ErrServerUnavailable):
+		return Explanation{
+			Message: "The Rancher server answered with a maintenance page or login portal instead of its API.",
+			Hint:    "Open the server in a browser to check it, or try again later.",
+		}
+	case domain.IsCertificateError(err):
+		return Explanation{
+			Message: "The TLS certificate of the Rancher server, or the client certificate presented to it, was rejected.",
+			Hint:    "Trust the server's CA in your system store, check clientCert and clientKey, or use --insecure.",
+		}
+	}
+
+	switch domain.ClassifyFailure(err) {
+	case domain.FailureAuth:
+		return Explanation{
+			Message: "Rancher rejected the credentials or the cached token.",
+			Hint:    "Check the username and auth type with 'cowpoke list', then run 'cowpoke login <url>'.",
+		}
+	case domain.FailurePermission:
+		return Explanation{
+			Message: "Rancher denied access to a resource the sync needs.",
+			Hint:    "Ask a Rancher administrator for access, or leave the clusters out with --exclude.",
+		}
+	case domain.FailureRateLimit:
+		return Explanation{
+			Message: "Rancher is throttling requests.",
+			Hint:    "Wait a few minutes before syncing again.",
+		}
+	case domain.FailureNetwork:
+		return Explanation{
+			Message: "Could not reach the Rancher server.",
+			Hint:    "Check the server URL and your VPN or tunnel, and run 'cowpoke status' to see which servers fail.",
+		}
+	case domain.FailureParse:
+		return Explanation{
+			Message: "Rancher answered with a response cowpoke could not read.",
+			Hint:    "Run with --debug-http and check the logged responses for a proxy rewriting them.",
+		}
+	default:
+		return Explanation{Message: err.Error()}
+	}
+}
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"cowpoke/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		expectedMessage string
+		expectedHint    string
+	}{
+		{
+			name:            "rejected credentials",
+			err:             fmt.Errorf("sync failed: list clusters failed: %w", domain.ErrUnauthorized),
+			expectedMessage: "Rancher rejected the credentials or the cached token.",
+			expectedHint:    "Check the username and auth type with 'cowpoke list', then run 'cowpoke login <url>'.",
+		},
+		{
+			name: "unreachable server",
+			err: fmt.Errorf("sync failed: %w", domain.WrapNetworkError(
+				&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")})),
+			expectedMessage: "Could not reach the Rancher server.",
+			expectedHint:    "Check the server URL and your VPN or tunnel, and run 'cowpoke status' to see which servers fail.",
+		},
+		{
+			name:            "maintenance page",
+			err:             fmt.Errorf("authentication failed: %w", domain.ErrServerUnavailable),
+			expectedMessage: "The Rancher server answered with a maintenance page or login portal instead of its API.",
+			expectedHint:    "Open the server in a browser to check it, or try again later.",
+		},
+		{
+			name: "failed downloads",
+			err: fmt.Errorf("sync failed: %w", &domain.DownloadError{Total: 3, Failures: []domain.ClusterFailure{
+				{Cluster: "prod", Category: domain.FailurePermission},
+				{Cluster: "staging", Category: domain.FailurePermission},
+			}}),
+			expectedMessage: "failed to download 2 out of 3 kubeconfigs: 2 clusters failed: 2 permission",
+			expectedHint:    "Run 'cowpoke last' to see why each cluster failed.",
+		},
+		{
+			name: "interrupted sync",
+			err: fmt.Errorf("sync failed: %w",
+				&domain.InterruptedError{Downloaded: 12, Total: 40, Err: context.Canceled}),
+			expectedMessage: "sync interrupted after downloading 12 of 40 kubeconfigs; the merged kubeconfig was not written",
+			expectedHint: "The kubeconfigs downloaded so far are cached: run 'cowpoke sync --offline' to merge them, " +
+				"or sync again.",
+		},
+		{
+			name: "encrypted configuration without its key",
+			err: fmt.Errorf("%w /home/user/.config/cowpoke/config.yaml: wrong key?",
+				domain.ErrConfigDecryption),
+			expectedMessage: "failed to decrypt the configuration file /home/user/.config/cowpoke/config.yaml: wrong key?",
+			expectedHint: "Set COWPOKE_CONFIG_KEY or COWPOKE_CONFIG_KEY_FILE to the passphrase the configuration " +
+				"was encrypted with, or unlock the keychain holding its key.",
+		},
+		{
+			name: "server verification failures suggest checking the flags",
+			err: fmt.Errorf("failed to add server: %w: https://rancher.example.com: connection refused",
+				domain.ErrServerVerification),
+			expectedMessage: "failed to add server: server verification failed: https://rancher.example.com: " +
+				"connection refused",
+			expectedHint: "Check the URL and --authtype, or add the server with --no-verify to skip this check.",
+		},
+		{
+			name:            "unknown errors keep their message",
+			err:             errors.New("either --url or --id must be specified"),
+			expectedMessage: "either --url or --id must be specified",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			explanation := Explain(tt.err)
+
+			// Assert
+			assert.Equal(t, tt.expectedMessage, explanation.Message)
+			assert.Equal(t, tt.expectedHint, explanation.Hint)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"cowpoke/internal/domain"
+)
+
+// ClusterFailureSummary counts failed kubeconfig downloads by cause, such as
+// "3 clusters failed: 2 permission, 1 network". It is empty if nothing failed.
+func ClusterFailureSummary(failures []domain.ClusterFailure) string {
+	categories := make([]domain.FailureCategory, 0, len(failures))
+	for _, failure := range failures {
+		categories = append(categories, failure.Category)
+	}
+	return failureSummary("cluster", categories)
+}
+
+// ServerFailureSummary counts the servers of a report that failed by cause, such as "1 server failed: 1 auth".
+// It is empty if no server failed.
+func ServerFailureSummary(servers []ServerReport) string {
+	var categories []domain.FailureCategory
+	for _, server := range servers {
+		if server.Error != "" {
+			categories = append(categories, server.Category)
+		}
+	}
+	return failureSummary("server", categories)
+}
+
+// failureSummary counts categories, most frequent first.
+func failureSummary(noun string, categories []domain.FailureCategory) string {
+	if len(categories) == 0 {
+		return ""
+	}
+
+	counts := make(map[domain.FailureCategory]int)
+	for _, category := range categories {
+		counts[cmp.Or(category, domain.FailureOther)]++
+	}
+	ordered := slices.SortedFunc(maps.Keys(counts), func(a, b domain.FailureCategory) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), cmp.Compare(a, b))
+	})
+
+	parts := make([]string, 0, len(ordered))
+	for _, category := range ordered {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[category], category))
+	}
+	if len(categories) != 1 {
+		noun += "s"
+	}
+	return fmt.Sprintf("%d %s failed: %s", len(categories), noun, strings.Join(parts, ", "))
+}
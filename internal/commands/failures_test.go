@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"testing"
+
+	"cowpoke/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterFailureSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures []domain.ClusterFailure
+		expected string
+	}{
+		{
+			name:     "no failures",
+			expected: "",
+		},
+		{
+			name:     "single failure",
+			failures: []domain.ClusterFailure{{Cluster: "prod", Category: domain.FailureNetwork}},
+			expected: "1 cluster failed: 1 network",
+		},
+		{
+			name: "most frequent category first",
+			failures: []domain.ClusterFailure{
+				{Cluster: "prod", Category: domain.FailureNetwork},
+				{Cluster: "staging", Category: domain.FailurePermission},
+				{Cluster: "dev", Category: domain.FailurePermission},
+			},
+			expected: "3 clusters failed: 2 permission, 1 network",
+		},
+		{
+			name: "ties ordered by name and missing categories counted as other",
+			failures: []domain.ClusterFailure{
+				{Cluster: "prod", Category: domain.FailureRateLimit},
+				{Cluster: "staging"},
+			},
+			expected: "2 clusters failed: 1 other, 1 rate-limit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			summary := ClusterFailureSummary(tt.failures)
+
+			// Assert
+			assert.Equal(t, tt.expected, summary)
+		})
+	}
+}
+
+func TestServerFailureSummary(t *testing.T) {
+	// Arrange
+	servers := []ServerReport{
+		{ServerURL: "https://rancher1.example.com"},
+		{ServerURL: "https://rancher2.example.com", Error: "list clusters failed: unauthorized",
+			Category: domain.FailureAuth},
+	}
+
+	// Act
+	summary := ServerFailureSummary(servers)
+
+	// Assert
+	assert.Equal(t, "1 server failed: 1 auth", summary)
+}
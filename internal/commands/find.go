@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"cowpoke/internal/domain"
+)
+
+// FindCommand handles searching the contexts cowpoke manages.
+type FindCommand struct {
+	kubeconfigHandler domain.KubeconfigHandler
+	configProvider    domain.ConfigProvider
+	logger            *slog.Logger
+}
+
+// NewFindCommand creates a new find command.
+func NewFindCommand(
+	kubeconfigHandler domain.KubeconfigHandler,
+	configProvider domain.ConfigProvider,
+	logger *slog.Logger,
+) *FindCommand {
+	return &FindCommand{
+		kubeconfigHandler: kubeconfigHandler,
+		configProvider:    configProvider,
+		logger:            logger,
+	}
+}
+
+// FindRequest contains the parameters for the find command.
+type FindRequest struct {
+	// Pattern is matched against context and cluster names, exactly, as a prefix or substring, or as an
+	// abbreviation.
+	Pattern string
+	// Kubeconfig is the merged kubeconfig to search; defaults to ~/.kube/config.
+	Kubeconfig string
+}
+
+// FindResult contains the contexts matching a pattern, closest matches first.
+type FindResult struct {
+	Matches    []domain.ManagedContext
+	Kubeconfig string
+}
+
+// Execute runs the find command.
+func (c *FindCommand) Execute(ctx context.Context, req FindRequest) (*FindResult, error) {
+	if req.Pattern == "" {
+		return nil, errors.New("a search pattern must be specified")
+	}
+
+	kubeconfigPath, err := resolveKubeconfig(c.configProvider, req.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	contexts, err := c.kubeconfigHandler.ListContexts(ctx, kubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contexts: %w", err)
+	}
+
+	ranks := make(map[string]matchRank, len(contexts))
+	var matches []domain.ManagedContext
+	for _, kubeContext := range contexts {
+		if rank := fuzzyRank(req.Pattern, kubeContext.Name, kubeContext.ClusterName); rank != noMatch {
+			ranks[kubeContext.Name] = rank
+			matches = append(matches, kubeContext)
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b domain.ManagedContext) int {
+		return cmp.Compare(ranks[a.Name], ranks[b.Name])
+	})
+
+	c.logger.DebugContext(ctx, "Searched contexts",
+		"pattern", req.Pattern,
+		"contexts", len(contexts),
+		"matches", len(matches))
+
+	return &FindResult{Matches: matches, Kubeconfig: kubeconfigPath}, nil
+}
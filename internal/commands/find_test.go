@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindCommand_Execute(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		want    []string
+	}{
+		{
+			name:    "cluster name on several servers",
+			pattern: "prod-us",
+			want:    []string{"prod-us-1a2b3c4d", "prod-us-9f8e7d6c"},
+		},
+		{
+			name:    "closest matches first",
+			pattern: "s",
+			want:    []string{"staging-9f8e7d6c", "prod-us-1a2b3c4d", "prod-us-9f8e7d6c"},
+		},
+		{
+			name:    "abbreviation",
+			pattern: "stg",
+			want:    []string{"staging-9f8e7d6c"},
+		},
+		{
+			name:    "no match",
+			pattern: "qa",
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+			mockConfigProvider := mocks.NewMockConfigProvider(t)
+
+			mockConfigProvider.On("GetDefaultKubeconfigPath").Return("/home/user/.kube/config", nil)
+			mockKubeconfigHandler.On("ListContexts", mock.Anything, "/home/user/.kube/config").
+				Return(managedContexts(), nil)
+
+			cmd := NewFindCommand(mockKubeconfigHandler, mockConfigProvider, testutil.Logger())
+
+			// Act
+			result, err := cmd.Execute(context.Background(), FindRequest{Pattern: tt.pattern})
+
+			// Assert
+			require.NoError(t, err)
+			var names []string
+			for _, match := range result.Matches {
+				names = append(names, match.Name)
+			}
+			assert.Equal(t, tt.want, names)
+			assert.Equal(t, "/home/user/.kube/config", result.Kubeconfig)
+		})
+	}
+}
+
+func TestFindCommand_Execute_RequiresPattern(t *testing.T) {
+	cmd := NewFindCommand(mocks.NewMockKubeconfigHandler(t), mocks.NewMockConfigProvider(t), testutil.Logger())
+
+	result, err := cmd.Execute(context.Background(), FindRequest{})
+
+	require.ErrorContains(t, err, "pattern must be specified")
+	assert.Nil(t, result)
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"strings"
+)
+
+// matchRank orders how closely a query matches a name; lower ranks are closer matches.
+type matchRank int
+
+const (
+	matchExact matchRank = iota
+	matchPrefix
+	matchSubstring
+	matchSubsequence
+	noMatch
+)
+
+// fuzzyRank returns the closest match of query against any of names, ignoring case.
+func fuzzyRank(query string, names ...string) matchRank {
+	query = strings.ToLower(query)
+	best := noMatch
+	for _, name := range names {
+		name = strings.ToLower(name)
+		var rank matchRank
+		switch {
+		case name == query:
+			rank = matchExact
+		case strings.HasPrefix(name, query):
+			rank = matchPrefix
+		case strings.Contains(name, query):
+			rank = matchSubstring
+		case isSubsequence(query, name):
+			rank = matchSubsequence
+		default:
+			rank = noMatch
+		}
+		best = min(best, rank)
+	}
+	return best
+}
+
+// isSubsequence reports whether the characters of query appear in name in order.
+func isSubsequence(query, name string) bool {
+	remaining := []rune(query)
+	for _, r := range name {
+		if len(remaining) == 0 {
+			break
+		}
+		if r == remaining[0] {
+			remaining = remaining[1:]
+		}
+	}
+	return len(remaining) == 0
+}
@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuzzyRank(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		names []string
+		want  matchRank
+	}{
+		{name: "exact ignores case", query: "Prod", names: []string{"prod"}, want: matchExact},
+		{name: "prefix", query: "pro", names: []string{"prod-eu"}, want: matchPrefix},
+		{name: "substring", query: "eu", names: []string{"prod-eu"}, want: matchSubstring},
+		{name: "subsequence", query: "pdeu", names: []string{"prod-eu"}, want: matchSubsequence},
+		{name: "best of several names", query: "prod", names: []string{"prod-1a2b3c4d", "prod"}, want: matchExact},
+		{name: "no match", query: "staging", names: []string{"prod-eu"}, want: noMatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, fuzzyRank(tt.query, tt.names...))
+		})
+	}
+}
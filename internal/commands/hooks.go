@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"cowpoke/internal/domain"
+)
+
+// Stages at which hooks run, as passed to them in COWPOKE_HOOK.
+const (
+	preSyncStage  = "preSync"
+	postSyncStage = "postSync"
+)
+
+// runPreSyncHooks runs the pre-sync hooks, returning an error if one fails under the fail policy.
+func (c *SyncCommand) runPreSyncHooks(ctx context.Context) error {
+	return c.runHooks(ctx, preSyncStage, c.hooks.PreSync, domain.HookFail, nil, nil)
+}
+
+// runPostSyncHooks runs the post-sync hooks with the sync's report as JSON on stdin, returning an error if
+// one fails under the fail policy.
+func (c *SyncCommand) runPostSyncHooks(ctx context.Context, report *SyncReport) error {
+	if len(c.hooks.PostSync) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode sync report for hooks: %w", err)
+	}
+	status := "succeeded"
+	if report.Error != "" {
+		status = "failed"
+	}
+	return c.runHooks(ctx, postSyncStage, c.hooks.PostSync, domain.HookWarn,
+		[]string{"COWPOKE_SYNC_STATUS=" + status}, payload)
+}
+
+// runHooks runs hooks in order, each within its timeout, applying each one's failure policy, or fallback if
+// it sets none.
+func (c *SyncCommand) runHooks(
+	ctx context.Context,
+	stage string,
+	hooks []domain.Hook,
+	fallback string,
+	env []string,
+	stdin []byte,
+) error {
+	if c.hookRunner == nil {
+		return nil
+	}
+	env = append([]string{"COWPOKE_HOOK=" + stage}, env...)
+	for _, hook := range hooks {
+		hookCtx, cancel := context.WithTimeout(ctx, hook.Timeout())
+		err := c.hookRunner.RunHook(hookCtx, hook.Command, env, stdin)
+		cancel()
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s", hook.Timeout())
+		}
+		if err == nil {
+			c.logger.DebugContext(ctx, "Ran hook", "stage", stage, "command", hook.Command)
+			continue
+		}
+
+		switch hook.Policy(fallback) {
+		case domain.HookIgnore:
+			c.logger.DebugContext(ctx, "Ignoring failed hook", "stage", stage, "command", hook.Command, "error", err)
+		case domain.HookWarn:
+			c.logger.WarnContext(ctx, "Hook failed", "stage", stage, "command", hook.Command, "error", err)
+		default:
+			return fmt.Errorf("%s hook %q failed: %w", stage, hook.Command, err)
+		}
+	}
+	return nil
+}
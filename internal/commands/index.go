@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"cowpoke/internal/domain"
+)
+
+// contextIndexState is the state document indexing the contexts cowpoke wrote into the merged kubeconfig.
+const contextIndexState = "contexts"
+
+// indexContexts replaces the context index with the cowpoke contexts of the kubeconfig at path and the
+// clusters last discovered on each server.
+func indexContexts(
+	ctx context.Context,
+	store domain.StateStore,
+	kubeconfigHandler domain.KubeconfigHandler,
+	path string,
+	clusters map[string][]string,
+) error {
+	contexts, err := kubeconfigHandler.ListContexts(ctx, path)
+	if err != nil {
+		return fmt.Errorf("failed to read contexts of %s: %w", path, err)
+	}
+
+	index := domain.ContextIndex{
+		Kubeconfig: path,
+		UpdatedAt:  time.Now(),
+		Contexts:   make(map[string]domain.IndexedContext, len(contexts)),
+		Clusters:   clusters,
+	}
+	for _, kubeContext := range contexts {
+		index.Contexts[kubeContext.Name] = domain.IndexedContext{
+			ServerURL:   kubeContext.Owner.ServerURL,
+			ServerID:    kubeContext.Owner.ServerID,
+			ClusterID:   kubeContext.Owner.ClusterID,
+			ClusterName: kubeContext.ClusterName,
+			SyncedAt:    kubeContext.Owner.SyncedAt,
+		}
+	}
+	return store.Save(ctx, contextIndexState, index)
+}
+
+// loadContextIndex returns the context index, or nil if none has been recorded.
+func loadContextIndex(ctx context.Context, store domain.StateStore) (*domain.ContextIndex, error) {
+	var index domain.ContextIndex
+	found, err := store.Load(ctx, contextIndexState, &index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load context index: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &index, nil
+}
+
+// discoveredClusters returns the clusters recorded in index, updated with those of the servers whose
+// discovery succeeded.
+func discoveredClusters(index *domain.ContextIndex, servers []domain.ServerSyncResult) map[string][]string {
+	clusters := make(map[string][]string)
+	if index != nil {
+		maps.Copy(clusters, index.Clusters)
+	}
+	for _, serverResult := range servers {
+		if serverResult.Error != nil {
+			continue
+		}
+		ids := make([]string, 0, len(serverResult.Clusters))
+		for _, cluster := range serverResult.Clusters {
+			ids = append(ids, cluster.ID)
+		}
+		slices.Sort(ids)
+		clusters[serverResult.Server.ID()] = ids
+	}
+	return clusters
+}
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+
+	"cowpoke/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscoveredClusters(t *testing.T) {
+	prod := domain.ConfigServer{URL: "https://prod.example.com"}
+	lab := domain.ConfigServer{URL: "https://lab.example.com"}
+	index := &domain.ContextIndex{Clusters: map[string][]string{
+		prod.ID(): {"c-old"},
+		lab.ID():  {"c-lab"},
+	}}
+
+	tests := []struct {
+		name    string
+		index   *domain.ContextIndex
+		servers []domain.ServerSyncResult
+		want    map[string][]string
+	}{
+		{
+			name: "no index",
+			servers: []domain.ServerSyncResult{
+				{Server: prod, Clusters: []domain.Cluster{{ID: "c-2"}, {ID: "c-1"}}},
+			},
+			want: map[string][]string{prod.ID(): {"c-1", "c-2"}},
+		},
+		{
+			name:  "discovered servers replace their clusters",
+			index: index,
+			servers: []domain.ServerSyncResult{
+				{Server: prod, Clusters: []domain.Cluster{{ID: "c-new"}}},
+			},
+			want: map[string][]string{prod.ID(): {"c-new"}, lab.ID(): {"c-lab"}},
+		},
+		{
+			name:  "failed discoveries keep the recorded clusters",
+			index: index,
+			servers: []domain.ServerSyncResult{
+				{Server: prod, Error: errors.New("unauthorized")},
+			},
+			want: map[string][]string{prod.ID(): {"c-old"}, lab.ID(): {"c-lab"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			clusters := discoveredClusters(tt.index, tt.servers)
+
+			// Assert
+			assert.Equal(t, tt.want, clusters)
+		})
+	}
+}
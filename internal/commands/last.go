@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"cowpoke/internal/domain"
+)
+
+// lastSyncState is the state document holding the report of the most recent sync.
+const lastSyncState = "last-sync"
+
+// LastCommand handles showing the report of the most recent sync.
+type LastCommand struct {
+	stateStore domain.StateStore
+	logger     *slog.Logger
+}
+
+// NewLastCommand creates a new last command.
+func NewLastCommand(stateStore domain.StateStore, logger *slog.Logger) *LastCommand {
+	return &LastCommand{
+		stateStore: stateStore,
+		logger:     logger,
+	}
+}
+
+// Execute returns the report of the most recent sync, or nil if no sync has been recorded.
+func (c *LastCommand) Execute(ctx context.Context) (*SyncReport, error) {
+	var report SyncReport
+	found, err := c.stateStore.Load(ctx, lastSyncState, &report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load last sync report: %w", err)
+	}
+	if !found {
+		c.logger.DebugContext(ctx, "No sync report recorded")
+		return nil, nil
+	}
+
+	c.logger.DebugContext(ctx, "Loaded last sync report", "started_at", report.StartedAt)
+	return &report, nil
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastCommand_Execute(t *testing.T) {
+	// Arrange
+	mockStore := mocks.NewMockStateStore(t)
+	startedAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	mockStore.On("Load", mock.Anything, "last-sync", mock.AnythingOfType("*commands.SyncReport")).
+		Run(func(args mock.Arguments) {
+			report := args.Get(2).(*SyncReport)
+			report.StartedAt = startedAt
+			report.Output = "/home/user/.kube/config"
+			report.Servers = []ServerReport{{ServerURL: "https://rancher.example.com", Clusters: 3}}
+		}).
+		Return(true, nil)
+
+	cmd := NewLastCommand(mockStore, testutil.Logger())
+
+	// Act
+	report, err := cmd.Execute(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	assert.Equal(t, startedAt, report.StartedAt)
+	assert.Equal(t, "/home/user/.kube/config", report.Output)
+	assert.Len(t, report.Servers, 1)
+}
+
+func TestLastCommand_Execute_NoReport(t *testing.T) {
+	// Arrange
+	mockStore := mocks.NewMockStateStore(t)
+	mockStore.On("Load", mock.Anything, "last-sync", mock.Anything).Return(false, nil)
+
+	cmd := NewLastCommand(mockStore, testutil.Logger())
+
+	// Act
+	report, err := cmd.Execute(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Nil(t, report)
+}
+
+func TestLastCommand_Execute_LoadFails(t *testing.T) {
+	// Arrange
+	mockStore := mocks.NewMockStateStore(t)
+	mockStore.On("Load", mock.Anything, "last-sync", mock.Anything).Return(false, errors.New("corrupt"))
+
+	cmd := NewLastCommand(mockStore, testutil.Logger())
+
+	// Act
+	_, err := cmd.Execute(context.Background())
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load last sync report")
+}
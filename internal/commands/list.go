@@ -4,22 +4,47 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"cowpoke/internal/domain"
 )
 
 // ListCommand handles listing configured Rancher servers.
 type ListCommand struct {
-	configRepo domain.ConfigRepository
-	logger     *slog.Logger
+	configRepo    domain.ConfigRepository
+	healthTracker domain.HealthTracker
+	stateStore    domain.StateStore
+	tokenCache    domain.TokenCache
+	logger        *slog.Logger
+}
+
+// ListOption is a functional option for wiring optional ListCommand dependencies.
+type ListOption func(*ListCommand)
+
+// WithSyncState reports each server's last successful sync, the clusters found by the last sync and
+// whether a valid token is cached, as recorded by previous syncs.
+func WithSyncState(
+	healthTracker domain.HealthTracker,
+	stateStore domain.StateStore,
+	tokenCache domain.TokenCache,
+) ListOption {
+	return func(c *ListCommand) {
+		c.healthTracker = healthTracker
+		c.stateStore = stateStore
+		c.tokenCache = tokenCache
+	}
 }
 
 // NewListCommand creates a new list command.
-func NewListCommand(configRepo domain.ConfigRepository, logger *slog.Logger) *ListCommand {
-	return &ListCommand{
+func NewListCommand(configRepo domain.ConfigRepository, logger *slog.Logger, opts ...ListOption) *ListCommand {
+	c := &ListCommand{
 		configRepo: configRepo,
 		logger:     logger,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // ListRequest contains the parameters for the list command.
@@ -31,7 +56,24 @@ type ListRequest struct {
 // ListResult contains the result of the list command.
 type ListResult struct {
 	Servers []domain.ConfigServer
-	Count   int
+	// States holds the recorded sync state of each server, in the order of Servers. It is only filled
+	// when the command was created WithSyncState.
+	States []ServerSyncState
+	Count  int
+}
+
+// ServerSyncState is what previous syncs recorded about a server.
+type ServerSyncState struct {
+	// LastSuccess is when the server last synced successfully, zero if it never did.
+	LastSuccess time.Time
+	// LastError is the error of the latest sync if it failed.
+	LastError string
+	// Clusters is the number of clusters the most recent sync found on the server, as of ClustersAt.
+	// ClustersAt is zero if that sync did not include the server or failed to list its clusters.
+	Clusters   int
+	ClustersAt time.Time
+	// TokenExpiresAt is when the cached token expires, zero if no valid token is cached.
+	TokenExpiresAt time.Time
 }
 
 // Execute runs the list command.
@@ -47,7 +89,51 @@ func (c *ListCommand) Execute(ctx context.Context, _ ListRequest) (*ListResult,
 		Servers: servers,
 		Count:   len(servers),
 	}
+	if c.healthTracker != nil {
+		if result.States, err = c.syncStates(ctx, servers); err != nil {
+			return nil, err
+		}
+	}
 
 	c.logger.InfoContext(ctx, "Retrieved server list", "count", len(servers))
 	return result, nil
 }
+
+// syncStates collects the recorded sync state of servers from their health, the last sync report and
+// the token cache.
+func (c *ListCommand) syncStates(ctx context.Context, servers []domain.ConfigServer) ([]ServerSyncState, error) {
+	var report SyncReport
+	found, err := c.stateStore.Load(ctx, lastSyncState, &report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load last sync report: %w", err)
+	}
+
+	states := make([]ServerSyncState, len(servers))
+	for i, server := range servers {
+		health, healthErr := c.healthTracker.Get(ctx, server)
+		if healthErr != nil {
+			return nil, fmt.Errorf("failed to get health for %s: %w", server.URL, healthErr)
+		}
+		states[i].LastSuccess = health.LastSuccess
+		if health.ConsecutiveFailures > 0 {
+			states[i].LastError = health.LastError
+		}
+
+		if found {
+			for _, serverReport := range report.Servers {
+				if serverReport.ServerID == server.ID() && serverReport.Error == "" {
+					states[i].Clusters = serverReport.Clusters
+					states[i].ClustersAt = report.StartedAt
+				}
+			}
+		}
+
+		token, tokenErr := c.tokenCache.Get(ctx, server)
+		if tokenErr != nil {
+			c.logger.WarnContext(ctx, "Could not read cached token", "url", server.URL, "error", tokenErr)
+		} else if token != nil {
+			states[i].TokenExpiresAt = token.ExpiresAt()
+		}
+	}
+	return states, nil
+}
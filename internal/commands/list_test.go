@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"cowpoke/internal/domain"
 	"cowpoke/internal/mocks"
@@ -262,3 +263,93 @@ func TestNewListCommand(t *testing.T) {
 	assert.NotNil(t, cmd)
 	assert.Equal(t, mockConfigRepo, cmd.configRepo)
 }
+
+func TestListCommand_Execute_WithSyncState(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockHealth := mocks.NewMockHealthTracker(t)
+	mockStore := mocks.NewMockStateStore(t)
+	mockTokens := mocks.NewMockTokenCache(t)
+	mockToken := mocks.NewMockAuthToken(t)
+
+	synced := domain.ConfigServer{URL: "https://rancher1.example.com", Username: "admin", AuthType: "local"}
+	failing := domain.ConfigServer{URL: "https://rancher2.example.com", Username: "admin", AuthType: "local"}
+	lastSuccess := time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC)
+	startedAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC)
+
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{synced, failing}, nil)
+	mockStore.On("Load", mock.Anything, "last-sync", mock.AnythingOfType("*commands.SyncReport")).
+		Run(func(args mock.Arguments) {
+			report := args.Get(2).(*SyncReport)
+			report.StartedAt = startedAt
+			report.Servers = []ServerReport{
+				{ServerURL: synced.URL, ServerID: synced.ID(), Clusters: 7},
+				{ServerURL: failing.URL, ServerID: failing.ID(), Error: "connection refused"},
+			}
+		}).
+		Return(true, nil)
+	mockHealth.On("Get", mock.Anything, synced).Return(domain.ServerHealth{LastSuccess: startedAt}, nil)
+	mockHealth.On("Get", mock.Anything, failing).Return(domain.ServerHealth{
+		ConsecutiveFailures: 2, LastError: "connection refused", LastSuccess: lastSuccess,
+	}, nil)
+	mockToken.On("ExpiresAt").Return(expiresAt)
+	mockTokens.On("Get", mock.Anything, synced).Return(mockToken, nil)
+	mockTokens.On("Get", mock.Anything, failing).Return(nil, nil)
+
+	cmd := NewListCommand(mockConfigRepo, testutil.Logger(), WithSyncState(mockHealth, mockStore, mockTokens))
+
+	// Act
+	result, err := cmd.Execute(context.Background(), ListRequest{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []ServerSyncState{
+		{LastSuccess: startedAt, Clusters: 7, ClustersAt: startedAt, TokenExpiresAt: expiresAt},
+		{LastSuccess: lastSuccess, LastError: "connection refused"},
+	}, result.States)
+}
+
+func TestListCommand_Execute_WithSyncState_NeverSynced(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockHealth := mocks.NewMockHealthTracker(t)
+	mockStore := mocks.NewMockStateStore(t)
+	mockTokens := mocks.NewMockTokenCache(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockStore.On("Load", mock.Anything, "last-sync", mock.Anything).Return(false, nil)
+	mockHealth.On("Get", mock.Anything, server).Return(domain.ServerHealth{}, nil)
+	mockTokens.On("Get", mock.Anything, server).Return(nil, errors.New("keychain locked"))
+
+	cmd := NewListCommand(mockConfigRepo, testutil.Logger(), WithSyncState(mockHealth, mockStore, mockTokens))
+
+	// Act
+	result, err := cmd.Execute(context.Background(), ListRequest{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []ServerSyncState{{}}, result.States)
+}
+
+func TestListCommand_Execute_WithSyncState_HealthError(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockHealth := mocks.NewMockHealthTracker(t)
+	mockStore := mocks.NewMockStateStore(t)
+	mockTokens := mocks.NewMockTokenCache(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockStore.On("Load", mock.Anything, "last-sync", mock.Anything).Return(false, nil)
+	mockHealth.On("Get", mock.Anything, server).Return(domain.ServerHealth{}, errors.New("corrupt state"))
+
+	cmd := NewListCommand(mockConfigRepo, testutil.Logger(), WithSyncState(mockHealth, mockStore, mockTokens))
+
+	// Act
+	_, err := cmd.Execute(context.Background(), ListRequest{})
+
+	// Assert
+	require.EqualError(t, err, "failed to get health for https://rancher.example.com: corrupt state")
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"cowpoke/internal/domain"
+)
+
+// LoginCommand authenticates a single server and caches its token, so later syncs need no password.
+type LoginCommand struct {
+	configRepo     domain.ConfigRepository
+	passwordReader domain.PasswordReader
+	rancherClient  domain.RancherClient
+	tokenCache     domain.TokenCache
+	logger         *slog.Logger
+}
+
+// NewLoginCommand creates a new login command.
+func NewLoginCommand(
+	configRepo domain.ConfigRepository,
+	passwordReader domain.PasswordReader,
+	rancherClient domain.RancherClient,
+	tokenCache domain.TokenCache,
+	logger *slog.Logger,
+) *LoginCommand {
+	return &LoginCommand{
+		configRepo:     configRepo,
+		passwordReader: passwordReader,
+		rancherClient:  rancherClient,
+		tokenCache:     tokenCache,
+		logger:         logger,
+	}
+}
+
+// LoginRequest contains the parameters for the login command. The server is given by URL or ID.
+type LoginRequest struct {
+	Server string
+}
+
+// LoginResult describes the token obtained by a login.
+type LoginResult struct {
+	ServerURL string
+	ExpiresAt time.Time
+}
+
+// Execute authenticates the requested server and caches the token.
+func (c *LoginCommand) Execute(ctx context.Context, req LoginRequest) (*LoginResult, error) {
+	if req.Server == "" {
+		return nil, errors.New("server must be specified")
+	}
+
+	servers, err := c.configRepo.GetServers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get servers: %w", err)
+	}
+	server, err := domain.FindServer(servers, req.Server)
+	if err != nil {
+		return nil, err
+	}
+	if server == nil {
+		return nil, fmt.Errorf("server %s is not configured", req.Server)
+	}
+	if server.AuthType == domain.AuthTypeToken {
+		return nil, fmt.Errorf("%s authenticates with an API token and needs no login "+
+			"(run 'cowpoke convert-to-token --authtype <type>' to replace the token)", server.URL)
+	}
+
+	password, err := c.passwordReader.ReadPassword(ctx, fmt.Sprintf("Password for %s: ", server.URL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read password for %s: %w", server.URL, err)
+	}
+
+	c.logger.InfoContext(ctx, "Logging in", "url", server.URL, "username", server.Username)
+	token, err := c.rancherClient.Authenticate(ctx, *server, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate with %s: %w", server.URL, err)
+	}
+
+	if putErr := c.tokenCache.Put(ctx, *server, token); putErr != nil {
+		return nil, fmt.Errorf("failed to cache token: %w", putErr)
+	}
+
+	c.logger.InfoContext(ctx, "Logged in", "url", server.URL, "expires_at", token.ExpiresAt())
+	return &LoginResult{ServerURL: server.URL, ExpiresAt: token.ExpiresAt()}, nil
+}
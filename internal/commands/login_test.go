@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginCommand_Execute(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockRancherClient := mocks.NewMockRancherClient(t)
+	mockTokenCache := mocks.NewMockTokenCache(t)
+	mockToken := mocks.NewMockAuthToken(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	expiresAt := time.Date(2025, 1, 2, 4, 0, 0, 0, time.UTC)
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, "Password for https://rancher.example.com: ").
+		Return("password123", nil)
+	mockRancherClient.On("Authenticate", mock.Anything, server, "password123").Return(mockToken, nil)
+	mockTokenCache.On("Put", mock.Anything, server, mockToken).Return(nil)
+	mockToken.On("ExpiresAt").Return(expiresAt)
+
+	cmd := NewLoginCommand(mockConfigRepo, mockPasswordReader, mockRancherClient, mockTokenCache, testutil.Logger())
+
+	// Act
+	result, err := cmd.Execute(context.Background(), LoginRequest{Server: server.ID()})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, &LoginResult{ServerURL: server.URL, ExpiresAt: expiresAt}, result)
+}
+
+func TestLoginCommand_Execute_UnknownServer(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{}, nil)
+
+	cmd := NewLoginCommand(mockConfigRepo, mocks.NewMockPasswordReader(t), mocks.NewMockRancherClient(t),
+		mocks.NewMockTokenCache(t), testutil.Logger())
+
+	// Act
+	_, err := cmd.Execute(context.Background(), LoginRequest{Server: "https://unknown.example.com"})
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not configured")
+}
+
+func TestLoginCommand_Execute_ShortID(t *testing.T) {
+	// Rancher174 and rancher316 have IDs starting with 746e
+	servers := []domain.ConfigServer{
+		{URL: "https://rancher174.example.com", Username: "admin", AuthType: "local"},
+		{URL: "https://rancher316.example.com", Username: "admin", AuthType: "local"},
+	}
+
+	tests := []struct {
+		name    string
+		ref     string
+		wantURL string
+		wantErr string
+	}{
+		{name: "unique prefix", ref: servers[1].ID()[:6], wantURL: servers[1].URL},
+		{name: "ambiguous prefix", ref: "746e", wantErr: "server ID 746e is ambiguous"},
+		{name: "prefix too short", ref: "746", wantErr: "is not configured"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockConfigRepo := mocks.NewMockConfigRepository(t)
+			mockPasswordReader := mocks.NewMockPasswordReader(t)
+			mockRancherClient := mocks.NewMockRancherClient(t)
+			mockTokenCache := mocks.NewMockTokenCache(t)
+			mockToken := mocks.NewMockAuthToken(t)
+
+			mockConfigRepo.On("GetServers", mock.Anything).Return(servers, nil)
+			if tt.wantErr == "" {
+				mockPasswordReader.On("ReadPassword", mock.Anything, mock.Anything).Return("password123", nil)
+				mockRancherClient.On("Authenticate", mock.Anything, servers[1], "password123").Return(mockToken, nil)
+				mockTokenCache.On("Put", mock.Anything, servers[1], mockToken).Return(nil)
+				mockToken.On("ExpiresAt").Return(time.Time{})
+			}
+
+			cmd := NewLoginCommand(mockConfigRepo, mockPasswordReader, mockRancherClient, mockTokenCache,
+				testutil.Logger())
+
+			// Act
+			result, err := cmd.Execute(context.Background(), LoginRequest{Server: tt.ref})
+
+			// Assert
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantURL, result.ServerURL)
+		})
+	}
+}
+
+func TestLoginCommand_Execute_AuthenticationFails(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockRancherClient := mocks.NewMockRancherClient(t)
+	mockTokenCache := mocks.NewMockTokenCache(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, mock.Anything).Return("wrong", nil)
+	mockRancherClient.On("Authenticate", mock.Anything, server, "wrong").Return(nil, errors.New("401"))
+
+	cmd := NewLoginCommand(mockConfigRepo, mockPasswordReader, mockRancherClient, mockTokenCache, testutil.Logger())
+
+	// Act
+	_, err := cmd.Execute(context.Background(), LoginRequest{Server: server.URL})
+
+	// Assert
+	require.Error(t, err)
+	mockTokenCache.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything)
+}
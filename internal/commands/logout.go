@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"cowpoke/internal/domain"
+)
+
+// LogoutCommand discards cached tokens, optionally revoking them in Rancher.
+type LogoutCommand struct {
+	configRepo    domain.ConfigRepository
+	tokenCache    domain.TokenCache
+	rancherClient domain.RancherClient
+	logger        *slog.Logger
+}
+
+// NewLogoutCommand creates a new logout command.
+func NewLogoutCommand(
+	configRepo domain.ConfigRepository,
+	tokenCache domain.TokenCache,
+	rancherClient domain.RancherClient,
+	logger *slog.Logger,
+) *LogoutCommand {
+	return &LogoutCommand{
+		configRepo:    configRepo,
+		tokenCache:    tokenCache,
+		rancherClient: rancherClient,
+		logger:        logger,
+	}
+}
+
+// LogoutRequest contains the parameters for the logout command.
+type LogoutRequest struct {
+	// Server is the URL or ID of the server to log out of.
+	Server string
+	// All logs out of every server in every profile.
+	All bool
+	// Revoke deletes the cached tokens in Rancher before discarding them.
+	Revoke bool
+}
+
+// Execute discards the cached tokens of the requested servers and returns their URLs. With All, every
+// server is attempted even if some fail.
+func (c *LogoutCommand) Execute(ctx context.Context, req LogoutRequest) ([]string, error) {
+	servers, err := c.servers(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var loggedOut []string
+	var errs []error
+	for _, server := range servers {
+		if logoutErr := c.logout(ctx, server, req.Revoke); logoutErr != nil {
+			errs = append(errs, logoutErr)
+			continue
+		}
+		loggedOut = append(loggedOut, server.URL)
+	}
+	return loggedOut, errors.Join(errs...)
+}
+
+// servers returns the servers a request applies to.
+func (c *LogoutCommand) servers(ctx context.Context, req LogoutRequest) ([]domain.ConfigServer, error) {
+	switch {
+	case req.All && req.Server != "":
+		return nil, errors.New("specify either a server or all servers, not both")
+	case req.All:
+		servers, err := c.configRepo.GetAllServers(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get servers: %w", err)
+		}
+		return servers, nil
+	case req.Server != "":
+		servers, err := c.configRepo.GetServers(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get servers: %w", err)
+		}
+		server, findErr := domain.FindServer(servers, req.Server)
+		if findErr != nil {
+			return nil, findErr
+		}
+		if server == nil {
+			return nil, fmt.Errorf("server %s is not configured", req.Server)
+		}
+		return []domain.ConfigServer{*server}, nil
+	default:
+		return nil, errors.New("either a server or all servers must be specified")
+	}
+}
+
+// logout discards the cached token for server, revoking it first if requested.
+func (c *LogoutCommand) logout(ctx context.Context, server domain.ConfigServer, revoke bool) error {
+	if revoke {
+		return revokeCachedToken(ctx, c.tokenCache, c.rancherClient, server, c.logger)
+	}
+	if err := c.tokenCache.Delete(ctx, server); err != nil {
+		return fmt.Errorf("failed to discard cached token for %s: %w", server.URL, err)
+	}
+	c.logger.InfoContext(ctx, "Discarded cached token", "url", server.URL)
+	return nil
+}
@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogoutCommand_Execute_Server(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockTokenCache := mocks.NewMockTokenCache(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	other := domain.ConfigServer{URL: "https://other.example.com", Username: "admin", AuthType: "local"}
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{other, server}, nil)
+	mockTokenCache.On("Delete", mock.Anything, server).Return(nil)
+
+	cmd := NewLogoutCommand(mockConfigRepo, mockTokenCache, mocks.NewMockRancherClient(t), testutil.Logger())
+
+	// Act
+	loggedOut, err := cmd.Execute(context.Background(), LogoutRequest{Server: server.URL})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{server.URL}, loggedOut)
+}
+
+func TestLogoutCommand_Execute_AllWithRevoke(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockTokenCache := mocks.NewMockTokenCache(t)
+	mockRancherClient := mocks.NewMockRancherClient(t)
+	mockToken := mocks.NewMockAuthToken(t)
+
+	healthy := domain.ConfigServer{URL: "https://healthy.example.com", Username: "admin", AuthType: "local"}
+	offline := domain.ConfigServer{URL: "https://offline.example.com", Username: "admin", AuthType: "local"}
+	unused := domain.ConfigServer{URL: "https://unused.example.com", Username: "admin", AuthType: "local"}
+	mockConfigRepo.On("GetAllServers", mock.Anything).Return([]domain.ConfigServer{healthy, offline, unused}, nil)
+	mockToken.On("ID").Return("token-abcde").Maybe()
+	mockTokenCache.On("Get", mock.Anything, healthy).Return(mockToken, nil)
+	mockTokenCache.On("Get", mock.Anything, offline).Return(mockToken, nil)
+	mockTokenCache.On("Get", mock.Anything, unused).Return(nil, nil)
+	mockTokenCache.On("Delete", mock.Anything, healthy).Return(nil)
+	mockRancherClient.On("RevokeToken", mock.Anything, mockToken, healthy).Return(nil)
+	mockRancherClient.On("RevokeToken", mock.Anything, mockToken, offline).Return(errors.New("connection refused"))
+
+	cmd := NewLogoutCommand(mockConfigRepo, mockTokenCache, mockRancherClient, testutil.Logger())
+
+	// Act
+	loggedOut, err := cmd.Execute(context.Background(), LogoutRequest{All: true, Revoke: true})
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "offline.example.com")
+	assert.Equal(t, []string{healthy.URL, unused.URL}, loggedOut)
+	mockTokenCache.AssertNotCalled(t, "Delete", mock.Anything, offline)
+}
+
+func TestLogoutCommand_Execute_RequiresTarget(t *testing.T) {
+	cmd := NewLogoutCommand(mocks.NewMockConfigRepository(t), mocks.NewMockTokenCache(t),
+		mocks.NewMockRancherClient(t), testutil.Logger())
+
+	_, err := cmd.Execute(context.Background(), LogoutRequest{})
+	require.Error(t, err)
+
+	_, err = cmd.Execute(context.Background(), LogoutRequest{Server: "https://rancher.example.com", All: true})
+	require.Error(t, err)
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"cowpoke/internal/domain"
+)
+
+// outputsDir is the subdirectory of the kubeconfig directory holding the local copies of remote outputs.
+const outputsDir = "outputs"
+
+// outputMirror returns the local copy of a remote output, which a sync merges into before publishing it.
+// Commands that read the output read the copy, so they work the same for remote outputs.
+func outputMirror(configProvider domain.ConfigProvider, destination *url.URL) (string, error) {
+	kubeconfigDir, err := configProvider.GetKubeconfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get kubeconfig directory: %w", err)
+	}
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, destination.Scheme+"-"+destination.Host+destination.Path)
+	return filepath.Join(kubeconfigDir, outputsDir, name+".yaml"), nil
+}
+
+// outputWriter returns the writer for the destination of a remote output.
+func (c *SyncCommand) outputWriter(destination *url.URL) (domain.OutputWriter, error) {
+	for _, writer := range c.outputWriters {
+		if strings.EqualFold(writer.Scheme(), destination.Scheme) {
+			return writer, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported output %s:// (use a file path or one of: %s)",
+		destination.Scheme, strings.Join(c.outputSchemes(), ", "))
+}
+
+// outputSchemes returns the schemes of the remote outputs the sync can write, as "scheme://".
+func (c *SyncCommand) outputSchemes() []string {
+	schemes := make([]string, 0, len(c.outputWriters))
+	for _, writer := range c.outputWriters {
+		schemes = append(schemes, writer.Scheme()+"://")
+	}
+	return schemes
+}
+
+// publish writes the merged kubeconfig at path to a remote output.
+func (c *SyncCommand) publish(
+	ctx context.Context,
+	writer domain.OutputWriter,
+	destination *url.URL,
+	path string,
+) error {
+	if err := writer.WriteOutput(ctx, destination, path); err != nil {
+		return fmt.Errorf("failed to write output %s: %w", destination.Redacted(), err)
+	}
+	c.logger.DebugContext(ctx, "Published merged kubeconfig", "output", destination.Redacted(), "path", path)
+	return nil
+}
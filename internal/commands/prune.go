@@ -0,0 +1,152 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"cowpoke/internal/domain"
+)
+
+const (
+	// pruneRemovedServer is why a context of a server no longer configured is pruned.
+	pruneRemovedServer = "server no longer configured"
+	// pruneMissingCluster is why a context of a cluster the last discovery did not find is pruned.
+	pruneMissingCluster = "cluster not found by the last discovery"
+	// pruneRenamedCluster is why a context left behind by a cluster renamed in Rancher is pruned.
+	pruneRenamedCluster = "cluster renamed to %s"
+)
+
+// PruneCommand handles removing cowpoke contexts whose server or cluster is gone, without contacting Rancher.
+type PruneCommand struct {
+	configRepo        domain.ConfigRepository
+	configProvider    domain.ConfigProvider
+	kubeconfigHandler domain.KubeconfigHandler
+	stateStore        domain.StateStore
+	logger            *slog.Logger
+}
+
+// NewPruneCommand creates a new prune command.
+func NewPruneCommand(
+	configRepo domain.ConfigRepository,
+	configProvider domain.ConfigProvider,
+	kubeconfigHandler domain.KubeconfigHandler,
+	stateStore domain.StateStore,
+	logger *slog.Logger,
+) *PruneCommand {
+	return &PruneCommand{
+		configRepo:        configRepo,
+		configProvider:    configProvider,
+		kubeconfigHandler: kubeconfigHandler,
+		stateStore:        stateStore,
+		logger:            logger,
+	}
+}
+
+// PruneRequest contains the parameters for the prune command.
+type PruneRequest struct {
+	// Kubeconfig is the merged kubeconfig to prune; defaults to ~/.kube/config.
+	Kubeconfig string
+	// DryRun reports the contexts that would be pruned without removing them.
+	DryRun bool
+}
+
+// PrunedContext is a context the prune removed, or would remove in a dry run.
+type PrunedContext struct {
+	Name      string
+	ServerURL string
+	Reason    string
+}
+
+// PruneResult describes what the prune removed.
+type PruneResult struct {
+	Kubeconfig string
+	Contexts   []PrunedContext
+	DryRun     bool
+}
+
+// Execute runs the prune command. Contexts of servers missing from every profile are pruned, as are
+// contexts of clusters their server did not report when it was last discovered and contexts superseded by
+// newer ones of the same cluster after it was renamed. Servers never discovered keep all their contexts.
+func (c *PruneCommand) Execute(ctx context.Context, req PruneRequest) (*PruneResult, error) {
+	kubeconfigPath := req.Kubeconfig
+	if kubeconfigPath == "" {
+		var err error
+		kubeconfigPath, err = c.configProvider.GetDefaultKubeconfigPath()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get default kubeconfig path: %w", err)
+		}
+	}
+
+	servers, err := c.configRepo.GetAllServers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get servers: %w", err)
+	}
+	configured := make(map[string]bool, len(servers))
+	for _, server := range servers {
+		configured[server.ID()] = true
+	}
+
+	index, err := loadContextIndex(ctx, c.stateStore)
+	if err != nil {
+		return nil, err
+	}
+	var discovered map[string][]string
+	if index != nil {
+		discovered = index.Clusters
+	}
+
+	contexts, err := c.kubeconfigHandler.ListContexts(ctx, kubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kubeconfig: %w", err)
+	}
+
+	result := &PruneResult{Kubeconfig: kubeconfigPath, DryRun: req.DryRun}
+	superseded := domain.SupersededContexts(contexts)
+	for _, kubeContext := range contexts {
+		reason := pruneReason(kubeContext.Owner, configured, discovered)
+		if replacement, ok := superseded[kubeContext.Name]; ok && reason == "" {
+			reason = fmt.Sprintf(pruneRenamedCluster, replacement)
+		}
+		if reason != "" {
+			result.Contexts = append(result.Contexts, PrunedContext{
+				Name:      kubeContext.Name,
+				ServerURL: kubeContext.Owner.ServerURL,
+				Reason:    reason,
+			})
+		}
+	}
+	if req.DryRun || len(result.Contexts) == 0 {
+		c.logger.InfoContext(ctx, "Found contexts to prune", "contexts", len(result.Contexts), "dry_run", req.DryRun)
+		return result, nil
+	}
+
+	names := make([]string, 0, len(result.Contexts))
+	for _, pruned := range result.Contexts {
+		names = append(names, pruned.Name)
+	}
+	if _, removeErr := c.kubeconfigHandler.RemoveContexts(ctx, kubeconfigPath, names); removeErr != nil {
+		return nil, fmt.Errorf("failed to remove contexts: %w", removeErr)
+	}
+	if index != nil && index.Kubeconfig == kubeconfigPath {
+		if indexErr := indexContexts(ctx, c.stateStore, c.kubeconfigHandler, kubeconfigPath, discovered); indexErr != nil {
+			c.logger.WarnContext(ctx, "Failed to update context index", "error", indexErr)
+		}
+	}
+
+	c.logger.InfoContext(ctx, "Pruned contexts", "kubeconfig", kubeconfigPath, "contexts", len(names))
+	return result, nil
+}
+
+// pruneReason returns why a context owned by owner should be pruned, or "" if it should be kept.
+func pruneReason(owner domain.ContextOwner, configured map[string]bool, discovered map[string][]string) string {
+	if !configured[owner.ServerID] {
+		return pruneRemovedServer
+	}
+	clusters, ok := discovered[owner.ServerID]
+	if ok && owner.ClusterID != "" && !slices.Contains(clusters, owner.ClusterID) {
+		return pruneMissingCluster
+	}
+	return ""
+}
@@ -0,0 +1,167 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneCommand_Execute(t *testing.T) {
+	prod := domain.ConfigServer{URL: "https://prod.example.com"}
+	lab := domain.ConfigServer{URL: "https://lab.example.com"}
+	removed := domain.ConfigServer{URL: "https://old.example.com"}
+	kubeconfig := "/home/user/.kube/config"
+	owner := func(server domain.ConfigServer, clusterID string) domain.ContextOwner {
+		return domain.ContextOwner{ServerURL: server.URL, ServerID: server.ID(), ClusterID: clusterID}
+	}
+	contexts := []domain.ManagedContext{
+		{Name: "api-" + prod.ID(), Owner: owner(prod, "c-api")},
+		{Name: "gone-" + prod.ID(), Owner: owner(prod, "c-gone")},
+		{Name: "lab-" + lab.ID(), Owner: owner(lab, "c-lab")},
+		{Name: "web-" + removed.ID(), Owner: owner(removed, "")},
+	}
+	index := domain.ContextIndex{
+		Kubeconfig: kubeconfig,
+		Clusters:   map[string][]string{prod.ID(): {"c-api"}},
+	}
+	wantPruned := []PrunedContext{
+		{Name: "gone-" + prod.ID(), ServerURL: prod.URL, Reason: pruneMissingCluster},
+		{Name: "web-" + removed.ID(), ServerURL: removed.URL, Reason: pruneRemovedServer},
+	}
+
+	tests := []struct {
+		name   string
+		dryRun bool
+	}{
+		{name: "prune", dryRun: false},
+		{name: "dry run", dryRun: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockConfigRepo := mocks.NewMockConfigRepository(t)
+			mockConfigProvider := mocks.NewMockConfigProvider(t)
+			mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+			mockStore := mocks.NewMockStateStore(t)
+
+			mockConfigProvider.On("GetDefaultKubeconfigPath").Return(kubeconfig, nil)
+			mockConfigRepo.On("GetAllServers", mock.Anything).Return([]domain.ConfigServer{prod, lab}, nil)
+			mockStore.On("Load", mock.Anything, "contexts", mock.Anything).Run(func(args mock.Arguments) {
+				*args.Get(2).(*domain.ContextIndex) = index
+			}).Return(true, nil)
+			mockKubeconfigHandler.On("ListContexts", mock.Anything, kubeconfig).Return(contexts, nil).Once()
+			if !tt.dryRun {
+				mockKubeconfigHandler.On("RemoveContexts", mock.Anything, kubeconfig,
+					[]string{"gone-" + prod.ID(), "web-" + removed.ID()}).Return(2, nil)
+				mockKubeconfigHandler.On("ListContexts", mock.Anything, kubeconfig).
+					Return([]domain.ManagedContext{contexts[0], contexts[2]}, nil).Once()
+				mockStore.On("Save", mock.Anything, "contexts", mock.MatchedBy(func(saved domain.ContextIndex) bool {
+					return len(saved.Contexts) == 2 && len(saved.Clusters) == 1
+				})).Return(nil)
+			}
+
+			cmd := NewPruneCommand(mockConfigRepo, mockConfigProvider, mockKubeconfigHandler, mockStore,
+				testutil.Logger())
+
+			// Act
+			result, err := cmd.Execute(context.Background(), PruneRequest{DryRun: tt.dryRun})
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, &PruneResult{Kubeconfig: kubeconfig, Contexts: wantPruned, DryRun: tt.dryRun}, result)
+		})
+	}
+}
+
+func TestPruneCommand_Execute_NothingToPrune(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+	mockStore := mocks.NewMockStateStore(t)
+
+	prod := domain.ConfigServer{URL: "https://prod.example.com"}
+	mockConfigRepo.On("GetAllServers", mock.Anything).Return([]domain.ConfigServer{prod}, nil)
+	mockStore.On("Load", mock.Anything, "contexts", mock.Anything).Return(false, nil)
+	mockKubeconfigHandler.On("ListContexts", mock.Anything, "/tmp/config").Return([]domain.ManagedContext{
+		{Name: "api-" + prod.ID(), Owner: domain.ContextOwner{ServerID: prod.ID(), ClusterID: "c-api"}},
+	}, nil)
+
+	cmd := NewPruneCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockKubeconfigHandler, mockStore,
+		testutil.Logger())
+
+	// Act
+	result, err := cmd.Execute(context.Background(), PruneRequest{Kubeconfig: "/tmp/config"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, result.Contexts, "servers never discovered keep all their contexts")
+}
+
+func TestPruneCommand_Execute_RemoveError(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+	mockStore := mocks.NewMockStateStore(t)
+
+	mockConfigRepo.On("GetAllServers", mock.Anything).Return([]domain.ConfigServer{}, nil)
+	mockStore.On("Load", mock.Anything, "contexts", mock.Anything).Return(false, nil)
+	mockKubeconfigHandler.On("ListContexts", mock.Anything, "/tmp/config").Return([]domain.ManagedContext{
+		{Name: "api-1a2b3c4d", Owner: domain.ContextOwner{ServerID: "1a2b3c4d"}},
+	}, nil)
+	mockKubeconfigHandler.On("RemoveContexts", mock.Anything, "/tmp/config", []string{"api-1a2b3c4d"}).
+		Return(0, errors.New("permission denied"))
+
+	cmd := NewPruneCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockKubeconfigHandler, mockStore,
+		testutil.Logger())
+
+	// Act
+	_, err := cmd.Execute(context.Background(), PruneRequest{Kubeconfig: "/tmp/config"})
+
+	// Assert
+	require.ErrorContains(t, err, "failed to remove contexts: permission denied")
+}
+
+func TestPruneCommand_Execute_RenamedCluster(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+	mockStore := mocks.NewMockStateStore(t)
+
+	prod := domain.ConfigServer{URL: "https://prod.example.com"}
+	kubeconfig := "/home/user/.kube/config"
+	synced := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	owner := func(syncedAt time.Time) domain.ContextOwner {
+		return domain.ContextOwner{ServerURL: prod.URL, ServerID: prod.ID(), ClusterID: "c-api", SyncedAt: syncedAt}
+	}
+
+	mockConfigRepo.On("GetAllServers", mock.Anything).Return([]domain.ConfigServer{prod}, nil)
+	mockStore.On("Load", mock.Anything, "contexts", mock.Anything).Return(false, nil)
+	mockKubeconfigHandler.On("ListContexts", mock.Anything, kubeconfig).Return([]domain.ManagedContext{
+		{Name: "api-" + prod.ID(), Owner: owner(synced.Add(-24 * time.Hour))},
+		{Name: "api-fqdn-" + prod.ID(), Owner: owner(synced.Add(-24 * time.Hour))},
+		{Name: "gateway-" + prod.ID(), Owner: owner(synced)},
+		{Name: "gateway-fqdn-" + prod.ID(), Owner: owner(synced)},
+	}, nil)
+
+	cmd := NewPruneCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockKubeconfigHandler, mockStore,
+		testutil.Logger())
+
+	// Act
+	result, err := cmd.Execute(context.Background(), PruneRequest{Kubeconfig: kubeconfig, DryRun: true})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []PrunedContext{
+		{Name: "api-" + prod.ID(), ServerURL: prod.URL, Reason: "cluster renamed to gateway-" + prod.ID()},
+		{Name: "api-fqdn-" + prod.ID(), ServerURL: prod.URL, Reason: "cluster renamed to gateway-" + prod.ID()},
+	}, result.Contexts)
+}
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"cowpoke/internal/domain"
+)
+
+// RefreshCommand handles finding the contexts of a merged kubeconfig whose kubeconfigs can be downloaded
+// again, so that their credentials are renewed without discovering new clusters.
+type RefreshCommand struct {
+	configRepo        domain.ConfigRepository
+	configProvider    domain.ConfigProvider
+	kubeconfigHandler domain.KubeconfigHandler
+	logger            *slog.Logger
+}
+
+// NewRefreshCommand creates a new refresh command.
+func NewRefreshCommand(
+	configRepo domain.ConfigRepository,
+	configProvider domain.ConfigProvider,
+	kubeconfigHandler domain.KubeconfigHandler,
+	logger *slog.Logger,
+) *RefreshCommand {
+	return &RefreshCommand{
+		configRepo:        configRepo,
+		configProvider:    configProvider,
+		kubeconfigHandler: kubeconfigHandler,
+		logger:            logger,
+	}
+}
+
+// RefreshRequest contains the parameters for the refresh command.
+type RefreshRequest struct {
+	// Kubeconfig is the merged kubeconfig to refresh, defaulting to the profile's or the default output.
+	Kubeconfig string
+}
+
+// RefreshResult contains the result of the refresh command.
+type RefreshResult struct {
+	Kubeconfig string
+	// Output is where the refreshed contexts are synced to: Kubeconfig, or the remote output it is the local
+	// copy of.
+	Output string
+	// Contexts are the contexts to refresh.
+	Contexts []domain.ManagedContext
+	// Skipped are the contexts that cannot be refreshed, because they were generated without a cluster ID
+	// or their server is not configured in the active profile. They are kept as they are.
+	Skipped []domain.ManagedContext
+}
+
+// Clusters returns the clusters of the contexts to refresh, for syncing only those.
+func (r *RefreshResult) Clusters() []domain.ClusterRef {
+	return clusterRefs(r.Contexts)
+}
+
+// Execute runs the refresh command.
+func (c *RefreshCommand) Execute(ctx context.Context, req RefreshRequest) (*RefreshResult, error) {
+	defaults, err := c.configRepo.GetDefaults(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile defaults: %w", err)
+	}
+	output := cmp.Or(req.Kubeconfig, defaults.Output)
+	kubeconfigPath, err := resolveKubeconfig(c.configProvider, output)
+	if err != nil {
+		return nil, err
+	}
+
+	servers, err := c.configRepo.GetServers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get servers: %w", err)
+	}
+
+	contexts, err := c.kubeconfigHandler.ListContexts(ctx, kubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contexts: %w", err)
+	}
+
+	result := &RefreshResult{Kubeconfig: kubeconfigPath, Output: kubeconfigPath}
+	if _, remote := domain.ParseOutputDestination(output); remote {
+		result.Output = output
+	}
+	for _, managed := range contexts {
+		configured := slices.ContainsFunc(servers, func(server domain.ConfigServer) bool {
+			return server.ID() == managed.Owner.ServerID
+		})
+		if managed.Owner.ClusterID == "" || !configured {
+			c.logger.DebugContext(ctx, "Context cannot be refreshed", "context", managed.Name)
+			result.Skipped = append(result.Skipped, managed)
+			continue
+		}
+		result.Contexts = append(result.Contexts, managed)
+	}
+
+	c.logger.DebugContext(ctx, "Found contexts to refresh",
+		"path", kubeconfigPath,
+		"contexts", len(result.Contexts),
+		"skipped", len(result.Skipped))
+	return result, nil
+}
@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshCommand_Execute(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigProvider := mocks.NewMockConfigProvider(t)
+	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	owner := func(serverID, clusterID string) domain.ContextOwner {
+		return domain.ContextOwner{ServerID: serverID, ClusterID: clusterID}
+	}
+	prod := domain.ManagedContext{Name: "prod-" + server.ID(), ClusterName: "prod",
+		Owner: owner(server.ID(), "c-m-prod")}
+	local := domain.ManagedContext{Name: "local-" + server.ID(), ClusterName: "local",
+		Owner: owner(server.ID(), "local")}
+	legacy := domain.ManagedContext{Name: "old-" + server.ID(), ClusterName: "old", Owner: owner(server.ID(), "")}
+	removed := domain.ManagedContext{Name: "qa-1a2b3c4d", ClusterName: "qa", Owner: owner("1a2b3c4d", "c-m-qa")}
+
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigProvider.On("GetDefaultKubeconfigPath").Return("/home/user/.kube/config", nil)
+	mockKubeconfigHandler.On("ListContexts", mock.Anything, "/home/user/.kube/config").
+		Return([]domain.ManagedContext{local, legacy, prod, removed}, nil)
+
+	cmd := NewRefreshCommand(mockConfigRepo, mockConfigProvider, mockKubeconfigHandler, testutil.Logger())
+
+	// Act
+	result, err := cmd.Execute(context.Background(), RefreshRequest{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "/home/user/.kube/config", result.Kubeconfig)
+	assert.Equal(t, []domain.ManagedContext{local, prod}, result.Contexts)
+	assert.Equal(t, []domain.ManagedContext{legacy, removed}, result.Skipped)
+	assert.Equal(t, []domain.ClusterRef{
+		{Server: server.ID(), ClusterID: "local", Name: "local"},
+		{Server: server.ID(), ClusterID: "c-m-prod", Name: "prod"},
+	}, result.Clusters())
+}
+
+func TestRefreshCommand_Execute_UsesRequestedKubeconfig(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{Output: "/work/kubeconfig"}, nil)
+	mockConfigRepo.On("GetServers", mock.Anything).Return(nil, nil)
+	mockKubeconfigHandler.On("ListContexts", mock.Anything, "/tmp/kubeconfig").Return(nil, nil)
+
+	cmd := NewRefreshCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockKubeconfigHandler,
+		testutil.Logger())
+
+	// Act
+	result, err := cmd.Execute(context.Background(), RefreshRequest{Kubeconfig: "/tmp/kubeconfig"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/kubeconfig", result.Kubeconfig)
+	assert.Empty(t, result.Contexts)
+}
+
+func TestRefreshCommand_Execute_RemoteOutput(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigProvider := mocks.NewMockConfigProvider(t)
+	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+	mirror := "/home/user/.config/cowpoke/kubeconfigs/outputs/secret-ci_cowpoke.yaml"
+
+	mockConfigRepo.On("GetDefaults", mock.Anything).
+		Return(domain.ProfileDefaults{Output: "secret://ci/cowpoke"}, nil)
+	mockConfigRepo.On("GetServers", mock.Anything).Return(nil, nil)
+	mockConfigProvider.On("GetKubeconfigDir").Return("/home/user/.config/cowpoke/kubeconfigs", nil)
+	mockKubeconfigHandler.On("ListContexts", mock.Anything, mirror).Return(nil, nil)
+
+	cmd := NewRefreshCommand(mockConfigRepo, mockConfigProvider, mockKubeconfigHandler, testutil.Logger())
+
+	// Act
+	result, err := cmd.Execute(context.Background(), RefreshRequest{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, mirror, result.Kubeconfig)
+	assert.Equal(t, "secret://ci/cowpoke", result.Output)
+}
+
+func TestRefreshCommand_Execute_ListFails(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{Output: "/work/kubeconfig"}, nil)
+	mockConfigRepo.On("GetServers", mock.Anything).Return(nil, nil)
+	mockKubeconfigHandler.On("ListContexts", mock.Anything, "/work/kubeconfig").
+		Return(nil, errors.New("invalid kubeconfig"))
+
+	cmd := NewRefreshCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockKubeconfigHandler,
+		testutil.Logger())
+
+	// Act
+	_, err := cmd.Execute(context.Background(), RefreshRequest{})
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to list contexts")
+}
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"cowpoke/internal/domain"
+)
+
+const (
+	// scoopManifestFile is the name of the manifest written, as the Scoop bucket expects it.
+	scoopManifestFile = "cowpoke.json"
+
+	manifestDirPermissions  = 0o755
+	manifestFilePermissions = 0o644
+)
+
+// ReleaseManifestsCommand handles generating the package manager manifests of a release.
+type ReleaseManifestsCommand struct {
+	updater domain.Updater
+	fs      domain.FileSystemAdapter
+	logger  *slog.Logger
+}
+
+// NewReleaseManifestsCommand creates a new release-manifests command.
+func NewReleaseManifestsCommand(
+	updater domain.Updater,
+	fs domain.FileSystemAdapter,
+	logger *slog.Logger,
+) *ReleaseManifestsCommand {
+	return &ReleaseManifestsCommand{
+		updater: updater,
+		fs:      fs,
+		logger:  logger,
+	}
+}
+
+// ReleaseManifestsRequest contains the parameters for the release-manifests command.
+type ReleaseManifestsRequest struct {
+	// Version is the release's version, with or without its "v" prefix.
+	Version string
+	// Checksums is the release's checksums file, as GoReleaser writes it.
+	Checksums string
+	// OutputDir is the directory the manifests are written to.
+	OutputDir string
+}
+
+// ReleaseManifestsResult lists the manifests written.
+type ReleaseManifestsResult struct {
+	Scoop string
+}
+
+// Execute runs the release-manifests command.
+func (c *ReleaseManifestsCommand) Execute(
+	ctx context.Context,
+	req ReleaseManifestsRequest,
+) (*ReleaseManifestsResult, error) {
+	if req.Checksums == "" {
+		return nil, errors.New("a checksums file must be specified")
+	}
+
+	checksums, err := c.fs.ReadFile(req.Checksums)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checksums: %w", err)
+	}
+	manifests, err := c.updater.Manifests(req.Version, checksums)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.fs.MkdirAll(req.OutputDir, manifestDirPermissions); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	result := &ReleaseManifestsResult{Scoop: filepath.Join(req.OutputDir, scoopManifestFile)}
+	if err := c.fs.WriteFile(result.Scoop, manifests.Scoop, manifestFilePermissions); err != nil {
+		return nil, fmt.Errorf("failed to write Scoop manifest: %w", err)
+	}
+
+	c.logger.InfoContext(ctx, "Wrote release manifests", "version", req.Version, "scoop", result.Scoop)
+	return result, nil
+}
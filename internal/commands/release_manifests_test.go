@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cowpoke/internal/adapters/filesystem"
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseManifestsCommand_Execute(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	checksums := filepath.Join(dir, "checksums.txt")
+	require.NoError(t, os.WriteFile(checksums, []byte("abc123  cowpoke_Darwin_arm64.tar.gz\n"), 0o600))
+	manifests := &domain.ReleaseManifests{Scoop: []byte("{}\n")}
+
+	mockUpdater := mocks.NewMockUpdater(t)
+	mockUpdater.On("Manifests", "v1.5.0", []byte("abc123  cowpoke_Darwin_arm64.tar.gz\n")).Return(manifests, nil)
+
+	cmd := NewReleaseManifestsCommand(mockUpdater, filesystem.New(), testutil.Logger())
+
+	// Act
+	result, err := cmd.Execute(context.Background(), ReleaseManifestsRequest{
+		Version:   "v1.5.0",
+		Checksums: checksums,
+		OutputDir: filepath.Join(dir, "manifests"),
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "manifests", "cowpoke.json"), result.Scoop)
+	scoop, err := os.ReadFile(result.Scoop)
+	require.NoError(t, err)
+	assert.Equal(t, manifests.Scoop, scoop)
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -11,26 +12,59 @@ import (
 
 // RemoveCommand handles removing Rancher servers from the configuration.
 type RemoveCommand struct {
-	configRepo domain.ConfigRepository
-	logger     *slog.Logger
+	configRepo    domain.ConfigRepository
+	tokenCache    domain.TokenCache
+	rancherClient domain.RancherClient
+	logger        *slog.Logger
+}
+
+// RemoveOption is a functional option for wiring optional RemoveCommand dependencies.
+type RemoveOption func(*RemoveCommand)
+
+// WithTokenCleanup discards the removed server's cached token, revoking it in Rancher first when requested.
+func WithTokenCleanup(tokenCache domain.TokenCache, rancherClient domain.RancherClient) RemoveOption {
+	return func(c *RemoveCommand) {
+		c.tokenCache = tokenCache
+		c.rancherClient = rancherClient
+	}
 }
 
 // NewRemoveCommand creates a new remove command.
-func NewRemoveCommand(configRepo domain.ConfigRepository, logger *slog.Logger) *RemoveCommand {
-	return &RemoveCommand{
+func NewRemoveCommand(configRepo domain.ConfigRepository, logger *slog.Logger, opts ...RemoveOption) *RemoveCommand {
+	c := &RemoveCommand{
 		configRepo: configRepo,
 		logger:     logger,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // RemoveRequest contains the parameters for the remove command.
 type RemoveRequest struct {
 	ServerURL string
 	ServerID  string
+	// Revoke deletes the server's cached token in Rancher before the server is removed.
+	Revoke bool
 }
 
 // Execute runs the remove command.
 func (c *RemoveCommand) Execute(ctx context.Context, req RemoveRequest) error {
+	if req.Revoke && c.tokenCache == nil {
+		return errors.New("token revocation is not available")
+	}
+
+	server, err := c.findServer(ctx, req)
+	if err != nil {
+		return err
+	}
+	if req.Revoke && server != nil {
+		if revokeErr := revokeCachedToken(ctx, c.tokenCache, c.rancherClient, *server, c.logger); revokeErr != nil {
+			return fmt.Errorf("%w (server not removed)", revokeErr)
+		}
+	}
+
 	switch {
 	case req.ServerURL != "":
 		// Remove by URL
@@ -54,5 +88,52 @@ func (c *RemoveCommand) Execute(ctx context.Context, req RemoveRequest) error {
 		return errors.New("either ServerURL or ServerID must be specified")
 	}
 
+	if server != nil {
+		if deleteErr := c.tokenCache.Delete(ctx, *server); deleteErr != nil {
+			c.logger.WarnContext(ctx, "Failed to discard cached token", "url", server.URL, "error", deleteErr)
+		}
+	}
+	return nil
+}
+
+// findServer returns the configured server being removed, or nil if its cached token need not be cleaned up.
+func (c *RemoveCommand) findServer(ctx context.Context, req RemoveRequest) (*domain.ConfigServer, error) {
+	if c.tokenCache == nil {
+		return nil, nil
+	}
+
+	servers, err := c.configRepo.GetServers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get servers: %w", err)
+	}
+	return domain.FindServer(servers, cmp.Or(req.ServerURL, req.ServerID))
+}
+
+// revokeCachedToken revokes the token cached for server in Rancher and discards it. Servers without a
+// cached token have nothing to revoke.
+func revokeCachedToken(
+	ctx context.Context,
+	tokenCache domain.TokenCache,
+	rancherClient domain.RancherClient,
+	server domain.ConfigServer,
+	logger *slog.Logger,
+) error {
+	token, err := tokenCache.Get(ctx, server)
+	if err != nil {
+		return fmt.Errorf("failed to read cached token: %w", err)
+	}
+	if token == nil {
+		logger.InfoContext(ctx, "No cached token to revoke", "url", server.URL)
+		return nil
+	}
+
+	if revokeErr := rancherClient.RevokeToken(ctx, token, server); revokeErr != nil {
+		return fmt.Errorf("failed to revoke token for %s: %w", server.URL, revokeErr)
+	}
+	if deleteErr := tokenCache.Delete(ctx, server); deleteErr != nil {
+		return fmt.Errorf("failed to discard cached token: %w", deleteErr)
+	}
+
+	logger.InfoContext(ctx, "Revoked cached token", "url", server.URL, "token", token.ID())
 	return nil
 }
@@ -311,3 +311,71 @@ func TestNewRemoveCommand(t *testing.T) {
 	assert.NotNil(t, cmd)
 	assert.Equal(t, mockConfigRepo, cmd.configRepo)
 }
+
+func TestRemoveCommand_Execute_DiscardsCachedToken(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockTokenCache := mocks.NewMockTokenCache(t)
+	mockRancherClient := mocks.NewMockRancherClient(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigRepo.On("RemoveServerByID", mock.Anything, server.ID()).Return(nil)
+	mockTokenCache.On("Delete", mock.Anything, server).Return(nil)
+
+	cmd := NewRemoveCommand(mockConfigRepo, testutil.Logger(), WithTokenCleanup(mockTokenCache, mockRancherClient))
+
+	// Act
+	err := cmd.Execute(context.Background(), RemoveRequest{ServerID: server.ID()})
+
+	// Assert
+	require.NoError(t, err)
+	mockRancherClient.AssertNotCalled(t, "RevokeToken", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRemoveCommand_Execute_RevokesCachedToken(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockTokenCache := mocks.NewMockTokenCache(t)
+	mockRancherClient := mocks.NewMockRancherClient(t)
+	mockToken := mocks.NewMockAuthToken(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	mockToken.On("ID").Return("token-abcde")
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigRepo.On("RemoveServer", mock.Anything, server.URL+"/").Return(nil)
+	mockTokenCache.On("Get", mock.Anything, server).Return(mockToken, nil)
+	mockTokenCache.On("Delete", mock.Anything, server).Return(nil)
+	mockRancherClient.On("RevokeToken", mock.Anything, mockToken, server).Return(nil)
+
+	cmd := NewRemoveCommand(mockConfigRepo, testutil.Logger(), WithTokenCleanup(mockTokenCache, mockRancherClient))
+
+	// Act
+	err := cmd.Execute(context.Background(), RemoveRequest{ServerURL: server.URL + "/", Revoke: true})
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestRemoveCommand_Execute_RevokeFailureKeepsServer(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockTokenCache := mocks.NewMockTokenCache(t)
+	mockRancherClient := mocks.NewMockRancherClient(t)
+	mockToken := mocks.NewMockAuthToken(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockTokenCache.On("Get", mock.Anything, server).Return(mockToken, nil)
+	mockRancherClient.On("RevokeToken", mock.Anything, mockToken, server).Return(errors.New("connection refused"))
+
+	cmd := NewRemoveCommand(mockConfigRepo, testutil.Logger(), WithTokenCleanup(mockTokenCache, mockRancherClient))
+
+	// Act
+	err := cmd.Execute(context.Background(), RemoveRequest{ServerURL: server.URL, Revoke: true})
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server not removed")
+	mockConfigRepo.AssertNotCalled(t, "RemoveServer", mock.Anything, mock.Anything)
+}
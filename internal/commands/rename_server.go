@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"cowpoke/internal/domain"
+)
+
+// RenameServerCommand handles changing a server's URL while keeping its kubeconfig contexts usable.
+type RenameServerCommand struct {
+	configRepo        domain.ConfigRepository
+	configProvider    domain.ConfigProvider
+	kubeconfigHandler domain.KubeconfigHandler
+	indexStore        domain.StateStore
+	logger            *slog.Logger
+}
+
+// RenameServerOption is a functional option for wiring optional RenameServerCommand dependencies.
+type RenameServerOption func(*RenameServerCommand)
+
+// WithRenamedContextIndex updates the context index when the rewritten kubeconfig is the indexed one.
+func WithRenamedContextIndex(store domain.StateStore) RenameServerOption {
+	return func(c *RenameServerCommand) {
+		c.indexStore = store
+	}
+}
+
+// NewRenameServerCommand creates a new rename-server command.
+func NewRenameServerCommand(
+	configRepo domain.ConfigRepository,
+	configProvider domain.ConfigProvider,
+	kubeconfigHandler domain.KubeconfigHandler,
+	logger *slog.Logger,
+	opts ...RenameServerOption,
+) *RenameServerCommand {
+	c := &RenameServerCommand{
+		configRepo:        configRepo,
+		configProvider:    configProvider,
+		kubeconfigHandler: kubeconfigHandler,
+		logger:            logger,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// RenameServerRequest contains the parameters for the rename-server command.
+type RenameServerRequest struct {
+	From string
+	To   string
+	// Kubeconfig is the merged kubeconfig to rewrite; defaults to ~/.kube/config.
+	Kubeconfig string
+}
+
+// RenameServerResult describes what the rename changed.
+type RenameServerResult struct {
+	OldID           string
+	NewID           string
+	Kubeconfig      string
+	RenamedContexts int
+}
+
+// Execute runs the rename-server command.
+func (c *RenameServerCommand) Execute(ctx context.Context, req RenameServerRequest) (*RenameServerResult, error) {
+	fromURL := strings.TrimSuffix(req.From, "/")
+	toURL := strings.TrimSuffix(req.To, "/")
+	if fromURL == "" || toURL == "" {
+		return nil, errors.New("both the current and the new server URL must be specified")
+	}
+	if parsed, err := url.Parse(toURL); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid server URL %q: must include a scheme and host", req.To)
+	}
+
+	servers, err := c.configRepo.GetServers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get servers: %w", err)
+	}
+	var from domain.ConfigServer
+	var found bool
+	for _, server := range servers {
+		if server.URL == fromURL {
+			from, found = server, true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("server %s not found in configuration", fromURL)
+	}
+	to := from
+	to.URL = toURL
+
+	kubeconfigPath := req.Kubeconfig
+	if kubeconfigPath == "" {
+		kubeconfigPath, err = c.configProvider.GetDefaultKubeconfigPath()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get default kubeconfig path: %w", err)
+		}
+	}
+
+	c.logger.InfoContext(ctx, "Renaming server",
+		"from", fromURL,
+		"to", toURL,
+		"old_id", from.ID(),
+		"new_id", to.ID())
+
+	if updateErr := c.configRepo.UpdateServerURL(ctx, fromURL, toURL); updateErr != nil {
+		return nil, fmt.Errorf("failed to rename server: %w", updateErr)
+	}
+
+	renamed, err := c.kubeconfigHandler.RenameServer(ctx, kubeconfigPath, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("server renamed in configuration, but failed to rewrite contexts "+
+			"(run sync to regenerate them): %w", err)
+	}
+	c.reindex(ctx, kubeconfigPath, from.ID(), to.ID())
+
+	return &RenameServerResult{
+		OldID:           from.ID(),
+		NewID:           to.ID(),
+		Kubeconfig:      kubeconfigPath,
+		RenamedContexts: renamed,
+	}, nil
+}
+
+// reindex rebuilds the context index from the kubeconfig at path if the index describes it, moving the
+// clusters discovered on the server to its new ID. Failures are logged, never fatal.
+func (c *RenameServerCommand) reindex(ctx context.Context, path, oldID, newID string) {
+	if c.indexStore == nil {
+		return
+	}
+	index, err := loadContextIndex(ctx, c.indexStore)
+	if err == nil && index != nil && index.Kubeconfig == path {
+		if clusters, ok := index.Clusters[oldID]; ok {
+			delete(index.Clusters, oldID)
+			index.Clusters[newID] = clusters
+		}
+		err = indexContexts(ctx, c.indexStore, c.kubeconfigHandler, path, index.Clusters)
+	}
+	if err != nil {
+		c.logger.WarnContext(ctx, "Failed to update context index", "error", err)
+	}
+}
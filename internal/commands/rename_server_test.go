@@ -0,0 +1,168 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenameServerCommand_Execute_Success(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigProvider := mocks.NewMockConfigProvider(t)
+	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+
+	from := domain.ConfigServer{URL: "https://old.example.com", Username: "admin", AuthType: "local"}
+	to := domain.ConfigServer{URL: "https://new.example.com", Username: "admin", AuthType: "local"}
+
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{from}, nil)
+	mockConfigProvider.On("GetDefaultKubeconfigPath").Return("/home/user/.kube/config", nil)
+	mockConfigRepo.On("UpdateServerURL", mock.Anything, from.URL, to.URL).Return(nil)
+	mockKubeconfigHandler.On("RenameServer", mock.Anything, "/home/user/.kube/config", from, to).Return(3, nil)
+
+	cmd := NewRenameServerCommand(mockConfigRepo, mockConfigProvider, mockKubeconfigHandler, testutil.Logger())
+
+	// Act
+	result, err := cmd.Execute(context.Background(), RenameServerRequest{
+		From: "https://old.example.com/",
+		To:   "https://new.example.com/",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, &RenameServerResult{
+		OldID:           from.ID(),
+		NewID:           to.ID(),
+		Kubeconfig:      "/home/user/.kube/config",
+		RenamedContexts: 3,
+	}, result)
+}
+
+func TestRenameServerCommand_Execute_UpdatesContextIndex(t *testing.T) {
+	tests := []struct {
+		name       string
+		indexed    string
+		wantUpdate bool
+	}{
+		{name: "indexed kubeconfig", indexed: "/home/user/.kube/config", wantUpdate: true},
+		{name: "other kubeconfig", indexed: "/home/user/other-config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockConfigRepo := mocks.NewMockConfigRepository(t)
+			mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+			mockStore := mocks.NewMockStateStore(t)
+
+			from := domain.ConfigServer{URL: "https://old.example.com"}
+			to := domain.ConfigServer{URL: "https://new.example.com"}
+			kubeconfig := "/home/user/.kube/config"
+			mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{from}, nil)
+			mockConfigRepo.On("UpdateServerURL", mock.Anything, from.URL, to.URL).Return(nil)
+			mockKubeconfigHandler.On("RenameServer", mock.Anything, kubeconfig, from, to).Return(1, nil)
+			mockStore.On("Load", mock.Anything, "contexts", mock.Anything).Run(func(args mock.Arguments) {
+				*args.Get(2).(*domain.ContextIndex) = domain.ContextIndex{Kubeconfig: tt.indexed}
+			}).Return(true, nil)
+			if tt.wantUpdate {
+				mockKubeconfigHandler.On("ListContexts", mock.Anything, kubeconfig).Return([]domain.ManagedContext{
+					{Name: "prod-" + to.ID(), ClusterName: "prod", Owner: domain.ContextOwner{ServerURL: to.URL, ServerID: to.ID()}},
+				}, nil)
+				mockStore.On("Save", mock.Anything, "contexts", mock.MatchedBy(func(index domain.ContextIndex) bool {
+					_, ok := index.Contexts["prod-"+to.ID()]
+					return ok && len(index.Contexts) == 1
+				})).Return(nil)
+			}
+
+			cmd := NewRenameServerCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockKubeconfigHandler,
+				testutil.Logger(), WithRenamedContextIndex(mockStore))
+
+			// Act
+			_, err := cmd.Execute(context.Background(), RenameServerRequest{
+				From:       from.URL,
+				To:         to.URL,
+				Kubeconfig: kubeconfig,
+			})
+
+			// Assert
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRenameServerCommand_Execute_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     RenameServerRequest
+		wantErr string
+	}{
+		{
+			name:    "missing target",
+			req:     RenameServerRequest{From: "https://old.example.com"},
+			wantErr: "must be specified",
+		},
+		{
+			name:    "invalid target",
+			req:     RenameServerRequest{From: "https://old.example.com", To: "new.example.com"},
+			wantErr: "must include a scheme and host",
+		},
+		{
+			name:    "unknown source",
+			req:     RenameServerRequest{From: "https://missing.example.com", To: "https://new.example.com"},
+			wantErr: "server https://missing.example.com not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockConfigRepo := mocks.NewMockConfigRepository(t)
+			mockConfigRepo.On("GetServers", mock.Anything).
+				Return([]domain.ConfigServer{{URL: "https://old.example.com"}}, nil).Maybe()
+
+			cmd := NewRenameServerCommand(mockConfigRepo, mocks.NewMockConfigProvider(t),
+				mocks.NewMockKubeconfigHandler(t), testutil.Logger())
+
+			// Act
+			_, err := cmd.Execute(context.Background(), tt.req)
+
+			// Assert
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestRenameServerCommand_Execute_RewriteFails(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+
+	mockConfigRepo.On("GetServers", mock.Anything).
+		Return([]domain.ConfigServer{{URL: "https://old.example.com"}}, nil)
+	mockConfigRepo.On("UpdateServerURL", mock.Anything, "https://old.example.com", "https://new.example.com").
+		Return(nil)
+	mockKubeconfigHandler.On("RenameServer", mock.Anything, "/tmp/config", mock.Anything, mock.Anything).
+		Return(0, errors.New("parse error"))
+
+	cmd := NewRenameServerCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockKubeconfigHandler,
+		testutil.Logger())
+
+	// Act
+	_, err := cmd.Execute(context.Background(), RenameServerRequest{
+		From:       "https://old.example.com",
+		To:         "https://new.example.com",
+		Kubeconfig: "/tmp/config",
+	})
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "run sync to regenerate them")
+}
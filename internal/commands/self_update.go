@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"cowpoke/internal/domain"
+)
+
+// SelfUpdateCommand handles replacing the running cowpoke binary with the latest release.
+type SelfUpdateCommand struct {
+	updater domain.Updater
+	logger  *slog.Logger
+}
+
+// NewSelfUpdateCommand creates a new self-update command.
+func NewSelfUpdateCommand(updater domain.Updater, logger *slog.Logger) *SelfUpdateCommand {
+	return &SelfUpdateCommand{
+		updater: updater,
+		logger:  logger,
+	}
+}
+
+// SelfUpdateRequest contains the parameters for the self-update command.
+type SelfUpdateRequest struct {
+	// CurrentVersion is the running binary's version.
+	CurrentVersion string
+	// Executable is the path of the running binary.
+	Executable string
+	// CheckOnly reports whether a newer release exists without installing it.
+	CheckOnly bool
+}
+
+// SelfUpdateResult describes the latest release and whether it was installed.
+type SelfUpdateResult struct {
+	CurrentVersion  string
+	LatestVersion   string
+	ReleaseURL      string
+	UpdateAvailable bool
+	Updated         bool
+}
+
+// Execute runs the self-update command. Development builds can be checked but are never replaced, since
+// their version cannot be compared with a release.
+func (c *SelfUpdateCommand) Execute(ctx context.Context, req SelfUpdateRequest) (*SelfUpdateResult, error) {
+	release, err := c.updater.LatestRelease(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for updates: %w", err)
+	}
+
+	result := &SelfUpdateResult{
+		CurrentVersion:  req.CurrentVersion,
+		LatestVersion:   release.Version,
+		ReleaseURL:      release.URL,
+		UpdateAvailable: domain.IsNewerVersion(req.CurrentVersion, release.Version),
+	}
+	c.logger.DebugContext(ctx, "Checked for updates",
+		"current", req.CurrentVersion,
+		"latest", release.Version,
+		"update_available", result.UpdateAvailable)
+
+	if req.CheckOnly || !result.UpdateAvailable {
+		if !req.CheckOnly && !domain.IsReleaseVersion(req.CurrentVersion) {
+			return nil, fmt.Errorf("cowpoke %s is a development build and cannot be updated, "+
+				"install a release from %s", req.CurrentVersion, release.URL)
+		}
+		return result, nil
+	}
+
+	if installErr := c.updater.Install(ctx, release, req.Executable); installErr != nil {
+		return nil, fmt.Errorf("failed to install cowpoke %s: %w", release.Version, installErr)
+	}
+	result.Updated = true
+	return result, nil
+}
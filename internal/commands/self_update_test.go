@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfUpdateCommand_Execute(t *testing.T) {
+	release := &domain.Release{Version: "1.5.0", URL: "https://github.com/imandrew/cowpoke/releases/tag/v1.5.0"}
+
+	tests := []struct {
+		name      string
+		current   string
+		checkOnly bool
+		want      SelfUpdateResult
+	}{
+		{
+			name:    "newer release is installed",
+			current: "1.4.2",
+			want:    SelfUpdateResult{UpdateAvailable: true, Updated: true},
+		},
+		{
+			name:    "up to date",
+			current: "1.5.0",
+			want:    SelfUpdateResult{},
+		},
+		{
+			name:    "pre-release of the latest version is outdated",
+			current: "v1.5.0-rc.1",
+			want:    SelfUpdateResult{UpdateAvailable: true, Updated: true},
+		},
+		{
+			name:      "check only",
+			current:   "1.4.2",
+			checkOnly: true,
+			want:      SelfUpdateResult{UpdateAvailable: true},
+		},
+		{
+			name:      "development build can be checked",
+			current:   "dev",
+			checkOnly: true,
+			want:      SelfUpdateResult{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUpdater := mocks.NewMockUpdater(t)
+			mockUpdater.On("LatestRelease", mock.Anything).Return(release, nil)
+			if tt.want.Updated {
+				mockUpdater.On("Install", mock.Anything, release, "/usr/local/bin/cowpoke").Return(nil)
+			}
+
+			cmd := NewSelfUpdateCommand(mockUpdater, testutil.Logger())
+
+			// Act
+			result, err := cmd.Execute(context.Background(), SelfUpdateRequest{
+				CurrentVersion: tt.current,
+				Executable:     "/usr/local/bin/cowpoke",
+				CheckOnly:      tt.checkOnly,
+			})
+
+			// Assert
+			require.NoError(t, err)
+			tt.want.CurrentVersion = tt.current
+			tt.want.LatestVersion = "1.5.0"
+			tt.want.ReleaseURL = release.URL
+			assert.Equal(t, &tt.want, result)
+		})
+	}
+}
+
+func TestSelfUpdateCommand_Execute_Errors(t *testing.T) {
+	t.Run("development build", func(t *testing.T) {
+		mockUpdater := mocks.NewMockUpdater(t)
+		mockUpdater.On("LatestRelease", mock.Anything).Return(&domain.Release{Version: "1.5.0"}, nil)
+
+		result, err := NewSelfUpdateCommand(mockUpdater, testutil.Logger()).
+			Execute(context.Background(), SelfUpdateRequest{CurrentVersion: "dev"})
+
+		require.ErrorContains(t, err, "cowpoke dev is a development build")
+		assert.Nil(t, result)
+	})
+
+	t.Run("install fails", func(t *testing.T) {
+		release := &domain.Release{Version: "1.5.0"}
+		mockUpdater := mocks.NewMockUpdater(t)
+		mockUpdater.On("LatestRelease", mock.Anything).Return(release, nil)
+		mockUpdater.On("Install", mock.Anything, release, "/usr/local/bin/cowpoke").
+			Return(errors.New("checksum mismatch"))
+
+		result, err := NewSelfUpdateCommand(mockUpdater, testutil.Logger()).
+			Execute(context.Background(), SelfUpdateRequest{CurrentVersion: "1.4.2", Executable: "/usr/local/bin/cowpoke"})
+
+		require.ErrorContains(t, err, "failed to install cowpoke 1.5.0: checksum mismatch")
+		assert.Nil(t, result)
+	})
+}
@@ -0,0 +1,234 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"cowpoke/internal/domain"
+)
+
+// ShowCommand handles printing the kubeconfig of a single cluster, served from the fragment cache or
+// downloaded from Rancher, without touching the merged kubeconfig.
+type ShowCommand struct {
+	configRepo        domain.ConfigRepository
+	fragmentCache     domain.FragmentCache
+	tokenCache        domain.TokenCache
+	passwordReader    domain.PasswordReader
+	kubeconfigHandler domain.KubeconfigHandler
+	logger            *slog.Logger
+}
+
+// NewShowCommand creates a new show command.
+func NewShowCommand(
+	configRepo domain.ConfigRepository,
+	fragmentCache domain.FragmentCache,
+	tokenCache domain.TokenCache,
+	passwordReader domain.PasswordReader,
+	kubeconfigHandler domain.KubeconfigHandler,
+	logger *slog.Logger,
+) *ShowCommand {
+	return &ShowCommand{
+		configRepo:        configRepo,
+		fragmentCache:     fragmentCache,
+		tokenCache:        tokenCache,
+		passwordReader:    passwordReader,
+		kubeconfigHandler: kubeconfigHandler,
+		logger:            logger,
+	}
+}
+
+// ShowRequest contains the parameters for the show command.
+type ShowRequest struct {
+	// Cluster is the name or ID of the cluster. Clusters without a cached fragment are looked up by ID.
+	Cluster string
+	// Server narrows the cluster down to one configured server, by URL or ID.
+	Server string
+	// Refresh downloads the kubeconfig even if a cached fragment exists.
+	Refresh bool
+	// Redact replaces the kubeconfig's tokens, passwords and client keys with a placeholder.
+	Redact bool
+}
+
+// ShowResult contains the result of the show command.
+type ShowResult struct {
+	ServerURL string
+	ClusterID string
+	// Cached reports whether the kubeconfig was served from the fragment cache rather than downloaded.
+	Cached     bool
+	Kubeconfig []byte
+}
+
+// Execute runs the show command. Downloads go through syncOrchestrator, which caches the fragment as a
+// sync would.
+func (c *ShowCommand) Execute(
+	ctx context.Context,
+	req ShowRequest,
+	syncOrchestrator domain.SyncOrchestrator,
+) (*ShowResult, error) {
+	if req.Cluster == "" {
+		return nil, errors.New("cluster name or ID is required")
+	}
+
+	servers, err := c.configRepo.GetServers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get servers: %w", err)
+	}
+	var server *domain.ConfigServer
+	if req.Server != "" {
+		if server, err = domain.FindServer(servers, req.Server); err != nil {
+			return nil, err
+		}
+		if server == nil {
+			return nil, fmt.Errorf("server %s is not configured", req.Server)
+		}
+	}
+
+	entry, err := c.findFragment(ctx, req.Cluster, server)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil && !req.Refresh {
+		path := filepath.Join(c.fragmentCache.Dir(), entry.File)
+		kubeconfig, readErr := c.kubeconfigHandler.ReadKubeconfig(ctx, path, req.Redact)
+		if readErr != nil {
+			return nil, readErr
+		}
+		c.logger.DebugContext(ctx, "Serving cached kubeconfig", "cluster", req.Cluster, "path", path)
+		return &ShowResult{
+			ServerURL:  entry.ServerURL,
+			ClusterID:  entry.ClusterID,
+			Cached:     true,
+			Kubeconfig: kubeconfig,
+		}, nil
+	}
+
+	cluster := domain.Cluster{ID: req.Cluster, Name: req.Cluster}
+	if entry != nil {
+		cluster = domain.Cluster{ID: entry.ClusterID, Name: entry.ClusterName}
+		if server == nil {
+			server, _ = domain.FindServer(servers, entry.ServerID)
+		}
+	}
+	if server == nil {
+		if len(servers) != 1 {
+			return nil, fmt.Errorf("cluster %s is not cached; use --server to choose the server to download it from",
+				req.Cluster)
+		}
+		server = &servers[0]
+	}
+	return c.download(ctx, req, *server, cluster, syncOrchestrator)
+}
+
+// findFragment returns the cached fragment of the cluster named or identified by cluster, or nil if it is
+// not cached. With a server, only that server's fragments are considered.
+func (c *ShowCommand) findFragment(
+	ctx context.Context,
+	cluster string,
+	server *domain.ConfigServer,
+) (*domain.CacheEntry, error) {
+	entries, err := c.fragmentCache.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cached kubeconfigs: %w", err)
+	}
+
+	var matches []domain.CacheEntry
+	for _, entry := range entries {
+		if entry.ClusterID == "" || (entry.ClusterName != cluster && entry.ClusterID != cluster) {
+			continue
+		}
+		if server != nil && entry.ServerID != server.ID() {
+			continue
+		}
+		matches = append(matches, entry)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &matches[0], nil
+	default:
+		urls := make([]string, 0, len(matches))
+		for _, match := range matches {
+			urls = append(urls, match.ServerURL)
+		}
+		slices.Sort(urls)
+		return nil, fmt.Errorf("cluster %s exists on several servers (%s); use --server to choose one",
+			cluster, strings.Join(urls, ", "))
+	}
+}
+
+// download downloads the kubeconfig of cluster from server and reads it.
+func (c *ShowCommand) download(
+	ctx context.Context,
+	req ShowRequest,
+	server domain.ConfigServer,
+	cluster domain.Cluster,
+	syncOrchestrator domain.SyncOrchestrator,
+) (*ShowResult, error) {
+	path, err := downloadFragment(ctx, c.tokenCache, c.passwordReader, server, cluster, syncOrchestrator, c.logger)
+	if err != nil {
+		return nil, err
+	}
+	kubeconfig, err := c.kubeconfigHandler.ReadKubeconfig(ctx, path, req.Redact)
+	if err != nil {
+		return nil, err
+	}
+	return &ShowResult{ServerURL: server.URL, ClusterID: cluster.ID, Kubeconfig: kubeconfig}, nil
+}
+
+// downloadFragment downloads the kubeconfig of cluster from server into the fragment cache, logging in only
+// if no token is cached, and returns the fragment's path.
+func downloadFragment(
+	ctx context.Context,
+	tokenCache domain.TokenCache,
+	passwordReader domain.PasswordReader,
+	server domain.ConfigServer,
+	cluster domain.Cluster,
+	syncOrchestrator domain.SyncOrchestrator,
+	logger *slog.Logger,
+) (string, error) {
+	passwords := make(map[string]string)
+	var token domain.AuthToken
+	if tokenCache != nil {
+		var err error
+		if token, err = tokenCache.Get(ctx, server); err != nil {
+			logger.DebugContext(ctx, "Could not read cached token", "server", server.URL, "error", err)
+		}
+	}
+	if token == nil {
+		if server.AuthType == domain.AuthTypeToken {
+			return "", fmt.Errorf("API token for %s not found (run cowpoke convert-to-token to create one)",
+				server.URL)
+		}
+		password, err := passwordReader.ReadPassword(ctx, fmt.Sprintf("Password for %s: ", server.URL))
+		if err != nil {
+			return "", fmt.Errorf("failed to read password for %s: %w", server.URL, err)
+		}
+		passwords[server.ID()] = password
+	}
+
+	logger.DebugContext(ctx, "Downloading kubeconfig", "server", server.URL, "cluster", cluster.ID)
+	targets := []domain.ServerClusters{{Server: server, Clusters: []domain.Cluster{cluster}}}
+	result, err := syncOrchestrator.SyncClusters(ctx, targets, passwords, domain.SyncOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to download kubeconfig: %w", err)
+	}
+	for _, serverResult := range result.Servers {
+		if serverResult.Error != nil {
+			return "", fmt.Errorf("failed to download kubeconfig: %w", serverResult.Error)
+		}
+	}
+	if downloadErr := result.DownloadErr(); downloadErr != nil {
+		return "", fmt.Errorf("failed to download kubeconfig: %w", downloadErr)
+	}
+	if len(result.KubeconfigPaths) == 0 {
+		return "", fmt.Errorf("no kubeconfig was downloaded for cluster %s", cluster.ID)
+	}
+	return result.KubeconfigPaths[0], nil
+}
@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShowCommand_Execute_Cached(t *testing.T) {
+	// Arrange
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockCache := mocks.NewMockFragmentCache(t)
+	mockCache.On("List", mock.Anything).Return([]domain.CacheEntry{{
+		File:        "prod-" + server.ID() + ".yaml",
+		ServerURL:   server.URL,
+		ServerID:    server.ID(),
+		ClusterID:   "c-m-prod",
+		ClusterName: "prod",
+	}}, nil)
+	mockCache.On("Dir").Return("/cache")
+	mockHandler := mocks.NewMockKubeconfigHandler(t)
+	mockHandler.On("ReadKubeconfig", mock.Anything, filepath.Join("/cache", "prod-"+server.ID()+".yaml"), true).
+		Return([]byte("kubeconfig"), nil)
+
+	cmd := NewShowCommand(mockConfigRepo, mockCache, nil, nil, mockHandler, testutil.Logger())
+
+	// Act
+	result, err := cmd.Execute(context.Background(), ShowRequest{Cluster: "prod", Redact: true}, nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, result.Cached)
+	assert.Equal(t, "c-m-prod", result.ClusterID)
+	assert.Equal(t, []byte("kubeconfig"), result.Kubeconfig)
+}
+
+func TestShowCommand_Execute_Download(t *testing.T) {
+	// Arrange
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	other := domain.ConfigServer{URL: "https://other.example.com", Username: "admin", AuthType: "local"}
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server, other}, nil)
+	mockCache := mocks.NewMockFragmentCache(t)
+	mockCache.On("List", mock.Anything).Return([]domain.CacheEntry{}, nil)
+	mockTokenCache := mocks.NewMockTokenCache(t)
+	mockTokenCache.On("Get", mock.Anything, server).Return(nil, nil)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockPasswordReader.On("ReadPassword", mock.Anything, "Password for https://rancher.example.com: ").
+		Return("password", nil)
+	mockSyncOrchestrator := mocks.NewMockSyncOrchestrator(t)
+	targets := []domain.ServerClusters{{Server: server, Clusters: []domain.Cluster{{ID: "c-m-prod", Name: "c-m-prod"}}}}
+	mockSyncOrchestrator.On("SyncClusters", mock.Anything, targets, map[string]string{server.ID(): "password"},
+		domain.SyncOptions{}).
+		Return(&domain.SyncResult{KubeconfigPaths: []string{"/cache/fragment.yaml"}}, nil)
+	mockHandler := mocks.NewMockKubeconfigHandler(t)
+	mockHandler.On("ReadKubeconfig", mock.Anything, "/cache/fragment.yaml", false).Return([]byte("kubeconfig"), nil)
+
+	cmd := NewShowCommand(mockConfigRepo, mockCache, mockTokenCache, mockPasswordReader, mockHandler,
+		testutil.Logger())
+
+	// Act
+	result, err := cmd.Execute(context.Background(),
+		ShowRequest{Cluster: "c-m-prod", Server: server.URL}, mockSyncOrchestrator)
+
+	// Assert
+	require.NoError(t, err)
+	assert.False(t, result.Cached)
+	assert.Equal(t, server.URL, result.ServerURL)
+	assert.Equal(t, []byte("kubeconfig"), result.Kubeconfig)
+}
+
+func TestShowCommand_Execute_AmbiguousCluster(t *testing.T) {
+	// Arrange
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	other := domain.ConfigServer{URL: "https://other.example.com", Username: "admin", AuthType: "local"}
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server, other}, nil)
+	mockCache := mocks.NewMockFragmentCache(t)
+	mockCache.On("List", mock.Anything).Return([]domain.CacheEntry{
+		{ServerURL: server.URL, ServerID: server.ID(), ClusterID: "c-m-1", ClusterName: "prod"},
+		{ServerURL: other.URL, ServerID: other.ID(), ClusterID: "c-m-2", ClusterName: "prod"},
+	}, nil)
+
+	cmd := NewShowCommand(mockConfigRepo, mockCache, nil, nil, mocks.NewMockKubeconfigHandler(t),
+		testutil.Logger())
+
+	// Act
+	_, err := cmd.Execute(context.Background(), ShowRequest{Cluster: "prod"}, nil)
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exists on several servers")
+}
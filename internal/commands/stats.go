@@ -0,0 +1,210 @@
+package commands
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+
+	"cowpoke/internal/domain"
+)
+
+// expiryRange is a bucket of the expiry histogram, holding credentials that expire within a duration.
+type expiryRange struct {
+	label  string
+	within time.Duration
+}
+
+// expiryRanges are the expiry histogram's buckets between the expired bucket and the later one.
+//
+//nolint:gochecknoglobals // Read-only histogram layout
+var expiryRanges = []expiryRange{
+	{label: "within 1d", within: 24 * time.Hour},
+	{label: "within 7d", within: 7 * 24 * time.Hour},
+	{label: "within 30d", within: 30 * 24 * time.Hour},
+}
+
+// StatsCommand handles summarizing what cowpoke maintains in a merged kubeconfig.
+type StatsCommand struct {
+	configRepo        domain.ConfigRepository
+	configProvider    domain.ConfigProvider
+	kubeconfigHandler domain.KubeconfigHandler
+	fs                domain.FileSystemAdapter
+	clock             domain.Clock
+	logger            *slog.Logger
+}
+
+// NewStatsCommand creates a new stats command.
+func NewStatsCommand(
+	configRepo domain.ConfigRepository,
+	configProvider domain.ConfigProvider,
+	kubeconfigHandler domain.KubeconfigHandler,
+	fs domain.FileSystemAdapter,
+	clock domain.Clock,
+	logger *slog.Logger,
+) *StatsCommand {
+	return &StatsCommand{
+		configRepo:        configRepo,
+		configProvider:    configProvider,
+		kubeconfigHandler: kubeconfigHandler,
+		fs:                fs,
+		clock:             clock,
+		logger:            logger,
+	}
+}
+
+// StatsRequest contains the parameters for the stats command.
+type StatsRequest struct {
+	// Kubeconfig is the merged kubeconfig to inspect, defaulting to the profile's or the default output.
+	Kubeconfig string
+}
+
+// ServerContexts counts the contexts of one server in a merged kubeconfig.
+type ServerContexts struct {
+	ServerURL string `json:"serverUrl"`
+	ServerID  string `json:"serverId"`
+	Contexts  int    `json:"contexts"`
+}
+
+// ExpiryBucket counts the contexts whose credentials expire within one range of time.
+type ExpiryBucket struct {
+	Label    string `json:"label"`
+	Contexts int    `json:"contexts"`
+}
+
+// DuplicateEndpoint is an API server URL that several cowpoke contexts point at.
+type DuplicateEndpoint struct {
+	Endpoint string   `json:"endpoint"`
+	Contexts []string `json:"contexts"`
+}
+
+// StatsResult summarizes a merged kubeconfig.
+type StatsResult struct {
+	Kubeconfig string `json:"kubeconfig"`
+	Size       int64  `json:"size"`
+	// Managed counts the contexts cowpoke generated; Foreign lists the others by name.
+	Managed int      `json:"managed"`
+	Foreign []string `json:"foreign"`
+	// Servers counts the managed contexts per server, most first.
+	Servers []ServerContexts `json:"servers"`
+	// Expiry is a histogram of when the credentials of the managed contexts expire, soonest first. Its
+	// last bucket holds the contexts without a recorded expiry.
+	Expiry []ExpiryBucket `json:"expiry"`
+	// Duplicates are the endpoints shared by more than one managed context, sorted by endpoint.
+	Duplicates []DuplicateEndpoint `json:"duplicates"`
+}
+
+// Execute runs the stats command.
+func (c *StatsCommand) Execute(ctx context.Context, req StatsRequest) (*StatsResult, error) {
+	defaults, err := c.configRepo.GetDefaults(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile defaults: %w", err)
+	}
+	kubeconfigPath, err := resolveKubeconfig(c.configProvider, cmp.Or(req.Kubeconfig, defaults.Output))
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := c.fs.Stat(kubeconfigPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no kubeconfig at %s (run cowpoke sync to create it)", kubeconfigPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect kubeconfig: %w", err)
+	}
+	contexts, err := c.kubeconfigHandler.ListContexts(ctx, kubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contexts: %w", err)
+	}
+	foreign, err := c.kubeconfigHandler.ForeignContexts(ctx, kubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list foreign contexts: %w", err)
+	}
+
+	result := &StatsResult{
+		Kubeconfig: kubeconfigPath,
+		Size:       info.Size(),
+		Managed:    len(contexts),
+		Foreign:    foreign,
+		Servers:    serverContexts(contexts),
+		Expiry:     c.expiryHistogram(contexts),
+		Duplicates: duplicateEndpoints(contexts),
+	}
+
+	c.logger.DebugContext(ctx, "Collected kubeconfig stats",
+		"path", kubeconfigPath,
+		"managed", result.Managed,
+		"foreign", len(result.Foreign))
+	return result, nil
+}
+
+// serverContexts counts contexts per server, most first and then by URL.
+func serverContexts(contexts []domain.ManagedContext) []ServerContexts {
+	index := make(map[string]int)
+	var servers []ServerContexts
+	for _, managed := range contexts {
+		i, ok := index[managed.Owner.ServerID]
+		if !ok {
+			i = len(servers)
+			index[managed.Owner.ServerID] = i
+			servers = append(servers, ServerContexts{ServerURL: managed.Owner.ServerURL, ServerID: managed.Owner.ServerID})
+		}
+		servers[i].Contexts++
+	}
+	slices.SortFunc(servers, func(a, b ServerContexts) int {
+		return cmp.Or(cmp.Compare(b.Contexts, a.Contexts), cmp.Compare(a.ServerURL, b.ServerURL))
+	})
+	return servers
+}
+
+// expiryHistogram sorts contexts into buckets by how soon their credentials expire.
+func (c *StatsCommand) expiryHistogram(contexts []domain.ManagedContext) []ExpiryBucket {
+	now := c.clock.Now()
+	histogram := make([]ExpiryBucket, 0, len(expiryRanges)+3) //nolint:mnd // Expired, later and never
+	histogram = append(histogram, ExpiryBucket{Label: "expired"})
+	for _, bucket := range expiryRanges {
+		histogram = append(histogram, ExpiryBucket{Label: bucket.label})
+	}
+	histogram = append(histogram, ExpiryBucket{Label: "later"}, ExpiryBucket{Label: "never"})
+
+	for _, managed := range contexts {
+		expiresAt := managed.Owner.ExpiresAt
+		switch {
+		case expiresAt.IsZero():
+			histogram[len(histogram)-1].Contexts++
+		case !expiresAt.After(now):
+			histogram[0].Contexts++
+		default:
+			i := slices.IndexFunc(expiryRanges, func(bucket expiryRange) bool {
+				return expiresAt.Sub(now) <= bucket.within
+			})
+			if i < 0 {
+				i = len(expiryRanges)
+			}
+			histogram[i+1].Contexts++
+		}
+	}
+	return histogram
+}
+
+// duplicateEndpoints returns the endpoints more than one context points at, sorted by endpoint.
+func duplicateEndpoints(contexts []domain.ManagedContext) []DuplicateEndpoint {
+	names := make(map[string][]string)
+	for _, managed := range contexts {
+		if managed.Endpoint != "" {
+			names[managed.Endpoint] = append(names[managed.Endpoint], managed.Name)
+		}
+	}
+	var duplicates []DuplicateEndpoint
+	for endpoint, contextNames := range names {
+		if len(contextNames) > 1 {
+			duplicates = append(duplicates, DuplicateEndpoint{Endpoint: endpoint, Contexts: contextNames})
+		}
+	}
+	slices.SortFunc(duplicates, func(a, b DuplicateEndpoint) int { return cmp.Compare(a.Endpoint, b.Endpoint) })
+	return duplicates
+}
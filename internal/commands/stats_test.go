@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cowpoke/internal/adapters/filesystem"
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsCommand_Execute(t *testing.T) {
+	// Arrange
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(path, []byte("kubeconfig"), 0o600))
+
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+
+	prod := domain.ContextOwner{ServerURL: "https://prod.example.com", ServerID: "1a2b3c4d"}
+	lab := domain.ContextOwner{ServerURL: "https://lab.example.com", ServerID: "5e6f7a8b"}
+	expiring := func(owner domain.ContextOwner, in time.Duration) domain.ContextOwner {
+		owner.ExpiresAt = now.Add(in)
+		return owner
+	}
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockKubeconfigHandler.On("ListContexts", mock.Anything, path).Return([]domain.ManagedContext{
+		{Name: "api-1a2b3c4d", Owner: expiring(prod, -time.Hour), Endpoint: "https://api.example.com"},
+		{Name: "api-5e6f7a8b", Owner: expiring(lab, 2*time.Hour), Endpoint: "https://api.example.com"},
+		{Name: "db-1a2b3c4d", Owner: expiring(prod, 3*24*time.Hour), Endpoint: "https://db.example.com"},
+		{Name: "web-1a2b3c4d", Owner: expiring(prod, 90*24*time.Hour), Endpoint: "https://web.example.com"},
+		{Name: "local-1a2b3c4d", Owner: prod},
+	}, nil)
+	mockKubeconfigHandler.On("ForeignContexts", mock.Anything, path).Return([]string{"kind-dev"}, nil)
+
+	cmd := NewStatsCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockKubeconfigHandler,
+		filesystem.New(), testutil.NewClock(now), testutil.Logger())
+
+	// Act
+	result, err := cmd.Execute(context.Background(), StatsRequest{Kubeconfig: path})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, &StatsResult{
+		Kubeconfig: path,
+		Size:       int64(len("kubeconfig")),
+		Managed:    5,
+		Foreign:    []string{"kind-dev"},
+		Servers: []ServerContexts{
+			{ServerURL: "https://prod.example.com", ServerID: "1a2b3c4d", Contexts: 4},
+			{ServerURL: "https://lab.example.com", ServerID: "5e6f7a8b", Contexts: 1},
+		},
+		Expiry: []ExpiryBucket{
+			{Label: "expired", Contexts: 1},
+			{Label: "within 1d", Contexts: 1},
+			{Label: "within 7d", Contexts: 1},
+			{Label: "within 30d", Contexts: 0},
+			{Label: "later", Contexts: 1},
+			{Label: "never", Contexts: 1},
+		},
+		Duplicates: []DuplicateEndpoint{
+			{Endpoint: "https://api.example.com", Contexts: []string{"api-1a2b3c4d", "api-5e6f7a8b"}},
+		},
+	}, result)
+}
+
+func TestStatsCommand_Execute_MissingKubeconfig(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "config")
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+
+	cmd := NewStatsCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mocks.NewMockKubeconfigHandler(t),
+		filesystem.New(), testutil.NewClock(time.Now()), testutil.Logger())
+
+	// Act
+	_, err := cmd.Execute(context.Background(), StatsRequest{Kubeconfig: path})
+
+	// Assert
+	require.ErrorContains(t, err, "run cowpoke sync to create it")
+}
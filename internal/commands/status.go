@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"cowpoke/internal/domain"
+)
+
+// StatusCommand handles reporting the health of configured Rancher servers.
+type StatusCommand struct {
+	configRepo    domain.ConfigRepository
+	healthTracker domain.HealthTracker
+	logger        *slog.Logger
+}
+
+// NewStatusCommand creates a new status command.
+func NewStatusCommand(
+	configRepo domain.ConfigRepository,
+	healthTracker domain.HealthTracker,
+	logger *slog.Logger,
+) *StatusCommand {
+	return &StatusCommand{
+		configRepo:    configRepo,
+		healthTracker: healthTracker,
+		logger:        logger,
+	}
+}
+
+// ServerStatus pairs a configured server with its recorded health.
+type ServerStatus struct {
+	Server domain.ConfigServer
+	Health domain.ServerHealth
+}
+
+// StatusResult contains the result of the status command.
+type StatusResult struct {
+	Servers []ServerStatus
+}
+
+// Execute runs the status command.
+func (c *StatusCommand) Execute(ctx context.Context) (*StatusResult, error) {
+	servers, err := c.configRepo.GetServers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get servers: %w", err)
+	}
+
+	result := &StatusResult{Servers: make([]ServerStatus, 0, len(servers))}
+	for _, server := range servers {
+		health, healthErr := c.healthTracker.Get(ctx, server)
+		if healthErr != nil {
+			return nil, fmt.Errorf("failed to get health for %s: %w", server.URL, healthErr)
+		}
+		result.Servers = append(result.Servers, ServerStatus{Server: server, Health: health})
+	}
+
+	c.logger.DebugContext(ctx, "Collected server status", "count", len(result.Servers))
+	return result, nil
+}
@@ -1,13 +1,21 @@
 package commands
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"cowpoke/internal/domain"
+	"cowpoke/internal/logging"
 	"cowpoke/internal/services/filter"
+	"cowpoke/internal/services/metrics"
 )
 
 // SyncCommand handles syncing kubeconfigs from Rancher servers.
@@ -15,22 +23,153 @@ type SyncCommand struct {
 	configRepo     domain.ConfigRepository
 	configProvider domain.ConfigProvider
 	passwordReader domain.PasswordReader
+	fragmentCache  domain.FragmentCache
+	limits         *domain.LimitSettings
+	healthTracker  domain.HealthTracker
+	tokenCache     domain.TokenCache
+	reportStore    domain.StateStore
+	indexStore     domain.StateStore
+	validator      domain.FragmentValidator
+	outputWriters  []domain.OutputWriter
+	renderer       domain.TemplateRenderer
+	templates      []domain.TemplateSettings
+	hookRunner     domain.HookRunner
+	hooks          domain.HookSettings
+	tracer         domain.Tracer
+	naming         domain.NamingSettings
+	settings       domain.SyncSettings
+	resolver       domain.Resolver
+	clock          domain.Clock
 	logger         *slog.Logger
 }
 
+// SyncOption is a functional option for wiring optional SyncCommand dependencies.
+type SyncOption func(*SyncCommand)
+
+// WithFragmentCache enables garbage collection of stale fragments after each sync.
+func WithFragmentCache(fragmentCache domain.FragmentCache) SyncOption {
+	return func(c *SyncCommand) {
+		c.fragmentCache = fragmentCache
+	}
+}
+
+// WithHealthTracker skips servers that are backing off after repeated failures and records outcomes.
+func WithHealthTracker(healthTracker domain.HealthTracker) SyncOption {
+	return func(c *SyncCommand) {
+		c.healthTracker = healthTracker
+	}
+}
+
+// WithClock sets the clock that backoff is checked against. Defaults to the system clock.
+func WithClock(clock domain.Clock) SyncOption {
+	return func(c *SyncCommand) {
+		c.clock = clock
+	}
+}
+
+// WithTokenCache skips password prompts for servers with a valid cached token and enables cached-only syncs.
+func WithTokenCache(tokenCache domain.TokenCache) SyncOption {
+	return func(c *SyncCommand) {
+		c.tokenCache = tokenCache
+	}
+}
+
+// WithReportStore persists the report of each sync so that it can be reviewed with LastCommand.
+func WithReportStore(store domain.StateStore) SyncOption {
+	return func(c *SyncCommand) {
+		c.reportStore = store
+	}
+}
+
+// WithContextIndex records the contexts of each merged kubeconfig written locally in the context index.
+func WithContextIndex(store domain.StateStore) SyncOption {
+	return func(c *SyncCommand) {
+		c.indexStore = store
+	}
+}
+
+// WithOutputWriters lets the sync publish merged kubeconfigs to remote outputs, such as Kubernetes Secrets,
+// named by URLs with the writers' schemes.
+func WithOutputWriters(writers ...domain.OutputWriter) SyncOption {
+	return func(c *SyncCommand) {
+		c.outputWriters = append(c.outputWriters, writers...)
+	}
+}
+
+// WithTemplates renders templates into companion files for every context of the merged kubeconfig after
+// each sync.
+func WithTemplates(renderer domain.TemplateRenderer, templates []domain.TemplateSettings) SyncOption {
+	return func(c *SyncCommand) {
+		c.renderer = renderer
+		c.templates = templates
+	}
+}
+
+// WithHooks runs the pre-sync and post-sync hooks around each sync.
+func WithHooks(runner domain.HookRunner, hooks domain.HookSettings) SyncOption {
+	return func(c *SyncCommand) {
+		c.hookRunner = runner
+		c.hooks = hooks
+	}
+}
+
+// WithFragmentValidator leaves downloaded fragments that fail validation out of the merge, quarantines them
+// and reports them.
+func WithFragmentValidator(validator domain.FragmentValidator) SyncOption {
+	return func(c *SyncCommand) {
+		c.validator = validator
+	}
+}
+
+// WithTracer records spans for the sync and merge phases.
+func WithTracer(tracer domain.Tracer) SyncOption {
+	return func(c *SyncCommand) {
+		c.tracer = tracer
+	}
+}
+
+// WithNaming sanitizes the aliases suggested for colliding contexts under the naming policy.
+func WithNaming(naming domain.NamingSettings) SyncOption {
+	return func(c *SyncCommand) {
+		c.naming = naming
+	}
+}
+
+// WithContextLimits warns about, and unless forced refuses, merged kubeconfigs with more contexts than the
+// limits allow.
+func WithContextLimits(limits domain.LimitSettings) SyncOption {
+	return func(c *SyncCommand) {
+		c.limits = &limits
+	}
+}
+
+// WithSyncSettings adds the configured exclude patterns to those requested on every sync.
+func WithSyncSettings(settings domain.SyncSettings) SyncOption {
+	return func(c *SyncCommand) {
+		c.settings = settings
+	}
+}
+
 // NewSyncCommand creates a new sync command.
 func NewSyncCommand(
 	configRepo domain.ConfigRepository,
 	configProvider domain.ConfigProvider,
 	passwordReader domain.PasswordReader,
 	logger *slog.Logger,
+	opts ...SyncOption,
 ) *SyncCommand {
-	return &SyncCommand{
+	c := &SyncCommand{
 		configRepo:     configRepo,
 		configProvider: configProvider,
 		passwordReader: passwordReader,
+		tracer:         domain.NoopTracer{},
+		clock:          domain.SystemClock{},
 		logger:         logger,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // SyncRequest contains the parameters for the sync command.
@@ -40,87 +179,830 @@ type SyncRequest struct {
 	CleanupTempFiles bool
 	Verbose          bool
 	ExcludePatterns  []string
+	// ExcludeTypes drops clusters whose provider type (e.g. "harvester") matches, case-insensitively.
+	ExcludeTypes []string
+	// IgnoreBackoff syncs servers even if they are backing off after repeated failures.
+	IgnoreBackoff bool
+	// CachedOnly never prompts for passwords: servers without a valid cached token are skipped.
+	CachedOnly bool
+	// ExcludeServers holds the URLs or IDs of configured servers left out of this sync.
+	ExcludeServers []string
+	// Clusters, if set, are the only clusters synced. Only their servers are contacted and their
+	// clusters are not listed.
+	Clusters []domain.ClusterRef
+	// KeepExisting keeps the contexts already in the output kubeconfig, replacing only those synced.
+	KeepExisting bool
+	// Offline makes no network calls: the merged kubeconfig is rebuilt from the cached fragments of the
+	// servers synced.
+	Offline bool
+	// Force writes the merged kubeconfig even if it holds more contexts than the configured maximum.
+	Force bool
+	// MaxDuration, if set, bounds the time spent discovering clusters and downloading kubeconfigs. Once it
+	// elapses no further downloads are started, and the kubeconfigs downloaded are merged into the output,
+	// keeping the contexts it already holds for the other clusters.
+	MaxDuration time.Duration
+	// CheckEndpoints looks up the endpoint hosts of the merged kubeconfig and reports those that do not
+	// resolve from this machine.
+	CheckEndpoints bool
+	// AuthRetries is how many more times a password Rancher rejects is asked for while prompting
+	// interactively. Servers whose passwords are still rejected are skipped.
+	AuthRetries int
+}
+
+// DefaultAuthRetries is how many more times a rejected password is asked for unless requested otherwise.
+const DefaultAuthRetries = 2
+
+// SyncReport summarises a sync. Error is set only in persisted reports of failed syncs, and Failures lists every
+// kubeconfig download that failed, which the log only summarizes.
+type SyncReport struct {
+	CorrelationID         string                  `json:"correlationId"`
+	StartedAt             time.Time               `json:"startedAt"`
+	FinishedAt            time.Time               `json:"finishedAt"`
+	Output                string                  `json:"output"`
+	ClustersFound         int                     `json:"clustersFound"`
+	KubeconfigsDownloaded int                     `json:"kubeconfigsDownloaded"`
+	Servers               []ServerReport          `json:"servers,omitempty"`
+	Skipped               []SkippedServer         `json:"skipped,omitempty"`
+	Collisions            []ContextCollision      `json:"collisions,omitempty"`
+	Invalid               []InvalidFragment       `json:"invalid,omitempty"`
+	Generated             []string                `json:"generated,omitempty"`
+	Offline               bool                    `json:"offline,omitempty"`
+	Truncated             bool                    `json:"truncated,omitempty"`
+	Contexts              int                     `json:"contexts,omitempty"`
+	Warnings              []string                `json:"warnings,omitempty"`
+	Unresolved            []UnresolvedEndpoint    `json:"unresolved,omitempty"`
+	Failures              []domain.ClusterFailure `json:"failures,omitempty"`
+	Changes               *KubeconfigChanges      `json:"changes,omitempty"`
+	Timing                domain.SyncTiming       `json:"timing"`
+	Error                 string                  `json:"error,omitempty"`
+}
+
+// ServerReport is the outcome for one server. Clusters counts the clusters found, and Downloaded, Excluded,
+// Invalid and Failed count them by what happened to their kubeconfigs. SkippedLocal reports whether the
+// server's local cluster was left out of them.
+type ServerReport struct {
+	ServerURL    string                 `json:"serverUrl"`
+	ServerID     string                 `json:"serverId"`
+	Version      string                 `json:"version,omitempty"`
+	Clusters     int                    `json:"clusters"`
+	Error        string                 `json:"error,omitempty"`
+	Category     domain.FailureCategory `json:"category,omitempty"`
+	Downloaded   int                    `json:"downloaded"`
+	Excluded     int                    `json:"excluded"`
+	Invalid      int                    `json:"invalid,omitempty"`
+	Failed       int                    `json:"failed"`
+	Duration     time.Duration          `json:"duration"`
+	SkippedLocal bool                   `json:"skippedLocal,omitempty"`
+}
+
+// InvalidFragment is a downloaded kubeconfig left out of the merge because it failed validation.
+type InvalidFragment struct {
+	ServerURL string   `json:"serverUrl"`
+	Cluster   string   `json:"cluster"`
+	File      string   `json:"file"`
+	Problems  []string `json:"problems"`
+	// Quarantine is where the fragment was moved, unless quarantining it failed.
+	Quarantine string `json:"quarantine,omitempty"`
+}
+
+// SkippedServer is a configured server that a sync did not contact, and why.
+type SkippedServer struct {
+	ServerURL string `json:"serverUrl"`
+	ServerID  string `json:"serverId"`
+	Reason    string `json:"reason"`
+}
+
+// skip records server as skipped for reason.
+func skip(server domain.ConfigServer, reason string) SkippedServer {
+	return SkippedServer{ServerURL: server.URL, ServerID: server.ID(), Reason: reason}
+}
+
+// NoServersError reports a sync that stopped because every server was skipped. Skipped lists why each
+// server was.
+type NoServersError struct {
+	Message string
+	Skipped []SkippedServer
+}
+
+func (e *NoServersError) Error() string {
+	return e.Message
 }
 
 // Execute runs the sync command using the SyncOrchestrator for concurrent processing.
+// The report is nil if no servers are configured.
 func (c *SyncCommand) Execute(
 	ctx context.Context,
 	req SyncRequest,
 	syncOrchestrator domain.SyncOrchestrator,
 	kubeconfigHandler domain.KubeconfigHandler,
-) error {
+) (*SyncReport, error) {
+	correlationID := logging.NewCorrelationID()
+	ctx = logging.WithCorrelationID(ctx, correlationID)
+	ctx, span := c.tracer.Start(ctx, "cowpoke.sync")
+	defer span.End()
+	span.SetAttribute("cowpoke.correlation_id", correlationID)
+
+	start := time.Now()
+	report, events, err := c.execute(ctx, req, syncOrchestrator, kubeconfigHandler)
+	span.RecordError(err)
+	if err != nil {
+		failed := &SyncReport{
+			CorrelationID: correlationID,
+			StartedAt:     start,
+			FinishedAt:    time.Now(),
+			Error:         err.Error(),
+		}
+		var downloadErr *domain.DownloadError
+		if errors.As(err, &downloadErr) {
+			failed.Failures = downloadErr.Failures
+		}
+		var noServersErr *NoServersError
+		if errors.As(err, &noServersErr) {
+			failed.Skipped = noServersErr.Skipped
+		}
+		// An interrupted sync still records its report, but exits without running post-sync hooks
+		var interruptedErr *domain.InterruptedError
+		if errors.As(err, &interruptedErr) {
+			c.saveReport(context.WithoutCancel(ctx), failed)
+			return nil, err
+		}
+		c.saveReport(ctx, failed)
+		if hookErr := c.runPostSyncHooks(ctx, failed); hookErr != nil {
+			err = errors.Join(err, hookErr)
+		}
+		return nil, err
+	}
+	if report == nil {
+		return nil, nil
+	}
+
+	end := time.Now()
+	events = append(events, domain.SyncEvent{Phase: domain.PhaseSync, Start: start, End: end})
+	report.CorrelationID = correlationID
+	report.StartedAt, report.FinishedAt = start, end
+	report.Timing = metrics.Summarize(events)
+	c.saveReport(ctx, report)
+	if err := c.runPostSyncHooks(ctx, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// saveReport persists the report of the latest sync. Failures are logged, never fatal.
+func (c *SyncCommand) saveReport(ctx context.Context, report *SyncReport) {
+	if c.reportStore == nil {
+		return
+	}
+	if err := c.reportStore.Save(ctx, lastSyncState, report); err != nil {
+		c.logger.WarnContext(ctx, "Failed to save sync report", "error", err)
+	}
+}
+
+// indexContexts records the contexts of the merged kubeconfig in the context index, and the clusters of
+// the servers the sync discovered. Failures are logged, never fatal.
+func (c *SyncCommand) indexContexts(
+	ctx context.Context,
+	kubeconfigHandler domain.KubeconfigHandler,
+	path string,
+	result *domain.SyncResult,
+	discovered bool,
+) {
+	if c.indexStore == nil {
+		return
+	}
+	index, err := loadContextIndex(ctx, c.indexStore)
+	if err == nil {
+		var servers []domain.ServerSyncResult
+		if discovered {
+			servers = result.Servers
+		}
+		err = indexContexts(ctx, c.indexStore, kubeconfigHandler, path, discoveredClusters(index, servers))
+	}
+	if err != nil {
+		c.logger.WarnContext(ctx, "Failed to update context index", "error", err)
+	}
+}
+
+// execute performs the sync within the root span, returning the events used for the timing summary.
+func (c *SyncCommand) execute(
+	ctx context.Context,
+	req SyncRequest,
+	syncOrchestrator domain.SyncOrchestrator,
+	kubeconfigHandler domain.KubeconfigHandler,
+) (*SyncReport, []domain.SyncEvent, error) {
+	if err := c.runPreSyncHooks(ctx); err != nil {
+		return nil, nil, err
+	}
+
 	servers, err := c.configRepo.GetServers(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get servers: %w", err)
+		return nil, nil, fmt.Errorf("failed to get servers: %w", err)
 	}
 
 	if len(servers) == 0 {
 		c.logger.InfoContext(ctx, "No servers configured")
-		return nil
+		return nil, nil, nil
 	}
 
-	c.logger.InfoContext(ctx, "Starting concurrent sync for servers", "count", len(servers))
+	req, err = c.applyDefaults(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	// Collect passwords for all servers upfront
-	passwords, err := c.collectPasswords(ctx, servers)
+	// Check a remote output before downloading anything for it
+	destination, writer, err := c.remoteOutput(req)
 	if err != nil {
-		return fmt.Errorf("failed to collect passwords: %w", err)
+		return nil, nil, err
 	}
 
-	// Create cluster filter based on exclude patterns
-	var clusterFilter domain.ClusterFilter
-	if len(req.ExcludePatterns) > 0 {
-		c.logger.DebugContext(ctx, "Creating exclude filter",
-			"patterns", req.ExcludePatterns)
-		excludeFilter, filterErr := filter.NewExcludeFilter(req.ExcludePatterns, c.logger)
-		if filterErr != nil {
-			return fmt.Errorf("failed to create exclude filter: %w", filterErr)
+	var listed map[string][]domain.Cluster
+	if len(req.Clusters) > 0 {
+		servers, listed, err = listedClusters(servers, req.Clusters)
+		if err != nil {
+			return nil, nil, err
 		}
-		clusterFilter = excludeFilter
-		c.logger.DebugContext(ctx, "Created exclude filter")
-	} else {
+	}
+
+	activeServers, skipped, err := c.excludeServers(ctx, servers, req.ExcludeServers)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(activeServers) == 0 {
+		return nil, nil, &NoServersError{Message: "all servers are excluded from the sync", Skipped: skipped}
+	}
+
+	var passwords map[string]string
+	if !req.Offline {
+		activeServers, passwords, skipped, err = c.authenticate(ctx, req, syncOrchestrator, activeServers, skipped)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	clusterFilter, err := c.clusterFilter(ctx, req.ExcludePatterns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	syncResult, err := c.fetch(ctx, req, syncOrchestrator, activeServers, listed, passwords)
+	if err != nil {
+		return nil, nil, err
+	}
+	if syncResult.Truncated {
+		skipped = append(skipped, unsynced(activeServers, syncResult)...)
+	}
+
+	if err := interrupted(ctx, syncResult); err != nil {
+		return nil, nil, err
+	}
+
+	kubeconfigPaths, invalid, err := c.validSyncedPaths(ctx, syncResult)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report, mergeEvent, err := c.mergeKubeconfigs(ctx, req, kubeconfigHandler, syncResult, kubeconfigPaths,
+		clusterFilter, listed == nil, destination, writer)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.cleanup(ctx, req, kubeconfigHandler, syncResult, listed == nil)
+
+	c.logger.InfoContext(ctx, "Sync completed",
+		"output", report.Output)
+
+	report.Skipped = skipped
+	c.reportDownload(ctx, report, req, syncResult, kubeconfigPaths, invalid, clusterFilter)
+	return report, append(syncResult.Events, mergeEvent), nil
+}
+
+// reportDownload completes report with the outcome of the download, from which kubeconfigPaths were merged.
+func (c *SyncCommand) reportDownload(
+	ctx context.Context,
+	report *SyncReport,
+	req SyncRequest,
+	syncResult *domain.SyncResult,
+	kubeconfigPaths []string,
+	invalid []InvalidFragment,
+	clusterFilter domain.ClusterFilter,
+) {
+	report.ClustersFound = syncResult.TotalClustersFound
+	report.KubeconfigsDownloaded = len(syncResult.KubeconfigPaths)
+	report.Servers = serverReports(syncResult, kubeconfigPaths, invalid, clusterFilter)
+	report.Collisions = findCollisions(syncResult.Servers, kubeconfigPaths, clusterFilter, c.naming)
+	report.Invalid = invalid
+	report.Offline = req.Offline
+	report.Truncated = syncResult.Truncated
+	report.Failures = syncResult.Failures
+	if req.Offline {
+		report.KubeconfigsDownloaded = 0
+	}
+	for _, collision := range report.Collisions {
+		c.logger.InfoContext(ctx, "Cluster name exists on several servers",
+			"name", collision.Name,
+			"servers", len(collision.Contexts))
+	}
+}
+
+// fetch downloads the kubeconfigs of activeServers, or takes them from the fragment cache if the sync is
+// offline.
+func (c *SyncCommand) fetch(
+	ctx context.Context,
+	req SyncRequest,
+	syncOrchestrator domain.SyncOrchestrator,
+	activeServers []domain.ConfigServer,
+	listed map[string][]domain.Cluster,
+	passwords map[string]string,
+) (*domain.SyncResult, error) {
+	options := domain.SyncOptions{ExcludeTypes: req.ExcludeTypes}
+	if req.Offline {
+		return c.cachedResult(ctx, activeServers, listed, options)
+	}
+	return c.download(ctx, syncOrchestrator, activeServers, listed, passwords, options, req.MaxDuration)
+}
+
+// remoteOutput returns the destination of a remote output and the writer that publishes to it, or a nil
+// writer if the output is a local file.
+func (c *SyncCommand) remoteOutput(req SyncRequest) (*url.URL, domain.OutputWriter, error) {
+	destination, remote := domain.ParseOutputDestination(req.Output)
+	if !remote {
+		return nil, nil, nil
+	}
+	if req.Offline {
+		return nil, nil, fmt.Errorf("offline syncs cannot write to %s:// outputs", destination.Scheme)
+	}
+	if c.settings.ShareCAs {
+		return nil, nil, fmt.Errorf("shared CA files cannot be used with %s:// outputs", destination.Scheme)
+	}
+	writer, err := c.outputWriter(destination)
+	if err != nil {
+		return nil, nil, err
+	}
+	return destination, writer, nil
+}
+
+// clusterFilter creates the filter of the clusters matching the exclude patterns.
+func (c *SyncCommand) clusterFilter(ctx context.Context, patterns []string) (domain.ClusterFilter, error) {
+	if len(patterns) == 0 {
 		c.logger.DebugContext(ctx, "No exclude patterns, using no-op filter")
-		clusterFilter = filter.NewNoOpFilter()
+		return filter.NewNoOpFilter(), nil
 	}
+	c.logger.DebugContext(ctx, "Creating exclude filter",
+		"patterns", patterns)
+	excludeFilter, err := filter.NewExcludeFilter(patterns, c.logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exclude filter: %w", err)
+	}
+	c.logger.DebugContext(ctx, "Created exclude filter")
+	return excludeFilter, nil
+}
 
-	// Use SyncOrchestrator for concurrent processing (no filtering at this level)
-	syncResult, err := syncOrchestrator.SyncServers(ctx, servers, passwords)
+// validSyncedPaths returns the synced kubeconfigs that are neither excluded by server patterns nor invalid,
+// and the invalid ones. It fails if every synced kubeconfig is invalid.
+func (c *SyncCommand) validSyncedPaths(
+	ctx context.Context,
+	syncResult *domain.SyncResult,
+) ([]string, []InvalidFragment, error) {
+	kubeconfigPaths, err := c.excludeServerPatterns(ctx, syncResult, syncResult.KubeconfigPaths)
 	if err != nil {
-		return fmt.Errorf("concurrent sync failed: %w", err)
+		return nil, nil, err
+	}
+	kubeconfigPaths, invalid := c.validateFragments(ctx, syncResult, kubeconfigPaths)
+	if len(kubeconfigPaths) == 0 && len(invalid) > 0 {
+		return nil, nil, fmt.Errorf("all %d downloaded kubeconfigs are invalid", len(invalid))
 	}
+	return kubeconfigPaths, invalid, nil
+}
 
-	if len(syncResult.KubeconfigPaths) == 0 {
-		return errors.New("no kubeconfigs downloaded successfully")
+// mergeKubeconfigs merges kubeconfigPaths into the output and publishes it if it is remote. It returns the
+// report of the output, which the caller completes with the outcome of the download.
+func (c *SyncCommand) mergeKubeconfigs(
+	ctx context.Context,
+	req SyncRequest,
+	kubeconfigHandler domain.KubeconfigHandler,
+	syncResult *domain.SyncResult,
+	kubeconfigPaths []string,
+	clusterFilter domain.ClusterFilter,
+	discovered bool,
+	destination *url.URL,
+	writer domain.OutputWriter,
+) (*SyncReport, domain.SyncEvent, error) {
+	// Determine output path, merging into the local copy of a remote output
+	outputPath, err := resolveKubeconfig(c.configProvider, req.Output)
+	if err != nil {
+		return nil, domain.SyncEvent{}, err
 	}
 
-	// Determine output path
-	outputPath := req.Output
-	if outputPath == "" {
-		outputPath, err = c.configProvider.GetDefaultKubeconfigPath()
+	// A truncated sync keeps the contexts of the clusters it did not get to, and any sync those of the
+	// clusters whose downloads failed
+	mergePaths := kubeconfigPaths
+	if req.KeepExisting || syncResult.Truncated || len(syncResult.Failures) > 0 {
+		mergePaths, err = keepExisting(ctx, kubeconfigHandler, outputPath, kubeconfigPaths)
 		if err != nil {
-			return fmt.Errorf("failed to get default kubeconfig path: %w", err)
+			return nil, domain.SyncEvent{}, err
 		}
 	}
 
 	c.logger.DebugContext(ctx, "Merging kubeconfigs",
-		"count", len(syncResult.KubeconfigPaths),
+		"count", len(mergePaths),
 		"output", outputPath)
 
-	if mergeErr := kubeconfigHandler.MergeKubeconfigs(ctx, syncResult.KubeconfigPaths, outputPath, clusterFilter); mergeErr != nil {
-		return fmt.Errorf("failed to merge kubeconfigs: %w", mergeErr)
+	contexts, warning, err := c.checkContextLimit(ctx, kubeconfigHandler, mergePaths, clusterFilter, req.Force)
+	if err != nil {
+		return nil, domain.SyncEvent{}, err
 	}
 
-	// Cleanup temporary files if requested
-	if req.CleanupTempFiles {
+	// Nothing is written once the sync is interrupted
+	if err := interrupted(ctx, syncResult); err != nil {
+		return nil, domain.SyncEvent{}, err
+	}
+	mergeEvent := domain.SyncEvent{Phase: domain.PhaseMerge, Start: time.Now()}
+	before, snapshotted := c.snapshotContexts(ctx, kubeconfigHandler, outputPath)
+	if mergeErr := c.merge(ctx, kubeconfigHandler, mergePaths, outputPath, clusterFilter); mergeErr != nil {
+		return nil, domain.SyncEvent{}, fmt.Errorf("failed to merge kubeconfigs: %w", mergeErr)
+	}
+	report := &SyncReport{Output: outputPath, Contexts: contexts}
+	if warning != "" {
+		report.Warnings = append(report.Warnings, warning)
+	}
+	if after, ok := c.snapshotContexts(ctx, kubeconfigHandler, outputPath); ok && snapshotted {
+		report.Changes = diffContexts(before, after)
+		c.logger.InfoContext(ctx, "Kubeconfig changes",
+			"added", len(report.Changes.Added),
+			"removed", len(report.Changes.Removed),
+			"token_refreshed", len(report.Changes.TokenRefreshed),
+			"endpoint_changed", len(report.Changes.EndpointChanged))
+	}
+	if report.Generated, err = c.renderTemplates(ctx, kubeconfigHandler, outputPath); err != nil {
+		return nil, domain.SyncEvent{}, err
+	}
+	if req.CheckEndpoints && !req.Offline && c.resolver != nil {
+		report.Unresolved = c.checkEndpoints(ctx, kubeconfigHandler, outputPath)
+	}
+	if writer == nil {
+		c.indexContexts(ctx, kubeconfigHandler, outputPath, syncResult, discovered && !req.Offline)
+	} else {
+		if publishErr := c.publish(ctx, writer, destination, outputPath); publishErr != nil {
+			return nil, domain.SyncEvent{}, publishErr
+		}
+		report.Output = destination.Redacted()
+	}
+	mergeEvent.End = time.Now()
+	return report, mergeEvent, nil
+}
+
+// cleanup removes the temporary files of the sync if requested, or otherwise collects the fragments of
+// clusters that are gone. Offline syncs keep the fragments they were built from.
+func (c *SyncCommand) cleanup(
+	ctx context.Context,
+	req SyncRequest,
+	kubeconfigHandler domain.KubeconfigHandler,
+	syncResult *domain.SyncResult,
+	discovered bool,
+) {
+	switch {
+	case req.Offline:
+	case req.CleanupTempFiles:
 		if cleanupErr := kubeconfigHandler.CleanupTempFiles(ctx, syncResult.KubeconfigPaths); cleanupErr != nil {
 			c.logger.WarnContext(ctx, "Failed to cleanup some temporary files", "error", cleanupErr)
 		}
+	default:
+		c.collectGarbage(ctx, syncResult, discovered)
 	}
+}
 
-	c.logger.InfoContext(ctx, "Sync completed",
-		"output", outputPath)
+// authenticate prepares the active servers for a download, leaving out those backing off and, with
+// CachedOnly, those without a cached token, which it adds to skipped. It returns the passwords of the
+// servers that need one.
+func (c *SyncCommand) authenticate(
+	ctx context.Context,
+	req SyncRequest,
+	syncOrchestrator domain.SyncOrchestrator,
+	activeServers []domain.ConfigServer,
+	skipped []SkippedServer,
+) ([]domain.ConfigServer, map[string]string, []SkippedServer, error) {
+	if !req.IgnoreBackoff {
+		var backingOff []SkippedServer
+		activeServers, backingOff = c.skipBackingOff(ctx, activeServers)
+		skipped = append(skipped, backingOff...)
+		if len(activeServers) == 0 {
+			return nil, nil, skipped, &NoServersError{
+				Message: "all servers are backing off after repeated failures (use --ignore-backoff to retry now)",
+				Skipped: skipped,
+			}
+		}
+	}
+
+	// Servers with a cached token need no password, and those authenticating with an API token cannot use one
+	cachedServers, needPasswords := c.partitionByToken(ctx, activeServers)
+	needPasswords, missingTokens := partitionByAuthType(needPasswords)
+	for _, server := range missingTokens {
+		c.logger.WarnContext(ctx, "Skipping server without its API token", "server", server.URL)
+		skipped = append(skipped, skip(server, "API token not found; run cowpoke convert-to-token to create one"))
+	}
+	if len(missingTokens) > 0 {
+		activeServers = slices.DeleteFunc(slices.Clone(activeServers), func(server domain.ConfigServer) bool {
+			return slices.ContainsFunc(missingTokens, func(missing domain.ConfigServer) bool {
+				return missing.ID() == server.ID()
+			})
+		})
+		if len(activeServers) == 0 {
+			return nil, nil, skipped, &NoServersError{
+				Message: "no server has its API token (run cowpoke convert-to-token)",
+				Skipped: skipped,
+			}
+		}
+	}
+	if req.CachedOnly {
+		if c.tokenCache == nil {
+			return nil, nil, skipped, errors.New("cached tokens are not available")
+		}
+		for _, server := range needPasswords {
+			c.logger.WarnContext(ctx, "Skipping server without a cached token", "server", server.URL)
+			skipped = append(skipped, skip(server, "no valid cached token; interactive authentication required"))
+		}
+		activeServers, needPasswords = cachedServers, nil
+		if len(activeServers) == 0 {
+			return nil, nil, skipped, &NoServersError{
+				Message: "no server has a valid cached token (run cowpoke sync interactively to log in)",
+				Skipped: skipped,
+			}
+		}
+	}
+
+	c.logger.InfoContext(ctx, "Starting concurrent sync for servers", "count", len(activeServers))
 
-	return nil
+	// Collect passwords for all servers upfront
+	passwords, err := c.collectPasswords(ctx, needPasswords)
+	if err != nil {
+		return nil, nil, skipped, fmt.Errorf("failed to collect passwords: %w", err)
+	}
+
+	// Check the passwords before the sync starts, so a mistyped one is caught now rather than minutes in
+	rejected, err := c.preauthenticate(ctx, syncOrchestrator, needPasswords, passwords, req.AuthRetries)
+	if err != nil {
+		return nil, nil, skipped, err
+	}
+	if len(rejected) > 0 {
+		urls := make([]string, 0, len(rejected))
+		for _, server := range rejected {
+			urls = append(urls, server.URL)
+			skipped = append(skipped, skip(server, "authentication failed: wrong password"))
+		}
+		c.logger.WarnContext(ctx, "Skipping servers whose passwords were rejected", "servers", urls)
+		activeServers = slices.DeleteFunc(slices.Clone(activeServers), func(server domain.ConfigServer) bool {
+			return slices.ContainsFunc(rejected, func(bad domain.ConfigServer) bool {
+				return bad.ID() == server.ID()
+			})
+		})
+		if len(activeServers) == 0 {
+			return nil, nil, skipped, &NoServersError{
+				Message: "no server accepted its password",
+				Skipped: skipped,
+			}
+		}
+	}
+	return activeServers, passwords, skipped, nil
+}
+
+// preauthenticate logs in to the servers with their passwords before the sync starts, asking up to retries
+// more times for the passwords Rancher rejects while the terminal is interactive. It returns the servers
+// whose passwords were still rejected. Other login failures, such as an unreachable server, are left for
+// the sync to report.
+func (c *SyncCommand) preauthenticate(
+	ctx context.Context,
+	syncOrchestrator domain.SyncOrchestrator,
+	servers []domain.ConfigServer,
+	passwords map[string]string,
+	retries int,
+) ([]domain.ConfigServer, error) {
+	pending := servers
+	for attempt := 0; len(pending) > 0; attempt++ {
+		errs := syncOrchestrator.Preauthenticate(ctx, pending, passwords)
+		var rejected []domain.ConfigServer
+		for _, server := range pending {
+			if errors.Is(errs[server.ID()], domain.ErrUnauthorized) {
+				rejected = append(rejected, server)
+			}
+		}
+		if len(rejected) == 0 || attempt >= retries || !c.passwordReader.IsInteractive() {
+			return rejected, nil
+		}
+		for _, server := range rejected {
+			c.logger.WarnContext(ctx, "Password rejected, try again", "server", server.URL)
+			password, err := c.passwordReader.ReadPassword(ctx, fmt.Sprintf("Password for %s: ", server.URL))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read password for %s: %w", server.URL, err)
+			}
+			passwords[server.ID()] = password
+		}
+		pending = rejected
+	}
+	return nil, nil
+}
+
+// download downloads the kubeconfigs of the active servers, failing if none could be downloaded. With a
+// maxDuration, downloads stop once it elapses and the result is marked truncated.
+func (c *SyncCommand) download(
+	ctx context.Context,
+	syncOrchestrator domain.SyncOrchestrator,
+	activeServers []domain.ConfigServer,
+	listed map[string][]domain.Cluster,
+	passwords map[string]string,
+	options domain.SyncOptions,
+	maxDuration time.Duration,
+) (*domain.SyncResult, error) {
+	budgetCtx := ctx
+	if maxDuration > 0 {
+		var cancel context.CancelFunc
+		budgetCtx, cancel = context.WithTimeout(ctx, maxDuration)
+		defer cancel()
+	}
+
+	// Use SyncOrchestrator for concurrent processing (no filtering at this level)
+	syncResult, err := syncServers(budgetCtx, syncOrchestrator, activeServers, listed, passwords, options)
+	if err != nil {
+		return nil, fmt.Errorf("concurrent sync failed: %w", err)
+	}
+	// Servers failing because the sync was interrupted say nothing about their health
+	if syncResult.Interrupted && ctx.Err() != nil {
+		return syncResult, nil
+	}
+	if syncResult.Interrupted {
+		syncResult.Interrupted, syncResult.Truncated = false, true
+		c.logger.WarnContext(ctx, "Sync ran out of time, merging the kubeconfigs downloaded",
+			"max_duration", maxDuration,
+			"kubeconfigs", len(syncResult.KubeconfigPaths),
+			"clusters", syncResult.TotalClustersFound)
+		if len(syncResult.KubeconfigPaths) == 0 {
+			return nil, fmt.Errorf("no kubeconfigs downloaded within the maximum duration of %s", maxDuration)
+		}
+	}
+	c.recordHealth(ctx, syncResult)
+
+	if len(syncResult.KubeconfigPaths) == 0 {
+		var serverErrs []error
+		for _, server := range syncResult.Servers {
+			if server.Error != nil {
+				serverErrs = append(serverErrs, fmt.Errorf("%s: %w", server.Server.URL, server.Error))
+			}
+		}
+		if downloadErr := syncResult.DownloadErr(); downloadErr != nil {
+			serverErrs = append(serverErrs, downloadErr)
+		}
+		if len(serverErrs) > 0 {
+			return nil, fmt.Errorf("no kubeconfigs downloaded successfully: %w", errors.Join(serverErrs...))
+		}
+		return nil, errors.New("no kubeconfigs downloaded successfully")
+	}
+
+	return syncResult, nil
+}
+
+// unsynced reports the active servers that a truncated sync did not get to as skipped.
+func unsynced(activeServers []domain.ConfigServer, result *domain.SyncResult) []SkippedServer {
+	var skipped []SkippedServer
+	for _, server := range activeServers {
+		reached := slices.ContainsFunc(result.Servers, func(serverResult domain.ServerSyncResult) bool {
+			return serverResult.Server.URL == server.URL
+		})
+		if !reached {
+			skipped = append(skipped, skip(server, "out of time (--max-duration)"))
+		}
+	}
+	return skipped
+}
+
+// interrupted returns an InterruptedError describing what result downloaded if the sync was interrupted.
+func interrupted(ctx context.Context, result *domain.SyncResult) error {
+	if !result.Interrupted && ctx.Err() == nil {
+		return nil
+	}
+	return &domain.InterruptedError{
+		Downloaded: len(result.KubeconfigPaths),
+		Total:      result.TotalClustersFound,
+		Err:        cmp.Or(ctx.Err(), context.Canceled),
+	}
+}
+
+// cachedResult stands in for the download in offline syncs, returning the cached fragments of servers as if
+// they had just been downloaded. With a cluster list, only the fragments of the listed clusters are used.
+func (c *SyncCommand) cachedResult(
+	ctx context.Context,
+	servers []domain.ConfigServer,
+	listed map[string][]domain.Cluster,
+	options domain.SyncOptions,
+) (*domain.SyncResult, error) {
+	if c.fragmentCache == nil {
+		return nil, errors.New("offline syncs need the kubeconfig cache")
+	}
+	entries, err := c.fragmentCache.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cached kubeconfigs: %w", err)
+	}
+
+	result := &domain.SyncResult{}
+	for _, server := range servers {
+		serverID := server.ID()
+		serverResult := domain.ServerSyncResult{Server: server}
+		for _, entry := range entries {
+			if entry.ServerID != serverID {
+				continue
+			}
+			name := strings.TrimSuffix(entry.File, domain.FragmentFileName("", serverID))
+			if listed != nil && !slices.ContainsFunc(listed[serverID], func(cluster domain.Cluster) bool {
+				return cluster.Name == name || entry.ClusterID != "" && cluster.ID == entry.ClusterID
+			}) {
+				continue
+			}
+			cluster := domain.Cluster{ID: entry.ClusterID, Name: cmp.Or(entry.ClusterName, name), Type: entry.ClusterType}
+			if options.ExcludesType(cluster) {
+				serverResult.ExcludedByType = append(serverResult.ExcludedByType, cluster)
+				continue
+			}
+			serverResult.Clusters = append(serverResult.Clusters, cluster)
+			result.KubeconfigPaths = append(result.KubeconfigPaths, filepath.Join(c.fragmentCache.Dir(), entry.File))
+		}
+		result.TotalClustersFound += len(serverResult.Clusters)
+		result.Servers = append(result.Servers, serverResult)
+	}
+	if len(result.KubeconfigPaths) == 0 {
+		return nil, errors.New("no cached kubeconfigs for the servers synced (run cowpoke sync online first)")
+	}
+
+	c.logger.InfoContext(ctx, "Rebuilding kubeconfig from cached fragments",
+		"servers", len(servers),
+		"fragments", len(result.KubeconfigPaths))
+	return result, nil
+}
+
+// listedClusters returns the configured servers that refs name by URL or ID, in configuration order,
+// along with the clusters listed for each by server ID. Naming a server that is not configured is an error.
+func listedClusters(
+	servers []domain.ConfigServer,
+	refs []domain.ClusterRef,
+) ([]domain.ConfigServer, map[string][]domain.Cluster, error) {
+	listed := make(map[string][]domain.Cluster)
+	for _, ref := range refs {
+		server, err := domain.FindServer(servers, ref.Server)
+		if err != nil {
+			return nil, nil, err
+		}
+		if server == nil {
+			return nil, nil, fmt.Errorf("listed server %s is not configured", ref.Server)
+		}
+		listed[server.ID()] = append(listed[server.ID()], domain.Cluster{
+			ID:   ref.ClusterID,
+			Name: cmp.Or(ref.Name, ref.ClusterID),
+		})
+	}
+
+	selected := make([]domain.ConfigServer, 0, len(listed))
+	for _, server := range servers {
+		if _, ok := listed[server.ID()]; ok {
+			selected = append(selected, server)
+		}
+	}
+	return selected, listed, nil
+}
+
+// keepExisting puts the output kubeconfig ahead of the fragments to merge if it holds cowpoke contexts, so
+// that the contexts of clusters not synced are kept and those of the synced clusters are replaced.
+func keepExisting(
+	ctx context.Context,
+	kubeconfigHandler domain.KubeconfigHandler,
+	outputPath string,
+	paths []string,
+) ([]string, error) {
+	contexts, err := kubeconfigHandler.ListContexts(ctx, outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read existing kubeconfig: %w", err)
+	}
+	if len(contexts) == 0 {
+		return paths, nil
+	}
+	return append([]string{outputPath}, paths...), nil
+}
+
+// syncServers discovers and downloads the clusters of servers, or downloads only the listed clusters
+// if a list was given.
+func syncServers(
+	ctx context.Context,
+	syncOrchestrator domain.SyncOrchestrator,
+	servers []domain.ConfigServer,
+	listed map[string][]domain.Cluster,
+	passwords map[string]string,
+	options domain.SyncOptions,
+) (*domain.SyncResult, error) {
+	if listed == nil {
+		return syncOrchestrator.SyncServers(ctx, servers, passwords, options)
+	}
+
+	targets := make([]domain.ServerClusters, 0, len(servers))
+	for _, server := range servers {
+		targets = append(targets, domain.ServerClusters{Server: server, Clusters: listed[server.ID()]})
+	}
+	return syncOrchestrator.SyncClusters(ctx, targets, passwords, options)
 }
 
 // collectPasswords prompts for passwords for all servers upfront.
@@ -140,3 +1022,421 @@ func (c *SyncCommand) collectPasswords(ctx context.Context, servers []domain.Con
 
 	return passwords, nil
 }
+
+// applyDefaults fills in options from the active profile's defaults and the sync settings. The default
+// output is used only when none was requested, while default and configured exclusions are added to those
+// requested.
+func (c *SyncCommand) applyDefaults(ctx context.Context, req SyncRequest) (SyncRequest, error) {
+	defaults, err := c.configRepo.GetDefaults(ctx)
+	if err != nil {
+		return req, fmt.Errorf("failed to get profile defaults: %w", err)
+	}
+
+	req.Output = cmp.Or(req.Output, defaults.Output)
+	req.ExcludePatterns = append(slices.Clip(req.ExcludePatterns), defaults.Exclude...)
+	req.ExcludePatterns = append(req.ExcludePatterns, c.settings.ExcludePatterns...)
+	req.CheckEndpoints = req.CheckEndpoints || c.settings.CheckEndpoints
+	req.ExcludeTypes = append(slices.Clip(req.ExcludeTypes), defaults.ExcludeTypes...)
+	return req, nil
+}
+
+// serverReports summarises each server's outcome: how many of its clusters were found, downloaded, left
+// out of the merged kubeconfig by type or name, invalid, and failed to download, and how long the server took.
+func serverReports(
+	result *domain.SyncResult,
+	mergedPaths []string,
+	invalid []InvalidFragment,
+	clusterFilter domain.ClusterFilter,
+) []ServerReport {
+	downloaded := fileNames(result.KubeconfigPaths)
+	merged := fileNames(mergedPaths)
+	invalidFiles := make(map[string]bool, len(invalid))
+	for _, fragment := range invalid {
+		invalidFiles[fragment.File] = true
+	}
+
+	reports := make([]ServerReport, 0, len(result.Servers))
+	for _, serverResult := range result.Servers {
+		report := ServerReport{
+			ServerURL:    serverResult.Server.URL,
+			ServerID:     serverResult.Server.ID(),
+			Version:      serverResult.Version,
+			Clusters:     len(serverResult.Clusters) + len(serverResult.ExcludedByType),
+			Excluded:     len(serverResult.ExcludedByType),
+			SkippedLocal: serverResult.SkippedLocal,
+		}
+		if serverResult.Error != nil {
+			report.Error = serverResult.Error.Error()
+			report.Category = domain.ClassifyFailure(serverResult.Error)
+		}
+
+		for _, failure := range result.Failures {
+			if failure.ServerURL == serverResult.Server.URL {
+				report.Failed++
+			}
+		}
+
+		serverID := serverResult.Server.ID()
+		for _, cluster := range serverResult.Clusters {
+			file := domain.FragmentFileName(cluster.Name, serverID)
+			if !downloaded[file] {
+				continue
+			}
+			report.Downloaded++
+			if invalidFiles[file] {
+				report.Invalid++
+				continue
+			}
+			if !merged[file] || clusterFilter.ShouldExclude(contextName(cluster.Name, serverID)) {
+				report.Excluded++
+			}
+		}
+
+		var start, end time.Time
+		for _, event := range result.Events {
+			if event.ServerURL != serverResult.Server.URL {
+				continue
+			}
+			if start.IsZero() || event.Start.Before(start) {
+				start = event.Start
+			}
+			if event.End.After(end) {
+				end = event.End
+			}
+		}
+		report.Duration = end.Sub(start)
+
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// fileNames returns the base names of paths.
+func fileNames(paths []string) map[string]bool {
+	names := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		names[filepath.Base(path)] = true
+	}
+	return names
+}
+
+// partitionByToken splits servers into those with a valid cached token and those that need a password.
+func (c *SyncCommand) partitionByToken(
+	ctx context.Context,
+	servers []domain.ConfigServer,
+) ([]domain.ConfigServer, []domain.ConfigServer) {
+	if c.tokenCache == nil {
+		return nil, servers
+	}
+
+	var cached, uncached []domain.ConfigServer
+	for _, server := range servers {
+		token, err := c.tokenCache.Get(ctx, server)
+		if err != nil {
+			c.logger.DebugContext(ctx, "Could not read cached token", "server", server.URL, "error", err)
+		}
+		if token != nil {
+			cached = append(cached, server)
+		} else {
+			uncached = append(uncached, server)
+		}
+	}
+	return cached, uncached
+}
+
+// partitionByAuthType splits servers into those that log in with a password and those that authenticate
+// with an API token.
+func partitionByAuthType(servers []domain.ConfigServer) ([]domain.ConfigServer, []domain.ConfigServer) {
+	var password, token []domain.ConfigServer
+	for _, server := range servers {
+		if server.AuthType == domain.AuthTypeToken {
+			token = append(token, server)
+		} else {
+			password = append(password, server)
+		}
+	}
+	return password, token
+}
+
+// collectGarbage removes fragments for servers and clusters that no longer exist or have gone stale.
+// Servers of every profile count as configured so that syncing one profile keeps the others' fragments.
+// Clusters are only known not to exist if the servers' clusters were discovered.
+func (c *SyncCommand) collectGarbage(ctx context.Context, result *domain.SyncResult, discovered bool) {
+	if c.fragmentCache == nil {
+		return
+	}
+
+	servers, err := c.configRepo.GetAllServers(ctx)
+	if err != nil {
+		c.logger.WarnContext(ctx, "Skipping fragment cleanup", "error", err)
+		return
+	}
+
+	settings, err := c.configRepo.GetSettings(ctx)
+	if err != nil {
+		c.logger.WarnContext(ctx, "Skipping fragment cleanup", "error", err)
+		return
+	}
+
+	policy := domain.CleanupPolicy{
+		MaxAge:              settings.Fragments.Retention(),
+		ConfiguredServerIDs: make([]string, 0, len(servers)),
+	}
+	for _, server := range servers {
+		policy.ConfiguredServerIDs = append(policy.ConfiguredServerIDs, server.ID())
+	}
+	for _, serverResult := range result.Servers {
+		if serverResult.Error != nil || !discovered {
+			continue
+		}
+		serverID := serverResult.Server.ID()
+		policy.SyncedServerIDs = append(policy.SyncedServerIDs, serverID)
+		for _, cluster := range serverResult.Clusters {
+			policy.LiveFragments = append(policy.LiveFragments, domain.FragmentFileName(cluster.Name, serverID))
+		}
+	}
+
+	if _, cleanErr := c.fragmentCache.Clean(ctx, policy); cleanErr != nil {
+		c.logger.WarnContext(ctx, "Failed to clean fragment cache", "error", cleanErr)
+	}
+}
+
+// excludeServers returns the servers not named by URL or ID in excluded, and reports the others as
+// skipped. Naming a server that is not configured is an error.
+func (c *SyncCommand) excludeServers(
+	ctx context.Context,
+	servers []domain.ConfigServer,
+	excluded []string,
+) ([]domain.ConfigServer, []SkippedServer, error) {
+	if len(excluded) == 0 {
+		return servers, nil, nil
+	}
+
+	excludedIDs := make(map[string]bool, len(excluded))
+	for _, name := range excluded {
+		server, err := domain.FindServer(servers, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if server == nil {
+			return nil, nil, fmt.Errorf("excluded server %s is not configured", name)
+		}
+		excludedIDs[server.ID()] = true
+	}
+
+	active := make([]domain.ConfigServer, 0, len(servers))
+	var skipped []SkippedServer
+	for _, server := range servers {
+		if !excludedIDs[server.ID()] {
+			active = append(active, server)
+			continue
+		}
+		c.logger.InfoContext(ctx, "Excluding server from sync", "server", server.URL)
+		skipped = append(skipped, skip(server, "excluded from this sync"))
+	}
+	return active, skipped, nil
+}
+
+// skipBackingOff returns the servers that are not currently backing off after repeated failures, and
+// those that are as skipped.
+func (c *SyncCommand) skipBackingOff(
+	ctx context.Context,
+	servers []domain.ConfigServer,
+) ([]domain.ConfigServer, []SkippedServer) {
+	if c.healthTracker == nil {
+		return servers, nil
+	}
+
+	now := c.clock.Now()
+	active := make([]domain.ConfigServer, 0, len(servers))
+	var skipped []SkippedServer
+	for _, server := range servers {
+		health, err := c.healthTracker.Get(ctx, server)
+		if err != nil {
+			c.logger.WarnContext(ctx, "Failed to read server health", "server", server.URL, "error", err)
+			active = append(active, server)
+			continue
+		}
+		if health.BackingOff(now) {
+			c.logger.WarnContext(ctx, "Skipping server after repeated failures",
+				"server", server.URL,
+				"consecutive_failures", health.ConsecutiveFailures,
+				"retry_after", health.NextAttempt.Format(time.RFC3339),
+				"last_error", health.LastError)
+			skipped = append(skipped, skip(server, fmt.Sprintf(
+				"backing off after %d consecutive failures until %s (use --ignore-backoff to retry now)",
+				health.ConsecutiveFailures, health.NextAttempt.Format(time.RFC3339))))
+			continue
+		}
+		active = append(active, server)
+	}
+	return active, skipped
+}
+
+// recordHealth records the per-server outcome with the health tracker. A server fails if its discovery
+// failed or every one of its kubeconfig downloads did.
+func (c *SyncCommand) recordHealth(ctx context.Context, result *domain.SyncResult) {
+	if c.healthTracker == nil || result == nil {
+		return
+	}
+
+	for _, serverResult := range result.Servers {
+		// Servers cut off by the time budget of a truncated sync did not fail
+		if result.Truncated && errors.Is(serverResult.Error, context.DeadlineExceeded) {
+			continue
+		}
+		cause := serverResult.Error
+		if cause == nil {
+			cause = downloadFailure(serverResult, result.Failures)
+		}
+		var err error
+		if cause != nil {
+			err = c.healthTracker.RecordFailure(ctx, serverResult.Server, cause)
+		} else {
+			err = c.healthTracker.RecordSuccess(ctx, serverResult.Server)
+		}
+		if err != nil {
+			c.logger.WarnContext(ctx, "Failed to record server health", "server", serverResult.Server.URL, "error", err)
+		}
+	}
+}
+
+// downloadFailure returns a DownloadError if every kubeconfig download of a server failed, otherwise nil.
+func downloadFailure(serverResult domain.ServerSyncResult, failures []domain.ClusterFailure) error {
+	var failed []domain.ClusterFailure
+	for _, failure := range failures {
+		if failure.ServerURL == serverResult.Server.URL {
+			failed = append(failed, failure)
+		}
+	}
+	if len(failed) == 0 || len(failed) < len(serverResult.Clusters) {
+		return nil
+	}
+	return &domain.DownloadError{Failures: failed, Total: len(serverResult.Clusters)}
+}
+
+// merge merges the downloaded fragments into the output kubeconfig within a tracing span.
+func (c *SyncCommand) merge(
+	ctx context.Context,
+	kubeconfigHandler domain.KubeconfigHandler,
+	paths []string,
+	outputPath string,
+	clusterFilter domain.ClusterFilter,
+) error {
+	ctx, span := c.tracer.Start(ctx, "kubeconfig.merge")
+	defer span.End()
+	span.SetAttribute("kubeconfig.fragments", len(paths))
+	span.SetAttribute("kubeconfig.output", outputPath)
+
+	err := kubeconfigHandler.MergeKubeconfigs(ctx, paths, outputPath, clusterFilter)
+	span.RecordError(err)
+	return err
+}
+
+// checkContextLimit counts the contexts merging paths would write, failing if there are more than the
+// configured maximum unless forced, and returning a warning if there are more than the warning threshold.
+// Without limits, nothing is counted and it returns zero.
+func (c *SyncCommand) checkContextLimit(
+	ctx context.Context,
+	kubeconfigHandler domain.KubeconfigHandler,
+	paths []string,
+	clusterFilter domain.ClusterFilter,
+	force bool,
+) (int, string, error) {
+	if c.limits == nil {
+		return 0, "", nil
+	}
+
+	count := kubeconfigHandler.CountContexts(ctx, paths, clusterFilter)
+	if maxContexts := c.limits.Max(); maxContexts > 0 && count > maxContexts && !force {
+		return count, "", fmt.Errorf("the merged kubeconfig would hold %d contexts, more than the limit of %d "+
+			"(exclude clusters, or use --force to write it anyway)", count, maxContexts)
+	}
+	if warn := c.limits.Warn(); warn > 0 && count > warn {
+		c.logger.WarnContext(ctx, "Merged kubeconfig holds many contexts", "contexts", count, "threshold", warn)
+		return count, fmt.Sprintf("The merged kubeconfig holds %d contexts, more than the %d that tools parsing it "+
+			"cope with well; consider excluding clusters", count, warn), nil
+	}
+	return count, "", nil
+}
+
+// validateFragments returns the fragments that passed validation, and reports and quarantines the others.
+// Every fragment passes if no validator is configured.
+func (c *SyncCommand) validateFragments(
+	ctx context.Context,
+	result *domain.SyncResult,
+	paths []string,
+) ([]string, []InvalidFragment) {
+	if c.validator == nil {
+		return paths, nil
+	}
+
+	valid := make([]string, 0, len(paths))
+	var invalid []InvalidFragment
+	for _, path := range paths {
+		problems := c.validator.ValidateFragment(ctx, path)
+		if len(problems) == 0 {
+			valid = append(valid, path)
+			continue
+		}
+
+		fragment := InvalidFragment{File: filepath.Base(path), Problems: problems}
+		for _, serverResult := range result.Servers {
+			for _, cluster := range serverResult.Clusters {
+				if domain.FragmentFileName(cluster.Name, serverResult.Server.ID()) == fragment.File {
+					fragment.ServerURL, fragment.Cluster = serverResult.Server.URL, cluster.Name
+				}
+			}
+		}
+		c.logger.WarnContext(ctx, "Leaving invalid kubeconfig out of the merge",
+			"server", fragment.ServerURL,
+			"cluster", fragment.Cluster,
+			"problems", strings.Join(problems, "; "))
+		quarantined, err := c.validator.QuarantineFragment(ctx, path, problems)
+		if err != nil {
+			c.logger.WarnContext(ctx, "Failed to quarantine invalid kubeconfig", "path", path, "error", err)
+		}
+		fragment.Quarantine = quarantined
+		invalid = append(invalid, fragment)
+	}
+	return valid, invalid
+}
+
+// excludeServerPatterns returns paths minus the fragments of clusters whose context name matches an exclude
+// pattern configured on their own server.
+func (c *SyncCommand) excludeServerPatterns(
+	ctx context.Context,
+	result *domain.SyncResult,
+	paths []string,
+) ([]string, error) {
+	excluded := make(map[string]bool)
+	for _, serverResult := range result.Servers {
+		patterns := serverResult.Server.Sync.ExcludePatterns
+		if len(patterns) == 0 {
+			continue
+		}
+		serverFilter, err := filter.NewExcludeFilter(patterns, c.logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create exclude filter for %s: %w", serverResult.Server.URL, err)
+		}
+		serverID := serverResult.Server.ID()
+		for _, cluster := range serverResult.Clusters {
+			if serverFilter.ShouldExclude(contextName(cluster.Name, serverID)) {
+				c.logger.DebugContext(ctx, "Excluding cluster by server pattern",
+					"cluster", cluster.Name, "server", serverResult.Server.URL)
+				excluded[domain.FragmentFileName(cluster.Name, serverID)] = true
+			}
+		}
+	}
+	if len(excluded) == 0 {
+		return paths, nil
+	}
+
+	kept := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if !excluded[filepath.Base(path)] {
+			kept = append(kept, path)
+		}
+	}
+	return kept, nil
+}
@@ -2,8 +2,12 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/url"
 	"testing"
+	"time"
 
 	"cowpoke/internal/domain"
 	"cowpoke/internal/mocks"
@@ -23,13 +27,32 @@ func newTestSyncCommand(
 	return NewSyncCommand(repo, provider, reader, testutil.Logger())
 }
 
+// newMockKubeconfigHandler returns a kubeconfig handler mock whose merged kubeconfig has no contexts to
+// compare before and after a sync.
+func newMockKubeconfigHandler(t *testing.T) *mocks.MockKubeconfigHandler {
+	t.Helper()
+	kubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+	kubeconfigHandler.On("SnapshotContexts", mock.Anything, mock.Anything).
+		Return([]domain.ContextSnapshot(nil), nil).Maybe()
+	return kubeconfigHandler
+}
+
+// newMockSyncOrchestrator returns a sync orchestrator mock accepting every password checked before a sync.
+func newMockSyncOrchestrator(t *testing.T) *mocks.MockSyncOrchestrator {
+	t.Helper()
+	syncOrchestrator := mocks.NewMockSyncOrchestrator(t)
+	syncOrchestrator.On("Preauthenticate", mock.Anything, mock.Anything, mock.Anything).
+		Return(map[string]error{}).Maybe()
+	return syncOrchestrator
+}
+
 func TestSyncCommand_Execute_NoServersConfigured(t *testing.T) {
 	// Arrange
 	mockConfigRepo := mocks.NewMockConfigRepository(t)
 	mockConfigProvider := mocks.NewMockConfigProvider(t)
 	mockPasswordReader := mocks.NewMockPasswordReader(t)
-	mockSyncOrchestrator := mocks.NewMockSyncOrchestrator(t)
-	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
 
 	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{}, nil)
 
@@ -38,7 +61,7 @@ func TestSyncCommand_Execute_NoServersConfigured(t *testing.T) {
 	req := SyncRequest{}
 
 	// Act
-	err := cmd.Execute(ctx, req, mockSyncOrchestrator, mockKubeconfigHandler)
+	_, err := cmd.Execute(ctx, req, mockSyncOrchestrator, mockKubeconfigHandler)
 
 	// Assert
 	require.NoError(t, err)
@@ -50,8 +73,8 @@ func TestSyncCommand_Execute_GetServersFails(t *testing.T) {
 	mockConfigRepo := mocks.NewMockConfigRepository(t)
 	mockConfigProvider := mocks.NewMockConfigProvider(t)
 	mockPasswordReader := mocks.NewMockPasswordReader(t)
-	mockSyncOrchestrator := mocks.NewMockSyncOrchestrator(t)
-	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
 
 	expectedErr := errors.New("config read error")
 	mockConfigRepo.On("GetServers", mock.Anything).Return(nil, expectedErr)
@@ -61,7 +84,7 @@ func TestSyncCommand_Execute_GetServersFails(t *testing.T) {
 	req := SyncRequest{}
 
 	// Act
-	err := cmd.Execute(ctx, req, mockSyncOrchestrator, mockKubeconfigHandler)
+	_, err := cmd.Execute(ctx, req, mockSyncOrchestrator, mockKubeconfigHandler)
 
 	// Assert
 	require.Error(t, err)
@@ -75,8 +98,8 @@ func TestSyncCommand_Execute_PasswordCollectionFails(t *testing.T) {
 	mockConfigRepo := mocks.NewMockConfigRepository(t)
 	mockConfigProvider := mocks.NewMockConfigProvider(t)
 	mockPasswordReader := mocks.NewMockPasswordReader(t)
-	mockSyncOrchestrator := mocks.NewMockSyncOrchestrator(t)
-	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
 
 	servers := []domain.ConfigServer{
 		{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"},
@@ -84,6 +107,7 @@ func TestSyncCommand_Execute_PasswordCollectionFails(t *testing.T) {
 	expectedErr := errors.New("password read error")
 
 	mockConfigRepo.On("GetServers", mock.Anything).Return(servers, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
 	mockPasswordReader.On("ReadPassword", mock.Anything, mock.AnythingOfType("string")).Return("", expectedErr)
 
 	cmd := newTestSyncCommand(mockConfigRepo, mockConfigProvider, mockPasswordReader)
@@ -91,7 +115,7 @@ func TestSyncCommand_Execute_PasswordCollectionFails(t *testing.T) {
 	req := SyncRequest{}
 
 	// Act
-	err := cmd.Execute(ctx, req, mockSyncOrchestrator, mockKubeconfigHandler)
+	_, err := cmd.Execute(ctx, req, mockSyncOrchestrator, mockKubeconfigHandler)
 
 	// Assert
 	require.Error(t, err)
@@ -106,14 +130,15 @@ func TestSyncCommand_Execute_InvalidExcludePattern(t *testing.T) {
 	mockConfigRepo := mocks.NewMockConfigRepository(t)
 	mockConfigProvider := mocks.NewMockConfigProvider(t)
 	mockPasswordReader := mocks.NewMockPasswordReader(t)
-	mockSyncOrchestrator := mocks.NewMockSyncOrchestrator(t)
-	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
 
 	servers := []domain.ConfigServer{
 		{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"},
 	}
 
 	mockConfigRepo.On("GetServers", mock.Anything).Return(servers, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
 	mockPasswordReader.On("ReadPassword", mock.Anything, mock.AnythingOfType("string")).Return("password123", nil)
 
 	cmd := newTestSyncCommand(mockConfigRepo, mockConfigProvider, mockPasswordReader)
@@ -123,7 +148,7 @@ func TestSyncCommand_Execute_InvalidExcludePattern(t *testing.T) {
 	}
 
 	// Act
-	err := cmd.Execute(ctx, req, mockSyncOrchestrator, mockKubeconfigHandler)
+	_, err := cmd.Execute(ctx, req, mockSyncOrchestrator, mockKubeconfigHandler)
 
 	// Assert
 	require.Error(t, err)
@@ -137,8 +162,8 @@ func TestSyncCommand_Execute_SyncFails(t *testing.T) {
 	mockConfigRepo := mocks.NewMockConfigRepository(t)
 	mockConfigProvider := mocks.NewMockConfigProvider(t)
 	mockPasswordReader := mocks.NewMockPasswordReader(t)
-	mockSyncOrchestrator := mocks.NewMockSyncOrchestrator(t)
-	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
 
 	servers := []domain.ConfigServer{
 		{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"},
@@ -146,8 +171,9 @@ func TestSyncCommand_Execute_SyncFails(t *testing.T) {
 	expectedErr := errors.New("sync orchestrator error")
 
 	mockConfigRepo.On("GetServers", mock.Anything).Return(servers, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
 	mockPasswordReader.On("ReadPassword", mock.Anything, mock.AnythingOfType("string")).Return("password123", nil)
-	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything).
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(nil, expectedErr)
 
 	cmd := newTestSyncCommand(mockConfigRepo, mockConfigProvider, mockPasswordReader)
@@ -155,7 +181,7 @@ func TestSyncCommand_Execute_SyncFails(t *testing.T) {
 	req := SyncRequest{}
 
 	// Act
-	err := cmd.Execute(ctx, req, mockSyncOrchestrator, mockKubeconfigHandler)
+	_, err := cmd.Execute(ctx, req, mockSyncOrchestrator, mockKubeconfigHandler)
 
 	// Assert
 	require.Error(t, err)
@@ -171,16 +197,17 @@ func TestSyncCommand_Execute_NoKubeconfigsDownloaded(t *testing.T) {
 	mockConfigRepo := mocks.NewMockConfigRepository(t)
 	mockConfigProvider := mocks.NewMockConfigProvider(t)
 	mockPasswordReader := mocks.NewMockPasswordReader(t)
-	mockSyncOrchestrator := mocks.NewMockSyncOrchestrator(t)
-	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
 
 	servers := []domain.ConfigServer{
 		{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"},
 	}
 
 	mockConfigRepo.On("GetServers", mock.Anything).Return(servers, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
 	mockPasswordReader.On("ReadPassword", mock.Anything, mock.AnythingOfType("string")).Return("password123", nil)
-	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything).
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(&domain.SyncResult{
 			KubeconfigPaths:    []string{},
 			TotalClustersFound: 0,
@@ -191,7 +218,7 @@ func TestSyncCommand_Execute_NoKubeconfigsDownloaded(t *testing.T) {
 	req := SyncRequest{}
 
 	// Act
-	err := cmd.Execute(ctx, req, mockSyncOrchestrator, mockKubeconfigHandler)
+	_, err := cmd.Execute(ctx, req, mockSyncOrchestrator, mockKubeconfigHandler)
 
 	// Assert
 	require.Error(t, err)
@@ -206,8 +233,8 @@ func TestSyncCommand_Execute_DefaultOutputPath(t *testing.T) {
 	mockConfigRepo := mocks.NewMockConfigRepository(t)
 	mockConfigProvider := mocks.NewMockConfigProvider(t)
 	mockPasswordReader := mocks.NewMockPasswordReader(t)
-	mockSyncOrchestrator := mocks.NewMockSyncOrchestrator(t)
-	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
 
 	servers := []domain.ConfigServer{
 		{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"},
@@ -216,8 +243,9 @@ func TestSyncCommand_Execute_DefaultOutputPath(t *testing.T) {
 	defaultPath := "/home/user/.kube/config"
 
 	mockConfigRepo.On("GetServers", mock.Anything).Return(servers, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
 	mockPasswordReader.On("ReadPassword", mock.Anything, mock.AnythingOfType("string")).Return("password123", nil)
-	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything).
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(&domain.SyncResult{
 			KubeconfigPaths:    kubeconfigPaths,
 			TotalClustersFound: 2,
@@ -231,10 +259,15 @@ func TestSyncCommand_Execute_DefaultOutputPath(t *testing.T) {
 	req := SyncRequest{} // No output specified, should use default
 
 	// Act
-	err := cmd.Execute(ctx, req, mockSyncOrchestrator, mockKubeconfigHandler)
+	report, err := cmd.Execute(ctx, req, mockSyncOrchestrator, mockKubeconfigHandler)
 
 	// Assert
 	require.NoError(t, err)
+	require.NotNil(t, report)
+	assert.Equal(t, defaultPath, report.Output)
+	assert.Equal(t, 2, report.ClustersFound)
+	assert.Equal(t, 2, report.KubeconfigsDownloaded)
+	assert.Positive(t, report.Timing.Total)
 	mockConfigRepo.AssertExpectations(t)
 	mockPasswordReader.AssertExpectations(t)
 	mockSyncOrchestrator.AssertExpectations(t)
@@ -247,8 +280,8 @@ func TestSyncCommand_Execute_CustomOutputPath(t *testing.T) {
 	mockConfigRepo := mocks.NewMockConfigRepository(t)
 	mockConfigProvider := mocks.NewMockConfigProvider(t)
 	mockPasswordReader := mocks.NewMockPasswordReader(t)
-	mockSyncOrchestrator := mocks.NewMockSyncOrchestrator(t)
-	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
 
 	servers := []domain.ConfigServer{
 		{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"},
@@ -257,8 +290,9 @@ func TestSyncCommand_Execute_CustomOutputPath(t *testing.T) {
 	customPath := "/custom/path/kubeconfig"
 
 	mockConfigRepo.On("GetServers", mock.Anything).Return(servers, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
 	mockPasswordReader.On("ReadPassword", mock.Anything, mock.AnythingOfType("string")).Return("password123", nil)
-	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything).
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(&domain.SyncResult{
 			KubeconfigPaths:    kubeconfigPaths,
 			TotalClustersFound: 2,
@@ -273,7 +307,7 @@ func TestSyncCommand_Execute_CustomOutputPath(t *testing.T) {
 	}
 
 	// Act
-	err := cmd.Execute(ctx, req, mockSyncOrchestrator, mockKubeconfigHandler)
+	_, err := cmd.Execute(ctx, req, mockSyncOrchestrator, mockKubeconfigHandler)
 
 	// Assert
 	require.NoError(t, err)
@@ -283,13 +317,53 @@ func TestSyncCommand_Execute_CustomOutputPath(t *testing.T) {
 	mockKubeconfigHandler.AssertExpectations(t)
 }
 
+func TestSyncCommand_Execute_AppliesProfileDefaults(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigProvider := mocks.NewMockConfigProvider(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
+
+	servers := []domain.ConfigServer{
+		{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"},
+	}
+	kubeconfigPaths := []string{"/tmp/cluster1.yaml"}
+	profilePath := "/home/user/.kube/work"
+
+	mockConfigRepo.On("GetServers", mock.Anything).Return(servers, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{
+		Output:  profilePath,
+		Exclude: []string{"^sandbox-"},
+	}, nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, mock.AnythingOfType("string")).Return("password123", nil)
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&domain.SyncResult{
+			KubeconfigPaths:    kubeconfigPaths,
+			TotalClustersFound: 1,
+		}, nil)
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, kubeconfigPaths, profilePath,
+		mock.AnythingOfType("*filter.ExcludeFilter")).
+		Return(nil)
+
+	cmd := newTestSyncCommand(mockConfigRepo, mockConfigProvider, mockPasswordReader)
+
+	// Act
+	report, err := cmd.Execute(context.Background(), SyncRequest{}, mockSyncOrchestrator, mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, profilePath, report.Output)
+	mockConfigProvider.AssertNotCalled(t, "GetDefaultKubeconfigPath")
+}
+
 func TestSyncCommand_Execute_WithCleanupTempFiles(t *testing.T) {
 	// Arrange
 	mockConfigRepo := mocks.NewMockConfigRepository(t)
 	mockConfigProvider := mocks.NewMockConfigProvider(t)
 	mockPasswordReader := mocks.NewMockPasswordReader(t)
-	mockSyncOrchestrator := mocks.NewMockSyncOrchestrator(t)
-	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
 
 	servers := []domain.ConfigServer{
 		{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"},
@@ -298,8 +372,9 @@ func TestSyncCommand_Execute_WithCleanupTempFiles(t *testing.T) {
 	customPath := "/custom/path/kubeconfig"
 
 	mockConfigRepo.On("GetServers", mock.Anything).Return(servers, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
 	mockPasswordReader.On("ReadPassword", mock.Anything, mock.AnythingOfType("string")).Return("password123", nil)
-	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything).
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(&domain.SyncResult{
 			KubeconfigPaths:    kubeconfigPaths,
 			TotalClustersFound: 2,
@@ -316,7 +391,7 @@ func TestSyncCommand_Execute_WithCleanupTempFiles(t *testing.T) {
 	}
 
 	// Act
-	err := cmd.Execute(ctx, req, mockSyncOrchestrator, mockKubeconfigHandler)
+	_, err := cmd.Execute(ctx, req, mockSyncOrchestrator, mockKubeconfigHandler)
 
 	// Assert
 	require.NoError(t, err)
@@ -331,8 +406,8 @@ func TestSyncCommand_Execute_WithExcludePatterns(t *testing.T) {
 	mockConfigRepo := mocks.NewMockConfigRepository(t)
 	mockConfigProvider := mocks.NewMockConfigProvider(t)
 	mockPasswordReader := mocks.NewMockPasswordReader(t)
-	mockSyncOrchestrator := mocks.NewMockSyncOrchestrator(t)
-	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
 
 	servers := []domain.ConfigServer{
 		{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"},
@@ -341,10 +416,11 @@ func TestSyncCommand_Execute_WithExcludePatterns(t *testing.T) {
 	excludePatterns := []string{"^test-.*", ".*-staging$"}
 
 	mockConfigRepo.On("GetServers", mock.Anything).Return(servers, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
 	mockPasswordReader.On("ReadPassword", mock.Anything, mock.AnythingOfType("string")).Return("password123", nil)
 
 	// Orchestrator now downloads ALL kubeconfigs without filtering
-	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything).
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(&domain.SyncResult{
 			KubeconfigPaths:    kubeconfigPaths,
 			TotalClustersFound: 3,
@@ -366,7 +442,7 @@ func TestSyncCommand_Execute_WithExcludePatterns(t *testing.T) {
 	}
 
 	// Act
-	err := cmd.Execute(ctx, req, mockSyncOrchestrator, mockKubeconfigHandler)
+	_, err := cmd.Execute(ctx, req, mockSyncOrchestrator, mockKubeconfigHandler)
 
 	// Assert
 	require.NoError(t, err)
@@ -376,6 +452,546 @@ func TestSyncCommand_Execute_WithExcludePatterns(t *testing.T) {
 	mockKubeconfigHandler.AssertExpectations(t)
 }
 
+func TestSyncCommand_Execute_WithExcludeTypes(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigProvider := mocks.NewMockConfigProvider(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	prodPath := "/tmp/" + domain.FragmentFileName("prod", server.ID())
+
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).
+		Return(domain.ProfileDefaults{ExcludeTypes: []string{"imported"}}, nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, mock.AnythingOfType("string")).Return("password123", nil)
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything,
+		domain.SyncOptions{ExcludeTypes: []string{"Harvester", "imported"}}).
+		Return(&domain.SyncResult{
+			KubeconfigPaths:    []string{prodPath},
+			TotalClustersFound: 1,
+			Servers: []domain.ServerSyncResult{{
+				Server:         server,
+				Clusters:       []domain.Cluster{{ID: "c-1", Name: "prod", Type: "rke2"}},
+				ExcludedByType: []domain.Cluster{{ID: "c-2", Name: "harvester", Type: "harvester"}},
+			}},
+		}, nil)
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, []string{prodPath}, "/out", mock.Anything).Return(nil)
+
+	cmd := newTestSyncCommand(mockConfigRepo, mockConfigProvider, mockPasswordReader)
+
+	// Act
+	report, err := cmd.Execute(context.Background(), SyncRequest{
+		Output:       "/out",
+		ExcludeTypes: []string{"Harvester"},
+	}, mockSyncOrchestrator, mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, report.Servers, 1)
+	assert.Equal(t, 2, report.Servers[0].Clusters)
+	assert.Equal(t, 1, report.Servers[0].Downloaded)
+	assert.Equal(t, 1, report.Servers[0].Excluded)
+	mockKubeconfigHandler.AssertExpectations(t)
+}
+
+func TestSyncCommand_Execute_ConfiguredExcludePatterns(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
+
+	server := domain.ConfigServer{
+		URL:      "https://rancher.example.com",
+		Username: "admin",
+		AuthType: "local",
+		Sync:     domain.SyncSettings{ExcludePatterns: []string{"^local-"}},
+	}
+	prodPath := "/tmp/" + domain.FragmentFileName("prod", server.ID())
+	localPath := "/tmp/" + domain.FragmentFileName("local", server.ID())
+
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, mock.AnythingOfType("string")).Return("password123", nil)
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&domain.SyncResult{
+			KubeconfigPaths:    []string{prodPath, localPath},
+			TotalClustersFound: 2,
+			Servers: []domain.ServerSyncResult{{
+				Server:   server,
+				Clusters: []domain.Cluster{{ID: "c-1", Name: "prod"}, {ID: "local", Name: "local"}},
+			}},
+		}, nil)
+	var clusterFilter domain.ClusterFilter
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, []string{prodPath}, "/out", mock.Anything).
+		Run(func(args mock.Arguments) {
+			clusterFilter = args.Get(3).(domain.ClusterFilter)
+		}).Return(nil)
+
+	cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockPasswordReader, testutil.Logger(),
+		WithSyncSettings(domain.SyncSettings{ExcludePatterns: []string{"^sandbox-"}}))
+
+	// Act
+	_, err := cmd.Execute(context.Background(), SyncRequest{
+		Output:          "/out",
+		ExcludePatterns: []string{"^staging-"},
+	}, mockSyncOrchestrator, mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, clusterFilter)
+	assert.True(t, clusterFilter.ShouldExclude("staging-api"), "requested patterns still apply")
+	assert.True(t, clusterFilter.ShouldExclude("sandbox-api"), "configured patterns are added to them")
+	assert.False(t, clusterFilter.ShouldExclude("local-abc12345"), "server patterns apply to their server only")
+}
+
+func TestSyncCommand_Execute_ReportsServerOutcomes(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigProvider := mocks.NewMockConfigProvider(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	prodPath := "/tmp/" + domain.FragmentFileName("prod", server.ID())
+	stagingPath := "/tmp/" + domain.FragmentFileName("staging", server.ID())
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, mock.AnythingOfType("string")).Return("password123", nil)
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&domain.SyncResult{
+			KubeconfigPaths:    []string{prodPath, stagingPath},
+			TotalClustersFound: 2,
+			Servers: []domain.ServerSyncResult{{
+				Server: server,
+				Clusters: []domain.Cluster{
+					{ID: "c-1", Name: "prod", Type: "rke2"},
+					{ID: "c-2", Name: "staging", Type: "rke2"},
+				},
+				ExcludedByType: []domain.Cluster{{ID: "c-3", Name: "harvester", Type: "harvester"}},
+			}},
+			Events: []domain.SyncEvent{
+				{Phase: domain.PhaseAuthenticate, ServerURL: server.URL, Start: start, End: start.Add(time.Second)},
+				{
+					Phase: domain.PhaseDownload, ServerURL: server.URL, Cluster: "prod",
+					Start: start.Add(time.Second), End: start.Add(3 * time.Second),
+				},
+			},
+		}, nil)
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, []string{prodPath, stagingPath}, "/out", mock.Anything).
+		Return(nil)
+
+	cmd := newTestSyncCommand(mockConfigRepo, mockConfigProvider, mockPasswordReader)
+
+	// Act
+	report, err := cmd.Execute(context.Background(), SyncRequest{
+		Output:          "/out",
+		ExcludePatterns: []string{"^staging"},
+		ExcludeTypes:    []string{"harvester"},
+	}, mockSyncOrchestrator, mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, report.Servers, 1)
+	assert.Equal(t, 3, report.Servers[0].Clusters)
+	assert.Equal(t, 2, report.Servers[0].Downloaded)
+	assert.Equal(t, 2, report.Servers[0].Excluded)
+	assert.Equal(t, 0, report.Servers[0].Failed)
+	assert.Equal(t, 3*time.Second, report.Servers[0].Duration)
+}
+
+func TestSyncCommand_Execute_CachedOnly(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigProvider := mocks.NewMockConfigProvider(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockTokenCache := mocks.NewMockTokenCache(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
+
+	cached := domain.ConfigServer{URL: "https://cached.example.com", Username: "admin", AuthType: "local"}
+	uncached := domain.ConfigServer{URL: "https://uncached.example.com", Username: "admin", AuthType: "local"}
+	kubeconfigPaths := []string{"/tmp/cluster1.yaml"}
+
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{cached, uncached}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockTokenCache.On("Get", mock.Anything, cached).Return(mocks.NewMockAuthToken(t), nil)
+	mockTokenCache.On("Get", mock.Anything, uncached).Return(nil, nil)
+	mockConfigProvider.On("GetDefaultKubeconfigPath").Return("/home/user/.kube/config", nil)
+	mockSyncOrchestrator.
+		On("SyncServers", mock.Anything, []domain.ConfigServer{cached}, map[string]string{}, mock.Anything).
+		Return(&domain.SyncResult{KubeconfigPaths: kubeconfigPaths, TotalClustersFound: 1}, nil)
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, kubeconfigPaths, "/home/user/.kube/config",
+		mock.Anything).Return(nil)
+
+	cmd := NewSyncCommand(mockConfigRepo, mockConfigProvider, mockPasswordReader, testutil.Logger(),
+		WithTokenCache(mockTokenCache))
+
+	// Act
+	report, err := cmd.Execute(context.Background(), SyncRequest{CachedOnly: true},
+		mockSyncOrchestrator, mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, report.Skipped, 1)
+	assert.Equal(t, uncached.URL, report.Skipped[0].ServerURL)
+	mockPasswordReader.AssertNotCalled(t, "ReadPassword", mock.Anything, mock.Anything)
+}
+
+func TestSyncCommand_Execute_SkipsTokenServersWithoutToken(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigProvider := mocks.NewMockConfigProvider(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
+	mockTokenCache := mocks.NewMockTokenCache(t)
+
+	tokenServer := domain.ConfigServer{URL: "https://token.example.com", Username: "admin", AuthType: "token"}
+	password := domain.ConfigServer{URL: "https://password.example.com", Username: "admin", AuthType: "local"}
+	kubeconfigPaths := []string{"/tmp/cluster1.yaml"}
+
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{tokenServer, password}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockTokenCache.On("Get", mock.Anything, mock.Anything).Return(nil, nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, "Password for https://password.example.com: ").
+		Return("password123", nil)
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, []domain.ConfigServer{password},
+		map[string]string{password.ID(): "password123"}, mock.Anything).
+		Return(&domain.SyncResult{KubeconfigPaths: kubeconfigPaths, TotalClustersFound: 1}, nil)
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, kubeconfigPaths, "/out", mock.Anything).Return(nil)
+
+	cmd := NewSyncCommand(mockConfigRepo, mockConfigProvider, mockPasswordReader, testutil.Logger(),
+		WithTokenCache(mockTokenCache))
+
+	// Act
+	report, err := cmd.Execute(context.Background(), SyncRequest{Output: "/out"},
+		mockSyncOrchestrator, mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, report.Skipped, 1)
+	assert.Equal(t, tokenServer.URL, report.Skipped[0].ServerURL)
+	assert.Contains(t, report.Skipped[0].Reason, "convert-to-token")
+}
+
+func TestSyncCommand_Execute_CachedOnlyWithoutTokens(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockTokenCache := mocks.NewMockTokenCache(t)
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockTokenCache.On("Get", mock.Anything, server).Return(nil, nil)
+
+	cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mocks.NewMockPasswordReader(t),
+		testutil.Logger(), WithTokenCache(mockTokenCache))
+
+	// Act
+	_, err := cmd.Execute(context.Background(), SyncRequest{CachedOnly: true},
+		mocks.NewMockSyncOrchestrator(t), mocks.NewMockKubeconfigHandler(t))
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no server has a valid cached token")
+}
+
+func TestSyncCommand_Execute_SkipsPasswordPromptForCachedTokens(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigProvider := mocks.NewMockConfigProvider(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockTokenCache := mocks.NewMockTokenCache(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
+
+	cached := domain.ConfigServer{URL: "https://cached.example.com", Username: "admin", AuthType: "local"}
+	uncached := domain.ConfigServer{URL: "https://uncached.example.com", Username: "admin", AuthType: "local"}
+	kubeconfigPaths := []string{"/tmp/cluster1.yaml"}
+
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{cached, uncached}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockTokenCache.On("Get", mock.Anything, cached).Return(mocks.NewMockAuthToken(t), nil)
+	mockTokenCache.On("Get", mock.Anything, uncached).Return(nil, nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, "Password for https://uncached.example.com: ").
+		Return("secret", nil).Once()
+	mockConfigProvider.On("GetDefaultKubeconfigPath").Return("/home/user/.kube/config", nil)
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, []domain.ConfigServer{cached, uncached},
+		map[string]string{uncached.ID(): "secret"}, mock.Anything).
+		Return(&domain.SyncResult{KubeconfigPaths: kubeconfigPaths, TotalClustersFound: 1}, nil)
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, kubeconfigPaths, "/home/user/.kube/config",
+		mock.Anything).Return(nil)
+
+	cmd := NewSyncCommand(mockConfigRepo, mockConfigProvider, mockPasswordReader, testutil.Logger(),
+		WithTokenCache(mockTokenCache))
+
+	// Act
+	report, err := cmd.Execute(context.Background(), SyncRequest{}, mockSyncOrchestrator, mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, report.Skipped)
+}
+
+func TestSyncCommand_Execute_RepromptsRejectedPasswords(t *testing.T) {
+	tests := []struct {
+		name        string
+		interactive bool
+		authRetries int
+		retries     string
+		wantSkipped bool
+	}{
+		{name: "corrected password", interactive: true, authRetries: 2, retries: "right"},
+		{name: "attempts exhausted", interactive: true, authRetries: 2, retries: "wrong", wantSkipped: true},
+		{name: "retries disabled", interactive: true, retries: "right", wantSkipped: true},
+		{name: "not interactive", authRetries: 2, wantSkipped: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockConfigRepo := mocks.NewMockConfigRepository(t)
+			mockConfigProvider := mocks.NewMockConfigProvider(t)
+			mockPasswordReader := mocks.NewMockPasswordReader(t)
+			mockSyncOrchestrator := mocks.NewMockSyncOrchestrator(t)
+			mockKubeconfigHandler := newMockKubeconfigHandler(t)
+
+			good := domain.ConfigServer{URL: "https://good.example.com", Username: "admin", AuthType: "local"}
+			typo := domain.ConfigServer{URL: "https://typo.example.com", Username: "admin", AuthType: "local"}
+			kubeconfigPaths := []string{"/tmp/cluster1.yaml"}
+
+			mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{good, typo}, nil)
+			mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+			mockPasswordReader.On("ReadPassword", mock.Anything, "Password for https://good.example.com: ").
+				Return("secret", nil).Once()
+			mockPasswordReader.On("ReadPassword", mock.Anything, "Password for https://typo.example.com: ").
+				Return("wrong", nil).Once()
+			mockPasswordReader.On("ReadPassword", mock.Anything, "Password for https://typo.example.com: ").
+				Return(tt.retries, nil).Maybe()
+			mockPasswordReader.On("IsInteractive").Return(tt.interactive).Maybe()
+			mockSyncOrchestrator.On("Preauthenticate", mock.Anything, mock.Anything, mock.Anything).
+				Return(func(_ context.Context, _ []domain.ConfigServer, passwords map[string]string) map[string]error {
+					if passwords[typo.ID()] == "wrong" {
+						return map[string]error{typo.ID(): fmt.Errorf("login failed: %w", domain.ErrUnauthorized)}
+					}
+					return map[string]error{}
+				})
+			wantServers := []domain.ConfigServer{good, typo}
+			if tt.wantSkipped {
+				wantServers = []domain.ConfigServer{good}
+			}
+			mockConfigProvider.On("GetDefaultKubeconfigPath").Return("/home/user/.kube/config", nil)
+			mockSyncOrchestrator.On("SyncServers", mock.Anything, wantServers, mock.Anything, mock.Anything).
+				Return(&domain.SyncResult{KubeconfigPaths: kubeconfigPaths, TotalClustersFound: 1}, nil)
+			mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, kubeconfigPaths, "/home/user/.kube/config",
+				mock.Anything).Return(nil)
+
+			cmd := newTestSyncCommand(mockConfigRepo, mockConfigProvider, mockPasswordReader)
+
+			// Act
+			report, err := cmd.Execute(context.Background(), SyncRequest{AuthRetries: tt.authRetries},
+				mockSyncOrchestrator, mockKubeconfigHandler)
+
+			// Assert
+			require.NoError(t, err)
+			if tt.wantSkipped {
+				require.Len(t, report.Skipped, 1)
+				assert.Equal(t, typo.URL, report.Skipped[0].ServerURL)
+			} else {
+				assert.Empty(t, report.Skipped)
+			}
+		})
+	}
+}
+
+func TestSyncCommand_Execute_SavesReport(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigProvider := mocks.NewMockConfigProvider(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockStore := mocks.NewMockStateStore(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
+
+	healthy := domain.ConfigServer{URL: "https://healthy.example.com", Username: "admin", AuthType: "local"}
+	failing := domain.ConfigServer{URL: "https://failing.example.com", Username: "admin", AuthType: "local"}
+	kubeconfigPaths := []string{"/tmp/cluster1.yaml"}
+
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{healthy, failing}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, mock.AnythingOfType("string")).Return("password123", nil)
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&domain.SyncResult{
+			KubeconfigPaths:    kubeconfigPaths,
+			TotalClustersFound: 1,
+			Servers: []domain.ServerSyncResult{
+				{Server: healthy, Clusters: []domain.Cluster{{ID: "c-1", Name: "prod"}}, Version: "v2.8.0"},
+				{Server: failing, Error: fmt.Errorf("authentication failed: %w", domain.ErrUnauthorized)},
+			},
+		}, nil)
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, kubeconfigPaths, "/custom/kubeconfig", mock.Anything).
+		Return(nil)
+
+	var saved *SyncReport
+	mockStore.On("Save", mock.Anything, "last-sync", mock.AnythingOfType("*commands.SyncReport")).
+		Run(func(args mock.Arguments) { saved = args.Get(2).(*SyncReport) }).
+		Return(nil)
+
+	cmd := NewSyncCommand(mockConfigRepo, mockConfigProvider, mockPasswordReader, testutil.Logger(),
+		WithReportStore(mockStore))
+
+	// Act
+	report, err := cmd.Execute(context.Background(), SyncRequest{Output: "/custom/kubeconfig"},
+		mockSyncOrchestrator, mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	assert.Same(t, report, saved)
+	assert.Len(t, saved.CorrelationID, 16)
+	assert.False(t, saved.StartedAt.IsZero())
+	assert.Equal(t, []ServerReport{
+		{ServerURL: healthy.URL, ServerID: healthy.ID(), Version: "v2.8.0", Clusters: 1},
+		{
+			ServerURL: failing.URL, ServerID: failing.ID(), Error: "authentication failed: unauthorized",
+			Category: domain.FailureAuth,
+		},
+	}, saved.Servers)
+}
+
+func TestSyncCommand_Execute_SavesFailedReport(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockStore := mocks.NewMockStateStore(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, mock.AnythingOfType("string")).Return("password123", nil)
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&domain.SyncResult{}, nil)
+	mockStore.On("Save", mock.Anything, "last-sync", mock.MatchedBy(func(report *SyncReport) bool {
+		return report.Error == "no kubeconfigs downloaded successfully" && !report.FinishedAt.IsZero()
+	})).Return(nil)
+
+	cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockPasswordReader, testutil.Logger(),
+		WithReportStore(mockStore))
+
+	// Act
+	_, err := cmd.Execute(context.Background(), SyncRequest{}, mockSyncOrchestrator, mocks.NewMockKubeconfigHandler(t))
+
+	// Assert
+	require.Error(t, err)
+	mockStore.AssertExpectations(t)
+}
+
+func TestSyncCommand_Execute_SavesDownloadFailures(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockStore := mocks.NewMockStateStore(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	failures := []domain.ClusterFailure{
+		{ServerURL: server.URL, Cluster: "prod", Error: "failed to get kubeconfig: status 403"},
+		{ServerURL: server.URL, Cluster: "staging", Error: "failed to get kubeconfig: status 403"},
+	}
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, mock.AnythingOfType("string")).Return("password123", nil)
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&domain.SyncResult{
+			TotalClustersFound: 2,
+			Servers: []domain.ServerSyncResult{{
+				Server:   server,
+				Clusters: []domain.Cluster{{ID: "c-1", Name: "prod"}, {ID: "c-2", Name: "staging"}},
+			}},
+			Failures: failures,
+		}, nil)
+	var saved *SyncReport
+	mockStore.On("Save", mock.Anything, "last-sync", mock.AnythingOfType("*commands.SyncReport")).
+		Run(func(args mock.Arguments) { saved, _ = args.Get(2).(*SyncReport) }).
+		Return(nil)
+
+	cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockPasswordReader, testutil.Logger(),
+		WithReportStore(mockStore))
+
+	// Act
+	_, err := cmd.Execute(context.Background(), SyncRequest{}, mockSyncOrchestrator, mocks.NewMockKubeconfigHandler(t))
+
+	// Assert
+	require.Error(t, err)
+	require.NotNil(t, saved)
+	assert.Contains(t, saved.Error, "failed to download 2 out of 2 kubeconfigs")
+	assert.Equal(t, failures, saved.Failures)
+}
+
+func TestSyncCommand_Execute_MergesDespiteFailedDownloads(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockHealthTracker := mocks.NewMockHealthTracker(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
+
+	partial := domain.ConfigServer{URL: "https://partial.example.com", Username: "admin", AuthType: "local"}
+	failing := domain.ConfigServer{URL: "https://failing.example.com", Username: "admin", AuthType: "local"}
+	prodPath := "/tmp/" + domain.FragmentFileName("prod", partial.ID())
+	failures := []domain.ClusterFailure{
+		{ServerURL: failing.URL, Cluster: "edge", Error: "status 403", Category: domain.FailurePermission},
+		{ServerURL: partial.URL, Cluster: "staging", Error: "status 403", Category: domain.FailurePermission},
+	}
+
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{partial, failing}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, mock.AnythingOfType("string")).Return("password123", nil)
+	mockHealthTracker.On("Get", mock.Anything, mock.Anything).Return(domain.ServerHealth{}, nil)
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&domain.SyncResult{
+			KubeconfigPaths:    []string{prodPath},
+			TotalClustersFound: 3,
+			Servers: []domain.ServerSyncResult{
+				{Server: partial, Clusters: []domain.Cluster{{ID: "c-1", Name: "prod"}, {ID: "c-2", Name: "staging"}}},
+				{Server: failing, Clusters: []domain.Cluster{{ID: "c-3", Name: "edge"}}},
+			},
+			Failures: failures,
+		}, nil)
+	mockHealthTracker.On("RecordSuccess", mock.Anything, partial).Return(nil)
+	mockHealthTracker.On("RecordFailure", mock.Anything, failing, mock.MatchedBy(func(err error) bool {
+		var downloadErr *domain.DownloadError
+		return errors.As(err, &downloadErr) && len(downloadErr.Failures) == 1
+	})).Return(nil)
+	mockKubeconfigHandler.On("ListContexts", mock.Anything, "/out").
+		Return([]domain.ManagedContext{{Name: "staging-" + partial.ID()}}, nil)
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, []string{"/out", prodPath}, "/out", mock.Anything).
+		Return(nil)
+
+	cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockPasswordReader, testutil.Logger(),
+		WithHealthTracker(mockHealthTracker))
+
+	// Act
+	report, err := cmd.Execute(context.Background(), SyncRequest{Output: "/out"}, mockSyncOrchestrator,
+		mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, failures, report.Failures)
+	require.Len(t, report.Servers, 2)
+	assert.Equal(t, 1, report.Servers[0].Downloaded)
+	assert.Equal(t, 1, report.Servers[0].Failed)
+	assert.Equal(t, 1, report.Servers[1].Failed)
+}
+
 func TestSyncCommand_collectPasswords(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -456,3 +1072,1016 @@ func TestSyncCommand_collectPasswords(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncCommand_Execute_SkipsServersBackingOff(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigProvider := mocks.NewMockConfigProvider(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
+	mockHealthTracker := mocks.NewMockHealthTracker(t)
+
+	healthy := domain.ConfigServer{URL: "https://healthy.example.com", Username: "admin", AuthType: "local"}
+	failing := domain.ConfigServer{URL: "https://failing.example.com", Username: "admin", AuthType: "local"}
+	kubeconfigPaths := []string{"/tmp/cluster1.yaml"}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{healthy, failing}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockHealthTracker.On("Get", mock.Anything, healthy).Return(domain.ServerHealth{}, nil)
+	mockHealthTracker.On("Get", mock.Anything, failing).Return(domain.ServerHealth{
+		ConsecutiveFailures: 3,
+		NextAttempt:         now.Add(time.Hour),
+	}, nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, "Password for https://healthy.example.com: ").
+		Return("password123", nil)
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, []domain.ConfigServer{healthy}, mock.Anything, mock.Anything).
+		Return(&domain.SyncResult{
+			KubeconfigPaths:    kubeconfigPaths,
+			TotalClustersFound: 1,
+			Servers:            []domain.ServerSyncResult{{Server: healthy}},
+		}, nil)
+	mockHealthTracker.On("RecordSuccess", mock.Anything, healthy).Return(nil)
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, kubeconfigPaths, "/out", mock.Anything).Return(nil)
+
+	cmd := NewSyncCommand(mockConfigRepo, mockConfigProvider, mockPasswordReader, testutil.Logger(),
+		WithHealthTracker(mockHealthTracker), WithClock(testutil.NewClock(now)))
+
+	// Act
+	report, err := cmd.Execute(context.Background(), SyncRequest{Output: "/out"}, mockSyncOrchestrator,
+		mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, report.Skipped, 1)
+	assert.Equal(t, failing.ID(), report.Skipped[0].ServerID)
+	assert.Contains(t, report.Skipped[0].Reason, "backing off after 3 consecutive failures")
+}
+
+func TestSyncCommand_Execute_AllServersBackingOff(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockHealthTracker := mocks.NewMockHealthTracker(t)
+
+	server := domain.ConfigServer{URL: "https://failing.example.com", Username: "admin", AuthType: "local"}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockHealthTracker.On("Get", mock.Anything, server).Return(domain.ServerHealth{
+		ConsecutiveFailures: 1,
+		NextAttempt:         now.Add(time.Minute),
+	}, nil)
+
+	cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mocks.NewMockPasswordReader(t),
+		testutil.Logger(), WithHealthTracker(mockHealthTracker), WithClock(testutil.NewClock(now)))
+
+	// Act
+	_, err := cmd.Execute(context.Background(), SyncRequest{},
+		mocks.NewMockSyncOrchestrator(t), mocks.NewMockKubeconfigHandler(t))
+
+	// Assert
+	var noServersErr *NoServersError
+	require.ErrorAs(t, err, &noServersErr)
+	assert.Contains(t, err.Error(), "backing off")
+	require.Len(t, noServersErr.Skipped, 1)
+	assert.Equal(t, server.URL, noServersErr.Skipped[0].ServerURL)
+}
+
+func TestSyncCommand_Execute_ReportsContextCollisions(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
+	mockTokenCache := mocks.NewMockTokenCache(t)
+
+	eu := domain.ConfigServer{URL: "https://eu.rancher.example.com", Username: "admin", AuthType: "local"}
+	us := domain.ConfigServer{URL: "https://us.rancher.example.com", Username: "admin", AuthType: "local"}
+	paths := []string{
+		"/tmp/" + domain.FragmentFileName("production", eu.ID()),
+		"/tmp/" + domain.FragmentFileName("staging", eu.ID()),
+		"/tmp/" + domain.FragmentFileName("production", us.ID()),
+		"/tmp/" + domain.FragmentFileName("staging", us.ID()),
+	}
+
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{us, eu}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockTokenCache.On("Get", mock.Anything, mock.Anything).Return(mocks.NewMockAuthToken(t), nil)
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&domain.SyncResult{
+			KubeconfigPaths:    paths,
+			TotalClustersFound: 4,
+			Servers: []domain.ServerSyncResult{
+				{Server: us, Clusters: []domain.Cluster{{ID: "c-1", Name: "production"}, {ID: "c-2", Name: "staging"}}},
+				{Server: eu, Clusters: []domain.Cluster{{ID: "c-1", Name: "production"}, {ID: "c-2", Name: "staging"}}},
+			},
+		}, nil)
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, paths, "/out", mock.Anything).Return(nil)
+
+	cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mocks.NewMockPasswordReader(t),
+		testutil.Logger(), WithTokenCache(mockTokenCache))
+
+	// Act
+	report, err := cmd.Execute(context.Background(), SyncRequest{
+		Output:          "/out",
+		ExcludePatterns: []string{"^staging-"},
+	}, mockSyncOrchestrator, mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, report.Collisions, 1)
+	assert.Equal(t, ContextCollision{
+		Name: "production",
+		Contexts: []CollidingContext{
+			{ServerURL: eu.URL, Context: "production-" + eu.ID(), SuggestedAlias: "production-eu"},
+			{ServerURL: us.URL, Context: "production-" + us.ID(), SuggestedAlias: "production-us"},
+		},
+	}, report.Collisions[0])
+}
+
+func TestSyncCommand_Execute_ExcludeServers(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
+
+	kept := domain.ConfigServer{URL: "https://kept.example.com", Username: "admin", AuthType: "local"}
+	byURL := domain.ConfigServer{URL: "https://maintenance.example.com", Username: "admin", AuthType: "local"}
+	byID := domain.ConfigServer{URL: "https://offline.example.com", Username: "admin", AuthType: "local"}
+	kubeconfigPaths := []string{"/tmp/cluster1.yaml"}
+
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{kept, byURL, byID}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, "Password for https://kept.example.com: ").
+		Return("password123", nil)
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, []domain.ConfigServer{kept}, mock.Anything, mock.Anything).
+		Return(&domain.SyncResult{
+			KubeconfigPaths:    kubeconfigPaths,
+			TotalClustersFound: 1,
+			Servers:            []domain.ServerSyncResult{{Server: kept}},
+		}, nil)
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, kubeconfigPaths, "/out", mock.Anything).Return(nil)
+
+	cmd := newTestSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockPasswordReader)
+
+	// Act
+	report, err := cmd.Execute(context.Background(), SyncRequest{
+		Output:         "/out",
+		ExcludeServers: []string{byURL.URL + "/", byID.ID()},
+	}, mockSyncOrchestrator, mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []SkippedServer{
+		{ServerURL: byURL.URL, ServerID: byURL.ID(), Reason: "excluded from this sync"},
+		{ServerURL: byID.URL, ServerID: byID.ID(), Reason: "excluded from this sync"},
+	}, report.Skipped)
+}
+
+func TestSyncCommand_Execute_ExcludeUnknownServer(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+
+	cmd := newTestSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mocks.NewMockPasswordReader(t))
+
+	// Act
+	_, err := cmd.Execute(context.Background(), SyncRequest{ExcludeServers: []string{"https://unknown.example.com"}},
+		mocks.NewMockSyncOrchestrator(t), mocks.NewMockKubeconfigHandler(t))
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "https://unknown.example.com is not configured")
+}
+
+func TestSyncCommand_Execute_ListedClusters(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
+	mockFragmentCache := mocks.NewMockFragmentCache(t)
+
+	listed := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	unlisted := domain.ConfigServer{URL: "https://other.example.com", Username: "admin", AuthType: "local"}
+	kubeconfigPaths := []string{"/tmp/" + domain.FragmentFileName("production", listed.ID())}
+	targets := []domain.ServerClusters{{
+		Server: listed,
+		Clusters: []domain.Cluster{
+			{ID: "c-m-abc123", Name: "production"},
+			{ID: "local", Name: "local"},
+		},
+	}}
+
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{unlisted, listed}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockConfigRepo.On("GetAllServers", mock.Anything).Return([]domain.ConfigServer{unlisted, listed}, nil)
+	mockConfigRepo.On("GetSettings", mock.Anything).Return(domain.Settings{}, nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, "Password for https://rancher.example.com: ").
+		Return("password123", nil)
+	mockSyncOrchestrator.On("SyncClusters", mock.Anything, targets, mock.Anything, mock.Anything).
+		Return(&domain.SyncResult{
+			KubeconfigPaths:    kubeconfigPaths,
+			TotalClustersFound: 2,
+			Servers:            []domain.ServerSyncResult{{Server: listed, Clusters: targets[0].Clusters}},
+		}, nil)
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, kubeconfigPaths, "/out", mock.Anything).Return(nil)
+	// Clusters missing from the list may still exist, so none of the server's fragments are collected.
+	mockFragmentCache.On("Clean", mock.Anything, mock.MatchedBy(func(policy domain.CleanupPolicy) bool {
+		return len(policy.SyncedServerIDs) == 0
+	})).Return(&domain.CleanupResult{}, nil)
+
+	cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockPasswordReader, testutil.Logger(),
+		WithFragmentCache(mockFragmentCache))
+
+	// Act
+	_, err := cmd.Execute(context.Background(), SyncRequest{
+		Output: "/out",
+		Clusters: []domain.ClusterRef{
+			{Server: listed.URL, ClusterID: "c-m-abc123", Name: "production"},
+			{Server: listed.ID(), ClusterID: "local"},
+		},
+	}, mockSyncOrchestrator, mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	mockSyncOrchestrator.AssertNotCalled(t, "SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSyncCommand_Execute_ListedClusterOnUnknownServer(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+
+	cmd := newTestSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mocks.NewMockPasswordReader(t))
+
+	// Act
+	_, err := cmd.Execute(context.Background(), SyncRequest{
+		Clusters: []domain.ClusterRef{{Server: "https://unknown.example.com", ClusterID: "c-1"}},
+	}, mocks.NewMockSyncOrchestrator(t), mocks.NewMockKubeconfigHandler(t))
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "listed server https://unknown.example.com is not configured")
+}
+
+func TestSyncCommand_Execute_KeepExisting(t *testing.T) {
+	tests := []struct {
+		name       string
+		existing   []domain.ManagedContext
+		mergePaths func(fragment string) []string
+	}{
+		{
+			name:       "existing contexts are merged first",
+			existing:   []domain.ManagedContext{{Name: "staging-1a2b3c4d", ClusterName: "staging"}},
+			mergePaths: func(fragment string) []string { return []string{"/out", fragment} },
+		},
+		{
+			name:       "missing output is not merged",
+			mergePaths: func(fragment string) []string { return []string{fragment} },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockConfigRepo := mocks.NewMockConfigRepository(t)
+			mockPasswordReader := mocks.NewMockPasswordReader(t)
+			mockSyncOrchestrator := newMockSyncOrchestrator(t)
+			mockKubeconfigHandler := newMockKubeconfigHandler(t)
+
+			server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+			fragment := "/tmp/" + domain.FragmentFileName("production", server.ID())
+			clusters := []domain.Cluster{{ID: "c-m-abc123", Name: "production"}}
+
+			mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+			mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+			mockPasswordReader.On("ReadPassword", mock.Anything, mock.Anything).Return("password123", nil)
+			mockSyncOrchestrator.On("SyncClusters", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return(&domain.SyncResult{
+					KubeconfigPaths:    []string{fragment},
+					TotalClustersFound: 1,
+					Servers:            []domain.ServerSyncResult{{Server: server, Clusters: clusters}},
+				}, nil)
+			mockKubeconfigHandler.On("ListContexts", mock.Anything, "/out").Return(tt.existing, nil)
+			mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, tt.mergePaths(fragment), "/out", mock.Anything).
+				Return(nil)
+
+			cmd := newTestSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockPasswordReader)
+
+			// Act
+			report, err := cmd.Execute(context.Background(), SyncRequest{
+				Output:       "/out",
+				Clusters:     []domain.ClusterRef{{Server: server.ID(), ClusterID: "c-m-abc123", Name: "production"}},
+				KeepExisting: true,
+			}, mockSyncOrchestrator, mockKubeconfigHandler)
+
+			// Assert
+			require.NoError(t, err)
+			require.Len(t, report.Servers, 1)
+			assert.Equal(t, 1, report.Servers[0].Downloaded)
+			assert.Zero(t, report.Servers[0].Excluded)
+		})
+	}
+}
+
+func TestSyncCommand_Execute_LeavesInvalidFragmentsOut(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
+	mockValidator := mocks.NewMockFragmentValidator(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	prodPath := "/tmp/" + domain.FragmentFileName("prod", server.ID())
+	stagingPath := "/tmp/" + domain.FragmentFileName("staging", server.ID())
+
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, mock.Anything).Return("password123", nil)
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&domain.SyncResult{
+			KubeconfigPaths:    []string{prodPath, stagingPath},
+			TotalClustersFound: 2,
+			Servers: []domain.ServerSyncResult{{
+				Server:   server,
+				Clusters: []domain.Cluster{{ID: "c-1", Name: "prod"}, {ID: "c-2", Name: "staging"}},
+			}},
+		}, nil)
+	mockValidator.On("ValidateFragment", mock.Anything, prodPath).Return(nil)
+	mockValidator.On("ValidateFragment", mock.Anything, stagingPath).Return([]string{"no users"})
+	mockValidator.On("QuarantineFragment", mock.Anything, stagingPath, []string{"no users"}).
+		Return("/tmp/quarantine/"+domain.FragmentFileName("staging", server.ID()), nil)
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, []string{prodPath}, "/out", mock.Anything).Return(nil)
+
+	cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockPasswordReader, testutil.Logger(),
+		WithFragmentValidator(mockValidator))
+
+	// Act
+	report, err := cmd.Execute(context.Background(), SyncRequest{Output: "/out"},
+		mockSyncOrchestrator, mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []InvalidFragment{{
+		ServerURL:  server.URL,
+		Cluster:    "staging",
+		File:       domain.FragmentFileName("staging", server.ID()),
+		Problems:   []string{"no users"},
+		Quarantine: "/tmp/quarantine/" + domain.FragmentFileName("staging", server.ID()),
+	}}, report.Invalid)
+	require.Len(t, report.Servers, 1)
+	assert.Equal(t, 2, report.Servers[0].Downloaded)
+	assert.Equal(t, 1, report.Servers[0].Invalid)
+	assert.Zero(t, report.Servers[0].Excluded)
+}
+
+func TestSyncCommand_Execute_AllFragmentsInvalid(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockValidator := mocks.NewMockFragmentValidator(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	prodPath := "/tmp/" + domain.FragmentFileName("prod", server.ID())
+
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, mock.Anything).Return("password123", nil)
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&domain.SyncResult{
+			KubeconfigPaths: []string{prodPath},
+			Servers:         []domain.ServerSyncResult{{Server: server, Clusters: []domain.Cluster{{ID: "c-1", Name: "prod"}}}},
+		}, nil)
+	mockValidator.On("ValidateFragment", mock.Anything, prodPath).Return([]string{"no clusters"})
+	mockValidator.On("QuarantineFragment", mock.Anything, prodPath, []string{"no clusters"}).
+		Return("", errors.New("permission denied"))
+
+	cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockPasswordReader, testutil.Logger(),
+		WithFragmentValidator(mockValidator))
+
+	// Act
+	_, err := cmd.Execute(context.Background(), SyncRequest{Output: "/out"},
+		mockSyncOrchestrator, mocks.NewMockKubeconfigHandler(t))
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "all 1 downloaded kubeconfigs are invalid")
+}
+
+func TestSyncCommand_Execute_PublishesRemoteOutput(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigProvider := mocks.NewMockConfigProvider(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
+	mockWriter := mocks.NewMockOutputWriter(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	prodPath := "/tmp/" + domain.FragmentFileName("prod", server.ID())
+	mirror := "/home/user/.config/cowpoke/kubeconfigs/outputs/secret-ci_cowpoke.yaml"
+
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockConfigProvider.On("GetKubeconfigDir").Return("/home/user/.config/cowpoke/kubeconfigs", nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, mock.Anything).Return("password123", nil)
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&domain.SyncResult{
+			KubeconfigPaths: []string{prodPath},
+			Servers:         []domain.ServerSyncResult{{Server: server, Clusters: []domain.Cluster{{ID: "c-1", Name: "prod"}}}},
+		}, nil)
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, []string{prodPath}, mirror, mock.Anything).Return(nil)
+	mockWriter.On("Scheme").Return("secret")
+	mockWriter.On("WriteOutput", mock.Anything, mock.MatchedBy(func(destination *url.URL) bool {
+		return destination.String() == "secret://ci/cowpoke?context=admin"
+	}), mirror).Return(nil)
+
+	cmd := NewSyncCommand(mockConfigRepo, mockConfigProvider, mockPasswordReader, testutil.Logger(),
+		WithOutputWriters(mockWriter))
+
+	// Act
+	report, err := cmd.Execute(context.Background(), SyncRequest{Output: "secret://ci/cowpoke?context=admin"},
+		mockSyncOrchestrator, mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "secret://ci/cowpoke?context=admin", report.Output)
+}
+
+func TestSyncCommand_Execute_UnsupportedRemoteOutput(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockWriter := mocks.NewMockOutputWriter(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{Output: "ftp://host/config"}, nil)
+	mockWriter.On("Scheme").Return("secret")
+
+	cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mocks.NewMockPasswordReader(t),
+		testutil.Logger(), WithOutputWriters(mockWriter))
+
+	// Act
+	_, err := cmd.Execute(context.Background(), SyncRequest{},
+		mocks.NewMockSyncOrchestrator(t), mocks.NewMockKubeconfigHandler(t))
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported output ftp:// (use a file path or one of: secret://)")
+}
+
+func TestSyncCommand_Execute_RendersTemplates(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
+	mockRenderer := mocks.NewMockTemplateRenderer(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	prodPath := "/tmp/" + domain.FragmentFileName("prod", server.ID())
+	output := "/home/user/.kube/config"
+	templates := []domain.TemplateSettings{{Source: "ssh.tmpl", Output: "ssh/{{.Name}}"}}
+	contexts := []domain.ManagedContext{{Name: "prod-" + server.ID(), ClusterName: "prod"}}
+
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, mock.Anything).Return("password123", nil)
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&domain.SyncResult{
+			KubeconfigPaths: []string{prodPath},
+			Servers:         []domain.ServerSyncResult{{Server: server, Clusters: []domain.Cluster{{ID: "c-1", Name: "prod"}}}},
+		}, nil)
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, []string{prodPath}, output, mock.Anything).Return(nil)
+	mockKubeconfigHandler.On("ListContexts", mock.Anything, output).Return(contexts, nil)
+	mockRenderer.On("RenderTemplates", mock.Anything, templates, "/home/user/.kube", contexts).
+		Return([]string{"/home/user/.kube/ssh/prod"}, nil)
+
+	cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockPasswordReader, testutil.Logger(),
+		WithTemplates(mockRenderer, templates))
+
+	// Act
+	report, err := cmd.Execute(context.Background(), SyncRequest{Output: output},
+		mockSyncOrchestrator, mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/home/user/.kube/ssh/prod"}, report.Generated)
+}
+
+func TestSyncCommand_Execute_TemplateFailureFailsSync(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
+	mockRenderer := mocks.NewMockTemplateRenderer(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	prodPath := "/tmp/" + domain.FragmentFileName("prod", server.ID())
+
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, mock.Anything).Return("password123", nil)
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&domain.SyncResult{KubeconfigPaths: []string{prodPath}}, nil)
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockKubeconfigHandler.On("ListContexts", mock.Anything, mock.Anything).Return(nil, nil)
+	mockRenderer.On("RenderTemplates", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, errors.New("failed to render template ssh.tmpl: failed to read template: no such file"))
+
+	cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockPasswordReader, testutil.Logger(),
+		WithTemplates(mockRenderer, []domain.TemplateSettings{{Source: "ssh.tmpl", Output: "ssh/config"}}))
+
+	// Act
+	_, err := cmd.Execute(context.Background(), SyncRequest{Output: "/home/user/.kube/config"},
+		mockSyncOrchestrator, mockKubeconfigHandler)
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to generate files from templates: failed to render template ssh.tmpl")
+}
+
+// syncWithOneCluster sets up mocks for a sync of one server with one cluster merged into output.
+func syncWithOneCluster(
+	t *testing.T,
+	output string,
+) (*mocks.MockConfigRepository, *mocks.MockPasswordReader, *mocks.MockSyncOrchestrator, *mocks.MockKubeconfigHandler) {
+	t.Helper()
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	prodPath := "/tmp/" + domain.FragmentFileName("prod", server.ID())
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, mock.Anything).Return("password123", nil)
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&domain.SyncResult{KubeconfigPaths: []string{prodPath}}, nil)
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, []string{prodPath}, output, mock.Anything).Return(nil)
+	return mockConfigRepo, mockPasswordReader, mockSyncOrchestrator, mockKubeconfigHandler
+}
+
+func TestSyncCommand_Execute_ReportsKubeconfigChanges(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+
+	output := "/home/user/.kube/config"
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	prodPath := "/tmp/" + domain.FragmentFileName("prod", server.ID())
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockPasswordReader.On("ReadPassword", mock.Anything, mock.Anything).Return("password123", nil)
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&domain.SyncResult{KubeconfigPaths: []string{prodPath}}, nil)
+	mockKubeconfigHandler.On("SnapshotContexts", mock.Anything, output).Return([]domain.ContextSnapshot{
+		{Name: "dev", Endpoint: "https://dev.example.com", Credential: "a"},
+		{Name: "prod", Endpoint: "https://prod.example.com", Credential: "b"},
+	}, nil).Once()
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, []string{prodPath}, output, mock.Anything).Return(nil)
+	mockKubeconfigHandler.On("SnapshotContexts", mock.Anything, output).Return([]domain.ContextSnapshot{
+		{Name: "prod", Endpoint: "https://prod.example.com", Credential: "c"},
+	}, nil).Once()
+
+	cmd := newTestSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockPasswordReader)
+
+	// Act
+	report, err := cmd.Execute(context.Background(), SyncRequest{Output: output}, mockSyncOrchestrator,
+		mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, &KubeconfigChanges{Removed: []string{"dev"}, TokenRefreshed: []string{"prod"}}, report.Changes)
+}
+
+func TestSyncCommand_Execute_IndexesContexts(t *testing.T) {
+	// Arrange
+	output := "/home/user/.kube/config"
+	mockConfigRepo, mockPasswordReader, mockSyncOrchestrator, mockKubeconfigHandler := syncWithOneCluster(t, output)
+	mockStore := mocks.NewMockStateStore(t)
+	syncedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	owner := domain.ContextOwner{
+		ServerURL: "https://rancher.example.com",
+		ServerID:  "abc12345",
+		ClusterID: "c-m-prod",
+		SyncedAt:  syncedAt,
+	}
+	mockKubeconfigHandler.On("ListContexts", mock.Anything, output).Return([]domain.ManagedContext{
+		{Name: "prod-abc12345", ClusterName: "prod", Owner: owner},
+	}, nil)
+	mockStore.On("Load", mock.Anything, "contexts", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(2).(*domain.ContextIndex) = domain.ContextIndex{Clusters: map[string][]string{"def67890": {"c-1"}}}
+	}).Return(true, nil)
+	var index domain.ContextIndex
+	mockStore.On("Save", mock.Anything, "contexts", mock.Anything).
+		Run(func(args mock.Arguments) { index = args.Get(2).(domain.ContextIndex) }).Return(nil)
+
+	cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockPasswordReader, testutil.Logger(),
+		WithContextIndex(mockStore))
+
+	// Act
+	_, err := cmd.Execute(context.Background(), SyncRequest{Output: output}, mockSyncOrchestrator, mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, output, index.Kubeconfig)
+	assert.False(t, index.UpdatedAt.IsZero())
+	assert.Equal(t, map[string]domain.IndexedContext{
+		"prod-abc12345": {
+			ServerURL:   "https://rancher.example.com",
+			ServerID:    "abc12345",
+			ClusterID:   "c-m-prod",
+			ClusterName: "prod",
+			SyncedAt:    syncedAt,
+		},
+	}, index.Contexts)
+	assert.Equal(t, map[string][]string{"def67890": {"c-1"}}, index.Clusters,
+		"clusters of servers not discovered are kept")
+}
+
+func TestSyncCommand_Execute_Interrupted(t *testing.T) {
+	// Arrange
+	output := "/home/user/.kube/config"
+	mockConfigRepo, mockPasswordReader, mockSyncOrchestrator, mockKubeconfigHandler := syncWithOneCluster(t, output)
+	mockHealthTracker := mocks.NewMockHealthTracker(t)
+	mockStore := mocks.NewMockStateStore(t)
+	mockRunner := mocks.NewMockHookRunner(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Unset()
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { cancel() }).
+		Return(&domain.SyncResult{
+			KubeconfigPaths:    []string{"/tmp/prod.yaml"},
+			TotalClustersFound: 3,
+			Interrupted:        true,
+		}, nil)
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, mock.Anything, output, mock.Anything).Unset()
+	var saved *SyncReport
+	mockStore.On("Save", mock.Anything, lastSyncState, mock.Anything).
+		Run(func(args mock.Arguments) { saved = args.Get(2).(*SyncReport) }).Return(nil)
+
+	cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockPasswordReader, testutil.Logger(),
+		WithHealthTracker(mockHealthTracker),
+		WithReportStore(mockStore),
+		WithHooks(mockRunner, domain.HookSettings{PostSync: []domain.Hook{{Command: "notify-team"}}}))
+
+	// Act
+	report, err := cmd.Execute(ctx, SyncRequest{Output: output, IgnoreBackoff: true}, mockSyncOrchestrator,
+		mockKubeconfigHandler)
+
+	// Assert
+	var interruptedErr *domain.InterruptedError
+	require.ErrorAs(t, err, &interruptedErr)
+	assert.Equal(t, 1, interruptedErr.Downloaded)
+	assert.Equal(t, 3, interruptedErr.Total)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, report)
+	require.NotNil(t, saved)
+	assert.Equal(t, err.Error(), saved.Error)
+}
+
+func TestSyncCommand_Execute_MaxDurationTruncates(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
+	mockHealthTracker := mocks.NewMockHealthTracker(t)
+
+	output := "/home/user/.kube/config"
+	prod := domain.ConfigServer{URL: "https://prod.example.com", Username: "admin", AuthType: "local", Priority: 1}
+	lab := domain.ConfigServer{URL: "https://lab.example.com", Username: "admin", AuthType: "local", Priority: 2}
+	stalled := domain.ConfigServer{URL: "https://stalled.example.com", Username: "admin", AuthType: "local"}
+	prodPath := "/tmp/" + domain.FragmentFileName("api", prod.ID())
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{prod, lab, stalled}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockSyncOrchestrator.On("SyncServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&domain.SyncResult{
+			KubeconfigPaths:    []string{prodPath},
+			TotalClustersFound: 2,
+			Servers: []domain.ServerSyncResult{
+				{Server: prod, Clusters: []domain.Cluster{{ID: "c-api", Name: "api"}, {ID: "c-web", Name: "web"}}},
+				{Server: lab, Error: fmt.Errorf("failed to list clusters: %w", context.DeadlineExceeded)},
+			},
+			Interrupted: true,
+		}, nil)
+	mockHealthTracker.On("RecordSuccess", mock.Anything, prod).Return(nil)
+	mockKubeconfigHandler.On("ListContexts", mock.Anything, output).Return([]domain.ManagedContext{
+		{Name: "web-" + prod.ID()},
+	}, nil)
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, []string{output, prodPath}, output, mock.Anything).
+		Return(nil)
+
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockPasswordReader.On("ReadPassword", mock.Anything, mock.Anything).Return("password123", nil)
+
+	cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockPasswordReader, testutil.Logger(),
+		WithHealthTracker(mockHealthTracker))
+
+	// Act
+	report, err := cmd.Execute(context.Background(), SyncRequest{
+		Output:        output,
+		IgnoreBackoff: true,
+		MaxDuration:   time.Minute,
+	}, mockSyncOrchestrator, mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, report.Truncated)
+	assert.Equal(t, 1, report.KubeconfigsDownloaded)
+	assert.Equal(t, []SkippedServer{
+		{ServerURL: stalled.URL, ServerID: stalled.ID(), Reason: "out of time (--max-duration)"},
+	}, report.Skipped)
+}
+
+func TestSyncCommand_Execute_RunsHooks(t *testing.T) {
+	// Arrange
+	output := "/home/user/.kube/config"
+	mockConfigRepo, mockPasswordReader, mockSyncOrchestrator, mockKubeconfigHandler := syncWithOneCluster(t, output)
+	mockRunner := mocks.NewMockHookRunner(t)
+
+	var ran []string
+	mockRunner.On("RunHook", mock.Anything, "vpn-status", []string{"COWPOKE_HOOK=preSync"}, []byte(nil)).
+		Run(func(mock.Arguments) { ran = append(ran, "vpn-status") }).Return(nil)
+	var payload []byte
+	mockRunner.On("RunHook", mock.Anything, "notify-team",
+		[]string{"COWPOKE_HOOK=postSync", "COWPOKE_SYNC_STATUS=succeeded"}, mock.Anything).
+		Run(func(args mock.Arguments) {
+			ran = append(ran, "notify-team")
+			payload = args.Get(3).([]byte)
+		}).Return(errors.New("exited with status 1"))
+
+	cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockPasswordReader, testutil.Logger(),
+		WithHooks(mockRunner, domain.HookSettings{
+			PreSync:  []domain.Hook{{Command: "vpn-status"}},
+			PostSync: []domain.Hook{{Command: "notify-team"}},
+		}))
+
+	// Act
+	report, err := cmd.Execute(context.Background(), SyncRequest{Output: output},
+		mockSyncOrchestrator, mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err, "a failing post-sync hook only warns by default")
+	assert.Equal(t, []string{"vpn-status", "notify-team"}, ran)
+	var received SyncReport
+	require.NoError(t, json.Unmarshal(payload, &received))
+	assert.Equal(t, report.CorrelationID, received.CorrelationID)
+	assert.Equal(t, output, received.Output)
+}
+
+func TestSyncCommand_Execute_FailingPreSyncHookStopsSync(t *testing.T) {
+	// Arrange
+	mockRunner := mocks.NewMockHookRunner(t)
+	mockRunner.On("RunHook", mock.Anything, "vpn-status", mock.Anything, mock.Anything).
+		Return(errors.New("exited with status 1"))
+	var payload []byte
+	mockRunner.On("RunHook", mock.Anything, "notify-team",
+		[]string{"COWPOKE_HOOK=postSync", "COWPOKE_SYNC_STATUS=failed"}, mock.Anything).
+		Run(func(args mock.Arguments) { payload = args.Get(3).([]byte) }).Return(nil)
+
+	cmd := NewSyncCommand(mocks.NewMockConfigRepository(t), mocks.NewMockConfigProvider(t),
+		mocks.NewMockPasswordReader(t), testutil.Logger(),
+		WithHooks(mockRunner, domain.HookSettings{
+			PreSync:  []domain.Hook{{Command: "vpn-status"}},
+			PostSync: []domain.Hook{{Command: "notify-team"}},
+		}))
+
+	// Act
+	_, err := cmd.Execute(context.Background(), SyncRequest{},
+		mocks.NewMockSyncOrchestrator(t), mocks.NewMockKubeconfigHandler(t))
+
+	// Assert
+	require.Error(t, err)
+	assert.Equal(t, `preSync hook "vpn-status" failed: exited with status 1`, err.Error())
+	var received SyncReport
+	require.NoError(t, json.Unmarshal(payload, &received))
+	assert.Equal(t, err.Error(), received.Error)
+}
+
+func TestSyncCommand_Execute_HookPolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		hook    domain.Hook
+		hookErr error
+		wantErr string
+	}{
+		{
+			name:    "ignored failure",
+			hook:    domain.Hook{Command: "optional", OnFailure: domain.HookIgnore},
+			hookErr: errors.New("exited with status 1"),
+		},
+		{
+			name:    "warned failure",
+			hook:    domain.Hook{Command: "optional", OnFailure: domain.HookWarn},
+			hookErr: errors.New("exited with status 1"),
+		},
+		{
+			name:    "timeout",
+			hook:    domain.Hook{Command: "slow", TimeoutSeconds: 5},
+			hookErr: context.DeadlineExceeded,
+			wantErr: `preSync hook "slow" failed: timed out after 5s`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			output := "/home/user/.kube/config"
+			mockConfigRepo, mockPasswordReader := mocks.NewMockConfigRepository(t), mocks.NewMockPasswordReader(t)
+			mockSyncOrchestrator, mockKubeconfigHandler := newMockSyncOrchestrator(t),
+				mocks.NewMockKubeconfigHandler(t)
+			if tt.wantErr == "" {
+				mockConfigRepo, mockPasswordReader, mockSyncOrchestrator, mockKubeconfigHandler = syncWithOneCluster(
+					t, output)
+			}
+			mockRunner := mocks.NewMockHookRunner(t)
+			mockRunner.On("RunHook", mock.MatchedBy(func(ctx context.Context) bool {
+				deadline, ok := ctx.Deadline()
+				return ok && time.Until(deadline) <= tt.hook.Timeout()
+			}), tt.hook.Command, mock.Anything, mock.Anything).Return(tt.hookErr)
+
+			cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockPasswordReader,
+				testutil.Logger(), WithHooks(mockRunner, domain.HookSettings{PreSync: []domain.Hook{tt.hook}}))
+
+			// Act
+			_, err := cmd.Execute(context.Background(), SyncRequest{Output: output},
+				mockSyncOrchestrator, mockKubeconfigHandler)
+
+			// Assert
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSyncCommand_Execute_Offline(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockPasswordReader := mocks.NewMockPasswordReader(t)
+	mockSyncOrchestrator := newMockSyncOrchestrator(t)
+	mockKubeconfigHandler := newMockKubeconfigHandler(t)
+	mockFragmentCache := mocks.NewMockFragmentCache(t)
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	other := domain.ConfigServer{URL: "https://other.example.com", Username: "admin", AuthType: "local"}
+	prodFile := domain.FragmentFileName("prod", server.ID())
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+	mockFragmentCache.On("List", mock.Anything).Return([]domain.CacheEntry{
+		{File: prodFile, ServerID: server.ID(), ClusterID: "c-m-abc123", ClusterName: "prod", ClusterType: "rke2"},
+		{File: domain.FragmentFileName("dev", other.ID()), ServerID: other.ID()},
+	}, nil)
+	mockFragmentCache.On("Dir").Return("/cache")
+	mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, []string{"/cache/" + prodFile}, "/out", mock.Anything).
+		Return(nil)
+
+	cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockPasswordReader, testutil.Logger(),
+		WithFragmentCache(mockFragmentCache))
+
+	// Act
+	report, err := cmd.Execute(context.Background(), SyncRequest{Output: "/out", Offline: true},
+		mockSyncOrchestrator, mockKubeconfigHandler)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, report.Offline)
+	assert.Equal(t, 1, report.ClustersFound)
+	assert.Zero(t, report.KubeconfigsDownloaded)
+	mockPasswordReader.AssertNotCalled(t, "ReadPassword", mock.Anything, mock.Anything)
+	mockFragmentCache.AssertNotCalled(t, "Clean", mock.Anything, mock.Anything)
+}
+
+func TestSyncCommand_Execute_OfflineErrors(t *testing.T) {
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	tests := []struct {
+		name    string
+		output  string
+		entries []domain.CacheEntry
+		wantErr string
+	}{
+		{
+			name:    "nothing cached",
+			output:  "/out",
+			entries: []domain.CacheEntry{{File: "dev-0000.yaml", ServerID: "0000"}},
+			wantErr: "no cached kubeconfigs for the servers synced",
+		},
+		{
+			name:    "remote output",
+			output:  "s3://bucket/config",
+			wantErr: "offline syncs cannot write to s3:// outputs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockConfigRepo := mocks.NewMockConfigRepository(t)
+			mockFragmentCache := mocks.NewMockFragmentCache(t)
+			mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{server}, nil)
+			mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+			if tt.entries != nil {
+				mockFragmentCache.On("List", mock.Anything).Return(tt.entries, nil)
+			}
+
+			cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mocks.NewMockPasswordReader(t),
+				testutil.Logger(), WithFragmentCache(mockFragmentCache))
+
+			// Act
+			_, err := cmd.Execute(context.Background(), SyncRequest{Output: tt.output, Offline: true},
+				mocks.NewMockSyncOrchestrator(t), mocks.NewMockKubeconfigHandler(t))
+
+			// Assert
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestSyncCommand_Execute_SharedCAsRejectRemoteOutput(t *testing.T) {
+	// Arrange
+	mockConfigRepo := mocks.NewMockConfigRepository(t)
+	mockConfigRepo.On("GetServers", mock.Anything).Return([]domain.ConfigServer{
+		{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"},
+	}, nil)
+	mockConfigRepo.On("GetDefaults", mock.Anything).Return(domain.ProfileDefaults{}, nil)
+
+	cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mocks.NewMockPasswordReader(t),
+		testutil.Logger(), WithSyncSettings(domain.SyncSettings{ShareCAs: true}))
+
+	// Act
+	_, err := cmd.Execute(context.Background(), SyncRequest{Output: "s3://bucket/config"},
+		mocks.NewMockSyncOrchestrator(t), mocks.NewMockKubeconfigHandler(t))
+
+	// Assert
+	require.ErrorContains(t, err, "shared CA files cannot be used with s3:// outputs")
+}
+
+func TestSyncCommand_Execute_ContextLimits(t *testing.T) {
+	limits := domain.LimitSettings{WarnContexts: 2, MaxContexts: 3}
+	tests := []struct {
+		name        string
+		contexts    int
+		force       bool
+		wantWarning bool
+		wantErr     string
+	}{
+		{name: "within limits", contexts: 2},
+		{name: "above warning threshold", contexts: 3, wantWarning: true},
+		{
+			name:     "above maximum",
+			contexts: 4,
+			wantErr:  "the merged kubeconfig would hold 4 contexts, more than the limit of 3",
+		},
+		{name: "forced above maximum", contexts: 4, force: true, wantWarning: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			output := "/home/user/.kube/config"
+			mockConfigRepo, mockPasswordReader, mockSyncOrchestrator, mockKubeconfigHandler := syncWithOneCluster(
+				t, output)
+			if tt.wantErr != "" {
+				mockKubeconfigHandler.On("MergeKubeconfigs", mock.Anything, mock.Anything, output, mock.Anything).Unset()
+			}
+			mockKubeconfigHandler.On("CountContexts", mock.Anything, mock.Anything, mock.Anything).Return(tt.contexts)
+
+			cmd := NewSyncCommand(mockConfigRepo, mocks.NewMockConfigProvider(t), mockPasswordReader,
+				testutil.Logger(), WithContextLimits(limits))
+
+			// Act
+			report, err := cmd.Execute(context.Background(), SyncRequest{Output: output, Force: tt.force},
+				mockSyncOrchestrator, mockKubeconfigHandler)
+
+			// Assert
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.contexts, report.Contexts)
+			assert.Equal(t, tt.wantWarning, len(report.Warnings) == 1)
+		})
+	}
+}
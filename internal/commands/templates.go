@@ -0,0 +1,31 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"cowpoke/internal/domain"
+)
+
+// renderTemplates renders the configured templates for every context of the merged kubeconfig at path,
+// writing the files next to it, and returns their paths.
+func (c *SyncCommand) renderTemplates(
+	ctx context.Context,
+	kubeconfigHandler domain.KubeconfigHandler,
+	path string,
+) ([]string, error) {
+	if c.renderer == nil || len(c.templates) == 0 {
+		return nil, nil
+	}
+	contexts, err := kubeconfigHandler.ListContexts(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contexts for templates: %w", err)
+	}
+	generated, err := c.renderer.RenderTemplates(ctx, c.templates, filepath.Dir(path), contexts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate files from templates: %w", err)
+	}
+	c.logger.InfoContext(ctx, "Generated files from templates", "templates", len(c.templates), "files", len(generated))
+	return generated, nil
+}
@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"cowpoke/internal/domain"
+)
+
+// UseCommand handles switching the merged kubeconfig's current context.
+type UseCommand struct {
+	kubeconfigHandler domain.KubeconfigHandler
+	configProvider    domain.ConfigProvider
+	logger            *slog.Logger
+}
+
+// NewUseCommand creates a new use command.
+func NewUseCommand(
+	kubeconfigHandler domain.KubeconfigHandler,
+	configProvider domain.ConfigProvider,
+	logger *slog.Logger,
+) *UseCommand {
+	return &UseCommand{
+		kubeconfigHandler: kubeconfigHandler,
+		configProvider:    configProvider,
+		logger:            logger,
+	}
+}
+
+// UseRequest contains the parameters for the use command.
+type UseRequest struct {
+	// Query is a context or cluster name, or a fuzzy abbreviation of one.
+	Query string
+	// Kubeconfig is the merged kubeconfig to update; defaults to ~/.kube/config.
+	Kubeconfig string
+}
+
+// UseResult describes the context that was switched to.
+type UseResult struct {
+	Context    domain.ManagedContext
+	Kubeconfig string
+}
+
+// Execute runs the use command. Exact context and cluster names win over fuzzy matches; a query matching
+// several contexts equally well is an error listing them.
+func (c *UseCommand) Execute(ctx context.Context, req UseRequest) (*UseResult, error) {
+	if req.Query == "" {
+		return nil, errors.New("a context or cluster name must be specified")
+	}
+
+	kubeconfigPath, err := resolveKubeconfig(c.configProvider, req.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	contexts, err := c.kubeconfigHandler.ListContexts(ctx, kubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contexts: %w", err)
+	}
+
+	match, err := matchContext(req.Query, contexts)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.DebugContext(ctx, "Resolved context", "query", req.Query, "context", match.Name)
+	if useErr := c.kubeconfigHandler.UseContext(ctx, kubeconfigPath, match.Name); useErr != nil {
+		return nil, fmt.Errorf("failed to switch context: %w", useErr)
+	}
+	match.Current = true
+
+	return &UseResult{Context: match, Kubeconfig: kubeconfigPath}, nil
+}
+
+// Contexts returns the cowpoke-managed contexts in the merged kubeconfig, for shell completion.
+func (c *UseCommand) Contexts(ctx context.Context, kubeconfig string) ([]domain.ManagedContext, error) {
+	kubeconfigPath, err := resolveKubeconfig(c.configProvider, kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	contexts, err := c.kubeconfigHandler.ListContexts(ctx, kubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contexts: %w", err)
+	}
+	return contexts, nil
+}
+
+// resolveKubeconfig returns kubeconfig, the local copy of it if it names a remote output, or the default
+// merged kubeconfig path if it is empty.
+func resolveKubeconfig(configProvider domain.ConfigProvider, kubeconfig string) (string, error) {
+	if destination, ok := domain.ParseOutputDestination(kubeconfig); ok {
+		return outputMirror(configProvider, destination)
+	}
+	if kubeconfig != "" {
+		return kubeconfig, nil
+	}
+	path, err := configProvider.GetDefaultKubeconfigPath()
+	if err != nil {
+		return "", fmt.Errorf("failed to get default kubeconfig path: %w", err)
+	}
+	return path, nil
+}
+
+// matchContext returns the single context that matches query best. An exact context name always wins,
+// since cluster names repeat across servers.
+func matchContext(query string, contexts []domain.ManagedContext) (domain.ManagedContext, error) {
+	var best []domain.ManagedContext
+	bestRank := noMatch
+	for _, kubeContext := range contexts {
+		if kubeContext.Name == query {
+			return kubeContext, nil
+		}
+		rank := fuzzyRank(query, kubeContext.Name, kubeContext.ClusterName)
+		switch {
+		case rank < bestRank:
+			best, bestRank = []domain.ManagedContext{kubeContext}, rank
+		case rank == bestRank && rank != noMatch:
+			best = append(best, kubeContext)
+		}
+	}
+
+	switch len(best) {
+	case 0:
+		return domain.ManagedContext{}, fmt.Errorf("no cowpoke context matches %q", query)
+	case 1:
+		return best[0], nil
+	default:
+		names := make([]string, len(best))
+		for i, kubeContext := range best {
+			names[i] = kubeContext.Name
+		}
+		return domain.ManagedContext{}, fmt.Errorf("%q matches %d contexts: %s",
+			query, len(best), strings.Join(names, ", "))
+	}
+}
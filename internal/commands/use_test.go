@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func managedContexts() []domain.ManagedContext {
+	return []domain.ManagedContext{
+		{Name: "prod-eu-1a2b3c4d", ClusterName: "prod-eu", Owner: domain.ContextOwner{ServerID: "1a2b3c4d"}},
+		{Name: "prod-us-1a2b3c4d", ClusterName: "prod-us", Owner: domain.ContextOwner{ServerID: "1a2b3c4d"}},
+		{Name: "prod-us-9f8e7d6c", ClusterName: "prod-us", Owner: domain.ContextOwner{ServerID: "9f8e7d6c"}},
+		{Name: "staging-9f8e7d6c", ClusterName: "staging", Owner: domain.ContextOwner{ServerID: "9f8e7d6c"}},
+	}
+}
+
+func TestUseCommand_Execute_Success(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		wantContext string
+	}{
+		{name: "exact context name", query: "prod-us-9f8e7d6c", wantContext: "prod-us-9f8e7d6c"},
+		{name: "unique cluster name", query: "staging", wantContext: "staging-9f8e7d6c"},
+		{name: "unique prefix", query: "stag", wantContext: "staging-9f8e7d6c"},
+		{name: "fuzzy", query: "preu", wantContext: "prod-eu-1a2b3c4d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+			mockConfigProvider := mocks.NewMockConfigProvider(t)
+
+			mockConfigProvider.On("GetDefaultKubeconfigPath").Return("/home/user/.kube/config", nil)
+			mockKubeconfigHandler.On("ListContexts", mock.Anything, "/home/user/.kube/config").
+				Return(managedContexts(), nil)
+			mockKubeconfigHandler.On("UseContext", mock.Anything, "/home/user/.kube/config", tt.wantContext).
+				Return(nil)
+
+			cmd := NewUseCommand(mockKubeconfigHandler, mockConfigProvider, testutil.Logger())
+
+			// Act
+			result, err := cmd.Execute(context.Background(), UseRequest{Query: tt.query})
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantContext, result.Context.Name)
+			assert.True(t, result.Context.Current)
+			assert.Equal(t, "/home/user/.kube/config", result.Kubeconfig)
+		})
+	}
+}
+
+func TestUseCommand_Execute_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{
+			name:    "cluster name on several servers",
+			query:   "prod-us",
+			wantErr: `"prod-us" matches 2 contexts: prod-us-1a2b3c4d, prod-us-9f8e7d6c`,
+		},
+		{
+			name:    "no match",
+			query:   "qa",
+			wantErr: `no cowpoke context matches "qa"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockKubeconfigHandler := mocks.NewMockKubeconfigHandler(t)
+			mockKubeconfigHandler.On("ListContexts", mock.Anything, "/tmp/config").Return(managedContexts(), nil)
+
+			cmd := NewUseCommand(mockKubeconfigHandler, mocks.NewMockConfigProvider(t), testutil.Logger())
+
+			// Act
+			result, err := cmd.Execute(context.Background(), UseRequest{Query: tt.query, Kubeconfig: "/tmp/config"})
+
+			// Assert
+			require.ErrorContains(t, err, tt.wantErr)
+			assert.Nil(t, result)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Clock tells the current time and waits for it to pass. Services that compute expiry or backoff, or wait
+// before retrying, take a Clock instead of calling time.Now or time.After so tests can control time.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After returns a channel that receives the current time once d has passed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock backed by the system time.
+type SystemClock struct{}
+
+// Now returns the current system time.
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// After waits for d to pass on the system clock.
+func (SystemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
@@ -1,27 +1,96 @@
 package domain
 
 import (
+	"cmp"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
 	"net/url"
+	"strings"
+	"time"
 )
 
-// ConfigRepository manages the cowpoke configuration.
+// DefaultProfile names the profile formed by the top-level servers of the configuration.
+const DefaultProfile = "default"
+
+// ConfigRepository manages the cowpoke configuration. Server and defaults accessors operate on the
+// active profile.
 type ConfigRepository interface {
+	Profile() string
 	GetServers(ctx context.Context) ([]ConfigServer, error)
+	GetAllServers(ctx context.Context) ([]ConfigServer, error)
+	GetDefaults(ctx context.Context) (ProfileDefaults, error)
 	AddServer(ctx context.Context, server ConfigServer) error
 	RemoveServer(ctx context.Context, serverURL string) error
 	RemoveServerByID(ctx context.Context, serverID string) error
+	UpdateServerURL(ctx context.Context, fromURL, toURL string) error
+	UpdateServerAuthType(ctx context.Context, serverURL, authType string) error
 	SaveConfig(ctx context.Context) error
 	LoadConfig(ctx context.Context) error
+	GetSettings(ctx context.Context) (Settings, error)
+	// Encrypted reports whether the configuration file is stored encrypted.
+	Encrypted() bool
+	// SetEncrypted rewrites the configuration file encrypted or in plain text.
+	SetEncrypted(ctx context.Context, encrypted bool) error
+	// Backups lists the backups of the configuration file, newest first.
+	Backups(ctx context.Context) ([]ConfigBackup, error)
+	// RestoreBackup replaces the configuration file with the backup taken at timestamp, or with the only
+	// backup whose timestamp starts with it. The file it replaces is backed up first.
+	RestoreBackup(ctx context.Context, timestamp string) (ConfigBackup, error)
+}
+
+// ConfigBackup is a copy of the configuration file taken before a change to it.
+type ConfigBackup struct {
+	// Timestamp identifies the backup, e.g. "20261015T093012.345Z".
+	Timestamp string
+	// Time is when the backup was taken.
+	Time time.Time
+	Path string
+	Size int64
+}
+
+// ErrConfigDecryption indicates an encrypted configuration file could not be decrypted.
+var ErrConfigDecryption = errors.New("failed to decrypt the configuration file")
+
+// ErrServerVerification indicates a server being added was unreachable or does not offer its auth type.
+var ErrServerVerification = errors.New("server verification failed")
+
+// ErrManagedServer indicates a change to a server managed by the system configuration.
+var ErrManagedServer = errors.New("server is managed by the system configuration")
+
+// ConfigValidator checks raw configuration data against the current schema.
+type ConfigValidator interface {
+	Validate(ctx context.Context, data []byte) []ConfigIssue
+}
+
+// Severity levels for configuration issues.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// ConfigIssue is a problem found in the configuration file, anchored to its position.
+// Line and Column are 1-based; zero means the position is unknown.
+type ConfigIssue struct {
+	Line     int
+	Column   int
+	Severity string
+	Message  string
 }
 
 // ConfigProvider provides configuration paths and defaults.
 type ConfigProvider interface {
 	GetDefaultKubeconfigPath() (string, error)
+	// GetKubectlKubeconfigPath returns the kubeconfig kubectl reads when KUBECONFIG is not set, which is the
+	// default kubeconfig unless cowpoke keeps its contexts separate.
+	GetKubectlKubeconfigPath() (string, error)
 	GetKubeconfigDir() (string, error)
 	GetConfigPath() (string, error)
+	GetStateDir() (string, error)
+	GetSystemConfigPath() string
 }
 
 // ConfigServer represents a Rancher server in the configuration.
@@ -29,6 +98,372 @@ type ConfigServer struct {
 	URL      string `yaml:"url"`
 	Username string `yaml:"username"`
 	AuthType string `yaml:"authType"`
+	// Managed marks a server in the system configuration that cannot be edited or removed locally.
+	Managed bool `yaml:"managed,omitempty"`
+	// ClientCert and ClientKey are paths to a PEM client certificate and key presented to gateways that
+	// require mutual TLS in front of Rancher.
+	ClientCert string `yaml:"clientCert,omitempty"`
+	ClientKey  string `yaml:"clientKey,omitempty"`
+	// Tunnel reaches a server only accessible through a bastion, either through a SOCKS5 proxy
+	// ("socks5://host:port") or an SSH tunnel cowpoke starts itself ("ssh user@bastion").
+	Tunnel string `yaml:"tunnel,omitempty"`
+	// Tags are free-form labels for grouping servers.
+	Tags []string `yaml:"tags,omitempty"`
+	// Alias is a short name for the server, such as "prod", that its contexts can be grouped under.
+	Alias string `yaml:"alias,omitempty"`
+	// Priority groups servers for syncing: groups are synced and merged in ascending order of priority,
+	// starting at 1, and servers without a priority are synced last.
+	Priority int `yaml:"priority,omitempty"`
+	// Sync holds sync settings of this server only; its exclude patterns apply to the server's clusters.
+	Sync SyncSettings `yaml:"sync,omitempty"`
+}
+
+// ProfileDefaults holds per-profile defaults for sync options not given on the command line.
+type ProfileDefaults struct {
+	// Output is the kubeconfig path written when --output is not set.
+	Output string `yaml:"output,omitempty"`
+	// Exclude holds cluster name patterns excluded in addition to any --exclude flags.
+	Exclude []string `yaml:"exclude,omitempty"`
+	// ExcludeTypes holds cluster types excluded in addition to any --exclude-type flags.
+	ExcludeTypes []string `yaml:"excludeTypes,omitempty"`
+}
+
+// NormalizeServerURL validates a Rancher server URL and returns it in canonical form: an http or https
+// scheme, a lowercase host without the scheme's default port, and no trailing slash.
+func NormalizeServerURL(serverURL string) (string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(serverURL))
+	if err != nil {
+		return "", fmt.Errorf("invalid server URL %q: %w", serverURL, err)
+	}
+	scheme := strings.ToLower(parsed.Scheme)
+	switch {
+	case scheme != "https" && scheme != "http":
+		return "", fmt.Errorf("invalid server URL %q: must start with https://", serverURL)
+	case parsed.Hostname() == "":
+		return "", fmt.Errorf("invalid server URL %q: must include a host", serverURL)
+	case parsed.User != nil || parsed.RawQuery != "" || parsed.Fragment != "":
+		return "", fmt.Errorf("invalid server URL %q: must not include credentials, a query or a fragment", serverURL)
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	port := parsed.Port()
+	if (scheme == "https" && port == "443") || (scheme == "http" && port == "80") {
+		port = ""
+	}
+	if port != "" {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	return scheme + "://" + host + strings.TrimRight(parsed.EscapedPath(), "/"), nil
+}
+
+// ParseTunnel validates a server's tunnel, returning either the SOCKS5 proxy URL or the SSH destination.
+func ParseTunnel(tunnel string) (*url.URL, string, error) {
+	if destination, ok := strings.CutPrefix(tunnel, "ssh "); ok {
+		destination = strings.TrimSpace(destination)
+		if destination == "" || strings.HasPrefix(destination, "-") || strings.ContainsAny(destination, " \t") {
+			return nil, "", fmt.Errorf("invalid SSH tunnel %q (use \"ssh user@bastion\")", tunnel)
+		}
+		return nil, destination, nil
+	}
+
+	proxyURL, err := url.Parse(tunnel)
+	if err != nil || (proxyURL.Scheme != "socks5" && proxyURL.Scheme != "socks5h") || proxyURL.Host == "" {
+		return nil, "", fmt.Errorf("invalid tunnel %q (use \"socks5://host:port\" or \"ssh user@bastion\")", tunnel)
+	}
+	return proxyURL, "", nil
+}
+
+// AuthTypeToken is the auth type of servers that authenticate with a long-lived API token stored in the
+// token cache instead of logging in with a password.
+const AuthTypeToken = "token"
+
+// SupportedAuthTypes returns the Rancher authentication providers cowpoke can log in with.
+func SupportedAuthTypes() []string {
+	return []string{
+		"local",
+		"openldap",
+		"activedirectory",
+		"github",
+		"googleoauth",
+		"shibboleth",
+		"azuread",
+		"keycloak",
+		"ping",
+		"okta",
+		"freeipa",
+	}
+}
+
+// Settings holds optional behaviour persisted alongside the server inventory.
+type Settings struct {
+	Fragments   FragmentSettings   `yaml:"fragments,omitempty"`
+	HTTP        HTTPSettings       `yaml:"http,omitempty"`
+	Updates     UpdateSettings     `yaml:"updates,omitempty"`
+	Expiry      ExpirySettings     `yaml:"expiry,omitempty"`
+	Naming      NamingSettings     `yaml:"naming,omitempty"`
+	Permissions PermissionSettings `yaml:"permissions,omitempty"`
+	Templates   []TemplateSettings `yaml:"templates,omitempty"`
+	Hooks       HookSettings       `yaml:"hooks,omitempty"`
+	Limits      LimitSettings      `yaml:"limits,omitempty"`
+	Discovery   DiscoverySettings  `yaml:"discovery,omitempty"`
+	Sync        SyncSettings       `yaml:"sync,omitempty"`
+	Endpoints   EndpointSettings   `yaml:"endpoints,omitempty"`
+	State       StateSettings      `yaml:"state,omitempty"`
+	Backups     BackupSettings     `yaml:"backups,omitempty"`
+}
+
+// BackupSettings controls the backups of the configuration file taken before every change to it.
+type BackupSettings struct {
+	// Keep is how many backups are kept, removing the oldest first. Zero uses the default of 20; a negative
+	// value disables backups.
+	Keep int `yaml:"keep,omitempty"`
+}
+
+// Retained returns how many backups are kept, or zero if backups are disabled.
+func (s BackupSettings) Retained() int {
+	const defaultKeep = 20
+	return configuredLimit(s.Keep, defaultKeep)
+}
+
+// StateSettings controls how cowpoke keeps its kubeconfig cache, runtime state and cached tokens.
+type StateSettings struct {
+	// IsolateProfiles gives every profile but the default one its own kubeconfig cache, runtime state and
+	// cached tokens, so that tenants sharing an account under different profiles never see each other's.
+	IsolateProfiles bool `yaml:"isolateProfiles,omitempty"`
+}
+
+// SyncSettings holds options applied to every sync in addition to those given on the command line.
+type SyncSettings struct {
+	// ExcludePatterns holds regular expressions of context names left out of the merged kubeconfig, as
+	// with --exclude.
+	ExcludePatterns []string `yaml:"excludePatterns,omitempty"`
+	// CheckEndpoints warns after every sync about cluster endpoints that do not resolve, as with
+	// --check-endpoints. It is ignored on servers.
+	CheckEndpoints bool `yaml:"checkEndpoints,omitempty"`
+	// ShareCAs writes CA bundles repeated across clusters to shared files next to the merged kubeconfig
+	// instead of embedding every copy. It is ignored on servers.
+	ShareCAs bool `yaml:"shareCAs,omitempty"`
+	// ExecCredentials writes contexts whose users run cowpoke credential to fetch their token when kubectl
+	// needs it, instead of embedding the token in the merged kubeconfig.
+	ExecCredentials bool `yaml:"execCredentials,omitempty"`
+	// SeparateKubeconfig writes the merged kubeconfig to ~/.kube/cowpoke.config instead of ~/.kube/config,
+	// leaving kubectl's own kubeconfig alone. cowpoke env prints the KUBECONFIG that combines the two.
+	SeparateKubeconfig bool `yaml:"separateKubeconfig,omitempty"`
+}
+
+// DiscoverySettings controls which of the clusters a server reports are synced.
+type DiscoverySettings struct {
+	// SkipLocalCluster leaves out the local cluster Rancher itself runs on. It defaults to true.
+	SkipLocalCluster *bool `yaml:"skipLocalCluster,omitempty"`
+}
+
+// SkipLocal reports whether the local cluster is left out, as it is unless SkipLocalCluster is false.
+func (s DiscoverySettings) SkipLocal() bool {
+	return s.SkipLocalCluster == nil || *s.SkipLocalCluster
+}
+
+// LimitSettings guards against merging more contexts than tools parsing the merged kubeconfig cope with,
+// such as a whole company's fleet.
+type LimitSettings struct {
+	// WarnContexts warns when a sync writes more than this many contexts. Zero uses the default of 200; a
+	// negative value disables the warning.
+	WarnContexts int `yaml:"warnContexts,omitempty"`
+	// MaxContexts fails a sync that would write more than this many contexts unless it is forced. Zero uses
+	// the default of 500; a negative value disables the limit.
+	MaxContexts int `yaml:"maxContexts,omitempty"`
+}
+
+// Warn returns the number of contexts above which a sync warns, or zero if the warning is disabled.
+func (s LimitSettings) Warn() int {
+	const defaultWarnContexts = 200
+	return configuredLimit(s.WarnContexts, defaultWarnContexts)
+}
+
+// Max returns the number of contexts above which a sync must be forced, or zero if there is no limit.
+func (s LimitSettings) Max() int {
+	const defaultMaxContexts = 500
+	return configuredLimit(s.MaxContexts, defaultMaxContexts)
+}
+
+// configuredLimit returns the configured limit, fallback if none is configured, or zero if it is disabled.
+func configuredLimit(configured, fallback int) int {
+	switch {
+	case configured < 0:
+		return 0
+	case configured == 0:
+		return fallback
+	default:
+		return configured
+	}
+}
+
+// HookSettings runs commands before and after each sync, e.g. to check that a VPN is up or to notify
+// teammates. Commands are run by the shell.
+type HookSettings struct {
+	// PreSync runs before anything is downloaded. A failing hook stops the sync unless its OnFailure says
+	// otherwise.
+	PreSync []Hook `yaml:"preSync,omitempty"`
+	// PostSync runs after every sync, successful or not, with its report as JSON on stdin. A failing hook
+	// is only warned about unless its OnFailure says otherwise.
+	PostSync []Hook `yaml:"postSync,omitempty"`
+}
+
+// Hook is a command run before or after a sync.
+type Hook struct {
+	Command string `yaml:"command"`
+	// TimeoutSeconds stops the command after this many seconds. Zero uses the default of 60 seconds.
+	TimeoutSeconds int `yaml:"timeoutSeconds,omitempty"`
+	// OnFailure is what a failing command does to the sync: "fail" fails it, "warn" logs a warning and
+	// "ignore" carries on silently. Empty uses "fail" for pre-sync hooks and "warn" for post-sync hooks.
+	OnFailure string `yaml:"onFailure,omitempty"`
+}
+
+// TemplateSettings generates a companion file for every context after a sync, such as a Telepresence
+// config, an SSH config stanza or a k9s skin. Source is rendered with text/template once per context, with
+// the fields of TemplateData.
+type TemplateSettings struct {
+	// Source is the template file. Relative paths are relative to the merged kubeconfig's directory, and a
+	// leading ~ is the home directory.
+	Source string `yaml:"source"`
+	// Output is the path of each context's file, itself a template such as "telepresence/{{.Context}}.yaml",
+	// resolved like Source. Contexts whose files share a path are rendered into one file, in name order.
+	Output string `yaml:"output"`
+}
+
+// TemplateData holds what templates know about a context.
+type TemplateData struct {
+	// Name is the cluster's name.
+	Name string
+	// Context is the context's name.
+	Context string
+	// Server is the URL of the Rancher server the cluster belongs to.
+	Server string
+	// Endpoint is the API server URL of the cluster.
+	Endpoint string
+	// ClusterID is the cluster's Rancher ID, if known.
+	ClusterID string
+}
+
+// PermissionSettings controls the permissions of the merged kubeconfigs cowpoke writes and of the
+// directories created for them, e.g. to let a team's group read a shared kubeconfig. Cached fragments and
+// the configuration file are always private to the user.
+type PermissionSettings struct {
+	// FileMode is the octal mode of merged kubeconfigs, such as "0640". Empty uses 0600.
+	FileMode string `yaml:"fileMode,omitempty"`
+	// DirMode is the octal mode of the directories created for them, such as "0750". Empty uses 0700.
+	DirMode string `yaml:"dirMode,omitempty"`
+	// Group is the name or numeric ID of the group made the group owner of merged kubeconfigs. Empty leaves
+	// the group alone.
+	Group string `yaml:"group,omitempty"`
+}
+
+// EndpointSettings controls the cluster endpoints written to kubeconfigs.
+type EndpointSettings struct {
+	// Rewrites change the server URLs Rancher advertises, e.g. to reach clusters through a VPN hostname.
+	Rewrites []EndpointRewrite `yaml:"rewrites,omitempty"`
+}
+
+// EndpointRewrite replaces the part of a cluster's server URL that matches the regular expression Match
+// with Replace, which may refer to submatches as $1 or ${name}.
+type EndpointRewrite struct {
+	Match   string `yaml:"match"`
+	Replace string `yaml:"replace"`
+}
+
+// NamingSettings controls how contexts and fragments are named after their clusters. Names that come from
+// Rancher are sanitized under the policy set here before they are used; see Sanitize.
+type NamingSettings struct {
+	// PreferDisplayName names contexts after the clusters' display names instead of the names in the
+	// kubeconfigs Rancher generates. Clusters of a server that would end up with the same name are told
+	// apart by their cluster IDs.
+	PreferDisplayName bool `yaml:"preferDisplayName,omitempty"`
+	// Replacement replaces each run of characters that names may not contain. Empty uses "-".
+	Replacement string `yaml:"replacement,omitempty"`
+	// Lowercase lower-cases names.
+	Lowercase bool `yaml:"lowercase,omitempty"`
+	// MaxLength truncates names to at most this many characters. Zero leaves their length alone.
+	MaxLength int `yaml:"maxLength,omitempty"`
+	// RFC1123 makes names valid RFC 1123 labels, as Kubernetes requires of most resource names: lower-case
+	// letters, digits and dashes, starting and ending with a letter or digit, and at most 63 characters.
+	RFC1123 bool `yaml:"rfc1123,omitempty"`
+	// Grouping prefixes context names with a group of their server's.
+	Grouping GroupingSettings `yaml:"grouping,omitempty"`
+}
+
+// GroupingSettings prefixes the names of contexts in merged kubeconfigs with a group, e.g. "prod/cluster-a-55110d2f",
+// so that the contexts of a group sort together in tools that list contexts alphabetically.
+type GroupingSettings struct {
+	// By is what contexts are grouped by: "alias" uses their server's alias and "tag" its first tag. Empty
+	// leaves contexts ungrouped, as are the contexts of servers without an alias or tag.
+	By string `yaml:"by,omitempty"`
+	// Separator separates the group from the context name. Empty uses "/".
+	Separator string `yaml:"separator,omitempty"`
+}
+
+// ExpirySettings controls the warning about kubeconfig credentials that are about to expire.
+type ExpirySettings struct {
+	// WarnDays warns after each command about contexts whose credentials expire within this many days.
+	// Zero uses the default of 7 days; a negative value disables the warning.
+	WarnDays int `yaml:"warnDays,omitempty"`
+}
+
+// Window returns how far ahead expiring credentials are warned about, or zero if the warning is disabled.
+func (s ExpirySettings) Window() time.Duration {
+	const defaultWarnDays = 7
+	switch {
+	case s.WarnDays < 0:
+		return 0
+	case s.WarnDays == 0:
+		return defaultWarnDays * 24 * time.Hour
+	default:
+		return time.Duration(s.WarnDays) * 24 * time.Hour
+	}
+}
+
+// UpdateSettings controls checking for newer cowpoke releases.
+type UpdateSettings struct {
+	// Check prints a notice after a command when a newer release exists. The latest release is looked up
+	// at most once a day.
+	Check bool `yaml:"check,omitempty"`
+}
+
+// HTTPSettings tunes the connections made to Rancher servers.
+type HTTPSettings struct {
+	// MaxIdleConnsPerHost is how many idle keep-alive connections are kept open to each server for
+	// reuse. Zero uses the default of 16.
+	MaxIdleConnsPerHost int `yaml:"maxIdleConnsPerHost,omitempty"`
+}
+
+// IdleConnsPerHost returns the number of idle connections to keep per server.
+func (s HTTPSettings) IdleConnsPerHost() int {
+	const defaultIdleConnsPerHost = 16
+	return cmp.Or(s.MaxIdleConnsPerHost, defaultIdleConnsPerHost)
+}
+
+// FragmentSettings controls how downloaded per-cluster kubeconfigs are stored on disk.
+type FragmentSettings struct {
+	// Encrypt enables AES-256-GCM encryption of cached fragments.
+	Encrypt bool `yaml:"encrypt,omitempty"`
+	// KeySource selects where the encryption key comes from: "passphrase" (default) or "keychain".
+	KeySource string `yaml:"keySource,omitempty"`
+	// RetentionDays removes fragments not refreshed within this many days. Zero uses the
+	// default of 30 days; a negative value disables age-based cleanup.
+	RetentionDays int `yaml:"retentionDays,omitempty"`
+}
+
+// Retention returns the maximum fragment age, or zero if age-based cleanup is disabled.
+func (s FragmentSettings) Retention() time.Duration {
+	const defaultRetentionDays = 30
+	switch {
+	case s.RetentionDays < 0:
+		return 0
+	case s.RetentionDays == 0:
+		return defaultRetentionDays * 24 * time.Hour
+	default:
+		return time.Duration(s.RetentionDays) * 24 * time.Hour
+	}
 }
 
 // ID returns a deterministic 8-character ID generated from the server domain.
@@ -1,6 +1,9 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // RancherClient handles all Rancher API operations.
 type RancherClient interface {
@@ -12,29 +15,147 @@ type RancherClient interface {
 
 	// GetKubeconfig retrieves the kubeconfig for a specific cluster.
 	GetKubeconfig(ctx context.Context, token AuthToken, server ConfigServer, clusterID string) ([]byte, error)
+
+	// ScopeKubeconfig replaces the token in a generated kubeconfig with a new token scoped to the cluster that
+	// expires after ttl, revoking the generated token. It returns the kubeconfig and the new token's expiry,
+	// which the server may have capped below ttl.
+	ScopeKubeconfig(
+		ctx context.Context,
+		token AuthToken,
+		server ConfigServer,
+		clusterID string,
+		kubeconfig []byte,
+		ttl time.Duration,
+	) ([]byte, time.Time, error)
+
+	// ProxyKubeconfig points every cluster of a generated kubeconfig at the server's /k8s/clusters proxy for
+	// clusterID, so that it reaches the cluster through Rancher even where it has its own endpoints.
+	ProxyKubeconfig(ctx context.Context, server ConfigServer, clusterID string, kubeconfig []byte) ([]byte, error)
+
+	// KubeconfigExpiry returns when the tokens of a kubeconfig generated by server expire, the earliest if
+	// there are several, or zero if they never expire.
+	KubeconfigExpiry(ctx context.Context, token AuthToken, server ConfigServer, kubeconfig []byte) (time.Time, error)
+
+	// GetVersion retrieves the Rancher release running on a server without authenticating.
+	GetVersion(ctx context.Context, server ConfigServer) (ServerVersion, error)
+
+	// AuthProviders lists the authentication providers enabled on a server without authenticating.
+	AuthProviders(ctx context.Context, server ConfigServer) ([]AuthProvider, error)
+
+	// CreateAPIToken creates a long-lived API token for the user of a session, which never expires unless
+	// the server caps token lifetimes. Its ExpiresAt is zero if it never expires.
+	CreateAPIToken(ctx context.Context, session AuthToken, server ConfigServer) (AuthToken, error)
+
+	// ClusterOwnership looks up the user a token belongs to and the clusters they hold the cluster-owner
+	// role on.
+	ClusterOwnership(ctx context.Context, token AuthToken, server ConfigServer) (ClusterOwnership, error)
+
+	// RevokeToken deletes a token in Rancher so it can no longer be used.
+	RevokeToken(ctx context.Context, token AuthToken, server ConfigServer) error
 }
 
 // KubeconfigHandler handles all kubeconfig file operations.
 type KubeconfigHandler interface {
 	// SaveKubeconfig saves a kubeconfig to a file after preprocessing to avoid conflicts.
-	SaveKubeconfig(ctx context.Context, path string, content []byte, serverID string) error
+	// Every context is tagged with owner so it can later be traced back to its server and cluster.
+	SaveKubeconfig(ctx context.Context, path string, content []byte, owner ContextOwner) error
 
 	// MergeKubeconfigs merges multiple kubeconfig files into one, applying cluster filtering.
 	// The filter is applied to context and cluster names within each kubeconfig before merging.
 	MergeKubeconfigs(ctx context.Context, paths []string, outputPath string, filter ClusterFilter) error
 
+	// CountContexts returns how many contexts merging the kubeconfigs at paths under filter would write.
+	CountContexts(ctx context.Context, paths []string, filter ClusterFilter) int
+
 	// CleanupTempFiles removes temporary kubeconfig files.
 	CleanupTempFiles(ctx context.Context, paths []string) error
+
+	// RenameServer rewrites the contexts owned by a server in the kubeconfig at path to the naming of
+	// its new URL, returning the number of contexts rewritten.
+	RenameServer(ctx context.Context, path string, from, to ConfigServer) (int, error)
+
+	// ListContexts returns the contexts cowpoke generated in the kubeconfig at path, sorted by name.
+	ListContexts(ctx context.Context, path string) ([]ManagedContext, error)
+
+	// ForeignContexts returns the names of the contexts in the kubeconfig at path that cowpoke did not
+	// generate, sorted.
+	ForeignContexts(ctx context.Context, path string) ([]string, error)
+
+	// SnapshotContexts returns every context in the kubeconfig at path with its endpoint and a fingerprint
+	// of its credentials, sorted by name, to tell what a sync changed.
+	SnapshotContexts(ctx context.Context, path string) ([]ContextSnapshot, error)
+
+	// ReadKubeconfig returns the kubeconfig at path, such as a cached fragment, decrypted. With redact, its
+	// credentials are replaced by a placeholder.
+	ReadKubeconfig(ctx context.Context, path string, redact bool) ([]byte, error)
+
+	// ClusterCredential returns the bearer token of the kubeconfig at path, such as a cached fragment.
+	ClusterCredential(ctx context.Context, path string) (ClusterCredential, error)
+
+	// UseContext makes name the current context of the kubeconfig at path.
+	UseContext(ctx context.Context, path, name string) error
+
+	// RemoveContexts removes the named contexts from the kubeconfig at path, with the clusters and users
+	// no other context references, and returns how many it removed.
+	RemoveContexts(ctx context.Context, path string, names []string) (int, error)
+}
+
+// FragmentValidator checks downloaded kubeconfig fragments before they are merged.
+type FragmentValidator interface {
+	// ValidateFragment returns the problems that keep the fragment at path from being merged, or none if it
+	// can be merged.
+	ValidateFragment(ctx context.Context, path string) []string
+
+	// QuarantineFragment moves the fragment at path to the quarantine directory, next to a file listing
+	// its problems, and returns its new path.
+	QuarantineFragment(ctx context.Context, path string, problems []string) (string, error)
+}
+
+// TemplateRenderer generates companion files, such as Telepresence configs or SSH config stanzas, from
+// user-provided templates after a sync.
+type TemplateRenderer interface {
+	// RenderTemplates renders each template once per context, resolving relative paths against dir, and
+	// returns the paths of the files it wrote.
+	RenderTemplates(ctx context.Context, templates []TemplateSettings, dir string, contexts []ManagedContext) (
+		[]string, error)
 }
 
 // SyncOrchestrator orchestrates the entire kubeconfig synchronization process.
 type SyncOrchestrator interface {
 	// SyncServers performs concurrent discovery and download of kubeconfigs from the provided servers.
-	// Returns a SyncResult containing paths to downloaded kubeconfig files and statistics.
-	// Filtering is now handled at the kubeconfig merge level, not during download.
+	// Returns a SyncResult containing paths to downloaded kubeconfig files, the downloads that failed, and
+	// statistics. Clusters are filtered by name at the kubeconfig merge level, and only by type during download.
 	SyncServers(
 		ctx context.Context,
 		servers []ConfigServer,
 		passwords map[string]string,
+		options SyncOptions,
+	) (*SyncResult, error)
+
+	// SyncClusters downloads the kubeconfigs of known clusters without listing the servers' clusters.
+	SyncClusters(
+		ctx context.Context,
+		targets []ServerClusters,
+		passwords map[string]string,
+		options SyncOptions,
 	) (*SyncResult, error)
+
+	// Preauthenticate logs in to the servers with their passwords ahead of a sync, keeping the tokens for it.
+	// Returns the errors of the servers that could not log in, keyed by server ID.
+	Preauthenticate(ctx context.Context, servers []ConfigServer, passwords map[string]string) map[string]error
+}
+
+// FragmentCache manages the directory of cached per-cluster kubeconfig fragments.
+type FragmentCache interface {
+	// Clean removes fragments according to the given policy.
+	Clean(ctx context.Context, policy CleanupPolicy) (*CleanupResult, error)
+
+	// List returns every cached fragment, enriched with metadata from the cache index.
+	List(ctx context.Context) ([]CacheEntry, error)
+
+	// Record upserts index entries for freshly downloaded fragments.
+	Record(ctx context.Context, entries []CacheEntry) error
+
+	// Dir returns the directory holding the cached fragments.
+	Dir() string
 }
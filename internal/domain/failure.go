@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// ErrNetwork indicates that a request failed in transport, before Rancher could answer.
+var ErrNetwork = errors.New("network error")
+
+// ErrForbidden indicates that Rancher denied the user access to a resource.
+var ErrForbidden = errors.New("permission denied")
+
+// ErrRateLimited indicates that Rancher or a gateway in front of it throttled requests.
+var ErrRateLimited = errors.New("rate limited")
+
+// ErrMalformedResponse indicates that Rancher answered with JSON that could not be decoded.
+var ErrMalformedResponse = errors.New("malformed response")
+
+// ErrInvalidKubeconfig indicates that a downloaded kubeconfig could not be parsed.
+var ErrInvalidKubeconfig = errors.New("invalid kubeconfig")
+
+// FailureCategory is the broad cause of a failed server or cluster sync.
+type FailureCategory string
+
+// Failure categories, from the most to the least specific.
+const (
+	FailureAuth       FailureCategory = "auth"
+	FailurePermission FailureCategory = "permission"
+	FailureRateLimit  FailureCategory = "rate-limit"
+	FailureNetwork    FailureCategory = "network"
+	FailureParse      FailureCategory = "parse"
+	FailureOther      FailureCategory = "other"
+)
+
+// ClassifyFailure returns the category of a sync error.
+func ClassifyFailure(err error) FailureCategory {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		return FailureAuth
+	case errors.Is(err, ErrForbidden):
+		return FailurePermission
+	case errors.Is(err, ErrRateLimited):
+		return FailureRateLimit
+	case errors.Is(err, ErrServerUnavailable), IsNetworkError(err):
+		return FailureNetwork
+	case errors.Is(err, ErrMalformedResponse), errors.Is(err, ErrInvalidKubeconfig),
+		errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return FailureParse
+	default:
+		return FailureOther
+	}
+}
+
+// IsNetworkError reports whether err is a transport failure: a failed dial, read or write, a DNS lookup
+// failure, a TLS handshake or certificate error, or a timeout.
+func IsNetworkError(err error) bool {
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	var recordErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	var verifyErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	return errors.Is(err, ErrNetwork) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.As(err, &opErr) ||
+		errors.As(err, &dnsErr) ||
+		(errors.As(err, &netErr) && netErr.Timeout()) ||
+		errors.As(err, &recordErr) ||
+		errors.As(err, &alertErr) ||
+		errors.As(err, &verifyErr) ||
+		errors.As(err, &authorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidErr)
+}
+
+// WrapNetworkError marks a transport failure with ErrNetwork, so that it is recognized however it is
+// wrapped later. Other errors are returned unchanged.
+func WrapNetworkError(err error) error {
+	if err == nil || errors.Is(err, ErrNetwork) || !IsNetworkError(err) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrNetwork, err)
+}
+
+// IsCertificateError reports whether err is a server certificate that could not be verified or a client
+// certificate the server rejected.
+func IsCertificateError(err error) bool {
+	var verifyErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	var alertErr tls.AlertError
+	return errors.As(err, &verifyErr) ||
+		errors.As(err, &authorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidErr) ||
+		errors.As(err, &alertErr)
+}
@@ -2,16 +2,21 @@ package domain
 
 import (
 	"os"
+	"time"
 )
 
 // FileSystemAdapter defines the interface for file operations.
 type FileSystemAdapter interface {
 	ReadFile(path string) ([]byte, error)
+	ReadDir(path string) ([]os.DirEntry, error)
 	WriteFile(path string, data []byte, perm os.FileMode) error
 	MkdirAll(path string, perm os.FileMode) error
 	Remove(path string) error
+	Rename(oldPath, newPath string) error
 	Stat(path string) (os.FileInfo, error)
 	Chmod(path string, perm os.FileMode) error
+	Chown(path string, uid, gid int) error
+	Chtimes(path string, atime, mtime time.Time) error
 	UserHomeDir() (string, error)
 	TempDir() string
 }
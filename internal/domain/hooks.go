@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"cmp"
+	"context"
+	"time"
+)
+
+// Failure policies of hooks.
+const (
+	HookFail   = "fail"
+	HookWarn   = "warn"
+	HookIgnore = "ignore"
+)
+
+// HookRunner runs the commands of hooks.
+type HookRunner interface {
+	// RunHook runs command with the shell, feeding it stdin and adding env to its environment. Its output
+	// is shown to the user.
+	RunHook(ctx context.Context, command string, env []string, stdin []byte) error
+}
+
+// Timeout returns how long the hook's command may run.
+func (h Hook) Timeout() time.Duration {
+	const defaultTimeoutSeconds = 60
+	return time.Duration(cmp.Or(h.TimeoutSeconds, defaultTimeoutSeconds)) * time.Second
+}
+
+// Policy returns the hook's failure policy, or fallback if it sets none.
+func (h Hook) Policy(fallback string) string {
+	return cmp.Or(h.OnFailure, fallback)
+}
+
+// ValidHookPolicy reports whether policy is a known failure policy of hooks.
+func ValidHookPolicy(policy string) bool {
+	return policy == HookFail || policy == HookWarn || policy == HookIgnore
+}
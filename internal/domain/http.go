@@ -15,4 +15,18 @@ type HTTPAdapter interface {
 		url, token string,
 		payload any,
 	) (*http.Response, error)
+	DeleteWithAuth(ctx context.Context, url, token string) (*http.Response, error)
+	// CloseHost stops the SSH tunnel to the host of serverURL and closes its idle connections. Later
+	// requests to the host open them again.
+	CloseHost(serverURL string) error
+}
+
+// HostCloser closes what is kept open to a server between requests, as HTTPAdapter.CloseHost does.
+type HostCloser interface {
+	CloseHost(serverURL string) error
+}
+
+// Resolver looks up host names, as net.Resolver does.
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
 }
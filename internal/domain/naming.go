@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"cmp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// rfc1123MaxLength is the longest valid RFC 1123 label.
+	rfc1123MaxLength = 63
+	// serverIDSuffixLength is the length of the "-<server ID>" suffix appended to cluster names.
+	serverIDSuffixLength = 9
+	// GroupByAlias groups contexts by their server's alias.
+	GroupByAlias = "alias"
+	// GroupByTag groups contexts by their server's first tag.
+	GroupByTag = "tag"
+	// defaultGroupSeparator separates groups from context names unless another separator is configured.
+	defaultGroupSeparator = "/"
+)
+
+// Sanitize makes name safe to use in kubeconfig resource and file names. Names may contain ASCII letters,
+// digits, dots, dashes and underscores, or only what RFC1123 allows; each run of other characters becomes
+// the replacement, which is also trimmed from both ends. Names are then lower-cased and truncated as
+// configured. Sanitizing a sanitized name leaves it unchanged.
+func (s NamingSettings) Sanitize(name string) string {
+	return s.sanitize(name, 0)
+}
+
+// ClusterName sanitizes a cluster name, leaving room for the server ID suffix that is appended to form
+// context names so that they stay within the length limits.
+func (s NamingSettings) ClusterName(name string) string {
+	return s.sanitize(name, serverIDSuffixLength)
+}
+
+// DistinctClusterName is ClusterName with the cluster ID appended, for telling apart clusters of a server
+// that would otherwise get the same name. The ID is kept whole when the name has to be truncated.
+func (s NamingSettings) DistinctClusterName(name, clusterID string) string {
+	suffix := "-" + s.Sanitize(clusterID)
+	base := s.sanitize(name, serverIDSuffixLength+len(suffix))
+	if base == "" {
+		return s.ClusterName(clusterID)
+	}
+	return base + suffix
+}
+
+// sanitize sanitizes name, leaving room for reserved more characters within the length limits.
+func (s NamingSettings) sanitize(name string, reserved int) string {
+	replacement := s.replacement()
+	lowercase := s.Lowercase || s.RFC1123
+	if lowercase {
+		name = strings.ToLower(name)
+	}
+
+	var b strings.Builder
+	replaced := false
+	for _, r := range name {
+		if s.allowed(r) {
+			b.WriteRune(r)
+			replaced = false
+			continue
+		}
+		if !replaced {
+			b.WriteString(replacement)
+			replaced = true
+		}
+	}
+	sanitized := s.trim(b.String(), replacement)
+
+	if maxLength := s.maxLength(); maxLength > 0 {
+		limit := max(maxLength-reserved, 1)
+		if utf8.RuneCountInString(sanitized) > limit {
+			sanitized = s.trim(string([]rune(sanitized)[:limit]), replacement)
+		}
+	}
+	return sanitized
+}
+
+// allowed reports whether names may contain r.
+func (s NamingSettings) allowed(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+		return true
+	case r >= 'A' && r <= 'Z', r == '.', r == '_':
+		return !s.RFC1123
+	default:
+		return false
+	}
+}
+
+// trim removes the replacement from both ends of name, and under RFC1123 anything but a letter or digit.
+func (s NamingSettings) trim(name, replacement string) string {
+	for replacement != "" && strings.HasPrefix(name, replacement) {
+		name = strings.TrimPrefix(name, replacement)
+	}
+	for replacement != "" && strings.HasSuffix(name, replacement) {
+		name = strings.TrimSuffix(name, replacement)
+	}
+	if s.RFC1123 {
+		name = strings.Trim(name, "-")
+	}
+	return name
+}
+
+// ValidReplacement reports whether names may contain the configured replacement, which is only used if so.
+func (s NamingSettings) ValidReplacement() bool {
+	return strings.IndexFunc(s.Replacement, func(r rune) bool { return !s.allowed(r) }) < 0
+}
+
+// replacement returns the configured replacement, or "-" if none is set or it is not valid.
+func (s NamingSettings) replacement() string {
+	if s.Replacement == "" || !s.ValidReplacement() {
+		return "-"
+	}
+	return s.Replacement
+}
+
+// maxLength returns the longest name allowed, or zero for no limit.
+func (s NamingSettings) maxLength() int {
+	if !s.RFC1123 {
+		return s.MaxLength
+	}
+	if s.MaxLength > 0 {
+		return min(s.MaxLength, rfc1123MaxLength)
+	}
+	return rfc1123MaxLength
+}
+
+// ValidGroupBy reports whether contexts can be grouped by what Grouping.By names.
+func (s NamingSettings) ValidGroupBy() bool {
+	return s.Grouping.By == "" || s.Grouping.By == GroupByAlias || s.Grouping.By == GroupByTag
+}
+
+// Group returns the sanitized group of server's contexts, or "" if they are not grouped.
+func (s NamingSettings) Group(server ConfigServer) string {
+	switch {
+	case s.Grouping.By == GroupByAlias:
+		return s.Sanitize(server.Alias)
+	case s.Grouping.By == GroupByTag && len(server.Tags) > 0:
+		return s.Sanitize(server.Tags[0])
+	default:
+		return ""
+	}
+}
+
+// GroupedName returns name prefixed with group and the separator. Names without a group, and names that
+// already carry the prefix, are returned as they are.
+func (s NamingSettings) GroupedName(group, name string) string {
+	if group == "" {
+		return name
+	}
+	prefix := group + cmp.Or(s.Grouping.Separator, defaultGroupSeparator)
+	if strings.HasPrefix(name, prefix) {
+		return name
+	}
+	return prefix + name
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// OutputWriter publishes merged kubeconfigs to a destination other than a local file, such as a Kubernetes
+// Secret. Destinations are URLs whose scheme selects the writer.
+type OutputWriter interface {
+	// Scheme returns the URL scheme of the destinations the writer handles, such as "secret".
+	Scheme() string
+
+	// WriteOutput publishes the merged kubeconfig at path to destination, replacing what it held.
+	WriteOutput(ctx context.Context, destination *url.URL, path string) error
+}
+
+// ParseOutputDestination returns the destination URL of an output that names one, such as
+// "secret://namespace/name", or false for an output that is a local path.
+func ParseOutputDestination(output string) (*url.URL, bool) {
+	if !strings.Contains(output, "://") {
+		return nil, false
+	}
+	destination, err := url.Parse(output)
+	if err != nil || destination.Scheme == "" {
+		return nil, false
+	}
+	return destination, true
+}
+
+// GitAdapter runs git in a repository working tree.
+type GitAdapter interface {
+	// Run runs git with args in dir and returns its standard output.
+	Run(ctx context.Context, dir string, args []string) (string, error)
+}
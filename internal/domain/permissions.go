@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+)
+
+const (
+	// defaultFileMode keeps merged kubeconfigs private to the user.
+	defaultFileMode os.FileMode = 0o600
+	// defaultDirMode keeps the directories created for them private to the user.
+	defaultDirMode os.FileMode = 0o700
+)
+
+// File returns the mode of merged kubeconfigs, or 0600 if FileMode is empty or invalid. The user can
+// always read and write them, whatever the mode says.
+func (s PermissionSettings) File() os.FileMode {
+	return parseModeOr(s.FileMode, defaultFileMode) | defaultFileMode
+}
+
+// Dir returns the mode of directories created for merged kubeconfigs, or 0700 if DirMode is empty or
+// invalid. The user always has full access to them, whatever the mode says.
+func (s PermissionSettings) Dir() os.FileMode {
+	return parseModeOr(s.DirMode, defaultDirMode) | defaultDirMode
+}
+
+// GroupID returns the numeric ID of Group, or -1 if no group is set.
+func (s PermissionSettings) GroupID() (int, error) {
+	if s.Group == "" {
+		return -1, nil
+	}
+	if gid, err := strconv.Atoi(s.Group); err == nil {
+		return gid, nil
+	}
+	group, err := user.LookupGroup(s.Group)
+	if err != nil {
+		return -1, fmt.Errorf("failed to look up group %q: %w", s.Group, err)
+	}
+	gid, err := strconv.Atoi(group.Gid)
+	if err != nil {
+		return -1, fmt.Errorf("group %q has no numeric ID", s.Group)
+	}
+	return gid, nil
+}
+
+// ParseMode parses an octal permission mode such as "0640" or "640".
+func ParseMode(mode string) (os.FileMode, error) {
+	bits, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || bits > uint64(os.ModePerm) {
+		return 0, fmt.Errorf("invalid mode %q (use octal permissions such as 0640)", mode)
+	}
+	return os.FileMode(bits), nil
+}
+
+// parseModeOr returns mode parsed, or fallback if it is empty or invalid.
+func parseModeOr(mode string, fallback os.FileMode) os.FileMode {
+	if mode == "" {
+		return fallback
+	}
+	parsed, err := ParseMode(mode)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
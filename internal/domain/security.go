@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrSecretNotFound is returned by a SecretStore when no secret exists for an account.
+var ErrSecretNotFound = errors.New("secret not found")
+
+// Encryptor encrypts and decrypts data stored at rest.
+type Encryptor interface {
+	// Encrypt seals plaintext into a self-describing ciphertext.
+	Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)
+	// Decrypt opens ciphertext produced by Encrypt. Data that was never encrypted is returned unchanged.
+	Decrypt(ctx context.Context, data []byte) ([]byte, error)
+	// IsEncrypted reports whether data was produced by Encrypt.
+	IsEncrypted(data []byte) bool
+}
+
+// SecretStore persists small secrets in the operating system keychain.
+type SecretStore interface {
+	Get(ctx context.Context, account string) (string, error)
+	Set(ctx context.Context, account, secret string) error
+	Delete(ctx context.Context, account string) error
+}
+
+// TokenCache keeps Rancher auth tokens between runs so that syncs can skip interactive login.
+type TokenCache interface {
+	// Get returns a cached token for server that remains valid long enough to sync, or nil.
+	Get(ctx context.Context, server ConfigServer) (AuthToken, error)
+	// Put caches token for server until it expires.
+	Put(ctx context.Context, server ConfigServer, token AuthToken) error
+	// Delete discards any cached token for server, e.g. after Rancher rejected it.
+	Delete(ctx context.Context, server ConfigServer) error
+}
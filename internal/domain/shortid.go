@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MinShortIDLength is the shortest prefix of a server ID accepted in place of the whole ID.
+const MinShortIDLength = 4
+
+// FindServer returns the server that ref names by URL, by ID or by a prefix of its ID of at least
+// MinShortIDLength characters, like a short git hash. It returns nil if no server matches, and an error if
+// the prefix matches several.
+func FindServer(servers []ConfigServer, ref string) (*ConfigServer, error) {
+	ref = strings.TrimSuffix(ref, "/")
+	if ref == "" {
+		return nil, nil
+	}
+
+	var matches []ConfigServer
+	for _, server := range servers {
+		id := server.ID()
+		if server.URL == ref || id == ref {
+			return &server, nil
+		}
+		if len(ref) >= MinShortIDLength && strings.HasPrefix(id, ref) {
+			matches = append(matches, server)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &matches[0], nil
+	default:
+		urls := make([]string, 0, len(matches))
+		for _, server := range matches {
+			urls = append(urls, server.URL)
+		}
+		return nil, fmt.Errorf("server ID %s is ambiguous: it matches %s", ref, strings.Join(urls, ", "))
+	}
+}
+
+// ShortID returns the shortest prefix of server's ID, of at least MinShortIDLength characters, that no
+// other of servers' IDs starts with.
+func ShortID(servers []ConfigServer, server ConfigServer) string {
+	id := server.ID()
+	for length := MinShortIDLength; length < len(id); length++ {
+		prefix := id[:length]
+		unique := true
+		for _, other := range servers {
+			if otherID := other.ID(); otherID != id && strings.HasPrefix(otherID, prefix) {
+				unique = false
+				break
+			}
+		}
+		if unique {
+			return prefix
+		}
+	}
+	return id
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// StateStore persists small JSON documents describing cowpoke's runtime state.
+type StateStore interface {
+	// Load decodes the named document into v. It reports false if the document does not exist.
+	Load(ctx context.Context, name string, v any) (bool, error)
+	// Save encodes v and atomically replaces the named document.
+	Save(ctx context.Context, name string, v any) error
+}
+
+// ServerHealth tracks consecutive sync failures for a server.
+type ServerHealth struct {
+	ServerID            string    `json:"serverId"`
+	ServerURL           string    `json:"serverUrl"`
+	ConsecutiveFailures int       `json:"consecutiveFailures"`
+	LastError           string    `json:"lastError,omitempty"`
+	LastFailure         time.Time `json:"lastFailure,omitzero"`
+	LastSuccess         time.Time `json:"lastSuccess,omitzero"`
+	NextAttempt         time.Time `json:"nextAttempt,omitzero"`
+}
+
+// BackingOff reports whether the server should not be contacted before NextAttempt.
+func (h ServerHealth) BackingOff(now time.Time) bool {
+	return h.ConsecutiveFailures > 0 && now.Before(h.NextAttempt)
+}
+
+// HealthTracker records server sync outcomes and applies exponential backoff to failing servers.
+type HealthTracker interface {
+	// Get returns the recorded health for a server.
+	Get(ctx context.Context, server ConfigServer) (ServerHealth, error)
+	// RecordSuccess resets the failure count for a server.
+	RecordSuccess(ctx context.Context, server ConfigServer) error
+	// RecordFailure increments the failure count for a server and extends its backoff.
+	RecordFailure(ctx context.Context, server ConfigServer, cause error) error
+}
+
+// IndexedContext records where a context cowpoke wrote into the merged kubeconfig came from.
+type IndexedContext struct {
+	ServerURL   string    `json:"serverUrl"`
+	ServerID    string    `json:"serverId"`
+	ClusterID   string    `json:"clusterId,omitempty"`
+	ClusterName string    `json:"clusterName,omitempty"`
+	SyncedAt    time.Time `json:"syncedAt,omitzero"`
+}
+
+// ContextIndex maps the contexts cowpoke wrote into a merged kubeconfig, by name, to where they came from.
+type ContextIndex struct {
+	Kubeconfig string                    `json:"kubeconfig"`
+	UpdatedAt  time.Time                 `json:"updatedAt"`
+	Contexts   map[string]IndexedContext `json:"contexts"`
+	// Clusters holds the IDs of the clusters each server, by ID, reported when it was last discovered.
+	Clusters map[string][]string `json:"clusters,omitempty"`
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "context"
+
+// Tracer starts spans around units of work so operators can see where sync time goes.
+type Tracer interface {
+	// Start begins a span as a child of any span already in ctx.
+	Start(ctx context.Context, name string) (context.Context, Span)
+	// Shutdown flushes buffered spans to the exporter.
+	Shutdown(ctx context.Context) error
+}
+
+// Span is a single timed operation within a trace.
+type Span interface {
+	SetAttribute(key string, value any)
+	RecordError(err error)
+	End()
+}
+
+// NoopTracer is a Tracer that records nothing; it is used when tracing is disabled.
+type NoopTracer struct{}
+
+// Start returns ctx unchanged and a span that records nothing.
+func (NoopTracer) Start(ctx context.Context, _ string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+// Shutdown does nothing.
+func (NoopTracer) Shutdown(_ context.Context) error {
+	return nil
+}
+
+// noopSpan is a Span that records nothing.
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(_ string, _ any) {}
+func (noopSpan) RecordError(_ error)          {}
+func (noopSpan) End()                         {}
@@ -2,21 +2,88 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 )
 
+// ErrUnauthorized indicates that Rancher rejected an auth token or the credentials used to log in.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// ErrServerUnavailable indicates that a server answered with something other than the Rancher API, such
+// as a maintenance page or a login portal. It is usually temporary, so the request is worth retrying later.
+var ErrServerUnavailable = errors.New("server unavailable")
+
 // AuthToken represents an authenticated session.
 type AuthToken interface {
+	// ID returns the name of the token resource in Rancher, used to revoke it.
+	ID() string
 	Value() string
 	IsValid() bool
 	ExpiresAt() time.Time
 }
 
+// TokenID returns the resource name from a Rancher token value of the form "<name>:<secret>".
+func TokenID(value string) string {
+	name, _, _ := strings.Cut(value, ":")
+	return name
+}
+
 // Cluster represents a Kubernetes cluster in Rancher.
 type Cluster struct {
 	ID   string
 	Name string
+	// DisplayName is the name shown in the Rancher UI, if the server reports one. Imported and Fleet-managed
+	// clusters often have a generated Name such as "c-m-abc123" and a meaningful display name.
+	DisplayName string
+	// Type is the lower-case provider or distribution, such as "rke2", "k3s", "harvester", or "imported".
 	Type string
+	// CreatorID is the ID of the Rancher user who created the cluster, if the server reports one.
+	CreatorID string
+}
+
+// ClusterOwnership describes the clusters a user owns on a Rancher server.
+type ClusterOwnership struct {
+	UserID string
+	// OwnerOf holds the IDs of the clusters the user holds the cluster-owner role on.
+	OwnerOf []string
+}
+
+// Owns reports whether the user created cluster or holds its cluster-owner role.
+func (o ClusterOwnership) Owns(cluster Cluster) bool {
+	return cluster.CreatorID != "" && cluster.CreatorID == o.UserID || slices.Contains(o.OwnerOf, cluster.ID)
+}
+
+// LocalClusterID is the ID of the local cluster, the management cluster a Rancher server runs on.
+const LocalClusterID = "local"
+
+// ClusterRef names a cluster to download without discovering the server's clusters, as listed in a
+// cluster list file. Server is the URL or ID of a configured server.
+type ClusterRef struct {
+	Server    string `yaml:"server"`
+	ClusterID string `yaml:"clusterId"`
+	// Name is used for the fragment and context names. It defaults to the cluster ID.
+	Name string `yaml:"name,omitempty"`
+}
+
+// ServerClusters is a server and the known clusters to download from it.
+type ServerClusters struct {
+	Server   ConfigServer
+	Clusters []Cluster
+}
+
+// SyncOptions narrows what a sync downloads.
+type SyncOptions struct {
+	// ExcludeTypes leaves out clusters whose provider type, such as "harvester", matches one of these
+	// case-insensitively, before their kubeconfigs are downloaded.
+	ExcludeTypes []string
+}
+
+// ExcludesType reports whether cluster is left out of the sync by its type.
+func (o SyncOptions) ExcludesType(cluster Cluster) bool {
+	return slices.ContainsFunc(o.ExcludeTypes, func(t string) bool { return strings.EqualFold(t, cluster.Type) })
 }
 
 // PasswordReader handles secure password input from users.
@@ -25,6 +92,12 @@ type PasswordReader interface {
 	IsInteractive() bool
 }
 
+// Chooser asks the user to pick one of several options.
+type Chooser interface {
+	Choose(ctx context.Context, prompt string, options []string) (string, error)
+	IsInteractive() bool
+}
+
 // ClusterFilter determines whether a cluster should be excluded from operations.
 type ClusterFilter interface {
 	ShouldExclude(clusterName string) bool
@@ -37,4 +110,285 @@ type SyncResult struct {
 	// TotalClustersFound is the total number of clusters found from Rancher APIs.
 	// Filtering is now applied at the kubeconfig merge level, not during sync.
 	TotalClustersFound int
+	// Servers contains the per-server discovery outcome.
+	Servers []ServerSyncResult
+	// Events records the timing of each authentication, discovery, and download step.
+	Events []SyncEvent
+	// Interrupted reports that the sync's context was canceled before every kubeconfig was downloaded.
+	// Downloads not yet started were skipped, and those in flight were given a moment to finish.
+	Interrupted bool
+	// Truncated reports that the sync ran out of its time budget and holds only the kubeconfigs
+	// downloaded within it.
+	Truncated bool
+	// Failures lists the kubeconfig downloads that failed, ordered by server and cluster. The kubeconfigs
+	// of the other clusters are in KubeconfigPaths.
+	Failures []ClusterFailure
+}
+
+// DownloadErr returns a DownloadError listing the failed downloads, or nil if none failed.
+func (r *SyncResult) DownloadErr() error {
+	if len(r.Failures) == 0 {
+		return nil
+	}
+	return &DownloadError{Failures: r.Failures, Total: r.TotalClustersFound}
+}
+
+// ServerSyncResult contains the discovery outcome for a single server.
+type ServerSyncResult struct {
+	Server   ConfigServer
+	Clusters []Cluster
+	// SkippedLocal reports whether the server's local cluster was left out of Clusters.
+	SkippedLocal bool
+	// ExcludedByType holds the clusters left out of Clusters by SyncOptions.ExcludeTypes.
+	ExcludedByType []Cluster
+	// Version is the detected Rancher release, if the server reported one.
+	Version string
+	Error   error
+}
+
+// ClusterFailure records why a cluster's kubeconfig could not be downloaded.
+type ClusterFailure struct {
+	ServerURL string          `json:"serverUrl"`
+	Cluster   string          `json:"cluster"`
+	Error     string          `json:"error"`
+	Category  FailureCategory `json:"category"`
+	// Quarantine is where the kubeconfig was kept, if it was downloaded but could not be saved.
+	Quarantine string `json:"quarantine,omitempty"`
+}
+
+// DownloadError reports the kubeconfig downloads of a sync that failed.
+type DownloadError struct {
+	Failures []ClusterFailure
+	// Total is the number of kubeconfigs the sync tried to download.
+	Total int
+}
+
+func (e *DownloadError) Error() string {
+	return fmt.Sprintf("failed to download %d out of %d kubeconfigs", len(e.Failures), e.Total)
+}
+
+// InterruptedError reports a sync that was interrupted, by a signal or its timeout, before it wrote the
+// merged kubeconfig. The kubeconfigs downloaded before the interruption are kept as cached fragments.
+type InterruptedError struct {
+	// Downloaded is the number of kubeconfigs downloaded before the interruption.
+	Downloaded int
+	// Total is the number of clusters discovered, zero if discovery was interrupted.
+	Total int
+	Err   error
+}
+
+func (e *InterruptedError) Error() string {
+	stopped := "interrupted"
+	if errors.Is(e.Err, context.DeadlineExceeded) {
+		stopped = "timed out"
+	}
+	if e.Total == 0 {
+		return fmt.Sprintf("sync %s while discovering clusters; the merged kubeconfig was not written", stopped)
+	}
+	return fmt.Sprintf("sync %s after downloading %d of %d kubeconfigs; the merged kubeconfig was not written",
+		stopped, e.Downloaded, e.Total)
+}
+
+func (e *InterruptedError) Unwrap() error {
+	return e.Err
+}
+
+// QuarantineError reports a downloaded kubeconfig that could not be saved and was moved to the quarantine
+// directory instead, next to a file explaining why.
+type QuarantineError struct {
+	// Path is the quarantined kubeconfig.
+	Path string
+	Err  error
+}
+
+func (e *QuarantineError) Error() string {
+	return fmt.Sprintf("%v (quarantined to %s)", e.Err, e.Path)
+}
+
+func (e *QuarantineError) Unwrap() error {
+	return e.Err
+}
+
+// FragmentFileName returns the file name used for a cluster's cached kubeconfig fragment.
+func FragmentFileName(clusterName, serverID string) string {
+	return fmt.Sprintf("%s-%s.yaml", clusterName, serverID)
+}
+
+// CacheEntry describes a cached kubeconfig fragment and where it came from.
+type CacheEntry struct {
+	File         string    `json:"file"`
+	ServerURL    string    `json:"serverUrl,omitempty"`
+	ServerID     string    `json:"serverId"`
+	ClusterID    string    `json:"clusterId,omitempty"`
+	ClusterName  string    `json:"clusterName,omitempty"`
+	ClusterType  string    `json:"clusterType,omitempty"`
+	DownloadedAt time.Time `json:"downloadedAt"`
+	// Size is the fragment size in bytes, populated when listing.
+	Size int64 `json:"-"`
+}
+
+// CleanupPolicy describes which cached kubeconfig fragments should be garbage collected.
+type CleanupPolicy struct {
+	// MaxAge removes fragments not written within this duration. Zero disables age-based removal.
+	MaxAge time.Duration
+	// ConfiguredServerIDs removes fragments whose server is no longer configured. Nil disables the check.
+	ConfiguredServerIDs []string
+	// SyncedServerIDs lists servers discovered successfully in this run; their fragments
+	// not named in LiveFragments belong to clusters that no longer exist.
+	SyncedServerIDs []string
+	// LiveFragments are fragment file names for every cluster discovered in this run.
+	LiveFragments []string
+	// All removes every fragment regardless of the other fields.
+	All bool
+}
+
+// CleanupResult reports what a cleanup pass removed.
+type CleanupResult struct {
+	Removed []string
+	Kept    int
+}
+
+// SyncPhase identifies a step of a sync that is timed.
+type SyncPhase string
+
+// Timed sync phases.
+const (
+	PhaseSync         SyncPhase = "sync"
+	PhaseAuthenticate SyncPhase = "authenticate"
+	PhaseListClusters SyncPhase = "list_clusters"
+	PhaseDownload     SyncPhase = "download"
+	PhaseMerge        SyncPhase = "merge"
+)
+
+// SyncEvent records when a phase of a sync started and finished.
+type SyncEvent struct {
+	Phase     SyncPhase
+	ServerURL string
+	Cluster   string
+	Start     time.Time
+	End       time.Time
+	// Bytes is the payload size for download events.
+	Bytes int
+	Error error
+}
+
+// Duration returns how long the event took.
+func (e SyncEvent) Duration() time.Duration {
+	return e.End.Sub(e.Start)
+}
+
+// SyncTiming is a timing breakdown of a sync computed from its events.
+type SyncTiming struct {
+	Total     time.Duration  `json:"total"`
+	Auth      []ServerTiming `json:"auth"`
+	Discovery time.Duration  `json:"discovery"`
+	Download  DownloadStats  `json:"download"`
+	Merge     time.Duration  `json:"merge"`
+}
+
+// ServerTiming is how long a single server took to authenticate.
+type ServerTiming struct {
+	ServerURL string        `json:"server"`
+	Duration  time.Duration `json:"duration"`
+	Failed    bool          `json:"failed,omitempty"`
+}
+
+// DownloadStats summarises kubeconfig download latency and throughput.
+type DownloadStats struct {
+	Count          int           `json:"count"`
+	Failed         int           `json:"failed"`
+	Bytes          int64         `json:"bytes"`
+	Wall           time.Duration `json:"wall"`
+	P50            time.Duration `json:"p50"`
+	P90            time.Duration `json:"p90"`
+	P99            time.Duration `json:"p99"`
+	Max            time.Duration `json:"max"`
+	BytesPerSecond float64       `json:"bytesPerSecond"`
+}
+
+// ContextExtensionName is the kubeconfig extension cowpoke writes into every context it generates.
+const ContextExtensionName = "cowpoke"
+
+// ContextOwner records where a generated kubeconfig context came from. It is stored in the context's
+// extensions so tooling can identify cowpoke-owned contexts without relying on name suffixes.
+type ContextOwner struct {
+	ServerURL string `json:"serverUrl"`
+	ServerID  string `json:"serverId"`
+	ClusterID string `json:"clusterId,omitempty"`
+	// ClusterName, if set, replaces the cluster name Rancher used in the kubeconfig's resource names.
+	ClusterName string `json:"clusterName,omitempty"`
+	// SyncedAt is when the context was last downloaded with changes. Downloads identical to the saved
+	// kubeconfig leave it, so that unchanged kubeconfigs are not rewritten.
+	SyncedAt time.Time `json:"syncedAt"`
+	// ExpiresAt is when the context's token expires, zero if it never expires or its expiry is unknown.
+	ExpiresAt time.Time `json:"expiresAt,omitzero"`
+	Version   string    `json:"version,omitempty"`
+}
+
+// ClusterCredential is the bearer token a kubeconfig authenticates to its cluster with.
+type ClusterCredential struct {
+	Token string
+	// ExpiresAt is when the token expires, zero if it never expires or its expiry is unknown.
+	ExpiresAt time.Time
+}
+
+// ManagedContext is a context cowpoke generated in a kubeconfig.
+type ManagedContext struct {
+	Name string
+	// ClusterName is the Rancher cluster's name, the context name without its server ID suffix.
+	ClusterName string
+	Owner       ContextOwner
+	// Endpoint is the API server URL of the context's cluster.
+	Endpoint string
+	// Current reports whether the context is the kubeconfig's current context.
+	Current bool
+}
+
+// ContextSnapshot is the state of a context in a kubeconfig, compared before and after a sync to tell
+// what the sync changed.
+type ContextSnapshot struct {
+	Name string
+	// Endpoint is the API server URL of the context's cluster.
+	Endpoint string
+	// Credential fingerprints the credentials of the context's user, changing when its token is refreshed.
+	Credential string
+}
+
+// SupersededContexts finds the contexts left behind by clusters renamed in Rancher. Contexts are matched to
+// their cluster by server and cluster ID; those written before the newest context of the same cluster are
+// superseded. It maps each superseded context to the newest context of its cluster that sorts first by name.
+func SupersededContexts(contexts []ManagedContext) map[string]string {
+	type clusterKey struct{ serverID, clusterID string }
+	newest := make(map[clusterKey]ManagedContext)
+	for _, kubeContext := range contexts {
+		if kubeContext.Owner.ClusterID == "" {
+			continue
+		}
+		key := clusterKey{kubeContext.Owner.ServerID, kubeContext.Owner.ClusterID}
+		current, ok := newest[key]
+		if !ok || kubeContext.Owner.SyncedAt.After(current.Owner.SyncedAt) ||
+			kubeContext.Owner.SyncedAt.Equal(current.Owner.SyncedAt) && kubeContext.Name < current.Name {
+			newest[key] = kubeContext
+		}
+	}
+
+	superseded := make(map[string]string)
+	for _, kubeContext := range contexts {
+		if kubeContext.Owner.ClusterID == "" {
+			continue
+		}
+		replacement := newest[clusterKey{kubeContext.Owner.ServerID, kubeContext.Owner.ClusterID}]
+		if kubeContext.Owner.SyncedAt.Before(replacement.Owner.SyncedAt) {
+			superseded[kubeContext.Name] = replacement.Name
+		}
+	}
+	return superseded
+}
+
+// AuthProvider is an authentication provider enabled on a Rancher server.
+type AuthProvider struct {
+	// ID is the provider's auth type as used to log in, e.g. "activedirectory".
+	ID string
+	// Type is Rancher's resource type for the provider, e.g. "activeDirectoryProvider".
+	Type string
 }
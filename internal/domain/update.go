@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// Release describes a published cowpoke release.
+type Release struct {
+	// Version is the release version without its "v" prefix, e.g. "1.4.0".
+	Version string
+	// URL is the release's web page.
+	URL    string
+	Assets []ReleaseAsset
+}
+
+// ReleaseAsset is a file attached to a release.
+type ReleaseAsset struct {
+	Name string
+	URL  string
+}
+
+// Updater finds published cowpoke releases and installs them over the running binary.
+type Updater interface {
+	// LatestRelease returns the most recent published release.
+	LatestRelease(ctx context.Context) (*Release, error)
+	// Install downloads the release's archive for this platform, verifies its checksum and replaces
+	// the binary at executable with the one it contains.
+	Install(ctx context.Context, release *Release, executable string) error
+	// Manifests renders the package manager manifests installing the archives of the release of version,
+	// from the release's checksums file.
+	Manifests(version string, checksums []byte) (*ReleaseManifests, error)
+}
+
+// ReleaseManifests are the package manager manifests of a release. Homebrew installs the cask GoReleaser
+// publishes instead.
+type ReleaseManifests struct {
+	// Scoop is the Scoop manifest, cowpoke.json in the bucket.
+	Scoop []byte
+}
+
+// IsReleaseVersion reports whether version is a release version such as "1.4.0" or "v1.4.0-rc.1", as
+// opposed to a development build.
+func IsReleaseVersion(version string) bool {
+	_, _, ok := parseVersion(version)
+	return ok
+}
+
+// IsNewerVersion reports whether candidate is a later release than current. A pre-release precedes the
+// release it leads up to. Development builds are never considered outdated.
+func IsNewerVersion(current, candidate string) bool {
+	currentParts, currentPre, currentOK := parseVersion(current)
+	candidateParts, candidatePre, candidateOK := parseVersion(candidate)
+	if !currentOK || !candidateOK {
+		return false
+	}
+	for i := range currentParts {
+		if currentParts[i] != candidateParts[i] {
+			return candidateParts[i] > currentParts[i]
+		}
+	}
+	switch {
+	case currentPre == "":
+		return false
+	case candidatePre == "":
+		return true
+	default:
+		return candidatePre > currentPre
+	}
+}
+
+// parseVersion splits a "[v]MAJOR.MINOR.PATCH[-PRERELEASE]" version into its numbers and pre-release.
+func parseVersion(version string) ([3]int, string, bool) {
+	var parts [3]int
+	core, pre, _ := strings.Cut(strings.TrimPrefix(version, "v"), "-")
+	fields := strings.Split(core, ".")
+	if len(fields) != len(parts) {
+		return parts, "", false
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return parts, "", false
+		}
+		parts[i] = n
+	}
+	return parts, pre, true
+}
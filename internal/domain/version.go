@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrIncompatibleVersion indicates a Rancher server runs a release cowpoke cannot talk to.
+var ErrIncompatibleVersion = errors.New("incompatible Rancher version")
+
+const (
+	// minSupportedMajor is the first Rancher major version with the v3 API.
+	minSupportedMajor = 2
+	// minTestedMinor is the oldest Rancher 2.x minor release cowpoke is tested against.
+	minTestedMinor = 6
+)
+
+// ServerVersion describes the Rancher release running on a server.
+type ServerVersion struct {
+	Version   string
+	GitCommit string
+}
+
+// Check reports whether cowpoke can sync from this release. It returns ErrIncompatibleVersion for
+// releases without the v3 API, and a non-empty warning for releases that are old enough to be untested.
+// Development builds and unrecognised version strings are assumed compatible.
+func (v ServerVersion) Check() (string, error) {
+	major, minor, ok := v.parse()
+	if !ok {
+		return "", nil
+	}
+	if major < minSupportedMajor {
+		return "", fmt.Errorf("%w: Rancher %s does not provide the v3 API", ErrIncompatibleVersion, v.Version)
+	}
+	if major == minSupportedMajor && minor < minTestedMinor {
+		return fmt.Sprintf("Rancher %s is older than v%d.%d and untested; sync may fail",
+			v.Version, minSupportedMajor, minTestedMinor), nil
+	}
+	return "", nil
+}
+
+// parse extracts the major and minor numbers from versions such as "v2.8.3" or "2.9.0-rc1".
+func (v ServerVersion) parse() (int, int, bool) {
+	majorPart, rest, _ := strings.Cut(strings.TrimPrefix(v.Version, "v"), ".")
+	minorPart, _, _ := strings.Cut(rest, ".")
+	major, err := strconv.Atoi(majorPart)
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(minorPart)
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+)
+
+// correlationIDSize is the number of random bytes in a correlation ID.
+const correlationIDSize = 8
+
+// correlationKey is the context key for the correlation ID.
+type correlationKey struct{}
+
+// NewCorrelationID returns a random ID tying together the logs and API requests of one operation.
+func NewCorrelationID() string {
+	b := make([]byte, correlationIDSize)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// WithCorrelationID returns a context carrying id.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationID returns the correlation ID carried by ctx, or "" if there is none.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// correlationHandler adds the context's correlation ID to every record.
+type correlationHandler struct {
+	slog.Handler
+}
+
+// Handle adds the correlation ID, if any, before passing the record on.
+func (h correlationHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := CorrelationID(ctx); id != "" {
+		record.AddAttrs(slog.String("correlation_id", id))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs returns a correlationHandler wrapping the handler with attrs added.
+func (h correlationHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return correlationHandler{h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup returns a correlationHandler wrapping the handler with the group opened.
+func (h correlationHandler) WithGroup(name string) slog.Handler {
+	return correlationHandler{h.Handler.WithGroup(name)}
+}
@@ -2,9 +2,18 @@
 package logging
 
 import (
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
+)
+
+const (
+	// debugDirPerm restricts the debug log directory to the current user.
+	debugDirPerm = 0o700
+	// debugFilePerm restricts debug logs to the current user since they describe private endpoints.
+	debugFilePerm = 0o600
 )
 
 // NewLogger creates a standard text logger for CLI usage.
@@ -12,7 +21,7 @@ func NewLogger(level slog.Level) *slog.Logger {
 	opts := &slog.HandlerOptions{
 		Level: level,
 	}
-	return slog.New(slog.NewTextHandler(os.Stderr, opts))
+	return slog.New(correlationHandler{slog.NewTextHandler(os.Stderr, opts)})
 }
 
 // NewTestLogger creates a silent logger for tests.
@@ -22,3 +31,19 @@ func NewTestLogger() *slog.Logger {
 	}
 	return slog.New(slog.NewTextHandler(io.Discard, opts))
 }
+
+// NewFileLogger creates a JSON logger writing every level to a new file at path. The caller must close
+// the returned file when done.
+func NewFileLogger(path string) (*slog.Logger, io.Closer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), debugDirPerm); err != nil {
+		return nil, nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, debugFilePerm)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create log file: %w", err)
+	}
+	opts := &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	}
+	return slog.New(correlationHandler{slog.NewJSONHandler(file, opts)}), file, nil
+}
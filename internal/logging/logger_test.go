@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -126,3 +128,45 @@ func TestNewLogger_vs_NewTestLogger(t *testing.T) {
 	// but we verified its configuration in other tests)
 	require.NotNil(t, testLogger)
 }
+
+func TestNewFileLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "debug", "http.log")
+
+	logger, file, err := NewFileLogger(path)
+	require.NoError(t, err)
+
+	logger.DebugContext(context.Background(), "debug message", "key", "value")
+	require.NoError(t, file.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"msg":"debug message"`)
+	assert.Contains(t, string(data), `"key":"value"`)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestCorrelationHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(correlationHandler{slog.NewTextHandler(&buf, nil)}).With("component", "sync")
+
+	ctx := WithCorrelationID(context.Background(), "abc123")
+	logger.InfoContext(ctx, "with id")
+	logger.InfoContext(context.Background(), "without id")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	assert.Contains(t, string(lines[0]), "component=sync")
+	assert.Contains(t, string(lines[0]), "correlation_id=abc123")
+	assert.NotContains(t, string(lines[1]), "correlation_id")
+}
+
+func TestNewCorrelationID(t *testing.T) {
+	id := NewCorrelationID()
+	assert.Len(t, id, 16)
+	assert.NotEqual(t, id, NewCorrelationID())
+	assert.Equal(t, id, CorrelationID(WithCorrelationID(context.Background(), id)))
+	assert.Empty(t, CorrelationID(context.Background()))
+}
@@ -0,0 +1,73 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Inode flags from linux/fs.h, which golang.org/x/sys/unix does not define.
+const (
+	immutableFlag  = 0x10 // FS_IMMUTABLE_FL
+	appendOnlyFlag = 0x20 // FS_APPEND_FL
+)
+
+// permissionHints explains what kept the permissions of path from being changed: another owner, an
+// immutable or append-only flag, extended ACL entries or SELinux, each with the command that lifts it.
+func permissionHints(path string, err error) []string {
+	info, statErr := os.Lstat(path)
+	if statErr != nil {
+		return nil
+	}
+
+	var hints []string
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok && int(stat.Uid) != os.Geteuid() {
+		hints = append(hints, fmt.Sprintf("%s is owned by user ID %d, run 'sudo chown %d %s'",
+			path, stat.Uid, os.Geteuid(), path))
+	}
+	if flags, flagsErr := inodeFlags(path); flagsErr == nil {
+		if flags&immutableFlag != 0 {
+			hints = append(hints, fmt.Sprintf("%s is immutable, run 'sudo chattr -i %s'", path, path))
+		}
+		if flags&appendOnlyFlag != 0 {
+			hints = append(hints, fmt.Sprintf("%s is append-only, run 'sudo chattr -a %s'", path, path))
+		}
+	}
+	if hasAttribute(path, "system.posix_acl_access") {
+		hints = append(hints, fmt.Sprintf(
+			"%s has extended ACL entries, review them with 'getfacl %s' and remove them with 'setfacl -b %s'",
+			path, path, path))
+	}
+	if errors.Is(err, syscall.EACCES) && selinuxEnforcing() && hasAttribute(path, "security.selinux") {
+		hints = append(hints, fmt.Sprintf(
+			"SELinux may deny access to %s, check its label with 'ls -Z %s' and restore it with 'restorecon -v %s'",
+			path, path, path))
+	}
+	return hints
+}
+
+// inodeFlags returns the attributes of path that chattr sets.
+func inodeFlags(path string) (uint32, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	return unix.IoctlGetUint32(int(file.Fd()), unix.FS_IOC_GETFLAGS)
+}
+
+// hasAttribute reports whether path has the extended attribute name.
+func hasAttribute(path, name string) bool {
+	size, err := unix.Lgetxattr(path, name, nil)
+	return err == nil && size > 0
+}
+
+// selinuxEnforcing reports whether SELinux is enforcing its policy.
+func selinuxEnforcing() bool {
+	data, err := os.ReadFile("/sys/fs/selinux/enforce")
+	return err == nil && strings.TrimSpace(string(data)) == "1"
+}
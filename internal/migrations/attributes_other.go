@@ -0,0 +1,9 @@
+//go:build !linux
+
+package migrations
+
+// permissionHints explains what kept the permissions of path from being changed. Ownership, file flags
+// and ACLs are only inspected on Linux.
+func permissionHints(_ string, _ error) []string {
+	return nil
+}
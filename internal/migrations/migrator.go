@@ -2,9 +2,12 @@ package migrations
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 
@@ -14,7 +17,12 @@ import (
 // ConfigMigrator handles configuration migrations between versions.
 type ConfigMigrator interface {
 	Migrate(ctx context.Context, data []byte, currentVersion string) ([]domain.ConfigServer, bool, error)
-	FixPermissionsPostMigration(ctx context.Context, configPath string, fs domain.FileSystemAdapter) error
+	FixPermissionsPostMigration(
+		ctx context.Context,
+		configPath string,
+		fs domain.FileSystemAdapter,
+		permissions domain.PermissionSettings,
+	) error
 }
 
 // Migrator implements configuration migration logic.
@@ -53,6 +61,9 @@ func (m *Migrator) Migrate(
 	switch version {
 	case "", "1.0":
 		return m.migrateFromV1(ctx, data)
+	case "2.0":
+		// Version 2.0 is a subset of later schemas and is loaded directly; only its version changes.
+		return nil, false, nil
 	default:
 		return nil, false, fmt.Errorf("unsupported configuration version: %s", version)
 	}
@@ -60,6 +71,12 @@ func (m *Migrator) Migrate(
 
 // detectVersion attempts to detect the configuration version.
 func (m *Migrator) detectVersion(data []byte) (string, error) {
+	return DetectVersion(data)
+}
+
+// DetectVersion returns the schema version of raw configuration data. Configs without
+// a version field are reported as "1.0".
+func DetectVersion(data []byte) (string, error) {
 	var versionCheck struct {
 		Version string `yaml:"version"`
 	}
@@ -89,11 +106,13 @@ func (m *Migrator) migrateFromV1(ctx context.Context, data []byte) ([]domain.Con
 
 // FixPermissionsPostMigration fixes file and directory permissions after migration from v1.x.
 // This ensures that users upgrading from v1.0.1 (which may not have had secure permissions)
-// get the proper security settings for v2.0.
+// get the proper security settings for v2.0. The configuration stays private to the user, while
+// the kubeconfig directory gets the directory mode of the permission policy.
 func (m *Migrator) FixPermissionsPostMigration(
 	ctx context.Context,
 	configPath string,
 	fs domain.FileSystemAdapter,
+	permissions domain.PermissionSettings,
 ) error {
 	const (
 		dirPermissions  = 0o700 // Owner-only access for security
@@ -102,6 +121,7 @@ func (m *Migrator) FixPermissionsPostMigration(
 
 	// Fix config file permissions.
 	if err := fs.Chmod(configPath, filePermissions); err != nil {
+		err = permissionError(configPath, err)
 		m.logger.WarnContext(ctx, "Failed to fix config file permissions",
 			"path", configPath, "error", err)
 		return fmt.Errorf("failed to fix config file permissions: %w", err)
@@ -110,6 +130,7 @@ func (m *Migrator) FixPermissionsPostMigration(
 	// Fix config directory permissions.
 	configDir := filepath.Dir(configPath)
 	if err := fs.Chmod(configDir, dirPermissions); err != nil {
+		err = permissionError(configDir, err)
 		m.logger.WarnContext(ctx, "Failed to fix config directory permissions",
 			"path", configDir, "error", err)
 		return fmt.Errorf("failed to fix config directory permissions: %w", err)
@@ -124,9 +145,9 @@ func (m *Migrator) FixPermissionsPostMigration(
 	for _, kubeconfigDir := range kubeconfigDirs {
 		// Only fix permissions if directory exists (don't create it).
 		if _, err := fs.Stat(kubeconfigDir); err == nil {
-			if chmodErr := fs.Chmod(kubeconfigDir, dirPermissions); chmodErr != nil {
+			if chmodErr := fs.Chmod(kubeconfigDir, permissions.Dir()); chmodErr != nil {
 				m.logger.WarnContext(ctx, "Failed to fix kubeconfig directory permissions",
-					"path", kubeconfigDir, "error", chmodErr)
+					"path", kubeconfigDir, "error", permissionError(kubeconfigDir, chmodErr))
 				// Don't fail the migration for kubeconfig directory permission issues.
 			} else {
 				m.logger.InfoContext(ctx, "Fixed kubeconfig directory permissions",
@@ -139,3 +160,16 @@ func (m *Migrator) FixPermissionsPostMigration(
 		"config_file", configPath, "config_dir", configDir)
 	return nil
 }
+
+// permissionError adds to a denied permission change of path what denied it and how to lift that, when
+// that can be told, rather than leaving a bare "operation not permitted".
+func permissionError(path string, err error) error {
+	if !errors.Is(err, os.ErrPermission) {
+		return err
+	}
+	hints := permissionHints(path, err)
+	if len(hints) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w (%s)", err, strings.Join(hints, "; "))
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"cowpoke/internal/domain"
@@ -111,6 +112,24 @@ servers:
 	assert.Nil(t, servers) // No servers returned when no migration needed
 }
 
+func TestMigrator_Migrate_FromV2LoadsDirectly(t *testing.T) {
+	// Test that v2 configs need no structural migration to the current version
+	migrator := NewMigrator(testutil.Logger())
+	ctx := context.Background()
+
+	configData := `version: "2.0"
+servers:
+  - url: "https://rancher.example.com"`
+
+	// Act
+	servers, wasMigrated, err := migrator.Migrate(ctx, []byte(configData), "3.0")
+
+	// Assert
+	require.NoError(t, err)
+	assert.False(t, wasMigrated)
+	assert.Nil(t, servers)
+}
+
 func TestMigrator_Migrate_FromV1_Success(t *testing.T) {
 	// Test successful migration from v1 to v2
 	migrator := NewMigrator(testutil.Logger())
@@ -251,7 +270,7 @@ func TestMigrator_FixPermissionsPostMigration_Success(t *testing.T) {
 	mockFS.On("Chmod", kubeconfigDir, os.FileMode(0o700)).Return(nil)
 
 	// Act
-	err := migrator.FixPermissionsPostMigration(ctx, configPath, mockFS)
+	err := migrator.FixPermissionsPostMigration(ctx, configPath, mockFS, domain.PermissionSettings{})
 
 	// Assert
 	require.NoError(t, err)
@@ -271,7 +290,7 @@ func TestMigrator_FixPermissionsPostMigration_ConfigFileError(t *testing.T) {
 	mockFS.On("Chmod", configPath, os.FileMode(0o600)).Return(expectedErr)
 
 	// Act
-	err := migrator.FixPermissionsPostMigration(ctx, configPath, mockFS)
+	err := migrator.FixPermissionsPostMigration(ctx, configPath, mockFS, domain.PermissionSettings{})
 
 	// Assert
 	require.Error(t, err)
@@ -295,7 +314,7 @@ func TestMigrator_FixPermissionsPostMigration_ConfigDirError(t *testing.T) {
 	mockFS.On("Chmod", configDir, os.FileMode(0o700)).Return(expectedErr)
 
 	// Act
-	err := migrator.FixPermissionsPostMigration(ctx, configPath, mockFS)
+	err := migrator.FixPermissionsPostMigration(ctx, configPath, mockFS, domain.PermissionSettings{})
 
 	// Assert
 	require.Error(t, err)
@@ -321,7 +340,7 @@ func TestMigrator_FixPermissionsPostMigration_KubeconfigDirNotExists(t *testing.
 	mockFS.On("Stat", kubeconfigDir).Return(nil, os.ErrNotExist) // Directory doesn't exist
 
 	// Act
-	err := migrator.FixPermissionsPostMigration(ctx, configPath, mockFS)
+	err := migrator.FixPermissionsPostMigration(ctx, configPath, mockFS, domain.PermissionSettings{})
 
 	// Assert
 	require.NoError(t, err) // Should not error when kubeconfig dir doesn't exist
@@ -347,13 +366,38 @@ func TestMigrator_FixPermissionsPostMigration_KubeconfigDirError(t *testing.T) {
 	mockFS.On("Chmod", kubeconfigDir, os.FileMode(0o700)).Return(kubeconfigErr)
 
 	// Act
-	err := migrator.FixPermissionsPostMigration(ctx, configPath, mockFS)
+	err := migrator.FixPermissionsPostMigration(ctx, configPath, mockFS, domain.PermissionSettings{})
 
 	// Assert
 	require.NoError(t, err) // Should not fail for kubeconfig directory errors
 	mockFS.AssertExpectations(t)
 }
 
+func TestMigrator_FixPermissionsPostMigration_KubeconfigDirFollowsPolicy(t *testing.T) {
+	// Test that the kubeconfig directory gets the policy's directory mode while the config stays private
+	migrator := NewMigrator(testutil.Logger())
+	ctx := context.Background()
+	mockFS := mocks.NewMockFileSystemAdapter(t)
+
+	tmpDir := t.TempDir()
+	configDir := tmpDir + "/.cowpoke"
+	configPath := configDir + "/config.yaml"
+	kubeconfigDir := filepath.Join(filepath.Dir(configDir), "..", ".kube")
+
+	mockFS.On("Chmod", configPath, os.FileMode(0o600)).Return(nil)
+	mockFS.On("Chmod", configDir, os.FileMode(0o700)).Return(nil)
+	mockFS.On("Stat", kubeconfigDir).Return(nil, nil) // Directory exists
+	mockFS.On("Chmod", kubeconfigDir, os.FileMode(0o750)).Return(nil)
+
+	// Act
+	err := migrator.FixPermissionsPostMigration(ctx, configPath, mockFS,
+		domain.PermissionSettings{FileMode: "0640", DirMode: "0750"})
+
+	// Assert
+	require.NoError(t, err)
+	mockFS.AssertExpectations(t)
+}
+
 func TestMigrator_Integration_FullV1Migration(t *testing.T) {
 	// Full integration test of v1 to v2 migration
 	migrator := NewMigrator(testutil.Logger())
@@ -476,3 +520,58 @@ servers:
 		})
 	}
 }
+
+func TestPermissionError(t *testing.T) {
+	tmpDir := t.TempDir()
+	otherErr := errors.New("disk full")
+
+	tests := []struct {
+		name     string
+		path     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "other errors are left alone",
+			path:     tmpDir,
+			err:      otherErr,
+			expected: "disk full",
+		},
+		{
+			name:     "nothing to explain on an ordinary path",
+			path:     tmpDir,
+			err:      os.ErrPermission,
+			expected: os.ErrPermission.Error(),
+		},
+		{
+			name:     "missing paths cannot be inspected",
+			path:     filepath.Join(tmpDir, "missing"),
+			err:      os.ErrPermission,
+			expected: os.ErrPermission.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := permissionError(tt.path, tt.err)
+
+			// Assert
+			require.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, err.Error())
+		})
+	}
+}
+
+func TestPermissionError_ExplainsForeignOwner(t *testing.T) {
+	if runtime.GOOS != "linux" || os.Geteuid() == 0 {
+		t.Skip("needs a path owned by another user")
+	}
+
+	// Act
+	err := permissionError("/", os.ErrPermission)
+
+	// Assert
+	require.ErrorIs(t, err, os.ErrPermission)
+	assert.Contains(t, err.Error(), "/ is owned by user ID 0, run 'sudo chown")
+}
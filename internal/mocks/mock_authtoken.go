@@ -81,6 +81,50 @@ func (_c *MockAuthToken_ExpiresAt_Call) RunAndReturn(run func() time.Time) *Mock
 	return _c
 }
 
+// ID provides a mock function for the type MockAuthToken
+func (_mock *MockAuthToken) ID() string {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ID")
+	}
+
+	var r0 string
+	if returnFunc, ok := ret.Get(0).(func() string); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	return r0
+}
+
+// MockAuthToken_ID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ID'
+type MockAuthToken_ID_Call struct {
+	*mock.Call
+}
+
+// ID is a helper method to define mock.On call
+func (_e *MockAuthToken_Expecter) ID() *MockAuthToken_ID_Call {
+	return &MockAuthToken_ID_Call{Call: _e.mock.On("ID")}
+}
+
+func (_c *MockAuthToken_ID_Call) Run(run func()) *MockAuthToken_ID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockAuthToken_ID_Call) Return(s string) *MockAuthToken_ID_Call {
+	_c.Call.Return(s)
+	return _c
+}
+
+func (_c *MockAuthToken_ID_Call) RunAndReturn(run func() string) *MockAuthToken_ID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // IsValid provides a mock function for the type MockAuthToken
 func (_mock *MockAuthToken) IsValid() bool {
 	ret := _mock.Called()
@@ -39,16 +39,16 @@ func (_m *MockConfigMigrator) EXPECT() *MockConfigMigrator_Expecter {
 }
 
 // FixPermissionsPostMigration provides a mock function for the type MockConfigMigrator
-func (_mock *MockConfigMigrator) FixPermissionsPostMigration(ctx context.Context, configPath string, fs domain.FileSystemAdapter) error {
-	ret := _mock.Called(ctx, configPath, fs)
+func (_mock *MockConfigMigrator) FixPermissionsPostMigration(ctx context.Context, configPath string, fs domain.FileSystemAdapter, permissions domain.PermissionSettings) error {
+	ret := _mock.Called(ctx, configPath, fs, permissions)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FixPermissionsPostMigration")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, domain.FileSystemAdapter) error); ok {
-		r0 = returnFunc(ctx, configPath, fs)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, domain.FileSystemAdapter, domain.PermissionSettings) error); ok {
+		r0 = returnFunc(ctx, configPath, fs, permissions)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -64,11 +64,12 @@ type MockConfigMigrator_FixPermissionsPostMigration_Call struct {
 //   - ctx context.Context
 //   - configPath string
 //   - fs domain.FileSystemAdapter
-func (_e *MockConfigMigrator_Expecter) FixPermissionsPostMigration(ctx interface{}, configPath interface{}, fs interface{}) *MockConfigMigrator_FixPermissionsPostMigration_Call {
-	return &MockConfigMigrator_FixPermissionsPostMigration_Call{Call: _e.mock.On("FixPermissionsPostMigration", ctx, configPath, fs)}
+//   - permissions domain.PermissionSettings
+func (_e *MockConfigMigrator_Expecter) FixPermissionsPostMigration(ctx interface{}, configPath interface{}, fs interface{}, permissions interface{}) *MockConfigMigrator_FixPermissionsPostMigration_Call {
+	return &MockConfigMigrator_FixPermissionsPostMigration_Call{Call: _e.mock.On("FixPermissionsPostMigration", ctx, configPath, fs, permissions)}
 }
 
-func (_c *MockConfigMigrator_FixPermissionsPostMigration_Call) Run(run func(ctx context.Context, configPath string, fs domain.FileSystemAdapter)) *MockConfigMigrator_FixPermissionsPostMigration_Call {
+func (_c *MockConfigMigrator_FixPermissionsPostMigration_Call) Run(run func(ctx context.Context, configPath string, fs domain.FileSystemAdapter, permissions domain.PermissionSettings)) *MockConfigMigrator_FixPermissionsPostMigration_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -82,10 +83,15 @@ func (_c *MockConfigMigrator_FixPermissionsPostMigration_Call) Run(run func(ctx
 		if args[2] != nil {
 			arg2 = args[2].(domain.FileSystemAdapter)
 		}
+		var arg3 domain.PermissionSettings
+		if args[3] != nil {
+			arg3 = args[3].(domain.PermissionSettings)
+		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
 		)
 	})
 	return _c
@@ -96,7 +102,7 @@ func (_c *MockConfigMigrator_FixPermissionsPostMigration_Call) Return(err error)
 	return _c
 }
 
-func (_c *MockConfigMigrator_FixPermissionsPostMigration_Call) RunAndReturn(run func(ctx context.Context, configPath string, fs domain.FileSystemAdapter) error) *MockConfigMigrator_FixPermissionsPostMigration_Call {
+func (_c *MockConfigMigrator_FixPermissionsPostMigration_Call) RunAndReturn(run func(ctx context.Context, configPath string, fs domain.FileSystemAdapter, permissions domain.PermissionSettings) error) *MockConfigMigrator_FixPermissionsPostMigration_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -193,3 +193,153 @@ func (_c *MockConfigProvider_GetKubeconfigDir_Call) RunAndReturn(run func() (str
 	_c.Call.Return(run)
 	return _c
 }
+
+// GetKubectlKubeconfigPath provides a mock function for the type MockConfigProvider
+func (_mock *MockConfigProvider) GetKubectlKubeconfigPath() (string, error) {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetKubectlKubeconfigPath")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func() (string, error)); ok {
+		return returnFunc()
+	}
+	if returnFunc, ok := ret.Get(0).(func() string); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func() error); ok {
+		r1 = returnFunc()
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockConfigProvider_GetKubectlKubeconfigPath_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetKubectlKubeconfigPath'
+type MockConfigProvider_GetKubectlKubeconfigPath_Call struct {
+	*mock.Call
+}
+
+// GetKubectlKubeconfigPath is a helper method to define mock.On call
+func (_e *MockConfigProvider_Expecter) GetKubectlKubeconfigPath() *MockConfigProvider_GetKubectlKubeconfigPath_Call {
+	return &MockConfigProvider_GetKubectlKubeconfigPath_Call{Call: _e.mock.On("GetKubectlKubeconfigPath")}
+}
+
+func (_c *MockConfigProvider_GetKubectlKubeconfigPath_Call) Run(run func()) *MockConfigProvider_GetKubectlKubeconfigPath_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfigProvider_GetKubectlKubeconfigPath_Call) Return(s string, err error) *MockConfigProvider_GetKubectlKubeconfigPath_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockConfigProvider_GetKubectlKubeconfigPath_Call) RunAndReturn(run func() (string, error)) *MockConfigProvider_GetKubectlKubeconfigPath_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetStateDir provides a mock function for the type MockConfigProvider
+func (_mock *MockConfigProvider) GetStateDir() (string, error) {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStateDir")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func() (string, error)); ok {
+		return returnFunc()
+	}
+	if returnFunc, ok := ret.Get(0).(func() string); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func() error); ok {
+		r1 = returnFunc()
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockConfigProvider_GetStateDir_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetStateDir'
+type MockConfigProvider_GetStateDir_Call struct {
+	*mock.Call
+}
+
+// GetStateDir is a helper method to define mock.On call
+func (_e *MockConfigProvider_Expecter) GetStateDir() *MockConfigProvider_GetStateDir_Call {
+	return &MockConfigProvider_GetStateDir_Call{Call: _e.mock.On("GetStateDir")}
+}
+
+func (_c *MockConfigProvider_GetStateDir_Call) Run(run func()) *MockConfigProvider_GetStateDir_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfigProvider_GetStateDir_Call) Return(s string, err error) *MockConfigProvider_GetStateDir_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockConfigProvider_GetStateDir_Call) RunAndReturn(run func() (string, error)) *MockConfigProvider_GetStateDir_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSystemConfigPath provides a mock function for the type MockConfigProvider
+func (_mock *MockConfigProvider) GetSystemConfigPath() string {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSystemConfigPath")
+	}
+
+	var r0 string
+	if returnFunc, ok := ret.Get(0).(func() string); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	return r0
+}
+
+// MockConfigProvider_GetSystemConfigPath_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSystemConfigPath'
+type MockConfigProvider_GetSystemConfigPath_Call struct {
+	*mock.Call
+}
+
+// GetSystemConfigPath is a helper method to define mock.On call
+func (_e *MockConfigProvider_Expecter) GetSystemConfigPath() *MockConfigProvider_GetSystemConfigPath_Call {
+	return &MockConfigProvider_GetSystemConfigPath_Call{Call: _e.mock.On("GetSystemConfigPath")}
+}
+
+func (_c *MockConfigProvider_GetSystemConfigPath_Call) Run(run func()) *MockConfigProvider_GetSystemConfigPath_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfigProvider_GetSystemConfigPath_Call) Return(s string) *MockConfigProvider_GetSystemConfigPath_Call {
+	_c.Call.Return(s)
+	return _c
+}
+
+func (_c *MockConfigProvider_GetSystemConfigPath_Call) RunAndReturn(run func() string) *MockConfigProvider_GetSystemConfigPath_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -95,6 +95,234 @@ func (_c *MockConfigRepository_AddServer_Call) RunAndReturn(run func(ctx context
 	return _c
 }
 
+// Backups provides a mock function for the type MockConfigRepository
+func (_mock *MockConfigRepository) Backups(ctx context.Context) ([]domain.ConfigBackup, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Backups")
+	}
+
+	var r0 []domain.ConfigBackup
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]domain.ConfigBackup, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []domain.ConfigBackup); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ConfigBackup)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockConfigRepository_Backups_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Backups'
+type MockConfigRepository_Backups_Call struct {
+	*mock.Call
+}
+
+// Backups is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockConfigRepository_Expecter) Backups(ctx interface{}) *MockConfigRepository_Backups_Call {
+	return &MockConfigRepository_Backups_Call{Call: _e.mock.On("Backups", ctx)}
+}
+
+func (_c *MockConfigRepository_Backups_Call) Run(run func(ctx context.Context)) *MockConfigRepository_Backups_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockConfigRepository_Backups_Call) Return(configBackups []domain.ConfigBackup, err error) *MockConfigRepository_Backups_Call {
+	_c.Call.Return(configBackups, err)
+	return _c
+}
+
+func (_c *MockConfigRepository_Backups_Call) RunAndReturn(run func(ctx context.Context) ([]domain.ConfigBackup, error)) *MockConfigRepository_Backups_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Encrypted provides a mock function for the type MockConfigRepository
+func (_mock *MockConfigRepository) Encrypted() bool {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Encrypted")
+	}
+
+	var r0 bool
+	if returnFunc, ok := ret.Get(0).(func() bool); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	return r0
+}
+
+// MockConfigRepository_Encrypted_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Encrypted'
+type MockConfigRepository_Encrypted_Call struct {
+	*mock.Call
+}
+
+// Encrypted is a helper method to define mock.On call
+func (_e *MockConfigRepository_Expecter) Encrypted() *MockConfigRepository_Encrypted_Call {
+	return &MockConfigRepository_Encrypted_Call{Call: _e.mock.On("Encrypted")}
+}
+
+func (_c *MockConfigRepository_Encrypted_Call) Run(run func()) *MockConfigRepository_Encrypted_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfigRepository_Encrypted_Call) Return(b bool) *MockConfigRepository_Encrypted_Call {
+	_c.Call.Return(b)
+	return _c
+}
+
+func (_c *MockConfigRepository_Encrypted_Call) RunAndReturn(run func() bool) *MockConfigRepository_Encrypted_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAllServers provides a mock function for the type MockConfigRepository
+func (_mock *MockConfigRepository) GetAllServers(ctx context.Context) ([]domain.ConfigServer, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllServers")
+	}
+
+	var r0 []domain.ConfigServer
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]domain.ConfigServer, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []domain.ConfigServer); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ConfigServer)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockConfigRepository_GetAllServers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllServers'
+type MockConfigRepository_GetAllServers_Call struct {
+	*mock.Call
+}
+
+// GetAllServers is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockConfigRepository_Expecter) GetAllServers(ctx interface{}) *MockConfigRepository_GetAllServers_Call {
+	return &MockConfigRepository_GetAllServers_Call{Call: _e.mock.On("GetAllServers", ctx)}
+}
+
+func (_c *MockConfigRepository_GetAllServers_Call) Run(run func(ctx context.Context)) *MockConfigRepository_GetAllServers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockConfigRepository_GetAllServers_Call) Return(configServers []domain.ConfigServer, err error) *MockConfigRepository_GetAllServers_Call {
+	_c.Call.Return(configServers, err)
+	return _c
+}
+
+func (_c *MockConfigRepository_GetAllServers_Call) RunAndReturn(run func(ctx context.Context) ([]domain.ConfigServer, error)) *MockConfigRepository_GetAllServers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDefaults provides a mock function for the type MockConfigRepository
+func (_mock *MockConfigRepository) GetDefaults(ctx context.Context) (domain.ProfileDefaults, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDefaults")
+	}
+
+	var r0 domain.ProfileDefaults
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (domain.ProfileDefaults, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) domain.ProfileDefaults); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(domain.ProfileDefaults)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockConfigRepository_GetDefaults_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDefaults'
+type MockConfigRepository_GetDefaults_Call struct {
+	*mock.Call
+}
+
+// GetDefaults is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockConfigRepository_Expecter) GetDefaults(ctx interface{}) *MockConfigRepository_GetDefaults_Call {
+	return &MockConfigRepository_GetDefaults_Call{Call: _e.mock.On("GetDefaults", ctx)}
+}
+
+func (_c *MockConfigRepository_GetDefaults_Call) Run(run func(ctx context.Context)) *MockConfigRepository_GetDefaults_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockConfigRepository_GetDefaults_Call) Return(profileDefaults domain.ProfileDefaults, err error) *MockConfigRepository_GetDefaults_Call {
+	_c.Call.Return(profileDefaults, err)
+	return _c
+}
+
+func (_c *MockConfigRepository_GetDefaults_Call) RunAndReturn(run func(ctx context.Context) (domain.ProfileDefaults, error)) *MockConfigRepository_GetDefaults_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetServers provides a mock function for the type MockConfigRepository
 func (_mock *MockConfigRepository) GetServers(ctx context.Context) ([]domain.ConfigServer, error) {
 	ret := _mock.Called(ctx)
@@ -157,6 +385,66 @@ func (_c *MockConfigRepository_GetServers_Call) RunAndReturn(run func(ctx contex
 	return _c
 }
 
+// GetSettings provides a mock function for the type MockConfigRepository
+func (_mock *MockConfigRepository) GetSettings(ctx context.Context) (domain.Settings, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSettings")
+	}
+
+	var r0 domain.Settings
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (domain.Settings, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) domain.Settings); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(domain.Settings)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockConfigRepository_GetSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSettings'
+type MockConfigRepository_GetSettings_Call struct {
+	*mock.Call
+}
+
+// GetSettings is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockConfigRepository_Expecter) GetSettings(ctx interface{}) *MockConfigRepository_GetSettings_Call {
+	return &MockConfigRepository_GetSettings_Call{Call: _e.mock.On("GetSettings", ctx)}
+}
+
+func (_c *MockConfigRepository_GetSettings_Call) Run(run func(ctx context.Context)) *MockConfigRepository_GetSettings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockConfigRepository_GetSettings_Call) Return(settings domain.Settings, err error) *MockConfigRepository_GetSettings_Call {
+	_c.Call.Return(settings, err)
+	return _c
+}
+
+func (_c *MockConfigRepository_GetSettings_Call) RunAndReturn(run func(ctx context.Context) (domain.Settings, error)) *MockConfigRepository_GetSettings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // LoadConfig provides a mock function for the type MockConfigRepository
 func (_mock *MockConfigRepository) LoadConfig(ctx context.Context) error {
 	ret := _mock.Called(ctx)
@@ -208,6 +496,50 @@ func (_c *MockConfigRepository_LoadConfig_Call) RunAndReturn(run func(ctx contex
 	return _c
 }
 
+// Profile provides a mock function for the type MockConfigRepository
+func (_mock *MockConfigRepository) Profile() string {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Profile")
+	}
+
+	var r0 string
+	if returnFunc, ok := ret.Get(0).(func() string); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	return r0
+}
+
+// MockConfigRepository_Profile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Profile'
+type MockConfigRepository_Profile_Call struct {
+	*mock.Call
+}
+
+// Profile is a helper method to define mock.On call
+func (_e *MockConfigRepository_Expecter) Profile() *MockConfigRepository_Profile_Call {
+	return &MockConfigRepository_Profile_Call{Call: _e.mock.On("Profile")}
+}
+
+func (_c *MockConfigRepository_Profile_Call) Run(run func()) *MockConfigRepository_Profile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfigRepository_Profile_Call) Return(s string) *MockConfigRepository_Profile_Call {
+	_c.Call.Return(s)
+	return _c
+}
+
+func (_c *MockConfigRepository_Profile_Call) RunAndReturn(run func() string) *MockConfigRepository_Profile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // RemoveServer provides a mock function for the type MockConfigRepository
 func (_mock *MockConfigRepository) RemoveServer(ctx context.Context, serverURL string) error {
 	ret := _mock.Called(ctx, serverURL)
@@ -322,6 +654,72 @@ func (_c *MockConfigRepository_RemoveServerByID_Call) RunAndReturn(run func(ctx
 	return _c
 }
 
+// RestoreBackup provides a mock function for the type MockConfigRepository
+func (_mock *MockConfigRepository) RestoreBackup(ctx context.Context, timestamp string) (domain.ConfigBackup, error) {
+	ret := _mock.Called(ctx, timestamp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreBackup")
+	}
+
+	var r0 domain.ConfigBackup
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (domain.ConfigBackup, error)); ok {
+		return returnFunc(ctx, timestamp)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) domain.ConfigBackup); ok {
+		r0 = returnFunc(ctx, timestamp)
+	} else {
+		r0 = ret.Get(0).(domain.ConfigBackup)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, timestamp)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockConfigRepository_RestoreBackup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestoreBackup'
+type MockConfigRepository_RestoreBackup_Call struct {
+	*mock.Call
+}
+
+// RestoreBackup is a helper method to define mock.On call
+//   - ctx context.Context
+//   - timestamp string
+func (_e *MockConfigRepository_Expecter) RestoreBackup(ctx interface{}, timestamp interface{}) *MockConfigRepository_RestoreBackup_Call {
+	return &MockConfigRepository_RestoreBackup_Call{Call: _e.mock.On("RestoreBackup", ctx, timestamp)}
+}
+
+func (_c *MockConfigRepository_RestoreBackup_Call) Run(run func(ctx context.Context, timestamp string)) *MockConfigRepository_RestoreBackup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockConfigRepository_RestoreBackup_Call) Return(configBackup domain.ConfigBackup, err error) *MockConfigRepository_RestoreBackup_Call {
+	_c.Call.Return(configBackup, err)
+	return _c
+}
+
+func (_c *MockConfigRepository_RestoreBackup_Call) RunAndReturn(run func(ctx context.Context, timestamp string) (domain.ConfigBackup, error)) *MockConfigRepository_RestoreBackup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SaveConfig provides a mock function for the type MockConfigRepository
 func (_mock *MockConfigRepository) SaveConfig(ctx context.Context) error {
 	ret := _mock.Called(ctx)
@@ -372,3 +770,186 @@ func (_c *MockConfigRepository_SaveConfig_Call) RunAndReturn(run func(ctx contex
 	_c.Call.Return(run)
 	return _c
 }
+
+// SetEncrypted provides a mock function for the type MockConfigRepository
+func (_mock *MockConfigRepository) SetEncrypted(ctx context.Context, encrypted bool) error {
+	ret := _mock.Called(ctx, encrypted)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetEncrypted")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bool) error); ok {
+		r0 = returnFunc(ctx, encrypted)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockConfigRepository_SetEncrypted_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetEncrypted'
+type MockConfigRepository_SetEncrypted_Call struct {
+	*mock.Call
+}
+
+// SetEncrypted is a helper method to define mock.On call
+//   - ctx context.Context
+//   - encrypted bool
+func (_e *MockConfigRepository_Expecter) SetEncrypted(ctx interface{}, encrypted interface{}) *MockConfigRepository_SetEncrypted_Call {
+	return &MockConfigRepository_SetEncrypted_Call{Call: _e.mock.On("SetEncrypted", ctx, encrypted)}
+}
+
+func (_c *MockConfigRepository_SetEncrypted_Call) Run(run func(ctx context.Context, encrypted bool)) *MockConfigRepository_SetEncrypted_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 bool
+		if args[1] != nil {
+			arg1 = args[1].(bool)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockConfigRepository_SetEncrypted_Call) Return(err error) *MockConfigRepository_SetEncrypted_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockConfigRepository_SetEncrypted_Call) RunAndReturn(run func(ctx context.Context, encrypted bool) error) *MockConfigRepository_SetEncrypted_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateServerAuthType provides a mock function for the type MockConfigRepository
+func (_mock *MockConfigRepository) UpdateServerAuthType(ctx context.Context, serverURL string, authType string) error {
+	ret := _mock.Called(ctx, serverURL, authType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateServerAuthType")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, serverURL, authType)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockConfigRepository_UpdateServerAuthType_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateServerAuthType'
+type MockConfigRepository_UpdateServerAuthType_Call struct {
+	*mock.Call
+}
+
+// UpdateServerAuthType is a helper method to define mock.On call
+//   - ctx context.Context
+//   - serverURL string
+//   - authType string
+func (_e *MockConfigRepository_Expecter) UpdateServerAuthType(ctx interface{}, serverURL interface{}, authType interface{}) *MockConfigRepository_UpdateServerAuthType_Call {
+	return &MockConfigRepository_UpdateServerAuthType_Call{Call: _e.mock.On("UpdateServerAuthType", ctx, serverURL, authType)}
+}
+
+func (_c *MockConfigRepository_UpdateServerAuthType_Call) Run(run func(ctx context.Context, serverURL string, authType string)) *MockConfigRepository_UpdateServerAuthType_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockConfigRepository_UpdateServerAuthType_Call) Return(err error) *MockConfigRepository_UpdateServerAuthType_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockConfigRepository_UpdateServerAuthType_Call) RunAndReturn(run func(ctx context.Context, serverURL string, authType string) error) *MockConfigRepository_UpdateServerAuthType_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateServerURL provides a mock function for the type MockConfigRepository
+func (_mock *MockConfigRepository) UpdateServerURL(ctx context.Context, fromURL string, toURL string) error {
+	ret := _mock.Called(ctx, fromURL, toURL)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateServerURL")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, fromURL, toURL)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockConfigRepository_UpdateServerURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateServerURL'
+type MockConfigRepository_UpdateServerURL_Call struct {
+	*mock.Call
+}
+
+// UpdateServerURL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fromURL string
+//   - toURL string
+func (_e *MockConfigRepository_Expecter) UpdateServerURL(ctx interface{}, fromURL interface{}, toURL interface{}) *MockConfigRepository_UpdateServerURL_Call {
+	return &MockConfigRepository_UpdateServerURL_Call{Call: _e.mock.On("UpdateServerURL", ctx, fromURL, toURL)}
+}
+
+func (_c *MockConfigRepository_UpdateServerURL_Call) Run(run func(ctx context.Context, fromURL string, toURL string)) *MockConfigRepository_UpdateServerURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockConfigRepository_UpdateServerURL_Call) Return(err error) *MockConfigRepository_UpdateServerURL_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockConfigRepository_UpdateServerURL_Call) RunAndReturn(run func(ctx context.Context, fromURL string, toURL string) error) *MockConfigRepository_UpdateServerURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
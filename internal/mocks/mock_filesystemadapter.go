@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"os"
+	"time"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -94,6 +95,132 @@ func (_c *MockFileSystemAdapter_Chmod_Call) RunAndReturn(run func(path string, p
 	return _c
 }
 
+// Chown provides a mock function for the type MockFileSystemAdapter
+func (_mock *MockFileSystemAdapter) Chown(path string, uid int, gid int) error {
+	ret := _mock.Called(path, uid, gid)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Chown")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, int, int) error); ok {
+		r0 = returnFunc(path, uid, gid)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockFileSystemAdapter_Chown_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Chown'
+type MockFileSystemAdapter_Chown_Call struct {
+	*mock.Call
+}
+
+// Chown is a helper method to define mock.On call
+//   - path string
+//   - uid int
+//   - gid int
+func (_e *MockFileSystemAdapter_Expecter) Chown(path interface{}, uid interface{}, gid interface{}) *MockFileSystemAdapter_Chown_Call {
+	return &MockFileSystemAdapter_Chown_Call{Call: _e.mock.On("Chown", path, uid, gid)}
+}
+
+func (_c *MockFileSystemAdapter_Chown_Call) Run(run func(path string, uid int, gid int)) *MockFileSystemAdapter_Chown_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockFileSystemAdapter_Chown_Call) Return(err error) *MockFileSystemAdapter_Chown_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockFileSystemAdapter_Chown_Call) RunAndReturn(run func(path string, uid int, gid int) error) *MockFileSystemAdapter_Chown_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Chtimes provides a mock function for the type MockFileSystemAdapter
+func (_mock *MockFileSystemAdapter) Chtimes(path string, atime time.Time, mtime time.Time) error {
+	ret := _mock.Called(path, atime, mtime)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Chtimes")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, time.Time, time.Time) error); ok {
+		r0 = returnFunc(path, atime, mtime)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockFileSystemAdapter_Chtimes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Chtimes'
+type MockFileSystemAdapter_Chtimes_Call struct {
+	*mock.Call
+}
+
+// Chtimes is a helper method to define mock.On call
+//   - path string
+//   - atime time.Time
+//   - mtime time.Time
+func (_e *MockFileSystemAdapter_Expecter) Chtimes(path interface{}, atime interface{}, mtime interface{}) *MockFileSystemAdapter_Chtimes_Call {
+	return &MockFileSystemAdapter_Chtimes_Call{Call: _e.mock.On("Chtimes", path, atime, mtime)}
+}
+
+func (_c *MockFileSystemAdapter_Chtimes_Call) Run(run func(path string, atime time.Time, mtime time.Time)) *MockFileSystemAdapter_Chtimes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockFileSystemAdapter_Chtimes_Call) Return(err error) *MockFileSystemAdapter_Chtimes_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockFileSystemAdapter_Chtimes_Call) RunAndReturn(run func(path string, atime time.Time, mtime time.Time) error) *MockFileSystemAdapter_Chtimes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // MkdirAll provides a mock function for the type MockFileSystemAdapter
 func (_mock *MockFileSystemAdapter) MkdirAll(path string, perm os.FileMode) error {
 	ret := _mock.Called(path, perm)
@@ -151,6 +278,68 @@ func (_c *MockFileSystemAdapter_MkdirAll_Call) RunAndReturn(run func(path string
 	return _c
 }
 
+// ReadDir provides a mock function for the type MockFileSystemAdapter
+func (_mock *MockFileSystemAdapter) ReadDir(path string) ([]os.DirEntry, error) {
+	ret := _mock.Called(path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadDir")
+	}
+
+	var r0 []os.DirEntry
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) ([]os.DirEntry, error)); ok {
+		return returnFunc(path)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) []os.DirEntry); ok {
+		r0 = returnFunc(path)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]os.DirEntry)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(path)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockFileSystemAdapter_ReadDir_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReadDir'
+type MockFileSystemAdapter_ReadDir_Call struct {
+	*mock.Call
+}
+
+// ReadDir is a helper method to define mock.On call
+//   - path string
+func (_e *MockFileSystemAdapter_Expecter) ReadDir(path interface{}) *MockFileSystemAdapter_ReadDir_Call {
+	return &MockFileSystemAdapter_ReadDir_Call{Call: _e.mock.On("ReadDir", path)}
+}
+
+func (_c *MockFileSystemAdapter_ReadDir_Call) Run(run func(path string)) *MockFileSystemAdapter_ReadDir_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockFileSystemAdapter_ReadDir_Call) Return(dirEntrys []os.DirEntry, err error) *MockFileSystemAdapter_ReadDir_Call {
+	_c.Call.Return(dirEntrys, err)
+	return _c
+}
+
+func (_c *MockFileSystemAdapter_ReadDir_Call) RunAndReturn(run func(path string) ([]os.DirEntry, error)) *MockFileSystemAdapter_ReadDir_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ReadFile provides a mock function for the type MockFileSystemAdapter
 func (_mock *MockFileSystemAdapter) ReadFile(path string) ([]byte, error) {
 	ret := _mock.Called(path)
@@ -264,6 +453,63 @@ func (_c *MockFileSystemAdapter_Remove_Call) RunAndReturn(run func(path string)
 	return _c
 }
 
+// Rename provides a mock function for the type MockFileSystemAdapter
+func (_mock *MockFileSystemAdapter) Rename(oldPath string, newPath string) error {
+	ret := _mock.Called(oldPath, newPath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rename")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = returnFunc(oldPath, newPath)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockFileSystemAdapter_Rename_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Rename'
+type MockFileSystemAdapter_Rename_Call struct {
+	*mock.Call
+}
+
+// Rename is a helper method to define mock.On call
+//   - oldPath string
+//   - newPath string
+func (_e *MockFileSystemAdapter_Expecter) Rename(oldPath interface{}, newPath interface{}) *MockFileSystemAdapter_Rename_Call {
+	return &MockFileSystemAdapter_Rename_Call{Call: _e.mock.On("Rename", oldPath, newPath)}
+}
+
+func (_c *MockFileSystemAdapter_Rename_Call) Run(run func(oldPath string, newPath string)) *MockFileSystemAdapter_Rename_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockFileSystemAdapter_Rename_Call) Return(err error) *MockFileSystemAdapter_Rename_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockFileSystemAdapter_Rename_Call) RunAndReturn(run func(oldPath string, newPath string) error) *MockFileSystemAdapter_Rename_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Stat provides a mock function for the type MockFileSystemAdapter
 func (_mock *MockFileSystemAdapter) Stat(path string) (os.FileInfo, error) {
 	ret := _mock.Called(path)
@@ -38,6 +38,131 @@ func (_m *MockHTTPAdapter) EXPECT() *MockHTTPAdapter_Expecter {
 	return &MockHTTPAdapter_Expecter{mock: &_m.Mock}
 }
 
+// CloseHost provides a mock function for the type MockHTTPAdapter
+func (_mock *MockHTTPAdapter) CloseHost(serverURL string) error {
+	ret := _mock.Called(serverURL)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CloseHost")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string) error); ok {
+		r0 = returnFunc(serverURL)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockHTTPAdapter_CloseHost_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CloseHost'
+type MockHTTPAdapter_CloseHost_Call struct {
+	*mock.Call
+}
+
+// CloseHost is a helper method to define mock.On call
+//   - serverURL string
+func (_e *MockHTTPAdapter_Expecter) CloseHost(serverURL interface{}) *MockHTTPAdapter_CloseHost_Call {
+	return &MockHTTPAdapter_CloseHost_Call{Call: _e.mock.On("CloseHost", serverURL)}
+}
+
+func (_c *MockHTTPAdapter_CloseHost_Call) Run(run func(serverURL string)) *MockHTTPAdapter_CloseHost_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockHTTPAdapter_CloseHost_Call) Return(err error) *MockHTTPAdapter_CloseHost_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockHTTPAdapter_CloseHost_Call) RunAndReturn(run func(serverURL string) error) *MockHTTPAdapter_CloseHost_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteWithAuth provides a mock function for the type MockHTTPAdapter
+func (_mock *MockHTTPAdapter) DeleteWithAuth(ctx context.Context, url string, token string) (*http.Response, error) {
+	ret := _mock.Called(ctx, url, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWithAuth")
+	}
+
+	var r0 *http.Response
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*http.Response, error)); ok {
+		return returnFunc(ctx, url, token)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *http.Response); ok {
+		r0 = returnFunc(ctx, url, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*http.Response)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, url, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockHTTPAdapter_DeleteWithAuth_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteWithAuth'
+type MockHTTPAdapter_DeleteWithAuth_Call struct {
+	*mock.Call
+}
+
+// DeleteWithAuth is a helper method to define mock.On call
+//   - ctx context.Context
+//   - url string
+//   - token string
+func (_e *MockHTTPAdapter_Expecter) DeleteWithAuth(ctx interface{}, url interface{}, token interface{}) *MockHTTPAdapter_DeleteWithAuth_Call {
+	return &MockHTTPAdapter_DeleteWithAuth_Call{Call: _e.mock.On("DeleteWithAuth", ctx, url, token)}
+}
+
+func (_c *MockHTTPAdapter_DeleteWithAuth_Call) Run(run func(ctx context.Context, url string, token string)) *MockHTTPAdapter_DeleteWithAuth_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockHTTPAdapter_DeleteWithAuth_Call) Return(response *http.Response, err error) *MockHTTPAdapter_DeleteWithAuth_Call {
+	_c.Call.Return(response, err)
+	return _c
+}
+
+func (_c *MockHTTPAdapter_DeleteWithAuth_Call) RunAndReturn(run func(ctx context.Context, url string, token string) (*http.Response, error)) *MockHTTPAdapter_DeleteWithAuth_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Get provides a mock function for the type MockHTTPAdapter
 func (_mock *MockHTTPAdapter) Get(ctx context.Context, url string) (*http.Response, error) {
 	ret := _mock.Called(ctx, url)
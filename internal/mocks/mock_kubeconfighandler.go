@@ -95,6 +95,271 @@ func (_c *MockKubeconfigHandler_CleanupTempFiles_Call) RunAndReturn(run func(ctx
 	return _c
 }
 
+// ClusterCredential provides a mock function for the type MockKubeconfigHandler
+func (_mock *MockKubeconfigHandler) ClusterCredential(ctx context.Context, path string) (domain.ClusterCredential, error) {
+	ret := _mock.Called(ctx, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClusterCredential")
+	}
+
+	var r0 domain.ClusterCredential
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (domain.ClusterCredential, error)); ok {
+		return returnFunc(ctx, path)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) domain.ClusterCredential); ok {
+		r0 = returnFunc(ctx, path)
+	} else {
+		r0 = ret.Get(0).(domain.ClusterCredential)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, path)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockKubeconfigHandler_ClusterCredential_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClusterCredential'
+type MockKubeconfigHandler_ClusterCredential_Call struct {
+	*mock.Call
+}
+
+// ClusterCredential is a helper method to define mock.On call
+//   - ctx context.Context
+//   - path string
+func (_e *MockKubeconfigHandler_Expecter) ClusterCredential(ctx interface{}, path interface{}) *MockKubeconfigHandler_ClusterCredential_Call {
+	return &MockKubeconfigHandler_ClusterCredential_Call{Call: _e.mock.On("ClusterCredential", ctx, path)}
+}
+
+func (_c *MockKubeconfigHandler_ClusterCredential_Call) Run(run func(ctx context.Context, path string)) *MockKubeconfigHandler_ClusterCredential_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockKubeconfigHandler_ClusterCredential_Call) Return(clusterCredential domain.ClusterCredential, err error) *MockKubeconfigHandler_ClusterCredential_Call {
+	_c.Call.Return(clusterCredential, err)
+	return _c
+}
+
+func (_c *MockKubeconfigHandler_ClusterCredential_Call) RunAndReturn(run func(ctx context.Context, path string) (domain.ClusterCredential, error)) *MockKubeconfigHandler_ClusterCredential_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountContexts provides a mock function for the type MockKubeconfigHandler
+func (_mock *MockKubeconfigHandler) CountContexts(ctx context.Context, paths []string, filter domain.ClusterFilter) int {
+	ret := _mock.Called(ctx, paths, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountContexts")
+	}
+
+	var r0 int
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, domain.ClusterFilter) int); ok {
+		r0 = returnFunc(ctx, paths, filter)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	return r0
+}
+
+// MockKubeconfigHandler_CountContexts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountContexts'
+type MockKubeconfigHandler_CountContexts_Call struct {
+	*mock.Call
+}
+
+// CountContexts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - paths []string
+//   - filter domain.ClusterFilter
+func (_e *MockKubeconfigHandler_Expecter) CountContexts(ctx interface{}, paths interface{}, filter interface{}) *MockKubeconfigHandler_CountContexts_Call {
+	return &MockKubeconfigHandler_CountContexts_Call{Call: _e.mock.On("CountContexts", ctx, paths, filter)}
+}
+
+func (_c *MockKubeconfigHandler_CountContexts_Call) Run(run func(ctx context.Context, paths []string, filter domain.ClusterFilter)) *MockKubeconfigHandler_CountContexts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		var arg2 domain.ClusterFilter
+		if args[2] != nil {
+			arg2 = args[2].(domain.ClusterFilter)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockKubeconfigHandler_CountContexts_Call) Return(n int) *MockKubeconfigHandler_CountContexts_Call {
+	_c.Call.Return(n)
+	return _c
+}
+
+func (_c *MockKubeconfigHandler_CountContexts_Call) RunAndReturn(run func(ctx context.Context, paths []string, filter domain.ClusterFilter) int) *MockKubeconfigHandler_CountContexts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ForeignContexts provides a mock function for the type MockKubeconfigHandler
+func (_mock *MockKubeconfigHandler) ForeignContexts(ctx context.Context, path string) ([]string, error) {
+	ret := _mock.Called(ctx, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ForeignContexts")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]string, error)); ok {
+		return returnFunc(ctx, path)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []string); ok {
+		r0 = returnFunc(ctx, path)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, path)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockKubeconfigHandler_ForeignContexts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ForeignContexts'
+type MockKubeconfigHandler_ForeignContexts_Call struct {
+	*mock.Call
+}
+
+// ForeignContexts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - path string
+func (_e *MockKubeconfigHandler_Expecter) ForeignContexts(ctx interface{}, path interface{}) *MockKubeconfigHandler_ForeignContexts_Call {
+	return &MockKubeconfigHandler_ForeignContexts_Call{Call: _e.mock.On("ForeignContexts", ctx, path)}
+}
+
+func (_c *MockKubeconfigHandler_ForeignContexts_Call) Run(run func(ctx context.Context, path string)) *MockKubeconfigHandler_ForeignContexts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockKubeconfigHandler_ForeignContexts_Call) Return(ss []string, err error) *MockKubeconfigHandler_ForeignContexts_Call {
+	_c.Call.Return(ss, err)
+	return _c
+}
+
+func (_c *MockKubeconfigHandler_ForeignContexts_Call) RunAndReturn(run func(ctx context.Context, path string) ([]string, error)) *MockKubeconfigHandler_ForeignContexts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListContexts provides a mock function for the type MockKubeconfigHandler
+func (_mock *MockKubeconfigHandler) ListContexts(ctx context.Context, path string) ([]domain.ManagedContext, error) {
+	ret := _mock.Called(ctx, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListContexts")
+	}
+
+	var r0 []domain.ManagedContext
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]domain.ManagedContext, error)); ok {
+		return returnFunc(ctx, path)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []domain.ManagedContext); ok {
+		r0 = returnFunc(ctx, path)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ManagedContext)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, path)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockKubeconfigHandler_ListContexts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListContexts'
+type MockKubeconfigHandler_ListContexts_Call struct {
+	*mock.Call
+}
+
+// ListContexts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - path string
+func (_e *MockKubeconfigHandler_Expecter) ListContexts(ctx interface{}, path interface{}) *MockKubeconfigHandler_ListContexts_Call {
+	return &MockKubeconfigHandler_ListContexts_Call{Call: _e.mock.On("ListContexts", ctx, path)}
+}
+
+func (_c *MockKubeconfigHandler_ListContexts_Call) Run(run func(ctx context.Context, path string)) *MockKubeconfigHandler_ListContexts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockKubeconfigHandler_ListContexts_Call) Return(managedContexts []domain.ManagedContext, err error) *MockKubeconfigHandler_ListContexts_Call {
+	_c.Call.Return(managedContexts, err)
+	return _c
+}
+
+func (_c *MockKubeconfigHandler_ListContexts_Call) RunAndReturn(run func(ctx context.Context, path string) ([]domain.ManagedContext, error)) *MockKubeconfigHandler_ListContexts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // MergeKubeconfigs provides a mock function for the type MockKubeconfigHandler
 func (_mock *MockKubeconfigHandler) MergeKubeconfigs(ctx context.Context, paths []string, outputPath string, filter domain.ClusterFilter) error {
 	ret := _mock.Called(ctx, paths, outputPath, filter)
@@ -164,17 +429,241 @@ func (_c *MockKubeconfigHandler_MergeKubeconfigs_Call) RunAndReturn(run func(ctx
 	return _c
 }
 
+// ReadKubeconfig provides a mock function for the type MockKubeconfigHandler
+func (_mock *MockKubeconfigHandler) ReadKubeconfig(ctx context.Context, path string, redact bool) ([]byte, error) {
+	ret := _mock.Called(ctx, path, redact)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadKubeconfig")
+	}
+
+	var r0 []byte
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, bool) ([]byte, error)); ok {
+		return returnFunc(ctx, path, redact)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, bool) []byte); ok {
+		r0 = returnFunc(ctx, path, redact)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, bool) error); ok {
+		r1 = returnFunc(ctx, path, redact)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockKubeconfigHandler_ReadKubeconfig_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReadKubeconfig'
+type MockKubeconfigHandler_ReadKubeconfig_Call struct {
+	*mock.Call
+}
+
+// ReadKubeconfig is a helper method to define mock.On call
+//   - ctx context.Context
+//   - path string
+//   - redact bool
+func (_e *MockKubeconfigHandler_Expecter) ReadKubeconfig(ctx interface{}, path interface{}, redact interface{}) *MockKubeconfigHandler_ReadKubeconfig_Call {
+	return &MockKubeconfigHandler_ReadKubeconfig_Call{Call: _e.mock.On("ReadKubeconfig", ctx, path, redact)}
+}
+
+func (_c *MockKubeconfigHandler_ReadKubeconfig_Call) Run(run func(ctx context.Context, path string, redact bool)) *MockKubeconfigHandler_ReadKubeconfig_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 bool
+		if args[2] != nil {
+			arg2 = args[2].(bool)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockKubeconfigHandler_ReadKubeconfig_Call) Return(bytes []byte, err error) *MockKubeconfigHandler_ReadKubeconfig_Call {
+	_c.Call.Return(bytes, err)
+	return _c
+}
+
+func (_c *MockKubeconfigHandler_ReadKubeconfig_Call) RunAndReturn(run func(ctx context.Context, path string, redact bool) ([]byte, error)) *MockKubeconfigHandler_ReadKubeconfig_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RemoveContexts provides a mock function for the type MockKubeconfigHandler
+func (_mock *MockKubeconfigHandler) RemoveContexts(ctx context.Context, path string, names []string) (int, error) {
+	ret := _mock.Called(ctx, path, names)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveContexts")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []string) (int, error)); ok {
+		return returnFunc(ctx, path, names)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []string) int); ok {
+		r0 = returnFunc(ctx, path, names)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = returnFunc(ctx, path, names)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockKubeconfigHandler_RemoveContexts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveContexts'
+type MockKubeconfigHandler_RemoveContexts_Call struct {
+	*mock.Call
+}
+
+// RemoveContexts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - path string
+//   - names []string
+func (_e *MockKubeconfigHandler_Expecter) RemoveContexts(ctx interface{}, path interface{}, names interface{}) *MockKubeconfigHandler_RemoveContexts_Call {
+	return &MockKubeconfigHandler_RemoveContexts_Call{Call: _e.mock.On("RemoveContexts", ctx, path, names)}
+}
+
+func (_c *MockKubeconfigHandler_RemoveContexts_Call) Run(run func(ctx context.Context, path string, names []string)) *MockKubeconfigHandler_RemoveContexts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []string
+		if args[2] != nil {
+			arg2 = args[2].([]string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockKubeconfigHandler_RemoveContexts_Call) Return(n int, err error) *MockKubeconfigHandler_RemoveContexts_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockKubeconfigHandler_RemoveContexts_Call) RunAndReturn(run func(ctx context.Context, path string, names []string) (int, error)) *MockKubeconfigHandler_RemoveContexts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RenameServer provides a mock function for the type MockKubeconfigHandler
+func (_mock *MockKubeconfigHandler) RenameServer(ctx context.Context, path string, from domain.ConfigServer, to domain.ConfigServer) (int, error) {
+	ret := _mock.Called(ctx, path, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenameServer")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, domain.ConfigServer, domain.ConfigServer) (int, error)); ok {
+		return returnFunc(ctx, path, from, to)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, domain.ConfigServer, domain.ConfigServer) int); ok {
+		r0 = returnFunc(ctx, path, from, to)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, domain.ConfigServer, domain.ConfigServer) error); ok {
+		r1 = returnFunc(ctx, path, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockKubeconfigHandler_RenameServer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RenameServer'
+type MockKubeconfigHandler_RenameServer_Call struct {
+	*mock.Call
+}
+
+// RenameServer is a helper method to define mock.On call
+//   - ctx context.Context
+//   - path string
+//   - from domain.ConfigServer
+//   - to domain.ConfigServer
+func (_e *MockKubeconfigHandler_Expecter) RenameServer(ctx interface{}, path interface{}, from interface{}, to interface{}) *MockKubeconfigHandler_RenameServer_Call {
+	return &MockKubeconfigHandler_RenameServer_Call{Call: _e.mock.On("RenameServer", ctx, path, from, to)}
+}
+
+func (_c *MockKubeconfigHandler_RenameServer_Call) Run(run func(ctx context.Context, path string, from domain.ConfigServer, to domain.ConfigServer)) *MockKubeconfigHandler_RenameServer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 domain.ConfigServer
+		if args[2] != nil {
+			arg2 = args[2].(domain.ConfigServer)
+		}
+		var arg3 domain.ConfigServer
+		if args[3] != nil {
+			arg3 = args[3].(domain.ConfigServer)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockKubeconfigHandler_RenameServer_Call) Return(n int, err error) *MockKubeconfigHandler_RenameServer_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockKubeconfigHandler_RenameServer_Call) RunAndReturn(run func(ctx context.Context, path string, from domain.ConfigServer, to domain.ConfigServer) (int, error)) *MockKubeconfigHandler_RenameServer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SaveKubeconfig provides a mock function for the type MockKubeconfigHandler
-func (_mock *MockKubeconfigHandler) SaveKubeconfig(ctx context.Context, path string, content []byte, serverID string) error {
-	ret := _mock.Called(ctx, path, content, serverID)
+func (_mock *MockKubeconfigHandler) SaveKubeconfig(ctx context.Context, path string, content []byte, owner domain.ContextOwner) error {
+	ret := _mock.Called(ctx, path, content, owner)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SaveKubeconfig")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []byte, string) error); ok {
-		r0 = returnFunc(ctx, path, content, serverID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []byte, domain.ContextOwner) error); ok {
+		r0 = returnFunc(ctx, path, content, owner)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -190,12 +679,12 @@ type MockKubeconfigHandler_SaveKubeconfig_Call struct {
 //   - ctx context.Context
 //   - path string
 //   - content []byte
-//   - serverID string
-func (_e *MockKubeconfigHandler_Expecter) SaveKubeconfig(ctx interface{}, path interface{}, content interface{}, serverID interface{}) *MockKubeconfigHandler_SaveKubeconfig_Call {
-	return &MockKubeconfigHandler_SaveKubeconfig_Call{Call: _e.mock.On("SaveKubeconfig", ctx, path, content, serverID)}
+//   - owner domain.ContextOwner
+func (_e *MockKubeconfigHandler_Expecter) SaveKubeconfig(ctx interface{}, path interface{}, content interface{}, owner interface{}) *MockKubeconfigHandler_SaveKubeconfig_Call {
+	return &MockKubeconfigHandler_SaveKubeconfig_Call{Call: _e.mock.On("SaveKubeconfig", ctx, path, content, owner)}
 }
 
-func (_c *MockKubeconfigHandler_SaveKubeconfig_Call) Run(run func(ctx context.Context, path string, content []byte, serverID string)) *MockKubeconfigHandler_SaveKubeconfig_Call {
+func (_c *MockKubeconfigHandler_SaveKubeconfig_Call) Run(run func(ctx context.Context, path string, content []byte, owner domain.ContextOwner)) *MockKubeconfigHandler_SaveKubeconfig_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -209,9 +698,9 @@ func (_c *MockKubeconfigHandler_SaveKubeconfig_Call) Run(run func(ctx context.Co
 		if args[2] != nil {
 			arg2 = args[2].([]byte)
 		}
-		var arg3 string
+		var arg3 domain.ContextOwner
 		if args[3] != nil {
-			arg3 = args[3].(string)
+			arg3 = args[3].(domain.ContextOwner)
 		}
 		run(
 			arg0,
@@ -228,7 +717,138 @@ func (_c *MockKubeconfigHandler_SaveKubeconfig_Call) Return(err error) *MockKube
 	return _c
 }
 
-func (_c *MockKubeconfigHandler_SaveKubeconfig_Call) RunAndReturn(run func(ctx context.Context, path string, content []byte, serverID string) error) *MockKubeconfigHandler_SaveKubeconfig_Call {
+func (_c *MockKubeconfigHandler_SaveKubeconfig_Call) RunAndReturn(run func(ctx context.Context, path string, content []byte, owner domain.ContextOwner) error) *MockKubeconfigHandler_SaveKubeconfig_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SnapshotContexts provides a mock function for the type MockKubeconfigHandler
+func (_mock *MockKubeconfigHandler) SnapshotContexts(ctx context.Context, path string) ([]domain.ContextSnapshot, error) {
+	ret := _mock.Called(ctx, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SnapshotContexts")
+	}
+
+	var r0 []domain.ContextSnapshot
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]domain.ContextSnapshot, error)); ok {
+		return returnFunc(ctx, path)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []domain.ContextSnapshot); ok {
+		r0 = returnFunc(ctx, path)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ContextSnapshot)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, path)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockKubeconfigHandler_SnapshotContexts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SnapshotContexts'
+type MockKubeconfigHandler_SnapshotContexts_Call struct {
+	*mock.Call
+}
+
+// SnapshotContexts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - path string
+func (_e *MockKubeconfigHandler_Expecter) SnapshotContexts(ctx interface{}, path interface{}) *MockKubeconfigHandler_SnapshotContexts_Call {
+	return &MockKubeconfigHandler_SnapshotContexts_Call{Call: _e.mock.On("SnapshotContexts", ctx, path)}
+}
+
+func (_c *MockKubeconfigHandler_SnapshotContexts_Call) Run(run func(ctx context.Context, path string)) *MockKubeconfigHandler_SnapshotContexts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockKubeconfigHandler_SnapshotContexts_Call) Return(contextSnapshots []domain.ContextSnapshot, err error) *MockKubeconfigHandler_SnapshotContexts_Call {
+	_c.Call.Return(contextSnapshots, err)
+	return _c
+}
+
+func (_c *MockKubeconfigHandler_SnapshotContexts_Call) RunAndReturn(run func(ctx context.Context, path string) ([]domain.ContextSnapshot, error)) *MockKubeconfigHandler_SnapshotContexts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseContext provides a mock function for the type MockKubeconfigHandler
+func (_mock *MockKubeconfigHandler) UseContext(ctx context.Context, path string, name string) error {
+	ret := _mock.Called(ctx, path, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseContext")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, path, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockKubeconfigHandler_UseContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseContext'
+type MockKubeconfigHandler_UseContext_Call struct {
+	*mock.Call
+}
+
+// UseContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - path string
+//   - name string
+func (_e *MockKubeconfigHandler_Expecter) UseContext(ctx interface{}, path interface{}, name interface{}) *MockKubeconfigHandler_UseContext_Call {
+	return &MockKubeconfigHandler_UseContext_Call{Call: _e.mock.On("UseContext", ctx, path, name)}
+}
+
+func (_c *MockKubeconfigHandler_UseContext_Call) Run(run func(ctx context.Context, path string, name string)) *MockKubeconfigHandler_UseContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockKubeconfigHandler_UseContext_Call) Return(err error) *MockKubeconfigHandler_UseContext_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockKubeconfigHandler_UseContext_Call) RunAndReturn(run func(ctx context.Context, path string, name string) error) *MockKubeconfigHandler_UseContext_Call {
 	_c.Call.Return(run)
 	return _c
 }
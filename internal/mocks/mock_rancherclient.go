@@ -7,6 +7,7 @@ package mocks
 import (
 	"context"
 	"cowpoke/internal/domain"
+	"time"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -38,6 +39,74 @@ func (_m *MockRancherClient) EXPECT() *MockRancherClient_Expecter {
 	return &MockRancherClient_Expecter{mock: &_m.Mock}
 }
 
+// AuthProviders provides a mock function for the type MockRancherClient
+func (_mock *MockRancherClient) AuthProviders(ctx context.Context, server domain.ConfigServer) ([]domain.AuthProvider, error) {
+	ret := _mock.Called(ctx, server)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthProviders")
+	}
+
+	var r0 []domain.AuthProvider
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.ConfigServer) ([]domain.AuthProvider, error)); ok {
+		return returnFunc(ctx, server)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.ConfigServer) []domain.AuthProvider); ok {
+		r0 = returnFunc(ctx, server)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AuthProvider)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.ConfigServer) error); ok {
+		r1 = returnFunc(ctx, server)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRancherClient_AuthProviders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AuthProviders'
+type MockRancherClient_AuthProviders_Call struct {
+	*mock.Call
+}
+
+// AuthProviders is a helper method to define mock.On call
+//   - ctx context.Context
+//   - server domain.ConfigServer
+func (_e *MockRancherClient_Expecter) AuthProviders(ctx interface{}, server interface{}) *MockRancherClient_AuthProviders_Call {
+	return &MockRancherClient_AuthProviders_Call{Call: _e.mock.On("AuthProviders", ctx, server)}
+}
+
+func (_c *MockRancherClient_AuthProviders_Call) Run(run func(ctx context.Context, server domain.ConfigServer)) *MockRancherClient_AuthProviders_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 domain.ConfigServer
+		if args[1] != nil {
+			arg1 = args[1].(domain.ConfigServer)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRancherClient_AuthProviders_Call) Return(authProviders []domain.AuthProvider, err error) *MockRancherClient_AuthProviders_Call {
+	_c.Call.Return(authProviders, err)
+	return _c
+}
+
+func (_c *MockRancherClient_AuthProviders_Call) RunAndReturn(run func(ctx context.Context, server domain.ConfigServer) ([]domain.AuthProvider, error)) *MockRancherClient_AuthProviders_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Authenticate provides a mock function for the type MockRancherClient
 func (_mock *MockRancherClient) Authenticate(ctx context.Context, server domain.ConfigServer, password string) (domain.AuthToken, error) {
 	ret := _mock.Called(ctx, server, password)
@@ -112,6 +181,152 @@ func (_c *MockRancherClient_Authenticate_Call) RunAndReturn(run func(ctx context
 	return _c
 }
 
+// ClusterOwnership provides a mock function for the type MockRancherClient
+func (_mock *MockRancherClient) ClusterOwnership(ctx context.Context, token domain.AuthToken, server domain.ConfigServer) (domain.ClusterOwnership, error) {
+	ret := _mock.Called(ctx, token, server)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClusterOwnership")
+	}
+
+	var r0 domain.ClusterOwnership
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.AuthToken, domain.ConfigServer) (domain.ClusterOwnership, error)); ok {
+		return returnFunc(ctx, token, server)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.AuthToken, domain.ConfigServer) domain.ClusterOwnership); ok {
+		r0 = returnFunc(ctx, token, server)
+	} else {
+		r0 = ret.Get(0).(domain.ClusterOwnership)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.AuthToken, domain.ConfigServer) error); ok {
+		r1 = returnFunc(ctx, token, server)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRancherClient_ClusterOwnership_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClusterOwnership'
+type MockRancherClient_ClusterOwnership_Call struct {
+	*mock.Call
+}
+
+// ClusterOwnership is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token domain.AuthToken
+//   - server domain.ConfigServer
+func (_e *MockRancherClient_Expecter) ClusterOwnership(ctx interface{}, token interface{}, server interface{}) *MockRancherClient_ClusterOwnership_Call {
+	return &MockRancherClient_ClusterOwnership_Call{Call: _e.mock.On("ClusterOwnership", ctx, token, server)}
+}
+
+func (_c *MockRancherClient_ClusterOwnership_Call) Run(run func(ctx context.Context, token domain.AuthToken, server domain.ConfigServer)) *MockRancherClient_ClusterOwnership_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 domain.AuthToken
+		if args[1] != nil {
+			arg1 = args[1].(domain.AuthToken)
+		}
+		var arg2 domain.ConfigServer
+		if args[2] != nil {
+			arg2 = args[2].(domain.ConfigServer)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRancherClient_ClusterOwnership_Call) Return(clusterOwnership domain.ClusterOwnership, err error) *MockRancherClient_ClusterOwnership_Call {
+	_c.Call.Return(clusterOwnership, err)
+	return _c
+}
+
+func (_c *MockRancherClient_ClusterOwnership_Call) RunAndReturn(run func(ctx context.Context, token domain.AuthToken, server domain.ConfigServer) (domain.ClusterOwnership, error)) *MockRancherClient_ClusterOwnership_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateAPIToken provides a mock function for the type MockRancherClient
+func (_mock *MockRancherClient) CreateAPIToken(ctx context.Context, session domain.AuthToken, server domain.ConfigServer) (domain.AuthToken, error) {
+	ret := _mock.Called(ctx, session, server)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAPIToken")
+	}
+
+	var r0 domain.AuthToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.AuthToken, domain.ConfigServer) (domain.AuthToken, error)); ok {
+		return returnFunc(ctx, session, server)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.AuthToken, domain.ConfigServer) domain.AuthToken); ok {
+		r0 = returnFunc(ctx, session, server)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(domain.AuthToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.AuthToken, domain.ConfigServer) error); ok {
+		r1 = returnFunc(ctx, session, server)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRancherClient_CreateAPIToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAPIToken'
+type MockRancherClient_CreateAPIToken_Call struct {
+	*mock.Call
+}
+
+// CreateAPIToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - session domain.AuthToken
+//   - server domain.ConfigServer
+func (_e *MockRancherClient_Expecter) CreateAPIToken(ctx interface{}, session interface{}, server interface{}) *MockRancherClient_CreateAPIToken_Call {
+	return &MockRancherClient_CreateAPIToken_Call{Call: _e.mock.On("CreateAPIToken", ctx, session, server)}
+}
+
+func (_c *MockRancherClient_CreateAPIToken_Call) Run(run func(ctx context.Context, session domain.AuthToken, server domain.ConfigServer)) *MockRancherClient_CreateAPIToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 domain.AuthToken
+		if args[1] != nil {
+			arg1 = args[1].(domain.AuthToken)
+		}
+		var arg2 domain.ConfigServer
+		if args[2] != nil {
+			arg2 = args[2].(domain.ConfigServer)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRancherClient_CreateAPIToken_Call) Return(authToken domain.AuthToken, err error) *MockRancherClient_CreateAPIToken_Call {
+	_c.Call.Return(authToken, err)
+	return _c
+}
+
+func (_c *MockRancherClient_CreateAPIToken_Call) RunAndReturn(run func(ctx context.Context, session domain.AuthToken, server domain.ConfigServer) (domain.AuthToken, error)) *MockRancherClient_CreateAPIToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetKubeconfig provides a mock function for the type MockRancherClient
 func (_mock *MockRancherClient) GetKubeconfig(ctx context.Context, token domain.AuthToken, server domain.ConfigServer, clusterID string) ([]byte, error) {
 	ret := _mock.Called(ctx, token, server, clusterID)
@@ -192,6 +407,150 @@ func (_c *MockRancherClient_GetKubeconfig_Call) RunAndReturn(run func(ctx contex
 	return _c
 }
 
+// GetVersion provides a mock function for the type MockRancherClient
+func (_mock *MockRancherClient) GetVersion(ctx context.Context, server domain.ConfigServer) (domain.ServerVersion, error) {
+	ret := _mock.Called(ctx, server)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetVersion")
+	}
+
+	var r0 domain.ServerVersion
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.ConfigServer) (domain.ServerVersion, error)); ok {
+		return returnFunc(ctx, server)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.ConfigServer) domain.ServerVersion); ok {
+		r0 = returnFunc(ctx, server)
+	} else {
+		r0 = ret.Get(0).(domain.ServerVersion)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.ConfigServer) error); ok {
+		r1 = returnFunc(ctx, server)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRancherClient_GetVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetVersion'
+type MockRancherClient_GetVersion_Call struct {
+	*mock.Call
+}
+
+// GetVersion is a helper method to define mock.On call
+//   - ctx context.Context
+//   - server domain.ConfigServer
+func (_e *MockRancherClient_Expecter) GetVersion(ctx interface{}, server interface{}) *MockRancherClient_GetVersion_Call {
+	return &MockRancherClient_GetVersion_Call{Call: _e.mock.On("GetVersion", ctx, server)}
+}
+
+func (_c *MockRancherClient_GetVersion_Call) Run(run func(ctx context.Context, server domain.ConfigServer)) *MockRancherClient_GetVersion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 domain.ConfigServer
+		if args[1] != nil {
+			arg1 = args[1].(domain.ConfigServer)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRancherClient_GetVersion_Call) Return(serverVersion domain.ServerVersion, err error) *MockRancherClient_GetVersion_Call {
+	_c.Call.Return(serverVersion, err)
+	return _c
+}
+
+func (_c *MockRancherClient_GetVersion_Call) RunAndReturn(run func(ctx context.Context, server domain.ConfigServer) (domain.ServerVersion, error)) *MockRancherClient_GetVersion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// KubeconfigExpiry provides a mock function for the type MockRancherClient
+func (_mock *MockRancherClient) KubeconfigExpiry(ctx context.Context, token domain.AuthToken, server domain.ConfigServer, kubeconfig []byte) (time.Time, error) {
+	ret := _mock.Called(ctx, token, server, kubeconfig)
+
+	if len(ret) == 0 {
+		panic("no return value specified for KubeconfigExpiry")
+	}
+
+	var r0 time.Time
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.AuthToken, domain.ConfigServer, []byte) (time.Time, error)); ok {
+		return returnFunc(ctx, token, server, kubeconfig)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.AuthToken, domain.ConfigServer, []byte) time.Time); ok {
+		r0 = returnFunc(ctx, token, server, kubeconfig)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.AuthToken, domain.ConfigServer, []byte) error); ok {
+		r1 = returnFunc(ctx, token, server, kubeconfig)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRancherClient_KubeconfigExpiry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'KubeconfigExpiry'
+type MockRancherClient_KubeconfigExpiry_Call struct {
+	*mock.Call
+}
+
+// KubeconfigExpiry is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token domain.AuthToken
+//   - server domain.ConfigServer
+//   - kubeconfig []byte
+func (_e *MockRancherClient_Expecter) KubeconfigExpiry(ctx interface{}, token interface{}, server interface{}, kubeconfig interface{}) *MockRancherClient_KubeconfigExpiry_Call {
+	return &MockRancherClient_KubeconfigExpiry_Call{Call: _e.mock.On("KubeconfigExpiry", ctx, token, server, kubeconfig)}
+}
+
+func (_c *MockRancherClient_KubeconfigExpiry_Call) Run(run func(ctx context.Context, token domain.AuthToken, server domain.ConfigServer, kubeconfig []byte)) *MockRancherClient_KubeconfigExpiry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 domain.AuthToken
+		if args[1] != nil {
+			arg1 = args[1].(domain.AuthToken)
+		}
+		var arg2 domain.ConfigServer
+		if args[2] != nil {
+			arg2 = args[2].(domain.ConfigServer)
+		}
+		var arg3 []byte
+		if args[3] != nil {
+			arg3 = args[3].([]byte)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRancherClient_KubeconfigExpiry_Call) Return(time1 time.Time, err error) *MockRancherClient_KubeconfigExpiry_Call {
+	_c.Call.Return(time1, err)
+	return _c
+}
+
+func (_c *MockRancherClient_KubeconfigExpiry_Call) RunAndReturn(run func(ctx context.Context, token domain.AuthToken, server domain.ConfigServer, kubeconfig []byte) (time.Time, error)) *MockRancherClient_KubeconfigExpiry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListClusters provides a mock function for the type MockRancherClient
 func (_mock *MockRancherClient) ListClusters(ctx context.Context, token domain.AuthToken, server domain.ConfigServer) ([]domain.Cluster, error) {
 	ret := _mock.Called(ctx, token, server)
@@ -265,3 +624,244 @@ func (_c *MockRancherClient_ListClusters_Call) RunAndReturn(run func(ctx context
 	_c.Call.Return(run)
 	return _c
 }
+
+// ProxyKubeconfig provides a mock function for the type MockRancherClient
+func (_mock *MockRancherClient) ProxyKubeconfig(ctx context.Context, server domain.ConfigServer, clusterID string, kubeconfig []byte) ([]byte, error) {
+	ret := _mock.Called(ctx, server, clusterID, kubeconfig)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProxyKubeconfig")
+	}
+
+	var r0 []byte
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.ConfigServer, string, []byte) ([]byte, error)); ok {
+		return returnFunc(ctx, server, clusterID, kubeconfig)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.ConfigServer, string, []byte) []byte); ok {
+		r0 = returnFunc(ctx, server, clusterID, kubeconfig)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.ConfigServer, string, []byte) error); ok {
+		r1 = returnFunc(ctx, server, clusterID, kubeconfig)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRancherClient_ProxyKubeconfig_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ProxyKubeconfig'
+type MockRancherClient_ProxyKubeconfig_Call struct {
+	*mock.Call
+}
+
+// ProxyKubeconfig is a helper method to define mock.On call
+//   - ctx context.Context
+//   - server domain.ConfigServer
+//   - clusterID string
+//   - kubeconfig []byte
+func (_e *MockRancherClient_Expecter) ProxyKubeconfig(ctx interface{}, server interface{}, clusterID interface{}, kubeconfig interface{}) *MockRancherClient_ProxyKubeconfig_Call {
+	return &MockRancherClient_ProxyKubeconfig_Call{Call: _e.mock.On("ProxyKubeconfig", ctx, server, clusterID, kubeconfig)}
+}
+
+func (_c *MockRancherClient_ProxyKubeconfig_Call) Run(run func(ctx context.Context, server domain.ConfigServer, clusterID string, kubeconfig []byte)) *MockRancherClient_ProxyKubeconfig_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 domain.ConfigServer
+		if args[1] != nil {
+			arg1 = args[1].(domain.ConfigServer)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 []byte
+		if args[3] != nil {
+			arg3 = args[3].([]byte)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRancherClient_ProxyKubeconfig_Call) Return(bytes []byte, err error) *MockRancherClient_ProxyKubeconfig_Call {
+	_c.Call.Return(bytes, err)
+	return _c
+}
+
+func (_c *MockRancherClient_ProxyKubeconfig_Call) RunAndReturn(run func(ctx context.Context, server domain.ConfigServer, clusterID string, kubeconfig []byte) ([]byte, error)) *MockRancherClient_ProxyKubeconfig_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeToken provides a mock function for the type MockRancherClient
+func (_mock *MockRancherClient) RevokeToken(ctx context.Context, token domain.AuthToken, server domain.ConfigServer) error {
+	ret := _mock.Called(ctx, token, server)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.AuthToken, domain.ConfigServer) error); ok {
+		r0 = returnFunc(ctx, token, server)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRancherClient_RevokeToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeToken'
+type MockRancherClient_RevokeToken_Call struct {
+	*mock.Call
+}
+
+// RevokeToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token domain.AuthToken
+//   - server domain.ConfigServer
+func (_e *MockRancherClient_Expecter) RevokeToken(ctx interface{}, token interface{}, server interface{}) *MockRancherClient_RevokeToken_Call {
+	return &MockRancherClient_RevokeToken_Call{Call: _e.mock.On("RevokeToken", ctx, token, server)}
+}
+
+func (_c *MockRancherClient_RevokeToken_Call) Run(run func(ctx context.Context, token domain.AuthToken, server domain.ConfigServer)) *MockRancherClient_RevokeToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 domain.AuthToken
+		if args[1] != nil {
+			arg1 = args[1].(domain.AuthToken)
+		}
+		var arg2 domain.ConfigServer
+		if args[2] != nil {
+			arg2 = args[2].(domain.ConfigServer)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRancherClient_RevokeToken_Call) Return(err error) *MockRancherClient_RevokeToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRancherClient_RevokeToken_Call) RunAndReturn(run func(ctx context.Context, token domain.AuthToken, server domain.ConfigServer) error) *MockRancherClient_RevokeToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ScopeKubeconfig provides a mock function for the type MockRancherClient
+func (_mock *MockRancherClient) ScopeKubeconfig(ctx context.Context, token domain.AuthToken, server domain.ConfigServer, clusterID string, kubeconfig []byte, ttl time.Duration) ([]byte, time.Time, error) {
+	ret := _mock.Called(ctx, token, server, clusterID, kubeconfig, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScopeKubeconfig")
+	}
+
+	var r0 []byte
+	var r1 time.Time
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.AuthToken, domain.ConfigServer, string, []byte, time.Duration) ([]byte, time.Time, error)); ok {
+		return returnFunc(ctx, token, server, clusterID, kubeconfig, ttl)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.AuthToken, domain.ConfigServer, string, []byte, time.Duration) []byte); ok {
+		r0 = returnFunc(ctx, token, server, clusterID, kubeconfig, ttl)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.AuthToken, domain.ConfigServer, string, []byte, time.Duration) time.Time); ok {
+		r1 = returnFunc(ctx, token, server, clusterID, kubeconfig, ttl)
+	} else {
+		r1 = ret.Get(1).(time.Time)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, domain.AuthToken, domain.ConfigServer, string, []byte, time.Duration) error); ok {
+		r2 = returnFunc(ctx, token, server, clusterID, kubeconfig, ttl)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// MockRancherClient_ScopeKubeconfig_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ScopeKubeconfig'
+type MockRancherClient_ScopeKubeconfig_Call struct {
+	*mock.Call
+}
+
+// ScopeKubeconfig is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token domain.AuthToken
+//   - server domain.ConfigServer
+//   - clusterID string
+//   - kubeconfig []byte
+//   - ttl time.Duration
+func (_e *MockRancherClient_Expecter) ScopeKubeconfig(ctx interface{}, token interface{}, server interface{}, clusterID interface{}, kubeconfig interface{}, ttl interface{}) *MockRancherClient_ScopeKubeconfig_Call {
+	return &MockRancherClient_ScopeKubeconfig_Call{Call: _e.mock.On("ScopeKubeconfig", ctx, token, server, clusterID, kubeconfig, ttl)}
+}
+
+func (_c *MockRancherClient_ScopeKubeconfig_Call) Run(run func(ctx context.Context, token domain.AuthToken, server domain.ConfigServer, clusterID string, kubeconfig []byte, ttl time.Duration)) *MockRancherClient_ScopeKubeconfig_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 domain.AuthToken
+		if args[1] != nil {
+			arg1 = args[1].(domain.AuthToken)
+		}
+		var arg2 domain.ConfigServer
+		if args[2] != nil {
+			arg2 = args[2].(domain.ConfigServer)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		var arg4 []byte
+		if args[4] != nil {
+			arg4 = args[4].([]byte)
+		}
+		var arg5 time.Duration
+		if args[5] != nil {
+			arg5 = args[5].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+			arg5,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRancherClient_ScopeKubeconfig_Call) Return(bytes []byte, time1 time.Time, err error) *MockRancherClient_ScopeKubeconfig_Call {
+	_c.Call.Return(bytes, time1, err)
+	return _c
+}
+
+func (_c *MockRancherClient_ScopeKubeconfig_Call) RunAndReturn(run func(ctx context.Context, token domain.AuthToken, server domain.ConfigServer, clusterID string, kubeconfig []byte, ttl time.Duration) ([]byte, time.Time, error)) *MockRancherClient_ScopeKubeconfig_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -38,28 +38,173 @@ func (_m *MockSyncOrchestrator) EXPECT() *MockSyncOrchestrator_Expecter {
 	return &MockSyncOrchestrator_Expecter{mock: &_m.Mock}
 }
 
-// SyncServers provides a mock function for the type MockSyncOrchestrator
-func (_mock *MockSyncOrchestrator) SyncServers(ctx context.Context, servers []domain.ConfigServer, passwords map[string]string) (*domain.SyncResult, error) {
+// Preauthenticate provides a mock function for the type MockSyncOrchestrator
+func (_mock *MockSyncOrchestrator) Preauthenticate(ctx context.Context, servers []domain.ConfigServer, passwords map[string]string) map[string]error {
 	ret := _mock.Called(ctx, servers, passwords)
 
+	if len(ret) == 0 {
+		panic("no return value specified for Preauthenticate")
+	}
+
+	var r0 map[string]error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []domain.ConfigServer, map[string]string) map[string]error); ok {
+		r0 = returnFunc(ctx, servers, passwords)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]error)
+		}
+	}
+	return r0
+}
+
+// MockSyncOrchestrator_Preauthenticate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Preauthenticate'
+type MockSyncOrchestrator_Preauthenticate_Call struct {
+	*mock.Call
+}
+
+// Preauthenticate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - servers []domain.ConfigServer
+//   - passwords map[string]string
+func (_e *MockSyncOrchestrator_Expecter) Preauthenticate(ctx interface{}, servers interface{}, passwords interface{}) *MockSyncOrchestrator_Preauthenticate_Call {
+	return &MockSyncOrchestrator_Preauthenticate_Call{Call: _e.mock.On("Preauthenticate", ctx, servers, passwords)}
+}
+
+func (_c *MockSyncOrchestrator_Preauthenticate_Call) Run(run func(ctx context.Context, servers []domain.ConfigServer, passwords map[string]string)) *MockSyncOrchestrator_Preauthenticate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []domain.ConfigServer
+		if args[1] != nil {
+			arg1 = args[1].([]domain.ConfigServer)
+		}
+		var arg2 map[string]string
+		if args[2] != nil {
+			arg2 = args[2].(map[string]string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSyncOrchestrator_Preauthenticate_Call) Return(sToV map[string]error) *MockSyncOrchestrator_Preauthenticate_Call {
+	_c.Call.Return(sToV)
+	return _c
+}
+
+func (_c *MockSyncOrchestrator_Preauthenticate_Call) RunAndReturn(run func(ctx context.Context, servers []domain.ConfigServer, passwords map[string]string) map[string]error) *MockSyncOrchestrator_Preauthenticate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SyncClusters provides a mock function for the type MockSyncOrchestrator
+func (_mock *MockSyncOrchestrator) SyncClusters(ctx context.Context, targets []domain.ServerClusters, passwords map[string]string, options domain.SyncOptions) (*domain.SyncResult, error) {
+	ret := _mock.Called(ctx, targets, passwords, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SyncClusters")
+	}
+
+	var r0 *domain.SyncResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []domain.ServerClusters, map[string]string, domain.SyncOptions) (*domain.SyncResult, error)); ok {
+		return returnFunc(ctx, targets, passwords, options)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []domain.ServerClusters, map[string]string, domain.SyncOptions) *domain.SyncResult); ok {
+		r0 = returnFunc(ctx, targets, passwords, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.SyncResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []domain.ServerClusters, map[string]string, domain.SyncOptions) error); ok {
+		r1 = returnFunc(ctx, targets, passwords, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSyncOrchestrator_SyncClusters_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SyncClusters'
+type MockSyncOrchestrator_SyncClusters_Call struct {
+	*mock.Call
+}
+
+// SyncClusters is a helper method to define mock.On call
+//   - ctx context.Context
+//   - targets []domain.ServerClusters
+//   - passwords map[string]string
+//   - options domain.SyncOptions
+func (_e *MockSyncOrchestrator_Expecter) SyncClusters(ctx interface{}, targets interface{}, passwords interface{}, options interface{}) *MockSyncOrchestrator_SyncClusters_Call {
+	return &MockSyncOrchestrator_SyncClusters_Call{Call: _e.mock.On("SyncClusters", ctx, targets, passwords, options)}
+}
+
+func (_c *MockSyncOrchestrator_SyncClusters_Call) Run(run func(ctx context.Context, targets []domain.ServerClusters, passwords map[string]string, options domain.SyncOptions)) *MockSyncOrchestrator_SyncClusters_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []domain.ServerClusters
+		if args[1] != nil {
+			arg1 = args[1].([]domain.ServerClusters)
+		}
+		var arg2 map[string]string
+		if args[2] != nil {
+			arg2 = args[2].(map[string]string)
+		}
+		var arg3 domain.SyncOptions
+		if args[3] != nil {
+			arg3 = args[3].(domain.SyncOptions)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSyncOrchestrator_SyncClusters_Call) Return(syncResult *domain.SyncResult, err error) *MockSyncOrchestrator_SyncClusters_Call {
+	_c.Call.Return(syncResult, err)
+	return _c
+}
+
+func (_c *MockSyncOrchestrator_SyncClusters_Call) RunAndReturn(run func(ctx context.Context, targets []domain.ServerClusters, passwords map[string]string, options domain.SyncOptions) (*domain.SyncResult, error)) *MockSyncOrchestrator_SyncClusters_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SyncServers provides a mock function for the type MockSyncOrchestrator
+func (_mock *MockSyncOrchestrator) SyncServers(ctx context.Context, servers []domain.ConfigServer, passwords map[string]string, options domain.SyncOptions) (*domain.SyncResult, error) {
+	ret := _mock.Called(ctx, servers, passwords, options)
+
 	if len(ret) == 0 {
 		panic("no return value specified for SyncServers")
 	}
 
 	var r0 *domain.SyncResult
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []domain.ConfigServer, map[string]string) (*domain.SyncResult, error)); ok {
-		return returnFunc(ctx, servers, passwords)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []domain.ConfigServer, map[string]string, domain.SyncOptions) (*domain.SyncResult, error)); ok {
+		return returnFunc(ctx, servers, passwords, options)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []domain.ConfigServer, map[string]string) *domain.SyncResult); ok {
-		r0 = returnFunc(ctx, servers, passwords)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []domain.ConfigServer, map[string]string, domain.SyncOptions) *domain.SyncResult); ok {
+		r0 = returnFunc(ctx, servers, passwords, options)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.SyncResult)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, []domain.ConfigServer, map[string]string) error); ok {
-		r1 = returnFunc(ctx, servers, passwords)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []domain.ConfigServer, map[string]string, domain.SyncOptions) error); ok {
+		r1 = returnFunc(ctx, servers, passwords, options)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -75,11 +220,12 @@ type MockSyncOrchestrator_SyncServers_Call struct {
 //   - ctx context.Context
 //   - servers []domain.ConfigServer
 //   - passwords map[string]string
-func (_e *MockSyncOrchestrator_Expecter) SyncServers(ctx interface{}, servers interface{}, passwords interface{}) *MockSyncOrchestrator_SyncServers_Call {
-	return &MockSyncOrchestrator_SyncServers_Call{Call: _e.mock.On("SyncServers", ctx, servers, passwords)}
+//   - options domain.SyncOptions
+func (_e *MockSyncOrchestrator_Expecter) SyncServers(ctx interface{}, servers interface{}, passwords interface{}, options interface{}) *MockSyncOrchestrator_SyncServers_Call {
+	return &MockSyncOrchestrator_SyncServers_Call{Call: _e.mock.On("SyncServers", ctx, servers, passwords, options)}
 }
 
-func (_c *MockSyncOrchestrator_SyncServers_Call) Run(run func(ctx context.Context, servers []domain.ConfigServer, passwords map[string]string)) *MockSyncOrchestrator_SyncServers_Call {
+func (_c *MockSyncOrchestrator_SyncServers_Call) Run(run func(ctx context.Context, servers []domain.ConfigServer, passwords map[string]string, options domain.SyncOptions)) *MockSyncOrchestrator_SyncServers_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -93,10 +239,15 @@ func (_c *MockSyncOrchestrator_SyncServers_Call) Run(run func(ctx context.Contex
 		if args[2] != nil {
 			arg2 = args[2].(map[string]string)
 		}
+		var arg3 domain.SyncOptions
+		if args[3] != nil {
+			arg3 = args[3].(domain.SyncOptions)
+		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
 		)
 	})
 	return _c
@@ -107,7 +258,7 @@ func (_c *MockSyncOrchestrator_SyncServers_Call) Return(syncResult *domain.SyncR
 	return _c
 }
 
-func (_c *MockSyncOrchestrator_SyncServers_Call) RunAndReturn(run func(ctx context.Context, servers []domain.ConfigServer, passwords map[string]string) (*domain.SyncResult, error)) *MockSyncOrchestrator_SyncServers_Call {
+func (_c *MockSyncOrchestrator_SyncServers_Call) RunAndReturn(run func(ctx context.Context, servers []domain.ConfigServer, passwords map[string]string, options domain.SyncOptions) (*domain.SyncResult, error)) *MockSyncOrchestrator_SyncServers_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"cowpoke/internal/domain"
+)
+
+const (
+	// indexFile is the cache index kept next to the fragments.
+	indexFile        = "index.json"
+	indexPermissions = 0o600
+)
+
+// index is the on-disk cache index, keyed by fragment file name.
+type index struct {
+	Entries map[string]domain.CacheEntry `json:"entries"`
+}
+
+// List returns every cached fragment, enriched with metadata from the cache index.
+func (m *Manager) List(ctx context.Context) ([]domain.CacheEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fragments, err := m.fragments()
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := m.loadIndex()
+	if err != nil {
+		m.logger.WarnContext(ctx, "Ignoring unreadable cache index", "error", err)
+		idx = &index{Entries: map[string]domain.CacheEntry{}}
+	}
+
+	entries := make([]domain.CacheEntry, 0, len(fragments))
+	for _, f := range fragments {
+		entry, ok := idx.Entries[f.name]
+		if !ok {
+			entry = domain.CacheEntry{
+				File:         f.name,
+				ServerID:     f.serverID,
+				ClusterName:  strings.TrimSuffix(f.name, "-"+f.serverID+fragmentExt),
+				DownloadedAt: f.modTime,
+			}
+		}
+		entry.Size = f.size
+		entries = append(entries, entry)
+	}
+
+	slices.SortFunc(entries, func(a, b domain.CacheEntry) int {
+		return strings.Compare(a.File, b.File)
+	})
+	return entries, nil
+}
+
+// Record upserts index entries for freshly downloaded fragments.
+func (m *Manager) Record(ctx context.Context, entries []domain.CacheEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	idx, err := m.loadIndex()
+	if err != nil {
+		m.logger.WarnContext(ctx, "Rebuilding unreadable cache index", "error", err)
+		idx = &index{Entries: map[string]domain.CacheEntry{}}
+	}
+
+	for _, entry := range entries {
+		idx.Entries[entry.File] = entry
+	}
+
+	if saveErr := m.saveIndex(idx); saveErr != nil {
+		return saveErr
+	}
+
+	m.logger.DebugContext(ctx, "Updated cache index", "entries", len(entries))
+	return nil
+}
+
+// Dir returns the directory holding the cached fragments.
+func (m *Manager) Dir() string {
+	return m.kubeconfigDir
+}
+
+// forget drops index entries for removed fragments. Callers must hold m.mu.
+func (m *Manager) forget(removed []string) error {
+	if len(removed) == 0 {
+		return nil
+	}
+
+	idx, err := m.loadIndex()
+	if err != nil {
+		return err
+	}
+
+	for _, path := range removed {
+		delete(idx.Entries, filepath.Base(path))
+	}
+	return m.saveIndex(idx)
+}
+
+// loadIndex reads the cache index, returning an empty index if it does not exist.
+func (m *Manager) loadIndex() (*index, error) {
+	idx := &index{Entries: map[string]domain.CacheEntry{}}
+
+	data, err := m.fs.ReadFile(m.indexPath())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return idx, nil
+		}
+		return nil, fmt.Errorf("failed to read cache index: %w", err)
+	}
+
+	if unmarshalErr := json.Unmarshal(data, idx); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse cache index: %w", unmarshalErr)
+	}
+	if idx.Entries == nil {
+		idx.Entries = map[string]domain.CacheEntry{}
+	}
+	return idx, nil
+}
+
+// saveIndex writes the cache index to disk.
+func (m *Manager) saveIndex(idx *index) error {
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache index: %w", err)
+	}
+	if writeErr := m.fs.WriteFile(m.indexPath(), data, indexPermissions); writeErr != nil {
+		return fmt.Errorf("failed to write cache index: %w", writeErr)
+	}
+	return nil
+}
+
+// indexPath returns the path of the cache index file.
+func (m *Manager) indexPath() string {
+	return filepath.Join(m.kubeconfigDir, indexFile)
+}
@@ -0,0 +1,162 @@
+// Package cache manages the directory of cached per-cluster kubeconfig fragments.
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"cowpoke/internal/domain"
+)
+
+const (
+	// fragmentExt is the extension of every kubeconfig fragment written by the orchestrator.
+	fragmentExt = ".yaml"
+	// serverIDLength is the length of the server ID suffix in fragment file names.
+	serverIDLength = 8
+)
+
+// Manager inspects and garbage collects the fragment directory.
+type Manager struct {
+	fs            domain.FileSystemAdapter
+	kubeconfigDir string
+	logger        *slog.Logger
+	clock         domain.Clock
+
+	// mu serializes access to the cache index.
+	mu sync.Mutex
+}
+
+// NewManager creates a new fragment cache manager.
+func NewManager(
+	fs domain.FileSystemAdapter,
+	kubeconfigDir string,
+	clock domain.Clock,
+	logger *slog.Logger,
+) *Manager {
+	return &Manager{
+		fs:            fs,
+		kubeconfigDir: kubeconfigDir,
+		logger:        logger,
+		clock:         clock,
+	}
+}
+
+// fragment describes a single cached kubeconfig file.
+type fragment struct {
+	name     string
+	path     string
+	serverID string
+	modTime  time.Time
+	size     int64
+}
+
+// Clean removes fragments according to the given policy.
+func (m *Manager) Clean(ctx context.Context, policy domain.CleanupPolicy) (*domain.CleanupResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fragments, err := m.fragments()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.CleanupResult{}
+	var errs []error
+	for _, f := range fragments {
+		reason := m.removalReason(f, policy)
+		if reason == "" {
+			result.Kept++
+			continue
+		}
+
+		if removeErr := m.fs.Remove(f.path); removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("failed to remove %s: %w", f.path, removeErr))
+			result.Kept++
+			continue
+		}
+
+		m.logger.DebugContext(ctx, "Removed cached kubeconfig fragment", "path", f.path, "reason", reason)
+		result.Removed = append(result.Removed, f.path)
+	}
+
+	if forgetErr := m.forget(result.Removed); forgetErr != nil {
+		errs = append(errs, forgetErr)
+	}
+
+	if len(result.Removed) > 0 {
+		m.logger.InfoContext(ctx, "Cleaned up kubeconfig fragment cache",
+			"removed", len(result.Removed),
+			"kept", result.Kept)
+	}
+
+	if len(errs) > 0 {
+		return result, fmt.Errorf("failed to clean some fragments: %w", errors.Join(errs...))
+	}
+	return result, nil
+}
+
+// removalReason returns why a fragment should be removed, or an empty string to keep it.
+func (m *Manager) removalReason(f fragment, policy domain.CleanupPolicy) string {
+	switch {
+	case policy.All:
+		return "all"
+	case policy.ConfiguredServerIDs != nil && !slices.Contains(policy.ConfiguredServerIDs, f.serverID):
+		return "server no longer configured"
+	case slices.Contains(policy.SyncedServerIDs, f.serverID) && !slices.Contains(policy.LiveFragments, f.name):
+		return "cluster no longer exists"
+	case policy.MaxAge > 0 && m.clock.Now().Sub(f.modTime) > policy.MaxAge:
+		return "not refreshed within retention period"
+	default:
+		return ""
+	}
+}
+
+// fragments lists the kubeconfig fragments in the cache directory.
+func (m *Manager) fragments() ([]fragment, error) {
+	entries, err := m.fs.ReadDir(m.kubeconfigDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read fragment directory: %w", err)
+	}
+
+	fragments := make([]fragment, 0, len(entries))
+	for _, entry := range entries {
+		serverID, ok := parseServerID(entry.Name())
+		if entry.IsDir() || !ok {
+			continue
+		}
+
+		info, infoErr := entry.Info()
+		if infoErr != nil {
+			continue
+		}
+
+		fragments = append(fragments, fragment{
+			name:     entry.Name(),
+			path:     filepath.Join(m.kubeconfigDir, entry.Name()),
+			serverID: serverID,
+			modTime:  info.ModTime(),
+			size:     info.Size(),
+		})
+	}
+	return fragments, nil
+}
+
+// parseServerID extracts the server ID suffix from a fragment file name ("<cluster>-<serverID>.yaml").
+func parseServerID(name string) (string, bool) {
+	base, found := strings.CutSuffix(name, fragmentExt)
+	if !found || len(base) < serverIDLength+2 || base[len(base)-serverIDLength-1] != '-' {
+		return "", false
+	}
+	return base[len(base)-serverIDLength:], true
+}
@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cowpoke/internal/adapters/filesystem"
+	"cowpoke/internal/domain"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFragment(t *testing.T, dir, name string, age time.Duration) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte("apiVersion: v1\n"), 0o600))
+	modTime := time.Now().Add(-age)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+	return path
+}
+
+func TestManager_Clean(t *testing.T) {
+	tests := []struct {
+		name            string
+		policy          domain.CleanupPolicy
+		expectedRemoved []string
+	}{
+		{
+			name:            "removes fragments of unconfigured servers",
+			policy:          domain.CleanupPolicy{ConfiguredServerIDs: []string{"aaaaaaaa"}},
+			expectedRemoved: []string{"prod-bbbbbbbb.yaml"},
+		},
+		{
+			name: "removes fragments of clusters missing from a successful discovery",
+			policy: domain.CleanupPolicy{
+				SyncedServerIDs: []string{"aaaaaaaa"},
+				LiveFragments:   []string{"prod-aaaaaaaa.yaml"},
+			},
+			expectedRemoved: []string{"old-aaaaaaaa.yaml"},
+		},
+		{
+			name:            "removes fragments older than max age",
+			policy:          domain.CleanupPolicy{MaxAge: 24 * time.Hour},
+			expectedRemoved: []string{"old-aaaaaaaa.yaml"},
+		},
+		{
+			name:            "removes everything",
+			policy:          domain.CleanupPolicy{All: true},
+			expectedRemoved: []string{"old-aaaaaaaa.yaml", "prod-aaaaaaaa.yaml", "prod-bbbbbbbb.yaml"},
+		},
+		{
+			name:            "empty policy keeps everything",
+			policy:          domain.CleanupPolicy{},
+			expectedRemoved: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFragment(t, dir, "prod-aaaaaaaa.yaml", time.Hour)
+			writeFragment(t, dir, "old-aaaaaaaa.yaml", 48*time.Hour)
+			writeFragment(t, dir, "prod-bbbbbbbb.yaml", time.Hour)
+			writeFragment(t, dir, ".fragment-salt", 48*time.Hour)
+
+			manager := NewManager(filesystem.New(), dir, domain.SystemClock{}, testutil.Logger())
+
+			result, err := manager.Clean(context.Background(), tt.policy)
+
+			require.NoError(t, err)
+			var removed []string
+			for _, path := range result.Removed {
+				removed = append(removed, filepath.Base(path))
+			}
+			assert.ElementsMatch(t, tt.expectedRemoved, removed)
+			assert.Equal(t, 3-len(tt.expectedRemoved), result.Kept)
+			assert.FileExists(t, filepath.Join(dir, ".fragment-salt"))
+		})
+	}
+}
+
+func TestManager_Clean_MissingDirectory(t *testing.T) {
+	manager := NewManager(filesystem.New(), filepath.Join(t.TempDir(), "missing"), domain.SystemClock{}, testutil.Logger())
+
+	result, err := manager.Clean(context.Background(), domain.CleanupPolicy{All: true})
+
+	require.NoError(t, err)
+	assert.Empty(t, result.Removed)
+}
+
+func TestParseServerID(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		ok       bool
+	}{
+		{name: "prod-55110d2f.yaml", expected: "55110d2f", ok: true},
+		{name: "multi-dash-name-55110d2f.yaml", expected: "55110d2f", ok: true},
+		{name: "prod.yaml", ok: false},
+		{name: "prod-55110d2f.yml", ok: false},
+		{name: ".fragment-salt", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := parseServerID(tt.name)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, id)
+		})
+	}
+}
+
+func TestManager_RecordListAndClean(t *testing.T) {
+	dir := t.TempDir()
+	writeFragment(t, dir, "prod-aaaaaaaa.yaml", time.Hour)
+	writeFragment(t, dir, "stray-bbbbbbbb.yaml", time.Hour)
+
+	manager := NewManager(filesystem.New(), dir, domain.SystemClock{}, testutil.Logger())
+	ctx := context.Background()
+
+	downloadedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, manager.Record(ctx, []domain.CacheEntry{{
+		File:         "prod-aaaaaaaa.yaml",
+		ServerURL:    "https://rancher.example.com",
+		ServerID:     "aaaaaaaa",
+		ClusterID:    "c-abc",
+		ClusterName:  "prod",
+		DownloadedAt: downloadedAt,
+	}}))
+
+	entries, err := manager.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "https://rancher.example.com", entries[0].ServerURL)
+	assert.Equal(t, "c-abc", entries[0].ClusterID)
+	assert.True(t, downloadedAt.Equal(entries[0].DownloadedAt))
+	assert.Positive(t, entries[0].Size)
+
+	// Fragments missing from the index fall back to what the file name tells us.
+	assert.Equal(t, "stray", entries[1].ClusterName)
+	assert.Equal(t, "bbbbbbbb", entries[1].ServerID)
+
+	_, err = manager.Clean(ctx, domain.CleanupPolicy{All: true})
+	require.NoError(t, err)
+
+	idx, err := manager.loadIndex()
+	require.NoError(t, err)
+	assert.Empty(t, idx.Entries)
+}
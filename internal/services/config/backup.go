@@ -0,0 +1,194 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"cowpoke/internal/domain"
+)
+
+const (
+	// backupTimestamp is the layout of the timestamps naming backups, precise enough to tell apart the
+	// backups of changes made in quick succession, such as adding servers from a file.
+	backupTimestamp = "20060102T150405.000Z"
+
+	backupPrefix = "config-"
+	backupSuffix = ".yaml"
+)
+
+// WithBackups copies the configuration file into dir before every change to it, keeping as many backups
+// as settings.backups allows. Backups are named after the time clock reports.
+func WithBackups(dir string, clock domain.Clock) RepositoryOption {
+	return func(r *Repository) {
+		r.backupDir = dir
+		r.clock = clock
+	}
+}
+
+// backup copies the configuration file into the backup directory before it is replaced with data, then
+// removes the oldest backups beyond those kept. A missing file, or one already holding data, is not
+// backed up.
+func (r *Repository) backup(ctx context.Context, data []byte) error {
+	keep := r.settings().Backups.Retained()
+	if r.backupDir == "" || keep == 0 {
+		return nil
+	}
+
+	current, err := r.fs.ReadFile(r.configPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read configuration file: %w", err)
+	}
+	if bytes.Equal(current, data) {
+		return nil
+	}
+
+	if err := r.fs.MkdirAll(r.backupDir, dirPermissions); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	path := filepath.Join(r.backupDir, backupPrefix+r.clock.Now().UTC().Format(backupTimestamp)+backupSuffix)
+	if err := r.fs.WriteFile(path, current, filePermissions); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	r.logger.DebugContext(ctx, "Configuration backed up", "path", path)
+
+	r.pruneBackups(ctx, keep)
+	return nil
+}
+
+// pruneBackups removes the oldest backups beyond keep. Failures are logged, never fatal.
+func (r *Repository) pruneBackups(ctx context.Context, keep int) {
+	backups, err := r.Backups(ctx)
+	if err != nil {
+		r.logger.WarnContext(ctx, "Failed to list configuration backups", "error", err)
+		return
+	}
+	for _, backup := range backups[min(keep, len(backups)):] {
+		if err := r.fs.Remove(backup.Path); err != nil {
+			r.logger.WarnContext(ctx, "Failed to remove old configuration backup", "path", backup.Path, "error", err)
+			continue
+		}
+		r.logger.DebugContext(ctx, "Removed old configuration backup", "path", backup.Path)
+	}
+}
+
+// Backups lists the backups of the configuration file, newest first.
+func (r *Repository) Backups(_ context.Context) ([]domain.ConfigBackup, error) {
+	if r.backupDir == "" {
+		return nil, errors.New("configuration backups are not available")
+	}
+
+	entries, err := r.fs.ReadDir(r.backupDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	var backups []domain.ConfigBackup
+	for _, entry := range entries {
+		timestamp, ok := strings.CutPrefix(entry.Name(), backupPrefix)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if timestamp, ok = strings.CutSuffix(timestamp, backupSuffix); !ok {
+			continue
+		}
+		taken, err := time.Parse(backupTimestamp, timestamp)
+		if err != nil {
+			continue
+		}
+		backup := domain.ConfigBackup{
+			Timestamp: timestamp,
+			Time:      taken,
+			Path:      filepath.Join(r.backupDir, entry.Name()),
+		}
+		if info, err := entry.Info(); err == nil {
+			backup.Size = info.Size()
+		}
+		backups = append(backups, backup)
+	}
+	slices.SortFunc(backups, func(a, b domain.ConfigBackup) int {
+		return b.Time.Compare(a.Time)
+	})
+	return backups, nil
+}
+
+// RestoreBackup replaces the configuration file with the backup taken at timestamp, or with the only
+// backup whose timestamp starts with it, and loads it. The file it replaces is backed up first, so a
+// restore can itself be undone. A backup that fails to load is not kept.
+func (r *Repository) RestoreBackup(ctx context.Context, timestamp string) (domain.ConfigBackup, error) {
+	backup, err := r.findBackup(ctx, timestamp)
+	if err != nil {
+		return domain.ConfigBackup{}, err
+	}
+
+	data, err := r.fs.ReadFile(backup.Path)
+	if err != nil {
+		return domain.ConfigBackup{}, fmt.Errorf("failed to read backup: %w", err)
+	}
+	previous, err := r.fs.ReadFile(r.configPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return domain.ConfigBackup{}, fmt.Errorf("failed to read configuration file: %w", err)
+	}
+	if err := r.backup(ctx, data); err != nil {
+		return domain.ConfigBackup{}, err
+	}
+
+	if err := r.fs.WriteFile(r.configPath, data, filePermissions); err != nil {
+		return domain.ConfigBackup{}, fmt.Errorf("failed to write configuration file: %w", err)
+	}
+	if loadErr := r.LoadConfig(ctx); loadErr != nil {
+		// Put back the file replaced, which the repository still holds.
+		var rollbackErr error
+		if previous != nil {
+			rollbackErr = r.fs.WriteFile(r.configPath, previous, filePermissions)
+		} else {
+			rollbackErr = r.fs.Remove(r.configPath)
+		}
+		if rollbackErr != nil {
+			r.logger.WarnContext(ctx, "Failed to put back the configuration file", "error", rollbackErr)
+		}
+		return domain.ConfigBackup{}, fmt.Errorf("backup %s cannot be loaded: %w", backup.Timestamp, loadErr)
+	}
+
+	r.logger.InfoContext(ctx, "Configuration restored from backup", "path", r.configPath, "backup", backup.Path)
+	return backup, nil
+}
+
+// findBackup returns the backup taken at timestamp, or the only one whose timestamp starts with it.
+func (r *Repository) findBackup(ctx context.Context, timestamp string) (domain.ConfigBackup, error) {
+	backups, err := r.Backups(ctx)
+	if err != nil {
+		return domain.ConfigBackup{}, err
+	}
+
+	var matches []domain.ConfigBackup
+	for _, backup := range backups {
+		if backup.Timestamp == timestamp {
+			return backup, nil
+		}
+		if timestamp != "" && strings.HasPrefix(backup.Timestamp, timestamp) {
+			matches = append(matches, backup)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return domain.ConfigBackup{}, fmt.Errorf("no configuration backup taken at %s", timestamp)
+	case 1:
+		return matches[0], nil
+	default:
+		return domain.ConfigBackup{}, fmt.Errorf("%d configuration backups start with %s; give more of the timestamp",
+			len(matches), timestamp)
+	}
+}
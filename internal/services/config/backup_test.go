@@ -0,0 +1,141 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cowpoke/internal/adapters/filesystem"
+	"cowpoke/internal/domain"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_BacksUpAndRestores(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	clock := testutil.NewClock(time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC))
+	repo, err := NewRepository(filesystem.New(), configPath, testutil.Logger(),
+		WithBackups(filepath.Join(dir, "backups"), clock))
+	require.NoError(t, err)
+
+	prod := domain.ConfigServer{URL: "https://prod.example.com", Username: "admin", AuthType: "local"}
+	staging := domain.ConfigServer{URL: "https://staging.example.com", Username: "admin", AuthType: "local"}
+	ctx := context.Background()
+	require.NoError(t, repo.AddServer(ctx, prod))
+	clock.Advance(time.Second)
+	require.NoError(t, repo.AddServer(ctx, staging))
+	clock.Advance(time.Second)
+	require.NoError(t, repo.RemoveServer(ctx, prod.URL))
+
+	// Act
+	backups, listErr := repo.Backups(ctx)
+	clock.Advance(time.Second)
+	restored, restoreErr := repo.RestoreBackup(ctx, "20261015T093002")
+
+	// Assert
+	require.NoError(t, listErr)
+	require.Len(t, backups, 2, "the first save had no file to back up")
+	assert.Equal(t, "20261015T093002.000Z", backups[0].Timestamp)
+	assert.Equal(t, "20261015T093001.000Z", backups[1].Timestamp)
+	require.NoError(t, restoreErr)
+	assert.Equal(t, backups[0], restored)
+	servers, err := repo.GetServers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ConfigServer{prod, staging}, servers)
+
+	backups, err = repo.Backups(ctx)
+	require.NoError(t, err)
+	require.Len(t, backups, 3, "the restore backed up the file it replaced")
+	data, err := os.ReadFile(backups[0].Path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), prod.URL)
+}
+
+func TestRepository_PrunesBackups(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	config := "version: \"3.0\"\nservers: []\nsettings:\n  backups:\n    keep: 2\n"
+	require.NoError(t, os.WriteFile(configPath, []byte(config), 0o600))
+	clock := testutil.NewClock(time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC))
+	repo, err := NewRepository(filesystem.New(), configPath, testutil.Logger(),
+		WithBackups(filepath.Join(dir, "backups"), clock))
+	require.NoError(t, err)
+
+	// Act
+	for _, host := range []string{"a", "b", "c"} {
+		clock.Advance(time.Second)
+		require.NoError(t, repo.AddServer(context.Background(),
+			domain.ConfigServer{URL: "https://" + host + ".example.com", Username: "admin", AuthType: "local"}))
+	}
+
+	// Assert
+	backups, err := repo.Backups(context.Background())
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, "20261015T093003.000Z", backups[0].Timestamp)
+	assert.Equal(t, "20261015T093002.000Z", backups[1].Timestamp)
+}
+
+func TestRepository_RestoreBackup_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		timestamp string
+		backups   map[string]string
+		wantErr   string
+	}{
+		{name: "unknown", timestamp: "2025", wantErr: "no configuration backup"},
+		{
+			name:      "ambiguous",
+			timestamp: "20261015",
+			backups: map[string]string{
+				"config-20261015T093001.000Z.yaml": "version: \"3.0\"\nservers: []\n",
+				"config-20261015T093002.000Z.yaml": "version: \"3.0\"\nservers: []\n",
+			},
+			wantErr: "2 configuration backups",
+		},
+		{
+			name:      "invalid",
+			timestamp: "20261015T093001",
+			backups:   map[string]string{"config-20261015T093001.000Z.yaml": "servers: [\n"},
+			wantErr:   "cannot be loaded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			dir := t.TempDir()
+			backupDir := filepath.Join(dir, "backups")
+			require.NoError(t, os.MkdirAll(backupDir, 0o700))
+			for name, content := range tt.backups {
+				require.NoError(t, os.WriteFile(filepath.Join(backupDir, name), []byte(content), 0o600))
+			}
+			configPath := filepath.Join(dir, "config.yaml")
+			original := []byte("version: \"3.0\"\nservers:\n  - url: https://prod.example.com\n" +
+				"    username: admin\n    authType: local\n")
+			require.NoError(t, os.WriteFile(configPath, original, 0o600))
+			repo, err := NewRepository(filesystem.New(), configPath, testutil.Logger(),
+				WithBackups(backupDir, testutil.NewClock(time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC))))
+			require.NoError(t, err)
+
+			// Act
+			_, err = repo.RestoreBackup(context.Background(), tt.timestamp)
+
+			// Assert
+			require.ErrorContains(t, err, tt.wantErr)
+			data, readErr := os.ReadFile(configPath)
+			require.NoError(t, readErr)
+			assert.Equal(t, original, data)
+			servers, serversErr := repo.GetServers(context.Background())
+			require.NoError(t, serversErr)
+			assert.Len(t, servers, 1)
+		})
+	}
+}
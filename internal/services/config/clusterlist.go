@@ -0,0 +1,49 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+
+	"cowpoke/internal/domain"
+)
+
+// clusterList is the schema of a cluster list file.
+type clusterList struct {
+	Clusters []domain.ClusterRef `yaml:"clusters"`
+}
+
+// ReadClusterList reads a cluster list file naming the clusters to sync without discovery, such as:
+//
+//	clusters:
+//	  - server: https://rancher.example.com
+//	    clusterId: c-m-abc123
+//	    name: production
+//
+// Every entry needs a server and a cluster ID; unknown fields are rejected.
+func ReadClusterList(fs domain.FileSystemAdapter, path string) ([]domain.ClusterRef, error) {
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cluster list: %w", err)
+	}
+
+	var list clusterList
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if decodeErr := decoder.Decode(&list); decodeErr != nil && !errors.Is(decodeErr, io.EOF) {
+		return nil, fmt.Errorf("failed to parse cluster list %s: %w", path, decodeErr)
+	}
+
+	if len(list.Clusters) == 0 {
+		return nil, fmt.Errorf("cluster list %s contains no clusters", path)
+	}
+	for i, cluster := range list.Clusters {
+		if cluster.Server == "" || cluster.ClusterID == "" {
+			return nil, fmt.Errorf("cluster list %s: entry %d needs both server and clusterId", path, i+1)
+		}
+	}
+	return list.Clusters, nil
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"testing"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadClusterList(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []domain.ClusterRef
+		errMsg   string
+	}{
+		{
+			name: "valid list",
+			content: `clusters:
+  - server: https://rancher.example.com
+    clusterId: c-m-abc123
+    name: production
+  - server: 55110d2f
+    clusterId: local
+`,
+			expected: []domain.ClusterRef{
+				{Server: "https://rancher.example.com", ClusterID: "c-m-abc123", Name: "production"},
+				{Server: "55110d2f", ClusterID: "local"},
+			},
+		},
+		{
+			name:    "missing cluster ID",
+			content: "clusters:\n  - server: https://rancher.example.com\n",
+			errMsg:  "entry 1 needs both server and clusterId",
+		},
+		{
+			name:    "unknown field",
+			content: "clusters:\n  - server: https://rancher.example.com\n    cluster: c-1\n",
+			errMsg:  "field cluster not found",
+		},
+		{
+			name:    "empty",
+			content: "",
+			errMsg:  "contains no clusters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockFS := mocks.NewMockFileSystemAdapter(t)
+			mockFS.On("ReadFile", "/clusters.yaml").Return([]byte(tt.content), nil)
+
+			// Act
+			clusters, err := ReadClusterList(mockFS, "/clusters.yaml")
+
+			// Assert
+			if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, clusters)
+		})
+	}
+}
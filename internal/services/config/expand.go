@@ -0,0 +1,49 @@
+package config
+
+import (
+	"os"
+	"regexp"
+	"slices"
+
+	"cowpoke/internal/domain"
+)
+
+// envReference matches ${VAR} references in configuration values.
+//
+//nolint:gochecknoglobals // Compiled once
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv replaces ${VAR} references in value with the environment variable's value. References to
+// unset variables are left untouched and their names are returned so callers can report them.
+func expandEnv(value string) (string, []string) {
+	var missing []string
+	expanded := envReference.ReplaceAllStringFunc(value, func(reference string) string {
+		name := envReference.FindStringSubmatch(reference)[1]
+		if resolved, ok := os.LookupEnv(name); ok {
+			return resolved
+		}
+		if !slices.Contains(missing, name) {
+			missing = append(missing, name)
+		}
+		return reference
+	})
+	return expanded, missing
+}
+
+// expandServer returns server with environment references in its fields expanded, along with the names
+// of any unset variables it refers to.
+func expandServer(server domain.ConfigServer) (domain.ConfigServer, []string) {
+	var missing []string
+	fields := []*string{&server.URL, &server.Username, &server.AuthType, &server.ClientCert, &server.ClientKey,
+		&server.Tunnel}
+	for _, field := range fields {
+		var unset []string
+		*field, unset = expandEnv(*field)
+		for _, name := range unset {
+			if !slices.Contains(missing, name) {
+				missing = append(missing, name)
+			}
+		}
+	}
+	return server, missing
+}
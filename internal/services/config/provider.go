@@ -7,20 +7,77 @@ import (
 	"cowpoke/internal/domain"
 )
 
+// systemConfigPath is where platform teams distribute a shared server inventory.
+const systemConfigPath = "/etc/cowpoke/config.yaml"
+
+// profilesDir holds the cache and state of isolated profiles, one directory per profile.
+const profilesDir = "profiles"
+
+// separateKubeconfigName is the file in ~/.kube that cowpoke writes instead of ~/.kube/config when it keeps
+// its contexts separate.
+const separateKubeconfigName = "cowpoke.config"
+
 // Provider provides configuration paths.
 type Provider struct {
-	fs domain.FileSystemAdapter
+	fs        domain.FileSystemAdapter
+	stateRoot string
+	namespace string
+	separate  bool
+}
+
+// ProviderOption is a functional option for configuring the Provider.
+type ProviderOption func(*Provider)
+
+// WithStateRoot keeps the kubeconfig cache and runtime state under dir instead of next to the
+// configuration file.
+func WithStateRoot(dir string) ProviderOption {
+	return func(p *Provider) {
+		p.stateRoot = dir
+	}
+}
+
+// WithNamespace gives the kubeconfig cache and runtime state their own directories for namespace, such as
+// an isolated profile. An empty namespace shares the common ones.
+func WithNamespace(namespace string) ProviderOption {
+	return func(p *Provider) {
+		p.namespace = namespace
+	}
+}
+
+// WithSeparateKubeconfig makes ~/.kube/cowpoke.config the default kubeconfig instead of ~/.kube/config, for
+// users who combine the two through KUBECONFIG rather than let cowpoke write the file kubectl reads.
+func WithSeparateKubeconfig(separate bool) ProviderOption {
+	return func(p *Provider) {
+		p.separate = separate
+	}
 }
 
 // NewProvider creates a new configuration provider.
-func NewProvider(fs domain.FileSystemAdapter) *Provider {
-	return &Provider{
+func NewProvider(fs domain.FileSystemAdapter, opts ...ProviderOption) *Provider {
+	p := &Provider{
 		fs: fs,
 	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
-// GetDefaultKubeconfigPath returns the default kubeconfig path.
+// GetDefaultKubeconfigPath returns the default kubeconfig path: kubectl's own, or ~/.kube/cowpoke.config
+// if cowpoke keeps its contexts separate.
 func (p *Provider) GetDefaultKubeconfigPath() (string, error) {
+	if !p.separate {
+		return p.GetKubectlKubeconfigPath()
+	}
+	homeDir, err := p.fs.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".kube", separateKubeconfigName), nil
+}
+
+// GetKubectlKubeconfigPath returns the kubeconfig kubectl reads when KUBECONFIG is not set.
+func (p *Provider) GetKubectlKubeconfigPath() (string, error) {
 	homeDir, err := p.fs.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("failed to get home directory: %w", err)
@@ -30,11 +87,11 @@ func (p *Provider) GetDefaultKubeconfigPath() (string, error) {
 
 // GetKubeconfigDir returns the directory for storing individual kubeconfigs.
 func (p *Provider) GetKubeconfigDir() (string, error) {
-	homeDir, err := p.fs.UserHomeDir()
+	root, err := p.stateDir()
 	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
+		return "", err
 	}
-	return filepath.Join(homeDir, ".config", "cowpoke", "kubeconfigs"), nil
+	return filepath.Join(root, "kubeconfigs"), nil
 }
 
 // GetConfigPath returns the path to the cowpoke configuration file.
@@ -45,3 +102,34 @@ func (p *Provider) GetConfigPath() (string, error) {
 	}
 	return filepath.Join(homeDir, ".config", "cowpoke", "config.yaml"), nil
 }
+
+// GetStateDir returns the directory for cowpoke's persisted runtime state.
+func (p *Provider) GetStateDir() (string, error) {
+	root, err := p.stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "state"), nil
+}
+
+// stateDir returns the directory holding the kubeconfig cache and runtime state directories: the state
+// root, defaulting to the configuration directory, or the namespace's directory within it.
+func (p *Provider) stateDir() (string, error) {
+	root := p.stateRoot
+	if root == "" {
+		homeDir, err := p.fs.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		root = filepath.Join(homeDir, ".config", "cowpoke")
+	}
+	if p.namespace != "" {
+		return filepath.Join(root, profilesDir, p.namespace), nil
+	}
+	return root, nil
+}
+
+// GetSystemConfigPath returns the path to the system-wide configuration layered beneath the user's.
+func (p *Provider) GetSystemConfigPath() string {
+	return systemConfigPath
+}
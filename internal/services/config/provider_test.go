@@ -0,0 +1,99 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"cowpoke/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvider_StateDirs(t *testing.T) {
+	home := filepath.Join("/home", "ci")
+	tests := []struct {
+		name           string
+		opts           []ProviderOption
+		wantKubeconfig string
+		wantState      string
+	}{
+		{
+			name:           "default",
+			wantKubeconfig: filepath.Join(home, ".config", "cowpoke", "kubeconfigs"),
+			wantState:      filepath.Join(home, ".config", "cowpoke", "state"),
+		},
+		{
+			name:           "state root",
+			opts:           []ProviderOption{WithStateRoot("/srv/cowpoke")},
+			wantKubeconfig: filepath.Join("/srv/cowpoke", "kubeconfigs"),
+			wantState:      filepath.Join("/srv/cowpoke", "state"),
+		},
+		{
+			name:           "namespace",
+			opts:           []ProviderOption{WithNamespace("team-a")},
+			wantKubeconfig: filepath.Join(home, ".config", "cowpoke", "profiles", "team-a", "kubeconfigs"),
+			wantState:      filepath.Join(home, ".config", "cowpoke", "profiles", "team-a", "state"),
+		},
+		{
+			name:           "namespace under state root",
+			opts:           []ProviderOption{WithStateRoot("/srv/cowpoke"), WithNamespace("team-a")},
+			wantKubeconfig: filepath.Join("/srv/cowpoke", "profiles", "team-a", "kubeconfigs"),
+			wantState:      filepath.Join("/srv/cowpoke", "profiles", "team-a", "state"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			fs := mocks.NewMockFileSystemAdapter(t)
+			fs.On("UserHomeDir").Return(home, nil).Maybe()
+			provider := NewProvider(fs, tt.opts...)
+
+			// Act
+			kubeconfigDir, kubeconfigErr := provider.GetKubeconfigDir()
+			stateDir, stateErr := provider.GetStateDir()
+
+			// Assert
+			require.NoError(t, kubeconfigErr)
+			require.NoError(t, stateErr)
+			assert.Equal(t, tt.wantKubeconfig, kubeconfigDir)
+			assert.Equal(t, tt.wantState, stateDir)
+		})
+	}
+}
+
+func TestProvider_DefaultKubeconfigPath(t *testing.T) {
+	home := filepath.Join("/home", "ci")
+	tests := []struct {
+		name string
+		opts []ProviderOption
+		want string
+	}{
+		{name: "default", want: filepath.Join(home, ".kube", "config")},
+		{
+			name: "separate",
+			opts: []ProviderOption{WithSeparateKubeconfig(true)},
+			want: filepath.Join(home, ".kube", "cowpoke.config"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			fs := mocks.NewMockFileSystemAdapter(t)
+			fs.On("UserHomeDir").Return(home, nil)
+			provider := NewProvider(fs, tt.opts...)
+
+			// Act
+			kubeconfig, kubeconfigErr := provider.GetDefaultKubeconfigPath()
+			kubectl, kubectlErr := provider.GetKubectlKubeconfigPath()
+
+			// Assert
+			require.NoError(t, kubeconfigErr)
+			require.NoError(t, kubectlErr)
+			assert.Equal(t, tt.want, kubeconfig)
+			assert.Equal(t, filepath.Join(home, ".kube", "config"), kubectl)
+		})
+	}
+}
@@ -1,10 +1,14 @@
 package config
 
 import (
+	"bytes"
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
@@ -19,22 +23,73 @@ import (
 const (
 	dirPermissions  = 0o700 // Owner-only access for security
 	filePermissions = 0o600 // Read/write owner only
-	configVersion   = "2.0" // Current configuration version
+	configVersion   = "3.0" // Current configuration version
 )
 
+// ErrUnknownFields indicates the configuration contains keys that are not part of the schema.
+var ErrUnknownFields = errors.New("unknown configuration fields")
+
 // Repository handles configuration persistence.
 type Repository struct {
 	fs         domain.FileSystemAdapter
 	configPath string
 	config     *Config
+	systemPath string
+	system     *Config
 	migrator   migrations.ConfigMigrator
+	strict     bool
+	profile    string
 	logger     *slog.Logger
+
+	// encryptor decrypts an encrypted configuration file; encrypted records that it is saved encrypted.
+	encryptor domain.Encryptor
+	encrypted bool
+
+	// backupDir, if set, receives a copy of the configuration file, named after the time clock reports,
+	// before every change to it.
+	backupDir string
+	clock     domain.Clock
 }
 
-// Config represents the cowpoke configuration structure.
+// RepositoryOption is a functional option for configuring the Repository.
+type RepositoryOption func(*Repository)
+
+// WithStrict rejects configurations containing unknown fields instead of warning about them.
+func WithStrict(strict bool) RepositoryOption {
+	return func(r *Repository) {
+		r.strict = strict
+	}
+}
+
+// WithProfile selects the profile whose servers and defaults the repository operates on.
+// An empty name selects the default profile.
+func WithProfile(name string) RepositoryOption {
+	return func(r *Repository) {
+		r.profile = cmp.Or(name, domain.DefaultProfile)
+	}
+}
+
+// WithEncryptor decrypts an encrypted configuration file on load and keeps it encrypted when saving.
+func WithEncryptor(encryptor domain.Encryptor) RepositoryOption {
+	return func(r *Repository) {
+		r.encryptor = encryptor
+	}
+}
+
+// Config represents the cowpoke configuration structure. The top-level servers and defaults form
+// the default profile.
 type Config struct {
-	Version string                `yaml:"version"`
-	Servers []domain.ConfigServer `yaml:"servers"`
+	Version  string                 `yaml:"version"`
+	Servers  []domain.ConfigServer  `yaml:"servers"`
+	Defaults domain.ProfileDefaults `yaml:"defaults,omitempty"`
+	Profiles map[string]*Profile    `yaml:"profiles,omitempty"`
+	Settings domain.Settings        `yaml:"settings,omitempty"`
+}
+
+// Profile is a named server inventory with its own sync defaults.
+type Profile struct {
+	Servers  []domain.ConfigServer  `yaml:"servers"`
+	Defaults domain.ProfileDefaults `yaml:"defaults,omitempty"`
 }
 
 // NewRepository creates a new configuration repository.
@@ -42,14 +97,19 @@ func NewRepository(
 	fs domain.FileSystemAdapter,
 	configPath string,
 	logger *slog.Logger,
+	opts ...RepositoryOption,
 ) (*Repository, error) {
 	repo := &Repository{
 		fs:         fs,
 		configPath: configPath,
 		config:     &Config{Version: configVersion, Servers: []domain.ConfigServer{}},
 		migrator:   migrations.NewMigrator(logger),
+		profile:    domain.DefaultProfile,
 		logger:     logger,
 	}
+	for _, opt := range opts {
+		opt(repo)
+	}
 
 	configDir := filepath.Dir(configPath)
 	if err := fs.MkdirAll(configDir, dirPermissions); err != nil {
@@ -57,18 +117,152 @@ func NewRepository(
 	}
 
 	if err := repo.LoadConfig(context.Background()); err != nil {
+		// Starting with an empty configuration would overwrite an encrypted one on the next save.
+		if errors.Is(err, ErrUnknownFields) || errors.Is(err, domain.ErrConfigDecryption) {
+			return nil, err
+		}
 		if !errors.Is(err, os.ErrNotExist) {
 			logger.Warn("Failed to load existing config, starting with empty config", "error", err)
 		}
 	}
 
+	if err := repo.loadSystemConfig(context.Background()); err != nil {
+		if errors.Is(err, ErrUnknownFields) {
+			return nil, err
+		}
+		logger.Warn("Failed to load system config, ignoring it", "path", repo.systemPath, "error", err)
+	}
+
 	return repo, nil
 }
 
-// GetServers returns all configured servers.
+// Profile returns the name of the active profile.
+func (r *Repository) Profile() string {
+	return cmp.Or(r.profile, domain.DefaultProfile)
+}
+
+// GetServers returns the servers of the active profile, with ${VAR} references expanded from the
+// environment. The stored configuration keeps the references so that saving never writes expanded values.
+// Servers from the system configuration come first.
 func (r *Repository) GetServers(ctx context.Context) ([]domain.ConfigServer, error) {
-	r.logger.DebugContext(ctx, "Getting servers from config", "count", len(r.config.Servers))
-	return r.config.Servers, nil
+	servers := mergeServers(expandServers(r.systemServers()), expandServers(r.servers()))
+	r.logger.DebugContext(ctx, "Getting servers from config", "profile", r.Profile(), "count", len(servers))
+	return servers, nil
+}
+
+// GetAllServers returns the servers of every profile, without duplicates.
+func (r *Repository) GetAllServers(ctx context.Context) ([]domain.ConfigServer, error) {
+	var stored []domain.ConfigServer
+	if r.system != nil {
+		stored = allServers(r.system)
+	}
+	stored = append(stored, allServers(r.config)...)
+
+	servers := make([]domain.ConfigServer, 0, len(stored))
+	for _, server := range expandServers(stored) {
+		if !slices.ContainsFunc(servers, func(seen domain.ConfigServer) bool { return seen.URL == server.URL }) {
+			servers = append(servers, server)
+		}
+	}
+	r.logger.DebugContext(ctx, "Getting servers from all profiles", "count", len(servers))
+	return servers, nil
+}
+
+// GetDefaults returns the sync defaults of the active profile, with environment references in the
+// output path expanded.
+func (r *Repository) GetDefaults(ctx context.Context) (domain.ProfileDefaults, error) {
+	r.logger.DebugContext(ctx, "Getting profile defaults from config", "profile", r.Profile())
+	defaults := profileDefaults(r.config, r.Profile())
+	if r.system != nil {
+		defaults = mergeDefaults(profileDefaults(r.system, r.Profile()), defaults)
+	}
+	defaults.Output, _ = expandEnv(defaults.Output)
+	return defaults, nil
+}
+
+// servers returns the stored server list of the active profile in the user configuration.
+func (r *Repository) servers() []domain.ConfigServer {
+	return profileServers(r.config, r.Profile())
+}
+
+// profileServers returns the stored server list of the named profile.
+func profileServers(config *Config, name string) []domain.ConfigServer {
+	if name == domain.DefaultProfile {
+		return config.Servers
+	}
+	if profile := config.Profiles[name]; profile != nil {
+		return profile.Servers
+	}
+	return nil
+}
+
+// profileDefaults returns the sync defaults of the named profile.
+func profileDefaults(config *Config, name string) domain.ProfileDefaults {
+	if name == domain.DefaultProfile {
+		return config.Defaults
+	}
+	if profile := config.Profiles[name]; profile != nil {
+		return profile.Defaults
+	}
+	return domain.ProfileDefaults{}
+}
+
+// allServers returns the stored servers of every profile, default profile first.
+func allServers(config *Config) []domain.ConfigServer {
+	servers := slices.Clone(config.Servers)
+	for _, name := range slices.Sorted(maps.Keys(config.Profiles)) {
+		if profile := config.Profiles[name]; profile != nil {
+			servers = append(servers, profile.Servers...)
+		}
+	}
+	return servers
+}
+
+// setServers replaces the stored server list of the active profile, creating the profile if needed.
+func (r *Repository) setServers(servers []domain.ConfigServer) {
+	if r.Profile() == domain.DefaultProfile {
+		r.config.Servers = servers
+		return
+	}
+	if r.config.Profiles == nil {
+		r.config.Profiles = make(map[string]*Profile)
+	}
+	profile := r.config.Profiles[r.Profile()]
+	if profile == nil {
+		profile = &Profile{}
+		r.config.Profiles[r.Profile()] = profile
+	}
+	profile.Servers = servers
+}
+
+// expandServers expands environment references in each of servers.
+func expandServers(stored []domain.ConfigServer) []domain.ConfigServer {
+	servers := make([]domain.ConfigServer, 0, len(stored))
+	for _, server := range stored {
+		expanded, _ := expandServer(server)
+		servers = append(servers, expanded)
+	}
+	return servers
+}
+
+// matchesURL reports whether a stored server refers to serverURL, either literally or once expanded.
+func matchesURL(server domain.ConfigServer, serverURL string) bool {
+	expanded, _ := expandServer(server)
+	return server.URL == serverURL || expanded.URL == serverURL
+}
+
+// GetSettings returns the optional settings section of the configuration.
+func (r *Repository) GetSettings(ctx context.Context) (domain.Settings, error) {
+	r.logger.DebugContext(ctx, "Getting settings from config")
+	return r.settings(), nil
+}
+
+// settings returns the user settings layered over those of the system configuration.
+func (r *Repository) settings() domain.Settings {
+	if r.system == nil {
+		return r.config.Settings
+	}
+	return mergeSettings(r.system.Settings, r.config.Settings)
 }
 
 // AddServer adds a new server to the configuration.
@@ -76,17 +270,24 @@ func (r *Repository) AddServer(ctx context.Context, server domain.ConfigServer)
 	// Normalize URL by removing trailing slashes to prevent API endpoint issues.
 	server.URL = strings.TrimSuffix(server.URL, "/")
 
-	for _, existing := range r.config.Servers {
-		if existing.URL == server.URL {
+	servers := r.servers()
+	for _, existing := range servers {
+		if matchesURL(existing, server.URL) {
 			return fmt.Errorf("server %s already exists in configuration", server.URL)
 		}
 	}
+	if managed := r.systemServer(server.URL); managed != nil && managed.Managed {
+		return fmt.Errorf("%w: server %s is managed by %s", domain.ErrManagedServer, server.URL, r.systemPath)
+	}
 
-	r.config.Servers = append(r.config.Servers, server)
-	r.logger.InfoContext(ctx, "Added server to configuration", "url", server.URL, "id", server.ID())
+	r.setServers(append(slices.Clip(servers), server))
+	r.logger.InfoContext(ctx, "Added server to configuration",
+		"url", server.URL,
+		"id", server.ID(),
+		"profile", r.Profile())
 
 	if err := r.SaveConfig(ctx); err != nil {
-		r.config.Servers = r.config.Servers[:len(r.config.Servers)-1] // Rollback
+		r.setServers(servers) // Rollback
 		return fmt.Errorf("failed to save configuration after adding server: %w", err)
 	}
 
@@ -95,65 +296,156 @@ func (r *Repository) AddServer(ctx context.Context, server domain.ConfigServer)
 
 // RemoveServer removes a server from the configuration.
 func (r *Repository) RemoveServer(ctx context.Context, serverURL string) error {
-	initialLength := len(r.config.Servers)
-	oldServers := slices.Clone(r.config.Servers)
+	oldServers := r.servers()
 
-	r.config.Servers = slices.DeleteFunc(r.config.Servers, func(server domain.ConfigServer) bool {
-		return server.URL == serverURL
+	servers := slices.DeleteFunc(slices.Clone(oldServers), func(server domain.ConfigServer) bool {
+		return matchesURL(server, serverURL)
 	})
 
-	if len(r.config.Servers) == initialLength {
+	if len(servers) == len(oldServers) {
+		if err := r.checkSystemServer(serverURL); err != nil {
+			return err
+		}
 		return fmt.Errorf("server %s not found in configuration", serverURL)
 	}
 
-	r.logger.InfoContext(ctx, "Removed server from configuration", "url", serverURL)
+	r.setServers(servers)
+	r.logger.InfoContext(ctx, "Removed server from configuration", "url", serverURL, "profile", r.Profile())
 
 	if err := r.SaveConfig(ctx); err != nil {
-		r.config.Servers = oldServers // Rollback
+		r.setServers(oldServers) // Rollback
 		return fmt.Errorf("failed to save configuration after removing server: %w", err)
 	}
 
 	return nil
 }
 
-// RemoveServerByID removes a server from the configuration by its ID.
+// RemoveServerByID removes a server from the configuration by its ID, or by a unique prefix of it.
 func (r *Repository) RemoveServerByID(ctx context.Context, serverID string) error {
-	initialLength := len(r.config.Servers)
-	oldServers := slices.Clone(r.config.Servers)
+	oldServers := r.servers()
+
+	server, err := domain.FindServer(mergeServers(expandServers(r.systemServers()), expandServers(oldServers)), serverID)
+	if err != nil {
+		return err
+	}
+	if server != nil {
+		serverID = server.ID()
+	}
 
 	var removedServerURL string
 	// Find the server URL before deletion for logging
-	for _, server := range r.config.Servers {
-		if server.ID() == serverID {
-			removedServerURL = server.URL
+	for _, server := range oldServers {
+		if expanded, _ := expandServer(server); expanded.ID() == serverID {
+			removedServerURL = expanded.URL
 			break
 		}
 	}
 
-	r.config.Servers = slices.DeleteFunc(r.config.Servers, func(server domain.ConfigServer) bool {
-		return server.ID() == serverID
+	servers := slices.DeleteFunc(slices.Clone(oldServers), func(server domain.ConfigServer) bool {
+		expanded, _ := expandServer(server)
+		return expanded.ID() == serverID
 	})
 
-	if len(r.config.Servers) == initialLength {
+	if len(servers) == len(oldServers) {
+		for _, server := range expandServers(r.systemServers()) {
+			if server.ID() == serverID {
+				return r.checkSystemServer(server.URL)
+			}
+		}
 		return fmt.Errorf("server with ID %s not found in configuration", serverID)
 	}
 
-	r.logger.InfoContext(ctx, "Removed server from configuration", "id", serverID, "url", removedServerURL)
+	r.setServers(servers)
+	r.logger.InfoContext(ctx, "Removed server from configuration",
+		"id", serverID,
+		"url", removedServerURL,
+		"profile", r.Profile())
 
 	if err := r.SaveConfig(ctx); err != nil {
-		r.config.Servers = oldServers // Rollback
+		r.setServers(oldServers) // Rollback
 		return fmt.Errorf("failed to save configuration after removing server: %w", err)
 	}
 
 	return nil
 }
 
+// UpdateServerURL changes the URL of a configured server, keeping its position and other settings.
+func (r *Repository) UpdateServerURL(ctx context.Context, fromURL, toURL string) error {
+	fromURL = strings.TrimSuffix(fromURL, "/")
+	toURL = strings.TrimSuffix(toURL, "/")
+
+	if err := r.checkSystemServer(fromURL); err != nil {
+		return err
+	}
+
+	servers := r.servers()
+	index := slices.IndexFunc(servers, func(server domain.ConfigServer) bool {
+		return matchesURL(server, fromURL)
+	})
+	if index < 0 {
+		return fmt.Errorf("server %s not found in configuration", fromURL)
+	}
+	if slices.ContainsFunc(servers, func(server domain.ConfigServer) bool {
+		return matchesURL(server, toURL)
+	}) || r.systemServer(toURL) != nil {
+		return fmt.Errorf("server %s already exists in configuration", toURL)
+	}
+
+	previousURL := servers[index].URL
+	servers[index].URL = toURL
+	r.logger.InfoContext(ctx, "Updated server URL in configuration", "from", fromURL, "to", toURL)
+
+	if err := r.SaveConfig(ctx); err != nil {
+		servers[index].URL = previousURL // Rollback
+		return fmt.Errorf("failed to save configuration after updating server: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateServerAuthType changes how a server authenticates, e.g. to domain.AuthTypeToken once an API token
+// was created for it.
+func (r *Repository) UpdateServerAuthType(ctx context.Context, serverURL, authType string) error {
+	serverURL = strings.TrimSuffix(serverURL, "/")
+
+	if err := r.checkSystemServer(serverURL); err != nil {
+		return err
+	}
+
+	servers := r.servers()
+	index := slices.IndexFunc(servers, func(server domain.ConfigServer) bool {
+		return matchesURL(server, serverURL)
+	})
+	if index < 0 {
+		return fmt.Errorf("server %s not found in configuration", serverURL)
+	}
+
+	previousAuthType := servers[index].AuthType
+	servers[index].AuthType = authType
+	r.logger.InfoContext(ctx, "Updated server auth type in configuration", "url", serverURL, "authType", authType)
+
+	if err := r.SaveConfig(ctx); err != nil {
+		servers[index].AuthType = previousAuthType // Rollback
+		return fmt.Errorf("failed to save configuration after updating server: %w", err)
+	}
+
+	return nil
+}
+
 // SaveConfig saves the current configuration to disk.
 func (r *Repository) SaveConfig(ctx context.Context) error {
 	data, err := yaml.Marshal(r.config)
 	if err != nil {
 		return fmt.Errorf("failed to marshal configuration: %w", err)
 	}
+	if r.encrypted {
+		if data, err = r.encryptor.Encrypt(ctx, data); err != nil {
+			return fmt.Errorf("failed to encrypt configuration: %w", err)
+		}
+	}
+	if err := r.backup(ctx, data); err != nil {
+		return fmt.Errorf("failed to back up configuration: %w", err)
+	}
 
 	if writeErr := r.fs.WriteFile(r.configPath, data, filePermissions); writeErr != nil {
 		return fmt.Errorf("failed to write configuration file: %w", writeErr)
@@ -163,6 +455,28 @@ func (r *Repository) SaveConfig(ctx context.Context) error {
 	return nil
 }
 
+// Encrypted reports whether the configuration file is stored encrypted.
+func (r *Repository) Encrypted() bool {
+	return r.encrypted
+}
+
+// SetEncrypted rewrites the configuration file encrypted or in plain text.
+func (r *Repository) SetEncrypted(ctx context.Context, encrypted bool) error {
+	if encrypted && r.encryptor == nil {
+		return errors.New("configuration encryption is not available")
+	}
+
+	previous := r.encrypted
+	r.encrypted = encrypted
+	if err := r.SaveConfig(ctx); err != nil {
+		r.encrypted = previous
+		return err
+	}
+
+	r.logger.InfoContext(ctx, "Configuration encryption changed", "path", r.configPath, "encrypted", encrypted)
+	return nil
+}
+
 // LoadConfig loads the configuration from disk.
 func (r *Repository) LoadConfig(ctx context.Context) error {
 	data, err := r.fs.ReadFile(r.configPath)
@@ -174,6 +488,13 @@ func (r *Repository) LoadConfig(ctx context.Context) error {
 		return fmt.Errorf("failed to read configuration file: %w", err)
 	}
 
+	if r.encryptor != nil && r.encryptor.IsEncrypted(data) {
+		if data, err = r.encryptor.Decrypt(ctx, data); err != nil {
+			return fmt.Errorf("%w %s: %w", domain.ErrConfigDecryption, r.configPath, err)
+		}
+		r.encrypted = true
+	}
+
 	// Try migration first
 	servers, migrated, migrationErr := r.migrator.Migrate(ctx, data, configVersion)
 	if migrationErr != nil {
@@ -183,7 +504,9 @@ func (r *Repository) LoadConfig(ctx context.Context) error {
 		r.config = &Config{Version: configVersion, Servers: servers}
 
 		// Fix file and directory permissions for security during migration from v1.x
-		if permErr := r.migrator.FixPermissionsPostMigration(ctx, r.configPath, r.fs); permErr != nil {
+		settings, _ := r.GetSettings(ctx)
+		permErr := r.migrator.FixPermissionsPostMigration(ctx, r.configPath, r.fs, settings.Permissions)
+		if permErr != nil {
 			r.logger.WarnContext(ctx, "Failed to fix permissions during migration", "error", permErr)
 		}
 
@@ -198,15 +521,75 @@ func (r *Repository) LoadConfig(ctx context.Context) error {
 	}
 
 	// Load as current version (no migration needed or migration failed)
-	var config Config
-	if unmarshalErr := yaml.Unmarshal(data, &config); unmarshalErr != nil {
-		return fmt.Errorf("failed to unmarshal configuration: %w", unmarshalErr)
+	config, err := r.decode(ctx, r.configPath, data)
+	if err != nil {
+		return err
 	}
 
 	r.config = &config
+	if r.Profile() != domain.DefaultProfile && config.Profiles[r.Profile()] == nil {
+		r.logger.WarnContext(ctx, "Profile not found in configuration", "profile", r.Profile())
+	}
+	for i, server := range r.servers() {
+		if _, missing := expandServer(server); len(missing) > 0 {
+			r.logger.WarnContext(ctx, "Configuration references unset environment variables",
+				"server", i,
+				"variables", missing)
+		}
+	}
+
+	// Version 2.0 configs are valid 3.0 configs without profiles, so only the version changes.
+	if config.Version == "2.0" {
+		r.config.Version = configVersion
+		if saveErr := r.SaveConfig(ctx); saveErr != nil {
+			r.logger.WarnContext(ctx, "Failed to save migrated config", "error", saveErr)
+		}
+		r.logger.InfoContext(ctx, "Configuration migrated", "from", "2.0", "to", configVersion)
+	}
+
 	r.logger.InfoContext(ctx, "Configuration loaded",
 		"path", r.configPath,
-		"version", config.Version,
-		"servers", len(config.Servers))
+		"version", r.config.Version,
+		"profile", r.Profile(),
+		"servers", len(r.servers()))
 	return nil
 }
+
+// decode parses current-version configuration data read from path, rejecting unknown fields. Unless
+// the repository is strict, unknown fields are logged with their positions and otherwise ignored.
+func (r *Repository) decode(ctx context.Context, path string, data []byte) (Config, error) {
+	var config Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	strictErr := decoder.Decode(&config)
+	if strictErr == nil || errors.Is(strictErr, io.EOF) {
+		return config, nil
+	}
+
+	unknown := unknownConfigFields(data)
+	if len(unknown) == 0 {
+		return Config{}, fmt.Errorf("failed to unmarshal configuration: %w", strictErr)
+	}
+
+	if r.strict {
+		locations := make([]string, 0, len(unknown))
+		for _, issue := range unknown {
+			locations = append(locations, fmt.Sprintf("%s:%d:%d: %s", path, issue.Line, issue.Column, issue.Message))
+		}
+		return Config{}, fmt.Errorf("%w:\n%s", ErrUnknownFields, strings.Join(locations, "\n"))
+	}
+
+	for _, issue := range unknown {
+		r.logger.WarnContext(ctx, "Ignoring unknown configuration field",
+			"path", path,
+			"line", issue.Line,
+			"column", issue.Column,
+			"issue", issue.Message)
+	}
+
+	config = Config{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return Config{}, fmt.Errorf("failed to unmarshal configuration: %w", err)
+	}
+	return config, nil
+}
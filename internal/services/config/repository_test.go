@@ -1,15 +1,20 @@
 package config
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
+	"cowpoke/internal/adapters/filesystem"
 	"cowpoke/internal/domain"
 	"cowpoke/internal/migrations"
 	"cowpoke/internal/mocks"
+	"cowpoke/internal/services/encryption"
 	"cowpoke/internal/testutil"
 
 	"github.com/stretchr/testify/assert"
@@ -35,7 +40,7 @@ func TestNewRepository_Success(t *testing.T) {
 	assert.NotNil(t, repo)
 	assert.Equal(t, mockFS, repo.fs)
 	assert.Equal(t, configPath, repo.configPath)
-	assert.Equal(t, "2.0", repo.config.Version)
+	assert.Equal(t, "3.0", repo.config.Version)
 	assert.Empty(t, repo.config.Servers)
 	mockFS.AssertExpectations(t)
 }
@@ -66,7 +71,7 @@ func TestNewRepository_LoadExistingConfig(t *testing.T) {
 	configPath := "/home/user/.cowpoke/config.yaml"
 
 	existingConfig := Config{
-		Version: "2.0",
+		Version: "3.0",
 		Servers: []domain.ConfigServer{
 			{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"},
 		},
@@ -82,7 +87,7 @@ func TestNewRepository_LoadExistingConfig(t *testing.T) {
 	// Assert
 	require.NoError(t, err)
 	assert.NotNil(t, repo)
-	assert.Equal(t, "2.0", repo.config.Version)
+	assert.Equal(t, "3.0", repo.config.Version)
 	assert.Len(t, repo.config.Servers, 1)
 	assert.Equal(t, "https://rancher.example.com", repo.config.Servers[0].URL)
 	mockFS.AssertExpectations(t)
@@ -114,6 +119,56 @@ func TestGetServers_Success(t *testing.T) {
 	assert.Equal(t, "https://rancher2.example.com", servers[1].URL)
 }
 
+func TestGetServers_ExpandsEnvironmentReferences(t *testing.T) {
+	// Arrange
+	t.Setenv("COWPOKE_TEST_RANCHER_HOST", "rancher.example.com")
+	t.Setenv("COWPOKE_TEST_USER", "jdoe")
+	stored := domain.ConfigServer{
+		URL:      "https://${COWPOKE_TEST_RANCHER_HOST}",
+		Username: "${COWPOKE_TEST_USER}@corp",
+		AuthType: "${COWPOKE_TEST_UNSET_AUTH}",
+	}
+	repo := &Repository{
+		fs:     mocks.NewMockFileSystemAdapter(t),
+		logger: testutil.Logger(),
+		config: &Config{Version: "2.0", Servers: []domain.ConfigServer{stored}},
+	}
+
+	// Act
+	servers, err := repo.GetServers(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "https://rancher.example.com", servers[0].URL)
+	assert.Equal(t, "jdoe@corp", servers[0].Username)
+	assert.Equal(t, "${COWPOKE_TEST_UNSET_AUTH}", servers[0].AuthType)
+	assert.Equal(t, stored, repo.config.Servers[0], "stored config should keep references")
+}
+
+func TestRemoveServer_MatchesExpandedURL(t *testing.T) {
+	// Arrange
+	t.Setenv("COWPOKE_TEST_RANCHER_HOST", "rancher.example.com")
+	mockFS := mocks.NewMockFileSystemAdapter(t)
+	templated := domain.ConfigServer{URL: "https://${COWPOKE_TEST_RANCHER_HOST}", Username: "admin", AuthType: "local"}
+	literal := domain.ConfigServer{URL: "https://other.example.com", Username: "admin", AuthType: "local"}
+	repo := &Repository{
+		fs:         mockFS,
+		configPath: "/test/config.yaml",
+		logger:     testutil.Logger(),
+		config:     &Config{Version: "2.0", Servers: []domain.ConfigServer{literal, templated}},
+	}
+
+	mockFS.On("WriteFile", "/test/config.yaml", mock.Anything, os.FileMode(0o600)).Return(nil)
+
+	// Act
+	err := repo.RemoveServer(context.Background(), "https://rancher.example.com")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ConfigServer{literal}, repo.config.Servers)
+}
+
 func TestGetServers_EmptyConfig(t *testing.T) {
 	// Arrange
 	mockFS := mocks.NewMockFileSystemAdapter(t)
@@ -413,6 +468,30 @@ func TestRemoveServerByID_Success(t *testing.T) {
 	mockFS.AssertExpectations(t)
 }
 
+func TestRemoveServerByID_ShortID(t *testing.T) {
+	// Arrange
+	mockFS := mocks.NewMockFileSystemAdapter(t)
+	serverToRemove := domain.ConfigServer{URL: "https://rancher1.example.com", Username: "admin", AuthType: "local"}
+	serverToKeep := domain.ConfigServer{URL: "https://rancher2.example.com", Username: "user", AuthType: "ldap"}
+
+	repo := &Repository{
+		fs:         mockFS,
+		configPath: "/test/config.yaml",
+		logger:     testutil.Logger(),
+		config:     &Config{Version: "2.0", Servers: []domain.ConfigServer{serverToRemove, serverToKeep}},
+	}
+
+	mockFS.On("WriteFile", "/test/config.yaml", mock.Anything, os.FileMode(0o600)).Return(nil)
+
+	// Act
+	err := repo.RemoveServerByID(context.Background(), domain.ShortID(repo.config.Servers, serverToRemove))
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, repo.config.Servers, 1)
+	assert.Equal(t, serverToKeep.URL, repo.config.Servers[0].URL)
+}
+
 func TestRemoveServerByID_NotFound(t *testing.T) {
 	// Arrange
 	mockFS := mocks.NewMockFileSystemAdapter(t)
@@ -475,6 +554,132 @@ func TestRemoveServerByID_SaveError_Rollback(t *testing.T) {
 	mockFS.AssertExpectations(t)
 }
 
+func TestUpdateServerURL_Success(t *testing.T) {
+	// Arrange
+	mockFS := mocks.NewMockFileSystemAdapter(t)
+	first := domain.ConfigServer{URL: "https://old.example.com", Username: "admin", AuthType: "local"}
+	second := domain.ConfigServer{URL: "https://other.example.com", Username: "user", AuthType: "ldap"}
+
+	repo := &Repository{
+		fs:         mockFS,
+		configPath: "/test/config.yaml",
+		logger:     testutil.Logger(),
+		config:     &Config{Version: "2.0", Servers: []domain.ConfigServer{first, second}},
+	}
+
+	mockFS.On("WriteFile", "/test/config.yaml", mock.Anything, os.FileMode(0o600)).Return(nil)
+
+	// Act
+	err := repo.UpdateServerURL(context.Background(), "https://old.example.com/", "https://new.example.com/")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ConfigServer{
+		{URL: "https://new.example.com", Username: "admin", AuthType: "local"},
+		second,
+	}, repo.config.Servers)
+	mockFS.AssertExpectations(t)
+}
+
+func TestUpdateServerURL_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		wantErr string
+	}{
+		{
+			name:    "source not configured",
+			from:    "https://missing.example.com",
+			to:      "https://new.example.com",
+			wantErr: "server https://missing.example.com not found",
+		},
+		{
+			name:    "target already configured",
+			from:    "https://old.example.com",
+			to:      "https://other.example.com",
+			wantErr: "server https://other.example.com already exists",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockFS := mocks.NewMockFileSystemAdapter(t)
+			servers := []domain.ConfigServer{
+				{URL: "https://old.example.com", Username: "admin", AuthType: "local"},
+				{URL: "https://other.example.com", Username: "user", AuthType: "ldap"},
+			}
+			repo := &Repository{
+				fs:         mockFS,
+				configPath: "/test/config.yaml",
+				logger:     testutil.Logger(),
+				config:     &Config{Version: "2.0", Servers: slices.Clone(servers)},
+			}
+
+			// Act
+			err := repo.UpdateServerURL(context.Background(), tt.from, tt.to)
+
+			// Assert
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Equal(t, servers, repo.config.Servers)
+		})
+	}
+}
+
+func TestUpdateServerURL_SaveError_Rollback(t *testing.T) {
+	// Arrange
+	mockFS := mocks.NewMockFileSystemAdapter(t)
+	repo := &Repository{
+		fs:         mockFS,
+		configPath: "/test/config.yaml",
+		logger:     testutil.Logger(),
+		config: &Config{Version: "2.0", Servers: []domain.ConfigServer{
+			{URL: "https://old.example.com", Username: "admin", AuthType: "local"},
+		}},
+	}
+
+	mockFS.On("WriteFile", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("write failure"))
+
+	// Act
+	err := repo.UpdateServerURL(context.Background(), "https://old.example.com", "https://new.example.com")
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to save configuration")
+	assert.Equal(t, "https://old.example.com", repo.config.Servers[0].URL)
+}
+
+func TestUpdateServerAuthType(t *testing.T) {
+	// Arrange
+	mockFS := mocks.NewMockFileSystemAdapter(t)
+	other := domain.ConfigServer{URL: "https://other.example.com", Username: "user", AuthType: "ldap"}
+	repo := &Repository{
+		fs:         mockFS,
+		configPath: "/test/config.yaml",
+		logger:     testutil.Logger(),
+		config: &Config{Version: "2.0", Servers: []domain.ConfigServer{
+			{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"},
+			other,
+		}},
+	}
+
+	mockFS.On("WriteFile", "/test/config.yaml", mock.Anything, os.FileMode(0o600)).Return(nil)
+
+	// Act
+	err := repo.UpdateServerAuthType(context.Background(), "https://rancher.example.com/", domain.AuthTypeToken)
+	missingErr := repo.UpdateServerAuthType(context.Background(), "https://missing.example.com", domain.AuthTypeToken)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ConfigServer{
+		{URL: "https://rancher.example.com", Username: "admin", AuthType: domain.AuthTypeToken},
+		other,
+	}, repo.config.Servers)
+	require.ErrorContains(t, missingErr, "server https://missing.example.com not found")
+}
+
 func TestSaveConfig_Success(t *testing.T) {
 	// Arrange
 	mockFS := mocks.NewMockFileSystemAdapter(t)
@@ -562,7 +767,7 @@ func TestLoadConfig_FileNotExists(t *testing.T) {
 func TestLoadConfig_Success(t *testing.T) {
 	// Arrange
 	mockFS := mocks.NewMockFileSystemAdapter(t)
-	configData := `version: "2.0"
+	configData := `version: "3.0"
 servers:
   - url: "https://rancher.example.com"
     username: "admin"
@@ -586,12 +791,351 @@ servers:
 
 	// Assert
 	require.NoError(t, err)
-	assert.Equal(t, "2.0", repo.config.Version)
+	assert.Equal(t, "3.0", repo.config.Version)
 	assert.Len(t, repo.config.Servers, 1)
 	assert.Equal(t, "https://rancher.example.com", repo.config.Servers[0].URL)
 	mockFS.AssertExpectations(t)
 }
 
+func TestNewRepository_LayersSystemConfig(t *testing.T) {
+	// Arrange
+	mockFS := mocks.NewMockFileSystemAdapter(t)
+	configPath := "/home/user/.config/cowpoke/config.yaml"
+	systemPath := "/etc/cowpoke/config.yaml"
+	userConfig := `version: "3.0"
+servers:
+  - url: "https://shared.example.com"
+    username: "jdoe"
+    authType: "openldap"
+  - url: "https://managed.example.com"
+    username: "someone-else"
+    authType: "local"
+  - url: "https://personal.example.com"
+    username: "me"
+    authType: "local"
+`
+	systemConfig := `version: "3.0"
+servers:
+  - url: "https://managed.example.com"
+    username: "platform"
+    authType: "openldap"
+    managed: true
+  - url: "https://shared.example.com"
+    username: "changeme"
+    authType: "openldap"
+`
+
+	mockFS.On("MkdirAll", mock.Anything, mock.Anything).Return(nil)
+	mockFS.On("ReadFile", configPath).Return([]byte(userConfig), nil)
+	mockFS.On("ReadFile", systemPath).Return([]byte(systemConfig), nil)
+
+	// Act
+	repo, err := NewRepository(mockFS, configPath, testutil.Logger(), WithSystemConfig(systemPath))
+	require.NoError(t, err)
+	servers, err := repo.GetServers(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ConfigServer{
+		{URL: "https://managed.example.com", Username: "platform", AuthType: "openldap", Managed: true},
+		{URL: "https://shared.example.com", Username: "jdoe", AuthType: "openldap"},
+		{URL: "https://personal.example.com", Username: "me", AuthType: "local"},
+	}, servers)
+	mockFS.AssertExpectations(t)
+}
+
+func TestRepository_GetSettings_LayersSystemSettings(t *testing.T) {
+	// Arrange
+	mockFS := mocks.NewMockFileSystemAdapter(t)
+	configPath := "/home/user/.config/cowpoke/config.yaml"
+	systemPath := "/etc/cowpoke/config.yaml"
+	userConfig := `version: "3.0"
+servers: []
+settings:
+  naming:
+    lowercase: true
+  limits:
+    maxContexts: 50
+`
+	systemConfig := `version: "3.0"
+servers: []
+settings:
+  naming:
+    replacement: "_"
+  limits:
+    warnContexts: 100
+    maxContexts: 500
+  updates:
+    check: true
+`
+
+	mockFS.On("MkdirAll", mock.Anything, mock.Anything).Return(nil)
+	mockFS.On("ReadFile", configPath).Return([]byte(userConfig), nil)
+	mockFS.On("ReadFile", systemPath).Return([]byte(systemConfig), nil)
+
+	// Act
+	repo, err := NewRepository(mockFS, configPath, testutil.Logger(), WithSystemConfig(systemPath))
+	require.NoError(t, err)
+	settings, err := repo.GetSettings(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, domain.NamingSettings{Lowercase: true, Replacement: "_"}, settings.Naming)
+	assert.Equal(t, domain.LimitSettings{WarnContexts: 100, MaxContexts: 50}, settings.Limits)
+	assert.True(t, settings.Updates.Check)
+}
+
+func TestSystemConfig_ManagedServersCannotBeChanged(t *testing.T) {
+	managed := domain.ConfigServer{
+		URL: "https://managed.example.com", Username: "platform", AuthType: "local", Managed: true,
+	}
+	unmanaged := domain.ConfigServer{URL: "https://shared.example.com", Username: "platform", AuthType: "local"}
+
+	tests := []struct {
+		name    string
+		change  func(repo *Repository) error
+		managed bool
+	}{
+		{
+			name:    "add",
+			change:  func(repo *Repository) error { return repo.AddServer(context.Background(), managed) },
+			managed: true,
+		},
+		{
+			name:    "remove",
+			change:  func(repo *Repository) error { return repo.RemoveServer(context.Background(), managed.URL) },
+			managed: true,
+		},
+		{
+			name:    "remove by ID",
+			change:  func(repo *Repository) error { return repo.RemoveServerByID(context.Background(), managed.ID()) },
+			managed: true,
+		},
+		{
+			name: "rename",
+			change: func(repo *Repository) error {
+				return repo.UpdateServerURL(context.Background(), managed.URL, "https://new.example.com")
+			},
+			managed: true,
+		},
+		{
+			name:    "remove unmanaged system server",
+			change:  func(repo *Repository) error { return repo.RemoveServer(context.Background(), unmanaged.URL) },
+			managed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			repo := &Repository{
+				fs:         mocks.NewMockFileSystemAdapter(t),
+				configPath: "/test/config.yaml",
+				systemPath: "/etc/cowpoke/config.yaml",
+				logger:     testutil.Logger(),
+				config:     &Config{Version: "3.0"},
+				system:     &Config{Version: "3.0", Servers: []domain.ConfigServer{managed, unmanaged}},
+			}
+
+			// Act
+			err := tt.change(repo)
+
+			// Assert
+			require.Error(t, err)
+			assert.Equal(t, tt.managed, errors.Is(err, domain.ErrManagedServer))
+			assert.Contains(t, err.Error(), "/etc/cowpoke/config.yaml")
+			assert.Empty(t, repo.config.Servers)
+		})
+	}
+}
+
+func TestLoadConfig_MigratesV2(t *testing.T) {
+	// Arrange
+	mockFS := mocks.NewMockFileSystemAdapter(t)
+	configData := `version: "2.0"
+servers:
+  - url: "https://rancher.example.com"
+    username: "admin"
+    authType: "local"
+settings:
+  fragments:
+    encrypt: true`
+
+	logger := testutil.Logger()
+	repo := &Repository{
+		fs:         mockFS,
+		configPath: "/test/config.yaml",
+		logger:     logger,
+		migrator:   migrations.NewMigrator(logger),
+		config:     &Config{Version: "3.0", Servers: []domain.ConfigServer{}},
+	}
+
+	mockFS.On("ReadFile", "/test/config.yaml").Return([]byte(configData), nil)
+	mockFS.On("WriteFile", "/test/config.yaml", mock.MatchedBy(func(data []byte) bool {
+		var saved Config
+		err := yaml.Unmarshal(data, &saved)
+		return err == nil && saved.Version == "3.0" && len(saved.Servers) == 1 && saved.Settings.Fragments.Encrypt
+	}), os.FileMode(0o600)).Return(nil)
+
+	// Act
+	err := repo.LoadConfig(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "3.0", repo.config.Version)
+	assert.Len(t, repo.config.Servers, 1)
+	assert.True(t, repo.config.Settings.Fragments.Encrypt)
+	mockFS.AssertExpectations(t)
+}
+
+func TestProfiles_ScopeServersAndDefaults(t *testing.T) {
+	// Arrange
+	shared := domain.ConfigServer{URL: "https://shared.example.com", Username: "admin", AuthType: "local"}
+	work := domain.ConfigServer{URL: "https://work.example.com", Username: "jdoe", AuthType: "openldap"}
+	config := &Config{
+		Version:  "3.0",
+		Servers:  []domain.ConfigServer{shared},
+		Defaults: domain.ProfileDefaults{Exclude: []string{"^test-"}},
+		Profiles: map[string]*Profile{
+			"work": {
+				Servers:  []domain.ConfigServer{work, shared},
+				Defaults: domain.ProfileDefaults{Output: "/home/user/.kube/work"},
+			},
+		},
+	}
+	defaultRepo := &Repository{fs: mocks.NewMockFileSystemAdapter(t), logger: testutil.Logger(), config: config}
+	workRepo := &Repository{
+		fs:      mocks.NewMockFileSystemAdapter(t),
+		logger:  testutil.Logger(),
+		profile: "work",
+		config:  config,
+	}
+	ctx := context.Background()
+
+	// Act
+	defaultServers, _ := defaultRepo.GetServers(ctx)
+	defaultDefaults, _ := defaultRepo.GetDefaults(ctx)
+	workServers, _ := workRepo.GetServers(ctx)
+	workDefaults, _ := workRepo.GetDefaults(ctx)
+	allServers, err := workRepo.GetAllServers(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, domain.DefaultProfile, defaultRepo.Profile())
+	assert.Equal(t, []domain.ConfigServer{shared}, defaultServers)
+	assert.Equal(t, []string{"^test-"}, defaultDefaults.Exclude)
+	assert.Equal(t, "work", workRepo.Profile())
+	assert.Equal(t, []domain.ConfigServer{work, shared}, workServers)
+	assert.Equal(t, "/home/user/.kube/work", workDefaults.Output)
+	assert.Equal(t, []domain.ConfigServer{shared, work}, allServers)
+}
+
+func TestProfiles_AddServerCreatesProfile(t *testing.T) {
+	// Arrange
+	mockFS := mocks.NewMockFileSystemAdapter(t)
+	existing := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	personal := domain.ConfigServer{URL: "https://home.example.com", Username: "me", AuthType: "local"}
+	repo := &Repository{
+		fs:         mockFS,
+		configPath: "/test/config.yaml",
+		logger:     testutil.Logger(),
+		profile:    "personal",
+		config:     &Config{Version: "3.0", Servers: []domain.ConfigServer{existing}},
+	}
+
+	mockFS.On("WriteFile", "/test/config.yaml", mock.MatchedBy(func(data []byte) bool {
+		var saved Config
+		err := yaml.Unmarshal(data, &saved)
+		return err == nil && len(saved.Servers) == 1 &&
+			saved.Profiles["personal"] != nil && len(saved.Profiles["personal"].Servers) == 1
+	}), os.FileMode(0o600)).Return(nil)
+
+	// Act
+	err := repo.AddServer(context.Background(), personal)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ConfigServer{existing}, repo.config.Servers)
+	assert.Equal(t, []domain.ConfigServer{personal}, repo.config.Profiles["personal"].Servers)
+	mockFS.AssertExpectations(t)
+}
+
+func TestProfiles_RemoveServerRollsBackOnSaveError(t *testing.T) {
+	// Arrange
+	mockFS := mocks.NewMockFileSystemAdapter(t)
+	server := domain.ConfigServer{URL: "https://work.example.com", Username: "jdoe", AuthType: "local"}
+	repo := &Repository{
+		fs:         mockFS,
+		configPath: "/test/config.yaml",
+		logger:     testutil.Logger(),
+		profile:    "work",
+		config: &Config{
+			Version:  "3.0",
+			Profiles: map[string]*Profile{"work": {Servers: []domain.ConfigServer{server}}},
+		},
+	}
+
+	mockFS.On("WriteFile", "/test/config.yaml", mock.Anything, os.FileMode(0o600)).Return(errors.New("disk full"))
+
+	// Act
+	err := repo.RemoveServer(context.Background(), server.URL)
+
+	// Assert
+	require.Error(t, err)
+	assert.Equal(t, []domain.ConfigServer{server}, repo.config.Profiles["work"].Servers)
+}
+
+func TestLoadConfig_UnknownFields(t *testing.T) {
+	configData := `version: "3.0"
+servers:
+  - url: "https://rancher.example.com"
+    usrname: "admin"
+    authType: "local"`
+
+	tests := []struct {
+		name    string
+		strict  bool
+		wantErr bool
+	}{
+		{name: "warns and loads when lenient", strict: false, wantErr: false},
+		{name: "fails when strict", strict: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockFS := mocks.NewMockFileSystemAdapter(t)
+			logger := testutil.Logger()
+			repo := &Repository{
+				fs:         mockFS,
+				configPath: "/test/config.yaml",
+				logger:     logger,
+				migrator:   migrations.NewMigrator(logger),
+				strict:     tt.strict,
+				config:     &Config{Version: "2.0", Servers: []domain.ConfigServer{}},
+			}
+
+			mockFS.On("ReadFile", "/test/config.yaml").Return([]byte(configData), nil)
+
+			// Act
+			err := repo.LoadConfig(context.Background())
+
+			// Assert
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrUnknownFields)
+				assert.Contains(t, err.Error(), "/test/config.yaml:4:5")
+				assert.Contains(t, err.Error(), "usrname")
+				assert.Empty(t, repo.config.Servers)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, repo.config.Servers, 1)
+			assert.Equal(t, "https://rancher.example.com", repo.config.Servers[0].URL)
+			assert.Empty(t, repo.config.Servers[0].Username)
+		})
+	}
+}
+
 func TestLoadConfig_ReadError(t *testing.T) {
 	// Arrange
 	mockFS := mocks.NewMockFileSystemAdapter(t)
@@ -706,3 +1250,47 @@ func TestRepository_ServerIDConsistency(t *testing.T) {
 
 	mockFS.AssertExpectations(t)
 }
+
+func TestRepository_EncryptedConfig(t *testing.T) {
+	// Arrange
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	key := func(b byte) encryption.KeyFunc {
+		return func(_ context.Context, _ bool) ([]byte, error) {
+			return bytes.Repeat([]byte{b}, encryption.KeySize), nil
+		}
+	}
+	server := domain.ConfigServer{URL: "https://rancher.internal.example.com", Username: "admin", AuthType: "local"}
+
+	repo, err := NewRepository(filesystem.New(), configPath, testutil.Logger(),
+		WithEncryptor(encryption.NewCipher(key(1))))
+	require.NoError(t, err)
+	require.NoError(t, repo.AddServer(context.Background(), server))
+
+	// Act
+	encryptErr := repo.SetEncrypted(context.Background(), true)
+
+	// Assert
+	require.NoError(t, encryptErr)
+	assert.True(t, repo.Encrypted())
+	data, readErr := os.ReadFile(configPath)
+	require.NoError(t, readErr)
+	assert.True(t, encryption.IsEncrypted(data))
+	assert.NotContains(t, string(data), "rancher.internal.example.com")
+
+	reloaded, err := NewRepository(filesystem.New(), configPath, testutil.Logger(),
+		WithEncryptor(encryption.NewCipher(key(1))))
+	require.NoError(t, err)
+	assert.True(t, reloaded.Encrypted())
+	servers, err := reloaded.GetServers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ConfigServer{server}, servers)
+
+	_, err = NewRepository(filesystem.New(), configPath, testutil.Logger(),
+		WithEncryptor(encryption.NewCipher(key(2))))
+	require.ErrorIs(t, err, domain.ErrConfigDecryption)
+
+	require.NoError(t, reloaded.SetEncrypted(context.Background(), false))
+	data, readErr = os.ReadFile(configPath)
+	require.NoError(t, readErr)
+	assert.Contains(t, string(data), "rancher.internal.example.com")
+}
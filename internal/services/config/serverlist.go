@@ -0,0 +1,128 @@
+package config
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"cowpoke/internal/domain"
+)
+
+// serverList is the schema of a YAML server list file.
+type serverList struct {
+	Servers []domain.ConfigServer `yaml:"servers"`
+}
+
+// ReadServerList reads a file of servers to add at once. Files ending in .csv hold a header row naming
+// the columns (url, username, authType, tags, alias, clientCert, clientKey, tunnel) with tags separated by
+// semicolons; any other file is YAML in the configuration file's format:
+//
+//	servers:
+//	  - url: https://rancher.example.com
+//	    username: admin
+//	    authType: local
+//	    tags: [prod]
+//
+// Unknown fields and columns are rejected. The entries themselves are validated when they are added.
+func ReadServerList(fs domain.FileSystemAdapter, path string) ([]domain.ConfigServer, error) {
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read server list: %w", err)
+	}
+
+	var servers []domain.ConfigServer
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		servers, err = parseServerCSV(data)
+	} else {
+		servers, err = parseServerYAML(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse server list %s: %w", path, err)
+	}
+
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("server list %s contains no servers", path)
+	}
+	return servers, nil
+}
+
+func parseServerYAML(data []byte) ([]domain.ConfigServer, error) {
+	var list serverList
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&list); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	for _, server := range list.Servers {
+		if server.Managed {
+			return nil, fmt.Errorf("server %s: managed servers can only be defined in the system configuration",
+				server.URL)
+		}
+	}
+	return list.Servers, nil
+}
+
+// serverColumns maps CSV column names, compared case-insensitively, to the server field they fill.
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var serverColumns = map[string]func(*domain.ConfigServer, string){
+	"url":        func(s *domain.ConfigServer, v string) { s.URL = v },
+	"username":   func(s *domain.ConfigServer, v string) { s.Username = v },
+	"authtype":   func(s *domain.ConfigServer, v string) { s.AuthType = v },
+	"clientcert": func(s *domain.ConfigServer, v string) { s.ClientCert = v },
+	"clientkey":  func(s *domain.ConfigServer, v string) { s.ClientKey = v },
+	"tunnel":     func(s *domain.ConfigServer, v string) { s.Tunnel = v },
+	"tags":       func(s *domain.ConfigServer, v string) { s.Tags = splitTags(v) },
+	"alias":      func(s *domain.ConfigServer, v string) { s.Alias = v },
+}
+
+func parseServerCSV(data []byte) ([]domain.ConfigServer, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := records[0]
+	setters := make([]func(*domain.ConfigServer, string), len(header))
+	for i, column := range header {
+		setter, ok := serverColumns[strings.ToLower(strings.TrimSpace(column))]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+		setters[i] = setter
+	}
+
+	servers := make([]domain.ConfigServer, 0, len(records)-1)
+	for _, record := range records[1:] {
+		var server domain.ConfigServer
+		for i, value := range record {
+			setters[i](&server, strings.TrimSpace(value))
+		}
+		servers = append(servers, server)
+	}
+	return servers, nil
+}
+
+// splitTags splits a semicolon-separated tag list, dropping empty tags.
+func splitTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, ";") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
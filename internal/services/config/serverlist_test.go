@@ -0,0 +1,113 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadServerList(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		content  string
+		expected []domain.ConfigServer
+		errMsg   string
+	}{
+		{
+			name: "yaml list",
+			path: "/servers.yaml",
+			content: `servers:
+  - url: https://rancher.example.com
+    username: admin
+    authType: local
+    tags: [prod, eu]
+  - url: https://rancher.corp.com
+    username: jdoe
+`,
+			expected: []domain.ConfigServer{
+				{URL: "https://rancher.example.com", Username: "admin", AuthType: "local", Tags: []string{"prod", "eu"}},
+				{URL: "https://rancher.corp.com", Username: "jdoe"},
+			},
+		},
+		{
+			name: "csv list",
+			path: "/servers.CSV",
+			content: `url,username,authtype,tags
+# onboarding batch
+https://rancher.example.com, admin, local, prod; eu
+https://rancher.corp.com,jdoe,,
+`,
+			expected: []domain.ConfigServer{
+				{URL: "https://rancher.example.com", Username: "admin", AuthType: "local", Tags: []string{"prod", "eu"}},
+				{URL: "https://rancher.corp.com", Username: "jdoe"},
+			},
+		},
+		{
+			name:    "unknown yaml field",
+			path:    "/servers.yaml",
+			content: "servers:\n  - url: https://rancher.example.com\n    user: admin\n",
+			errMsg:  "field user not found",
+		},
+		{
+			name:    "managed yaml entry",
+			path:    "/servers.yaml",
+			content: "servers:\n  - url: https://rancher.example.com\n    managed: true\n",
+			errMsg:  "managed servers can only be defined in the system configuration",
+		},
+		{
+			name:    "unknown csv column",
+			path:    "/servers.csv",
+			content: "url,user\nhttps://rancher.example.com,admin\n",
+			errMsg:  `unknown column "user"`,
+		},
+		{
+			name:    "ragged csv row",
+			path:    "/servers.csv",
+			content: "url,username\nhttps://rancher.example.com\n",
+			errMsg:  "wrong number of fields",
+		},
+		{
+			name:    "header only",
+			path:    "/servers.csv",
+			content: "url,username\n",
+			errMsg:  "contains no servers",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockFS := mocks.NewMockFileSystemAdapter(t)
+			mockFS.On("ReadFile", tt.path).Return([]byte(tt.content), nil)
+
+			// Act
+			servers, err := ReadServerList(mockFS, tt.path)
+
+			// Assert
+			if tt.errMsg != "" {
+				require.ErrorContains(t, err, tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, servers)
+		})
+	}
+}
+
+func TestReadServerList_ReadError(t *testing.T) {
+	// Arrange
+	mockFS := mocks.NewMockFileSystemAdapter(t)
+	mockFS.On("ReadFile", "/servers.yaml").Return(nil, errors.New("permission denied"))
+
+	// Act
+	_, err := ReadServerList(mockFS, "/servers.yaml")
+
+	// Assert
+	require.ErrorContains(t, err, "failed to read server list: permission denied")
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+
+	"cowpoke/internal/domain"
+)
+
+// WithSystemConfig layers the read-only configuration at path beneath the user configuration.
+// A missing file is ignored.
+func WithSystemConfig(path string) RepositoryOption {
+	return func(r *Repository) {
+		r.systemPath = path
+	}
+}
+
+// loadSystemConfig reads the system-wide configuration, which is never migrated or written.
+func (r *Repository) loadSystemConfig(ctx context.Context) error {
+	if r.systemPath == "" {
+		return nil
+	}
+
+	data, err := r.fs.ReadFile(r.systemPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			r.logger.DebugContext(ctx, "System configuration file does not exist", "path", r.systemPath)
+			return nil
+		}
+		return fmt.Errorf("failed to read system configuration file: %w", err)
+	}
+
+	config, err := r.decode(ctx, r.systemPath, data)
+	if err != nil {
+		return fmt.Errorf("failed to load system configuration: %w", err)
+	}
+	r.system = &config
+
+	for _, server := range r.servers() {
+		if managed := r.systemServer(server.URL); managed != nil && managed.Managed {
+			r.logger.WarnContext(ctx, "Ignoring local entry for server managed by the system configuration",
+				"url", server.URL,
+				"system_config", r.systemPath)
+		}
+	}
+
+	r.logger.InfoContext(ctx, "System configuration loaded",
+		"path", r.systemPath,
+		"servers", len(r.systemServers()))
+	return nil
+}
+
+// systemServers returns the system configuration's servers for the active profile.
+func (r *Repository) systemServers() []domain.ConfigServer {
+	if r.system == nil {
+		return nil
+	}
+	return profileServers(r.system, r.Profile())
+}
+
+// systemServer returns the system server of the active profile matching serverURL, or nil.
+func (r *Repository) systemServer(serverURL string) *domain.ConfigServer {
+	for _, server := range r.systemServers() {
+		if matchesURL(server, serverURL) {
+			expanded, _ := expandServer(server)
+			return &expanded
+		}
+	}
+	return nil
+}
+
+// checkSystemServer returns an error if serverURL is defined by the system configuration and so
+// cannot be removed or renamed locally.
+func (r *Repository) checkSystemServer(serverURL string) error {
+	server := r.systemServer(serverURL)
+	switch {
+	case server == nil:
+		return nil
+	case server.Managed:
+		return fmt.Errorf("%w: server %s is managed by %s", domain.ErrManagedServer, server.URL, r.systemPath)
+	default:
+		return fmt.Errorf("server %s is defined in the system configuration %s", server.URL, r.systemPath)
+	}
+}
+
+// mergeServers layers user servers over system servers. A user entry replaces a system server with
+// the same URL unless the system server is managed.
+func mergeServers(system, user []domain.ConfigServer) []domain.ConfigServer {
+	merged := slices.Clone(system)
+	for _, server := range user {
+		index := slices.IndexFunc(merged, func(existing domain.ConfigServer) bool {
+			return existing.URL == server.URL
+		})
+		switch {
+		case index < 0:
+			merged = append(merged, server)
+		case !merged[index].Managed:
+			merged[index] = server
+		}
+	}
+	return merged
+}
+
+// mergeDefaults layers user profile defaults over system ones. Exclusions from both apply.
+func mergeDefaults(system, user domain.ProfileDefaults) domain.ProfileDefaults {
+	return domain.ProfileDefaults{
+		Output:       cmp.Or(user.Output, system.Output),
+		Exclude:      append(slices.Clone(system.Exclude), user.Exclude...),
+		ExcludeTypes: append(slices.Clone(system.ExcludeTypes), user.ExcludeTypes...),
+	}
+}
+
+// mergeSettings layers user settings over system ones field by field: a field the user set wins, and the
+// system value fills every field the user left empty. Sections are merged the same way, while lists are
+// taken whole from one side.
+func mergeSettings(system, user domain.Settings) domain.Settings {
+	merged := user
+	layerFields(reflect.ValueOf(&merged).Elem(), reflect.ValueOf(system))
+	return merged
+}
+
+// layerFields fills the empty fields of the struct user with those of system, descending into nested
+// structs.
+func layerFields(user, system reflect.Value) {
+	for i := range user.NumField() {
+		field := user.Field(i)
+		switch {
+		case !user.Type().Field(i).IsExported():
+		case field.Kind() == reflect.Struct:
+			layerFields(field, system.Field(i))
+		case field.IsZero():
+			field.Set(system.Field(i))
+		}
+	}
+}
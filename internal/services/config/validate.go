@@ -0,0 +1,554 @@
+package config
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/migrations"
+)
+
+// yamlErrorLine extracts the line number from yaml.v3 error messages such as "line 5: cannot unmarshal ...".
+var yamlErrorLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`) //nolint:gochecknoglobals // Compiled once
+
+// Validator checks configuration files against the current schema.
+type Validator struct {
+	logger *slog.Logger
+}
+
+// NewValidator creates a new configuration validator.
+func NewValidator(logger *slog.Logger) *Validator {
+	return &Validator{
+		logger: logger,
+	}
+}
+
+// Validate reports unknown fields, type errors, outdated versions, malformed URLs, unsupported
+// authentication types and duplicate servers, sorted by position.
+func (v *Validator) Validate(ctx context.Context, data []byte) []domain.ConfigIssue {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return yamlIssues(err)
+	}
+	if len(root.Content) == 0 {
+		return []domain.ConfigIssue{{Severity: domain.SeverityError, Message: "configuration is empty"}}
+	}
+	doc := root.Content[0]
+
+	// Legacy configs are checked against the schema they were written for, since migration handles them.
+	schema := reflect.TypeFor[Config]()
+	if version, err := migrations.DetectVersion(data); err == nil && version == "1.0" {
+		schema = reflect.TypeFor[migrations.V1Config]()
+	}
+	issues := unknownFields(doc, schema, "")
+
+	var config Config
+	if err := doc.Decode(&config); err != nil {
+		issues = append(issues, yamlIssues(err)...)
+	}
+
+	issues = append(issues, v.checkVersion(data, doc)...)
+	issues = append(issues, checkServers(config.Servers, mappingValue(doc, "servers"), "servers")...)
+	issues = append(issues, checkProfiles(config.Profiles, mappingValue(doc, "profiles"))...)
+	issues = append(issues, checkSettings(config.Settings, mappingValue(doc, "settings"))...)
+
+	slices.SortStableFunc(issues, func(a, b domain.ConfigIssue) int {
+		return cmp.Or(cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column))
+	})
+
+	v.logger.DebugContext(ctx, "Validated configuration", "issues", len(issues))
+	return issues
+}
+
+// checkVersion flags configs written for an older schema, which are migrated on load.
+func (v *Validator) checkVersion(data []byte, doc *yaml.Node) []domain.ConfigIssue {
+	version, err := migrations.DetectVersion(data)
+	if err != nil || version == configVersion {
+		return nil
+	}
+
+	line, column := doc.Line, doc.Column
+	if node := mappingValue(doc, "version"); node != nil {
+		line, column = node.Line, node.Column
+	}
+	if version == "1.0" || version == "2.0" {
+		return []domain.ConfigIssue{{
+			Line:     line,
+			Column:   column,
+			Severity: domain.SeverityWarning,
+			Message: fmt.Sprintf(
+				"configuration version %s is outdated and will be migrated to %s", version, configVersion),
+		}}
+	}
+	return []domain.ConfigIssue{{
+		Line:     line,
+		Column:   column,
+		Severity: domain.SeverityError,
+		Message:  fmt.Sprintf("unsupported configuration version %q (expected %s)", version, configVersion),
+	}}
+}
+
+// checkServers validates each server entry and flags duplicates.
+func checkServers(servers []domain.ConfigServer, node *yaml.Node, prefix string) []domain.ConfigIssue {
+	var issues []domain.ConfigIssue
+	seenURLs := make(map[string]int)
+	seenIDs := make(map[string]int)
+
+	for i, stored := range servers {
+		entry := sequenceItem(node, i)
+		path := fmt.Sprintf("%s[%d]", prefix, i)
+		at := func(field string) (int, int) {
+			return position(cmp.Or(mappingValue(entry, field), entry))
+		}
+
+		issue := func(field, severity, format string, args ...any) {
+			line, column := at(field)
+			issues = append(issues, domain.ConfigIssue{
+				Line:     line,
+				Column:   column,
+				Severity: severity,
+				Message:  fmt.Sprintf("%s.%s: ", path, field) + fmt.Sprintf(format, args...),
+			})
+		}
+
+		server, missing := expandServer(stored)
+		for _, name := range missing {
+			line, column := position(entry)
+			issues = append(issues, domain.ConfigIssue{
+				Line:     line,
+				Column:   column,
+				Severity: domain.SeverityWarning,
+				Message:  fmt.Sprintf("%s: references unset environment variable %s", path, name),
+			})
+		}
+
+		if server.URL == "" {
+			issue("url", domain.SeverityError, "is required")
+		} else if parsed, err := url.Parse(server.URL); err != nil ||
+			(parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			issue("url", domain.SeverityError, "%q is not a valid http(s) URL", server.URL)
+		} else {
+			normalized := strings.TrimSuffix(server.URL, "/")
+			if first, ok := seenURLs[normalized]; ok {
+				issue("url", domain.SeverityError, "duplicate of servers[%d]", first)
+			} else if first, ok := seenIDs[server.ID()]; ok {
+				issue("url", domain.SeverityError, "has the same host as servers[%d], so both would get server ID %s",
+					first, server.ID())
+			}
+			seenURLs[normalized] = i
+			if _, ok := seenIDs[server.ID()]; !ok {
+				seenIDs[server.ID()] = i
+			}
+		}
+
+		if server.Username == "" {
+			issue("username", domain.SeverityError, "is required")
+		}
+
+		if server.AuthType != domain.AuthTypeToken && !slices.Contains(domain.SupportedAuthTypes(), server.AuthType) {
+			issue("authType", domain.SeverityError, "unsupported authentication type %q (supported: %s)",
+				server.AuthType, strings.Join(domain.SupportedAuthTypes(), ", "))
+		}
+
+		switch {
+		case server.ClientCert != "" && server.ClientKey == "":
+			issue("clientKey", domain.SeverityError, "is required when clientCert is set")
+		case server.ClientKey != "" && server.ClientCert == "":
+			issue("clientCert", domain.SeverityError, "is required when clientKey is set")
+		}
+
+		if server.Tunnel != "" {
+			if _, _, err := domain.ParseTunnel(server.Tunnel); err != nil {
+				issue("tunnel", domain.SeverityError, "%s", err)
+			}
+		}
+
+		if server.Priority < 0 {
+			issue("priority", domain.SeverityError, "must be 1 or more, or omitted to sync the server last")
+		}
+
+		issues = append(issues, checkExcludePatterns(server.Sync.ExcludePatterns,
+			mappingValue(mappingValue(entry, "sync"), "excludePatterns"), path+".sync.excludePatterns")...)
+	}
+	return issues
+}
+
+// checkProfiles validates the servers of each named profile.
+func checkProfiles(profiles map[string]*Profile, node *yaml.Node) []domain.ConfigIssue {
+	var issues []domain.ConfigIssue
+	for _, name := range slices.Sorted(maps.Keys(profiles)) {
+		if name == domain.DefaultProfile {
+			line, column := position(mappingKey(node, name))
+			issues = append(issues, domain.ConfigIssue{
+				Line:     line,
+				Column:   column,
+				Severity: domain.SeverityError,
+				Message: fmt.Sprintf(
+					"profiles.%s: the %s profile is defined by the top-level servers", name, domain.DefaultProfile),
+			})
+			continue
+		}
+		if profiles[name] == nil {
+			continue
+		}
+		prefix := fmt.Sprintf("profiles.%s.servers", name)
+		issues = append(issues,
+			checkServers(profiles[name].Servers, mappingValue(mappingValue(node, name), "servers"), prefix)...)
+	}
+	return issues
+}
+
+// checkSettings validates the optional settings section.
+func checkSettings(settings domain.Settings, node *yaml.Node) []domain.ConfigIssue {
+	var issues []domain.ConfigIssue
+
+	keySource := settings.Fragments.KeySource
+	if keySource != "" && keySource != "passphrase" && keySource != "keychain" {
+		line, column := position(cmp.Or(mappingValue(mappingValue(node, "fragments"), "keySource"), node))
+		issues = append(issues, domain.ConfigIssue{
+			Line:     line,
+			Column:   column,
+			Severity: domain.SeverityError,
+			Message: fmt.Sprintf(
+				"settings.fragments.keySource: unsupported key source %q (use passphrase or keychain)", keySource),
+		})
+	}
+
+	if settings.HTTP.MaxIdleConnsPerHost < 0 {
+		line, column := position(cmp.Or(mappingValue(mappingValue(node, "http"), "maxIdleConnsPerHost"), node))
+		issues = append(issues, domain.ConfigIssue{
+			Line:     line,
+			Column:   column,
+			Severity: domain.SeverityError,
+			Message:  "settings.http.maxIdleConnsPerHost: must not be negative",
+		})
+	}
+
+	naming := settings.Naming
+	if naming.MaxLength < 0 {
+		line, column := position(cmp.Or(mappingValue(mappingValue(node, "naming"), "maxLength"), node))
+		issues = append(issues, domain.ConfigIssue{
+			Line:     line,
+			Column:   column,
+			Severity: domain.SeverityError,
+			Message:  "settings.naming.maxLength: must not be negative",
+		})
+	}
+	if !naming.ValidReplacement() {
+		line, column := position(cmp.Or(mappingValue(mappingValue(node, "naming"), "replacement"), node))
+		issues = append(issues, domain.ConfigIssue{
+			Line:     line,
+			Column:   column,
+			Severity: domain.SeverityWarning,
+			Message: fmt.Sprintf(
+				"settings.naming.replacement: %q is not allowed in names and is ignored in favour of \"-\"",
+				naming.Replacement),
+		})
+	}
+	if !naming.ValidGroupBy() {
+		grouping := mappingValue(mappingValue(node, "naming"), "grouping")
+		line, column := position(cmp.Or(mappingValue(grouping, "by"), node))
+		issues = append(issues, domain.ConfigIssue{
+			Line:     line,
+			Column:   column,
+			Severity: domain.SeverityError,
+			Message: fmt.Sprintf("settings.naming.grouping.by: unsupported grouping %q (use %s or %s)",
+				naming.Grouping.By, domain.GroupByAlias, domain.GroupByTag),
+		})
+	}
+
+	issues = append(issues, checkExcludePatterns(settings.Sync.ExcludePatterns,
+		mappingValue(mappingValue(node, "sync"), "excludePatterns"), "settings.sync.excludePatterns")...)
+	issues = append(issues, checkEndpointRewrites(settings.Endpoints.Rewrites,
+		mappingValue(mappingValue(node, "endpoints"), "rewrites"))...)
+	issues = append(issues, checkPermissions(settings.Permissions, mappingValue(node, "permissions"))...)
+	issues = append(issues, checkTemplates(settings.Templates, mappingValue(node, "templates"))...)
+	hooks := mappingValue(node, "hooks")
+	issues = append(issues, checkHooks(settings.Hooks.PreSync, mappingValue(hooks, "preSync"), "preSync")...)
+	return append(issues, checkHooks(settings.Hooks.PostSync, mappingValue(hooks, "postSync"), "postSync")...)
+}
+
+// checkExcludePatterns reports exclude patterns that are not valid regular expressions.
+func checkExcludePatterns(patterns []string, node *yaml.Node, path string) []domain.ConfigIssue {
+	var issues []domain.ConfigIssue
+	for i, pattern := range patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			line, column := position(cmp.Or(sequenceItem(node, i), node))
+			issues = append(issues, domain.ConfigIssue{
+				Line:     line,
+				Column:   column,
+				Severity: domain.SeverityError,
+				Message:  fmt.Sprintf("%s[%d]: invalid regular expression %q: %v", path, i, pattern, err),
+			})
+		}
+	}
+	return issues
+}
+
+// checkEndpointRewrites reports endpoint rewrites whose pattern is missing or not a valid regular expression.
+func checkEndpointRewrites(rewrites []domain.EndpointRewrite, node *yaml.Node) []domain.ConfigIssue {
+	var issues []domain.ConfigIssue
+	for i, rewrite := range rewrites {
+		item := sequenceItem(node, i)
+		var message string
+		if rewrite.Match == "" {
+			message = "is required"
+		} else if _, err := regexp.Compile(rewrite.Match); err != nil {
+			message = fmt.Sprintf("invalid regular expression %q: %v", rewrite.Match, err)
+		} else {
+			continue
+		}
+		line, column := position(cmp.Or(mappingValue(item, "match"), item, node))
+		issues = append(issues, domain.ConfigIssue{
+			Line:     line,
+			Column:   column,
+			Severity: domain.SeverityError,
+			Message:  fmt.Sprintf("settings.endpoints.rewrites[%d].match: %s", i, message),
+		})
+	}
+	return issues
+}
+
+// checkHooks reports hooks without a command, with a negative timeout or with an unknown failure policy.
+func checkHooks(hooks []domain.Hook, node *yaml.Node, stage string) []domain.ConfigIssue {
+	var issues []domain.ConfigIssue
+	for i, hook := range hooks {
+		entry := sequenceItem(node, i)
+		issue := func(key, message string) {
+			line, column := position(cmp.Or(mappingValue(entry, key), entry, node))
+			issues = append(issues, domain.ConfigIssue{
+				Line:     line,
+				Column:   column,
+				Severity: domain.SeverityError,
+				Message:  fmt.Sprintf("settings.hooks.%s[%d].%s: %s", stage, i, key, message),
+			})
+		}
+
+		if strings.TrimSpace(hook.Command) == "" {
+			issue("command", "is required")
+		}
+		if hook.TimeoutSeconds < 0 {
+			issue("timeoutSeconds", "must not be negative")
+		}
+		if hook.OnFailure != "" && !domain.ValidHookPolicy(hook.OnFailure) {
+			issue("onFailure", fmt.Sprintf("unsupported policy %q (use fail, warn or ignore)", hook.OnFailure))
+		}
+	}
+	return issues
+}
+
+// checkTemplates reports templates without a source or output, and outputs that do not parse as templates.
+func checkTemplates(templates []domain.TemplateSettings, node *yaml.Node) []domain.ConfigIssue {
+	var issues []domain.ConfigIssue
+	for i, settings := range templates {
+		entry := sequenceItem(node, i)
+		issue := func(key, message string) {
+			line, column := position(cmp.Or(mappingValue(entry, key), entry, node))
+			issues = append(issues, domain.ConfigIssue{
+				Line:     line,
+				Column:   column,
+				Severity: domain.SeverityError,
+				Message:  fmt.Sprintf("settings.templates[%d].%s: %s", i, key, message),
+			})
+		}
+
+		if settings.Source == "" {
+			issue("source", "is required")
+		}
+		if settings.Output == "" {
+			issue("output", "is required")
+		} else if _, err := template.New("output").Parse(settings.Output); err != nil {
+			issue("output", err.Error())
+		}
+	}
+	return issues
+}
+
+// checkPermissions reports permission modes that do not parse, file modes that let every user read merged
+// kubeconfigs, and groups unknown on this machine.
+func checkPermissions(permissions domain.PermissionSettings, node *yaml.Node) []domain.ConfigIssue {
+	var issues []domain.ConfigIssue
+	issue := func(key, severity, message string) {
+		line, column := position(cmp.Or(mappingValue(node, key), node))
+		issues = append(issues, domain.ConfigIssue{
+			Line:     line,
+			Column:   column,
+			Severity: severity,
+			Message:  "settings.permissions." + key + ": " + message,
+		})
+	}
+
+	if _, err := domain.ParseMode(permissions.FileMode); permissions.FileMode != "" && err != nil {
+		issue("fileMode", domain.SeverityError, err.Error())
+	} else if permissions.File()&0o004 != 0 {
+		issue("fileMode", domain.SeverityWarning,
+			fmt.Sprintf("%s lets every user read the credentials in merged kubeconfigs", permissions.FileMode))
+	}
+	if _, err := domain.ParseMode(permissions.DirMode); permissions.DirMode != "" && err != nil {
+		issue("dirMode", domain.SeverityError, err.Error())
+	}
+	if _, err := permissions.GroupID(); err != nil {
+		issue("group", domain.SeverityWarning, err.Error())
+	}
+	return issues
+}
+
+// unknownConfigFields reports keys in current-version configuration data that do not match the schema.
+func unknownConfigFields(data []byte) []domain.ConfigIssue {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil || len(root.Content) == 0 {
+		return nil
+	}
+	return unknownFields(root.Content[0], reflect.TypeFor[Config](), "")
+}
+
+// unknownFields walks a YAML node alongside the Go type it decodes into and reports keys that
+// do not correspond to any field.
+func unknownFields(node *yaml.Node, t reflect.Type, path string) []domain.ConfigIssue {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if node == nil {
+		return nil
+	}
+
+	var issues []domain.ConfigIssue
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			field, ok := fields[key.Value]
+			if !ok {
+				issues = append(issues, domain.ConfigIssue{
+					Line:     key.Line,
+					Column:   key.Column,
+					Severity: domain.SeverityError,
+					Message:  fmt.Sprintf("unknown field %q%s", key.Value, inPath(path)),
+				})
+				continue
+			}
+			issues = append(issues, unknownFields(value, field, joinPath(path, key.Value))...)
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			issues = append(issues, unknownFields(node.Content[i+1], t.Elem(), joinPath(path, node.Content[i].Value))...)
+		}
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for i, item := range node.Content {
+			issues = append(issues, unknownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return issues
+}
+
+// yamlFields maps the YAML keys of a struct to their field types.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// yamlIssues converts a yaml.v3 decoding error into issues, extracting line numbers when present.
+func yamlIssues(err error) []domain.ConfigIssue {
+	var messages []string
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		messages = typeErr.Errors
+	} else {
+		messages = []string{err.Error()}
+	}
+
+	issues := make([]domain.ConfigIssue, 0, len(messages))
+	for _, message := range messages {
+		issue := domain.ConfigIssue{Severity: domain.SeverityError, Message: message}
+		if match := yamlErrorLine.FindStringSubmatch(message); match != nil {
+			issue.Line, _ = strconv.Atoi(match[1])
+			issue.Message = match[2]
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// mappingKey returns the key node for key in a mapping node, or nil.
+func mappingKey(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i]
+		}
+	}
+	return nil
+}
+
+// sequenceItem returns the i-th item of a sequence node, or nil.
+func sequenceItem(node *yaml.Node, i int) *yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode || i >= len(node.Content) {
+		return nil
+	}
+	return node.Content[i]
+}
+
+// position returns a node's line and column, or zeros for a nil node.
+func position(node *yaml.Node) (int, int) {
+	if node == nil {
+		return 0, 0
+	}
+	return node.Line, node.Column
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func inPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	return " in " + path
+}
@@ -0,0 +1,398 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidator_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		expected []domain.ConfigIssue
+	}{
+		{
+			name: "valid config",
+			config: `version: "3.0"
+servers:
+  - url: https://rancher.example.com
+    username: admin
+    authType: local
+settings:
+  fragments:
+    encrypt: true
+    keySource: keychain
+`,
+			expected: nil,
+		},
+		{
+			name: "unknown fields are reported at their key",
+			config: `version: "3.0"
+servers:
+  - url: https://rancher.example.com
+    usrname: admin
+    authType: local
+setings: {}
+`,
+			expected: []domain.ConfigIssue{
+				{Line: 3, Column: 5, Severity: domain.SeverityError, Message: "servers[0].username: is required"},
+				{Line: 4, Column: 5, Severity: domain.SeverityError, Message: `unknown field "usrname" in servers[0]`},
+				{Line: 6, Column: 1, Severity: domain.SeverityError, Message: `unknown field "setings"`},
+			},
+		},
+		{
+			name: "invalid url and auth type",
+			config: `version: "3.0"
+servers:
+  - url: rancher.example.com
+    username: admin
+    authType: saml
+`,
+			expected: []domain.ConfigIssue{
+				{
+					Line: 3, Column: 10, Severity: domain.SeverityError,
+					Message: `servers[0].url: "rancher.example.com" is not a valid http(s) URL`,
+				},
+				{
+					Line: 5, Column: 15, Severity: domain.SeverityError,
+					Message: `servers[0].authType: unsupported authentication type "saml" (supported: ` +
+						`local, openldap, activedirectory, github, googleoauth, shibboleth, azuread, keycloak, ping, okta, freeipa)`,
+				},
+			},
+		},
+		{
+			name: "profiles",
+			config: `version: "3.0"
+servers: []
+profiles:
+  default:
+    servers: []
+  work:
+    servers:
+      - url: https://rancher.example.com
+        usrname: jdoe
+        authType: local
+    defaults:
+      output: ~/.kube/work
+`,
+			expected: []domain.ConfigIssue{
+				{
+					Line: 4, Column: 3, Severity: domain.SeverityError,
+					Message: "profiles.default: the default profile is defined by the top-level servers",
+				},
+				{
+					Line: 8, Column: 9, Severity: domain.SeverityError,
+					Message: "profiles.work.servers[0].username: is required",
+				},
+				{
+					Line: 9, Column: 9, Severity: domain.SeverityError,
+					Message: `unknown field "usrname" in profiles.work.servers[0]`,
+				},
+			},
+		},
+		{
+			name: "unset environment references",
+			config: `version: "3.0"
+servers:
+  - url: https://rancher.example.com
+    username: ${COWPOKE_TEST_UNSET_USER}
+    authType: local
+`,
+			expected: []domain.ConfigIssue{
+				{
+					Line: 3, Column: 5, Severity: domain.SeverityWarning,
+					Message: "servers[0]: references unset environment variable COWPOKE_TEST_UNSET_USER",
+				},
+			},
+		},
+		{
+			name: "duplicate servers",
+			config: `version: "3.0"
+servers:
+  - url: https://rancher.example.com
+    username: admin
+    authType: local
+  - url: https://rancher.example.com/
+    username: other
+    authType: local
+  - url: https://rancher.example.com/alt
+    username: admin
+    authType: local
+`,
+			expected: []domain.ConfigIssue{
+				{Line: 6, Column: 10, Severity: domain.SeverityError, Message: "servers[1].url: duplicate of servers[0]"},
+				{
+					Line: 9, Column: 10, Severity: domain.SeverityError,
+					Message: "servers[2].url: has the same host as servers[0], so both would get server ID " +
+						(&domain.ConfigServer{URL: "https://rancher.example.com"}).ID(),
+				},
+			},
+		},
+		{
+			name: "outdated version",
+			config: `servers:
+  - id: abc
+    name: prod
+    url: https://rancher.example.com
+    username: admin
+    authType: local
+`,
+			expected: []domain.ConfigIssue{
+				{
+					Line: 1, Column: 1, Severity: domain.SeverityWarning,
+					Message: "configuration version 1.0 is outdated and will be migrated to 3.0",
+				},
+			},
+		},
+		{
+			name: "negative idle connections",
+			config: `version: "3.0"
+servers: []
+settings:
+  http:
+    maxIdleConnsPerHost: -1
+`,
+			expected: []domain.ConfigIssue{
+				{
+					Line: 5, Column: 26, Severity: domain.SeverityError,
+					Message: "settings.http.maxIdleConnsPerHost: must not be negative",
+				},
+			},
+		},
+		{
+			name: "invalid naming policy",
+			config: `version: "3.0"
+servers: []
+settings:
+  naming:
+    rfc1123: true
+    replacement: "_"
+    maxLength: -1
+    grouping:
+      by: owner
+`,
+			expected: []domain.ConfigIssue{
+				{
+					Line: 6, Column: 18, Severity: domain.SeverityWarning,
+					Message: `settings.naming.replacement: "_" is not allowed in names and is ignored in favour of "-"`,
+				},
+				{
+					Line: 7, Column: 16, Severity: domain.SeverityError,
+					Message: "settings.naming.maxLength: must not be negative",
+				},
+				{
+					Line: 9, Column: 11, Severity: domain.SeverityError,
+					Message: `settings.naming.grouping.by: unsupported grouping "owner" (use alias or tag)`,
+				},
+			},
+		},
+		{
+			name: "invalid permission policy",
+			config: `version: "3.0"
+servers: []
+settings:
+  permissions:
+    fileMode: "0644"
+    dirMode: "0999"
+    group: "1000"
+`,
+			expected: []domain.ConfigIssue{
+				{
+					Line: 5, Column: 15, Severity: domain.SeverityWarning,
+					Message: "settings.permissions.fileMode: 0644 lets every user read the credentials in merged kubeconfigs",
+				},
+				{
+					Line: 6, Column: 14, Severity: domain.SeverityError,
+					Message: `settings.permissions.dirMode: invalid mode "0999" (use octal permissions such as 0640)`,
+				},
+			},
+		},
+		{
+			name: "invalid templates",
+			config: `version: "3.0"
+servers: []
+settings:
+  templates:
+    - source: telepresence.tmpl
+      output: "{{.Context"
+    - output: ssh/config
+`,
+			expected: []domain.ConfigIssue{
+				{
+					Line: 6, Column: 15, Severity: domain.SeverityError,
+					Message: "settings.templates[0].output: template: output:1: unclosed action",
+				},
+				{
+					Line: 7, Column: 7, Severity: domain.SeverityError,
+					Message: "settings.templates[1].source: is required",
+				},
+			},
+		},
+		{
+			name: "invalid hooks",
+			config: `version: "3.0"
+servers: []
+settings:
+  hooks:
+    preSync:
+      - command: vpn-status
+        timeoutSeconds: -1
+    postSync:
+      - onFailure: abort
+`,
+			expected: []domain.ConfigIssue{
+				{
+					Line: 7, Column: 25, Severity: domain.SeverityError,
+					Message: "settings.hooks.preSync[0].timeoutSeconds: must not be negative",
+				},
+				{
+					Line: 9, Column: 9, Severity: domain.SeverityError,
+					Message: "settings.hooks.postSync[0].command: is required",
+				},
+				{
+					Line: 9, Column: 20, Severity: domain.SeverityError,
+					Message: `settings.hooks.postSync[0].onFailure: unsupported policy "abort" (use fail, warn or ignore)`,
+				},
+			},
+		},
+		{
+			name: "client certificate without key",
+			config: `version: "3.0"
+servers:
+  - url: https://rancher.example.com
+    username: admin
+    authType: local
+    clientCert: /etc/cowpoke/client.pem
+`,
+			expected: []domain.ConfigIssue{
+				{
+					Line: 3, Column: 5, Severity: domain.SeverityError,
+					Message: "servers[0].clientKey: is required when clientCert is set",
+				},
+			},
+		},
+		{
+			name: "tunnel must be socks5 or ssh",
+			config: `version: "3.0"
+servers:
+  - url: https://rancher.internal.example.com
+    username: admin
+    authType: local
+    tunnel: ssh -oProxyCommand=evil
+  - url: https://rancher.lab.example.com
+    username: admin
+    authType: local
+    tunnel: socks5://bastion.example.com:1080
+`,
+			expected: []domain.ConfigIssue{
+				{
+					Line: 6, Column: 13, Severity: domain.SeverityError,
+					Message: `servers[0].tunnel: invalid SSH tunnel "ssh -oProxyCommand=evil" (use "ssh user@bastion")`,
+				},
+			},
+		},
+		{
+			name: "priority must be positive",
+			config: `version: "3.0"
+servers:
+  - url: https://rancher.prod.example.com
+    username: admin
+    authType: local
+    priority: -1
+  - url: https://rancher.lab.example.com
+    username: admin
+    authType: local
+    priority: 2
+`,
+			expected: []domain.ConfigIssue{
+				{
+					Line: 6, Column: 15, Severity: domain.SeverityError,
+					Message: "servers[0].priority: must be 1 or more, or omitted to sync the server last",
+				},
+			},
+		},
+		{
+			name: "exclude patterns must be regular expressions",
+			config: `version: "3.0"
+servers:
+  - url: https://rancher.prod.example.com
+    username: admin
+    authType: local
+    sync:
+      excludePatterns:
+        - "^local-"
+        - "[sandbox"
+settings:
+  sync:
+    excludePatterns: ["(staging"]
+`,
+			expected: []domain.ConfigIssue{
+				{
+					Line: 9, Column: 11, Severity: domain.SeverityError,
+					Message: "servers[0].sync.excludePatterns[1]: invalid regular expression \"[sandbox\": " +
+						"error parsing regexp: missing closing ]: `[sandbox`",
+				},
+				{
+					Line: 12, Column: 23, Severity: domain.SeverityError,
+					Message: "settings.sync.excludePatterns[0]: invalid regular expression \"(staging\": " +
+						"error parsing regexp: missing closing ): `(staging`",
+				},
+			},
+		},
+		{
+			name: "endpoint rewrites need a valid pattern",
+			config: `version: "3.0"
+servers: []
+settings:
+  endpoints:
+    rewrites:
+      - match: "^https://([a-z0-9-]+)\\.internal\\.example\\.com"
+        replace: "https://$1.vpn.example.com"
+      - match: "(unclosed"
+        replace: "https://vpn.example.com"
+      - replace: "https://vpn.example.com"
+`,
+			expected: []domain.ConfigIssue{
+				{
+					Line: 8, Column: 16, Severity: domain.SeverityError,
+					Message: "settings.endpoints.rewrites[1].match: invalid regular expression \"(unclosed\": " +
+						"error parsing regexp: missing closing ): `(unclosed`",
+				},
+				{
+					Line: 10, Column: 9, Severity: domain.SeverityError,
+					Message: "settings.endpoints.rewrites[2].match: is required",
+				},
+			},
+		},
+		{
+			name: "type errors carry line numbers",
+			config: `version: "3.0"
+servers: []
+settings:
+  fragments:
+    retentionDays: forever
+`,
+			expected: []domain.ConfigIssue{
+				{Line: 5, Severity: domain.SeverityError, Message: "cannot unmarshal !!str `forever` into int"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			validator := NewValidator(testutil.Logger())
+
+			// Act
+			issues := validator.Validate(context.Background(), []byte(tt.config))
+
+			// Assert
+			assert.Equal(t, tt.expected, issues)
+		})
+	}
+}
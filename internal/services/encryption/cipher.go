@@ -0,0 +1,117 @@
+// Package encryption provides at-rest encryption for cowpoke's cached files.
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	// KeySize is the AES-256 key length in bytes.
+	KeySize = 32
+)
+
+// header prefixes every ciphertext so encrypted and plain files can be told apart.
+//
+//nolint:gochecknoglobals // Immutable file format marker
+var header = []byte("cowpoke-enc:v1\n")
+
+// KeyFunc resolves the symmetric key on first use. create is false when the key is resolved to decrypt
+// existing data: a missing key is then an error, since a new one could never decrypt it.
+type KeyFunc func(ctx context.Context, create bool) ([]byte, error)
+
+// Cipher encrypts data with AES-256-GCM, resolving its key lazily so prompts only happen when needed.
+type Cipher struct {
+	keyFunc KeyFunc
+
+	once sync.Once
+	aead cipher.AEAD
+	err  error
+}
+
+// NewCipher creates a new cipher using the given key source.
+func NewCipher(keyFunc KeyFunc) *Cipher {
+	return &Cipher{keyFunc: keyFunc}
+}
+
+// IsEncrypted reports whether data was produced by Cipher.Encrypt.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, header)
+}
+
+// IsEncrypted reports whether data was produced by Encrypt.
+func (c *Cipher) IsEncrypted(data []byte) bool {
+	return IsEncrypted(data)
+}
+
+// Encrypt seals plaintext with a random nonce.
+func (c *Cipher) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	aead, err := c.init(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, randErr := rand.Read(nonce); randErr != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", randErr)
+	}
+
+	out := make([]byte, 0, len(header)+len(nonce)+len(plaintext)+aead.Overhead())
+	out = append(out, header...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plaintext, header), nil
+}
+
+// Decrypt opens data produced by Encrypt. Unencrypted data is returned unchanged so
+// that fragments written before encryption was enabled remain readable.
+func (c *Cipher) Decrypt(ctx context.Context, data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+
+	aead, err := c.init(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	body := data[len(header):]
+	if len(body) < aead.NonceSize() {
+		return nil, errors.New("encrypted data is truncated")
+	}
+
+	nonce, sealed := body[:aead.NonceSize()], body[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data (wrong key?): %w", err)
+	}
+	return plaintext, nil
+}
+
+// init resolves the key and builds the AEAD exactly once. create allows generating a missing key.
+func (c *Cipher) init(ctx context.Context, create bool) (cipher.AEAD, error) {
+	c.once.Do(func() {
+		key, err := c.keyFunc(ctx, create)
+		if err != nil {
+			c.err = fmt.Errorf("failed to resolve encryption key: %w", err)
+			return
+		}
+		if len(key) != KeySize {
+			c.err = fmt.Errorf("encryption key must be %d bytes, got %d", KeySize, len(key))
+			return
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			c.err = fmt.Errorf("failed to create cipher: %w", err)
+			return
+		}
+		c.aead, c.err = cipher.NewGCM(block)
+	})
+	return c.aead, c.err
+}
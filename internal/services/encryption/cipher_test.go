@@ -0,0 +1,201 @@
+package encryption
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func staticKey(b byte) KeyFunc {
+	return func(_ context.Context, _ bool) ([]byte, error) {
+		key := make([]byte, KeySize)
+		for i := range key {
+			key[i] = b
+		}
+		return key, nil
+	}
+}
+
+func TestCipher_RoundTrip(t *testing.T) {
+	c := NewCipher(staticKey(1))
+	plaintext := []byte("apiVersion: v1\nkind: Config\n")
+
+	ciphertext, err := c.Encrypt(context.Background(), plaintext)
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(ciphertext))
+	assert.NotContains(t, string(ciphertext), "apiVersion")
+
+	decrypted, err := c.Decrypt(context.Background(), ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+}
+
+func TestCipher_DecryptPassesThroughPlaintext(t *testing.T) {
+	c := NewCipher(func(_ context.Context, _ bool) ([]byte, error) {
+		t.Fatal("key should not be resolved for plaintext data")
+		return nil, nil
+	})
+	plaintext := []byte("apiVersion: v1\n")
+
+	decrypted, err := c.Decrypt(context.Background(), plaintext)
+
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+}
+
+func TestCipher_DecryptWithWrongKey(t *testing.T) {
+	ciphertext, err := NewCipher(staticKey(1)).Encrypt(context.Background(), []byte("secret"))
+	require.NoError(t, err)
+
+	_, err = NewCipher(staticKey(2)).Decrypt(context.Background(), ciphertext)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "wrong key")
+}
+
+func TestCipher_KeyErrorIsSticky(t *testing.T) {
+	calls := 0
+	c := NewCipher(func(_ context.Context, _ bool) ([]byte, error) {
+		calls++
+		return nil, errors.New("no keychain")
+	})
+
+	_, err1 := c.Encrypt(context.Background(), []byte("a"))
+	_, err2 := c.Encrypt(context.Background(), []byte("b"))
+
+	require.Error(t, err1)
+	require.Error(t, err2)
+	assert.Equal(t, 1, calls)
+}
+
+func TestKeychainKey_GeneratesAndStoresKey(t *testing.T) {
+	store := mocks.NewMockSecretStore(t)
+	store.On("Get", mock.Anything, KeychainAccount).Return("", domain.ErrSecretNotFound)
+	store.On("Set", mock.Anything, KeychainAccount, mock.AnythingOfType("string")).Return(nil)
+
+	key, err := KeychainKey(store)(context.Background(), true)
+
+	require.NoError(t, err)
+	assert.Len(t, key, KeySize)
+}
+
+func TestKeychainKey_DoesNotCreateKeyToDecrypt(t *testing.T) {
+	// Arrange
+	store := mocks.NewMockSecretStore(t)
+	store.On("Get", mock.Anything, KeychainAccount).Return("", domain.ErrSecretNotFound)
+
+	// Act
+	_, err := KeychainKey(store)(context.Background(), false)
+
+	// Assert
+	require.Error(t, err)
+	store.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestKeychainKey_DoesNotReplaceUnreadableKey(t *testing.T) {
+	// Arrange
+	store := mocks.NewMockSecretStore(t)
+	store.On("Get", mock.Anything, KeychainAccount).Return("", errors.New("keychain is locked"))
+
+	// Act
+	_, err := KeychainKey(store)(context.Background(), true)
+
+	// Assert
+	require.ErrorContains(t, err, "keychain is locked")
+	store.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCipher_DecryptDoesNotCreateKey(t *testing.T) {
+	// Arrange
+	ciphertext, err := NewCipher(staticKey(1)).Encrypt(context.Background(), []byte("secret"))
+	require.NoError(t, err)
+	store := mocks.NewMockSecretStore(t)
+	store.On("Get", mock.Anything, KeychainAccount).Return("", domain.ErrSecretNotFound)
+
+	// Act
+	_, err = NewCipher(KeychainKey(store)).Decrypt(context.Background(), ciphertext)
+
+	// Assert
+	require.ErrorContains(t, err, "to decrypt existing data")
+}
+
+func TestPassphraseKey_DeterministicWithStoredSalt(t *testing.T) {
+	t.Setenv(PassphraseEnvVar, "correct horse")
+	fs := mocks.NewMockFileSystemAdapter(t)
+	salt := []byte("0123456789abcdef")
+	fs.On("ReadFile", "/salt").Return(salt, nil)
+
+	key1, err := PassphraseKey(nil, fs, "/salt")(context.Background(), true)
+	require.NoError(t, err)
+	key2, err := PassphraseKey(nil, fs, "/salt")(context.Background(), true)
+	require.NoError(t, err)
+
+	assert.Len(t, key1, KeySize)
+	assert.Equal(t, key1, key2)
+}
+
+func TestPassphraseKey_CreatesSalt(t *testing.T) {
+	t.Setenv(PassphraseEnvVar, "correct horse")
+	fs := mocks.NewMockFileSystemAdapter(t)
+	fs.On("ReadFile", "/salt").Return(nil, os.ErrNotExist)
+	fs.On("WriteFile", "/salt", mock.AnythingOfType("[]uint8"), os.FileMode(0o600)).Return(nil)
+
+	key, err := PassphraseKey(nil, fs, "/salt")(context.Background(), true)
+
+	require.NoError(t, err)
+	assert.Len(t, key, KeySize)
+}
+
+func TestConfigKey_PassphraseFromKeyFile(t *testing.T) {
+	t.Setenv(ConfigKeyEnvVar, "")
+	t.Setenv(ConfigKeyFileEnvVar, "/keys/cowpoke")
+	fs := mocks.NewMockFileSystemAdapter(t)
+	fs.On("ReadFile", "/keys/cowpoke").Return([]byte("correct horse\n"), nil)
+	fs.On("ReadFile", "/salt").Return([]byte("0123456789abcdef"), nil)
+
+	fromFile, err := ConfigKey(nil, fs, "/salt")(context.Background(), true)
+	require.NoError(t, err)
+
+	t.Setenv(ConfigKeyEnvVar, "correct horse")
+	fromEnv, err := ConfigKey(nil, fs, "/salt")(context.Background(), true)
+	require.NoError(t, err)
+
+	assert.Len(t, fromFile, KeySize)
+	assert.Equal(t, fromEnv, fromFile)
+}
+
+func TestConfigKey_DoesNotCreateKeyToLoad(t *testing.T) {
+	// Arrange
+	t.Setenv(ConfigKeyEnvVar, "")
+	t.Setenv(ConfigKeyFileEnvVar, "")
+	store := mocks.NewMockSecretStore(t)
+	store.On("Get", mock.Anything, ConfigKeychainAccount).Return("", domain.ErrSecretNotFound)
+
+	// Act
+	_, err := ConfigKey(store, mocks.NewMockFileSystemAdapter(t), "/salt")(context.Background(), false)
+
+	// Assert
+	require.Error(t, err)
+	store.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConfigKey_FallsBackToKeychain(t *testing.T) {
+	t.Setenv(ConfigKeyEnvVar, "")
+	t.Setenv(ConfigKeyFileEnvVar, "")
+	store := mocks.NewMockSecretStore(t)
+	store.On("Get", mock.Anything, ConfigKeychainAccount).Return("", domain.ErrSecretNotFound)
+	store.On("Set", mock.Anything, ConfigKeychainAccount, mock.AnythingOfType("string")).Return(nil)
+
+	key, err := ConfigKey(store, mocks.NewMockFileSystemAdapter(t), "/salt")(context.Background(), true)
+
+	require.NoError(t, err)
+	assert.Len(t, key, KeySize)
+}
@@ -0,0 +1,51 @@
+package encryption
+
+import (
+	"context"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"strings"
+
+	"cowpoke/internal/domain"
+)
+
+const (
+	// ConfigKeyEnvVar supplies the passphrase protecting an encrypted configuration file.
+	ConfigKeyEnvVar = "COWPOKE_CONFIG_KEY"
+
+	// ConfigKeyFileEnvVar names a file whose contents are the configuration passphrase.
+	ConfigKeyFileEnvVar = "COWPOKE_CONFIG_KEY_FILE"
+
+	// ConfigKeychainAccount is the keychain account name under which the configuration key is stored.
+	ConfigKeychainAccount = "config-key"
+)
+
+// ConfigKey resolves the key of an encrypted configuration file. A passphrase from ConfigKeyEnvVar, or read
+// from the file named by ConfigKeyFileEnvVar, takes precedence; its salt is persisted at saltPath. Without
+// one, a random key is loaded from the OS keychain. The key is only generated to encrypt, which a loaded
+// configuration only does once config encrypt has enabled encryption.
+func ConfigKey(store domain.SecretStore, fs domain.FileSystemAdapter, saltPath string) KeyFunc {
+	return func(ctx context.Context, create bool) ([]byte, error) {
+		passphrase := os.Getenv(ConfigKeyEnvVar)
+		if keyFile := os.Getenv(ConfigKeyFileEnvVar); passphrase == "" && keyFile != "" {
+			data, err := fs.ReadFile(keyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read key file: %w", err)
+			}
+			if passphrase = strings.TrimSpace(string(data)); passphrase == "" {
+				return nil, fmt.Errorf("key file %s is empty", keyFile)
+			}
+		}
+		if passphrase == "" {
+			return keychainKey(ctx, store, ConfigKeychainAccount, create)
+		}
+
+		salt, err := loadOrCreateSalt(fs, saltPath, create)
+		if err != nil {
+			return nil, err
+		}
+		return pbkdf2.Key(sha256.New, passphrase, salt, pbkdf2Iterations, KeySize)
+	}
+}
@@ -0,0 +1,113 @@
+package encryption
+
+import (
+	"context"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+
+	"cowpoke/internal/domain"
+)
+
+const (
+	// PassphraseEnvVar supplies the fragment passphrase for non-interactive runs.
+	PassphraseEnvVar = "COWPOKE_FRAGMENT_PASSPHRASE"
+
+	// KeychainAccount is the keychain account name under which the fragment key is stored.
+	KeychainAccount = "fragment-key"
+
+	saltSize         = 16
+	pbkdf2Iterations = 600000
+	saltPermissions  = 0o600
+)
+
+// PassphraseKey derives a key from a passphrase read from PassphraseEnvVar or prompted
+// interactively. The salt is persisted at saltPath so the same passphrase always yields the same key.
+func PassphraseKey(reader domain.PasswordReader, fs domain.FileSystemAdapter, saltPath string) KeyFunc {
+	return func(ctx context.Context, create bool) ([]byte, error) {
+		passphrase := os.Getenv(PassphraseEnvVar)
+		if passphrase == "" {
+			var err error
+			passphrase, err = reader.ReadPassword(ctx, "Fragment encryption passphrase: ")
+			if err != nil {
+				return nil, fmt.Errorf("failed to read passphrase: %w", err)
+			}
+		}
+		if passphrase == "" {
+			return nil, errors.New("empty passphrase")
+		}
+
+		salt, err := loadOrCreateSalt(fs, saltPath, create)
+		if err != nil {
+			return nil, err
+		}
+
+		return pbkdf2.Key(sha256.New, passphrase, salt, pbkdf2Iterations, KeySize)
+	}
+}
+
+// KeychainKey loads a random key from the OS keychain, generating and storing one when it first encrypts.
+func KeychainKey(store domain.SecretStore) KeyFunc {
+	return func(ctx context.Context, create bool) ([]byte, error) {
+		return keychainKey(ctx, store, KeychainAccount, create)
+	}
+}
+
+// keychainKey loads the random key stored under account. If there is none, it generates and stores one if
+// create is set. Any other keychain failure is returned, so that an unreadable key is never replaced.
+func keychainKey(ctx context.Context, store domain.SecretStore, account string, create bool) ([]byte, error) {
+	encoded, err := store.Get(ctx, account)
+	if err == nil {
+		key, decodeErr := base64.StdEncoding.DecodeString(encoded)
+		if decodeErr != nil {
+			return nil, fmt.Errorf("keychain entry %q is corrupt: %w", account, decodeErr)
+		}
+		return key, nil
+	}
+	if !errors.Is(err, domain.ErrSecretNotFound) {
+		return nil, fmt.Errorf("failed to read key from keychain: %w", err)
+	}
+	if !create {
+		return nil, fmt.Errorf("keychain has no key %q to decrypt existing data", account)
+	}
+
+	key := make([]byte, KeySize)
+	if _, randErr := rand.Read(key); randErr != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", randErr)
+	}
+	if setErr := store.Set(ctx, account, base64.StdEncoding.EncodeToString(key)); setErr != nil {
+		return nil, fmt.Errorf("failed to store key in keychain: %w", setErr)
+	}
+	return key, nil
+}
+
+// loadOrCreateSalt reads the persisted salt. If it does not exist yet, it creates a random one if create is
+// set.
+func loadOrCreateSalt(fs domain.FileSystemAdapter, saltPath string, create bool) ([]byte, error) {
+	salt, err := fs.ReadFile(saltPath)
+	if err == nil {
+		if len(salt) != saltSize {
+			return nil, fmt.Errorf("salt file %s is corrupt", saltPath)
+		}
+		return salt, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read salt: %w", err)
+	}
+	if !create {
+		return nil, fmt.Errorf("salt file %s to decrypt existing data is missing", saltPath)
+	}
+
+	salt = make([]byte, saltSize)
+	if _, randErr := rand.Read(salt); randErr != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", randErr)
+	}
+	if writeErr := fs.WriteFile(saltPath, salt, saltPermissions); writeErr != nil {
+		return nil, fmt.Errorf("failed to write salt: %w", writeErr)
+	}
+	return salt, nil
+}
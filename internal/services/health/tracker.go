@@ -0,0 +1,131 @@
+// Package health tracks per-server sync failures and computes exponential backoff.
+package health
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"cowpoke/internal/domain"
+)
+
+const (
+	// stateName is the state document holding server health.
+	stateName = "health"
+
+	// baseBackoff is the delay after the first failure; it doubles with each further failure.
+	baseBackoff = time.Minute
+	// maxBackoff caps the delay between attempts for a persistently failing server.
+	maxBackoff = time.Hour
+)
+
+// Tracker records server health in the state store.
+type Tracker struct {
+	store  domain.StateStore
+	logger *slog.Logger
+	clock  domain.Clock
+
+	mu sync.Mutex
+}
+
+// NewTracker creates a new health tracker.
+func NewTracker(store domain.StateStore, clock domain.Clock, logger *slog.Logger) *Tracker {
+	return &Tracker{
+		store:  store,
+		logger: logger,
+		clock:  clock,
+	}
+}
+
+// Get returns the recorded health for a server.
+func (t *Tracker) Get(ctx context.Context, server domain.ConfigServer) (domain.ServerHealth, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	servers, err := t.load(ctx)
+	if err != nil {
+		return domain.ServerHealth{}, err
+	}
+
+	health, ok := servers[server.ID()]
+	if !ok {
+		return domain.ServerHealth{ServerID: server.ID(), ServerURL: server.URL}, nil
+	}
+	return health, nil
+}
+
+// RecordSuccess resets the failure count for a server.
+func (t *Tracker) RecordSuccess(ctx context.Context, server domain.ConfigServer) error {
+	return t.update(ctx, server, func(h *domain.ServerHealth) {
+		h.ConsecutiveFailures = 0
+		h.LastError = ""
+		h.LastSuccess = t.clock.Now()
+		h.NextAttempt = time.Time{}
+	})
+}
+
+// RecordFailure increments the failure count for a server and extends its backoff.
+func (t *Tracker) RecordFailure(ctx context.Context, server domain.ConfigServer, cause error) error {
+	return t.update(ctx, server, func(h *domain.ServerHealth) {
+		h.ConsecutiveFailures++
+		h.LastFailure = t.clock.Now()
+		h.NextAttempt = h.LastFailure.Add(Backoff(h.ConsecutiveFailures))
+		if cause != nil {
+			h.LastError = cause.Error()
+		}
+
+		t.logger.DebugContext(ctx, "Server marked as failing",
+			"server", server.URL,
+			"consecutive_failures", h.ConsecutiveFailures,
+			"next_attempt", h.NextAttempt)
+	})
+}
+
+// Backoff returns the delay before retrying a server after the given number of consecutive failures.
+func Backoff(failures int) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+
+	delay := baseBackoff
+	for range failures - 1 {
+		delay *= 2
+		if delay >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return delay
+}
+
+// update applies fn to a server's health and persists the result.
+func (t *Tracker) update(ctx context.Context, server domain.ConfigServer, fn func(*domain.ServerHealth)) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	servers, err := t.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	health := servers[server.ID()]
+	health.ServerID = server.ID()
+	health.ServerURL = server.URL
+	fn(&health)
+	servers[server.ID()] = health
+
+	if saveErr := t.store.Save(ctx, stateName, servers); saveErr != nil {
+		return fmt.Errorf("failed to save server health: %w", saveErr)
+	}
+	return nil
+}
+
+// load reads all recorded server health keyed by server ID.
+func (t *Tracker) load(ctx context.Context) (map[string]domain.ServerHealth, error) {
+	servers := make(map[string]domain.ServerHealth)
+	if _, err := t.store.Load(ctx, stateName, &servers); err != nil {
+		return nil, fmt.Errorf("failed to load server health: %w", err)
+	}
+	return servers, nil
+}
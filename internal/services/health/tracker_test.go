@@ -0,0 +1,77 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cowpoke/internal/adapters/filesystem"
+	"cowpoke/internal/domain"
+	"cowpoke/internal/services/state"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		failures int
+		expected time.Duration
+	}{
+		{failures: 0, expected: 0},
+		{failures: 1, expected: time.Minute},
+		{failures: 2, expected: 2 * time.Minute},
+		{failures: 4, expected: 8 * time.Minute},
+		{failures: 7, expected: time.Hour},
+		{failures: 100, expected: time.Hour},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, Backoff(tt.failures), "failures=%d", tt.failures)
+	}
+}
+
+func TestTracker_RecordFailureAndSuccess(t *testing.T) {
+	store := state.NewStore(filesystem.New(), t.TempDir(), testutil.Logger())
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := testutil.NewClock(now)
+	tracker := NewTracker(store, clock, testutil.Logger())
+
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	ctx := context.Background()
+
+	require.NoError(t, tracker.RecordFailure(ctx, server, errors.New("connection refused")))
+	require.NoError(t, tracker.RecordFailure(ctx, server, errors.New("connection refused")))
+
+	health, err := tracker.Get(ctx, server)
+	require.NoError(t, err)
+	assert.Equal(t, 2, health.ConsecutiveFailures)
+	assert.Equal(t, "connection refused", health.LastError)
+	assert.Equal(t, now.Add(2*time.Minute), health.NextAttempt)
+	assert.True(t, health.BackingOff(now.Add(time.Minute)))
+	assert.False(t, health.BackingOff(now.Add(3*time.Minute)))
+
+	clock.Advance(3 * time.Minute)
+	require.NoError(t, tracker.RecordSuccess(ctx, server))
+
+	health, err = tracker.Get(ctx, server)
+	require.NoError(t, err)
+	assert.Zero(t, health.ConsecutiveFailures)
+	assert.Empty(t, health.LastError)
+	assert.Equal(t, now.Add(3*time.Minute), health.LastSuccess)
+	assert.False(t, health.BackingOff(clock.Now()))
+}
+
+func TestTracker_GetUnknownServer(t *testing.T) {
+	store := state.NewStore(filesystem.New(), t.TempDir(), testutil.Logger())
+	tracker := NewTracker(store, domain.SystemClock{}, testutil.Logger())
+	server := domain.ConfigServer{URL: "https://rancher.example.com"}
+
+	health, err := tracker.Get(context.Background(), server)
+
+	require.NoError(t, err)
+	assert.Equal(t, server.ID(), health.ServerID)
+	assert.Zero(t, health.ConsecutiveFailures)
+}
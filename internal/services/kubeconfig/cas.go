@@ -0,0 +1,108 @@
+package kubeconfig
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	// caDirSuffix names the directory of shared CA files next to a merged kubeconfig, such as config-ca.
+	caDirSuffix = "-ca"
+	// caFileExt is the extension of shared CA files, which are named by a hash of their content.
+	caFileExt = ".crt"
+	// caHashLength is how many hex digits of the SHA-256 of a CA bundle name its file.
+	caHashLength = 16
+)
+
+// WithSharedCAs writes CA bundles that several clusters of the merged kubeconfig repeat to files in a
+// directory next to it once, referencing them with certificate-authority instead of embedding each copy.
+func WithSharedCAs(share bool) Option {
+	return func(h *Handler) {
+		h.shareCAs = share
+	}
+}
+
+// shareCertificateAuthorities moves the CA data that more than one cluster of config embeds to files in the
+// CA directory of outputPath and points those clusters at them. CA files no longer referenced are removed.
+func (h *Handler) shareCertificateAuthorities(ctx context.Context, config *api.Config, outputPath string) error {
+	outputPath, err := filepath.Abs(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve output path: %w", err)
+	}
+	caDir := outputPath + caDirSuffix
+
+	repeated := make(map[string]int)
+	for _, cluster := range config.Clusters {
+		if len(cluster.CertificateAuthorityData) > 0 {
+			repeated[string(cluster.CertificateAuthorityData)]++
+		}
+	}
+
+	referenced := make(map[string]bool)
+	shared := 0
+	for name, cluster := range config.Clusters {
+		data := cluster.CertificateAuthorityData
+		if len(data) == 0 || repeated[string(data)] < 2 {
+			if filepath.Dir(cluster.CertificateAuthority) == caDir {
+				referenced[cluster.CertificateAuthority] = true
+			}
+			continue
+		}
+		sum := sha256.Sum256(data)
+		path := filepath.Join(caDir, hex.EncodeToString(sum[:])[:caHashLength]+caFileExt)
+		if !referenced[path] {
+			if writeErr := h.writeCAFile(caDir, path, data); writeErr != nil {
+				return writeErr
+			}
+			referenced[path] = true
+		}
+		h.logger.DebugContext(ctx, "Sharing cluster CA", "cluster", name, "path", path)
+		cluster.CertificateAuthority = path
+		cluster.CertificateAuthorityData = nil
+		shared++
+	}
+
+	h.removeStaleCAFiles(ctx, caDir, referenced)
+	if shared > 0 {
+		h.logger.InfoContext(ctx, "Shared cluster CAs", "clusters", shared, "files", len(referenced), "dir", caDir)
+	}
+	return nil
+}
+
+// writeCAFile writes data to path in caDir unless the file already holds it.
+func (h *Handler) writeCAFile(caDir, path string, data []byte) error {
+	if existing, err := h.fs.ReadFile(path); err == nil && string(existing) == string(data) {
+		return nil
+	}
+	if err := h.fs.MkdirAll(caDir, h.permissions.Dir()); err != nil {
+		return fmt.Errorf("failed to create CA directory: %w", err)
+	}
+	if err := h.fs.WriteFile(path, data, h.permissions.File()); err != nil {
+		return fmt.Errorf("failed to write CA file: %w", err)
+	}
+	return h.applyPermissions(path)
+}
+
+// removeStaleCAFiles removes the CA files in caDir that are not referenced. Failures are only logged,
+// since a stale CA file does no harm.
+func (h *Handler) removeStaleCAFiles(ctx context.Context, caDir string, referenced map[string]bool) {
+	entries, err := h.fs.ReadDir(caDir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		path := filepath.Join(caDir, entry.Name())
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), caFileExt) || referenced[path] {
+			continue
+		}
+		if removeErr := h.fs.Remove(path); removeErr != nil {
+			h.logger.WarnContext(ctx, "Could not remove stale CA file", "path", path, "error", removeErr)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package kubeconfig
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"cowpoke/internal/domain"
+)
+
+// ListContexts returns the contexts cowpoke generated in the kubeconfig at path, sorted by name. A missing
+// kubeconfig has none.
+func (h *Handler) ListContexts(ctx context.Context, path string) ([]domain.ManagedContext, error) {
+	config, err := h.loadKubeconfig(path)
+	if err != nil || config == nil {
+		return nil, err
+	}
+
+	var contexts []domain.ManagedContext
+	for name, kubeContext := range config.Contexts {
+		owner, owned := ContextOwnerOf(kubeContext)
+		if !owned {
+			continue
+		}
+		var endpoint string
+		if cluster, ok := config.Clusters[kubeContext.Cluster]; ok {
+			endpoint = cluster.Server
+		}
+		contexts = append(contexts, domain.ManagedContext{
+			Name:        name,
+			ClusterName: strings.TrimSuffix(name, "-"+owner.ServerID),
+			Owner:       owner,
+			Current:     name == config.CurrentContext,
+			Endpoint:    endpoint,
+		})
+	}
+	slices.SortFunc(contexts, func(a, b domain.ManagedContext) int { return cmp.Compare(a.Name, b.Name) })
+
+	h.logger.DebugContext(ctx, "Listed cowpoke contexts", "path", path, "contexts", len(contexts))
+	return contexts, nil
+}
+
+// ForeignContexts returns the names of the contexts in the kubeconfig at path that cowpoke did not
+// generate, sorted. A missing kubeconfig has none.
+func (h *Handler) ForeignContexts(_ context.Context, path string) ([]string, error) {
+	config, err := h.loadKubeconfig(path)
+	if err != nil || config == nil {
+		return nil, err
+	}
+
+	var names []string
+	for name, kubeContext := range config.Contexts {
+		if _, owned := ContextOwnerOf(kubeContext); !owned {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// SnapshotContexts returns every context in the kubeconfig at path with its endpoint and a fingerprint of
+// its credentials, sorted by name. A missing kubeconfig has none.
+func (h *Handler) SnapshotContexts(_ context.Context, path string) ([]domain.ContextSnapshot, error) {
+	config, err := h.loadKubeconfig(path)
+	if err != nil || config == nil {
+		return nil, err
+	}
+
+	snapshots := make([]domain.ContextSnapshot, 0, len(config.Contexts))
+	for name, kubeContext := range config.Contexts {
+		snapshot := domain.ContextSnapshot{Name: name}
+		if cluster, ok := config.Clusters[kubeContext.Cluster]; ok {
+			snapshot.Endpoint = cluster.Server
+		}
+		if authInfo, ok := config.AuthInfos[kubeContext.AuthInfo]; ok {
+			snapshot.Credential = credentialFingerprint(authInfo)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	slices.SortFunc(snapshots, func(a, b domain.ContextSnapshot) int { return cmp.Compare(a.Name, b.Name) })
+	return snapshots, nil
+}
+
+// credentialFingerprint hashes the credentials of authInfo, so that snapshots tell when they change without
+// holding them.
+func credentialFingerprint(authInfo *api.AuthInfo) string {
+	hash := sha256.New()
+	parts := []string{
+		authInfo.Token,
+		authInfo.TokenFile,
+		authInfo.Username,
+		authInfo.Password,
+		authInfo.ClientCertificate,
+		string(authInfo.ClientCertificateData),
+	}
+	if authInfo.Exec != nil {
+		parts = append(parts, authInfo.Exec.Command)
+		parts = append(parts, authInfo.Exec.Args...)
+	}
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// UseContext makes name the current context of the kubeconfig at path.
+func (h *Handler) UseContext(ctx context.Context, path, name string) error {
+	config, err := h.loadKubeconfig(path)
+	if err != nil {
+		return err
+	}
+	if config == nil || config.Contexts[name] == nil {
+		return fmt.Errorf("context %s not found in %s", name, path)
+	}
+	if config.CurrentContext == name {
+		return nil
+	}
+
+	config.CurrentContext = name
+	content, err := clientcmd.Write(*config)
+	if err != nil {
+		return fmt.Errorf("failed to serialize kubeconfig: %w", err)
+	}
+	if writeErr := h.fs.WriteFile(path, content, h.permissions.File()); writeErr != nil {
+		return fmt.Errorf("failed to write kubeconfig %s: %w", path, writeErr)
+	}
+
+	h.logger.InfoContext(ctx, "Switched current context", "path", path, "context", name)
+	return nil
+}
+
+// RemoveContexts removes the named contexts from the kubeconfig at path, with the clusters and users no
+// other context references, and returns how many it removed. A removed current context is unset, and a
+// missing kubeconfig has nothing to remove.
+func (h *Handler) RemoveContexts(ctx context.Context, path string, names []string) (int, error) {
+	config, err := h.loadKubeconfig(path)
+	if err != nil || config == nil {
+		return 0, err
+	}
+
+	var removed int
+	for _, name := range names {
+		if _, ok := config.Contexts[name]; !ok {
+			continue
+		}
+		delete(config.Contexts, name)
+		if config.CurrentContext == name {
+			config.CurrentContext = ""
+		}
+		removed++
+		h.logger.DebugContext(ctx, "Removed context", "context", name)
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+
+	clusters, users := make(map[string]bool), make(map[string]bool)
+	for _, kubeContext := range config.Contexts {
+		clusters[kubeContext.Cluster] = true
+		users[kubeContext.AuthInfo] = true
+	}
+	maps.DeleteFunc(config.Clusters, func(name string, _ *api.Cluster) bool { return !clusters[name] })
+	maps.DeleteFunc(config.AuthInfos, func(name string, _ *api.AuthInfo) bool { return !users[name] })
+
+	content, err := clientcmd.Write(*config)
+	if err != nil {
+		return 0, fmt.Errorf("failed to serialize kubeconfig: %w", err)
+	}
+	if writeErr := h.fs.WriteFile(path, content, h.permissions.File()); writeErr != nil {
+		return 0, fmt.Errorf("failed to write kubeconfig %s: %w", path, writeErr)
+	}
+
+	h.logger.InfoContext(ctx, "Removed contexts", "path", path, "contexts", removed)
+	return removed, nil
+}
+
+// redacted replaces the credentials of a kubeconfig read with redaction.
+const redacted = "[REDACTED]"
+
+// ReadKubeconfig returns the kubeconfig at path, decrypting an encrypted fragment. With redact, tokens,
+// passwords and client keys are replaced by a placeholder.
+func (h *Handler) ReadKubeconfig(ctx context.Context, path string, redact bool) ([]byte, error) {
+	data, err := h.readDecrypted(ctx, path)
+	if err != nil || !redact {
+		return data, err
+	}
+
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig %s: %w", path, err)
+	}
+	for _, authInfo := range config.AuthInfos {
+		if authInfo.Token != "" {
+			authInfo.Token = redacted
+		}
+		if authInfo.Password != "" {
+			authInfo.Password = redacted
+		}
+		if len(authInfo.ClientKeyData) > 0 {
+			authInfo.ClientKeyData = []byte(redacted)
+		}
+	}
+	content, err := clientcmd.Write(*config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize kubeconfig: %w", err)
+	}
+	return content, nil
+}
+
+// ClusterCredential returns the bearer token of the kubeconfig at path, such as a cached fragment, and when
+// it expires. The current context's user is preferred over those of the other contexts cowpoke generated.
+func (h *Handler) ClusterCredential(ctx context.Context, path string) (domain.ClusterCredential, error) {
+	data, err := h.readDecrypted(ctx, path)
+	if err != nil {
+		return domain.ClusterCredential{}, err
+	}
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return domain.ClusterCredential{}, fmt.Errorf("failed to parse kubeconfig %s: %w", path, err)
+	}
+
+	names := slices.Sorted(maps.Keys(config.Contexts))
+	if _, ok := config.Contexts[config.CurrentContext]; ok {
+		names = append([]string{config.CurrentContext}, names...)
+	}
+	for _, name := range names {
+		kubeContext := config.Contexts[name]
+		owner, owned := ContextOwnerOf(kubeContext)
+		authInfo, ok := config.AuthInfos[kubeContext.AuthInfo]
+		if !owned || !ok || authInfo.Token == "" {
+			continue
+		}
+		return domain.ClusterCredential{Token: authInfo.Token, ExpiresAt: owner.ExpiresAt}, nil
+	}
+	return domain.ClusterCredential{}, fmt.Errorf("no token found in kubeconfig %s", path)
+}
+
+// readDecrypted reads the kubeconfig at path, decrypting it if fragments are encrypted.
+func (h *Handler) readDecrypted(ctx context.Context, path string) ([]byte, error) {
+	data, err := h.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kubeconfig %s: %w", path, err)
+	}
+	if h.encryptor != nil {
+		if data, err = h.encryptor.Decrypt(ctx, data); err != nil {
+			return nil, fmt.Errorf("failed to decrypt kubeconfig %s: %w", path, err)
+		}
+	}
+	return data, nil
+}
+
+// loadKubeconfig reads the kubeconfig at path, returning nil if it does not exist.
+func (h *Handler) loadKubeconfig(path string) (*api.Config, error) {
+	data, err := h.fs.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read kubeconfig %s: %w", path, err)
+	}
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig %s: %w", path, err)
+	}
+	return config, nil
+}
@@ -0,0 +1,45 @@
+package kubeconfig
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"cowpoke/internal/domain"
+)
+
+// endpointRewrite is a compiled domain.EndpointRewrite.
+type endpointRewrite struct {
+	match   *regexp.Regexp
+	replace string
+}
+
+// compileRewrites compiles the patterns of rewrites.
+func compileRewrites(rewrites []domain.EndpointRewrite) ([]endpointRewrite, error) {
+	compiled := make([]endpointRewrite, 0, len(rewrites))
+	for _, rewrite := range rewrites {
+		match, err := regexp.Compile(rewrite.Match)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint rewrite pattern %q: %w", rewrite.Match, err)
+		}
+		compiled = append(compiled, endpointRewrite{match: match, replace: rewrite.Replace})
+	}
+	return compiled, nil
+}
+
+// rewriteEndpoints applies the first matching rewrite to the server URL of each cluster in config.
+func (h *Handler) rewriteEndpoints(ctx context.Context, config *api.Config) {
+	for name, cluster := range config.Clusters {
+		for _, rewrite := range h.endpoints {
+			if !rewrite.match.MatchString(cluster.Server) {
+				continue
+			}
+			server := rewrite.match.ReplaceAllString(cluster.Server, rewrite.replace)
+			h.logger.DebugContext(ctx, "Rewrote cluster endpoint", "cluster", name, "from", cluster.Server, "to", server)
+			cluster.Server = server
+			break
+		}
+	}
+}
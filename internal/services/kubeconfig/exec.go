@@ -0,0 +1,50 @@
+package kubeconfig
+
+import (
+	"context"
+	"slices"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// execAPIVersion is the version of the client.authentication.k8s.io API the credential plugin speaks.
+const execAPIVersion = "client.authentication.k8s.io/v1"
+
+// WithExecCredentials replaces the tokens of the merged kubeconfig's users with an exec credential plugin
+// running command, so that kubectl fetches each cluster's token on demand. Flags are passed to command
+// ahead of the credential subcommand. An empty command keeps the tokens.
+func WithExecCredentials(command string, flags ...string) Option {
+	return func(h *Handler) {
+		h.execCommand = command
+		h.execFlags = flags
+	}
+}
+
+// useExecCredentials points the users of the contexts cowpoke generated for a known cluster at the exec
+// credential plugin, dropping their embedded credentials. It returns how many users it replaced.
+func (h *Handler) useExecCredentials(ctx context.Context, config *api.Config) int {
+	replaced := 0
+	for name, kubeContext := range config.Contexts {
+		owner, ok := ContextOwnerOf(kubeContext)
+		if !ok || owner.ClusterID == "" {
+			continue
+		}
+		authInfo, ok := config.AuthInfos[kubeContext.AuthInfo]
+		if !ok || authInfo.Exec != nil {
+			continue
+		}
+		args := append(slices.Clone(h.execFlags),
+			"credential", "--server", owner.ServerID, "--cluster", owner.ClusterID)
+		config.AuthInfos[kubeContext.AuthInfo] = &api.AuthInfo{
+			Exec: &api.ExecConfig{
+				APIVersion:      execAPIVersion,
+				Command:         h.execCommand,
+				Args:            args,
+				InteractiveMode: api.IfAvailableExecInteractiveMode,
+			},
+		}
+		h.logger.DebugContext(ctx, "Using exec credentials", "context", name, "user", kubeContext.AuthInfo)
+		replaced++
+	}
+	return replaced
+}
@@ -0,0 +1,45 @@
+package kubeconfig
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"cowpoke/internal/domain"
+)
+
+// setContextOwner writes owner into the context's cowpoke extension, replacing any previous value.
+func setContextOwner(context *api.Context, owner domain.ContextOwner) error {
+	raw, err := json.Marshal(owner)
+	if err != nil {
+		return fmt.Errorf("failed to encode context owner: %w", err)
+	}
+	if context.Extensions == nil {
+		context.Extensions = make(map[string]runtime.Object)
+	}
+	context.Extensions[domain.ContextExtensionName] = &runtime.Unknown{
+		Raw:         raw,
+		ContentType: runtime.ContentTypeJSON,
+	}
+	return nil
+}
+
+// ContextOwnerOf returns the owner recorded in a context's cowpoke extension.
+// It reports false for contexts that were not generated by cowpoke.
+func ContextOwnerOf(context *api.Context) (domain.ContextOwner, bool) {
+	if context == nil {
+		return domain.ContextOwner{}, false
+	}
+	extension, ok := context.Extensions[domain.ContextExtensionName].(*runtime.Unknown)
+	if !ok || len(extension.Raw) == 0 {
+		return domain.ContextOwner{}, false
+	}
+
+	var owner domain.ContextOwner
+	if err := json.Unmarshal(extension.Raw, &owner); err != nil || owner.ServerID == "" {
+		return domain.ContextOwner{}, false
+	}
+	return owner, true
+}
@@ -1,6 +1,8 @@
 package kubeconfig
 
 import (
+	"bytes"
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -8,6 +10,11 @@ import (
 	"maps"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
@@ -24,33 +31,147 @@ const (
 type Handler struct {
 	fs            domain.FileSystemAdapter
 	kubeconfigDir string
+	encryptor     domain.Encryptor
+	version       string
+	naming        domain.NamingSettings
+	groups        map[string]string
+	permissions   domain.PermissionSettings
+	rewrites      []domain.EndpointRewrite
+	endpoints     []endpointRewrite
+	shareCAs      bool
+	execCommand   string
+	execFlags     []string
 	logger        *slog.Logger
+
+	// mu guards counted, the merge built by CountContexts and kept for the MergeKubeconfigs call that follows.
+	mu      sync.Mutex
+	counted *mergeResult
+}
+
+// mergeResult is a merged kubeconfig held in memory until it is written, with the inputs it was built from.
+type mergeResult struct {
+	paths    []string
+	filter   domain.ClusterFilter
+	config   *api.Config
+	excluded int
+}
+
+// Option is a functional option for configuring the Handler.
+type Option func(*Handler)
+
+// WithEncryptor encrypts fragments at rest; they are only decrypted in memory while merging.
+func WithEncryptor(encryptor domain.Encryptor) Option {
+	return func(h *Handler) {
+		h.encryptor = encryptor
+	}
+}
+
+// WithVersion records the cowpoke version in the extension written to each generated context.
+func WithVersion(version string) Option {
+	return func(h *Handler) {
+		h.version = version
+	}
+}
+
+// WithNaming sanitizes the names of kubeconfig resources under the naming policy.
+func WithNaming(naming domain.NamingSettings) Option {
+	return func(h *Handler) {
+		h.naming = naming
+	}
+}
+
+// WithGroups prefixes the names of merged contexts with the groups of their servers, keyed by server ID,
+// as the naming policy's grouping describes.
+func WithGroups(groups map[string]string) Option {
+	return func(h *Handler) {
+		h.groups = groups
+	}
+}
+
+// WithPermissions applies the permission policy to the merged kubeconfigs the handler writes.
+func WithPermissions(permissions domain.PermissionSettings) Option {
+	return func(h *Handler) {
+		h.permissions = permissions
+	}
+}
+
+// WithEndpointRewrites rewrites the server URLs of downloaded kubeconfigs. The first rewrite whose pattern
+// matches a URL applies.
+func WithEndpointRewrites(rewrites []domain.EndpointRewrite) Option {
+	return func(h *Handler) {
+		h.rewrites = rewrites
+	}
 }
 
 // NewHandler creates a new kubeconfig handler.
-func NewHandler(fs domain.FileSystemAdapter, kubeconfigDir string, logger *slog.Logger) (*Handler, error) {
+func NewHandler(
+	fs domain.FileSystemAdapter,
+	kubeconfigDir string,
+	logger *slog.Logger,
+	opts ...Option,
+) (*Handler, error) {
 	if err := fs.MkdirAll(kubeconfigDir, dirPermissions); err != nil {
 		return nil, fmt.Errorf("failed to create kubeconfig directory: %w", err)
 	}
 
-	return &Handler{
+	h := &Handler{
 		fs:            fs,
 		kubeconfigDir: kubeconfigDir,
 		logger:        logger,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	endpoints, err := compileRewrites(h.rewrites)
+	if err != nil {
+		return nil, err
+	}
+	h.endpoints = endpoints
+
+	return h, nil
 }
 
-// SaveKubeconfig saves a kubeconfig to a file after preprocessing to avoid conflicts.
-func (h *Handler) SaveKubeconfig(ctx context.Context, path string, content []byte, serverID string) error {
+// SaveKubeconfig saves a kubeconfig to a file after preprocessing to avoid conflicts. A kubeconfig that
+// cannot be preprocessed is quarantined instead and reported with a domain.QuarantineError. The kubeconfig
+// is parsed once and only written if it differs from the saved one.
+func (h *Handler) SaveKubeconfig(ctx context.Context, path string, content []byte, owner domain.ContextOwner) error {
 	dir := filepath.Dir(path)
 	if err := h.fs.MkdirAll(dir, dirPermissions); err != nil {
 		return fmt.Errorf("failed to create directory for kubeconfig: %w", err)
 	}
 
 	// Preprocess the kubeconfig to append server ID to all resources
-	processedContent, err := h.PreprocessKubeconfig(ctx, content, serverID)
+	config, err := h.preprocess(ctx, content, owner)
 	if err != nil {
-		return fmt.Errorf("failed to preprocess kubeconfig: %w", err)
+		err = fmt.Errorf("failed to preprocess kubeconfig: %w", err)
+		quarantined, quarantineErr := h.quarantine(ctx, filepath.Base(path), content, err)
+		if quarantineErr != nil {
+			h.logger.WarnContext(ctx, "Failed to quarantine kubeconfig", "path", path, "error", quarantineErr)
+			return err
+		}
+		return &domain.QuarantineError{Path: quarantined, Err: err}
+	}
+
+	if h.unchanged(ctx, path, config) {
+		// Refresh the modification time so retention still sees the fragment as current.
+		now := time.Now()
+		if touchErr := h.fs.Chtimes(path, now, now); touchErr != nil {
+			return fmt.Errorf("failed to refresh kubeconfig file: %w", touchErr)
+		}
+		h.logger.DebugContext(ctx, "Kubeconfig unchanged, keeping existing file", "path", path)
+		return nil
+	}
+
+	processedContent, err := clientcmd.Write(*config)
+	if err != nil {
+		return fmt.Errorf("failed to serialize kubeconfig: %w", err)
+	}
+
+	if h.encryptor != nil {
+		processedContent, err = h.encryptor.Encrypt(ctx, processedContent)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt kubeconfig: %w", err)
+		}
 	}
 
 	if writeErr := h.fs.WriteFile(path, processedContent, filePermissions); writeErr != nil {
@@ -61,11 +182,59 @@ func (h *Handler) SaveKubeconfig(ctx context.Context, path string, content []byt
 	return nil
 }
 
-// PreprocessKubeconfig appends server ID to all kubeconfig resources to avoid naming conflicts.
-func (h *Handler) PreprocessKubeconfig(ctx context.Context, content []byte, serverID string) ([]byte, error) {
+// unchanged reports whether the fragment at path already holds the preprocessed config. The sync time
+// recorded in each context is left out of the comparison, and kept in the file: rewriting it on every sync
+// would change the merged kubeconfig too.
+func (h *Handler) unchanged(ctx context.Context, path string, config *api.Config) bool {
+	existing, err := h.fs.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	if h.encryptor != nil {
+		if existing, err = h.encryptor.Decrypt(ctx, existing); err != nil {
+			return false
+		}
+	}
+
+	saved, err := clientcmd.Load(existing)
+	if err != nil {
+		return false
+	}
+	for name, context := range saved.Contexts {
+		previous, ok := ContextOwnerOf(context)
+		current, owned := ContextOwnerOf(config.Contexts[name])
+		if !ok || !owned {
+			return false
+		}
+		previous.SyncedAt = current.SyncedAt
+		if setContextOwner(context, previous) != nil {
+			return false
+		}
+	}
+	return reflect.DeepEqual(saved, config)
+}
+
+// PreprocessKubeconfig appends the owner's server ID to all kubeconfig resources to avoid naming conflicts
+// and records the owner in each context's cowpoke extension.
+func (h *Handler) PreprocessKubeconfig(ctx context.Context, content []byte, owner domain.ContextOwner) ([]byte, error) {
+	config, err := h.preprocess(ctx, content, owner)
+	if err != nil {
+		return nil, err
+	}
+	return clientcmd.Write(*config)
+}
+
+// preprocess parses content and preprocesses it as PreprocessKubeconfig does, leaving serializing it to the
+// caller.
+func (h *Handler) preprocess(ctx context.Context, content []byte, owner domain.ContextOwner) (*api.Config, error) {
 	config, err := clientcmd.Load(content)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse kubeconfig: %w", err)
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidKubeconfig, err)
+	}
+
+	serverID := owner.ServerID
+	if owner.Version == "" {
+		owner.Version = h.version
 	}
 
 	h.logger.DebugContext(ctx, "Preprocessing kubeconfig to append server ID",
@@ -74,18 +243,79 @@ func (h *Handler) PreprocessKubeconfig(ctx context.Context, content []byte, serv
 		"contexts", len(config.Contexts),
 		"users", len(config.AuthInfos))
 
+	if owner.ClusterName != "" {
+		h.renameCluster(ctx, config, owner.ClusterName)
+	}
+	h.rewriteEndpoints(ctx, config)
+
 	// Rename resources and track mappings
 	clusterNameMap := h.renameClusters(ctx, config, serverID)
 	userNameMap := h.renameUsers(ctx, config, serverID)
 	contextNameMap := h.renameContexts(ctx, config, serverID, clusterNameMap, userNameMap)
 
+	for _, context := range config.Contexts {
+		if ownerErr := setContextOwner(context, owner); ownerErr != nil {
+			return nil, ownerErr
+		}
+	}
+
 	h.logger.DebugContext(ctx, "Kubeconfig preprocessing completed",
 		"server_id", serverID,
 		"renamed_clusters", len(clusterNameMap),
 		"renamed_users", len(userNameMap),
 		"renamed_contexts", len(contextNameMap))
 
-	return clientcmd.Write(*config)
+	return config, nil
+}
+
+// renameCluster replaces the cluster name Rancher used in the kubeconfig's resource names with name. Rancher
+// names the current context after the cluster, and any other resources after the cluster with a suffix,
+// e.g. "prod-node1" for a node's endpoint.
+func (h *Handler) renameCluster(ctx context.Context, config *api.Config, name string) {
+	base := config.CurrentContext
+	if base == "" && len(config.Contexts) == 1 {
+		for contextName := range config.Contexts {
+			base = contextName
+		}
+	}
+	if base == "" || base == name {
+		return
+	}
+
+	rename := func(old string) string {
+		if old == base {
+			return name
+		}
+		if suffix, ok := strings.CutPrefix(old, base+"-"); ok {
+			return h.naming.ClusterName(name + "-" + suffix)
+		}
+		return old
+	}
+	config.Clusters = renameKeys(config.Clusters, rename)
+	config.AuthInfos = renameKeys(config.AuthInfos, rename)
+	config.Contexts = renameKeys(config.Contexts, rename)
+	for _, context := range config.Contexts {
+		context.Cluster = rename(context.Cluster)
+		context.AuthInfo = rename(context.AuthInfo)
+	}
+	config.CurrentContext = rename(config.CurrentContext)
+
+	h.logger.DebugContext(ctx, "Renamed cluster resources", "old", base, "new", name)
+}
+
+// renameKeys returns m with its keys renamed by rename.
+func renameKeys[V any](m map[string]V, rename func(string) string) map[string]V {
+	renamed := make(map[string]V, len(m))
+	for key, value := range m {
+		renamed[rename(key)] = value
+	}
+	return renamed
+}
+
+// resourceName returns the sanitized name of a kubeconfig resource with the server ID appended. Names with
+// nothing usable in them are kept as they are.
+func (h *Handler) resourceName(name, serverID string) string {
+	return fmt.Sprintf("%s-%s", cmp.Or(h.naming.ClusterName(name), name), serverID)
 }
 
 // renameClusters renames all clusters by appending server ID and returns name mappings.
@@ -94,7 +324,7 @@ func (h *Handler) renameClusters(ctx context.Context, config *api.Config, server
 
 	config.Clusters = maps.Collect(func(yield func(string, *api.Cluster) bool) {
 		for oldName, cluster := range config.Clusters {
-			newName := fmt.Sprintf("%s-%s", oldName, serverID)
+			newName := h.resourceName(oldName, serverID)
 			clusterNameMap[oldName] = newName
 			h.logger.DebugContext(ctx, "Renamed cluster", "old", oldName, "new", newName)
 			if !yield(newName, cluster) {
@@ -112,7 +342,7 @@ func (h *Handler) renameUsers(ctx context.Context, config *api.Config, serverID
 
 	config.AuthInfos = maps.Collect(func(yield func(string, *api.AuthInfo) bool) {
 		for oldName, authInfo := range config.AuthInfos {
-			newName := fmt.Sprintf("%s-%s", oldName, serverID)
+			newName := h.resourceName(oldName, serverID)
 			userNameMap[oldName] = newName
 			h.logger.DebugContext(ctx, "Renamed user", "old", oldName, "new", newName)
 			if !yield(newName, authInfo) {
@@ -135,7 +365,7 @@ func (h *Handler) renameContexts(
 
 	config.Contexts = maps.Collect(func(yield func(string, *api.Context) bool) {
 		for oldName, context := range config.Contexts {
-			newName := fmt.Sprintf("%s-%s", oldName, serverID)
+			newName := h.resourceName(oldName, serverID)
 
 			// Update cluster reference
 			if newClusterName, exists := clusterNameMap[context.Cluster]; exists {
@@ -171,6 +401,99 @@ func (h *Handler) MergeKubeconfigs(
 		return errors.New("no kubeconfig paths provided for merging")
 	}
 
+	merged := h.takeCounted(paths, filter)
+	if merged == nil {
+		merged = h.merge(ctx, paths, filter)
+	}
+	mergedConfig, excludedContexts := merged.config, merged.excluded
+
+	if len(mergedConfig.Clusters) == 0 {
+		if excludedContexts > 0 {
+			h.logger.InfoContext(ctx, "All contexts excluded by filters",
+				"excluded_contexts", excludedContexts)
+			return errors.New("all clusters were excluded by filters - no kubeconfig to write")
+		}
+		return errors.New("no valid clusters found after filtering")
+	}
+
+	// Ensure output directory exists with the configured permissions
+	outputDir := filepath.Dir(outputPath)
+	if mkdirErr := h.fs.MkdirAll(outputDir, h.permissions.Dir()); mkdirErr != nil {
+		return fmt.Errorf("failed to create output directory: %w", mkdirErr)
+	}
+	if h.execCommand != "" {
+		replaced := h.useExecCredentials(ctx, mergedConfig)
+		h.logger.DebugContext(ctx, "Replaced tokens with exec credentials", "users", replaced)
+	}
+	if h.shareCAs {
+		if shareErr := h.shareCertificateAuthorities(ctx, mergedConfig, outputPath); shareErr != nil {
+			return shareErr
+		}
+	}
+
+	content, err := clientcmd.Write(*mergedConfig)
+	if err != nil {
+		return fmt.Errorf("failed to serialize merged kubeconfig: %w", err)
+	}
+	if existing, readErr := h.fs.ReadFile(outputPath); readErr == nil && bytes.Equal(existing, content) {
+		h.logger.InfoContext(ctx, "Merged kubeconfig unchanged",
+			"contexts", len(mergedConfig.Contexts),
+			"output", outputPath)
+		return h.applyPermissions(outputPath)
+	}
+	if writeErr := h.fs.WriteFile(outputPath, content, h.permissions.File()); writeErr != nil {
+		return fmt.Errorf("failed to write merged kubeconfig: %w", writeErr)
+	}
+	if permErr := h.applyPermissions(outputPath); permErr != nil {
+		return permErr
+	}
+
+	h.logger.InfoContext(ctx, "Merged kubeconfigs successfully",
+		"contexts", len(mergedConfig.Contexts),
+		"excluded", excludedContexts,
+		"output", outputPath)
+
+	return nil
+}
+
+// CountContexts returns how many contexts merging the kubeconfigs at paths under filter would write.
+// Kubeconfigs that cannot be loaded are skipped, as merging skips them. The merge is kept in memory, so
+// a MergeKubeconfigs call with the same paths and filter that follows writes it without loading the
+// kubeconfigs again.
+func (h *Handler) CountContexts(ctx context.Context, paths []string, filter domain.ClusterFilter) int {
+	merged := h.merge(ctx, paths, filter)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.counted = merged
+	return len(merged.config.Contexts)
+}
+
+// takeCounted returns the merge kept by CountContexts if it was built from paths and filter, and forgets
+// it either way.
+func (h *Handler) takeCounted(paths []string, filter domain.ClusterFilter) *mergeResult {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	counted := h.counted
+	h.counted = nil
+	if counted == nil || !slices.Equal(counted.paths, paths) || !sameFilter(counted.filter, filter) {
+		return nil
+	}
+	return counted
+}
+
+// sameFilter reports whether a and b are the same filter, without panicking on filters that cannot be
+// compared.
+func sameFilter(a, b domain.ClusterFilter) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return reflect.TypeOf(a).Comparable() && a == b
+}
+
+// merge loads the kubeconfigs at paths, filters and groups their contexts, and merges them in memory,
+// also counting the contexts the filter excluded.
+func (h *Handler) merge(ctx context.Context, paths []string, filter domain.ClusterFilter) *mergeResult {
 	h.logger.DebugContext(ctx, "Starting kubeconfig merge with filtering",
 		"input_count", len(paths),
 		"filter_type", fmt.Sprintf("%T", filter))
@@ -223,38 +546,36 @@ func (h *Handler) MergeKubeconfigs(
 			"excluded_contexts", originalContextCount-filteredContextCount)
 
 		// Merge filtered config into the accumulated result
+		h.groupContexts(filteredConfig)
 		h.mergeConfigInto(mergedConfig, filteredConfig)
 	}
-
-	if len(mergedConfig.Clusters) == 0 {
-		if excludedContexts > 0 {
-			h.logger.InfoContext(ctx, "All contexts excluded by filters",
-				"excluded_contexts", excludedContexts)
-			return errors.New("all clusters were excluded by filters - no kubeconfig to write")
-		}
-		return errors.New("no valid clusters found after filtering")
+	if replaced := h.dropSupersededContexts(ctx, mergedConfig); replaced > 0 {
+		h.logger.InfoContext(ctx, "Dropped contexts of renamed clusters", "contexts", replaced)
 	}
 
-	// Ensure output directory exists with secure permissions
-	outputDir := filepath.Dir(outputPath)
-	if mkdirErr := h.fs.MkdirAll(outputDir, dirPermissions); mkdirErr != nil {
-		return fmt.Errorf("failed to create output directory: %w", mkdirErr)
+	return &mergeResult{
+		paths:    slices.Clone(paths),
+		filter:   filter,
+		config:   mergedConfig,
+		excluded: excludedContexts,
 	}
+}
 
-	if writeErr := clientcmd.WriteToFile(*mergedConfig, outputPath); writeErr != nil {
-		return fmt.Errorf("failed to write merged kubeconfig: %w", writeErr)
+// applyPermissions gives a merged kubeconfig the configured mode and group, including one written before
+// the policy changed.
+func (h *Handler) applyPermissions(path string) error {
+	if err := h.fs.Chmod(path, h.permissions.File()); err != nil {
+		return fmt.Errorf("failed to set permissions on output file: %w", err)
 	}
-
-	// Ensure the output file has secure permissions
-	if chmodErr := h.fs.Chmod(outputPath, filePermissions); chmodErr != nil {
-		return fmt.Errorf("failed to set secure permissions on output file: %w", chmodErr)
+	gid, err := h.permissions.GroupID()
+	if err != nil {
+		return err
+	}
+	if gid >= 0 {
+		if chownErr := h.fs.Chown(path, -1, gid); chownErr != nil {
+			return fmt.Errorf("failed to set group %s on output file: %w", h.permissions.Group, chownErr)
+		}
 	}
-
-	h.logger.InfoContext(ctx, "Merged kubeconfigs successfully",
-		"contexts", len(mergedConfig.Contexts),
-		"excluded", excludedContexts,
-		"output", outputPath)
-
 	return nil
 }
 
@@ -272,6 +593,14 @@ func (h *Handler) loadAndFilterKubeconfig(
 		return nil, fmt.Errorf("failed to read kubeconfig file %s: %w", path, err)
 	}
 
+	// Decrypt in memory only; plaintext fragments are passed through unchanged.
+	if h.encryptor != nil {
+		data, err = h.encryptor.Decrypt(ctx, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt kubeconfig %s: %w", path, err)
+		}
+	}
+
 	// Parse the kubeconfig
 	config, err := clientcmd.Load(data)
 	if err != nil {
@@ -346,6 +675,22 @@ func (h *Handler) applyFilterToConfig(
 	return filteredConfig
 }
 
+// groupContexts prefixes the names of the contexts in config with the groups of the servers owning them.
+// Contexts merged before, e.g. those kept from an existing kubeconfig, already carry the prefix and keep it.
+func (h *Handler) groupContexts(config *api.Config) {
+	if len(h.groups) == 0 {
+		return
+	}
+	grouped := make(map[string]*api.Context, len(config.Contexts))
+	for name, kubeContext := range config.Contexts {
+		if owner, ok := ContextOwnerOf(kubeContext); ok {
+			name = h.naming.GroupedName(h.groups[owner.ServerID], name)
+		}
+		grouped[name] = kubeContext
+	}
+	config.Contexts = grouped
+}
+
 // mergeConfigInto merges the source config into the destination config.
 func (h *Handler) mergeConfigInto(dest, src *api.Config) {
 	// Merge clusters
@@ -0,0 +1,101 @@
+package kubeconfig
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"cowpoke/internal/adapters/filesystem"
+	"cowpoke/internal/domain"
+	"cowpoke/internal/services/filter"
+	"cowpoke/internal/testutil"
+)
+
+const (
+	// benchServers and benchClusters are the fleet merged by benchmarks: servers and clusters per server.
+	benchServers  = 50
+	benchClusters = 100
+	// benchNodes is the number of node endpoints in the kubeconfig of each cluster.
+	benchNodes = 3
+)
+
+func BenchmarkHandler_PreprocessKubeconfig(b *testing.B) {
+	for _, bench := range []struct {
+		name  string
+		nodes int
+	}{
+		{name: "cluster", nodes: benchNodes},
+		// Some 3 MB, as for a cluster exposing an endpoint for each of its 2000 nodes
+		{name: "multi-MB", nodes: 2000},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			handler, err := NewHandler(filesystem.New(), b.TempDir(), testutil.Logger())
+			if err != nil {
+				b.Fatal(err)
+			}
+			content := testutil.RancherKubeconfig("cluster", bench.nodes)
+			owner := domain.ContextOwner{ServerURL: "https://rancher.example.com", ServerID: "abc12345"}
+			ctx := context.Background()
+			b.SetBytes(int64(len(content)))
+
+			for b.Loop() {
+				if _, err := handler.PreprocessKubeconfig(ctx, content, owner); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkHandler_SaveKubeconfig saves a multi-MB kubeconfig over an unchanged fragment, as most syncs do.
+func BenchmarkHandler_SaveKubeconfig(b *testing.B) {
+	dir := b.TempDir()
+	handler, err := NewHandler(filesystem.New(), dir, testutil.Logger())
+	if err != nil {
+		b.Fatal(err)
+	}
+	content := testutil.RancherKubeconfig("cluster", 2000)
+	owner := domain.ContextOwner{ServerURL: "https://rancher.example.com", ServerID: "abc12345"}
+	path := filepath.Join(dir, domain.FragmentFileName("cluster", owner.ServerID))
+	ctx := context.Background()
+	b.SetBytes(int64(len(content)))
+
+	for b.Loop() {
+		if err := handler.SaveKubeconfig(ctx, path, content, owner); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkHandler_MergeKubeconfigs(b *testing.B) {
+	dir := b.TempDir()
+	handler, err := NewHandler(filesystem.New(), dir, testutil.Logger())
+	if err != nil {
+		b.Fatal(err)
+	}
+	ctx := context.Background()
+	paths := make([]string, 0, benchServers*benchClusters)
+	for server := range benchServers {
+		owner := domain.ContextOwner{
+			ServerURL: fmt.Sprintf("https://rancher-%02d.example.com", server),
+			ServerID:  fmt.Sprintf("%08x", server),
+		}
+		for cluster := range benchClusters {
+			name := fmt.Sprintf("cluster-%03d", cluster)
+			path := filepath.Join(dir, domain.FragmentFileName(name, owner.ServerID))
+			if err := handler.SaveKubeconfig(ctx, path, testutil.RancherKubeconfig(name, benchNodes), owner); err != nil {
+				b.Fatal(err)
+			}
+			paths = append(paths, path)
+		}
+	}
+	outputPath := filepath.Join(dir, "merged", "config")
+	noFilter := filter.NewNoOpFilter()
+
+	for b.Loop() {
+		if err := handler.MergeKubeconfigs(ctx, paths, outputPath, noFilter); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -2,12 +2,18 @@ package kubeconfig
 
 import (
 	"context"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"cowpoke/internal/adapters/filesystem"
 	"cowpoke/internal/domain"
+	"cowpoke/internal/services/encryption"
 	"cowpoke/internal/services/filter"
 	"cowpoke/internal/testutil"
 
@@ -517,3 +523,1157 @@ users:
 	assert.Contains(t, config.Contexts, "mgmt-context")
 	assert.Contains(t, config.Contexts, "app-context")
 }
+
+func TestHandler_SaveKubeconfig_EncryptsFragmentAndMergeDecrypts(t *testing.T) {
+	tempDir := t.TempDir()
+
+	kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://app.example.com
+  name: app
+contexts:
+- context:
+    cluster: app
+    user: app
+  name: app
+users:
+- name: app
+  user:
+    token: super-secret-token`
+
+	cipher := encryption.NewCipher(func(_ context.Context, _ bool) ([]byte, error) {
+		return make([]byte, encryption.KeySize), nil
+	})
+	handler, err := NewHandler(filesystem.New(), tempDir, testutil.Logger(), WithEncryptor(cipher))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	fragmentPath := filepath.Join(tempDir, "app-abc12345.yaml")
+	owner := domain.ContextOwner{ServerURL: "https://rancher.example.com", ServerID: "abc12345"}
+	require.NoError(t, handler.SaveKubeconfig(ctx, fragmentPath, []byte(kubeconfig), owner))
+
+	// The fragment on disk must not contain the token in plain text.
+	onDisk, readErr := os.ReadFile(fragmentPath)
+	require.NoError(t, readErr)
+	assert.True(t, encryption.IsEncrypted(onDisk))
+	assert.NotContains(t, string(onDisk), "super-secret-token")
+
+	outputPath := filepath.Join(tempDir, "merged.yaml")
+	require.NoError(t, handler.MergeKubeconfigs(ctx, []string{fragmentPath}, outputPath, filter.NewNoOpFilter()))
+
+	config, loadErr := clientcmd.LoadFromFile(outputPath)
+	require.NoError(t, loadErr)
+	assert.Contains(t, config.Contexts, "app-abc12345")
+	assert.Equal(t, "super-secret-token", config.AuthInfos["app-abc12345"].Token)
+}
+
+func TestHandler_SaveKubeconfig_RecordsContextOwner(t *testing.T) {
+	tempDir := t.TempDir()
+
+	kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://app.example.com
+  name: app
+contexts:
+- context:
+    cluster: app
+    user: app
+  name: app
+users:
+- name: app
+  user:
+    token: token`
+
+	handler, err := NewHandler(filesystem.New(), tempDir, testutil.Logger(), WithVersion("v1.2.3"))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	syncedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	owner := domain.ContextOwner{
+		ServerURL: "https://rancher.example.com",
+		ServerID:  "abc12345",
+		ClusterID: "c-m-xyz",
+		SyncedAt:  syncedAt,
+	}
+	fragmentPath := filepath.Join(tempDir, "app-abc12345.yaml")
+	require.NoError(t, handler.SaveKubeconfig(ctx, fragmentPath, []byte(kubeconfig), owner))
+
+	outputPath := filepath.Join(tempDir, "merged.yaml")
+	require.NoError(t, handler.MergeKubeconfigs(ctx, []string{fragmentPath}, outputPath, filter.NewNoOpFilter()))
+
+	config, loadErr := clientcmd.LoadFromFile(outputPath)
+	require.NoError(t, loadErr)
+
+	got, ok := ContextOwnerOf(config.Contexts["app-abc12345"])
+	require.True(t, ok)
+	owner.Version = "v1.2.3"
+	assert.Equal(t, owner, got)
+
+	_, ok = ContextOwnerOf(clientcmdapi.NewContext())
+	assert.False(t, ok)
+}
+
+func TestHandler_PreprocessKubeconfig_RenamesCluster(t *testing.T) {
+	kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://rancher.example.com/k8s/clusters/c-m-xyz
+  name: c-m-xyz
+- cluster:
+    server: https://node1.example.com:6443
+  name: c-m-xyz-node1
+contexts:
+- context:
+    cluster: c-m-xyz
+    user: c-m-xyz
+  name: c-m-xyz
+- context:
+    cluster: c-m-xyz-node1
+    user: c-m-xyz
+  name: c-m-xyz-node1
+current-context: c-m-xyz
+users:
+- name: c-m-xyz
+  user:
+    token: token`
+
+	handler, err := NewHandler(filesystem.New(), t.TempDir(), testutil.Logger())
+	require.NoError(t, err)
+
+	processed, err := handler.PreprocessKubeconfig(context.Background(), []byte(kubeconfig), domain.ContextOwner{
+		ServerID:    "abc12345",
+		ClusterID:   "c-m-xyz",
+		ClusterName: "prod-eu",
+	})
+	require.NoError(t, err)
+
+	config, err := clientcmd.Load(processed)
+	require.NoError(t, err)
+	renamed := []string{"prod-eu-abc12345", "prod-eu-node1-abc12345"}
+	assert.ElementsMatch(t, renamed, slices.Collect(maps.Keys(config.Contexts)))
+	assert.ElementsMatch(t, renamed, slices.Collect(maps.Keys(config.Clusters)))
+	assert.ElementsMatch(t, []string{"prod-eu-abc12345"}, slices.Collect(maps.Keys(config.AuthInfos)))
+	node := config.Contexts["prod-eu-node1-abc12345"]
+	assert.Equal(t, "prod-eu-node1-abc12345", node.Cluster)
+	assert.Equal(t, "prod-eu-abc12345", node.AuthInfo)
+}
+
+func TestHandler_PreprocessKubeconfig_RewritesEndpoints(t *testing.T) {
+	kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://rancher.example.com/k8s/clusters/c-m-xyz
+  name: prod
+- cluster:
+    server: https://prod.internal.example.com:6443
+  name: prod-fqdn
+contexts:
+- context:
+    cluster: prod
+    user: prod
+  name: prod
+- context:
+    cluster: prod-fqdn
+    user: prod
+  name: prod-fqdn
+current-context: prod
+users:
+- name: prod
+  user:
+    token: token`
+
+	handler, err := NewHandler(filesystem.New(), t.TempDir(), testutil.Logger(), WithEndpointRewrites(
+		[]domain.EndpointRewrite{
+			{Match: `^https://([a-z0-9-]+)\.internal\.example\.com`, Replace: "https://$1.vpn.example.com"},
+			{Match: `internal`, Replace: "unused"},
+		}))
+	require.NoError(t, err)
+
+	processed, err := handler.PreprocessKubeconfig(context.Background(), []byte(kubeconfig),
+		domain.ContextOwner{ServerID: "abc12345", ClusterID: "c-m-xyz"})
+	require.NoError(t, err)
+
+	config, err := clientcmd.Load(processed)
+	require.NoError(t, err)
+	assert.Equal(t, "https://rancher.example.com/k8s/clusters/c-m-xyz", config.Clusters["prod-abc12345"].Server)
+	assert.Equal(t, "https://prod.vpn.example.com:6443", config.Clusters["prod-fqdn-abc12345"].Server,
+		"only the first matching rewrite applies")
+
+	_, err = NewHandler(filesystem.New(), t.TempDir(), testutil.Logger(),
+		WithEndpointRewrites([]domain.EndpointRewrite{{Match: "(unclosed"}}))
+	require.ErrorContains(t, err, `invalid endpoint rewrite pattern "(unclosed"`)
+}
+
+func TestHandler_PreprocessKubeconfig_SanitizesNames(t *testing.T) {
+	kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://rancher.example.com/k8s/clusters/c-m-xyz
+  name: Prod EU/1
+contexts:
+- context:
+    cluster: Prod EU/1
+    user: Prod EU/1
+  name: Prod EU/1
+users:
+- name: Prod EU/1
+  user:
+    token: token`
+
+	tests := []struct {
+		name     string
+		naming   domain.NamingSettings
+		expected string
+	}{
+		{name: "default policy", expected: "Prod-EU-1-abc12345"},
+		{name: "replacement", naming: domain.NamingSettings{Replacement: "_"}, expected: "Prod_EU_1-abc12345"},
+		{name: "lowercase", naming: domain.NamingSettings{Lowercase: true}, expected: "prod-eu-1-abc12345"},
+		{name: "max length", naming: domain.NamingSettings{MaxLength: 14}, expected: "Prod-abc12345"},
+		{
+			name:     "rfc1123 with replacement it does not allow",
+			naming:   domain.NamingSettings{RFC1123: true, Replacement: "_"},
+			expected: "prod-eu-1-abc12345",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, err := NewHandler(filesystem.New(), t.TempDir(), testutil.Logger(), WithNaming(tt.naming))
+			require.NoError(t, err)
+
+			processed, err := handler.PreprocessKubeconfig(context.Background(), []byte(kubeconfig),
+				domain.ContextOwner{ServerID: "abc12345", ClusterID: "c-m-xyz"})
+			require.NoError(t, err)
+
+			config, err := clientcmd.Load(processed)
+			require.NoError(t, err)
+			require.Contains(t, config.Contexts, tt.expected)
+			assert.Equal(t, tt.expected, config.Contexts[tt.expected].Cluster)
+			assert.Equal(t, tt.expected, config.Contexts[tt.expected].AuthInfo)
+		})
+	}
+}
+
+func TestHandler_SaveKubeconfig_SkipsUnchangedFragment(t *testing.T) {
+	tempDir := t.TempDir()
+
+	kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://app.example.com
+  name: app
+contexts:
+- context:
+    cluster: app
+    user: app
+  name: app
+users:
+- name: app
+  user:
+    token: token`
+
+	handler, err := NewHandler(filesystem.New(), tempDir, testutil.Logger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	fragmentPath := filepath.Join(tempDir, "app-abc12345.yaml")
+	firstSync := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	owner := domain.ContextOwner{ServerURL: "https://rancher.example.com", ServerID: "abc12345", SyncedAt: firstSync}
+	require.NoError(t, handler.SaveKubeconfig(ctx, fragmentPath, []byte(kubeconfig), owner))
+	original, readErr := os.ReadFile(fragmentPath)
+	require.NoError(t, readErr)
+
+	stale := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, os.Chtimes(fragmentPath, stale, stale))
+
+	// An identical download only refreshes the modification time.
+	owner.SyncedAt = firstSync.Add(time.Hour)
+	require.NoError(t, handler.SaveKubeconfig(ctx, fragmentPath, []byte(kubeconfig), owner))
+
+	unchanged, readErr := os.ReadFile(fragmentPath)
+	require.NoError(t, readErr)
+	assert.Equal(t, original, unchanged)
+	info, statErr := os.Stat(fragmentPath)
+	require.NoError(t, statErr)
+	assert.True(t, info.ModTime().After(stale.Add(time.Hour)))
+
+	// A changed download is written with the new sync time.
+	rotated := strings.Replace(kubeconfig, "token: token", "token: rotated", 1)
+	require.NoError(t, handler.SaveKubeconfig(ctx, fragmentPath, []byte(rotated), owner))
+
+	config, loadErr := clientcmd.LoadFromFile(fragmentPath)
+	require.NoError(t, loadErr)
+	assert.Equal(t, "rotated", config.AuthInfos["app-abc12345"].Token)
+	got, ok := ContextOwnerOf(config.Contexts["app-abc12345"])
+	require.True(t, ok)
+	assert.Equal(t, owner.SyncedAt, got.SyncedAt)
+}
+
+func TestHandler_MergeKubeconfigs_SkipsUnchangedOutput(t *testing.T) {
+	tempDir := t.TempDir()
+
+	kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://app.example.com
+  name: app
+contexts:
+- context:
+    cluster: app
+    user: app
+  name: app
+users:
+- name: app
+  user:
+    token: token`
+
+	handler, err := NewHandler(filesystem.New(), tempDir, testutil.Logger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	fragmentPath := filepath.Join(tempDir, "app-abc12345.yaml")
+	owner := domain.ContextOwner{ServerURL: "https://rancher.example.com", ServerID: "abc12345"}
+	require.NoError(t, handler.SaveKubeconfig(ctx, fragmentPath, []byte(kubeconfig), owner))
+
+	outputPath := filepath.Join(tempDir, "merged.yaml")
+	require.NoError(t, handler.MergeKubeconfigs(ctx, []string{fragmentPath}, outputPath, filter.NewNoOpFilter()))
+
+	stale := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, os.Chtimes(outputPath, stale, stale))
+
+	require.NoError(t, handler.MergeKubeconfigs(ctx, []string{fragmentPath}, outputPath, filter.NewNoOpFilter()))
+
+	info, statErr := os.Stat(outputPath)
+	require.NoError(t, statErr)
+	assert.True(t, info.ModTime().Equal(stale), "unchanged merged kubeconfig must not be rewritten")
+}
+
+// writeCountingFS counts the files written through it.
+type writeCountingFS struct {
+	*filesystem.Adapter
+	writes int
+}
+
+func (fs *writeCountingFS) WriteFile(path string, data []byte, perm os.FileMode) error {
+	fs.writes++
+	return fs.Adapter.WriteFile(path, data, perm)
+}
+
+func TestHandler_IdenticalSyncsWriteNothing(t *testing.T) {
+	// Arrange
+	tempDir := t.TempDir()
+	kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://app.example.com
+  name: app
+contexts:
+- context:
+    cluster: app
+    user: app
+  name: app
+users:
+- name: app
+  user:
+    token: token`
+	fs := &writeCountingFS{Adapter: filesystem.New()}
+	handler, err := NewHandler(fs, tempDir, testutil.Logger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	fragmentPath := filepath.Join(tempDir, "app-abc12345.yaml")
+	outputPath := filepath.Join(tempDir, "merged.yaml")
+	firstSync := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	owner := domain.ContextOwner{ServerURL: "https://rancher.example.com", ServerID: "abc12345", SyncedAt: firstSync}
+	runSync := func() {
+		require.NoError(t, handler.SaveKubeconfig(ctx, fragmentPath, []byte(kubeconfig), owner))
+		require.NoError(t, handler.MergeKubeconfigs(ctx, []string{fragmentPath}, outputPath, filter.NewNoOpFilter()))
+	}
+	runSync()
+	writes := fs.writes
+
+	// Act
+	owner.SyncedAt = firstSync.Add(time.Hour)
+	runSync()
+
+	// Assert
+	assert.Equal(t, 2, writes)
+	assert.Equal(t, writes, fs.writes, "identical sync must not write the fragment or the merged kubeconfig")
+}
+
+func TestHandler_MergeKubeconfigs_SharesCAs(t *testing.T) {
+	tempDir := t.TempDir()
+
+	kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    certificate-authority-data: c2hhcmVkLWNh
+    server: https://rancher.example.com/k8s/clusters/c-m-prod
+  name: prod
+- cluster:
+    certificate-authority-data: c2hhcmVkLWNh
+    server: https://prod.internal.example.com:6443
+  name: prod-fqdn
+- cluster:
+    certificate-authority-data: dW5pcXVlLWNh
+    server: https://stage.internal.example.com:6443
+  name: stage-fqdn
+contexts:
+- context:
+    cluster: prod
+    user: prod
+  name: prod
+- context:
+    cluster: prod-fqdn
+    user: prod
+  name: prod-fqdn
+- context:
+    cluster: stage-fqdn
+    user: prod
+  name: stage-fqdn
+users:
+- name: prod
+  user:
+    token: token`
+
+	handler, err := NewHandler(filesystem.New(), tempDir, testutil.Logger(), WithSharedCAs(true))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	fragmentPath := filepath.Join(tempDir, "prod-abc12345.yaml")
+	owner := domain.ContextOwner{ServerURL: "https://rancher.example.com", ServerID: "abc12345"}
+	require.NoError(t, handler.SaveKubeconfig(ctx, fragmentPath, []byte(kubeconfig), owner))
+
+	caDir := filepath.Join(tempDir, "merged.yaml-ca")
+	stalePath := filepath.Join(caDir, "0000000000000000.crt")
+	require.NoError(t, os.MkdirAll(caDir, 0o700))
+	require.NoError(t, os.WriteFile(stalePath, []byte("old-ca"), 0o600))
+
+	outputPath := filepath.Join(tempDir, "merged.yaml")
+	require.NoError(t, handler.MergeKubeconfigs(ctx, []string{fragmentPath}, outputPath, filter.NewNoOpFilter()))
+
+	merged, err := clientcmd.LoadFromFile(outputPath)
+	require.NoError(t, err)
+	prod, prodFQDN := merged.Clusters["prod-abc12345"], merged.Clusters["prod-fqdn-abc12345"]
+	assert.Empty(t, prod.CertificateAuthorityData)
+	assert.Equal(t, filepath.Dir(prod.CertificateAuthority), caDir)
+	assert.Equal(t, prod.CertificateAuthority, prodFQDN.CertificateAuthority, "identical CAs share one file")
+	shared, err := os.ReadFile(prod.CertificateAuthority)
+	require.NoError(t, err)
+	assert.Equal(t, "shared-ca", string(shared))
+	assert.Equal(t, "unique-ca", string(merged.Clusters["stage-fqdn-abc12345"].CertificateAuthorityData),
+		"a CA used by a single cluster stays embedded")
+	assert.NoFileExists(t, stalePath, "unreferenced CA files are removed")
+}
+
+func TestHandler_MergeKubeconfigs_ExecCredentials(t *testing.T) {
+	tempDir := t.TempDir()
+	handler, err := NewHandler(filesystem.New(), tempDir, testutil.Logger(),
+		WithExecCredentials("cowpoke", "--profile", "team-a"))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	fragmentPath := filepath.Join(tempDir, "prod-abc12345.yaml")
+	owner := domain.ContextOwner{ServerURL: "https://rancher.example.com", ServerID: "abc12345", ClusterID: "c-m-prod"}
+	require.NoError(t, handler.SaveKubeconfig(ctx, fragmentPath, testutil.RancherKubeconfig("prod", 1), owner))
+
+	outputPath := filepath.Join(tempDir, "merged.yaml")
+	require.NoError(t, handler.MergeKubeconfigs(ctx, []string{fragmentPath}, outputPath, filter.NewNoOpFilter()))
+
+	merged, err := clientcmd.LoadFromFile(outputPath)
+	require.NoError(t, err)
+	require.Len(t, merged.AuthInfos, 1)
+	user := merged.AuthInfos["prod-abc12345"]
+	require.NotNil(t, user)
+	assert.Empty(t, user.Token, "tokens are not written to the merged kubeconfig")
+	require.NotNil(t, user.Exec)
+	assert.Equal(t, "client.authentication.k8s.io/v1", user.Exec.APIVersion)
+	assert.Equal(t, "cowpoke", user.Exec.Command)
+	assert.Equal(t, []string{"--profile", "team-a", "credential", "--server", "abc12345", "--cluster", "c-m-prod"},
+		user.Exec.Args)
+
+	fragment, err := clientcmd.LoadFromFile(fragmentPath)
+	require.NoError(t, err)
+	assert.NotEmpty(t, fragment.AuthInfos["prod-abc12345"].Token, "the cached fragment keeps its token")
+}
+
+func TestHandler_MergeKubeconfigs_DropsContextsOfRenamedClusters(t *testing.T) {
+	tempDir := t.TempDir()
+	kubeconfig := func(name string) string {
+		return `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://rancher.example.com/k8s/clusters/c-m-api
+  name: ` + name + `
+contexts:
+- context:
+    cluster: ` + name + `
+    user: ` + name + `
+  name: ` + name + `
+users:
+- name: ` + name + `
+  user:
+    token: token`
+	}
+
+	handler, err := NewHandler(filesystem.New(), tempDir, testutil.Logger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	synced := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	owner := domain.ContextOwner{ServerURL: "https://rancher.example.com", ServerID: "abc12345", ClusterID: "c-m-api"}
+	oldPath := filepath.Join(tempDir, "api-abc12345.yaml")
+	owner.SyncedAt = synced.Add(-24 * time.Hour)
+	require.NoError(t, handler.SaveKubeconfig(ctx, oldPath, []byte(kubeconfig("api")), owner))
+	newPath := filepath.Join(tempDir, "gateway-abc12345.yaml")
+	owner.SyncedAt = synced
+	require.NoError(t, handler.SaveKubeconfig(ctx, newPath, []byte(kubeconfig("gateway")), owner))
+
+	// The renamed cluster's new kubeconfig wins whichever order the kubeconfigs are merged in
+	for _, paths := range [][]string{{oldPath, newPath}, {newPath, oldPath}} {
+		outputPath := filepath.Join(tempDir, "merged.yaml")
+		require.NoError(t, handler.MergeKubeconfigs(ctx, paths, outputPath, filter.NewNoOpFilter()))
+
+		merged, loadErr := clientcmd.LoadFromFile(outputPath)
+		require.NoError(t, loadErr)
+		assert.Equal(t, []string{"gateway-abc12345"}, slices.Collect(maps.Keys(merged.Contexts)))
+		assert.Equal(t, []string{"gateway-abc12345"}, slices.Collect(maps.Keys(merged.Clusters)))
+		assert.Equal(t, []string{"gateway-abc12345"}, slices.Collect(maps.Keys(merged.AuthInfos)))
+	}
+}
+
+func TestHandler_MergeKubeconfigs_GroupsContexts(t *testing.T) {
+	// Arrange
+	tempDir := t.TempDir()
+	kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://app.example.com
+  name: app
+contexts:
+- context:
+    cluster: app
+    user: app
+  name: app
+users:
+- name: app
+  user:
+    token: token`
+
+	naming := domain.NamingSettings{Grouping: domain.GroupingSettings{By: domain.GroupByTag, Separator: ":"}}
+	prod := domain.ConfigServer{URL: "https://rancher.example.com", Tags: []string{"Prod EU", "eu"}}
+	lab := domain.ConfigServer{URL: "https://lab.example.com"}
+	handler, err := NewHandler(filesystem.New(), tempDir, testutil.Logger(), WithNaming(naming),
+		WithGroups(map[string]string{prod.ID(): naming.Group(prod), lab.ID(): naming.Group(lab)}))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	var fragments []string
+	for _, server := range []domain.ConfigServer{prod, lab} {
+		path := filepath.Join(tempDir, domain.FragmentFileName("app", server.ID()))
+		owner := domain.ContextOwner{ServerURL: server.URL, ServerID: server.ID()}
+		require.NoError(t, handler.SaveKubeconfig(ctx, path, []byte(kubeconfig), owner))
+		fragments = append(fragments, path)
+	}
+	outputPath := filepath.Join(tempDir, "merged.yaml")
+
+	// Act
+	require.NoError(t, handler.MergeKubeconfigs(ctx, fragments, outputPath, filter.NewNoOpFilter()))
+	// Merging the output again, as keeping existing contexts does, must not prefix the contexts twice
+	err = handler.MergeKubeconfigs(ctx, append([]string{outputPath}, fragments...), outputPath,
+		filter.NewNoOpFilter())
+
+	// Assert
+	require.NoError(t, err)
+	merged, loadErr := clientcmd.LoadFromFile(outputPath)
+	require.NoError(t, loadErr)
+	assert.ElementsMatch(t, []string{"Prod-EU:app-" + prod.ID(), "app-" + lab.ID()},
+		slices.Collect(maps.Keys(merged.Contexts)))
+	assert.Equal(t, "app-"+prod.ID(), merged.Contexts["Prod-EU:app-"+prod.ID()].Cluster)
+}
+
+func TestHandler_CountContexts(t *testing.T) {
+	// Arrange
+	tempDir := t.TempDir()
+	kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://app.example.com
+  name: app
+- cluster:
+    server: https://test.example.com
+  name: test
+contexts:
+- context:
+    cluster: app
+    user: app
+  name: app
+- context:
+    cluster: test
+    user: app
+  name: test
+users:
+- name: app
+  user:
+    token: token`
+
+	handler, err := NewHandler(filesystem.New(), tempDir, testutil.Logger())
+	require.NoError(t, err)
+	ctx := context.Background()
+	fragmentPath := filepath.Join(tempDir, "app-abc12345.yaml")
+	owner := domain.ContextOwner{ServerURL: "https://rancher.example.com", ServerID: "abc12345"}
+	require.NoError(t, handler.SaveKubeconfig(ctx, fragmentPath, []byte(kubeconfig), owner))
+	outputPath := filepath.Join(tempDir, "merged.yaml")
+	require.NoError(t, handler.MergeKubeconfigs(ctx, []string{fragmentPath}, outputPath, filter.NewNoOpFilter()))
+	testFilter, err := filter.NewExcludeFilter([]string{"^test"}, testutil.Logger())
+	require.NoError(t, err)
+
+	// Act
+	all := handler.CountContexts(ctx, []string{outputPath, fragmentPath, filepath.Join(tempDir, "missing.yaml")},
+		filter.NewNoOpFilter())
+	filtered := handler.CountContexts(ctx, []string{fragmentPath}, testFilter)
+
+	// Assert
+	assert.Equal(t, 2, all, "contexts in several kubeconfigs are counted once")
+	assert.Equal(t, 1, filtered)
+}
+
+func TestHandler_MergeKubeconfigs_ReusesCountedMerge(t *testing.T) {
+	// Arrange
+	tempDir := t.TempDir()
+	handler, err := NewHandler(filesystem.New(), tempDir, testutil.Logger())
+	require.NoError(t, err)
+	ctx := context.Background()
+	fragmentPath := filepath.Join(tempDir, "app-abc12345.yaml")
+	owner := domain.ContextOwner{ServerURL: "https://rancher.example.com", ServerID: "abc12345"}
+	require.NoError(t, handler.SaveKubeconfig(ctx, fragmentPath, testutil.RancherKubeconfig("app", 1), owner))
+	noOp := filter.NewNoOpFilter()
+	outputPath := filepath.Join(tempDir, "merged.yaml")
+
+	// Act
+	count := handler.CountContexts(ctx, []string{fragmentPath}, noOp)
+	require.NoError(t, os.Remove(fragmentPath))
+	mergeErr := handler.MergeKubeconfigs(ctx, []string{fragmentPath}, outputPath, noOp)
+	againErr := handler.MergeKubeconfigs(ctx, []string{fragmentPath}, outputPath, noOp)
+
+	// Assert
+	assert.Equal(t, 2, count)
+	require.NoError(t, mergeErr, "the counted merge is written without loading the fragment again")
+	merged, err := clientcmd.LoadFromFile(outputPath)
+	require.NoError(t, err)
+	assert.Len(t, merged.Contexts, 2)
+	assert.Error(t, againErr, "the counted merge is only reused once")
+}
+
+func TestHandler_RenameServer(t *testing.T) {
+	tempDir := t.TempDir()
+	from := domain.ConfigServer{URL: "https://old.example.com"}
+	to := domain.ConfigServer{URL: "https://new.example.com"}
+	oldID, newID := from.ID(), to.ID()
+
+	kubeconfig := `apiVersion: v1
+kind: Config
+current-context: prod-` + oldID + `
+clusters:
+- cluster:
+    server: https://prod.example.com
+  name: prod-` + oldID + `
+- cluster:
+    server: https://legacy.example.com
+  name: legacy-` + oldID + `
+- cluster:
+    server: https://other.example.com
+  name: other-abcdef12
+contexts:
+- context:
+    cluster: prod-` + oldID + `
+    user: prod-` + oldID + `
+    extensions:
+    - name: cowpoke
+      extension:
+        serverUrl: https://old.example.com
+        serverId: ` + oldID + `
+        clusterId: c-1
+  name: prod-` + oldID + `
+- context:
+    cluster: legacy-` + oldID + `
+    user: legacy-` + oldID + `
+  name: legacy-` + oldID + `
+- context:
+    cluster: other-abcdef12
+    user: other-abcdef12
+  name: other-abcdef12
+users:
+- name: prod-` + oldID + `
+  user:
+    token: prod
+- name: legacy-` + oldID + `
+  user:
+    token: legacy
+- name: other-abcdef12
+  user:
+    token: other`
+
+	path := filepath.Join(tempDir, "config")
+	require.NoError(t, os.WriteFile(path, []byte(kubeconfig), 0o600))
+
+	handler, err := NewHandler(filesystem.New(), tempDir, testutil.Logger())
+	require.NoError(t, err)
+
+	// Act
+	renamed, err := handler.RenameServer(context.Background(), path, from, to)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, renamed)
+
+	config, loadErr := clientcmd.LoadFromFile(path)
+	require.NoError(t, loadErr)
+	assert.Equal(t, "prod-"+newID, config.CurrentContext)
+	expectedNames := []string{"prod-" + newID, "legacy-" + newID, "other-abcdef12"}
+	assert.ElementsMatch(t, expectedNames, slices.Collect(maps.Keys(config.Contexts)))
+	assert.ElementsMatch(t, expectedNames, slices.Collect(maps.Keys(config.Clusters)))
+	assert.Equal(t, "legacy", config.AuthInfos["legacy-"+newID].Token)
+	assert.Equal(t, "prod-"+newID, config.Contexts["prod-"+newID].Cluster)
+
+	owner, ok := ContextOwnerOf(config.Contexts["prod-"+newID])
+	require.True(t, ok)
+	assert.Equal(t, "https://new.example.com", owner.ServerURL)
+	assert.Equal(t, newID, owner.ServerID)
+	assert.Equal(t, "c-1", owner.ClusterID)
+}
+
+func TestHandler_RenameServer_MissingKubeconfig(t *testing.T) {
+	handler, err := NewHandler(filesystem.New(), t.TempDir(), testutil.Logger())
+	require.NoError(t, err)
+
+	renamed, err := handler.RenameServer(context.Background(), filepath.Join(t.TempDir(), "config"),
+		domain.ConfigServer{URL: "https://old.example.com"}, domain.ConfigServer{URL: "https://new.example.com"})
+
+	require.NoError(t, err)
+	assert.Zero(t, renamed)
+}
+
+func TestHandler_SnapshotContexts(t *testing.T) {
+	// Arrange
+	tempDir := t.TempDir()
+	kubeconfig := func(token string) string {
+		return `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://prod.example.com
+  name: prod
+- cluster:
+    server: https://dev.example.com
+  name: dev
+contexts:
+- context:
+    cluster: prod
+    user: prod
+  name: prod
+- context:
+    cluster: dev
+    user: dev
+  name: dev
+users:
+- name: prod
+  user:
+    token: ` + token + `
+- name: dev
+  user:
+    token: dev`
+	}
+	before := filepath.Join(tempDir, "before")
+	after := filepath.Join(tempDir, "after")
+	require.NoError(t, os.WriteFile(before, []byte(kubeconfig("old")), 0o600))
+	require.NoError(t, os.WriteFile(after, []byte(kubeconfig("new")), 0o600))
+
+	handler, err := NewHandler(filesystem.New(), tempDir, testutil.Logger())
+	require.NoError(t, err)
+
+	// Act
+	oldSnapshots, oldErr := handler.SnapshotContexts(context.Background(), before)
+	newSnapshots, newErr := handler.SnapshotContexts(context.Background(), after)
+	missing, missingErr := handler.SnapshotContexts(context.Background(), filepath.Join(tempDir, "missing"))
+
+	// Assert
+	require.NoError(t, oldErr)
+	require.NoError(t, newErr)
+	require.Len(t, oldSnapshots, 2)
+	require.Len(t, newSnapshots, 2)
+	assert.Equal(t, "dev", oldSnapshots[0].Name)
+	assert.Equal(t, "https://prod.example.com", oldSnapshots[1].Endpoint)
+	assert.Equal(t, oldSnapshots[0], newSnapshots[0])
+	assert.NotEqual(t, oldSnapshots[1].Credential, newSnapshots[1].Credential)
+	assert.NotContains(t, oldSnapshots[1].Credential, "old")
+	require.NoError(t, missingErr)
+	assert.Empty(t, missing)
+}
+
+func TestHandler_ListContextsAndUseContext(t *testing.T) {
+	// Arrange
+	tempDir := t.TempDir()
+	server := domain.ConfigServer{URL: "https://rancher.example.com"}
+	serverID := server.ID()
+
+	kubeconfig := `apiVersion: v1
+kind: Config
+current-context: manual
+clusters:
+- cluster:
+    server: https://prod.example.com
+  name: prod-` + serverID + `
+- cluster:
+    server: https://manual.example.com
+  name: manual
+contexts:
+- context:
+    cluster: prod-` + serverID + `
+    user: prod-` + serverID + `
+    extensions:
+    - name: cowpoke
+      extension:
+        serverUrl: https://rancher.example.com
+        serverId: ` + serverID + `
+        clusterId: c-1
+  name: prod-` + serverID + `
+- context:
+    cluster: manual
+    user: manual
+  name: manual
+users:
+- name: prod-` + serverID + `
+  user:
+    token: prod
+- name: manual
+  user:
+    token: manual`
+
+	path := filepath.Join(tempDir, "config")
+	require.NoError(t, os.WriteFile(path, []byte(kubeconfig), 0o600))
+
+	handler, err := NewHandler(filesystem.New(), tempDir, testutil.Logger())
+	require.NoError(t, err)
+
+	// Act
+	contexts, listErr := handler.ListContexts(context.Background(), path)
+	foreign, foreignErr := handler.ForeignContexts(context.Background(), path)
+	useErr := handler.UseContext(context.Background(), path, "prod-"+serverID)
+
+	// Assert
+	require.NoError(t, listErr)
+	require.Len(t, contexts, 1)
+	assert.Equal(t, "prod-"+serverID, contexts[0].Name)
+	assert.Equal(t, "prod", contexts[0].ClusterName)
+	assert.Equal(t, "c-1", contexts[0].Owner.ClusterID)
+	assert.Equal(t, "https://prod.example.com", contexts[0].Endpoint)
+	assert.False(t, contexts[0].Current)
+
+	require.NoError(t, foreignErr)
+	assert.Equal(t, []string{"manual"}, foreign)
+
+	require.NoError(t, useErr)
+	config, loadErr := clientcmd.LoadFromFile(path)
+	require.NoError(t, loadErr)
+	assert.Equal(t, "prod-"+serverID, config.CurrentContext)
+	assert.Len(t, config.Contexts, 2)
+}
+
+func TestHandler_UseContext_UnknownContext(t *testing.T) {
+	handler, err := NewHandler(filesystem.New(), t.TempDir(), testutil.Logger())
+	require.NoError(t, err)
+
+	err = handler.UseContext(context.Background(), filepath.Join(t.TempDir(), "config"), "prod-12345678")
+
+	require.ErrorContains(t, err, "context prod-12345678 not found")
+}
+
+func TestHandler_ReadKubeconfig_Redacts(t *testing.T) {
+	// Arrange
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "fragment.yaml")
+	require.NoError(t, os.WriteFile(path, testutil.RancherKubeconfig("prod", 1), 0o600))
+
+	handler, err := NewHandler(filesystem.New(), tempDir, testutil.Logger())
+	require.NoError(t, err)
+
+	// Act
+	plain, plainErr := handler.ReadKubeconfig(context.Background(), path, false)
+	redactedContent, redactErr := handler.ReadKubeconfig(context.Background(), path, true)
+
+	// Assert
+	require.NoError(t, plainErr)
+	assert.Equal(t, testutil.RancherKubeconfig("prod", 1), plain)
+
+	require.NoError(t, redactErr)
+	config, loadErr := clientcmd.Load(redactedContent)
+	require.NoError(t, loadErr)
+	require.NotEmpty(t, config.AuthInfos)
+	for _, authInfo := range config.AuthInfos {
+		assert.Equal(t, redacted, authInfo.Token)
+	}
+	assert.Len(t, config.Contexts, 2, "only credentials are redacted")
+}
+
+func TestHandler_ClusterCredential(t *testing.T) {
+	// Arrange
+	tempDir := t.TempDir()
+	handler, err := NewHandler(filesystem.New(), tempDir, testutil.Logger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	expiresAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	fragmentPath := filepath.Join(tempDir, "prod-abc12345.yaml")
+	owner := domain.ContextOwner{ServerID: "abc12345", ClusterID: "c-m-prod", ExpiresAt: expiresAt}
+	require.NoError(t, handler.SaveKubeconfig(ctx, fragmentPath, testutil.RancherKubeconfig("prod", 1), owner))
+
+	// Act
+	credential, credErr := handler.ClusterCredential(ctx, fragmentPath)
+	_, missingErr := handler.ClusterCredential(ctx, filepath.Join(tempDir, "missing.yaml"))
+
+	// Assert
+	require.NoError(t, credErr)
+	assert.Equal(t, "kubeconfig-user-abc12:"+strings.Repeat("x", 64), credential.Token)
+	assert.True(t, expiresAt.Equal(credential.ExpiresAt))
+	require.Error(t, missingErr)
+}
+
+func TestHandler_RemoveContexts(t *testing.T) {
+	// Arrange
+	kubeconfig := `apiVersion: v1
+kind: Config
+current-context: prod
+clusters:
+- cluster:
+    server: https://prod.example.com
+  name: prod
+- cluster:
+    server: https://staging.example.com
+  name: staging
+contexts:
+- context:
+    cluster: prod
+    user: admin
+  name: prod
+- context:
+    cluster: staging
+    user: admin
+  name: staging
+users:
+- name: admin
+  user:
+    token: token`
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(path, []byte(kubeconfig), 0o600))
+	handler, err := NewHandler(filesystem.New(), t.TempDir(), testutil.Logger())
+	require.NoError(t, err)
+
+	// Act
+	removed, err := handler.RemoveContexts(context.Background(), path, []string{"prod", "missing"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	config, err := clientcmd.LoadFromFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, config.CurrentContext)
+	assert.Equal(t, []string{"staging"}, slices.Sorted(maps.Keys(config.Contexts)))
+	assert.Equal(t, []string{"staging"}, slices.Sorted(maps.Keys(config.Clusters)))
+	assert.Equal(t, []string{"admin"}, slices.Sorted(maps.Keys(config.AuthInfos)), "users still in use are kept")
+}
+
+func TestHandler_ValidateFragment(t *testing.T) {
+	tests := []struct {
+		name       string
+		kubeconfig string
+		problems   []string
+	}{
+		{
+			name: "valid",
+			kubeconfig: `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://rancher.example.com/k8s/clusters/c-m-xyz
+  name: prod
+contexts:
+- context:
+    cluster: prod
+    user: prod
+  name: prod
+users:
+- name: prod
+  user:
+    token: token`,
+		},
+		{
+			name:       "empty",
+			kubeconfig: "apiVersion: v1\nkind: Config\n",
+			problems:   []string{"no clusters", "no contexts", "no users"},
+		},
+		{
+			name: "broken references, server URL and credentials",
+			kubeconfig: `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: rancher.example.com
+  name: prod
+contexts:
+- context:
+    cluster: staging
+    user: admin
+  name: prod
+users:
+- name: prod
+  user:
+    token: ""`,
+			problems: []string{
+				`cluster "prod": invalid server URL "rancher.example.com"`,
+				`user "prod": no token or other credentials`,
+				`context "prod": cluster "staging" not found`,
+				`context "prod": user "admin" not found`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tempDir := t.TempDir()
+			path := filepath.Join(tempDir, "prod-abc12345.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.kubeconfig), 0o600))
+			handler, err := NewHandler(filesystem.New(), tempDir, testutil.Logger())
+			require.NoError(t, err)
+
+			// Act
+			problems := handler.ValidateFragment(context.Background(), path)
+
+			// Assert
+			assert.Equal(t, tt.problems, problems)
+		})
+	}
+}
+
+func TestHandler_ValidateFragment_Unreadable(t *testing.T) {
+	// Arrange
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "prod-abc12345.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("clusters: {"), 0o600))
+	handler, err := NewHandler(filesystem.New(), tempDir, testutil.Logger())
+	require.NoError(t, err)
+
+	// Act
+	problems := handler.ValidateFragment(context.Background(), path)
+
+	// Assert
+	require.Len(t, problems, 1)
+	assert.Contains(t, problems[0], "failed to parse kubeconfig")
+}
+
+func TestHandler_QuarantineFragment(t *testing.T) {
+	// Arrange
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "prod-abc12345.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("kind: Config"), 0o600))
+	handler, err := NewHandler(filesystem.New(), tempDir, testutil.Logger())
+	require.NoError(t, err)
+
+	// Act
+	quarantined, err := handler.QuarantineFragment(context.Background(), path, []string{"no clusters", "no users"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(tempDir, "quarantine", "prod-abc12345.yaml"), quarantined)
+	assert.NoFileExists(t, path)
+	content, readErr := os.ReadFile(quarantined)
+	require.NoError(t, readErr)
+	assert.Equal(t, "kind: Config", string(content))
+	reason, readErr := os.ReadFile(quarantined + ".error")
+	require.NoError(t, readErr)
+	assert.Equal(t, "no clusters\nno users\n", string(reason))
+}
+
+func TestHandler_SaveKubeconfig_QuarantinesUnparseableKubeconfig(t *testing.T) {
+	// Arrange
+	tempDir := t.TempDir()
+	handler, err := NewHandler(filesystem.New(), tempDir, testutil.Logger())
+	require.NoError(t, err)
+	path := filepath.Join(tempDir, "prod-abc12345.yaml")
+
+	// Act
+	err = handler.SaveKubeconfig(context.Background(), path, []byte("clusters: {"),
+		domain.ContextOwner{ServerID: "abc12345"})
+
+	// Assert
+	var quarantineErr *domain.QuarantineError
+	require.ErrorAs(t, err, &quarantineErr)
+	require.ErrorIs(t, err, domain.ErrInvalidKubeconfig)
+	assert.Equal(t, filepath.Join(tempDir, "quarantine", "prod-abc12345.yaml"), quarantineErr.Path)
+	assert.NoFileExists(t, path)
+	content, readErr := os.ReadFile(quarantineErr.Path)
+	require.NoError(t, readErr)
+	assert.Equal(t, "clusters: {", string(content))
+	reason, readErr := os.ReadFile(quarantineErr.Path + ".error")
+	require.NoError(t, readErr)
+	assert.Contains(t, string(reason), "failed to preprocess kubeconfig")
+}
+
+func TestHandler_MergeKubeconfigs_AppliesPermissionPolicy(t *testing.T) {
+	// Arrange
+	tempDir := t.TempDir()
+	inputPath := filepath.Join(tempDir, "prod-abc12345.yaml")
+	require.NoError(t, os.WriteFile(inputPath, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://prod.example.com
+  name: prod
+contexts:
+- context:
+    cluster: prod
+    user: prod
+  name: prod
+users:
+- name: prod
+  user:
+    token: secret`), 0o600))
+	outputPath := filepath.Join(tempDir, "team", "config")
+	handler, err := NewHandler(filesystem.New(), tempDir, testutil.Logger(), WithPermissions(domain.PermissionSettings{
+		FileMode: "0640",
+		DirMode:  "0750",
+		Group:    strconv.Itoa(os.Getgid()),
+	}))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// Act
+	err = handler.MergeKubeconfigs(ctx, []string{inputPath}, outputPath, filter.NewNoOpFilter())
+	require.NoError(t, err)
+	require.NoError(t, os.Chmod(outputPath, 0o600))
+	unchangedErr := handler.MergeKubeconfigs(ctx, []string{inputPath}, outputPath, filter.NewNoOpFilter())
+
+	// Assert
+	require.NoError(t, unchangedErr)
+	fileInfo, statErr := os.Stat(outputPath)
+	require.NoError(t, statErr)
+	assert.Equal(t, os.FileMode(0o640), fileInfo.Mode().Perm())
+	dirInfo, statErr := os.Stat(filepath.Dir(outputPath))
+	require.NoError(t, statErr)
+	assert.Equal(t, os.FileMode(0o750), dirInfo.Mode().Perm())
+}
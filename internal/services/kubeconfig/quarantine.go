@@ -0,0 +1,76 @@
+package kubeconfig
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// quarantineDir is the subdirectory of the kubeconfig directory holding downloads that were not merged.
+	quarantineDir = "quarantine"
+	// reasonExt is appended to a quarantined kubeconfig's file name for the file explaining why.
+	reasonExt = ".error"
+)
+
+// QuarantineFragment moves the fragment at path to the quarantine directory, next to a file listing its
+// problems, so it is neither merged nor mistaken for a good fragment. An earlier quarantined copy of the
+// same fragment is replaced.
+func (h *Handler) QuarantineFragment(ctx context.Context, path string, problems []string) (string, error) {
+	quarantined, err := h.quarantinePath(filepath.Base(path))
+	if err != nil {
+		return "", err
+	}
+	if renameErr := h.fs.Rename(path, quarantined); renameErr != nil {
+		return "", fmt.Errorf("failed to quarantine kubeconfig: %w", renameErr)
+	}
+	if reasonErr := h.writeReason(quarantined, problems); reasonErr != nil {
+		return "", reasonErr
+	}
+
+	h.logger.DebugContext(ctx, "Kubeconfig quarantined", "path", path, "quarantine", quarantined)
+	return quarantined, nil
+}
+
+// quarantine writes a downloaded kubeconfig that could not be saved to the quarantine directory as name,
+// encrypted like any other fragment, along with the reason it could not be saved.
+func (h *Handler) quarantine(ctx context.Context, name string, content []byte, reason error) (string, error) {
+	quarantined, err := h.quarantinePath(name)
+	if err != nil {
+		return "", err
+	}
+	if h.encryptor != nil {
+		if content, err = h.encryptor.Encrypt(ctx, content); err != nil {
+			return "", fmt.Errorf("failed to encrypt quarantined kubeconfig: %w", err)
+		}
+	}
+	if writeErr := h.fs.WriteFile(quarantined, content, filePermissions); writeErr != nil {
+		return "", fmt.Errorf("failed to write quarantined kubeconfig: %w", writeErr)
+	}
+	if reasonErr := h.writeReason(quarantined, []string{reason.Error()}); reasonErr != nil {
+		return "", reasonErr
+	}
+
+	h.logger.DebugContext(ctx, "Kubeconfig quarantined", "quarantine", quarantined, "error", reason)
+	return quarantined, nil
+}
+
+// quarantinePath returns the path in the quarantine directory for a file called name, creating the
+// directory if needed.
+func (h *Handler) quarantinePath(name string) (string, error) {
+	dir := filepath.Join(h.kubeconfigDir, quarantineDir)
+	if err := h.fs.MkdirAll(dir, dirPermissions); err != nil {
+		return "", fmt.Errorf("failed to create quarantine directory: %w", err)
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// writeReason writes the problems with a quarantined kubeconfig to the file next to it, one per line.
+func (h *Handler) writeReason(quarantined string, problems []string) error {
+	reason := strings.Join(problems, "\n") + "\n"
+	if err := h.fs.WriteFile(quarantined+reasonExt, []byte(reason), filePermissions); err != nil {
+		return fmt.Errorf("failed to write quarantine reason: %w", err)
+	}
+	return nil
+}
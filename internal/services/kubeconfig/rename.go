@@ -0,0 +1,143 @@
+package kubeconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"cowpoke/internal/domain"
+)
+
+// RenameServer rewrites the contexts owned by one server so they follow the naming of its new URL.
+// Contexts are matched by their cowpoke extension, falling back to the "-<server ID>" name suffix for
+// contexts written before extensions existed. It returns the number of contexts rewritten; a missing
+// kubeconfig is not an error.
+func (h *Handler) RenameServer(ctx context.Context, path string, from, to domain.ConfigServer) (int, error) {
+	data, err := h.fs.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			h.logger.DebugContext(ctx, "Kubeconfig does not exist, nothing to rename", "path", path)
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read kubeconfig %s: %w", path, err)
+	}
+
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse kubeconfig %s: %w", path, err)
+	}
+
+	oldID, newID := from.ID(), to.ID()
+	rename := func(name string) string {
+		if base, ok := strings.CutSuffix(name, "-"+oldID); ok {
+			return base + "-" + newID
+		}
+		return name
+	}
+
+	contexts := make(map[string]*api.Context, len(config.Contexts))
+	var renamed int
+	for name, kubeContext := range config.Contexts {
+		owner, owned := ContextOwnerOf(kubeContext)
+		if (owned && owner.ServerID != oldID) || (!owned && !strings.HasSuffix(name, "-"+oldID)) {
+			contexts[name] = kubeContext
+			continue
+		}
+
+		newName := rename(name)
+		kubeContext.Cluster = moveEntry(config.Clusters, kubeContext.Cluster, rename(kubeContext.Cluster))
+		kubeContext.AuthInfo = moveEntry(config.AuthInfos, kubeContext.AuthInfo, rename(kubeContext.AuthInfo))
+		if owned {
+			owner.ServerURL = to.URL
+			owner.ServerID = newID
+			if ownerErr := setContextOwner(kubeContext, owner); ownerErr != nil {
+				return 0, ownerErr
+			}
+		}
+		if config.CurrentContext == name {
+			config.CurrentContext = newName
+		}
+
+		h.logger.DebugContext(ctx, "Renamed context", "old", name, "new", newName)
+		contexts[newName] = kubeContext
+		renamed++
+	}
+	config.Contexts = contexts
+
+	if renamed == 0 {
+		return 0, nil
+	}
+
+	content, err := clientcmd.Write(*config)
+	if err != nil {
+		return 0, fmt.Errorf("failed to serialize kubeconfig: %w", err)
+	}
+	if writeErr := h.fs.WriteFile(path, content, h.permissions.File()); writeErr != nil {
+		return 0, fmt.Errorf("failed to write kubeconfig %s: %w", path, writeErr)
+	}
+
+	h.logger.InfoContext(ctx, "Renamed server contexts",
+		"path", path,
+		"from", from.URL,
+		"to", to.URL,
+		"contexts", renamed)
+	return renamed, nil
+}
+
+// moveEntry renames a cluster or user entry, returning the name to reference. Entries shared by several
+// contexts are moved only once.
+func moveEntry[T any](entries map[string]T, oldName, newName string) string {
+	if oldName == newName {
+		return oldName
+	}
+	if entry, ok := entries[oldName]; ok {
+		delete(entries, oldName)
+		entries[newName] = entry
+	}
+	return newName
+}
+
+// dropSupersededContexts removes the contexts that an older kubeconfig merged into config still holds for
+// clusters since renamed in Rancher, so each renamed cluster keeps only its contexts under the new name.
+// Clusters and users that only the removed contexts referenced are removed too. It returns the number of
+// contexts removed.
+func (h *Handler) dropSupersededContexts(ctx context.Context, config *api.Config) int {
+	var managed []domain.ManagedContext
+	for name, kubeContext := range config.Contexts {
+		if owner, owned := ContextOwnerOf(kubeContext); owned {
+			managed = append(managed, domain.ManagedContext{Name: name, Owner: owner})
+		}
+	}
+	superseded := domain.SupersededContexts(managed)
+	if len(superseded) == 0 {
+		return 0
+	}
+
+	clusters, users := make(map[string]bool), make(map[string]bool)
+	for name, replacement := range superseded {
+		kubeContext := config.Contexts[name]
+		clusters[kubeContext.Cluster] = true
+		users[kubeContext.AuthInfo] = true
+		delete(config.Contexts, name)
+		if config.CurrentContext == name {
+			config.CurrentContext = replacement
+		}
+		h.logger.DebugContext(ctx, "Replaced context of renamed cluster", "old", name, "new", replacement)
+	}
+	for _, kubeContext := range config.Contexts {
+		delete(clusters, kubeContext.Cluster)
+		delete(users, kubeContext.AuthInfo)
+	}
+	for name := range clusters {
+		delete(config.Clusters, name)
+	}
+	for name := range users {
+		delete(config.AuthInfos, name)
+	}
+	return len(superseded)
+}
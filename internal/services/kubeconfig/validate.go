@@ -0,0 +1,79 @@
+package kubeconfig
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ValidateFragment checks a downloaded fragment before it is merged: it must decrypt and parse, and hold
+// at least one cluster, context and user. Every context must refer to a cluster and user in the fragment,
+// every cluster must have a server URL that parses, and every user must have credentials.
+func (h *Handler) ValidateFragment(ctx context.Context, path string) []string {
+	config, err := h.loadAndFilterKubeconfig(ctx, path, nil)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	var problems []string
+	if len(config.Clusters) == 0 {
+		problems = append(problems, "no clusters")
+	}
+	if len(config.Contexts) == 0 {
+		problems = append(problems, "no contexts")
+	}
+	if len(config.AuthInfos) == 0 {
+		problems = append(problems, "no users")
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(config.Clusters)) {
+		if problem := clusterProblem(config.Clusters[name]); problem != "" {
+			problems = append(problems, fmt.Sprintf("cluster %q: %s", name, problem))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(config.AuthInfos)) {
+		if !hasCredentials(config.AuthInfos[name]) {
+			problems = append(problems, fmt.Sprintf("user %q: no token or other credentials", name))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(config.Contexts)) {
+		kubeContext := config.Contexts[name]
+		if _, ok := config.Clusters[kubeContext.Cluster]; !ok {
+			problems = append(problems, fmt.Sprintf("context %q: cluster %q not found", name, kubeContext.Cluster))
+		}
+		if _, ok := config.AuthInfos[kubeContext.AuthInfo]; !ok {
+			problems = append(problems, fmt.Sprintf("context %q: user %q not found", name, kubeContext.AuthInfo))
+		}
+	}
+
+	if len(problems) > 0 {
+		h.logger.DebugContext(ctx, "Invalid kubeconfig fragment", "path", path, "problems", problems)
+	}
+	return problems
+}
+
+// clusterProblem returns what is wrong with a cluster's server URL, if anything.
+func clusterProblem(cluster *api.Cluster) string {
+	if cluster.Server == "" {
+		return "no server URL"
+	}
+	serverURL, err := url.Parse(cluster.Server)
+	if err != nil {
+		return fmt.Sprintf("invalid server URL: %v", err)
+	}
+	if serverURL.Scheme != "https" && serverURL.Scheme != "http" || serverURL.Host == "" {
+		return fmt.Sprintf("invalid server URL %q", cluster.Server)
+	}
+	return ""
+}
+
+// hasCredentials reports whether a user has a token or any other way to authenticate.
+func hasCredentials(authInfo *api.AuthInfo) bool {
+	return authInfo.Token != "" || authInfo.TokenFile != "" ||
+		len(authInfo.ClientCertificateData) > 0 || authInfo.ClientCertificate != "" ||
+		authInfo.Exec != nil || authInfo.AuthProvider != nil || authInfo.Username != ""
+}
@@ -0,0 +1,103 @@
+// Package metrics derives timing and throughput summaries from sync events.
+package metrics
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+
+	"cowpoke/internal/domain"
+)
+
+// Percentiles reported for download latency.
+const (
+	p50 = 50
+	p90 = 90
+	p99 = 99
+)
+
+// Summarize computes a timing breakdown from the events recorded during a sync.
+func Summarize(events []domain.SyncEvent) domain.SyncTiming {
+	var timing domain.SyncTiming
+	var discovery, download window
+	var latencies []time.Duration
+
+	for _, event := range events {
+		switch event.Phase {
+		case domain.PhaseSync:
+			timing.Total = event.Duration()
+		case domain.PhaseMerge:
+			timing.Merge += event.Duration()
+		case domain.PhaseAuthenticate:
+			discovery.add(event)
+			timing.Auth = append(timing.Auth, domain.ServerTiming{
+				ServerURL: event.ServerURL,
+				Duration:  event.Duration(),
+				Failed:    event.Error != nil,
+			})
+		case domain.PhaseListClusters:
+			discovery.add(event)
+		case domain.PhaseDownload:
+			download.add(event)
+			if event.Error != nil {
+				timing.Download.Failed++
+				continue
+			}
+			timing.Download.Count++
+			timing.Download.Bytes += int64(event.Bytes)
+			latencies = append(latencies, event.Duration())
+		}
+	}
+
+	slices.SortFunc(timing.Auth, func(a, b domain.ServerTiming) int {
+		return cmp.Compare(b.Duration, a.Duration)
+	})
+
+	timing.Discovery = discovery.duration()
+	timing.Download.Wall = download.duration()
+	if timing.Download.Wall > 0 {
+		timing.Download.BytesPerSecond = float64(timing.Download.Bytes) / timing.Download.Wall.Seconds()
+	}
+
+	slices.Sort(latencies)
+	timing.Download.P50 = percentile(latencies, p50)
+	timing.Download.P90 = percentile(latencies, p90)
+	timing.Download.P99 = percentile(latencies, p99)
+	if len(latencies) > 0 {
+		timing.Download.Max = latencies[len(latencies)-1]
+	}
+
+	return timing
+}
+
+// percentile returns the nearest-rank percentile p of sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(float64(p) / 100 * float64(len(sorted)))) //nolint:mnd // Percent
+	return sorted[max(rank, 1)-1]
+}
+
+// window tracks the earliest start and latest end of a set of concurrent events.
+type window struct {
+	start time.Time
+	end   time.Time
+}
+
+func (w *window) add(event domain.SyncEvent) {
+	if w.start.IsZero() || event.Start.Before(w.start) {
+		w.start = event.Start
+	}
+	if event.End.After(w.end) {
+		w.end = event.End
+	}
+}
+
+func (w *window) duration() time.Duration {
+	if w.start.IsZero() {
+		return 0
+	}
+	return w.end.Sub(w.start)
+}
@@ -0,0 +1,57 @@
+package metrics_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/services/metrics"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	// Arrange
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time { return base.Add(time.Duration(ms) * time.Millisecond) }
+	events := []domain.SyncEvent{
+		{Phase: domain.PhaseSync, Start: at(0), End: at(5000)},
+		{Phase: domain.PhaseAuthenticate, ServerURL: "https://a", Start: at(0), End: at(200)},
+		{Phase: domain.PhaseAuthenticate, ServerURL: "https://b", Start: at(0), End: at(800), Error: errors.New("boom")},
+		{Phase: domain.PhaseListClusters, ServerURL: "https://a", Start: at(200), End: at(1000)},
+		{Phase: domain.PhaseDownload, Cluster: "c1", Start: at(1000), End: at(1100), Bytes: 1000},
+		{Phase: domain.PhaseDownload, Cluster: "c2", Start: at(1000), End: at(1300), Bytes: 1000},
+		{Phase: domain.PhaseDownload, Cluster: "c3", Start: at(1100), End: at(2000), Bytes: 2000},
+		{Phase: domain.PhaseDownload, Cluster: "c4", Start: at(1100), End: at(1500), Error: errors.New("boom")},
+		{Phase: domain.PhaseMerge, Start: at(2000), End: at(2250)},
+	}
+
+	// Act
+	timing := metrics.Summarize(events)
+
+	// Assert
+	assert.Equal(t, 5*time.Second, timing.Total)
+	assert.Equal(t, time.Second, timing.Discovery)
+	assert.Equal(t, 250*time.Millisecond, timing.Merge)
+	assert.Equal(t, []domain.ServerTiming{
+		{ServerURL: "https://b", Duration: 800 * time.Millisecond, Failed: true},
+		{ServerURL: "https://a", Duration: 200 * time.Millisecond},
+	}, timing.Auth)
+	assert.Equal(t, 3, timing.Download.Count)
+	assert.Equal(t, 1, timing.Download.Failed)
+	assert.Equal(t, int64(4000), timing.Download.Bytes)
+	assert.Equal(t, time.Second, timing.Download.Wall)
+	assert.Equal(t, 300*time.Millisecond, timing.Download.P50)
+	assert.Equal(t, 900*time.Millisecond, timing.Download.P90)
+	assert.Equal(t, 900*time.Millisecond, timing.Download.Max)
+	assert.InDelta(t, 4000.0, timing.Download.BytesPerSecond, 0.001)
+}
+
+func TestSummarize_NoEvents(t *testing.T) {
+	// Act
+	timing := metrics.Summarize(nil)
+
+	// Assert
+	assert.Equal(t, domain.SyncTiming{}, timing)
+}
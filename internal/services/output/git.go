@@ -0,0 +1,294 @@
+package output
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"cowpoke/internal/domain"
+)
+
+const (
+	// GitScheme is the scheme of git working tree destinations.
+	GitScheme = "git"
+	// gitFileMode keeps kubeconfigs written into a working tree private to the user.
+	gitFileMode os.FileMode = 0o600
+	// gitDirMode keeps the directories created for them private to the user.
+	gitDirMode os.FileMode = 0o700
+	// splitExt is the extension of per-context kubeconfigs.
+	splitExt = ".yaml"
+)
+
+// GitWriter writes merged kubeconfigs into a git working tree and commits them, so every sync that changes
+// them leaves a commit to inspect or roll back to. Destinations look like
+//
+//	git:///home/me/kubeconfigs/config?split=clusters&merged=false
+//
+// where the path is the merged kubeconfig inside the working tree. split names a directory, relative to
+// the merged kubeconfig, that receives one kubeconfig per context, and merged=false writes only those.
+// Only the written files are committed; other changes in the working tree are left alone.
+type GitWriter struct {
+	git    domain.GitAdapter
+	fs     domain.FileSystemAdapter
+	logger *slog.Logger
+}
+
+// NewGitWriter creates a new git working tree writer.
+func NewGitWriter(git domain.GitAdapter, fs domain.FileSystemAdapter, logger *slog.Logger) *GitWriter {
+	return &GitWriter{
+		git:    git,
+		fs:     fs,
+		logger: logger,
+	}
+}
+
+// gitTarget is a parsed git destination.
+type gitTarget struct {
+	path   string
+	split  string
+	merged bool
+}
+
+// parseGitTarget reads the path and options of a git destination.
+func parseGitTarget(destination *url.URL) (gitTarget, error) {
+	query := destination.Query()
+	target := gitTarget{path: filepath.Clean(destination.Path), merged: true}
+	if destination.Host != "" || !filepath.IsAbs(destination.Path) || strings.HasSuffix(destination.Path, "/") {
+		return gitTarget{}, fmt.Errorf("invalid git destination %q (use git:///path/in/working/tree/config)",
+			destination.Redacted())
+	}
+	if split := query.Get("split"); split != "" {
+		target.split = filepath.Join(filepath.Dir(target.path), split)
+	}
+	if merged := query.Get("merged"); merged != "" {
+		var err error
+		if target.merged, err = strconv.ParseBool(merged); err != nil {
+			return gitTarget{}, fmt.Errorf("invalid merged %q (use true or false)", merged)
+		}
+	}
+	if !target.merged && target.split == "" {
+		return gitTarget{}, errors.New("merged=false requires split to name a directory")
+	}
+	return target, nil
+}
+
+// Scheme returns the scheme of git working tree destinations.
+func (w *GitWriter) Scheme() string {
+	return GitScheme
+}
+
+// WriteOutput writes the kubeconfig at path into the destination working tree and commits it.
+func (w *GitWriter) WriteOutput(ctx context.Context, destination *url.URL, path string) error {
+	target, err := parseGitTarget(destination)
+	if err != nil {
+		return err
+	}
+	content, err := w.fs.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read merged kubeconfig: %w", err)
+	}
+	config, err := clientcmd.Load(content)
+	if err != nil {
+		return fmt.Errorf("failed to parse merged kubeconfig: %w", err)
+	}
+
+	dir := filepath.Dir(target.path)
+	if err := w.fs.MkdirAll(dir, gitDirMode); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+	if _, err := w.git.Run(ctx, dir, []string{"rev-parse", "--show-toplevel"}); err != nil {
+		return fmt.Errorf("%s is not in a git working tree: %w", dir, err)
+	}
+
+	previous := w.previousContexts(target)
+	var paths []string
+	if target.merged {
+		if err := w.fs.WriteFile(target.path, content, gitFileMode); err != nil {
+			return fmt.Errorf("failed to write %s: %w", target.path, err)
+		}
+		paths = append(paths, target.path)
+	}
+	if target.split != "" {
+		if err := w.writeSplit(target.split, config); err != nil {
+			return err
+		}
+		paths = append(paths, target.split)
+	}
+
+	if _, err := w.git.Run(ctx, dir, append([]string{"add", "--all", "--"}, paths...)); err != nil {
+		return fmt.Errorf("failed to stage kubeconfigs: %w", err)
+	}
+	status, err := w.git.Run(ctx, dir, append([]string{"status", "--porcelain", "--"}, paths...))
+	if err != nil {
+		return fmt.Errorf("failed to check for changes: %w", err)
+	}
+	if strings.TrimSpace(status) == "" {
+		w.logger.InfoContext(ctx, "Kubeconfigs in git working tree are unchanged", "path", target.path)
+		return nil
+	}
+
+	subject, body := commitMessage(previous, splitContexts(config))
+	args := append([]string{"commit", "--quiet", "--message", subject, "--message", body, "--"}, paths...)
+	if _, err := w.git.Run(ctx, dir, args); err != nil {
+		return fmt.Errorf("failed to commit kubeconfigs: %w", err)
+	}
+
+	w.logger.InfoContext(ctx, "Committed merged kubeconfig to git", "path", target.path, "commit", subject)
+	return nil
+}
+
+// previousContexts returns the contexts the destination held before this sync, read from the merged
+// kubeconfig if it is written and from the per-context kubeconfigs otherwise.
+func (w *GitWriter) previousContexts(target gitTarget) map[string]*clientcmdapi.Config {
+	if target.merged {
+		content, err := w.fs.ReadFile(target.path)
+		if err != nil {
+			return nil
+		}
+		config, err := clientcmd.Load(content)
+		if err != nil {
+			return nil
+		}
+		return splitContexts(config)
+	}
+
+	entries, err := w.fs.ReadDir(target.split)
+	if err != nil {
+		return nil
+	}
+	contexts := make(map[string]*clientcmdapi.Config, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != splitExt {
+			continue
+		}
+		content, err := w.fs.ReadFile(filepath.Join(target.split, entry.Name()))
+		if err != nil {
+			continue
+		}
+		config, err := clientcmd.Load(content)
+		if err != nil {
+			continue
+		}
+		for name, single := range splitContexts(config) {
+			contexts[name] = single
+		}
+	}
+	return contexts
+}
+
+// writeSplit writes one kubeconfig per context of config into dir and removes those of contexts that are
+// gone.
+func (w *GitWriter) writeSplit(dir string, config *clientcmdapi.Config) error {
+	if err := w.fs.MkdirAll(dir, gitDirMode); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+	written := make(map[string]bool)
+	for name, single := range splitContexts(config) {
+		content, err := clientcmd.Write(*single)
+		if err != nil {
+			return fmt.Errorf("failed to serialize context %s: %w", name, err)
+		}
+		file := splitFileName(name)
+		if err := w.fs.WriteFile(filepath.Join(dir, file), content, gitFileMode); err != nil {
+			return fmt.Errorf("failed to write context %s: %w", name, err)
+		}
+		written[file] = true
+	}
+
+	entries, err := w.fs.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != splitExt || written[entry.Name()] {
+			continue
+		}
+		if err := w.fs.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// splitContexts returns a kubeconfig per context of config, holding just that context, its cluster and
+// its user.
+func splitContexts(config *clientcmdapi.Config) map[string]*clientcmdapi.Config {
+	contexts := make(map[string]*clientcmdapi.Config, len(config.Contexts))
+	for name, kubeContext := range config.Contexts {
+		single := clientcmdapi.NewConfig()
+		single.Contexts[name] = kubeContext
+		single.CurrentContext = name
+		if cluster, ok := config.Clusters[kubeContext.Cluster]; ok {
+			single.Clusters[kubeContext.Cluster] = cluster
+		}
+		if authInfo, ok := config.AuthInfos[kubeContext.AuthInfo]; ok {
+			single.AuthInfos[kubeContext.AuthInfo] = authInfo
+		}
+		contexts[name] = single
+	}
+	return contexts
+}
+
+// splitFileName returns the file name of a context's kubeconfig, replacing characters that are unsafe in
+// file names.
+func splitFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, name) + splitExt
+}
+
+// commitMessage returns the subject and body of the commit recording a sync, listing the contexts it
+// added, changed and removed.
+func commitMessage(previous, current map[string]*clientcmdapi.Config) (string, string) {
+	var added, changed, removed []string
+	for name, single := range current {
+		old, ok := previous[name]
+		switch {
+		case !ok:
+			added = append(added, name)
+		case !reflect.DeepEqual(old, single):
+			changed = append(changed, name)
+		}
+	}
+	for name := range previous {
+		if _, ok := current[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+
+	subject := fmt.Sprintf("cowpoke sync: %d contexts", len(current))
+	if len(current) == 1 {
+		subject = "cowpoke sync: 1 context"
+	}
+	var body strings.Builder
+	for _, group := range []struct {
+		label string
+		names []string
+	}{{"Added", added}, {"Changed", changed}, {"Removed", removed}} {
+		if len(group.names) == 0 {
+			continue
+		}
+		slices.Sort(group.names)
+		fmt.Fprintf(&body, "%s: %s\n", group.label, strings.Join(group.names, ", "))
+	}
+	if body.Len() == 0 {
+		body.WriteString("No context was added, changed or removed.\n")
+	}
+	fmt.Fprintf(&body, "\nCowpoke-Contexts: %d\nCowpoke-Added: %d\nCowpoke-Changed: %d\nCowpoke-Removed: %d",
+		len(current), len(added), len(changed), len(removed))
+	return subject, body.String()
+}
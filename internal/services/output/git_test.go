@@ -0,0 +1,219 @@
+package output
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cowpoke/internal/adapters/filesystem"
+	"cowpoke/internal/adapters/git"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// twoContexts is a merged kubeconfig with the contexts "dev" and "prod".
+const twoContexts = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://dev.example.com
+  name: dev
+- cluster:
+    server: https://prod.example.com
+  name: prod
+contexts:
+- context:
+    cluster: dev
+    user: dev
+  name: dev
+- context:
+    cluster: prod
+    user: prod
+  name: prod
+users:
+- name: dev
+  user:
+    token: dev-token
+- name: prod
+  user:
+    token: prod-token
+`
+
+// newDevToken is twoContexts with a new token for "dev" and without "prod".
+const newDevToken = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://dev.example.com
+  name: dev
+contexts:
+- context:
+    cluster: dev
+    user: dev
+  name: dev
+users:
+- name: dev
+  user:
+    token: new-dev-token
+`
+
+// newTestRepo creates a git repository with an isolated configuration and returns its working tree.
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "cowpoke")
+	t.Setenv("GIT_AUTHOR_EMAIL", "cowpoke@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "cowpoke")
+	t.Setenv("GIT_COMMITTER_EMAIL", "cowpoke@example.com")
+	repo := t.TempDir()
+	runGit(t, repo, "init", "--quiet")
+	return repo
+}
+
+// runGit runs git in dir and returns its trimmed output.
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := git.New().Run(context.Background(), dir, args)
+	require.NoError(t, err)
+	return strings.TrimSpace(out)
+}
+
+// writeKubeconfig writes content to a temporary merged kubeconfig and returns its path.
+func writeKubeconfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "merged.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestGitWriter_WriteOutput_CommitsMergedAndSplitKubeconfigs(t *testing.T) {
+	// Arrange
+	repo := newTestRepo(t)
+	destination, err := url.Parse("git://" + repo + "/kube/config?split=clusters")
+	require.NoError(t, err)
+	writer := NewGitWriter(git.New(), filesystem.New(), testutil.Logger())
+
+	// Act
+	err = writer.WriteOutput(context.Background(), destination, writeKubeconfig(t, twoContexts))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "kube/clusters/dev.yaml\nkube/clusters/prod.yaml\nkube/config",
+		runGit(t, repo, "ls-files"))
+	assert.Equal(t, "cowpoke sync: 2 contexts\n\nAdded: dev, prod\n\n"+
+		"Cowpoke-Contexts: 2\nCowpoke-Added: 2\nCowpoke-Changed: 0\nCowpoke-Removed: 0",
+		runGit(t, repo, "log", "-1", "--format=%B"))
+	prod, err := os.ReadFile(filepath.Join(repo, "kube", "clusters", "prod.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(prod), "prod-token")
+	assert.NotContains(t, string(prod), "dev-token")
+}
+
+func TestGitWriter_WriteOutput_RecordsChangedAndRemovedContexts(t *testing.T) {
+	// Arrange
+	repo := newTestRepo(t)
+	destination, err := url.Parse("git://" + repo + "/config?split=clusters&merged=false")
+	require.NoError(t, err)
+	writer := NewGitWriter(git.New(), filesystem.New(), testutil.Logger())
+	require.NoError(t, writer.WriteOutput(context.Background(), destination, writeKubeconfig(t, twoContexts)))
+
+	// Act
+	err = writer.WriteOutput(context.Background(), destination, writeKubeconfig(t, newDevToken))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "clusters/dev.yaml", runGit(t, repo, "ls-files"))
+	assert.Equal(t, "cowpoke sync: 1 context\n\nChanged: dev\nRemoved: prod\n\n"+
+		"Cowpoke-Contexts: 1\nCowpoke-Added: 0\nCowpoke-Changed: 1\nCowpoke-Removed: 1",
+		runGit(t, repo, "log", "-1", "--format=%B"))
+}
+
+func TestGitWriter_WriteOutput_SkipsCommitWhenUnchanged(t *testing.T) {
+	// Arrange
+	repo := newTestRepo(t)
+	destination, err := url.Parse("git://" + repo + "/config")
+	require.NoError(t, err)
+	writer := NewGitWriter(git.New(), filesystem.New(), testutil.Logger())
+	merged := writeKubeconfig(t, twoContexts)
+	require.NoError(t, writer.WriteOutput(context.Background(), destination, merged))
+
+	// Act
+	err = writer.WriteOutput(context.Background(), destination, merged)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "1", runGit(t, repo, "rev-list", "--count", "HEAD"))
+}
+
+func TestGitWriter_WriteOutput_LeavesOtherChangesAlone(t *testing.T) {
+	// Arrange
+	repo := newTestRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "notes.txt"), []byte("wip"), 0o600))
+	runGit(t, repo, "add", "notes.txt")
+	destination, err := url.Parse("git://" + repo + "/config")
+	require.NoError(t, err)
+	writer := NewGitWriter(git.New(), filesystem.New(), testutil.Logger())
+
+	// Act
+	err = writer.WriteOutput(context.Background(), destination, writeKubeconfig(t, twoContexts))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "config", runGit(t, repo, "ls-tree", "--name-only", "HEAD"))
+	assert.Equal(t, "A  notes.txt", runGit(t, repo, "status", "--porcelain"))
+}
+
+func TestGitWriter_WriteOutput_NotAWorkingTree(t *testing.T) {
+	// Arrange
+	newTestRepo(t)
+	dir := t.TempDir()
+	destination, err := url.Parse("git://" + dir + "/config")
+	require.NoError(t, err)
+	writer := NewGitWriter(git.New(), filesystem.New(), testutil.Logger())
+
+	// Act
+	err = writer.WriteOutput(context.Background(), destination, writeKubeconfig(t, twoContexts))
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not in a git working tree")
+}
+
+func TestGitWriter_WriteOutput_InvalidDestination(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination string
+		wantErr     string
+	}{
+		{name: "host", destination: "git://repo/config", wantErr: "use git:///path/in/working/tree/config"},
+		{name: "directory", destination: "git:///repo/", wantErr: "use git:///path/in/working/tree/config"},
+		{name: "invalid merged", destination: "git:///repo/config?merged=maybe", wantErr: "invalid merged"},
+		{name: "nothing to write", destination: "git:///repo/config?merged=false", wantErr: "requires split"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			destination, err := url.Parse(tt.destination)
+			require.NoError(t, err)
+			writer := NewGitWriter(git.New(), filesystem.New(), testutil.Logger())
+
+			// Act
+			err = writer.WriteOutput(context.Background(), destination, "/unused")
+
+			// Assert
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package output
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"cowpoke/internal/domain"
+)
+
+const (
+	// S3Scheme is the scheme of Amazon S3 and S3-compatible destinations.
+	S3Scheme = "s3"
+	// GCSScheme is the scheme of Google Cloud Storage destinations.
+	GCSScheme = "gs"
+	// gcsEndpoint is the Cloud Storage XML API, which accepts S3-style requests signed with HMAC keys.
+	gcsEndpoint = "https://storage.googleapis.com"
+	// defaultS3Region is the region used when neither the destination nor the environment names one.
+	defaultS3Region = "us-east-1"
+	// objectTimeout bounds each upload.
+	objectTimeout = 60 * time.Second
+)
+
+// ObjectWriter uploads merged kubeconfigs to object storage, so a centrally synced kubeconfig can be
+// published for workstations to pull. Destinations look like
+//
+//	s3://bucket/path/config?region=eu-west-1&sse=aws:kms&kmsKeyId=alias/kubeconfigs
+//	gs://bucket/path/config?kmsKeyName=projects/p/locations/l/keyRings/r/cryptoKeys/k
+//
+// S3 objects are encrypted with sse (AES256 or aws:kms, with kmsKeyId naming the KMS key), and Cloud
+// Storage objects with the Cloud KMS key kmsKeyName; otherwise the bucket's default encryption applies.
+// endpoint points s3:// at an S3-compatible service such as MinIO, which is addressed path-style.
+//
+// Requests are signed with the access key in AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and, for temporary
+// credentials, AWS_SESSION_TOKEN. For Cloud Storage these hold an HMAC key of a service account.
+type ObjectWriter struct {
+	scheme string
+	fs     domain.FileSystemAdapter
+	client *http.Client
+	// clock dates the signature of each request.
+	clock  domain.Clock
+	logger *slog.Logger
+}
+
+// NewS3Writer creates a new writer for s3:// destinations.
+func NewS3Writer(fs domain.FileSystemAdapter, clock domain.Clock, logger *slog.Logger) *ObjectWriter {
+	return newObjectWriter(S3Scheme, fs, clock, logger)
+}
+
+// NewGCSWriter creates a new writer for gs:// destinations.
+func NewGCSWriter(fs domain.FileSystemAdapter, clock domain.Clock, logger *slog.Logger) *ObjectWriter {
+	return newObjectWriter(GCSScheme, fs, clock, logger)
+}
+
+func newObjectWriter(
+	scheme string,
+	fs domain.FileSystemAdapter,
+	clock domain.Clock,
+	logger *slog.Logger,
+) *ObjectWriter {
+	return &ObjectWriter{
+		scheme: scheme,
+		fs:     fs,
+		client: &http.Client{Timeout: objectTimeout},
+		clock:  clock,
+		logger: logger,
+	}
+}
+
+// objectTarget is a parsed object storage destination.
+type objectTarget struct {
+	bucket   string
+	key      string
+	region   string
+	endpoint string
+	headers  map[string]string
+}
+
+// parseObjectTarget reads the bucket, key and options of an object storage destination.
+func (w *ObjectWriter) parseObjectTarget(destination *url.URL) (objectTarget, error) {
+	query := destination.Query()
+	target := objectTarget{
+		bucket:   destination.Host,
+		key:      strings.TrimPrefix(destination.Path, "/"),
+		endpoint: strings.TrimRight(query.Get("endpoint"), "/"),
+		headers:  map[string]string{},
+	}
+	if target.bucket == "" || target.key == "" || strings.HasSuffix(target.key, "/") {
+		return objectTarget{}, fmt.Errorf("invalid %s destination %q (use %s://bucket/path/to/object)",
+			w.scheme, destination.Redacted(), w.scheme)
+	}
+
+	switch w.scheme {
+	case GCSScheme:
+		target.endpoint = cmp.Or(target.endpoint, gcsEndpoint)
+		target.region = cmp.Or(query.Get("region"), "auto")
+		if keyName := query.Get("kmsKeyName"); keyName != "" {
+			target.headers["X-Goog-Encryption-Kms-Key-Name"] = keyName
+		}
+	default:
+		target.region = cmp.Or(query.Get("region"), os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"),
+			defaultS3Region)
+		sse, keyID := query.Get("sse"), query.Get("kmsKeyId")
+		if keyID != "" {
+			sse = cmp.Or(sse, "aws:kms")
+		}
+		switch sse {
+		case "":
+		case "AES256", "aws:kms", "aws:kms:dsse":
+			target.headers["X-Amz-Server-Side-Encryption"] = sse
+		default:
+			return objectTarget{}, fmt.Errorf("invalid sse %q (use AES256, aws:kms or aws:kms:dsse)", sse)
+		}
+		if keyID != "" {
+			if sse == "AES256" {
+				return objectTarget{}, errors.New("kmsKeyId requires sse=aws:kms")
+			}
+			target.headers["X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"] = keyID
+		}
+	}
+	return target, nil
+}
+
+// objectURL returns the URL of the target's object: virtual-hosted on AWS, path-style on other endpoints.
+func (t objectTarget) objectURL() string {
+	key := uriEncode(t.key, false)
+	if t.endpoint == "" {
+		return "https://" + t.bucket + ".s3." + t.region + ".amazonaws.com/" + key
+	}
+	return t.endpoint + "/" + uriEncode(t.bucket, true) + "/" + key
+}
+
+// Scheme returns the scheme of the destinations the writer handles.
+func (w *ObjectWriter) Scheme() string {
+	return w.scheme
+}
+
+// WriteOutput uploads the kubeconfig at path to the destination object.
+func (w *ObjectWriter) WriteOutput(ctx context.Context, destination *url.URL, path string) error {
+	target, err := w.parseObjectTarget(destination)
+	if err != nil {
+		return err
+	}
+	creds := credentials{
+		accessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if creds.accessKeyID == "" || creds.secretAccessKey == "" {
+		return errors.New("no object storage credentials (set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY)")
+	}
+	content, err := w.fs.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read merged kubeconfig: %w", err)
+	}
+
+	objectURL := target.objectURL()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL, bytes.NewReader(content))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	payloadHash := hashHex(content)
+	req.Header.Set("Content-Type", "application/yaml")
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	for name, value := range target.headers {
+		req.Header.Set(name, value)
+	}
+	signV4(req, creds, target.region, "s3", payloadHash, w.clock.Now())
+
+	if err := w.send(req); err != nil {
+		return fmt.Errorf("failed to write %s://%s/%s: %w", w.scheme, target.bucket, target.key, err)
+	}
+
+	w.logger.InfoContext(ctx, "Wrote merged kubeconfig to object storage",
+		"bucket", target.bucket,
+		"key", target.key,
+		"url", objectURL)
+	return nil
+}
+
+// send makes the upload request, returning an error carrying the service's message if it did not succeed.
+func (w *ObjectWriter) send(req *http.Request) error {
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	w.logger.DebugContext(req.Context(), "Object storage request",
+		"method", req.Method, "url", req.URL.Redacted(), "status", resp.StatusCode)
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+
+	var apiError struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	if xml.Unmarshal(respBody, &apiError) != nil || apiError.Code == "" {
+		return fmt.Errorf("object storage returned %s", resp.Status)
+	}
+	return fmt.Errorf("object storage returned %s: %s: %s", resp.Status, apiError.Code, apiError.Message)
+}
@@ -0,0 +1,180 @@
+package output
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"cowpoke/internal/domain"
+)
+
+const (
+	// SecretScheme is the scheme of Kubernetes Secret destinations.
+	SecretScheme = "secret"
+	// defaultSecretKey is the key of the Secret's data that holds the kubeconfig.
+	defaultSecretKey = "config"
+	// managedByLabel marks the Secrets cowpoke creates.
+	managedByLabel = "app.kubernetes.io/managed-by"
+	// maxErrorBody caps how much of an error response is read for its message.
+	maxErrorBody = 64 << 10
+)
+
+// SecretWriter writes merged kubeconfigs into Kubernetes Secrets, reaching the cluster through a context
+// of the user's own kubeconfig. Destinations look like
+//
+//	secret://namespace/name?context=admin&key=config&kubeconfig=/path/to/kubeconfig
+//
+// where context defaults to the current context, key to "config" and kubeconfig to the usual KUBECONFIG
+// and ~/.kube/config lookup. The Secret is created if it does not exist; otherwise only its key is
+// replaced, leaving its other keys, labels and annotations alone.
+type SecretWriter struct {
+	fs     domain.FileSystemAdapter
+	logger *slog.Logger
+}
+
+// NewSecretWriter creates a new Kubernetes Secret writer.
+func NewSecretWriter(fs domain.FileSystemAdapter, logger *slog.Logger) *SecretWriter {
+	return &SecretWriter{
+		fs:     fs,
+		logger: logger,
+	}
+}
+
+// secretTarget is a parsed Secret destination.
+type secretTarget struct {
+	namespace  string
+	name       string
+	key        string
+	context    string
+	kubeconfig string
+}
+
+// parseSecretTarget reads the namespace, name and options of a Secret destination.
+func parseSecretTarget(destination *url.URL) (secretTarget, error) {
+	query := destination.Query()
+	target := secretTarget{
+		namespace:  destination.Host,
+		name:       strings.Trim(destination.Path, "/"),
+		key:        cmp.Or(query.Get("key"), defaultSecretKey),
+		context:    query.Get("context"),
+		kubeconfig: query.Get("kubeconfig"),
+	}
+	if target.namespace == "" || target.name == "" || strings.Contains(target.name, "/") {
+		return secretTarget{}, fmt.Errorf("invalid secret destination %q (use secret://namespace/name)",
+			destination.Redacted())
+	}
+	return target, nil
+}
+
+// Scheme returns the scheme of Kubernetes Secret destinations.
+func (w *SecretWriter) Scheme() string {
+	return SecretScheme
+}
+
+// WriteOutput stores the kubeconfig at path in the destination Secret.
+func (w *SecretWriter) WriteOutput(ctx context.Context, destination *url.URL, path string) error {
+	target, err := parseSecretTarget(destination)
+	if err != nil {
+		return err
+	}
+	content, err := w.fs.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read merged kubeconfig: %w", err)
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = target.kubeconfig
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules, &clientcmd.ConfigOverrides{CurrentContext: target.context}).ClientConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load context %q: %w", target.context, err)
+	}
+	client, err := rest.HTTPClientFor(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	secretsURL := strings.TrimRight(restConfig.Host, "/") +
+		"/api/v1/namespaces/" + url.PathEscape(target.namespace) + "/secrets"
+	data := map[string]string{target.key: base64.StdEncoding.EncodeToString(content)}
+
+	// Patch the Secret's key in place, and create the Secret if the patch finds none.
+	patch := map[string]any{"data": data}
+	status, err := w.send(ctx, client, http.MethodPatch, secretsURL+"/"+url.PathEscape(target.name),
+		"application/merge-patch+json", patch)
+	if status == http.StatusNotFound {
+		secret := map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]any{
+				"name":      target.name,
+				"namespace": target.namespace,
+				"labels":    map[string]string{managedByLabel: "cowpoke"},
+			},
+			"type": "Opaque",
+			"data": data,
+		}
+		_, err = w.send(ctx, client, http.MethodPost, secretsURL, "application/json", secret)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write secret %s/%s: %w", target.namespace, target.name, err)
+	}
+
+	w.logger.InfoContext(ctx, "Wrote merged kubeconfig to secret",
+		"namespace", target.namespace,
+		"name", target.name,
+		"key", target.key,
+		"host", restConfig.Host)
+	return nil
+}
+
+// send makes a request with body encoded as JSON, returning the response status and an error carrying the
+// API's message if the request did not succeed.
+func (w *SecretWriter) send(
+	ctx context.Context,
+	client *http.Client,
+	method, endpoint, contentType string,
+	body any,
+) (int, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	w.logger.DebugContext(ctx, "Kubernetes API request", "method", method, "url", endpoint, "status", resp.StatusCode)
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return resp.StatusCode, nil
+	}
+
+	var apiStatus struct {
+		Message string `json:"message"`
+	}
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	if json.Unmarshal(respBody, &apiStatus) != nil || apiStatus.Message == "" {
+		return resp.StatusCode, fmt.Errorf("kubernetes API returned %s", resp.Status)
+	}
+	return resp.StatusCode, fmt.Errorf("kubernetes API returned %s: %s", resp.Status, apiStatus.Message)
+}
@@ -0,0 +1,185 @@
+package output
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cowpoke/internal/adapters/filesystem"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// apiRequest is a request received by the fake Kubernetes API.
+type apiRequest struct {
+	Method      string
+	Path        string
+	ContentType string
+	Auth        string
+	Body        map[string]any
+}
+
+// writeTestFiles writes a merged kubeconfig and a kubeconfig with an "admin" context for the API at
+// serverURL, returning their paths.
+func writeTestFiles(t *testing.T, serverURL string) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	merged := filepath.Join(dir, "merged.yaml")
+	require.NoError(t, os.WriteFile(merged, []byte("kind: Config\n"), 0o600))
+	kubeconfig := filepath.Join(dir, "admin.yaml")
+	require.NoError(t, os.WriteFile(kubeconfig, fmt.Appendf(nil, `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: %s
+    insecure-skip-tls-verify: true
+  name: ci
+contexts:
+- context:
+    cluster: ci
+    user: admin
+  name: admin
+users:
+- name: admin
+  user:
+    token: admin-token
+current-context: admin
+`, serverURL), 0o600))
+	return merged, kubeconfig
+}
+
+// fakeAPI serves the Kubernetes API, answering each request with the next status and recording it. It uses
+// TLS, since client-go only sends credentials to https servers.
+func fakeAPI(t *testing.T, statuses ...int) (*httptest.Server, *[]apiRequest) {
+	t.Helper()
+	var requests []apiRequest
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received := apiRequest{
+			Method:      r.Method,
+			Path:        r.URL.Path,
+			ContentType: r.Header.Get("Content-Type"),
+			Auth:        r.Header.Get("Authorization"),
+		}
+		_ = json.Unmarshal(body, &received.Body)
+		requests = append(requests, received)
+
+		status := statuses[len(requests)-1]
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status >= http.StatusBadRequest {
+			_, _ = fmt.Fprintf(w, `{"kind":"Status","message":"status %d"}`, status)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestSecretWriter_WriteOutput_PatchesExistingSecret(t *testing.T) {
+	// Arrange
+	server, requests := fakeAPI(t, http.StatusOK)
+	merged, kubeconfig := writeTestFiles(t, server.URL)
+	destination, err := url.Parse("secret://ci/cowpoke?context=admin&key=kubeconfig&kubeconfig=" + kubeconfig)
+	require.NoError(t, err)
+	writer := NewSecretWriter(filesystem.New(), testutil.Logger())
+
+	// Act
+	err = writer.WriteOutput(context.Background(), destination, merged)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, *requests, 1)
+	patch := (*requests)[0]
+	assert.Equal(t, http.MethodPatch, patch.Method)
+	assert.Equal(t, "/api/v1/namespaces/ci/secrets/cowpoke", patch.Path)
+	assert.Equal(t, "application/merge-patch+json", patch.ContentType)
+	assert.Equal(t, "Bearer admin-token", patch.Auth)
+	assert.Equal(t, map[string]any{
+		"data": map[string]any{"kubeconfig": base64.StdEncoding.EncodeToString([]byte("kind: Config\n"))},
+	}, patch.Body)
+}
+
+func TestSecretWriter_WriteOutput_CreatesMissingSecret(t *testing.T) {
+	// Arrange
+	server, requests := fakeAPI(t, http.StatusNotFound, http.StatusCreated)
+	merged, kubeconfig := writeTestFiles(t, server.URL)
+	destination, err := url.Parse("secret://ci/cowpoke?kubeconfig=" + kubeconfig)
+	require.NoError(t, err)
+	writer := NewSecretWriter(filesystem.New(), testutil.Logger())
+
+	// Act
+	err = writer.WriteOutput(context.Background(), destination, merged)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, *requests, 2)
+	create := (*requests)[1]
+	assert.Equal(t, http.MethodPost, create.Method)
+	assert.Equal(t, "/api/v1/namespaces/ci/secrets", create.Path)
+	assert.Equal(t, map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]any{
+			"name":      "cowpoke",
+			"namespace": "ci",
+			"labels":    map[string]any{"app.kubernetes.io/managed-by": "cowpoke"},
+		},
+		"type": "Opaque",
+		"data": map[string]any{"config": base64.StdEncoding.EncodeToString([]byte("kind: Config\n"))},
+	}, create.Body)
+}
+
+func TestSecretWriter_WriteOutput_ReportsAPIError(t *testing.T) {
+	// Arrange
+	server, _ := fakeAPI(t, http.StatusForbidden)
+	merged, kubeconfig := writeTestFiles(t, server.URL)
+	destination, err := url.Parse("secret://ci/cowpoke?kubeconfig=" + kubeconfig)
+	require.NoError(t, err)
+	writer := NewSecretWriter(filesystem.New(), testutil.Logger())
+
+	// Act
+	err = writer.WriteOutput(context.Background(), destination, merged)
+
+	// Assert
+	require.Error(t, err)
+	assert.Equal(t, "failed to write secret ci/cowpoke: kubernetes API returned 403 Forbidden: status 403", err.Error())
+}
+
+func TestSecretWriter_WriteOutput_InvalidDestination(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination string
+	}{
+		{name: "no namespace", destination: "secret:///cowpoke"},
+		{name: "no name", destination: "secret://ci"},
+		{name: "nested name", destination: "secret://ci/a/b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			destination, err := url.Parse(tt.destination)
+			require.NoError(t, err)
+			writer := NewSecretWriter(filesystem.New(), testutil.Logger())
+
+			// Act
+			err = writer.WriteOutput(context.Background(), destination, "/unused")
+
+			// Assert
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "use secret://namespace/name")
+		})
+	}
+}
@@ -1,23 +1,35 @@
 package rancher
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"mime"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"cowpoke/internal/domain"
 )
 
+// steveClustersPath is the Steve (/v1) endpoint for management clusters, used when the Norman API is unavailable.
+const steveClustersPath = "/v1/management.cattle.io.clusters"
+
 // Client handles all Rancher API operations.
 type Client struct {
 	httpAdapter domain.HTTPAdapter
+	clock       domain.Clock
 	logger      *slog.Logger
+
+	// endpoints remembers, by server ID, the kubeconfig endpoint that last worked.
+	endpointsMu sync.Mutex
+	endpoints   map[string]kubeconfigEndpoint
 }
 
 // normalizeURL removes trailing slashes from a URL to ensure consistent API endpoint construction.
@@ -26,19 +38,31 @@ func normalizeURL(url string) string {
 }
 
 // NewClient creates a new Rancher client.
-func NewClient(httpAdapter domain.HTTPAdapter, logger *slog.Logger) *Client {
+func NewClient(httpAdapter domain.HTTPAdapter, clock domain.Clock, logger *slog.Logger) *Client {
 	return &Client{
 		httpAdapter: httpAdapter,
+		clock:       clock,
 		logger:      logger,
+		endpoints:   make(map[string]kubeconfigEndpoint),
 	}
 }
 
+// CloseHost stops the SSH tunnel to the server at serverURL and closes its idle connections.
+func (c *Client) CloseHost(serverURL string) error {
+	return c.httpAdapter.CloseHost(serverURL)
+}
+
 // Authenticate performs authentication with a Rancher server.
 func (c *Client) Authenticate(
 	ctx context.Context,
 	server domain.ConfigServer,
 	password string,
 ) (domain.AuthToken, error) {
+	if server.AuthType == domain.AuthTypeToken {
+		return nil, fmt.Errorf("%s authenticates with an API token, not a password (%w)",
+			server.URL, domain.ErrUnauthorized)
+	}
+
 	authURL := fmt.Sprintf("%s/v3-public/%sProviders/%s?action=login",
 		normalizeURL(server.URL), server.AuthType, server.AuthType)
 
@@ -57,6 +81,10 @@ func (c *Client) Authenticate(
 	}
 	defer resp.Body.Close()
 
+	if unavailableErr := checkAvailable(resp); unavailableErr != nil {
+		return nil, fmt.Errorf("authentication failed: %w", unavailableErr)
+	}
+
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read auth response body: %w", err)
@@ -65,14 +93,14 @@ func (c *Client) Authenticate(
 	var authResp authResponse
 	err = json.Unmarshal(bodyBytes, &authResp)
 	if err != nil {
-		return nil, fmt.Errorf("authentication failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+		return nil, &statusError{operation: "authentication", status: resp.StatusCode, body: bodyBytes}
 	}
 
 	if authResp.Token == "" {
 		if len(bodyBytes) > 0 {
-			return nil, fmt.Errorf("authentication failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+			return nil, &statusError{operation: "authentication", status: resp.StatusCode, body: bodyBytes}
 		}
-		return nil, fmt.Errorf("authentication failed: no token in response (status %d)", resp.StatusCode)
+		return nil, &statusError{operation: "authentication", status: resp.StatusCode}
 	}
 
 	c.logger.InfoContext(ctx, "Authentication successful",
@@ -86,16 +114,18 @@ func (c *Client) Authenticate(
 		}
 	}
 	if expiresAt.IsZero() && authResp.TTL > 0 {
-		expiresAt = time.Now().Add(time.Duration(authResp.TTL) * time.Millisecond)
+		expiresAt = c.clock.Now().Add(time.Duration(authResp.TTL) * time.Millisecond)
 	}
 	if expiresAt.IsZero() {
 		// Final fallback to default 16 hours (Rancher's typical default).
-		expiresAt = time.Now().Add(16 * time.Hour) //nolint:mnd // Rancher default session TTL
+		expiresAt = c.clock.Now().Add(16 * time.Hour) //nolint:mnd // Rancher default session TTL
 	}
 
 	return &token{
+		id:        cmp.Or(authResp.ID, domain.TokenID(authResp.Token)),
 		value:     authResp.Token,
 		expiresAt: expiresAt,
+		clock:     c.clock,
 	}, nil
 }
 
@@ -115,18 +145,28 @@ func (c *Client) ListClusters(
 	}
 	defer resp.Body.Close()
 
+	if unavailableErr := checkAvailable(resp); unavailableErr != nil {
+		return nil, fmt.Errorf("list clusters failed: %w", unavailableErr)
+	}
+
+	if normanDisabled(resp.StatusCode) {
+		c.logger.InfoContext(ctx, "Norman cluster API unavailable, falling back to Steve API",
+			"server", server.URL,
+			"status", resp.StatusCode)
+		return c.listClustersSteve(ctx, token, server)
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf("list clusters failed: %w", domain.ErrUnauthorized)
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf(
-			"list clusters failed with status %d: %s",
-			resp.StatusCode,
-			string(body),
-		)
+		return nil, newStatusError("list clusters", resp)
 	}
 
 	var clustersResp clustersResponse
 	if decodeErr := json.NewDecoder(resp.Body).Decode(&clustersResp); decodeErr != nil {
-		return nil, fmt.Errorf("failed to decode clusters response: %w", decodeErr)
+		return nil, fmt.Errorf("failed to decode clusters response: %w (%w)", decodeErr, domain.ErrMalformedResponse)
 	}
 
 	clusters := make([]domain.Cluster, 0, len(clustersResp.Data))
@@ -137,9 +177,11 @@ func (c *Client) ListClusters(
 		}
 
 		clusters = append(clusters, domain.Cluster{
-			ID:   cluster.ID,
-			Name: cluster.Name,
-			Type: cluster.Type,
+			ID:          cluster.ID,
+			Name:        cluster.Name,
+			DisplayName: cluster.Labels[displayNameLabel],
+			Type:        clusterType(cluster.Labels, cluster.Provider, cluster.Driver),
+			CreatorID:   cluster.CreatorID,
 		})
 	}
 
@@ -150,56 +192,233 @@ func (c *Client) ListClusters(
 	return clusters, nil
 }
 
-// GetKubeconfig retrieves the kubeconfig for a specific cluster.
-func (c *Client) GetKubeconfig(
+// listClustersSteve retrieves all clusters using the Steve API, following pagination links.
+func (c *Client) listClustersSteve(
 	ctx context.Context,
 	token domain.AuthToken,
 	server domain.ConfigServer,
-	clusterID string,
-) ([]byte, error) {
-	kubeconfigURL := fmt.Sprintf(
-		"%s/v3/clusters/%s?action=generateKubeconfig",
-		normalizeURL(server.URL),
-		clusterID,
-	)
-
-	c.logger.InfoContext(ctx, "Fetching kubeconfig for cluster",
+) ([]domain.Cluster, error) {
+	var clusters []domain.Cluster
+	nextURL := normalizeURL(server.URL) + steveClustersPath
+
+	for nextURL != "" {
+		page, err := c.getSteveClusters(ctx, token, nextURL)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range page.Data {
+			cluster := item.toCluster()
+			if cluster.ID == "" || cluster.Name == "" {
+				c.logger.WarnContext(ctx, "Skipping invalid cluster", "id", cluster.ID, "name", cluster.Name)
+				continue
+			}
+			clusters = append(clusters, cluster)
+		}
+		nextURL = page.Pagination.Next
+	}
+
+	c.logger.InfoContext(ctx, "Successfully fetched clusters using Steve API",
 		"server", server.URL,
-		"cluster", clusterID)
+		"count", len(clusters))
+
+	return clusters, nil
+}
 
-	resp, err := c.httpAdapter.PostWithAuth(ctx, kubeconfigURL, token.Value(), nil)
+// getSteveClusters fetches a single page of the Steve clusters collection.
+func (c *Client) getSteveClusters(
+	ctx context.Context,
+	token domain.AuthToken,
+	url string,
+) (*steveClustersResponse, error) {
+	resp, err := c.httpAdapter.GetWithAuth(ctx, url, token.Value())
 	if err != nil {
-		return nil, fmt.Errorf("failed to get kubeconfig: %w", err)
+		return nil, fmt.Errorf("failed to list clusters: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf(
-			"get kubeconfig failed with status %d: %s",
-			resp.StatusCode,
-			string(body),
-		)
+	if unavailableErr := checkAvailable(resp); unavailableErr != nil {
+		return nil, fmt.Errorf("list clusters failed: %w", unavailableErr)
 	}
 
-	var kubeconfigResp kubeconfigResponse
-	if decodeErr := json.NewDecoder(resp.Body).Decode(&kubeconfigResp); decodeErr != nil {
-		return nil, fmt.Errorf("failed to decode kubeconfig response: %w", decodeErr)
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError("list clusters", resp)
 	}
 
-	if kubeconfigResp.Config == "" {
-		return nil, errors.New("kubeconfig generation succeeded but no config was returned")
+	var page steveClustersResponse
+	if decodeErr := json.NewDecoder(resp.Body).Decode(&page); decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode clusters response: %w (%w)", decodeErr, domain.ErrMalformedResponse)
 	}
+	return &page, nil
+}
 
-	c.logger.InfoContext(ctx, "Successfully fetched kubeconfig",
-		"server", server.URL,
-		"cluster", clusterID)
+// checkAvailable returns ErrServerUnavailable for gateway errors and for successful responses that are
+// not JSON, which is how maintenance pages and login portals in front of Rancher typically answer. Other
+// error responses are left to the caller so that API fallbacks still apply.
+func checkAvailable(resp *http.Response) error {
+	contentType := resp.Header.Get("Content-Type")
+	switch {
+	case resp.StatusCode == http.StatusBadGateway,
+		resp.StatusCode == http.StatusServiceUnavailable,
+		resp.StatusCode == http.StatusGatewayTimeout:
+	case resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices && !isJSON(contentType):
+	default:
+		return nil
+	}
+	return fmt.Errorf("%w: server appears to be in maintenance or behind a login portal (status %d, content type %q)",
+		domain.ErrServerUnavailable, resp.StatusCode, contentType)
+}
+
+// statusError is an unexpected response status. It wraps the domain error matching the status, if any,
+// so that failures can be classified.
+type statusError struct {
+	operation string
+	status    int
+	body      []byte
+}
 
-	return []byte(kubeconfigResp.Config), nil
+// newStatusError reads the body of an unexpected response to describe it.
+func newStatusError(operation string, resp *http.Response) *statusError {
+	body, _ := io.ReadAll(resp.Body)
+	return &statusError{operation: operation, status: resp.StatusCode, body: body}
+}
+
+func (e *statusError) Error() string {
+	if len(e.body) == 0 {
+		return fmt.Sprintf("%s failed with status %d", e.operation, e.status)
+	}
+	return fmt.Sprintf("%s failed with status %d: %s", e.operation, e.status, string(e.body))
+}
+
+func (e *statusError) Unwrap() error {
+	switch e.status {
+	case http.StatusUnauthorized:
+		return domain.ErrUnauthorized
+	case http.StatusForbidden:
+		return domain.ErrForbidden
+	case http.StatusTooManyRequests:
+		return domain.ErrRateLimited
+	default:
+		return nil
+	}
+}
+
+// isJSON reports whether a Content-Type header denotes JSON. Responses without one are given the
+// benefit of the doubt.
+func isJSON(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+// normanDisabled reports whether a status code indicates the Norman (/v3) API has been disabled or blocked.
+func normanDisabled(statusCode int) bool {
+	return statusCode == http.StatusNotFound ||
+		statusCode == http.StatusForbidden ||
+		statusCode == http.StatusMethodNotAllowed
+}
+
+// GetVersion retrieves the Rancher release running on a server. It queries the public
+// /rancherversion endpoint and falls back to the server-version setting.
+func (c *Client) GetVersion(ctx context.Context, server domain.ConfigServer) (domain.ServerVersion, error) {
+	baseURL := normalizeURL(server.URL)
+
+	var versionResp versionResponse
+	versionErr := c.getJSON(ctx, baseURL+"/rancherversion", &versionResp)
+	if versionErr == nil && versionResp.Version != "" {
+		return domain.ServerVersion{Version: versionResp.Version, GitCommit: versionResp.GitCommit}, nil
+	}
+
+	var setting settingResponse
+	if err := c.getJSON(ctx, baseURL+"/v3/settings/server-version", &setting); err != nil {
+		return domain.ServerVersion{}, fmt.Errorf("failed to detect Rancher version: %w", errors.Join(versionErr, err))
+	}
+	if setting.Value == "" {
+		return domain.ServerVersion{}, errors.New("failed to detect Rancher version: server-version setting is empty")
+	}
+	return domain.ServerVersion{Version: setting.Value}, nil
+}
+
+// authProvidersResponse is the public list of a server's enabled authentication providers.
+type authProvidersResponse struct {
+	Data []struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	} `json:"data"`
+}
+
+// AuthProviders lists the authentication providers enabled on a server. The list is public, so it also
+// shows whether the server is reachable before any credentials are used.
+func (c *Client) AuthProviders(ctx context.Context, server domain.ConfigServer) ([]domain.AuthProvider, error) {
+	var providersResp authProvidersResponse
+	if err := c.getJSON(ctx, normalizeURL(server.URL)+"/v3-public/authProviders", &providersResp); err != nil {
+		return nil, fmt.Errorf("failed to list auth providers: %w", err)
+	}
+
+	providers := make([]domain.AuthProvider, 0, len(providersResp.Data))
+	for _, provider := range providersResp.Data {
+		providers = append(providers, domain.AuthProvider{ID: provider.ID, Type: provider.Type})
+	}
+	c.logger.DebugContext(ctx, "Listed auth providers", "server", server.URL, "providers", len(providers))
+	return providers, nil
+}
+
+// RevokeToken deletes a token in Rancher. Tokens that no longer exist or no longer authenticate are
+// already unusable and are not treated as errors.
+func (c *Client) RevokeToken(ctx context.Context, token domain.AuthToken, server domain.ConfigServer) error {
+	tokenURL := fmt.Sprintf("%s/v3/tokens/%s", normalizeURL(server.URL), url.PathEscape(token.ID()))
+
+	c.logger.InfoContext(ctx, "Revoking token", "server", server.URL, "token", token.ID())
+
+	resp, err := c.httpAdapter.DeleteWithAuth(ctx, tokenURL, token.Value())
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if unavailableErr := checkAvailable(resp); unavailableErr != nil {
+		return fmt.Errorf("revoke token failed: %w", unavailableErr)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	case http.StatusNotFound, http.StatusUnauthorized:
+		c.logger.DebugContext(ctx, "Token already revoked or expired",
+			"server", server.URL,
+			"status", resp.StatusCode)
+		return nil
+	default:
+		return newStatusError("revoke token", resp)
+	}
+}
+
+// getJSON performs an unauthenticated GET and decodes a JSON response.
+func (c *Client) getJSON(ctx context.Context, url string, v any) error {
+	resp, err := c.httpAdapter.Get(ctx, url)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if unavailableErr := checkAvailable(resp); unavailableErr != nil {
+		return fmt.Errorf("request to %s failed: %w", url, unavailableErr)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return &statusError{operation: "request to " + url, status: resp.StatusCode}
+	}
+	if decodeErr := json.NewDecoder(resp.Body).Decode(v); decodeErr != nil {
+		return fmt.Errorf("failed to decode response from %s: %w (%w)", url, decodeErr, domain.ErrMalformedResponse)
+	}
+	return nil
 }
 
 // authResponse represents the Rancher authentication response.
 type authResponse struct {
+	ID        string `json:"id"`
 	Token     string `json:"token"`
 	Type      string `json:"type"`
 	UserID    string `json:"userId"`
@@ -210,12 +429,15 @@ type authResponse struct {
 
 // token implements the AuthToken interface.
 type token struct {
+	id        string
 	value     string
 	expiresAt time.Time
+	clock     domain.Clock
 }
 
+func (t *token) ID() string           { return t.id }
 func (t *token) Value() string        { return t.value }
-func (t *token) IsValid() bool        { return time.Now().Before(t.expiresAt) }
+func (t *token) IsValid() bool        { return t.expiresAt.IsZero() || t.clock.Now().Before(t.expiresAt) }
 func (t *token) ExpiresAt() time.Time { return t.expiresAt }
 
 // clustersResponse represents the Rancher clusters list response.
@@ -225,12 +447,83 @@ type clustersResponse struct {
 
 // clusterData represents a single cluster in the response.
 type clusterData struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-	Type string `json:"type"`
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Driver    string            `json:"driver"`
+	Provider  string            `json:"provider"`
+	CreatorID string            `json:"creatorId"`
+	Labels    map[string]string `json:"labels"`
+}
+
+// providerLabel is set by Rancher on clusters provisioned by or imported from a known provider, e.g. Harvester.
+const providerLabel = "provider.cattle.io"
+
+// displayNameLabel carries the display name of clusters managed through Fleet, which the Norman API does
+// not otherwise report.
+const displayNameLabel = "management.cattle.io/cluster-display-name"
+
+// creatorIDAnnotation carries the ID of the user who created a cluster, which the Steve API reports only
+// as an annotation.
+const creatorIDAnnotation = "field.cattle.io/creatorId"
+
+// clusterType derives a cluster's provider, preferring the provider label over the provider and driver fields.
+func clusterType(labels map[string]string, provider, driver string) string {
+	for _, candidate := range []string{labels[providerLabel], provider, driver} {
+		if candidate != "" {
+			return strings.ToLower(candidate)
+		}
+	}
+	return ""
+}
+
+// steveClustersResponse represents a page of the Steve management clusters collection.
+type steveClustersResponse struct {
+	Data       []steveCluster `json:"data"`
+	Pagination struct {
+		Next string `json:"next"`
+	} `json:"pagination"`
+}
+
+// steveCluster represents a management.cattle.io Cluster resource.
+type steveCluster struct {
+	ID       string `json:"id"`
+	Metadata struct {
+		Name        string            `json:"name"`
+		Labels      map[string]string `json:"labels"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+	Spec struct {
+		DisplayName string `json:"displayName"`
+	} `json:"spec"`
+	Status struct {
+		Driver   string `json:"driver"`
+		Provider string `json:"provider"`
+	} `json:"status"`
+}
+
+// toCluster normalizes a Steve cluster into the domain type used by the Norman API.
+func (s steveCluster) toCluster() domain.Cluster {
+	id := s.Metadata.Name
+	if id == "" {
+		id = s.ID
+	}
+	displayName := cmp.Or(s.Spec.DisplayName, s.Metadata.Labels[displayNameLabel])
+	return domain.Cluster{
+		ID:          id,
+		Name:        cmp.Or(displayName, id),
+		DisplayName: displayName,
+		Type:        clusterType(s.Metadata.Labels, s.Status.Provider, s.Status.Driver),
+		CreatorID:   s.Metadata.Annotations[creatorIDAnnotation],
+	}
+}
+
+// versionResponse represents the public /rancherversion response.
+type versionResponse struct {
+	Version   string `json:"Version"`
+	GitCommit string `json:"GitCommit"`
 }
 
-// kubeconfigResponse represents the Rancher kubeconfig generation response.
-type kubeconfigResponse struct {
-	Config string `json:"config"`
+// settingResponse represents a Rancher setting.
+type settingResponse struct {
+	Value string `json:"value"`
 }
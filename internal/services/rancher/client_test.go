@@ -1,9 +1,26 @@
 package rancher
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	cowpokehttp "cowpoke/internal/adapters/http"
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNormalizeURL(t *testing.T) {
@@ -46,3 +63,463 @@ func TestNormalizeURL(t *testing.T) {
 		})
 	}
 }
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func TestListClusters_FallsBackToSteveAPI(t *testing.T) {
+	// Arrange
+	httpAdapter := mocks.NewMockHTTPAdapter(t)
+	authToken := mocks.NewMockAuthToken(t)
+	authToken.On("Value").Return("token")
+	server := domain.ConfigServer{URL: "https://rancher.example.com/"}
+
+	httpAdapter.On("GetWithAuth", mock.Anything, "https://rancher.example.com/v3/clusters", "token").
+		Return(jsonResponse(http.StatusNotFound, "not found"), nil)
+	httpAdapter.On("GetWithAuth", mock.Anything, "https://rancher.example.com"+steveClustersPath, "token").
+		Return(jsonResponse(http.StatusOK, `{
+			"data": [
+				{"id": "c-m-abc", "metadata": {"name": "c-m-abc"}, "spec": {"displayName": "prod"},
+				 "status": {"driver": "rke2"}},
+				{"id": "local", "metadata": {"name": "local"}, "spec": {}, "status": {"provider": "k3s"}}
+			],
+			"pagination": {"next": "https://rancher.example.com/v1/page2"}
+		}`), nil)
+	httpAdapter.On("GetWithAuth", mock.Anything, "https://rancher.example.com/v1/page2", "token").
+		Return(jsonResponse(http.StatusOK, `{"data": [{"metadata": {"name": "c-m-def"},
+			"spec": {"displayName": "staging"}, "status": {"driver": "imported"}}]}`), nil)
+
+	client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+	// Act
+	clusters, err := client.ListClusters(context.Background(), authToken, server)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Cluster{
+		{ID: "c-m-abc", Name: "prod", DisplayName: "prod", Type: "rke2"},
+		{ID: "local", Name: "local", Type: "k3s"},
+		{ID: "c-m-def", Name: "staging", DisplayName: "staging", Type: "imported"},
+	}, clusters)
+}
+
+func TestListClusters_ReportsFleetDisplayName(t *testing.T) {
+	// Arrange
+	httpAdapter := mocks.NewMockHTTPAdapter(t)
+	authToken := mocks.NewMockAuthToken(t)
+	authToken.On("Value").Return("token")
+	server := domain.ConfigServer{URL: "https://rancher.example.com"}
+
+	httpAdapter.On("GetWithAuth", mock.Anything, "https://rancher.example.com/v3/clusters", "token").
+		Return(jsonResponse(http.StatusOK, `{"data": [
+			{"id": "c-m-abc", "name": "c-m-abc", "driver": "imported",
+			 "labels": {"management.cattle.io/cluster-display-name": "Prod EU"}},
+			{"id": "local", "name": "local", "provider": "k3s"}
+		]}`), nil)
+
+	client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+	// Act
+	clusters, err := client.ListClusters(context.Background(), authToken, server)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Cluster{
+		{ID: "c-m-abc", Name: "c-m-abc", DisplayName: "Prod EU", Type: "imported"},
+		{ID: "local", Name: "local", Type: "k3s"},
+	}, clusters)
+}
+
+func TestListClusters_NormanErrorDoesNotFallBack(t *testing.T) {
+	// Arrange
+	httpAdapter := mocks.NewMockHTTPAdapter(t)
+	authToken := mocks.NewMockAuthToken(t)
+	authToken.On("Value").Return("token")
+	server := domain.ConfigServer{URL: "https://rancher.example.com"}
+
+	httpAdapter.On("GetWithAuth", mock.Anything, "https://rancher.example.com/v3/clusters", "token").
+		Return(jsonResponse(http.StatusInternalServerError, "boom"), nil)
+
+	client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+	// Act
+	_, err := client.ListClusters(context.Background(), authToken, server)
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 500")
+}
+
+func TestListClusters_UnauthorizedIsReported(t *testing.T) {
+	// Arrange
+	httpAdapter := mocks.NewMockHTTPAdapter(t)
+	authToken := mocks.NewMockAuthToken(t)
+	authToken.On("Value").Return("revoked")
+	server := domain.ConfigServer{URL: "https://rancher.example.com"}
+
+	httpAdapter.On("GetWithAuth", mock.Anything, "https://rancher.example.com/v3/clusters", "revoked").
+		Return(jsonResponse(http.StatusUnauthorized, `{"message":"must authenticate"}`), nil)
+
+	client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+	// Act
+	_, err := client.ListClusters(context.Background(), authToken, server)
+
+	// Assert
+	require.ErrorIs(t, err, domain.ErrUnauthorized)
+}
+
+func TestListClusters_RateLimitIsReported(t *testing.T) {
+	// Arrange
+	httpAdapter := mocks.NewMockHTTPAdapter(t)
+	authToken := mocks.NewMockAuthToken(t)
+	authToken.On("Value").Return("token")
+	server := domain.ConfigServer{URL: "https://rancher.example.com"}
+
+	httpAdapter.On("GetWithAuth", mock.Anything, "https://rancher.example.com/v3/clusters", "token").
+		Return(jsonResponse(http.StatusTooManyRequests, `{"message":"slow down"}`), nil)
+
+	client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+	// Act
+	_, err := client.ListClusters(context.Background(), authToken, server)
+
+	// Assert
+	require.ErrorIs(t, err, domain.ErrRateLimited)
+	assert.Contains(t, err.Error(), "list clusters failed with status 429")
+}
+
+func TestAuthenticate_TokenExpiresAfterTTL(t *testing.T) {
+	// Arrange
+	httpAdapter := mocks.NewMockHTTPAdapter(t)
+	clock := testutil.NewClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+
+	httpAdapter.On("Post", mock.Anything, mock.Anything, mock.Anything).
+		Return(jsonResponse(http.StatusCreated, `{"token":"token-abc:secret","ttl":3600000}`), nil)
+
+	client := NewClient(httpAdapter, clock, testutil.Logger())
+
+	// Act
+	token, err := client.Authenticate(context.Background(), server, "password")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "token-abc", token.ID())
+	assert.Equal(t, clock.Now().Add(time.Hour), token.ExpiresAt())
+	assert.True(t, token.IsValid())
+
+	clock.Advance(time.Hour)
+	assert.False(t, token.IsValid())
+}
+
+func TestRevokeToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "deleted", status: http.StatusNoContent},
+		{name: "already deleted", status: http.StatusNotFound},
+		{name: "already expired", status: http.StatusUnauthorized},
+		{name: "server error", status: http.StatusInternalServerError, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			httpAdapter := mocks.NewMockHTTPAdapter(t)
+			authToken := mocks.NewMockAuthToken(t)
+			authToken.On("ID").Return("token-abc")
+			authToken.On("Value").Return("token-abc:secret")
+			server := domain.ConfigServer{URL: "https://rancher.example.com/"}
+
+			httpAdapter.On("DeleteWithAuth", mock.Anything, "https://rancher.example.com/v3/tokens/token-abc",
+				"token-abc:secret").Return(jsonResponse(tt.status, ""), nil)
+
+			client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+			// Act
+			err := client.RevokeToken(context.Background(), authToken, server)
+
+			// Assert
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAuthProviders(t *testing.T) {
+	// Arrange
+	httpAdapter := mocks.NewMockHTTPAdapter(t)
+	httpAdapter.On("Get", mock.Anything, "https://rancher.example.com/v3-public/authProviders").
+		Return(jsonResponse(http.StatusOK, `{"data": [
+			{"id": "local", "type": "localProvider"},
+			{"id": "activedirectory", "type": "activeDirectoryProvider"}
+		]}`), nil)
+
+	client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+	// Act
+	providers, err := client.AuthProviders(context.Background(), domain.ConfigServer{URL: "https://rancher.example.com/"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []domain.AuthProvider{
+		{ID: "local", Type: "localProvider"},
+		{ID: "activedirectory", Type: "activeDirectoryProvider"},
+	}, providers)
+}
+
+func TestClusterOwnership(t *testing.T) {
+	// Arrange
+	httpAdapter := mocks.NewMockHTTPAdapter(t)
+	authToken := mocks.NewMockAuthToken(t)
+	authToken.On("Value").Return("token-abc:secret")
+
+	httpAdapter.On("GetWithAuth", mock.Anything, "https://rancher.example.com/v3/users?me=true", "token-abc:secret").
+		Return(jsonResponse(http.StatusOK, `{"data": [{"id": "u-abc"}]}`), nil)
+	httpAdapter.On("GetWithAuth", mock.Anything,
+		"https://rancher.example.com/v3/clusterroletemplatebindings?limit=-1&roleTemplateId=cluster-owner&userId=u-abc",
+		"token-abc:secret").
+		Return(jsonResponse(http.StatusOK, `{"data": [{"clusterId": "c-m-prod"}, {"clusterId": "c-m-lab"}]}`), nil)
+
+	client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+	// Act
+	ownership, err := client.ClusterOwnership(context.Background(), authToken,
+		domain.ConfigServer{URL: "https://rancher.example.com/"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, domain.ClusterOwnership{UserID: "u-abc", OwnerOf: []string{"c-m-prod", "c-m-lab"}}, ownership)
+	assert.True(t, ownership.Owns(domain.Cluster{ID: "c-m-dev", CreatorID: "u-abc"}))
+	assert.True(t, ownership.Owns(domain.Cluster{ID: "c-m-lab", CreatorID: "u-other"}))
+	assert.False(t, ownership.Owns(domain.Cluster{ID: "c-m-shared"}))
+}
+
+func TestClusterType(t *testing.T) {
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		provider string
+		driver   string
+		expected string
+	}{
+		{
+			name:     "harvester label wins",
+			labels:   map[string]string{providerLabel: "harvester"},
+			provider: "rke2",
+			driver:   "imported",
+			expected: "harvester",
+		},
+		{
+			name:     "provider before driver",
+			provider: "k3s",
+			driver:   "imported",
+			expected: "k3s",
+		},
+		{
+			name:     "driver is normalized to lower case",
+			driver:   "EKS",
+			expected: "eks",
+		},
+		{
+			name:     "unknown",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, clusterType(tt.labels, tt.provider, tt.driver))
+		})
+	}
+}
+
+func TestClient_ReplaysRecordedSync(t *testing.T) {
+	// Arrange
+	adapter := cowpokehttp.NewAdapter(time.Second, false, testutil.Logger(),
+		cowpokehttp.WithReplay(filepath.Join("testdata", "replay")))
+	client := NewClient(adapter, domain.SystemClock{}, testutil.Logger())
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	ctx := context.Background()
+
+	// Act
+	token, authErr := client.Authenticate(ctx, server, "password")
+	require.NoError(t, authErr)
+	clusters, listErr := client.ListClusters(ctx, token, server)
+	require.NoError(t, listErr)
+	kubeconfig, getErr := client.GetKubeconfig(ctx, token, server, "c-m-prod")
+	require.NoError(t, getErr)
+
+	// Assert
+	assert.Equal(t, "[REDACTED]", token.Value())
+	assert.Equal(t, []domain.Cluster{{ID: "c-m-prod", Name: "prod", Type: "rke2"}}, clusters)
+	assert.Contains(t, string(kubeconfig), "server: https://rancher.example.com/k8s/clusters/c-m-prod")
+	assert.NotContains(t, string(kubeconfig), "s3cr3t")
+}
+
+func TestAuthenticate_UntrustedCertificateIsNetworkError(t *testing.T) {
+	// Arrange
+	rancher := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer rancher.Close()
+
+	adapter := cowpokehttp.NewAdapter(time.Second, false, testutil.Logger())
+	client := NewClient(adapter, domain.SystemClock{}, testutil.Logger())
+	server := domain.ConfigServer{URL: rancher.URL, Username: "admin", AuthType: "local"}
+
+	// Act
+	start := time.Now()
+	_, err := client.Authenticate(context.Background(), server, "password")
+
+	// Assert
+	require.ErrorIs(t, err, domain.ErrNetwork)
+	assert.Equal(t, domain.FailureNetwork, domain.ClassifyFailure(err))
+	assert.Less(t, time.Since(start), time.Second, "certificate errors should not be retried")
+}
+
+func TestListClusters_MaintenancePageIsUnavailable(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+	}{
+		{name: "maintenance page", status: http.StatusOK, contentType: "text/html; charset=utf-8"},
+		{name: "service unavailable", status: http.StatusServiceUnavailable, contentType: "text/html"},
+		{name: "bad gateway without content type", status: http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			httpAdapter := mocks.NewMockHTTPAdapter(t)
+			authToken := mocks.NewMockAuthToken(t)
+			authToken.On("Value").Return("token")
+			server := domain.ConfigServer{URL: "https://rancher.example.com"}
+
+			resp := jsonResponse(tt.status, "<html><body>Down for maintenance</body></html>")
+			resp.Header = http.Header{"Content-Type": []string{tt.contentType}}
+			httpAdapter.On("GetWithAuth", mock.Anything, "https://rancher.example.com/v3/clusters", "token").
+				Return(resp, nil)
+
+			client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+			// Act
+			_, err := client.ListClusters(context.Background(), authToken, server)
+
+			// Assert
+			require.ErrorIs(t, err, domain.ErrServerUnavailable)
+			assert.Contains(t, err.Error(), "maintenance or behind a login portal")
+		})
+	}
+}
+
+func TestAuthenticate_LoginPortalIsUnavailable(t *testing.T) {
+	// Arrange
+	httpAdapter := mocks.NewMockHTTPAdapter(t)
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+
+	resp := jsonResponse(http.StatusOK, "<html><form action=\"/sso\"></form></html>")
+	resp.Header = http.Header{"Content-Type": []string{"text/html"}}
+	httpAdapter.On("Post", mock.Anything, mock.Anything, mock.Anything).Return(resp, nil)
+
+	client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+	// Act
+	_, err := client.Authenticate(context.Background(), server, "password")
+
+	// Assert
+	require.ErrorIs(t, err, domain.ErrServerUnavailable)
+}
+
+func TestClient_ErrorsAreClassified(t *testing.T) {
+	const clustersURL = "https://rancher.example.com/v3/clusters"
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+
+	listClusters := func(resp *http.Response, err error) func(*testing.T, *Client, *mocks.MockHTTPAdapter) error {
+		return func(t *testing.T, client *Client, httpAdapter *mocks.MockHTTPAdapter) error {
+			httpAdapter.On("GetWithAuth", mock.Anything, clustersURL, "token").Return(resp, err)
+			authToken := mocks.NewMockAuthToken(t)
+			authToken.On("Value").Return("token")
+			_, listErr := client.ListClusters(context.Background(), authToken, server)
+			return listErr
+		}
+	}
+
+	tests := []struct {
+		name     string
+		call     func(*testing.T, *Client, *mocks.MockHTTPAdapter) error
+		expected domain.FailureCategory
+	}{
+		{
+			name: "rejected login",
+			call: func(t *testing.T, client *Client, httpAdapter *mocks.MockHTTPAdapter) error {
+				httpAdapter.On("Post", mock.Anything, mock.Anything, mock.Anything).
+					Return(jsonResponse(http.StatusUnauthorized, `{"message":"invalid credentials"}`), nil)
+				_, err := client.Authenticate(context.Background(), server, "wrong")
+				return err
+			},
+			expected: domain.FailureAuth,
+		},
+		{
+			name: "denied revocation",
+			call: func(t *testing.T, client *Client, httpAdapter *mocks.MockHTTPAdapter) error {
+				httpAdapter.On("DeleteWithAuth", mock.Anything, "https://rancher.example.com/v3/tokens/token-abc", "token").
+					Return(jsonResponse(http.StatusForbidden, `{"message":"forbidden"}`), nil)
+				authToken := mocks.NewMockAuthToken(t)
+				authToken.On("ID").Return("token-abc")
+				authToken.On("Value").Return("token")
+				return client.RevokeToken(context.Background(), authToken, server)
+			},
+			expected: domain.FailurePermission,
+		},
+		{
+			name:     "throttled",
+			call:     listClusters(jsonResponse(http.StatusTooManyRequests, "slow down"), nil),
+			expected: domain.FailureRateLimit,
+		},
+		{
+			name: "connection refused",
+			call: listClusters(nil, fmt.Errorf("failed to execute authenticated GET request: %w",
+				&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")})),
+			expected: domain.FailureNetwork,
+		},
+		{
+			name:     "gateway error",
+			call:     listClusters(jsonResponse(http.StatusBadGateway, "bad gateway"), nil),
+			expected: domain.FailureNetwork,
+		},
+		{
+			name:     "malformed response",
+			call:     listClusters(jsonResponse(http.StatusOK, `{"data": [`), nil),
+			expected: domain.FailureParse,
+		},
+		{
+			name:     "server error",
+			call:     listClusters(jsonResponse(http.StatusInternalServerError, "boom"), nil),
+			expected: domain.FailureOther,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			httpAdapter := mocks.NewMockHTTPAdapter(t)
+			client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+			// Act
+			err := tt.call(t, client, httpAdapter)
+
+			// Assert
+			require.Error(t, err)
+			assert.Equal(t, tt.expected, domain.ClassifyFailure(err))
+		})
+	}
+}
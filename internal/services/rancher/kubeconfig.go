@@ -0,0 +1,477 @@
+package rancher
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"cowpoke/internal/domain"
+)
+
+// kubeconfigEndpoint is a way of obtaining a cluster's kubeconfig, in order of preference.
+type kubeconfigEndpoint int
+
+const (
+	// normanKubeconfig generates a kubeconfig with the Norman generateKubeconfig action.
+	normanKubeconfig kubeconfigEndpoint = iota
+	// steveKubeconfig generates a kubeconfig with the Steve generateKubeconfig action.
+	steveKubeconfig
+	// proxyKubeconfig builds a kubeconfig for Rancher's /k8s/clusters proxy using the session token.
+	proxyKubeconfig
+)
+
+// String returns the endpoint's name for logs.
+func (e kubeconfigEndpoint) String() string {
+	switch e {
+	case normanKubeconfig:
+		return "norman"
+	case steveKubeconfig:
+		return "steve"
+	default:
+		return "proxy"
+	}
+}
+
+// errEndpointUnavailable reports that a server does not provide a kubeconfig endpoint.
+var errEndpointUnavailable = errors.New("kubeconfig endpoint unavailable")
+
+// unavailableEndpoint reports that the kubeconfig endpoint at url answered with status. A denied request
+// also wraps ErrForbidden, so that the failure is classified as a permission problem if no endpoint works.
+func unavailableEndpoint(url string, status int) error {
+	if status == http.StatusForbidden {
+		return fmt.Errorf("%w: %s returned status %d (%w)", errEndpointUnavailable, url, status, domain.ErrForbidden)
+	}
+	return fmt.Errorf("%w: %s returned status %d", errEndpointUnavailable, url, status)
+}
+
+// GetKubeconfig retrieves the kubeconfig for a specific cluster. Endpoints are tried in order of
+// preference, starting from the one that last worked for the server, until one is available.
+func (c *Client) GetKubeconfig(
+	ctx context.Context,
+	token domain.AuthToken,
+	server domain.ConfigServer,
+	clusterID string,
+) ([]byte, error) {
+	c.logger.InfoContext(ctx, "Fetching kubeconfig for cluster",
+		"server", server.URL,
+		"cluster", clusterID)
+
+	var errs []error
+	for endpoint := c.kubeconfigEndpoint(server); endpoint <= proxyKubeconfig; endpoint++ {
+		kubeconfig, err := c.fetchKubeconfig(ctx, endpoint, token, server, clusterID)
+		if errors.Is(err, errEndpointUnavailable) {
+			c.logger.DebugContext(ctx, "Kubeconfig endpoint unavailable, trying the next",
+				"server", server.URL,
+				"endpoint", endpoint,
+				"error", err)
+			errs = append(errs, err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		c.rememberKubeconfigEndpoint(server, endpoint)
+		c.logger.InfoContext(ctx, "Successfully fetched kubeconfig",
+			"server", server.URL,
+			"cluster", clusterID,
+			"endpoint", endpoint)
+		return kubeconfig, nil
+	}
+	return nil, fmt.Errorf("get kubeconfig failed: %w", errors.Join(errs...))
+}
+
+// kubeconfigEndpoint returns the endpoint to try first for server.
+func (c *Client) kubeconfigEndpoint(server domain.ConfigServer) kubeconfigEndpoint {
+	c.endpointsMu.Lock()
+	defer c.endpointsMu.Unlock()
+	return c.endpoints[server.ID()]
+}
+
+// rememberKubeconfigEndpoint records the endpoint that worked for server, so later clusters skip
+// the endpoints it lacks.
+func (c *Client) rememberKubeconfigEndpoint(server domain.ConfigServer, endpoint kubeconfigEndpoint) {
+	c.endpointsMu.Lock()
+	defer c.endpointsMu.Unlock()
+	c.endpoints[server.ID()] = endpoint
+}
+
+// fetchKubeconfig obtains a kubeconfig from a single endpoint.
+func (c *Client) fetchKubeconfig(
+	ctx context.Context,
+	endpoint kubeconfigEndpoint,
+	token domain.AuthToken,
+	server domain.ConfigServer,
+	clusterID string,
+) ([]byte, error) {
+	baseURL := normalizeURL(server.URL)
+	switch endpoint {
+	case normanKubeconfig:
+		return c.generateKubeconfig(ctx, token,
+			fmt.Sprintf("%s/v3/clusters/%s?action=generateKubeconfig", baseURL, url.PathEscape(clusterID)))
+	case steveKubeconfig:
+		return c.generateKubeconfig(ctx, token,
+			fmt.Sprintf("%s%s/%s?action=generateKubeconfig", baseURL, steveClustersPath, url.PathEscape(clusterID)))
+	default:
+		return c.proxyKubeconfig(ctx, token, server, clusterID)
+	}
+}
+
+// generateKubeconfig invokes a generateKubeconfig action.
+func (c *Client) generateKubeconfig(ctx context.Context, token domain.AuthToken, actionURL string) ([]byte, error) {
+	resp, err := c.httpAdapter.PostWithAuth(ctx, actionURL, token.Value(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubeconfig: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if unavailableErr := checkAvailable(resp); unavailableErr != nil {
+		return nil, fmt.Errorf("get kubeconfig failed: %w", unavailableErr)
+	}
+
+	if normanDisabled(resp.StatusCode) {
+		return nil, unavailableEndpoint(actionURL, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, newStatusError("get kubeconfig", resp)
+	}
+
+	var kubeconfigResp kubeconfigResponse
+	if decodeErr := json.NewDecoder(resp.Body).Decode(&kubeconfigResp); decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode kubeconfig response: %w (%w)", decodeErr, domain.ErrMalformedResponse)
+	}
+
+	if kubeconfigResp.Config == "" {
+		return nil, fmt.Errorf("%w: kubeconfig generation succeeded but no config was returned",
+			domain.ErrInvalidKubeconfig)
+	}
+	return []byte(kubeconfigResp.Config), nil
+}
+
+// proxyKubeconfig builds a kubeconfig for the cluster's /k8s/clusters proxy once the proxy accepts the
+// token. The kubeconfig authenticates with the session token, so it stops working when that expires.
+func (c *Client) proxyKubeconfig(
+	ctx context.Context,
+	token domain.AuthToken,
+	server domain.ConfigServer,
+	clusterID string,
+) ([]byte, error) {
+	proxyURL := fmt.Sprintf("%s/k8s/clusters/%s", normalizeURL(server.URL), url.PathEscape(clusterID))
+
+	resp, err := c.httpAdapter.GetWithAuth(ctx, proxyURL+"/version", token.Value())
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach cluster proxy: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if unavailableErr := checkAvailable(resp); unavailableErr != nil {
+		return nil, fmt.Errorf("cluster proxy failed: %w", unavailableErr)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return nil, fmt.Errorf("cluster proxy rejected the token: %w", domain.ErrUnauthorized)
+	case normanDisabled(resp.StatusCode):
+		return nil, unavailableEndpoint(proxyURL, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return nil, newStatusError("cluster proxy", resp)
+	}
+
+	c.logger.WarnContext(ctx, "Rancher cannot generate kubeconfigs, using the cluster proxy with the session token",
+		"server", server.URL,
+		"cluster", clusterID,
+		"expires_at", token.ExpiresAt())
+
+	name := c.clusterName(ctx, token, server, clusterID)
+	config := api.NewConfig()
+	config.Clusters[name] = &api.Cluster{Server: proxyURL}
+	config.AuthInfos[name] = &api.AuthInfo{Token: token.Value()}
+	config.Contexts[name] = &api.Context{Cluster: name, AuthInfo: name}
+	config.CurrentContext = name
+
+	kubeconfig, err := clientcmd.Write(*config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write proxy kubeconfig: %w", err)
+	}
+	return kubeconfig, nil
+}
+
+// clusterName looks up a cluster's display name for naming a built kubeconfig, falling back to its ID.
+func (c *Client) clusterName(
+	ctx context.Context,
+	token domain.AuthToken,
+	server domain.ConfigServer,
+	clusterID string,
+) string {
+	clusterURL := fmt.Sprintf("%s%s/%s", normalizeURL(server.URL), steveClustersPath, url.PathEscape(clusterID))
+	resp, err := c.httpAdapter.GetWithAuth(ctx, clusterURL, token.Value())
+	if err != nil {
+		return clusterID
+	}
+	defer resp.Body.Close()
+
+	var cluster steveCluster
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&cluster) != nil {
+		return clusterID
+	}
+	return cluster.toCluster().Name
+}
+
+// ScopeKubeconfig replaces the tokens of a generated kubeconfig with a new API token scoped to the cluster
+// and expiring after ttl, since generated kubeconfig tokens always get the server's default TTL. The tokens
+// it replaces are revoked, except for the session token that the cluster proxy kubeconfig uses.
+func (c *Client) ScopeKubeconfig(
+	ctx context.Context,
+	session domain.AuthToken,
+	server domain.ConfigServer,
+	clusterID string,
+	kubeconfig []byte,
+	ttl time.Duration,
+) ([]byte, time.Time, error) {
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to parse kubeconfig: %w (%w)", err, domain.ErrInvalidKubeconfig)
+	}
+
+	scoped, err := c.createScopedToken(ctx, session, server, clusterID, ttl)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var replaced []string
+	for _, authInfo := range config.AuthInfos {
+		if authInfo.Token == "" || authInfo.Token == scoped.Value() {
+			continue
+		}
+		if authInfo.Token != session.Value() {
+			replaced = append(replaced, domain.TokenID(authInfo.Token))
+		}
+		authInfo.Token = scoped.Value()
+	}
+
+	scopedKubeconfig, err := clientcmd.Write(*config)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to write scoped kubeconfig: %w", err)
+	}
+
+	for _, id := range replaced {
+		generated := &token{id: id, value: session.Value(), clock: c.clock}
+		if revokeErr := c.RevokeToken(ctx, generated, server); revokeErr != nil {
+			c.logger.WarnContext(ctx, "Could not revoke replaced kubeconfig token",
+				"server", server.URL,
+				"cluster", clusterID,
+				"token", id,
+				"error", revokeErr)
+		}
+	}
+
+	c.logger.DebugContext(ctx, "Scoped kubeconfig token",
+		"server", server.URL,
+		"cluster", clusterID,
+		"expires_at", scoped.ExpiresAt())
+	return scopedKubeconfig, scoped.ExpiresAt(), nil
+}
+
+// ProxyKubeconfig points every cluster of a generated kubeconfig at Rancher's /k8s/clusters proxy, including
+// the cluster's own endpoints (Authorized Cluster Endpoints). Their contexts are kept, so their names stay
+// usable. The rewritten clusters trust the certificate authority of the proxy's cluster entry, if any,
+// instead of the downstream cluster's.
+func (c *Client) ProxyKubeconfig(
+	ctx context.Context,
+	server domain.ConfigServer,
+	clusterID string,
+	kubeconfig []byte,
+) ([]byte, error) {
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig: %w (%w)", err, domain.ErrInvalidKubeconfig)
+	}
+
+	proxyURL := fmt.Sprintf("%s/k8s/clusters/%s", normalizeURL(server.URL), url.PathEscape(clusterID))
+	proxy := &api.Cluster{}
+	for _, cluster := range config.Clusters {
+		if cluster.Server == proxyURL {
+			proxy = cluster
+			break
+		}
+	}
+
+	rewritten := 0
+	for _, cluster := range config.Clusters {
+		if cluster.Server == proxyURL {
+			continue
+		}
+		cluster.Server = proxyURL
+		cluster.CertificateAuthority = proxy.CertificateAuthority
+		cluster.CertificateAuthorityData = proxy.CertificateAuthorityData
+		cluster.TLSServerName = proxy.TLSServerName
+		cluster.InsecureSkipTLSVerify = proxy.InsecureSkipTLSVerify
+		rewritten++
+	}
+	if rewritten == 0 {
+		return kubeconfig, nil
+	}
+
+	proxied, err := clientcmd.Write(*config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write proxied kubeconfig: %w", err)
+	}
+	c.logger.DebugContext(ctx, "Pointed kubeconfig endpoints at the cluster proxy",
+		"server", server.URL,
+		"cluster", clusterID,
+		"endpoints", rewritten)
+	return proxied, nil
+}
+
+// KubeconfigExpiry looks up when the tokens of a kubeconfig expire. A kubeconfig that authenticates with the
+// session token, such as one built for the cluster proxy, expires with the session.
+func (c *Client) KubeconfigExpiry(
+	ctx context.Context,
+	session domain.AuthToken,
+	server domain.ConfigServer,
+	kubeconfig []byte,
+) (time.Time, error) {
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse kubeconfig: %w (%w)", err, domain.ErrInvalidKubeconfig)
+	}
+
+	var earliest time.Time
+	for _, authInfo := range config.AuthInfos {
+		if authInfo.Token == "" {
+			continue
+		}
+
+		expiresAt := session.ExpiresAt()
+		if authInfo.Token != session.Value() {
+			expiresAt, err = c.tokenExpiry(ctx, session, server, domain.TokenID(authInfo.Token))
+			if err != nil {
+				return time.Time{}, err
+			}
+		}
+		if !expiresAt.IsZero() && (earliest.IsZero() || expiresAt.Before(earliest)) {
+			earliest = expiresAt
+		}
+	}
+	return earliest, nil
+}
+
+// tokenExpiry returns when the token with the given ID expires, or zero if it never does.
+func (c *Client) tokenExpiry(
+	ctx context.Context,
+	session domain.AuthToken,
+	server domain.ConfigServer,
+	id string,
+) (time.Time, error) {
+	tokenURL := fmt.Sprintf("%s/v3/tokens/%s", normalizeURL(server.URL), url.PathEscape(id))
+	resp, err := c.httpAdapter.GetWithAuth(ctx, tokenURL, session.Value())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to look up token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if unavailableErr := checkAvailable(resp); unavailableErr != nil {
+		return time.Time{}, fmt.Errorf("look up token failed: %w", unavailableErr)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return time.Time{}, newStatusError("look up token", resp)
+	}
+
+	var found authResponse
+	if decodeErr := json.NewDecoder(resp.Body).Decode(&found); decodeErr != nil {
+		return time.Time{}, fmt.Errorf("failed to decode token response: %w (%w)", decodeErr, domain.ErrMalformedResponse)
+	}
+	if found.ExpiresAt == "" {
+		return time.Time{}, nil
+	}
+	expiresAt, err := time.Parse(time.RFC3339, found.ExpiresAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse token expiry %q: %w", found.ExpiresAt, domain.ErrMalformedResponse)
+	}
+	return expiresAt, nil
+}
+
+// createScopedToken creates an API token that only grants access to clusterID and expires after ttl.
+func (c *Client) createScopedToken(
+	ctx context.Context,
+	session domain.AuthToken,
+	server domain.ConfigServer,
+	clusterID string,
+	ttl time.Duration,
+) (domain.AuthToken, error) {
+	payload := map[string]any{
+		"type":        "token",
+		"description": "cowpoke kubeconfig for cluster " + clusterID,
+		"clusterId":   clusterID,
+		"ttl":         ttl.Milliseconds(),
+	}
+	return c.createToken(ctx, session, server, "kubeconfig token", payload)
+}
+
+// CreateAPIToken creates an API token that is not scoped to a cluster and does not expire, unless the
+// server caps the lifetime of tokens.
+func (c *Client) CreateAPIToken(
+	ctx context.Context,
+	session domain.AuthToken,
+	server domain.ConfigServer,
+) (domain.AuthToken, error) {
+	payload := map[string]any{
+		"type":        "token",
+		"description": "cowpoke API token for " + server.Username,
+		"ttl":         0,
+	}
+	c.logger.InfoContext(ctx, "Creating API token", "server", server.URL, "username", server.Username)
+	return c.createToken(ctx, session, server, "API token", payload)
+}
+
+// createToken posts payload to the tokens endpoint and returns the token created. A token without an
+// expiry or TTL never expires.
+func (c *Client) createToken(
+	ctx context.Context,
+	session domain.AuthToken,
+	server domain.ConfigServer,
+	kind string,
+	payload map[string]any,
+) (domain.AuthToken, error) {
+	resp, err := c.httpAdapter.PostWithAuth(ctx, normalizeURL(server.URL)+"/v3/tokens", session.Value(), payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", kind, err)
+	}
+	defer resp.Body.Close()
+
+	if unavailableErr := checkAvailable(resp); unavailableErr != nil {
+		return nil, fmt.Errorf("create %s failed: %w", kind, unavailableErr)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, newStatusError("create "+kind, resp)
+	}
+
+	var created authResponse
+	if decodeErr := json.NewDecoder(resp.Body).Decode(&created); decodeErr != nil || created.Token == "" {
+		return nil, fmt.Errorf("failed to decode %s response: %w", kind, domain.ErrMalformedResponse)
+	}
+
+	expiresAt, parseErr := time.Parse(time.RFC3339, created.ExpiresAt)
+	if parseErr != nil && created.TTL > 0 {
+		expiresAt = c.clock.Now().Add(time.Duration(created.TTL) * time.Millisecond)
+	}
+	return &token{
+		id:        cmp.Or(created.ID, domain.TokenID(created.Token)),
+		value:     created.Token,
+		expiresAt: expiresAt,
+		clock:     c.clock,
+	}, nil
+}
+
+// kubeconfigResponse represents the Rancher kubeconfig generation response.
+type kubeconfigResponse struct {
+	Config string `json:"config"`
+}
@@ -0,0 +1,403 @@
+package rancher
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	normanActionURL = "https://rancher.example.com/v3/clusters/c-m-abc?action=generateKubeconfig"
+	steveActionURL  = "https://rancher.example.com/v1/management.cattle.io.clusters/c-m-abc?action=generateKubeconfig"
+)
+
+func TestGetKubeconfig_UsesNormanAction(t *testing.T) {
+	// Arrange
+	httpAdapter := mocks.NewMockHTTPAdapter(t)
+	authToken := mocks.NewMockAuthToken(t)
+	authToken.On("Value").Return("token")
+	server := domain.ConfigServer{URL: "https://rancher.example.com/"}
+
+	httpAdapter.On("PostWithAuth", mock.Anything, normanActionURL, "token", mock.Anything).
+		Return(jsonResponse(http.StatusOK, `{"config":"apiVersion: v1"}`), nil)
+
+	client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+	// Act
+	kubeconfig, err := client.GetKubeconfig(context.Background(), authToken, server, "c-m-abc")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "apiVersion: v1", string(kubeconfig))
+}
+
+func TestGetKubeconfig_FallsBackToSteveActionAndRemembersIt(t *testing.T) {
+	// Arrange
+	httpAdapter := mocks.NewMockHTTPAdapter(t)
+	authToken := mocks.NewMockAuthToken(t)
+	authToken.On("Value").Return("token")
+	server := domain.ConfigServer{URL: "https://rancher.example.com"}
+
+	httpAdapter.On("PostWithAuth", mock.Anything, normanActionURL, "token", mock.Anything).
+		Return(jsonResponse(http.StatusNotFound, "not found"), nil).Once()
+	httpAdapter.On("PostWithAuth", mock.Anything, steveActionURL, "token", mock.Anything).
+		Return(jsonResponse(http.StatusOK, `{"config":"apiVersion: v1"}`), nil).Once()
+	httpAdapter.On("PostWithAuth", mock.Anything, steveActionURL, "token", mock.Anything).
+		Return(jsonResponse(http.StatusOK, `{"config":"apiVersion: v1"}`), nil).Once()
+
+	client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+	// Act
+	_, firstErr := client.GetKubeconfig(context.Background(), authToken, server, "c-m-abc")
+	kubeconfig, secondErr := client.GetKubeconfig(context.Background(), authToken, server, "c-m-abc")
+
+	// Assert
+	require.NoError(t, firstErr)
+	require.NoError(t, secondErr)
+	assert.Equal(t, "apiVersion: v1", string(kubeconfig))
+	httpAdapter.AssertNumberOfCalls(t, "PostWithAuth", 3)
+}
+
+func TestGetKubeconfig_FallsBackToClusterProxy(t *testing.T) {
+	// Arrange
+	httpAdapter := mocks.NewMockHTTPAdapter(t)
+	authToken := mocks.NewMockAuthToken(t)
+	authToken.On("Value").Return("token")
+	authToken.On("ExpiresAt").Return(time.Time{})
+	server := domain.ConfigServer{URL: "https://rancher.example.com"}
+
+	httpAdapter.On("PostWithAuth", mock.Anything, normanActionURL, "token", mock.Anything).
+		Return(jsonResponse(http.StatusForbidden, "forbidden"), nil)
+	httpAdapter.On("PostWithAuth", mock.Anything, steveActionURL, "token", mock.Anything).
+		Return(jsonResponse(http.StatusMethodNotAllowed, "method not allowed"), nil)
+	httpAdapter.On("GetWithAuth", mock.Anything, "https://rancher.example.com/k8s/clusters/c-m-abc/version", "token").
+		Return(jsonResponse(http.StatusOK, `{"gitVersion":"v1.30.4"}`), nil)
+	httpAdapter.On("GetWithAuth", mock.Anything, "https://rancher.example.com"+steveClustersPath+"/c-m-abc", "token").
+		Return(jsonResponse(http.StatusOK,
+			`{"id":"c-m-abc","metadata":{"name":"c-m-abc"},"spec":{"displayName":"prod"}}`), nil)
+
+	client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+	// Act
+	kubeconfig, err := client.GetKubeconfig(context.Background(), authToken, server, "c-m-abc")
+
+	// Assert
+	require.NoError(t, err)
+	config, err := clientcmd.Load(kubeconfig)
+	require.NoError(t, err)
+	require.Contains(t, config.Contexts, "prod")
+	assert.Equal(t, "https://rancher.example.com/k8s/clusters/c-m-abc", config.Clusters["prod"].Server)
+	assert.Equal(t, "token", config.AuthInfos["prod"].Token)
+}
+
+func TestGetKubeconfig_ErrorDoesNotFallBack(t *testing.T) {
+	// Arrange
+	httpAdapter := mocks.NewMockHTTPAdapter(t)
+	authToken := mocks.NewMockAuthToken(t)
+	authToken.On("Value").Return("token")
+	server := domain.ConfigServer{URL: "https://rancher.example.com"}
+
+	httpAdapter.On("PostWithAuth", mock.Anything, normanActionURL, "token", mock.Anything).
+		Return(jsonResponse(http.StatusInternalServerError, "boom"), nil)
+
+	client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+	// Act
+	_, err := client.GetKubeconfig(context.Background(), authToken, server, "c-m-abc")
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 500")
+}
+
+func TestGetKubeconfig_DeniedByEveryEndpointIsForbidden(t *testing.T) {
+	// Arrange
+	httpAdapter := mocks.NewMockHTTPAdapter(t)
+	authToken := mocks.NewMockAuthToken(t)
+	authToken.On("Value").Return("token")
+	server := domain.ConfigServer{URL: "https://rancher.example.com"}
+
+	httpAdapter.On("PostWithAuth", mock.Anything, normanActionURL, "token", mock.Anything).
+		Return(jsonResponse(http.StatusForbidden, "forbidden"), nil)
+	httpAdapter.On("PostWithAuth", mock.Anything, steveActionURL, "token", mock.Anything).
+		Return(jsonResponse(http.StatusForbidden, "forbidden"), nil)
+	httpAdapter.On("GetWithAuth", mock.Anything, "https://rancher.example.com/k8s/clusters/c-m-abc/version", "token").
+		Return(jsonResponse(http.StatusForbidden, "forbidden"), nil)
+
+	client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+	// Act
+	_, err := client.GetKubeconfig(context.Background(), authToken, server, "c-m-abc")
+
+	// Assert
+	require.ErrorIs(t, err, domain.ErrForbidden)
+	assert.Equal(t, domain.FailurePermission, domain.ClassifyFailure(err))
+}
+
+const generatedKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: prod
+  cluster:
+    server: https://rancher.example.com/k8s/clusters/c-m-abc
+users:
+- name: prod
+  user:
+    token: kubeconfig-u-abc:secret
+contexts:
+- name: prod
+  context:
+    cluster: prod
+    user: prod
+current-context: prod
+`
+
+func TestScopeKubeconfig_ReplacesAndRevokesGeneratedToken(t *testing.T) {
+	// Arrange
+	httpAdapter := mocks.NewMockHTTPAdapter(t)
+	session := mocks.NewMockAuthToken(t)
+	session.On("Value").Return("session")
+	server := domain.ConfigServer{URL: "https://rancher.example.com"}
+
+	httpAdapter.On("PostWithAuth", mock.Anything, "https://rancher.example.com/v3/tokens", "session",
+		map[string]any{
+			"type":        "token",
+			"description": "cowpoke kubeconfig for cluster c-m-abc",
+			"clusterId":   "c-m-abc",
+			"ttl":         int64(30 * 24 * time.Hour / time.Millisecond),
+		}).
+		Return(jsonResponse(http.StatusCreated,
+			`{"id":"token-xyz","token":"token-xyz:scoped","expiresAt":"2025-02-01T00:00:00Z"}`), nil)
+	httpAdapter.On("DeleteWithAuth", mock.Anything, "https://rancher.example.com/v3/tokens/kubeconfig-u-abc",
+		"session").Return(jsonResponse(http.StatusNoContent, ""), nil)
+
+	client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+	// Act
+	kubeconfig, expiresAt, err := client.ScopeKubeconfig(context.Background(), session, server, "c-m-abc",
+		[]byte(generatedKubeconfig), 30*24*time.Hour)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), expiresAt)
+	config, loadErr := clientcmd.Load(kubeconfig)
+	require.NoError(t, loadErr)
+	assert.Equal(t, "token-xyz:scoped", config.AuthInfos["prod"].Token)
+	assert.Equal(t, "https://rancher.example.com/k8s/clusters/c-m-abc", config.Clusters["prod"].Server)
+}
+
+func TestScopeKubeconfig_KeepsSessionToken(t *testing.T) {
+	// Arrange
+	httpAdapter := mocks.NewMockHTTPAdapter(t)
+	session := mocks.NewMockAuthToken(t)
+	session.On("Value").Return("kubeconfig-u-abc:secret")
+	server := domain.ConfigServer{URL: "https://rancher.example.com"}
+	clock := testutil.NewClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	httpAdapter.On("PostWithAuth", mock.Anything, "https://rancher.example.com/v3/tokens",
+		"kubeconfig-u-abc:secret", mock.Anything).
+		Return(jsonResponse(http.StatusCreated, `{"token":"token-xyz:scoped","ttl":3600000}`), nil)
+
+	client := NewClient(httpAdapter, clock, testutil.Logger())
+
+	// Act
+	kubeconfig, expiresAt, err := client.ScopeKubeconfig(context.Background(), session, server, "c-m-abc",
+		[]byte(generatedKubeconfig), time.Hour)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 1, 1, 0, 0, 0, time.UTC), expiresAt)
+	assert.Contains(t, string(kubeconfig), "token-xyz:scoped")
+	httpAdapter.AssertNotCalled(t, "DeleteWithAuth", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestScopeKubeconfig_TokenCreationRefused(t *testing.T) {
+	// Arrange
+	httpAdapter := mocks.NewMockHTTPAdapter(t)
+	session := mocks.NewMockAuthToken(t)
+	session.On("Value").Return("session")
+	server := domain.ConfigServer{URL: "https://rancher.example.com"}
+
+	httpAdapter.On("PostWithAuth", mock.Anything, "https://rancher.example.com/v3/tokens", "session", mock.Anything).
+		Return(jsonResponse(http.StatusUnprocessableEntity, `{"message":"ttl exceeds max ttl"}`), nil)
+
+	client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+	// Act
+	_, _, err := client.ScopeKubeconfig(context.Background(), session, server, "c-m-abc",
+		[]byte(generatedKubeconfig), 365*24*time.Hour)
+
+	// Assert
+	require.ErrorContains(t, err, "create kubeconfig token")
+}
+
+func TestProxyKubeconfig_RoutesClusterEndpointsThroughRancher(t *testing.T) {
+	// Arrange
+	kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- name: prod
+  cluster:
+    server: https://rancher.example.com/k8s/clusters/c-m-abc
+    certificate-authority-data: cmFuY2hlci1jYQ==
+- name: prod-fqdn
+  cluster:
+    server: https://prod.internal.example.com
+    certificate-authority-data: ZG93bnN0cmVhbS1jYQ==
+users:
+- name: prod
+  user:
+    token: kubeconfig-u-abc:secret
+contexts:
+- name: prod
+  context:
+    cluster: prod
+    user: prod
+- name: prod-fqdn
+  context:
+    cluster: prod-fqdn
+    user: prod
+current-context: prod
+`
+	server := domain.ConfigServer{URL: "https://rancher.example.com/"}
+	client := NewClient(mocks.NewMockHTTPAdapter(t), domain.SystemClock{}, testutil.Logger())
+
+	// Act
+	proxied, err := client.ProxyKubeconfig(context.Background(), server, "c-m-abc", []byte(kubeconfig))
+
+	// Assert
+	require.NoError(t, err)
+	config, loadErr := clientcmd.Load(proxied)
+	require.NoError(t, loadErr)
+	assert.Len(t, config.Contexts, 2, "contexts of the cluster's own endpoints are kept")
+	for _, name := range []string{"prod", "prod-fqdn"} {
+		assert.Equal(t, "https://rancher.example.com/k8s/clusters/c-m-abc", config.Clusters[name].Server)
+		assert.Equal(t, []byte("rancher-ca"), config.Clusters[name].CertificateAuthorityData)
+	}
+}
+
+func TestProxyKubeconfig_LeavesProxyKubeconfigUnchanged(t *testing.T) {
+	// Arrange
+	server := domain.ConfigServer{URL: "https://rancher.example.com"}
+	client := NewClient(mocks.NewMockHTTPAdapter(t), domain.SystemClock{}, testutil.Logger())
+
+	// Act
+	proxied, err := client.ProxyKubeconfig(context.Background(), server, "c-m-abc", []byte(generatedKubeconfig))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, generatedKubeconfig, string(proxied))
+}
+
+func TestCreateAPIToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		response      string
+		wantExpiresAt time.Time
+	}{
+		{
+			name:     "never expires",
+			response: `{"id":"token-api","token":"token-api:secret","ttl":0}`,
+		},
+		{
+			name:          "capped by the server",
+			response:      `{"id":"token-api","token":"token-api:secret","expiresAt":"2025-04-01T00:00:00Z"}`,
+			wantExpiresAt: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			httpAdapter := mocks.NewMockHTTPAdapter(t)
+			session := mocks.NewMockAuthToken(t)
+			session.On("Value").Return("session")
+			server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin"}
+
+			httpAdapter.On("PostWithAuth", mock.Anything, "https://rancher.example.com/v3/tokens", "session",
+				map[string]any{"type": "token", "description": "cowpoke API token for admin", "ttl": 0}).
+				Return(jsonResponse(http.StatusCreated, tt.response), nil)
+
+			client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+			// Act
+			token, err := client.CreateAPIToken(context.Background(), session, server)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, "token-api", token.ID())
+			assert.Equal(t, "token-api:secret", token.Value())
+			assert.Equal(t, tt.wantExpiresAt, token.ExpiresAt())
+		})
+	}
+}
+
+func TestKubeconfigExpiry_LooksUpGeneratedToken(t *testing.T) {
+	// Arrange
+	httpAdapter := mocks.NewMockHTTPAdapter(t)
+	session := mocks.NewMockAuthToken(t)
+	session.On("Value").Return("session")
+	session.On("ExpiresAt").Return(time.Date(2025, 1, 1, 16, 0, 0, 0, time.UTC))
+	server := domain.ConfigServer{URL: "https://rancher.example.com"}
+
+	httpAdapter.On("GetWithAuth", mock.Anything, "https://rancher.example.com/v3/tokens/kubeconfig-u-abc", "session").
+		Return(jsonResponse(http.StatusOK, `{"id":"kubeconfig-u-abc","expiresAt":"2025-01-31T00:00:00Z"}`), nil)
+
+	client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+	// Act
+	expiresAt, err := client.KubeconfigExpiry(context.Background(), session, server, []byte(generatedKubeconfig))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), expiresAt)
+}
+
+func TestKubeconfigExpiry_NeverExpiringToken(t *testing.T) {
+	// Arrange
+	httpAdapter := mocks.NewMockHTTPAdapter(t)
+	session := mocks.NewMockAuthToken(t)
+	session.On("Value").Return("session")
+	session.On("ExpiresAt").Return(time.Date(2025, 1, 1, 16, 0, 0, 0, time.UTC))
+	server := domain.ConfigServer{URL: "https://rancher.example.com"}
+
+	httpAdapter.On("GetWithAuth", mock.Anything, "https://rancher.example.com/v3/tokens/kubeconfig-u-abc", "session").
+		Return(jsonResponse(http.StatusOK, `{"id":"kubeconfig-u-abc","ttl":0,"expiresAt":""}`), nil)
+
+	client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+	// Act
+	expiresAt, err := client.KubeconfigExpiry(context.Background(), session, server, []byte(generatedKubeconfig))
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, expiresAt.IsZero())
+}
+
+func TestKubeconfigExpiry_SessionTokenExpiresWithSession(t *testing.T) {
+	// Arrange
+	httpAdapter := mocks.NewMockHTTPAdapter(t)
+	session := mocks.NewMockAuthToken(t)
+	session.On("Value").Return("kubeconfig-u-abc:secret")
+	session.On("ExpiresAt").Return(time.Date(2025, 1, 1, 16, 0, 0, 0, time.UTC))
+	server := domain.ConfigServer{URL: "https://rancher.example.com"}
+
+	client := NewClient(httpAdapter, domain.SystemClock{}, testutil.Logger())
+
+	// Act
+	expiresAt, err := client.KubeconfigExpiry(context.Background(), session, server, []byte(generatedKubeconfig))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 1, 16, 0, 0, 0, time.UTC), expiresAt)
+}
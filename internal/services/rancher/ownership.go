@@ -0,0 +1,99 @@
+package rancher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"cowpoke/internal/domain"
+)
+
+// clusterOwnerRole is the role template granting full control of a cluster.
+const clusterOwnerRole = "cluster-owner"
+
+// usersResponse represents the Rancher users list response.
+type usersResponse struct {
+	Data []struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+// bindingsResponse represents the Rancher cluster role template bindings list response.
+type bindingsResponse struct {
+	Data []struct {
+		ClusterID string `json:"clusterId"`
+	} `json:"data"`
+}
+
+// ClusterOwnership looks up the user a token belongs to and the clusters they are bound to as cluster
+// owner. Ownership granted through a group is not included.
+func (c *Client) ClusterOwnership(
+	ctx context.Context,
+	token domain.AuthToken,
+	server domain.ConfigServer,
+) (domain.ClusterOwnership, error) {
+	baseURL := normalizeURL(server.URL)
+
+	var users usersResponse
+	if err := c.getWithAuth(ctx, "look up user", baseURL+"/v3/users?me=true", token, &users); err != nil {
+		return domain.ClusterOwnership{}, err
+	}
+	if len(users.Data) == 0 || users.Data[0].ID == "" {
+		return domain.ClusterOwnership{}, fmt.Errorf("look up user failed: no user returned (%w)",
+			domain.ErrMalformedResponse)
+	}
+	ownership := domain.ClusterOwnership{UserID: users.Data[0].ID}
+
+	query := url.Values{
+		"userId":         {ownership.UserID},
+		"roleTemplateId": {clusterOwnerRole},
+		"limit":          {"-1"},
+	}
+	var bindings bindingsResponse
+	bindingsURL := baseURL + "/v3/clusterroletemplatebindings?" + query.Encode()
+	if err := c.getWithAuth(ctx, "list cluster owner bindings", bindingsURL, token, &bindings); err != nil {
+		return domain.ClusterOwnership{}, err
+	}
+	for _, binding := range bindings.Data {
+		if binding.ClusterID != "" {
+			ownership.OwnerOf = append(ownership.OwnerOf, binding.ClusterID)
+		}
+	}
+
+	c.logger.DebugContext(ctx, "Looked up cluster ownership",
+		"server", server.URL,
+		"user", ownership.UserID,
+		"owned", len(ownership.OwnerOf))
+	return ownership, nil
+}
+
+// getWithAuth performs an authenticated GET for operation and decodes a JSON response.
+func (c *Client) getWithAuth(
+	ctx context.Context,
+	operation string,
+	requestURL string,
+	token domain.AuthToken,
+	v any,
+) error {
+	resp, err := c.httpAdapter.GetWithAuth(ctx, requestURL, token.Value())
+	if err != nil {
+		return fmt.Errorf("failed to %s: %w", operation, err)
+	}
+	defer resp.Body.Close()
+
+	if unavailableErr := checkAvailable(resp); unavailableErr != nil {
+		return fmt.Errorf("%s failed: %w", operation, unavailableErr)
+	}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return fmt.Errorf("%s failed: %w", operation, domain.ErrUnauthorized)
+	case resp.StatusCode != http.StatusOK:
+		return newStatusError(operation, resp)
+	}
+	if decodeErr := json.NewDecoder(resp.Body).Decode(v); decodeErr != nil {
+		return fmt.Errorf("failed to decode %s response: %w (%w)", operation, decodeErr, domain.ErrMalformedResponse)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Package state persists cowpoke's runtime state as JSON documents in the state directory.
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"cowpoke/internal/domain"
+)
+
+const (
+	dirPermissions  = 0o700 // Owner-only access
+	filePermissions = 0o600 // Read/write owner only
+)
+
+// Store reads and writes JSON documents in a directory.
+type Store struct {
+	fs     domain.FileSystemAdapter
+	dir    string
+	logger *slog.Logger
+}
+
+// NewStore creates a new state store rooted at dir.
+func NewStore(fs domain.FileSystemAdapter, dir string, logger *slog.Logger) *Store {
+	return &Store{
+		fs:     fs,
+		dir:    dir,
+		logger: logger,
+	}
+}
+
+// Load decodes the named document into v. It reports false if the document does not exist.
+func (s *Store) Load(ctx context.Context, name string, v any) (bool, error) {
+	path := s.path(name)
+	data, err := s.fs.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read state %s: %w", name, err)
+	}
+
+	if unmarshalErr := json.Unmarshal(data, v); unmarshalErr != nil {
+		return false, fmt.Errorf("failed to parse state %s: %w", name, unmarshalErr)
+	}
+
+	s.logger.DebugContext(ctx, "Loaded state", "path", path)
+	return true, nil
+}
+
+// Save encodes v and atomically replaces the named document.
+func (s *Store) Save(ctx context.Context, name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state %s: %w", name, err)
+	}
+
+	if mkdirErr := s.fs.MkdirAll(s.dir, dirPermissions); mkdirErr != nil {
+		return fmt.Errorf("failed to create state directory: %w", mkdirErr)
+	}
+
+	// Write to a temporary file first so readers never observe a partially written document.
+	path := s.path(name)
+	tmpPath := path + ".tmp"
+	if writeErr := s.fs.WriteFile(tmpPath, data, filePermissions); writeErr != nil {
+		return fmt.Errorf("failed to write state %s: %w", name, writeErr)
+	}
+	if renameErr := s.fs.Rename(tmpPath, path); renameErr != nil {
+		return fmt.Errorf("failed to replace state %s: %w", name, renameErr)
+	}
+
+	s.logger.DebugContext(ctx, "Saved state", "path", path)
+	return nil
+}
+
+// path returns the file path for a named document.
+func (s *Store) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
@@ -1,11 +1,17 @@
 package sync
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math"
 	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"cowpoke/internal/domain"
 )
@@ -13,6 +19,10 @@ import (
 const (
 	// maxConcurrentDownloads is the number of concurrent download workers.
 	maxConcurrentDownloads = 5
+	// maxLoggedClusters is how many clusters an aggregated failure log line names.
+	maxLoggedClusters = 3
+	// interruptGrace is how long downloads in flight when a sync is interrupted are given to finish.
+	interruptGrace = 5 * time.Second
 )
 
 // Orchestrator orchestrates concurrent kubeconfig synchronization from multiple Rancher servers.
@@ -20,7 +30,87 @@ type Orchestrator struct {
 	rancherClient     domain.RancherClient
 	kubeconfigHandler domain.KubeconfigHandler
 	configProvider    domain.ConfigProvider
+	fragmentCache     domain.FragmentCache
+	tokenCache        domain.TokenCache
+	tracer            domain.Tracer
 	logger            *slog.Logger
+	// clock stamps downloads and times the grace given to downloads in flight when a sync is interrupted.
+	clock domain.Clock
+	// kubeconfigTTL is the lifetime requested for kubeconfig tokens; zero keeps the server's default.
+	kubeconfigTTL time.Duration
+	// naming is how clusters, and so their fragments and contexts, are named.
+	naming domain.NamingSettings
+	// skipLocal leaves each server's local cluster out of the clusters it discovers.
+	skipLocal bool
+	// viaRancher points every downloaded kubeconfig at Rancher's cluster proxy.
+	viaRancher bool
+	// ownedOnly keeps only the discovered clusters the authenticated user created or owns.
+	ownedOnly bool
+	// hostCloser, if set, closes each server's SSH tunnel and idle connections once it is synced.
+	hostCloser domain.HostCloser
+
+	// mu guards preauthenticated, the tokens of logins made by Preauthenticate, keyed by server ID.
+	mu               sync.Mutex
+	preauthenticated map[string]domain.AuthToken
+}
+
+// OrchestratorOption is a functional option for configuring the Orchestrator.
+type OrchestratorOption func(*Orchestrator)
+
+// WithKubeconfigTTL gives each downloaded kubeconfig a token scoped to its cluster that expires after ttl,
+// instead of the token with the server's default TTL that Rancher generates.
+func WithKubeconfigTTL(ttl time.Duration) OrchestratorOption {
+	return func(o *Orchestrator) {
+		o.kubeconfigTTL = ttl
+	}
+}
+
+// WithNaming sets how clusters, and so their fragments and contexts, are named: whether after their display
+// names, and the policy their names are sanitized under.
+func WithNaming(naming domain.NamingSettings) OrchestratorOption {
+	return func(o *Orchestrator) {
+		o.naming = naming
+	}
+}
+
+// WithSkipLocalCluster leaves the local cluster, which Rancher runs on, out of discovered clusters. Clusters
+// given to SyncClusters are synced as given.
+func WithSkipLocalCluster(skip bool) OrchestratorOption {
+	return func(o *Orchestrator) {
+		o.skipLocal = skip
+	}
+}
+
+// WithViaRancher points every context of the downloaded kubeconfigs at Rancher's /k8s/clusters proxy, for
+// machines that can only reach clusters through Rancher.
+func WithViaRancher(viaRancher bool) OrchestratorOption {
+	return func(o *Orchestrator) {
+		o.viaRancher = viaRancher
+	}
+}
+
+// WithOwnedOnly keeps only the discovered clusters that the authenticated user created or holds the
+// cluster-owner role on. Clusters given to SyncClusters are synced as given.
+func WithOwnedOnly(ownedOnly bool) OrchestratorOption {
+	return func(o *Orchestrator) {
+		o.ownedOnly = ownedOnly
+	}
+}
+
+// WithClock sets the clock downloads are stamped with and interrupted downloads are given their grace
+// period on. Defaults to the system clock.
+func WithClock(clock domain.Clock) OrchestratorOption {
+	return func(o *Orchestrator) {
+		o.clock = clock
+	}
+}
+
+// WithHostCloser closes each server's SSH tunnel and idle connections as soon as its kubeconfigs are
+// downloaded, rather than keeping them open until the process exits.
+func WithHostCloser(hostCloser domain.HostCloser) OrchestratorOption {
+	return func(o *Orchestrator) {
+		o.hostCloser = hostCloser
+	}
 }
 
 // NewOrchestrator creates a new sync orchestrator.
@@ -28,28 +118,45 @@ func NewOrchestrator(
 	rancherClient domain.RancherClient,
 	kubeconfigHandler domain.KubeconfigHandler,
 	configProvider domain.ConfigProvider,
+	fragmentCache domain.FragmentCache,
+	tokenCache domain.TokenCache,
+	tracer domain.Tracer,
 	logger *slog.Logger,
+	opts ...OrchestratorOption,
 ) *Orchestrator {
-	return &Orchestrator{
+	o := &Orchestrator{
 		rancherClient:     rancherClient,
 		kubeconfigHandler: kubeconfigHandler,
 		configProvider:    configProvider,
+		fragmentCache:     fragmentCache,
+		tokenCache:        tokenCache,
+		tracer:            tracer,
 		logger:            logger,
+		clock:             domain.SystemClock{},
+	}
+	for _, opt := range opts {
+		opt(o)
 	}
+	return o
 }
 
 // DiscoveryTask represents a server to authenticate and discover clusters from.
 type DiscoveryTask struct {
 	Server   domain.ConfigServer
 	Password string
+	// Clusters holds known clusters; listing the server's clusters is skipped when set.
+	Clusters []domain.Cluster
 }
 
 // DiscoveryResult contains the result of cluster discovery for a server.
 type DiscoveryResult struct {
-	Server   domain.ConfigServer
-	Token    domain.AuthToken
-	Clusters []domain.Cluster
-	Error    error
+	Server       domain.ConfigServer
+	Token        domain.AuthToken
+	Clusters     []domain.Cluster
+	SkippedLocal bool
+	Version      string
+	Events       []domain.SyncEvent
+	Error        error
 }
 
 // DownloadTask represents a cluster kubeconfig to download.
@@ -64,6 +171,8 @@ type DownloadTask struct {
 type DownloadResult struct {
 	Task     DownloadTask
 	FilePath string
+	Bytes    int
+	Event    domain.SyncEvent
 	Error    error
 }
 
@@ -72,60 +181,184 @@ func (o *Orchestrator) SyncServers(
 	ctx context.Context,
 	servers []domain.ConfigServer,
 	passwords map[string]string,
+	options domain.SyncOptions,
 ) (*domain.SyncResult, error) {
-	if len(servers) == 0 {
+	targets := make([]domain.ServerClusters, 0, len(servers))
+	for _, server := range servers {
+		targets = append(targets, domain.ServerClusters{Server: server})
+	}
+	return o.sync(ctx, targets, passwords, options)
+}
+
+// SyncClusters downloads the kubeconfigs of known clusters concurrently. Servers are authenticated
+// with but their clusters are not listed.
+func (o *Orchestrator) SyncClusters(
+	ctx context.Context,
+	targets []domain.ServerClusters,
+	passwords map[string]string,
+	options domain.SyncOptions,
+) (*domain.SyncResult, error) {
+	for _, target := range targets {
+		if len(target.Clusters) == 0 {
+			return nil, fmt.Errorf("no clusters given for server %s", target.Server.URL)
+		}
+	}
+	return o.sync(ctx, targets, passwords, options)
+}
+
+// sync authenticates with each target's server, lists its clusters unless they are known, and downloads
+// their kubeconfigs. Servers are synced in priority groups, each group finishing before the next starts.
+// Failed downloads are listed in the result's Failures rather than failing the sync.
+func (o *Orchestrator) sync(
+	ctx context.Context,
+	targets []domain.ServerClusters,
+	passwords map[string]string,
+	options domain.SyncOptions,
+) (*domain.SyncResult, error) {
+	if len(targets) == 0 {
 		return &domain.SyncResult{}, nil
 	}
 
 	o.logger.InfoContext(ctx, "Starting sync",
-		"servers", len(servers))
+		"servers", len(targets))
+
+	result := &domain.SyncResult{}
+	var failures []domain.ClusterFailure
+	groups := priorityGroups(targets)
+	for _, group := range groups {
+		if len(groups) > 1 {
+			o.logger.InfoContext(ctx, "Syncing priority group",
+				"priority", group[0].Server.Priority,
+				"servers", len(group))
+		}
+		groupFailures, err := o.syncGroup(ctx, group, passwords, options, result)
+		if err != nil {
+			return nil, err
+		}
+		failures = append(failures, groupFailures...)
+		if result.Interrupted {
+			break
+		}
+	}
+	// Failures of the groups that finished are kept when a later group is interrupted.
+	slices.SortFunc(failures, compareFailures)
+	result.Failures = failures
+
+	if result.TotalClustersFound == 0 && !result.Interrupted {
+		o.logger.WarnContext(ctx, "No clusters discovered from any server")
+		return result, nil
+	}
+	if result.Interrupted {
+		o.logger.WarnContext(ctx, "Sync interrupted",
+			"kubeconfigs", len(result.KubeconfigPaths),
+			"clusters", result.TotalClustersFound)
+		return result, nil
+	}
+
+	o.logger.InfoContext(ctx, "Downloaded kubeconfigs",
+		"kubeconfigs", len(result.KubeconfigPaths),
+		"failed", len(failures),
+		"clusters", result.TotalClustersFound)
+	return result, nil
+}
 
+// syncGroup discovers the clusters of a priority group's servers and downloads their kubeconfigs, adding
+// the outcome to result. It returns the failed downloads; downloads failing because the sync was
+// interrupted are expected, so an interrupted group reports only what it downloaded.
+func (o *Orchestrator) syncGroup(
+	ctx context.Context,
+	targets []domain.ServerClusters,
+	passwords map[string]string,
+	options domain.SyncOptions,
+	result *domain.SyncResult,
+) ([]domain.ClusterFailure, error) {
 	// Phase 1: Concurrent cluster discovery
-	downloadTasks, totalClustersFound, err := o.discoverClustersAsync(ctx, servers, passwords)
+	downloadTasks, serverResults, events, err := o.discoverClustersAsync(ctx, targets, passwords, options)
 	if err != nil {
 		return nil, fmt.Errorf("cluster discovery failed: %w", err)
 	}
-
-	if len(downloadTasks) == 0 {
-		o.logger.WarnContext(ctx, "No clusters discovered from any server")
-		return &domain.SyncResult{
-			TotalClustersFound: totalClustersFound,
-		}, nil
+	result.Servers = append(result.Servers, serverResults...)
+	result.Events = append(result.Events, events...)
+	for _, serverResult := range serverResults {
+		if !slices.ContainsFunc(downloadTasks, func(task DownloadTask) bool {
+			return task.Server.ID() == serverResult.Server.ID()
+		}) {
+			o.closeHost(ctx, serverResult.Server)
+		}
+	}
+	if ctx.Err() != nil {
+		result.Interrupted = true
+		return nil, nil
 	}
 
 	// Phase 2: Concurrent kubeconfig downloads
-	kubeconfigPaths, err := o.downloadKubeconfigsAsync(ctx, downloadTasks)
-	if err != nil {
-		return nil, fmt.Errorf("kubeconfig downloads failed: %w", err)
+	kubeconfigPaths, downloadEvents, err := o.downloadKubeconfigsAsync(ctx, downloadTasks)
+	result.KubeconfigPaths = append(result.KubeconfigPaths, kubeconfigPaths...)
+	result.TotalClustersFound += len(downloadTasks)
+	result.Events = append(result.Events, downloadEvents...)
+	if ctx.Err() != nil {
+		result.Interrupted = true
+		return nil, nil
 	}
+	var downloadErr *domain.DownloadError
+	if errors.As(err, &downloadErr) {
+		return downloadErr.Failures, nil
+	}
+	return nil, err
+}
 
-	o.logger.InfoContext(ctx, "Downloaded kubeconfigs",
-		"kubeconfigs", len(kubeconfigPaths),
-		"clusters", len(downloadTasks))
+// priorityGroups splits targets into groups of servers of equal priority, in ascending order of priority.
+// Servers without a priority form the last group, and targets keep their order within a group.
+func priorityGroups(targets []domain.ServerClusters) [][]domain.ServerClusters {
+	rank := func(target domain.ServerClusters) int {
+		if target.Server.Priority <= 0 {
+			return math.MaxInt
+		}
+		return target.Server.Priority
+	}
+	sorted := slices.Clone(targets)
+	slices.SortStableFunc(sorted, func(a, b domain.ServerClusters) int {
+		return cmp.Compare(rank(a), rank(b))
+	})
 
-	return &domain.SyncResult{
-		KubeconfigPaths:    kubeconfigPaths,
-		TotalClustersFound: totalClustersFound,
-	}, nil
+	var groups [][]domain.ServerClusters
+	for i, target := range sorted {
+		if i == 0 || rank(target) != rank(sorted[i-1]) {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], target)
+	}
+	return groups
 }
 
-// discoverClustersAsync performs concurrent authentication and cluster discovery.
+// discoverClustersAsync performs concurrent authentication and cluster discovery. Clusters whose type is
+// excluded are left out before download tasks are built for the rest.
 func (o *Orchestrator) discoverClustersAsync(
 	ctx context.Context,
-	servers []domain.ConfigServer,
+	targets []domain.ServerClusters,
 	passwords map[string]string,
-) ([]DownloadTask, int, error) {
+	options domain.SyncOptions,
+) ([]DownloadTask, []domain.ServerSyncResult, []domain.SyncEvent, error) {
+	var serverResults []domain.ServerSyncResult
+
 	// Create discovery tasks
-	discoveryTasks := make([]DiscoveryTask, 0, len(servers))
-	for _, server := range servers {
+	discoveryTasks := make([]DiscoveryTask, 0, len(targets))
+	for _, target := range targets {
+		server := target.Server
+		// Servers with a valid cached token are synced without a password
 		password, exists := passwords[server.ID()]
-		if !exists {
+		if !exists && o.cachedToken(ctx, server) == nil {
 			o.logger.WarnContext(ctx, "No password provided for server", "server", server.URL)
+			serverResults = append(serverResults, domain.ServerSyncResult{
+				Server: server,
+				Error:  errors.New("no password provided"),
+			})
 			continue
 		}
 		discoveryTasks = append(discoveryTasks, DiscoveryTask{
 			Server:   server,
 			Password: password,
+			Clusters: target.Clusters,
 		})
 	}
 
@@ -148,13 +381,24 @@ func (o *Orchestrator) discoverClustersAsync(
 	// Get kubeconfig directory for download tasks
 	kubeconfigDir, err := o.configProvider.GetKubeconfigDir()
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get kubeconfig directory: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to get kubeconfig directory: %w", err)
 	}
 
 	// Collect results and build download tasks
 	var downloadTasks []DownloadTask
-	var totalClustersFound int
+	var events []domain.SyncEvent
 	for result := range resultChan {
+		events = append(events, result.Events...)
+		clusters, excluded := o.excludeTypes(ctx, result.Server, result.Clusters, options)
+		serverResults = append(serverResults, domain.ServerSyncResult{
+			Server:         result.Server,
+			Clusters:       clusters,
+			SkippedLocal:   result.SkippedLocal,
+			ExcludedByType: excluded,
+			Version:        result.Version,
+			Error:          result.Error,
+		})
+
 		if result.Error != nil {
 			o.logger.ErrorContext(ctx, "Failed to discover clusters for server",
 				"server", result.Server.URL,
@@ -162,10 +406,8 @@ func (o *Orchestrator) discoverClustersAsync(
 			continue
 		}
 
-		for _, cluster := range result.Clusters {
-			totalClustersFound++
-
-			// All clusters downloaded; filtering happens at merge level
+		for _, cluster := range clusters {
+			// Filtering by name happens at merge level
 			o.logger.DebugContext(ctx, "Discovered cluster",
 				"cluster", fmt.Sprintf("%q", cluster.Name),
 				"server", result.Server.URL,
@@ -180,7 +422,7 @@ func (o *Orchestrator) discoverClustersAsync(
 		}
 	}
 
-	return downloadTasks, totalClustersFound, nil
+	return downloadTasks, serverResults, events, nil
 }
 
 // discoverClustersForServer authenticates with a server and discovers its clusters.
@@ -191,22 +433,62 @@ func (o *Orchestrator) discoverClustersForServer(
 ) {
 	o.logger.DebugContext(ctx, "Discovering clusters for server", "server", task.Server.URL)
 
-	// Authenticate with the server
-	token, err := o.rancherClient.Authenticate(ctx, task.Server, task.Password)
+	// Reject releases we know cannot work before prompting the server for a token
+	version, err := o.checkVersion(ctx, task.Server)
+	if err != nil {
+		resultChan <- DiscoveryResult{
+			Server:  task.Server,
+			Version: version,
+			Error:   err,
+		}
+		return
+	}
+
+	// Authenticate with the server, preferring a cached token
+	token, cached, authEvent, err := o.authenticate(ctx, task)
 	if err != nil {
 		resultChan <- DiscoveryResult{
-			Server: task.Server,
-			Error:  fmt.Errorf("authentication failed: %w", err),
+			Server:  task.Server,
+			Version: version,
+			Events:  []domain.SyncEvent{authEvent},
+			Error:   fmt.Errorf("authentication failed: %w", err),
+		}
+		return
+	}
+
+	if len(task.Clusters) > 0 {
+		o.logger.InfoContext(ctx, "Using listed clusters for server",
+			"server", task.Server.URL,
+			"clusters", len(task.Clusters))
+		resultChan <- DiscoveryResult{
+			Server:   task.Server,
+			Token:    token,
+			Clusters: o.nameClusters(ctx, task.Server, task.Clusters),
+			Version:  version,
+			Events:   []domain.SyncEvent{authEvent},
 		}
 		return
 	}
 
 	// Get list of clusters
-	clusters, err := o.rancherClient.ListClusters(ctx, token, task.Server)
+	clusters, listEvent, err := o.listClusters(ctx, token, task.Server)
+	if errors.Is(err, domain.ErrUnauthorized) && cached {
+		// Rancher may have revoked the token; forget it so the next sync logs in again.
+		if deleteErr := o.tokenCache.Delete(ctx, task.Server); deleteErr != nil {
+			o.logger.WarnContext(ctx, "Failed to discard rejected token", "server", task.Server.URL, "error", deleteErr)
+		}
+		hint := "sync again to log in"
+		if task.Server.AuthType == domain.AuthTypeToken {
+			hint = "run cowpoke convert-to-token to create another"
+		}
+		err = fmt.Errorf("%w (the cached token was discarded; %s)", err, hint)
+	}
 	if err != nil {
 		resultChan <- DiscoveryResult{
-			Server: task.Server,
-			Error:  fmt.Errorf("failed to list clusters: %w", err),
+			Server:  task.Server,
+			Version: version,
+			Events:  []domain.SyncEvent{authEvent, listEvent},
+			Error:   fmt.Errorf("failed to list clusters: %w", err),
 		}
 		return
 	}
@@ -214,22 +496,258 @@ func (o *Orchestrator) discoverClustersForServer(
 	o.logger.InfoContext(ctx, "Discovered clusters for server",
 		"server", task.Server.URL,
 		"clusters", len(clusters))
+	var skippedLocal bool
+	if o.skipLocal {
+		clusters, skippedLocal = withoutLocalCluster(clusters)
+	}
+	if skippedLocal {
+		o.logger.InfoContext(ctx, "Skipped the local cluster", "server", task.Server.URL)
+	}
+	if o.ownedOnly {
+		clusters, err = o.ownedClusters(ctx, token, task.Server, clusters)
+		if err != nil {
+			resultChan <- DiscoveryResult{
+				Server:  task.Server,
+				Version: version,
+				Events:  []domain.SyncEvent{authEvent, listEvent},
+				Error:   fmt.Errorf("failed to determine owned clusters: %w", err),
+			}
+			return
+		}
+	}
+	clusters = o.nameClusters(ctx, task.Server, clusters)
 
 	resultChan <- DiscoveryResult{
-		Server:   task.Server,
-		Token:    token,
-		Clusters: clusters,
-		Error:    nil,
+		Server:       task.Server,
+		Token:        token,
+		Clusters:     clusters,
+		SkippedLocal: skippedLocal,
+		Version:      version,
+		Events:       []domain.SyncEvent{authEvent, listEvent},
+		Error:        nil,
 	}
 }
 
-// downloadKubeconfigsAsync performs concurrent kubeconfig downloads using a worker pool.
+// ownedClusters returns the clusters that the user token belongs to created or owns.
+func (o *Orchestrator) ownedClusters(
+	ctx context.Context,
+	token domain.AuthToken,
+	server domain.ConfigServer,
+	clusters []domain.Cluster,
+) ([]domain.Cluster, error) {
+	ownership, err := o.rancherClient.ClusterOwnership(ctx, token, server)
+	if err != nil {
+		return nil, err
+	}
+	owned := slices.DeleteFunc(slices.Clone(clusters), func(cluster domain.Cluster) bool {
+		return !ownership.Owns(cluster)
+	})
+	o.logger.InfoContext(ctx, "Kept owned clusters",
+		"server", server.URL,
+		"user", ownership.UserID,
+		"owned", len(owned),
+		"skipped", len(clusters)-len(owned))
+	return owned, nil
+}
+
+// excludeTypes splits a server's clusters into those to download and those whose type is excluded.
+func (o *Orchestrator) excludeTypes(
+	ctx context.Context,
+	server domain.ConfigServer,
+	clusters []domain.Cluster,
+	options domain.SyncOptions,
+) ([]domain.Cluster, []domain.Cluster) {
+	var kept, excluded []domain.Cluster
+	for _, cluster := range clusters {
+		if !options.ExcludesType(cluster) {
+			kept = append(kept, cluster)
+			continue
+		}
+		o.logger.DebugContext(ctx, "Excluding cluster by type",
+			"server", server.URL,
+			"cluster", cluster.Name,
+			"type", cluster.Type)
+		excluded = append(excluded, cluster)
+	}
+	return kept, excluded
+}
+
+// withoutLocalCluster returns clusters without the local cluster, reporting whether it was among them.
+func withoutLocalCluster(clusters []domain.Cluster) ([]domain.Cluster, bool) {
+	kept := slices.DeleteFunc(slices.Clone(clusters), func(cluster domain.Cluster) bool {
+		return cluster.ID == domain.LocalClusterID
+	})
+	return kept, len(kept) < len(clusters)
+}
+
+// nameClusters names clusters under the naming settings, after their display names if preferred and they
+// have one. Clusters that would share a name are told apart by their cluster IDs.
+func (o *Orchestrator) nameClusters(
+	ctx context.Context,
+	server domain.ConfigServer,
+	clusters []domain.Cluster,
+) []domain.Cluster {
+	named := make([]domain.Cluster, 0, len(clusters))
+	preferred := make([]string, 0, len(clusters))
+	counts := make(map[string]int, len(clusters))
+	for _, cluster := range clusters {
+		name := cluster.Name
+		if o.naming.PreferDisplayName && cluster.DisplayName != "" {
+			name = cluster.DisplayName
+		}
+		preferred = append(preferred, name)
+		cluster.Name = cmp.Or(o.naming.ClusterName(name), o.naming.ClusterName(cluster.ID))
+		counts[cluster.Name]++
+		named = append(named, cluster)
+	}
+
+	for i, cluster := range named {
+		if counts[cluster.Name] < 2 {
+			continue
+		}
+		named[i].Name = o.naming.DistinctClusterName(preferred[i], cluster.ID)
+		o.logger.WarnContext(ctx, "Clusters share a name, adding the cluster ID to tell them apart",
+			"server", server.URL,
+			"name", cluster.Name,
+			"cluster_id", cluster.ID)
+	}
+	return named
+}
+
+// checkVersion detects a server's Rancher release and rejects releases cowpoke cannot sync from.
+// Detection failures are not fatal since some servers block the version endpoints.
+func (o *Orchestrator) checkVersion(ctx context.Context, server domain.ConfigServer) (string, error) {
+	version, err := o.rancherClient.GetVersion(ctx, server)
+	if err != nil {
+		o.logger.DebugContext(ctx, "Could not detect Rancher version", "server", server.URL, "error", err)
+		return "", nil
+	}
+
+	warning, err := version.Check()
+	if err != nil {
+		return version.Version, err
+	}
+	if warning != "" {
+		o.logger.WarnContext(ctx, warning, "server", server.URL)
+	}
+
+	o.logger.DebugContext(ctx, "Detected Rancher version", "server", server.URL, "version", version.Version)
+	return version.Version, nil
+}
+
+// authenticate logs in to a server within a tracing span and times the attempt. A valid cached
+// token is used instead of logging in when available; it reports whether the token came from the cache.
+func (o *Orchestrator) authenticate(
+	ctx context.Context,
+	task DiscoveryTask,
+) (domain.AuthToken, bool, domain.SyncEvent, error) {
+	ctx, span := o.tracer.Start(ctx, "rancher.authenticate")
+	defer span.End()
+	span.SetAttribute("rancher.server", task.Server.URL)
+	span.SetAttribute("rancher.auth_type", task.Server.AuthType)
+
+	event := domain.SyncEvent{Phase: domain.PhaseAuthenticate, ServerURL: task.Server.URL, Start: time.Now()}
+	o.mu.Lock()
+	token, preauthenticated := o.preauthenticated[task.Server.ID()]
+	o.mu.Unlock()
+	if preauthenticated {
+		event.End = time.Now()
+		return token, false, event, nil
+	}
+	if token := o.cachedToken(ctx, task.Server); token != nil {
+		event.End = time.Now()
+		span.SetAttribute("rancher.token_cached", true)
+		return token, true, event, nil
+	}
+
+	token, err := o.rancherClient.Authenticate(ctx, task.Server, task.Password)
+	event.End = time.Now()
+	event.Error = err
+	span.RecordError(err)
+	if err == nil && o.tokenCache != nil {
+		if putErr := o.tokenCache.Put(ctx, task.Server, token); putErr != nil {
+			o.logger.DebugContext(ctx, "Could not cache token", "server", task.Server.URL, "error", putErr)
+		}
+	}
+	return token, false, event, err
+}
+
+// Preauthenticate logs in to the servers concurrently with their passwords, ahead of a sync, so that a
+// wrong password is found before any discovery starts. The tokens are kept for the syncs that follow, which
+// do not log in again. It returns the errors of the servers that could not log in, keyed by server ID.
+func (o *Orchestrator) Preauthenticate(
+	ctx context.Context,
+	servers []domain.ConfigServer,
+	passwords map[string]string,
+) map[string]error {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	errs := make(map[string]error)
+	for _, server := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, _, _, err := o.authenticate(ctx, DiscoveryTask{Server: server, Password: passwords[server.ID()]})
+			if err != nil {
+				o.logger.DebugContext(ctx, "Preauthentication failed", "server", server.URL, "error", err)
+				mu.Lock()
+				errs[server.ID()] = err
+				mu.Unlock()
+				return
+			}
+			o.mu.Lock()
+			if o.preauthenticated == nil {
+				o.preauthenticated = make(map[string]domain.AuthToken)
+			}
+			o.preauthenticated[server.ID()] = token
+			o.mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
+// cachedToken returns a valid cached token for server, or nil if caching is disabled or none is cached.
+func (o *Orchestrator) cachedToken(ctx context.Context, server domain.ConfigServer) domain.AuthToken {
+	if o.tokenCache == nil {
+		return nil
+	}
+	token, err := o.tokenCache.Get(ctx, server)
+	if err != nil {
+		o.logger.DebugContext(ctx, "Could not read cached token", "server", server.URL, "error", err)
+		return nil
+	}
+	return token
+}
+
+// listClusters lists a server's clusters within a tracing span.
+func (o *Orchestrator) listClusters(
+	ctx context.Context,
+	token domain.AuthToken,
+	server domain.ConfigServer,
+) ([]domain.Cluster, domain.SyncEvent, error) {
+	ctx, span := o.tracer.Start(ctx, "rancher.list_clusters")
+	defer span.End()
+	span.SetAttribute("rancher.server", server.URL)
+
+	event := domain.SyncEvent{Phase: domain.PhaseListClusters, ServerURL: server.URL, Start: time.Now()}
+	clusters, err := o.rancherClient.ListClusters(ctx, token, server)
+	event.End = time.Now()
+	event.Error = err
+
+	span.RecordError(err)
+	span.SetAttribute("rancher.clusters", len(clusters))
+	return clusters, event, err
+}
+
+// downloadKubeconfigsAsync performs concurrent kubeconfig downloads using a worker pool. Once ctx is
+// canceled, no further downloads are started and those in flight have interruptGrace to finish.
 func (o *Orchestrator) downloadKubeconfigsAsync(
 	ctx context.Context,
 	downloadTasks []DownloadTask,
-) ([]string, error) {
+) ([]string, []domain.SyncEvent, error) {
 	if len(downloadTasks) == 0 {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	o.logger.InfoContext(ctx, "Starting concurrent downloads",
@@ -241,12 +759,17 @@ func (o *Orchestrator) downloadKubeconfigsAsync(
 	resultChan := make(chan DownloadResult, len(downloadTasks))
 
 	// Start worker pool
+	downloadCtx, cancel := withGrace(ctx, o.clock, interruptGrace)
+	defer cancel()
+	remaining := newServerCountdown(downloadTasks, func(server domain.ConfigServer) {
+		o.closeHost(ctx, server)
+	})
 	var wg sync.WaitGroup
 	for i := range maxConcurrentDownloads {
 		wg.Add(1)
 		go func(workerID int) {
 			defer wg.Done()
-			o.downloadWorker(ctx, workerID, taskChan, resultChan)
+			o.downloadWorker(ctx, downloadCtx, workerID, taskChan, resultChan, remaining)
 		}(i)
 	}
 
@@ -262,54 +785,254 @@ func (o *Orchestrator) downloadKubeconfigsAsync(
 
 	// Collect results
 	var kubeconfigPaths []string
-	var errorCount int
+	var cacheEntries []domain.CacheEntry
+	var events []domain.SyncEvent
+	var failures []domain.ClusterFailure
 	for result := range resultChan {
+		events = append(events, result.Event)
 		if result.Error != nil {
-			o.logger.ErrorContext(ctx, "Failed to download kubeconfig",
+			o.logger.DebugContext(ctx, "Failed to download kubeconfig",
 				"server", result.Task.Server.URL,
 				"cluster", result.Task.Cluster.Name,
 				"error", result.Error)
-			errorCount++
+			failure := domain.ClusterFailure{
+				ServerURL: result.Task.Server.URL,
+				Cluster:   result.Task.Cluster.Name,
+				Error:     result.Error.Error(),
+				Category:  domain.ClassifyFailure(result.Error),
+			}
+			var quarantineErr *domain.QuarantineError
+			if errors.As(result.Error, &quarantineErr) {
+				failure.Quarantine = quarantineErr.Path
+			}
+			failures = append(failures, failure)
 			continue
 		}
 		kubeconfigPaths = append(kubeconfigPaths, result.FilePath)
+		cacheEntries = append(cacheEntries, domain.CacheEntry{
+			File:         filepath.Base(result.FilePath),
+			ServerURL:    result.Task.Server.URL,
+			ServerID:     result.Task.Server.ID(),
+			ClusterID:    result.Task.Cluster.ID,
+			ClusterName:  result.Task.Cluster.Name,
+			ClusterType:  result.Task.Cluster.Type,
+			DownloadedAt: o.clock.Now(),
+		})
+	}
+
+	if recordErr := o.fragmentCache.Record(ctx, cacheEntries); recordErr != nil {
+		o.logger.WarnContext(ctx, "Failed to update cache index", "error", recordErr)
 	}
 
 	o.logger.InfoContext(ctx, "Concurrent downloads completed",
 		"successful", len(kubeconfigPaths),
-		"failed", errorCount,
+		"failed", len(failures),
 		"total", len(downloadTasks))
 
-	if errorCount > 0 {
-		return kubeconfigPaths, fmt.Errorf(
-			"failed to download %d out of %d kubeconfigs",
-			errorCount,
-			len(downloadTasks),
-		)
+	if len(failures) > 0 {
+		slices.SortFunc(failures, compareFailures)
+		o.logFailures(ctx, failures)
+		return kubeconfigPaths, events, &domain.DownloadError{Failures: failures, Total: len(downloadTasks)}
+	}
+	return kubeconfigPaths, events, nil
+}
+
+// compareFailures orders download failures by server and cluster.
+func compareFailures(a, b domain.ClusterFailure) int {
+	return cmp.Or(cmp.Compare(a.ServerURL, b.ServerURL), cmp.Compare(a.Cluster, b.Cluster))
+}
+
+// logFailures logs download failures with one line per server and distinct error, so that many clusters
+// failing the same way do not flood the log. Each failure is logged individually at debug level.
+func (o *Orchestrator) logFailures(ctx context.Context, failures []domain.ClusterFailure) {
+	type failureGroup struct {
+		serverURL string
+		err       string
+		category  domain.FailureCategory
+		clusters  []string
+	}
+	var groups []*failureGroup
+	for _, failure := range failures {
+		index := slices.IndexFunc(groups, func(group *failureGroup) bool {
+			return group.serverURL == failure.ServerURL && group.err == failure.Error
+		})
+		if index < 0 {
+			groups = append(groups, &failureGroup{
+				serverURL: failure.ServerURL,
+				err:       failure.Error,
+				category:  failure.Category,
+			})
+			index = len(groups) - 1
+		}
+		groups[index].clusters = append(groups[index].clusters, failure.Cluster)
+	}
+
+	for _, group := range groups {
+		if len(group.clusters) == 1 {
+			o.logger.ErrorContext(ctx, "Failed to download kubeconfig",
+				"server", group.serverURL,
+				"cluster", group.clusters[0],
+				"category", group.category,
+				"error", group.err)
+			continue
+		}
+		o.logger.ErrorContext(ctx, "Failed to download kubeconfigs",
+			"server", group.serverURL,
+			"count", len(group.clusters),
+			"clusters", sampleClusters(group.clusters),
+			"category", group.category,
+			"error", group.err)
+	}
+}
+
+// sampleClusters names the first few clusters, counting the rest.
+func sampleClusters(clusters []string) string {
+	if len(clusters) <= maxLoggedClusters {
+		return strings.Join(clusters, ", ")
 	}
-	return kubeconfigPaths, nil
+	return fmt.Sprintf("%s and %d more",
+		strings.Join(clusters[:maxLoggedClusters], ", "), len(clusters)-maxLoggedClusters)
 }
 
-// downloadWorker processes download tasks from the task channel.
+// withGrace returns a context that is canceled grace after ctx is, by clock, so that work in flight when ctx
+// is canceled can finish.
+func withGrace(ctx context.Context, clock domain.Clock, grace time.Duration) (context.Context, context.CancelFunc) {
+	graceCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		select {
+		case <-clock.After(grace):
+			cancel()
+		case <-graceCtx.Done():
+		}
+	})
+	return graceCtx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// downloadWorker processes download tasks from the task channel, downloading within downloadCtx, and counts
+// each one down in remaining. Tasks received after ctx is canceled are skipped without a result.
 func (o *Orchestrator) downloadWorker(
 	ctx context.Context,
+	downloadCtx context.Context,
 	workerID int,
 	taskChan <-chan DownloadTask,
 	resultChan chan<- DownloadResult,
+	remaining *serverCountdown,
 ) {
 	for task := range taskChan {
+		if ctx.Err() != nil {
+			remaining.done(task.Server)
+			continue
+		}
 		o.logger.DebugContext(ctx, "Worker processing download",
 			"worker", workerID,
 			"server", task.Server.URL,
 			"cluster", task.Cluster.Name)
 
-		result := o.downloadKubeconfig(ctx, task)
+		result := o.downloadKubeconfig(downloadCtx, task)
+		remaining.done(task.Server)
 		resultChan <- result
 	}
 }
 
+// serverCountdown counts down the download tasks of each server, calling finished with a server once its
+// last task is done.
+type serverCountdown struct {
+	mu        sync.Mutex
+	remaining map[string]int
+	finished  func(domain.ConfigServer)
+}
+
+func newServerCountdown(tasks []DownloadTask, finished func(domain.ConfigServer)) *serverCountdown {
+	remaining := make(map[string]int)
+	for _, task := range tasks {
+		remaining[task.Server.ID()]++
+	}
+	return &serverCountdown{remaining: remaining, finished: finished}
+}
+
+// done counts down one task of server.
+func (c *serverCountdown) done(server domain.ConfigServer) {
+	c.mu.Lock()
+	c.remaining[server.ID()]--
+	last := c.remaining[server.ID()] == 0
+	c.mu.Unlock()
+	if last {
+		c.finished(server)
+	}
+}
+
+// closeHost closes the SSH tunnel and idle connections of a server that has been synced.
+func (o *Orchestrator) closeHost(ctx context.Context, server domain.ConfigServer) {
+	if o.hostCloser == nil {
+		return
+	}
+	if err := o.hostCloser.CloseHost(server.URL); err != nil {
+		o.logger.WarnContext(ctx, "Failed to close connections to server", "server", server.URL, "error", err)
+	}
+}
+
 // downloadKubeconfig downloads and saves a kubeconfig for a specific cluster.
 func (o *Orchestrator) downloadKubeconfig(ctx context.Context, task DownloadTask) DownloadResult {
+	ctx, span := o.tracer.Start(ctx, "kubeconfig.download")
+	defer span.End()
+	span.SetAttribute("rancher.server", task.Server.URL)
+	span.SetAttribute("rancher.cluster", task.Cluster.Name)
+	span.SetAttribute("rancher.cluster_id", task.Cluster.ID)
+
+	start := time.Now()
+	result := o.fetchKubeconfig(ctx, task)
+	result.Event = domain.SyncEvent{
+		Phase:     domain.PhaseDownload,
+		ServerURL: task.Server.URL,
+		Cluster:   task.Cluster.Name,
+		Start:     start,
+		End:       time.Now(),
+		Bytes:     result.Bytes,
+		Error:     result.Error,
+	}
+
+	span.RecordError(result.Error)
+	return result
+}
+
+// scopeKubeconfig swaps the kubeconfig's token for one expiring after the requested TTL. Servers that
+// refuse to create the token keep the kubeconfig Rancher generated, with no recorded expiry.
+func (o *Orchestrator) scopeKubeconfig(
+	ctx context.Context,
+	task DownloadTask,
+	kubeconfig []byte,
+) ([]byte, time.Time) {
+	scoped, expiresAt, err := o.rancherClient.ScopeKubeconfig(ctx, task.Token, task.Server, task.Cluster.ID,
+		kubeconfig, o.kubeconfigTTL)
+	if err != nil {
+		o.logger.WarnContext(ctx, "Could not apply kubeconfig TTL, keeping the server's default",
+			"server", task.Server.URL,
+			"cluster", task.Cluster.Name,
+			"error", err)
+		return kubeconfig, time.Time{}
+	}
+	return scoped, expiresAt.UTC()
+}
+
+// kubeconfigExpiry looks up when the kubeconfig's token expires, so that expiring contexts can be warned
+// about. An unknown expiry is not an error.
+func (o *Orchestrator) kubeconfigExpiry(ctx context.Context, task DownloadTask, kubeconfig []byte) time.Time {
+	expiresAt, err := o.rancherClient.KubeconfigExpiry(ctx, task.Token, task.Server, kubeconfig)
+	if err != nil {
+		o.logger.DebugContext(ctx, "Could not look up kubeconfig token expiry",
+			"server", task.Server.URL,
+			"cluster", task.Cluster.Name,
+			"error", err)
+		return time.Time{}
+	}
+	return expiresAt.UTC()
+}
+
+// fetchKubeconfig fetches a cluster's kubeconfig and saves it as a fragment.
+func (o *Orchestrator) fetchKubeconfig(ctx context.Context, task DownloadTask) DownloadResult {
 	// Get kubeconfig for this cluster
 	kubeconfig, err := o.rancherClient.GetKubeconfig(ctx, task.Token, task.Server, task.Cluster.ID)
 	if err != nil {
@@ -319,11 +1042,33 @@ func (o *Orchestrator) downloadKubeconfig(ctx context.Context, task DownloadTask
 		}
 	}
 
+	if o.viaRancher {
+		if kubeconfig, err = o.rancherClient.ProxyKubeconfig(ctx, task.Server, task.Cluster.ID, kubeconfig); err != nil {
+			return DownloadResult{
+				Task:  task,
+				Error: fmt.Errorf("failed to route kubeconfig through Rancher: %w", err),
+			}
+		}
+	}
+
 	// Save to temporary file
-	filename := fmt.Sprintf("%s-%s.yaml", task.Cluster.Name, task.Server.ID())
+	filename := domain.FragmentFileName(task.Cluster.Name, task.Server.ID())
 	path := filepath.Join(task.OutputDir, filename)
 
-	if saveErr := o.kubeconfigHandler.SaveKubeconfig(ctx, path, kubeconfig, task.Server.ID()); saveErr != nil {
+	owner := domain.ContextOwner{
+		ServerURL:   task.Server.URL,
+		ServerID:    task.Server.ID(),
+		ClusterID:   task.Cluster.ID,
+		ClusterName: task.Cluster.Name,
+		SyncedAt:    o.clock.Now().UTC(),
+	}
+	if o.kubeconfigTTL > 0 {
+		kubeconfig, owner.ExpiresAt = o.scopeKubeconfig(ctx, task, kubeconfig)
+	}
+	if owner.ExpiresAt.IsZero() {
+		owner.ExpiresAt = o.kubeconfigExpiry(ctx, task, kubeconfig)
+	}
+	if saveErr := o.kubeconfigHandler.SaveKubeconfig(ctx, path, kubeconfig, owner); saveErr != nil {
 		return DownloadResult{
 			Task:  task,
 			Error: fmt.Errorf("failed to save kubeconfig: %w", saveErr),
@@ -338,6 +1083,7 @@ func (o *Orchestrator) downloadKubeconfig(ctx context.Context, task DownloadTask
 	return DownloadResult{
 		Task:     task,
 		FilePath: path,
+		Bytes:    len(kubeconfig),
 		Error:    nil,
 	}
 }
@@ -0,0 +1,118 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/testutil"
+)
+
+const (
+	// benchServers and benchClusters are the fleet synced by benchmarks: servers and clusters per server.
+	benchServers  = 50
+	benchClusters = 100
+)
+
+// benchRancher serves every server the same clusters and kubeconfig without network calls, so benchmarks
+// measure the orchestration itself.
+type benchRancher struct {
+	domain.RancherClient
+	clusters   []domain.Cluster
+	kubeconfig []byte
+}
+
+func (r *benchRancher) GetVersion(context.Context, domain.ConfigServer) (domain.ServerVersion, error) {
+	return domain.ServerVersion{Version: "v2.9.0"}, nil
+}
+
+func (r *benchRancher) Authenticate(context.Context, domain.ConfigServer, string) (domain.AuthToken, error) {
+	return benchToken{}, nil
+}
+
+func (r *benchRancher) ListClusters(context.Context, domain.AuthToken, domain.ConfigServer) ([]domain.Cluster, error) {
+	return r.clusters, nil
+}
+
+func (r *benchRancher) GetKubeconfig(context.Context, domain.AuthToken, domain.ConfigServer, string) ([]byte, error) {
+	return r.kubeconfig, nil
+}
+
+func (r *benchRancher) KubeconfigExpiry(
+	context.Context,
+	domain.AuthToken,
+	domain.ConfigServer,
+	[]byte,
+) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+// benchToken is a token that never expires.
+type benchToken struct{}
+
+func (benchToken) ID() string           { return "token-bench" }
+func (benchToken) Value() string        { return "token-bench:secret" }
+func (benchToken) IsValid() bool        { return true }
+func (benchToken) ExpiresAt() time.Time { return time.Time{} }
+
+// benchHandler discards kubeconfigs instead of preprocessing and saving them, which the kubeconfig
+// package benchmarks on its own.
+type benchHandler struct {
+	domain.KubeconfigHandler
+}
+
+func (benchHandler) SaveKubeconfig(context.Context, string, []byte, domain.ContextOwner) error {
+	return nil
+}
+
+// benchProvider places fragments in a fixed directory.
+type benchProvider struct {
+	domain.ConfigProvider
+	dir string
+}
+
+func (p benchProvider) GetKubeconfigDir() (string, error) {
+	return p.dir, nil
+}
+
+// benchCache discards cache index entries.
+type benchCache struct {
+	domain.FragmentCache
+}
+
+func (benchCache) Record(context.Context, []domain.CacheEntry) error {
+	return nil
+}
+
+func BenchmarkOrchestrator_SyncServers(b *testing.B) {
+	clusters := make([]domain.Cluster, benchClusters)
+	for i := range clusters {
+		clusters[i] = domain.Cluster{ID: fmt.Sprintf("c-m-%05d", i), Name: fmt.Sprintf("cluster-%03d", i)}
+	}
+	servers := make([]domain.ConfigServer, benchServers)
+	passwords := make(map[string]string, benchServers)
+	for i := range servers {
+		servers[i] = domain.ConfigServer{
+			URL:      fmt.Sprintf("https://rancher-%02d.example.com", i),
+			Username: "admin",
+			AuthType: "local",
+		}
+		passwords[servers[i].ID()] = "password"
+	}
+	rancher := &benchRancher{clusters: clusters, kubeconfig: testutil.RancherKubeconfig("cluster", 3)}
+	orchestrator := NewOrchestrator(rancher, benchHandler{}, benchProvider{dir: b.TempDir()}, benchCache{}, nil,
+		domain.NoopTracer{}, testutil.Logger())
+	ctx := context.Background()
+
+	for b.Loop() {
+		result, err := orchestrator.SyncServers(ctx, servers, passwords, domain.SyncOptions{})
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(result.KubeconfigPaths) != benchServers*benchClusters {
+			b.Fatalf("downloaded %d kubeconfigs, want %d", len(result.KubeconfigPaths), benchServers*benchClusters)
+		}
+	}
+}
@@ -0,0 +1,544 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrchestrator_SyncServers_SkipsLocalCluster(t *testing.T) {
+	tests := []struct {
+		name         string
+		skipLocal    bool
+		wantClusters []string
+	}{
+		{name: "skipped", skipLocal: true, wantClusters: []string{"prod"}},
+		{name: "included", skipLocal: false, wantClusters: []string{"local", "prod"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			rancher := &benchRancher{
+				clusters: []domain.Cluster{
+					{ID: domain.LocalClusterID, Name: "local"},
+					{ID: "c-m-prod", Name: "prod"},
+				},
+				kubeconfig: testutil.RancherKubeconfig("cluster", 1),
+			}
+			server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+			orchestrator := NewOrchestrator(rancher, benchHandler{}, benchProvider{dir: t.TempDir()}, benchCache{},
+				nil, domain.NoopTracer{}, testutil.Logger(), WithSkipLocalCluster(tt.skipLocal))
+
+			// Act
+			result, err := orchestrator.SyncServers(context.Background(), []domain.ConfigServer{server},
+				map[string]string{server.ID(): "password"}, domain.SyncOptions{})
+
+			// Assert
+			require.NoError(t, err)
+			require.Len(t, result.Servers, 1)
+			var names []string
+			for _, cluster := range result.Servers[0].Clusters {
+				names = append(names, cluster.Name)
+			}
+			assert.Equal(t, tt.wantClusters, names)
+			assert.Equal(t, tt.skipLocal, result.Servers[0].SkippedLocal)
+			assert.Len(t, result.KubeconfigPaths, len(tt.wantClusters))
+		})
+	}
+}
+
+// downloadRecordingRancher records the clusters whose kubeconfigs are downloaded.
+type downloadRecordingRancher struct {
+	*benchRancher
+	mu         sync.Mutex
+	downloaded []string
+}
+
+func (r *downloadRecordingRancher) GetKubeconfig(
+	ctx context.Context,
+	token domain.AuthToken,
+	server domain.ConfigServer,
+	clusterID string,
+) ([]byte, error) {
+	r.mu.Lock()
+	r.downloaded = append(r.downloaded, clusterID)
+	r.mu.Unlock()
+	return r.benchRancher.GetKubeconfig(ctx, token, server, clusterID)
+}
+
+func TestOrchestrator_SyncServers_ExcludesTypesBeforeDownload(t *testing.T) {
+	// Arrange
+	rancher := &downloadRecordingRancher{benchRancher: &benchRancher{
+		clusters: []domain.Cluster{
+			{ID: "c-m-prod", Name: "prod", Type: "rke2"},
+			{ID: "c-m-hci", Name: "hci", Type: "harvester"},
+		},
+		kubeconfig: testutil.RancherKubeconfig("cluster", 1),
+	}}
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	orchestrator := NewOrchestrator(rancher, benchHandler{}, benchProvider{dir: t.TempDir()}, benchCache{},
+		nil, domain.NoopTracer{}, testutil.Logger())
+
+	// Act
+	result, err := orchestrator.SyncServers(context.Background(), []domain.ConfigServer{server},
+		map[string]string{server.ID(): "password"}, domain.SyncOptions{ExcludeTypes: []string{"Harvester"}})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c-m-prod"}, rancher.downloaded)
+	assert.Equal(t, 1, result.TotalClustersFound)
+	require.Len(t, result.Servers, 1)
+	assert.Equal(t, []domain.Cluster{{ID: "c-m-prod", Name: "prod", Type: "rke2"}}, result.Servers[0].Clusters)
+	assert.Equal(t, []domain.Cluster{{ID: "c-m-hci", Name: "hci", Type: "harvester"}},
+		result.Servers[0].ExcludedByType)
+}
+
+func TestOrchestrator_SyncServers_ClosesHostOfSyncedServers(t *testing.T) {
+	// Arrange
+	rancher := &benchRancher{
+		clusters:   []domain.Cluster{{ID: "c-m-prod", Name: "prod"}, {ID: "c-m-dev", Name: "dev"}},
+		kubeconfig: testutil.RancherKubeconfig("cluster", 1),
+	}
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	hostCloser := &recordingHostCloser{}
+	orchestrator := NewOrchestrator(rancher, benchHandler{}, benchProvider{dir: t.TempDir()}, benchCache{},
+		nil, domain.NoopTracer{}, testutil.Logger(), WithHostCloser(hostCloser))
+
+	// Act
+	result, err := orchestrator.SyncServers(context.Background(), []domain.ConfigServer{server},
+		map[string]string{server.ID(): "password"}, domain.SyncOptions{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, result.KubeconfigPaths, 2)
+	assert.Equal(t, []string{server.URL}, hostCloser.closed)
+}
+
+// recordingHostCloser records the servers whose hosts are closed.
+type recordingHostCloser struct {
+	mu     sync.Mutex
+	closed []string
+}
+
+func (c *recordingHostCloser) CloseHost(serverURL string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = append(c.closed, serverURL)
+	return nil
+}
+
+// refusingRancher fails to generate the kubeconfig of one cluster.
+type refusingRancher struct {
+	*benchRancher
+	clusterID string
+}
+
+func (r *refusingRancher) GetKubeconfig(
+	ctx context.Context,
+	token domain.AuthToken,
+	server domain.ConfigServer,
+	clusterID string,
+) ([]byte, error) {
+	if clusterID == r.clusterID {
+		return nil, errors.New("failed to get kubeconfig: status 403")
+	}
+	return r.benchRancher.GetKubeconfig(ctx, token, server, clusterID)
+}
+
+func TestOrchestrator_SyncServers_ReportsFailedDownloads(t *testing.T) {
+	// Arrange
+	rancher := &refusingRancher{benchRancher: &benchRancher{
+		clusters:   []domain.Cluster{{ID: "c-m-prod", Name: "prod"}, {ID: "c-m-dev", Name: "dev"}},
+		kubeconfig: testutil.RancherKubeconfig("cluster", 1),
+	}, clusterID: "c-m-dev"}
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	orchestrator := NewOrchestrator(rancher, benchHandler{}, benchProvider{dir: t.TempDir()}, benchCache{},
+		nil, domain.NoopTracer{}, testutil.Logger())
+
+	// Act
+	result, err := orchestrator.SyncServers(context.Background(), []domain.ConfigServer{server},
+		map[string]string{server.ID(): "password"}, domain.SyncOptions{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, result.KubeconfigPaths, 1)
+	assert.Equal(t, 2, result.TotalClustersFound)
+	require.Len(t, result.Failures, 1)
+	assert.Equal(t, "dev", result.Failures[0].Cluster)
+	assert.Equal(t, server.URL, result.Failures[0].ServerURL)
+	var downloadErr *domain.DownloadError
+	require.ErrorAs(t, result.DownloadErr(), &downloadErr)
+	assert.Equal(t, 2, downloadErr.Total)
+}
+
+// interruptingRancher cancels the sync when the first kubeconfig download starts, and fails downloads whose
+// context is canceled.
+type interruptingRancher struct {
+	*benchRancher
+	interrupt context.CancelFunc
+	once      sync.Once
+}
+
+func (r *interruptingRancher) GetKubeconfig(
+	ctx context.Context,
+	token domain.AuthToken,
+	server domain.ConfigServer,
+	clusterID string,
+) ([]byte, error) {
+	r.once.Do(r.interrupt)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.benchRancher.GetKubeconfig(ctx, token, server, clusterID)
+}
+
+func TestOrchestrator_SyncServers_Interrupted(t *testing.T) {
+	// Arrange
+	clusters := make([]domain.Cluster, 50)
+	for i := range clusters {
+		clusters[i] = domain.Cluster{ID: fmt.Sprintf("c-m-%05d", i), Name: fmt.Sprintf("cluster-%03d", i)}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rancher := &interruptingRancher{
+		benchRancher: &benchRancher{clusters: clusters, kubeconfig: testutil.RancherKubeconfig("cluster", 1)},
+		interrupt:    cancel,
+	}
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	orchestrator := NewOrchestrator(rancher, benchHandler{}, benchProvider{dir: t.TempDir()}, benchCache{},
+		nil, domain.NoopTracer{}, testutil.Logger())
+
+	// Act
+	result, err := orchestrator.SyncServers(ctx, []domain.ConfigServer{server},
+		map[string]string{server.ID(): "password"}, domain.SyncOptions{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, result.Interrupted)
+	assert.Equal(t, len(clusters), result.TotalClustersFound)
+	assert.NotEmpty(t, result.KubeconfigPaths, "downloads in flight when interrupted are finished")
+	assert.LessOrEqual(t, len(result.KubeconfigPaths), maxConcurrentDownloads,
+		"no download is started once interrupted")
+}
+
+// ownerRecordingHandler records the owners kubeconfigs are saved with.
+type ownerRecordingHandler struct {
+	benchHandler
+	mu     sync.Mutex
+	owners []domain.ContextOwner
+}
+
+func (h *ownerRecordingHandler) SaveKubeconfig(_ context.Context, _ string, _ []byte, owner domain.ContextOwner) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.owners = append(h.owners, owner)
+	return nil
+}
+
+// entryRecordingCache records the cache index entries of downloaded fragments.
+type entryRecordingCache struct {
+	benchCache
+	entries []domain.CacheEntry
+}
+
+func (c *entryRecordingCache) Record(_ context.Context, entries []domain.CacheEntry) error {
+	c.entries = append(c.entries, entries...)
+	return nil
+}
+
+func TestOrchestrator_SyncServers_StampsDownloadsWithClock(t *testing.T) {
+	// Arrange
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	rancher := &benchRancher{
+		clusters:   []domain.Cluster{{ID: "c-m-prod", Name: "prod"}},
+		kubeconfig: testutil.RancherKubeconfig("cluster", 1),
+	}
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	handler := &ownerRecordingHandler{}
+	cache := &entryRecordingCache{}
+	orchestrator := NewOrchestrator(rancher, handler, benchProvider{dir: t.TempDir()}, cache,
+		nil, domain.NoopTracer{}, testutil.Logger(), WithClock(testutil.NewClock(now)))
+
+	// Act
+	_, err := orchestrator.SyncServers(context.Background(), []domain.ConfigServer{server},
+		map[string]string{server.ID(): "password"}, domain.SyncOptions{})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, handler.owners, 1)
+	assert.Equal(t, now.UTC(), handler.owners[0].SyncedAt)
+	require.Len(t, cache.entries, 1)
+	assert.True(t, now.Equal(cache.entries[0].DownloadedAt))
+}
+
+func TestWithGrace_CancelsGraceAfterInterruptOnClock(t *testing.T) {
+	// Arrange
+	clock := testutil.NewClock(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	ctx, interrupt := context.WithCancel(context.Background())
+	graceCtx, cancel := withGrace(ctx, clock, interruptGrace)
+	defer cancel()
+
+	// Act
+	interrupt()
+	require.Eventually(t, func() bool { return clock.Waiters() == 1 }, 5*time.Second, time.Millisecond)
+	clock.Advance(interruptGrace - time.Second)
+
+	// Assert
+	require.NoError(t, graceCtx.Err(), "work in flight keeps running within the grace period")
+	clock.Advance(time.Second)
+	select {
+	case <-graceCtx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("grace context not canceled once the grace period passed")
+	}
+}
+
+// lateInterruptingRancher refuses the downloads of one cluster, and cancels the sync when a download from
+// the server at interruptURL starts.
+type lateInterruptingRancher struct {
+	*refusingRancher
+	interruptURL string
+	interrupt    context.CancelFunc
+	once         sync.Once
+}
+
+func (r *lateInterruptingRancher) GetKubeconfig(
+	ctx context.Context,
+	token domain.AuthToken,
+	server domain.ConfigServer,
+	clusterID string,
+) ([]byte, error) {
+	if server.URL == r.interruptURL {
+		r.once.Do(r.interrupt)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return r.refusingRancher.GetKubeconfig(ctx, token, server, clusterID)
+}
+
+func TestOrchestrator_SyncServers_InterruptedKeepsEarlierFailures(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	first := domain.ConfigServer{URL: "https://first.example.com", Username: "admin", AuthType: "local", Priority: 1}
+	second := domain.ConfigServer{URL: "https://second.example.com", Username: "admin", AuthType: "local", Priority: 2}
+	rancher := &lateInterruptingRancher{
+		refusingRancher: &refusingRancher{benchRancher: &benchRancher{
+			clusters:   []domain.Cluster{{ID: "c-m-prod", Name: "prod"}, {ID: "c-m-dev", Name: "dev"}},
+			kubeconfig: testutil.RancherKubeconfig("cluster", 1),
+		}, clusterID: "c-m-dev"},
+		interruptURL: second.URL,
+		interrupt:    cancel,
+	}
+	orchestrator := NewOrchestrator(rancher, benchHandler{}, benchProvider{dir: t.TempDir()}, benchCache{},
+		nil, domain.NoopTracer{}, testutil.Logger())
+
+	// Act
+	result, err := orchestrator.SyncServers(ctx, []domain.ConfigServer{first, second},
+		map[string]string{first.ID(): "password", second.ID(): "password"}, domain.SyncOptions{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, result.Interrupted)
+	require.Len(t, result.Failures, 1, "failures of the group that finished are kept")
+	assert.Equal(t, first.URL, result.Failures[0].ServerURL)
+	assert.Equal(t, "dev", result.Failures[0].Cluster)
+}
+
+// recordingRancher records the servers whose clusters are listed, in order.
+type recordingRancher struct {
+	*benchRancher
+	mu     sync.Mutex
+	listed []string
+}
+
+func (r *recordingRancher) ListClusters(
+	ctx context.Context,
+	token domain.AuthToken,
+	server domain.ConfigServer,
+) ([]domain.Cluster, error) {
+	r.mu.Lock()
+	r.listed = append(r.listed, server.URL)
+	r.mu.Unlock()
+	return r.benchRancher.ListClusters(ctx, token, server)
+}
+
+func TestOrchestrator_SyncServers_PriorityGroups(t *testing.T) {
+	// Arrange
+	rancher := &recordingRancher{benchRancher: &benchRancher{
+		clusters:   []domain.Cluster{{ID: "c-m-1", Name: "cluster"}},
+		kubeconfig: testutil.RancherKubeconfig("cluster", 1),
+	}}
+	misc := domain.ConfigServer{URL: "https://misc.example.com", Username: "admin", AuthType: "local"}
+	lab := domain.ConfigServer{URL: "https://lab.example.com", Username: "admin", AuthType: "local", Priority: 2}
+	prod := domain.ConfigServer{URL: "https://prod.example.com", Username: "admin", AuthType: "local", Priority: 1}
+	servers := []domain.ConfigServer{misc, lab, prod}
+	passwords := map[string]string{misc.ID(): "password", lab.ID(): "password", prod.ID(): "password"}
+	dir := t.TempDir()
+	orchestrator := NewOrchestrator(rancher, benchHandler{}, benchProvider{dir: dir}, benchCache{},
+		nil, domain.NoopTracer{}, testutil.Logger())
+
+	// Act
+	result, err := orchestrator.SyncServers(context.Background(), servers, passwords, domain.SyncOptions{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{prod.URL, lab.URL, misc.URL}, rancher.listed)
+	assert.Equal(t, []string{
+		filepath.Join(dir, domain.FragmentFileName("cluster", prod.ID())),
+		filepath.Join(dir, domain.FragmentFileName("cluster", lab.ID())),
+		filepath.Join(dir, domain.FragmentFileName("cluster", misc.ID())),
+	}, result.KubeconfigPaths, "fragments are merged in priority order")
+}
+
+// proxyingRancher records the clusters whose kubeconfigs were routed through the cluster proxy.
+type proxyingRancher struct {
+	*benchRancher
+	mu      sync.Mutex
+	proxied []string
+}
+
+func (r *proxyingRancher) ProxyKubeconfig(
+	_ context.Context,
+	_ domain.ConfigServer,
+	clusterID string,
+	kubeconfig []byte,
+) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.proxied = append(r.proxied, clusterID)
+	return kubeconfig, nil
+}
+
+func TestOrchestrator_SyncServers_ViaRancher(t *testing.T) {
+	for _, viaRancher := range []bool{true, false} {
+		t.Run(fmt.Sprintf("via rancher %t", viaRancher), func(t *testing.T) {
+			// Arrange
+			rancher := &proxyingRancher{benchRancher: &benchRancher{
+				clusters:   []domain.Cluster{{ID: "c-m-prod", Name: "prod"}, {ID: "c-m-lab", Name: "lab"}},
+				kubeconfig: testutil.RancherKubeconfig("cluster", 1),
+			}}
+			server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+			orchestrator := NewOrchestrator(rancher, benchHandler{}, benchProvider{dir: t.TempDir()}, benchCache{},
+				nil, domain.NoopTracer{}, testutil.Logger(), WithViaRancher(viaRancher))
+
+			// Act
+			result, err := orchestrator.SyncServers(context.Background(), []domain.ConfigServer{server},
+				map[string]string{server.ID(): "password"}, domain.SyncOptions{})
+
+			// Assert
+			require.NoError(t, err)
+			assert.Len(t, result.KubeconfigPaths, 2)
+			if viaRancher {
+				assert.ElementsMatch(t, []string{"c-m-prod", "c-m-lab"}, rancher.proxied)
+			} else {
+				assert.Empty(t, rancher.proxied)
+			}
+		})
+	}
+}
+
+type owningRancher struct {
+	*benchRancher
+	ownership domain.ClusterOwnership
+}
+
+func (r *owningRancher) ClusterOwnership(
+	context.Context,
+	domain.AuthToken,
+	domain.ConfigServer,
+) (domain.ClusterOwnership, error) {
+	return r.ownership, nil
+}
+
+func TestOrchestrator_SyncServers_OwnedOnly(t *testing.T) {
+	// Arrange
+	rancher := &owningRancher{
+		benchRancher: &benchRancher{
+			clusters: []domain.Cluster{
+				{ID: "c-m-prod", Name: "prod", CreatorID: "u-abc"},
+				{ID: "c-m-lab", Name: "lab", CreatorID: "u-other"},
+				{ID: "c-m-shared", Name: "shared", CreatorID: "u-other"},
+			},
+			kubeconfig: testutil.RancherKubeconfig("cluster", 1),
+		},
+		ownership: domain.ClusterOwnership{UserID: "u-abc", OwnerOf: []string{"c-m-lab"}},
+	}
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	orchestrator := NewOrchestrator(rancher, benchHandler{}, benchProvider{dir: t.TempDir()}, benchCache{},
+		nil, domain.NoopTracer{}, testutil.Logger(), WithOwnedOnly(true))
+
+	// Act
+	result, err := orchestrator.SyncServers(context.Background(), []domain.ConfigServer{server},
+		map[string]string{server.ID(): "password"}, domain.SyncOptions{})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, result.KubeconfigPaths, 2)
+	var names []string
+	for _, path := range result.KubeconfigPaths {
+		names = append(names, filepath.Base(path))
+	}
+	assert.ElementsMatch(t, []string{"prod-" + server.ID() + ".yaml", "lab-" + server.ID() + ".yaml"}, names)
+}
+
+// passwordRancher accepts only one password and counts the logins made.
+type passwordRancher struct {
+	*benchRancher
+	password string
+	mu       sync.Mutex
+	logins   int
+}
+
+func (r *passwordRancher) Authenticate(
+	ctx context.Context,
+	server domain.ConfigServer,
+	password string,
+) (domain.AuthToken, error) {
+	r.mu.Lock()
+	r.logins++
+	r.mu.Unlock()
+	if password != r.password {
+		return nil, fmt.Errorf("login to %s failed: %w", server.URL, domain.ErrUnauthorized)
+	}
+	return r.benchRancher.Authenticate(ctx, server, password)
+}
+
+func TestOrchestrator_Preauthenticate(t *testing.T) {
+	// Arrange
+	rancher := &passwordRancher{
+		benchRancher: &benchRancher{
+			clusters:   []domain.Cluster{{ID: "c-m-prod", Name: "prod"}},
+			kubeconfig: testutil.RancherKubeconfig("cluster", 1),
+		},
+		password: "secret",
+	}
+	good := domain.ConfigServer{URL: "https://good.example.com", Username: "admin", AuthType: "local"}
+	typo := domain.ConfigServer{URL: "https://typo.example.com", Username: "admin", AuthType: "local"}
+	orchestrator := NewOrchestrator(rancher, benchHandler{}, benchProvider{dir: t.TempDir()}, benchCache{},
+		nil, domain.NoopTracer{}, testutil.Logger())
+	passwords := map[string]string{good.ID(): "secret", typo.ID(): "secert"}
+
+	// Act
+	errs := orchestrator.Preauthenticate(context.Background(), []domain.ConfigServer{good, typo}, passwords)
+	result, err := orchestrator.SyncServers(context.Background(), []domain.ConfigServer{good}, passwords,
+		domain.SyncOptions{})
+
+	// Assert
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[typo.ID()], domain.ErrUnauthorized)
+	require.NoError(t, err)
+	assert.Len(t, result.KubeconfigPaths, 1)
+	assert.Equal(t, 2, rancher.logins, "the sync reuses the preflight login")
+}
@@ -0,0 +1,150 @@
+// Package templates generates companion files for synced clusters from user-provided Go templates.
+package templates
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"cowpoke/internal/domain"
+)
+
+const (
+	// fileMode keeps generated files private to the user, since templates may render credentials.
+	fileMode os.FileMode = 0o600
+	// dirMode keeps the directories created for them private to the user.
+	dirMode os.FileMode = 0o700
+)
+
+// Renderer renders templates into files once per context of a merged kubeconfig.
+type Renderer struct {
+	fs     domain.FileSystemAdapter
+	logger *slog.Logger
+}
+
+// NewRenderer creates a new template renderer.
+func NewRenderer(fs domain.FileSystemAdapter, logger *slog.Logger) *Renderer {
+	return &Renderer{
+		fs:     fs,
+		logger: logger,
+	}
+}
+
+// RenderTemplates renders each template once per context, resolving relative paths against dir, and returns
+// the paths of the files it wrote. Contexts whose output paths are the same are rendered into one file, in
+// the order of contexts.
+func (r *Renderer) RenderTemplates(
+	ctx context.Context,
+	templates []domain.TemplateSettings,
+	dir string,
+	contexts []domain.ManagedContext,
+) ([]string, error) {
+	var written []string
+	for _, settings := range templates {
+		paths, err := r.render(ctx, settings, dir, contexts)
+		if err != nil {
+			return written, fmt.Errorf("failed to render template %s: %w", settings.Source, err)
+		}
+		written = append(written, paths...)
+	}
+	return written, nil
+}
+
+// render renders one template for every context and writes the files.
+func (r *Renderer) render(
+	ctx context.Context,
+	settings domain.TemplateSettings,
+	dir string,
+	contexts []domain.ManagedContext,
+) ([]string, error) {
+	source, err := r.resolve(dir, settings.Source)
+	if err != nil {
+		return nil, err
+	}
+	content, err := r.fs.ReadFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	body, err := template.New(filepath.Base(source)).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	output, err := template.New("output").Option("missingkey=error").Parse(settings.Output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse output %q: %w", settings.Output, err)
+	}
+
+	// Render every context first, so a failing context leaves no file half written
+	var paths []string
+	files := make(map[string]*bytes.Buffer)
+	for _, kubeContext := range contexts {
+		data := templateData(kubeContext)
+		var name strings.Builder
+		if err := output.Execute(&name, data); err != nil {
+			return nil, fmt.Errorf("failed to render output path for %s: %w", kubeContext.Name, err)
+		}
+		path, err := r.resolve(dir, name.String())
+		if err != nil {
+			return nil, err
+		}
+		file, ok := files[path]
+		if !ok {
+			file = &bytes.Buffer{}
+			files[path] = file
+			paths = append(paths, path)
+		}
+		if err := body.Execute(file, data); err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", kubeContext.Name, err)
+		}
+	}
+
+	for _, path := range paths {
+		if err := r.fs.MkdirAll(filepath.Dir(path), dirMode); err != nil {
+			return nil, fmt.Errorf("failed to create directory %s: %w", filepath.Dir(path), err)
+		}
+		if err := r.fs.WriteFile(path, files[path].Bytes(), fileMode); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	r.logger.DebugContext(ctx, "Rendered template",
+		"source", source,
+		"contexts", len(contexts),
+		"files", len(paths))
+	return paths, nil
+}
+
+// resolve returns path with a leading ~ expanded to the home directory and relative paths joined to dir.
+func (r *Renderer) resolve(dir, path string) (string, error) {
+	if path == "" {
+		return "", errors.New("empty path")
+	}
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := r.fs.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		return filepath.Join(home, path[1:]), nil
+	}
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path), nil
+	}
+	return filepath.Join(dir, path), nil
+}
+
+// templateData returns what templates know about a context.
+func templateData(kubeContext domain.ManagedContext) domain.TemplateData {
+	return domain.TemplateData{
+		Name:      kubeContext.ClusterName,
+		Context:   kubeContext.Name,
+		Server:    kubeContext.Owner.ServerURL,
+		Endpoint:  kubeContext.Endpoint,
+		ClusterID: kubeContext.Owner.ClusterID,
+	}
+}
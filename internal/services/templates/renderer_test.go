@@ -0,0 +1,113 @@
+package templates
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cowpoke/internal/adapters/filesystem"
+	"cowpoke/internal/domain"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testContexts are the contexts of a merged kubeconfig with the clusters "dev" and "prod".
+func testContexts() []domain.ManagedContext {
+	owner := domain.ContextOwner{ServerURL: "https://rancher.example.com", ServerID: "1a2b3c4d"}
+	dev, prod := owner, owner
+	dev.ClusterID, prod.ClusterID = "c-dev", "c-prod"
+	return []domain.ManagedContext{
+		{Name: "dev-1a2b3c4d", ClusterName: "dev", Owner: dev, Endpoint: "https://dev.example.com"},
+		{Name: "prod-1a2b3c4d", ClusterName: "prod", Owner: prod, Endpoint: "https://prod.example.com"},
+	}
+}
+
+// writeTemplate writes a template into dir and returns its name.
+func writeTemplate(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	return name
+}
+
+func TestRenderer_RenderTemplates_WritesFilePerContext(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	source := writeTemplate(t, dir, "telepresence.tmpl",
+		"cluster: {{.Name}}\ncontext: {{.Context}}\nserver: {{.Server}}\nendpoint: {{.Endpoint}}\nid: {{.ClusterID}}\n")
+	renderer := NewRenderer(filesystem.New(), testutil.Logger())
+
+	// Act
+	written, err := renderer.RenderTemplates(context.Background(), []domain.TemplateSettings{
+		{Source: source, Output: "telepresence/{{.Context}}.yaml"},
+	}, dir, testContexts())
+
+	// Assert
+	require.NoError(t, err)
+	devPath := filepath.Join(dir, "telepresence", "dev-1a2b3c4d.yaml")
+	assert.Equal(t, []string{devPath, filepath.Join(dir, "telepresence", "prod-1a2b3c4d.yaml")}, written)
+	dev, err := os.ReadFile(devPath)
+	require.NoError(t, err)
+	assert.Equal(t, "cluster: dev\ncontext: dev-1a2b3c4d\nserver: https://rancher.example.com\n"+
+		"endpoint: https://dev.example.com\nid: c-dev\n", string(dev))
+	info, err := os.Stat(devPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestRenderer_RenderTemplates_CombinesContextsSharingAFile(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	source := writeTemplate(t, dir, "ssh.tmpl", "Host {{.Name}}\n  ProxyJump bastion\n")
+	output := filepath.Join(dir, "ssh", "config")
+	renderer := NewRenderer(filesystem.New(), testutil.Logger())
+
+	// Act
+	written, err := renderer.RenderTemplates(context.Background(), []domain.TemplateSettings{
+		{Source: filepath.Join(dir, source), Output: output},
+	}, t.TempDir(), testContexts())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{output}, written)
+	content, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, "Host dev\n  ProxyJump bastion\nHost prod\n  ProxyJump bastion\n", string(content))
+}
+
+func TestRenderer_RenderTemplates_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		output   string
+		wantErr  string
+	}{
+		{name: "unknown field", template: "{{.Namespace}}", output: "{{.Context}}", wantErr: "failed to render dev"},
+		{name: "invalid template", template: "{{.Name", output: "{{.Context}}", wantErr: "failed to parse template"},
+		{name: "invalid output", template: "{{.Name}}", output: "{{.Context", wantErr: "failed to parse output"},
+		{name: "empty output", template: "{{.Name}}", output: `{{if false}}x{{end}}`, wantErr: "empty path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			dir := t.TempDir()
+			source := writeTemplate(t, dir, "companion.tmpl", tt.template)
+			renderer := NewRenderer(filesystem.New(), testutil.Logger())
+
+			// Act
+			_, err := renderer.RenderTemplates(context.Background(), []domain.TemplateSettings{
+				{Source: source, Output: tt.output},
+			}, dir, testContexts())
+
+			// Assert
+			require.ErrorContains(t, err, tt.wantErr)
+			assert.Contains(t, err.Error(), "companion.tmpl")
+			entries, readErr := os.ReadDir(dir)
+			require.NoError(t, readErr)
+			assert.Len(t, entries, 1, "no file may be written when a context fails")
+		})
+	}
+}
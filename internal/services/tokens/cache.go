@@ -0,0 +1,127 @@
+// Package tokens caches Rancher auth tokens in the OS keychain between runs.
+package tokens
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"cowpoke/internal/domain"
+)
+
+// minValidity is how long a cached token must remain valid to be handed out, so that a sync
+// started with it does not fail halfway through.
+const minValidity = 5 * time.Minute
+
+// Cache stores tokens in a SecretStore, one entry per server and user.
+type Cache struct {
+	store     domain.SecretStore
+	logger    *slog.Logger
+	clock     domain.Clock
+	namespace string
+}
+
+// Option is a functional option for configuring the Cache.
+type Option func(*Cache)
+
+// WithNamespace keeps the cache's tokens apart from those cached under any other namespace, such as the
+// tokens of another profile.
+func WithNamespace(namespace string) Option {
+	return func(c *Cache) {
+		c.namespace = namespace
+	}
+}
+
+// NewCache creates a new token cache.
+func NewCache(store domain.SecretStore, clock domain.Clock, logger *slog.Logger, opts ...Option) *Cache {
+	c := &Cache{
+		store:  store,
+		logger: logger,
+		clock:  clock,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// entry is the keychain representation of a token.
+type entry struct {
+	ID        string    `json:"id,omitempty"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// cachedToken is a token restored from the keychain.
+type cachedToken struct {
+	entry
+	clock domain.Clock
+}
+
+func (t *cachedToken) ID() string    { return cmp.Or(t.entry.ID, domain.TokenID(t.Token)) }
+func (t *cachedToken) Value() string { return t.Token }
+func (t *cachedToken) IsValid() bool {
+	return t.entry.ExpiresAt.IsZero() || t.clock.Now().Before(t.entry.ExpiresAt)
+}
+func (t *cachedToken) ExpiresAt() time.Time { return t.entry.ExpiresAt }
+
+// Get returns a cached token for server that remains valid for at least minValidity, or nil. Tokens without
+// an expiry, such as API tokens, never expire. Expired and unreadable entries are removed.
+func (c *Cache) Get(ctx context.Context, server domain.ConfigServer) (domain.AuthToken, error) {
+	secret, err := c.store.Get(ctx, c.account(server))
+	if err != nil {
+		if errors.Is(err, domain.ErrSecretNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read cached token: %w", err)
+	}
+
+	var stored entry
+	if unmarshalErr := json.Unmarshal([]byte(secret), &stored); unmarshalErr != nil || stored.Token == "" {
+		c.logger.WarnContext(ctx, "Discarding unreadable cached token", "server", server.URL)
+		return nil, c.Delete(ctx, server)
+	}
+
+	if !stored.ExpiresAt.IsZero() && c.clock.Now().Add(minValidity).After(stored.ExpiresAt) {
+		c.logger.DebugContext(ctx, "Cached token expired", "server", server.URL, "expires_at", stored.ExpiresAt)
+		return nil, c.Delete(ctx, server)
+	}
+
+	c.logger.DebugContext(ctx, "Using cached token", "server", server.URL, "expires_at", stored.ExpiresAt)
+	return &cachedToken{entry: stored, clock: c.clock}, nil
+}
+
+// Put caches token for server until it expires.
+func (c *Cache) Put(ctx context.Context, server domain.ConfigServer, token domain.AuthToken) error {
+	data, err := json.Marshal(entry{ID: token.ID(), Token: token.Value(), ExpiresAt: token.ExpiresAt()})
+	if err != nil {
+		return fmt.Errorf("failed to encode token: %w", err)
+	}
+	if setErr := c.store.Set(ctx, c.account(server), string(data)); setErr != nil {
+		return fmt.Errorf("failed to cache token: %w", setErr)
+	}
+
+	c.logger.DebugContext(ctx, "Cached token", "server", server.URL, "expires_at", token.ExpiresAt())
+	return nil
+}
+
+// Delete discards any cached token for server.
+func (c *Cache) Delete(ctx context.Context, server domain.ConfigServer) error {
+	if err := c.store.Delete(ctx, c.account(server)); err != nil && !errors.Is(err, domain.ErrSecretNotFound) {
+		return fmt.Errorf("failed to delete cached token: %w", err)
+	}
+	return nil
+}
+
+// account returns the keychain account for a server's token. Tokens belong to a user, so the
+// username is part of the account and a changed username never reuses another user's token.
+func (c *Cache) account(server domain.ConfigServer) string {
+	if c.namespace != "" {
+		return fmt.Sprintf("token-%s-%s-%s", c.namespace, server.ID(), server.Username)
+	}
+	return fmt.Sprintf("token-%s-%s", server.ID(), server.Username)
+}
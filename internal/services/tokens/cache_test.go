@@ -0,0 +1,122 @@
+package tokens
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_PutThenGet(t *testing.T) {
+	// Arrange
+	store := mocks.NewMockSecretStore(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := testutil.NewClock(now)
+	cache := NewCache(store, clock, testutil.Logger())
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	token := mocks.NewMockAuthToken(t)
+	token.On("ID").Return("token-abc")
+	token.On("Value").Return("token-abc:xyz")
+	token.On("ExpiresAt").Return(now.Add(time.Hour))
+
+	var stored string
+	store.On("Set", mock.Anything, "token-"+server.ID()+"-admin", mock.Anything).
+		Run(func(args mock.Arguments) { stored = args.String(2) }).
+		Return(nil)
+
+	// Act
+	require.NoError(t, cache.Put(context.Background(), server, token))
+	store.On("Get", mock.Anything, "token-"+server.ID()+"-admin").Return(stored, nil)
+	cached, err := cache.Get(context.Background(), server)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, cached)
+	assert.Equal(t, "token-abc", cached.ID())
+	assert.Equal(t, "token-abc:xyz", cached.Value())
+	assert.True(t, cached.IsValid())
+	assert.Equal(t, now.Add(time.Hour), cached.ExpiresAt())
+
+	clock.Advance(time.Hour)
+	assert.False(t, cached.IsValid())
+}
+
+func TestCache_Get(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+
+	tests := []struct {
+		name       string
+		secret     string
+		err        error
+		wantToken  bool
+		wantDelete bool
+	}{
+		{
+			name:   "missing",
+			err:    domain.ErrSecretNotFound,
+			secret: "",
+		},
+		{
+			name:      "valid",
+			secret:    `{"token":"abc","expiresAt":"2025-01-01T13:00:00Z"}`,
+			wantToken: true,
+		},
+		{
+			name:      "never expires",
+			secret:    `{"token":"abc","expiresAt":"0001-01-01T00:00:00Z"}`,
+			wantToken: true,
+		},
+		{
+			name:       "expiring soon",
+			secret:     `{"token":"abc","expiresAt":"2025-01-01T12:01:00Z"}`,
+			wantDelete: true,
+		},
+		{
+			name:       "corrupt",
+			secret:     `not json`,
+			wantDelete: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			store := mocks.NewMockSecretStore(t)
+			cache := NewCache(store, testutil.NewClock(now), testutil.Logger())
+			store.On("Get", mock.Anything, mock.Anything).Return(tt.secret, tt.err)
+			if tt.wantDelete {
+				store.On("Delete", mock.Anything, "token-"+server.ID()+"-admin").Return(nil)
+			}
+
+			// Act
+			token, err := cache.Get(context.Background(), server)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantToken, token != nil)
+		})
+	}
+}
+
+func TestCache_WithNamespace(t *testing.T) {
+	// Arrange
+	store := mocks.NewMockSecretStore(t)
+	cache := NewCache(store, testutil.NewClock(time.Now()), testutil.Logger(), WithNamespace("team-a"))
+	server := domain.ConfigServer{URL: "https://rancher.example.com", Username: "admin", AuthType: "local"}
+	store.On("Get", mock.Anything, "token-team-a-"+server.ID()+"-admin").Return("", domain.ErrSecretNotFound)
+
+	// Act
+	token, err := cache.Get(context.Background(), server)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Nil(t, token, "tokens cached outside the namespace are not read")
+}
@@ -0,0 +1,76 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"cowpoke/internal/domain"
+)
+
+const (
+	// checkStateName is the state document recording the last update check.
+	checkStateName = "update-check"
+
+	// checkInterval is how long the result of an update check is reused before GitHub is asked again.
+	checkInterval = 24 * time.Hour
+)
+
+// lastCheck records the outcome of the last update check.
+type lastCheck struct {
+	CheckedAt time.Time `json:"checkedAt"`
+	Version   string    `json:"version,omitempty"`
+	URL       string    `json:"url,omitempty"`
+}
+
+// Checker looks up whether a newer release exists, at most once per checkInterval.
+type Checker struct {
+	updater domain.Updater
+	store   domain.StateStore
+	clock   domain.Clock
+	logger  *slog.Logger
+}
+
+// NewChecker creates a new update checker.
+func NewChecker(updater domain.Updater, store domain.StateStore, clock domain.Clock, logger *slog.Logger) *Checker {
+	return &Checker{
+		updater: updater,
+		store:   store,
+		clock:   clock,
+		logger:  logger,
+	}
+}
+
+// NewerRelease returns the latest release if it is newer than current, or nil. Within checkInterval of the
+// last check its recorded result is used. A failed lookup is recorded as well, so it is not retried on
+// every run. Development builds are never checked.
+func (c *Checker) NewerRelease(ctx context.Context, current string) (*domain.Release, error) {
+	if !domain.IsReleaseVersion(current) {
+		return nil, nil
+	}
+
+	var last lastCheck
+	if _, err := c.store.Load(ctx, checkStateName, &last); err != nil {
+		return nil, fmt.Errorf("failed to load last update check: %w", err)
+	}
+
+	now := c.clock.Now()
+	if now.Sub(last.CheckedAt) >= checkInterval || now.Before(last.CheckedAt) {
+		last = lastCheck{CheckedAt: now}
+		release, err := c.updater.LatestRelease(ctx)
+		if err != nil {
+			c.logger.DebugContext(ctx, "Update check failed", "error", err)
+		} else {
+			last.Version, last.URL = release.Version, release.URL
+		}
+		if saveErr := c.store.Save(ctx, checkStateName, last); saveErr != nil {
+			return nil, fmt.Errorf("failed to record update check: %w", saveErr)
+		}
+	}
+
+	if !domain.IsNewerVersion(current, last.Version) {
+		return nil, nil
+	}
+	return &domain.Release{Version: last.Version, URL: last.URL}, nil
+}
@@ -0,0 +1,83 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cowpoke/internal/adapters/filesystem"
+	"cowpoke/internal/domain"
+	"cowpoke/internal/mocks"
+	"cowpoke/internal/services/state"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker_NewerRelease_ChecksOncePerDay(t *testing.T) {
+	// Arrange
+	store := state.NewStore(filesystem.New(), t.TempDir(), testutil.Logger())
+	clock := testutil.NewClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	release := &domain.Release{Version: "1.5.0", URL: "https://github.com/imandrew/cowpoke/releases/tag/v1.5.0"}
+
+	mockUpdater := mocks.NewMockUpdater(t)
+	mockUpdater.On("LatestRelease", mock.Anything).Return(release, nil).Once()
+
+	checker := NewChecker(mockUpdater, store, clock, testutil.Logger())
+	ctx := context.Background()
+
+	// Act
+	first, firstErr := checker.NewerRelease(ctx, "1.4.2")
+	clock.Advance(23 * time.Hour)
+	cached, cachedErr := checker.NewerRelease(ctx, "1.4.2")
+	current, currentErr := checker.NewerRelease(ctx, "1.5.0")
+
+	// Assert
+	require.NoError(t, firstErr)
+	assert.Equal(t, release, first)
+	require.NoError(t, cachedErr)
+	assert.Equal(t, release, cached)
+	require.NoError(t, currentErr)
+	assert.Nil(t, current)
+
+	// A day after the first check, GitHub is asked again.
+	mockUpdater.On("LatestRelease", mock.Anything).Return(&domain.Release{Version: "1.6.0"}, nil).Once()
+	clock.Advance(time.Hour)
+	next, err := checker.NewerRelease(ctx, "1.5.0")
+	require.NoError(t, err)
+	assert.Equal(t, "1.6.0", next.Version)
+}
+
+func TestChecker_NewerRelease_RecordsFailedChecks(t *testing.T) {
+	// Arrange
+	store := state.NewStore(filesystem.New(), t.TempDir(), testutil.Logger())
+	clock := testutil.NewClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	mockUpdater := mocks.NewMockUpdater(t)
+	mockUpdater.On("LatestRelease", mock.Anything).Return(nil, errors.New("connection refused")).Once()
+
+	checker := NewChecker(mockUpdater, store, clock, testutil.Logger())
+
+	// Act
+	first, firstErr := checker.NewerRelease(context.Background(), "1.4.2")
+	second, secondErr := checker.NewerRelease(context.Background(), "1.4.2")
+
+	// Assert
+	require.NoError(t, firstErr)
+	assert.Nil(t, first)
+	require.NoError(t, secondErr)
+	assert.Nil(t, second)
+}
+
+func TestChecker_NewerRelease_SkipsDevelopmentBuilds(t *testing.T) {
+	checker := NewChecker(mocks.NewMockUpdater(t), mocks.NewMockStateStore(t),
+		testutil.NewClock(time.Now()), testutil.Logger())
+
+	release, err := checker.NewerRelease(context.Background(), "dev")
+
+	require.NoError(t, err)
+	assert.Nil(t, release)
+}
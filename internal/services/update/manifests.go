@@ -0,0 +1,85 @@
+package update
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"cowpoke/internal/domain"
+)
+
+const (
+	// releaseDownloadURL is where the archives of a release are downloaded from, followed by its tag.
+	releaseDownloadURL = "https://github.com/imandrew/cowpoke/releases/download"
+
+	// Project metadata of the manifest, matching .goreleaser.yml.
+	homepage    = "https://github.com/imandrew/cowpoke"
+	description = "CLI tool for syncing kubeconfigs from multiple Rancher servers"
+	license     = "MIT"
+)
+
+// scoopManifest is a Scoop app manifest. Scoop's autoupdate finds the hashes of new releases in their
+// checksums file.
+type scoopManifest struct {
+	Version      string                       `json:"version"`
+	Description  string                       `json:"description"`
+	Homepage     string                       `json:"homepage"`
+	License      string                       `json:"license"`
+	Architecture map[string]scoopArchitecture `json:"architecture"`
+	Bin          string                       `json:"bin"`
+	Checkver     map[string]string            `json:"checkver"`
+	Autoupdate   scoopAutoupdate              `json:"autoupdate"`
+}
+
+type scoopArchitecture struct {
+	URL  string `json:"url"`
+	Hash string `json:"hash,omitempty"`
+}
+
+type scoopAutoupdate struct {
+	Architecture map[string]scoopArchitecture `json:"architecture"`
+	Hash         map[string]string            `json:"hash"`
+}
+
+// Manifests renders the Scoop manifest of the release of version. Its archives are named as self-update
+// expects, and must be listed in checksums. Homebrew installs through the cask GoReleaser publishes.
+func (u *Updater) Manifests(version string, checksums []byte) (*domain.ReleaseManifests, error) {
+	version = strings.TrimPrefix(version, "v")
+	if !domain.IsReleaseVersion(version) {
+		return nil, fmt.Errorf("%q is not a release version", version)
+	}
+
+	scoop := scoopManifest{
+		Version:      version,
+		Description:  description,
+		Homepage:     homepage,
+		License:      license,
+		Architecture: make(map[string]scoopArchitecture),
+		Bin:          binaryName + ".exe",
+		Checkver:     map[string]string{"github": homepage},
+		Autoupdate: scoopAutoupdate{
+			Architecture: make(map[string]scoopArchitecture),
+			Hash:         map[string]string{"url": "$baseurl/" + checksumsAsset},
+		},
+	}
+	for _, arch := range []struct{ scoop, goarch string }{{"64bit", "amd64"}, {"arm64", "arm64"}} {
+		name := platform{goos: "windows", goarch: arch.goarch}.archiveName()
+		sum, err := checksumFor(checksums, name)
+		if err != nil {
+			return nil, err
+		}
+		scoop.Architecture[arch.scoop] = scoopArchitecture{URL: archiveURL(version, name), Hash: sum}
+		scoop.Autoupdate.Architecture[arch.scoop] = scoopArchitecture{URL: archiveURL("$version", name)}
+	}
+	manifest, err := json.MarshalIndent(scoop, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to render Scoop manifest: %w", err)
+	}
+
+	return &domain.ReleaseManifests{Scoop: append(manifest, '\n')}, nil
+}
+
+// archiveURL returns where the archive name of the release of version is downloaded from.
+func archiveURL(version, name string) string {
+	return releaseDownloadURL + "/v" + version + "/" + name
+}
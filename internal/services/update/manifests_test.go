@@ -0,0 +1,75 @@
+package update
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"cowpoke/internal/adapters/filesystem"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// releaseChecksums lists a checksum for the archive of every platform GoReleaser builds.
+func releaseChecksums() []byte {
+	var checksums strings.Builder
+	for i, name := range []string{
+		"cowpoke_Darwin_x86_64.tar.gz", "cowpoke_Darwin_arm64.tar.gz",
+		"cowpoke_Linux_x86_64.tar.gz", "cowpoke_Linux_arm64.tar.gz", "cowpoke_Linux_armv7.tar.gz",
+		"cowpoke_Windows_x86_64.tar.gz", "cowpoke_Windows_arm64.tar.gz",
+	} {
+		fmt.Fprintf(&checksums, "%064d  %s\n", i, name)
+	}
+	return []byte(checksums.String())
+}
+
+func TestUpdater_Manifests(t *testing.T) {
+	// Arrange
+	updater := NewUpdater(nil, filesystem.New(), testutil.Logger())
+
+	// Act
+	manifests, err := updater.Manifests("v1.5.0", releaseChecksums())
+
+	// Assert
+	require.NoError(t, err)
+	var scoop scoopManifest
+	require.NoError(t, json.Unmarshal(manifests.Scoop, &scoop))
+	assert.Equal(t, "1.5.0", scoop.Version)
+	assert.Equal(t, "cowpoke.exe", scoop.Bin)
+	assert.Equal(t, scoopArchitecture{
+		URL:  "https://github.com/imandrew/cowpoke/releases/download/v1.5.0/cowpoke_Windows_x86_64.tar.gz",
+		Hash: fmt.Sprintf("%064d", 5),
+	}, scoop.Architecture["64bit"])
+	assert.Equal(t, "https://github.com/imandrew/cowpoke/releases/download/v$version/cowpoke_Windows_arm64.tar.gz",
+		scoop.Autoupdate.Architecture["arm64"].URL)
+}
+
+func TestUpdater_Manifests_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		version   string
+		checksums []byte
+		wantErr   string
+	}{
+		{name: "development build", version: "dev", checksums: releaseChecksums(), wantErr: "not a release version"},
+		{
+			name:      "missing archive",
+			version:   "1.5.0",
+			checksums: []byte("0000  cowpoke_Windows_x86_64.tar.gz\n"),
+			wantErr:   "checksums.txt does not list cowpoke_Windows_arm64.tar.gz",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updater := NewUpdater(nil, filesystem.New(), testutil.Logger())
+
+			_, err := updater.Manifests(tt.version, tt.checksums)
+
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package update
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// platform identifies the release archive built for an operating system and architecture.
+type platform struct {
+	goos   string
+	goarch string
+	// goarm is the ARM version for 32-bit ARM builds.
+	goarm string
+}
+
+// currentPlatform returns the platform the running binary was built for.
+func currentPlatform() platform {
+	p := platform{goos: runtime.GOOS, goarch: runtime.GOARCH}
+	if p.goarch != "arm" {
+		return p
+	}
+
+	// ARMv6 binaries also run on ARMv7, so they are the safe choice when the build does not say.
+	p.goarm = "6"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "GOARM" && setting.Value != "" {
+				p.goarm = strings.TrimSuffix(strings.TrimSuffix(setting.Value, ",softfloat"), ",hardfloat")
+			}
+		}
+	}
+	return p
+}
+
+// archiveName returns the release archive's name, following the archive name template in .goreleaser.yml.
+func (p platform) archiveName() string {
+	arch := p.goarch
+	switch p.goarch {
+	case "amd64":
+		arch = "x86_64"
+	case "386":
+		arch = "i386"
+	case "arm":
+		arch = "armv" + p.goarm
+	}
+	return fmt.Sprintf("%s_%s_%s.tar.gz", binaryName, strings.ToUpper(p.goos[:1])+p.goos[1:], arch)
+}
+
+func (p platform) String() string {
+	if p.goarm != "" {
+		return fmt.Sprintf("%s/%s/v%s", p.goos, p.goarch, p.goarm)
+	}
+	return p.goos + "/" + p.goarch
+}
@@ -0,0 +1,53 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// replaceExecutable replaces the binary at executable with binary, keeping its permissions. The new binary
+// is written next to the old one and renamed over it, which Unix systems allow while the old binary runs.
+func (u *Updater) replaceExecutable(ctx context.Context, executable string, binary []byte) error {
+	if runtime.GOOS == "windows" {
+		return errors.New("self-update is not supported on Windows, download the release manually")
+	}
+
+	path, err := filepath.EvalSymlinks(executable)
+	if err != nil {
+		return fmt.Errorf("failed to resolve executable %s: %w", executable, err)
+	}
+	if homebrewManaged(path) {
+		return fmt.Errorf("%s is managed by Homebrew, run 'brew upgrade cowpoke' instead", path)
+	}
+
+	info, err := u.fs.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to inspect executable %s: %w", path, err)
+	}
+
+	staged := path + ".new"
+	if writeErr := u.fs.WriteFile(staged, binary, info.Mode().Perm()); writeErr != nil {
+		return fmt.Errorf("failed to write new binary next to %s: %w", path, writeErr)
+	}
+	if chmodErr := u.fs.Chmod(staged, info.Mode().Perm()); chmodErr != nil {
+		_ = u.fs.Remove(staged)
+		return fmt.Errorf("failed to set permissions of new binary: %w", chmodErr)
+	}
+	if renameErr := u.fs.Rename(staged, path); renameErr != nil {
+		_ = u.fs.Remove(staged)
+		return fmt.Errorf("failed to replace %s: %w", path, renameErr)
+	}
+
+	u.logger.DebugContext(ctx, "Replaced executable", "path", path, "bytes", len(binary))
+	return nil
+}
+
+// homebrewManaged reports whether path was installed by Homebrew, which installs the cask GoReleaser
+// publishes under its Caskroom. Replacing it would leave Homebrew's record of the installed version stale.
+func homebrewManaged(path string) bool {
+	return strings.Contains(path, "/Caskroom/")
+}
@@ -0,0 +1,227 @@
+// Package update finds cowpoke releases on GitHub and installs them over the running binary.
+package update
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"cowpoke/internal/domain"
+)
+
+const (
+	// latestReleaseURL is the GitHub API endpoint describing the most recent cowpoke release.
+	latestReleaseURL = "https://api.github.com/repos/imandrew/cowpoke/releases/latest"
+
+	// checksumsAsset is the release asset listing the SHA-256 checksum of every archive.
+	checksumsAsset = "checksums.txt"
+
+	// binaryName is the name of the cowpoke binary inside release archives.
+	binaryName = "cowpoke"
+
+	// maxDownloadSize bounds release downloads so a misbehaving server cannot exhaust memory.
+	maxDownloadSize = 128 << 20
+)
+
+// Updater installs cowpoke releases published on GitHub.
+type Updater struct {
+	httpAdapter domain.HTTPAdapter
+	fs          domain.FileSystemAdapter
+	logger      *slog.Logger
+
+	releaseURL string
+	platform   platform
+}
+
+// Option configures an Updater.
+type Option func(*Updater)
+
+// WithReleaseURL sets the endpoint describing the latest release, in the format of GitHub's releases API.
+func WithReleaseURL(url string) Option {
+	return func(u *Updater) {
+		u.releaseURL = url
+	}
+}
+
+// WithPlatform sets the operating system and architecture whose archive is installed, instead of the
+// running binary's.
+func WithPlatform(goos, goarch, goarm string) Option {
+	return func(u *Updater) {
+		u.platform = platform{goos: goos, goarch: goarch, goarm: goarm}
+	}
+}
+
+// NewUpdater creates a new updater.
+func NewUpdater(
+	httpAdapter domain.HTTPAdapter,
+	fs domain.FileSystemAdapter,
+	logger *slog.Logger,
+	opts ...Option,
+) *Updater {
+	u := &Updater{
+		httpAdapter: httpAdapter,
+		fs:          fs,
+		logger:      logger,
+		releaseURL:  latestReleaseURL,
+		platform:    currentPlatform(),
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+// githubRelease is the subset of a GitHub release that cowpoke uses.
+type githubRelease struct {
+	TagName string `json:"tag_name"`
+	HTMLURL string `json:"html_url"`
+	Assets  []struct {
+		Name               string `json:"name"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// LatestRelease returns the most recent published release.
+func (u *Updater) LatestRelease(ctx context.Context) (*domain.Release, error) {
+	data, err := u.download(ctx, "latest release", u.releaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest githubRelease
+	if unmarshalErr := json.Unmarshal(data, &latest); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to decode latest release: %w: %w", domain.ErrMalformedResponse, unmarshalErr)
+	}
+	if latest.TagName == "" {
+		return nil, fmt.Errorf("failed to decode latest release: %w: no tag name", domain.ErrMalformedResponse)
+	}
+
+	release := &domain.Release{
+		Version: strings.TrimPrefix(latest.TagName, "v"),
+		URL:     latest.HTMLURL,
+	}
+	for _, asset := range latest.Assets {
+		release.Assets = append(release.Assets, domain.ReleaseAsset{Name: asset.Name, URL: asset.BrowserDownloadURL})
+	}
+
+	u.logger.DebugContext(ctx, "Found latest release", "version", release.Version, "assets", len(release.Assets))
+	return release, nil
+}
+
+// Install downloads the release's archive for this platform, verifies it against the release's checksums
+// and replaces the binary at executable with the one it contains. The checksums are published with the
+// release itself, so they catch corrupted downloads but do not prove who published the release.
+func (u *Updater) Install(ctx context.Context, release *domain.Release, executable string) error {
+	archiveName := u.platform.archiveName()
+	archiveAsset, found := findAsset(release, archiveName)
+	if !found {
+		return fmt.Errorf("release %s has no archive for %s", release.Version, u.platform)
+	}
+	sumsAsset, found := findAsset(release, checksumsAsset)
+	if !found {
+		return fmt.Errorf("release %s has no %s to verify the download against", release.Version, checksumsAsset)
+	}
+
+	checksums, err := u.download(ctx, checksumsAsset, sumsAsset.URL)
+	if err != nil {
+		return err
+	}
+	expected, err := checksumFor(checksums, archiveName)
+	if err != nil {
+		return err
+	}
+
+	u.logger.InfoContext(ctx, "Downloading release", "version", release.Version, "archive", archiveName)
+	archive, err := u.download(ctx, archiveName, archiveAsset.URL)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(archive)
+	if actual := hex.EncodeToString(sum[:]); actual != expected {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", archiveName, expected, actual)
+	}
+
+	binary, err := extractBinary(archive)
+	if err != nil {
+		return fmt.Errorf("failed to extract %s: %w", archiveName, err)
+	}
+
+	if replaceErr := u.replaceExecutable(ctx, executable, binary); replaceErr != nil {
+		return replaceErr
+	}
+	u.logger.InfoContext(ctx, "Installed release", "version", release.Version, "path", executable)
+	return nil
+}
+
+// download fetches url, describing it as what in errors.
+func (u *Updater) download(ctx context.Context, what, url string) ([]byte, error) {
+	resp, err := u.httpAdapter.Get(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: unexpected status %d", what, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", what, domain.WrapNetworkError(err))
+	}
+	return data, nil
+}
+
+func findAsset(release *domain.Release, name string) (domain.ReleaseAsset, bool) {
+	for _, asset := range release.Assets {
+		if asset.Name == name {
+			return asset, true
+		}
+	}
+	return domain.ReleaseAsset{}, false
+}
+
+// checksumFor returns the SHA-256 checksum of name from a "<checksum>  <name>" checksums file.
+func checksumFor(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == name { //nolint:mnd // Checksum and file name
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("%s does not list %s", checksumsAsset, name)
+}
+
+// extractBinary returns the cowpoke binary from a gzipped tar archive.
+func extractBinary(archive []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	reader := tar.NewReader(gz)
+	for {
+		header, nextErr := reader.Next()
+		if errors.Is(nextErr, io.EOF) {
+			return nil, fmt.Errorf("archive does not contain %s", binaryName)
+		}
+		if nextErr != nil {
+			return nil, nextErr
+		}
+		if header.Typeflag == tar.TypeReg && header.Name == binaryName {
+			return io.ReadAll(io.LimitReader(reader, maxDownloadSize))
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package update
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cowpoke/internal/adapters/filesystem"
+	cowpokehttp "cowpoke/internal/adapters/http"
+	"cowpoke/internal/domain"
+	"cowpoke/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// releaseServer serves a GitHub-style latest release with a linux/amd64 archive containing binary.
+func releaseServer(t *testing.T, binary []byte, checksum string) *httptest.Server {
+	t.Helper()
+
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "README.md", Mode: 0o644, Size: 2}))
+	_, err := tw.Write([]byte("hi"))
+	require.NoError(t, err)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "cowpoke", Mode: 0o755, Size: int64(len(binary))}))
+	_, err = tw.Write(binary)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	if checksum == "" {
+		sum := sha256.Sum256(archive.Bytes())
+		checksum = hex.EncodeToString(sum[:])
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/releases/latest":
+			fmt.Fprintf(w, `{"tag_name":"v1.5.0","html_url":"https://github.com/imandrew/cowpoke/releases/tag/v1.5.0",
+				"assets":[
+					{"name":"checksums.txt","browser_download_url":"%[1]s/checksums.txt"},
+					{"name":"cowpoke_Linux_x86_64.tar.gz","browser_download_url":"%[1]s/cowpoke_Linux_x86_64.tar.gz"}
+				]}`, server.URL)
+		case "/checksums.txt":
+			fmt.Fprintf(w, "0000  cowpoke_Darwin_arm64.tar.gz\n%s  cowpoke_Linux_x86_64.tar.gz\n", checksum)
+		case "/cowpoke_Linux_x86_64.tar.gz":
+			_, _ = w.Write(archive.Bytes())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestUpdater(server *httptest.Server) *Updater {
+	adapter := cowpokehttp.NewAdapter(time.Second, false, testutil.Logger())
+	return NewUpdater(adapter, filesystem.New(), testutil.Logger(),
+		WithReleaseURL(server.URL+"/releases/latest"),
+		WithPlatform("linux", "amd64", ""))
+}
+
+func TestUpdater_LatestReleaseAndInstall(t *testing.T) {
+	// Arrange
+	server := releaseServer(t, []byte("new binary"), "")
+	updater := newTestUpdater(server)
+
+	executable := filepath.Join(t.TempDir(), "cowpoke")
+	require.NoError(t, os.WriteFile(executable, []byte("old binary"), 0o750))
+
+	// Act
+	release, err := updater.LatestRelease(context.Background())
+	require.NoError(t, err)
+	installErr := updater.Install(context.Background(), release, executable)
+
+	// Assert
+	assert.Equal(t, "1.5.0", release.Version)
+	assert.Equal(t, "https://github.com/imandrew/cowpoke/releases/tag/v1.5.0", release.URL)
+	assert.Len(t, release.Assets, 2)
+
+	require.NoError(t, installErr)
+	data, readErr := os.ReadFile(executable)
+	require.NoError(t, readErr)
+	assert.Equal(t, "new binary", string(data))
+	info, statErr := os.Stat(executable)
+	require.NoError(t, statErr)
+	assert.Equal(t, os.FileMode(0o750), info.Mode().Perm())
+	assert.NoFileExists(t, executable+".new")
+}
+
+func TestUpdater_Install_RejectsChecksumMismatch(t *testing.T) {
+	// Arrange
+	server := releaseServer(t, []byte("tampered binary"), "deadbeef")
+	updater := newTestUpdater(server)
+
+	executable := filepath.Join(t.TempDir(), "cowpoke")
+	require.NoError(t, os.WriteFile(executable, []byte("old binary"), 0o750))
+
+	release, err := updater.LatestRelease(context.Background())
+	require.NoError(t, err)
+
+	// Act
+	err = updater.Install(context.Background(), release, executable)
+
+	// Assert
+	require.ErrorContains(t, err, "checksum mismatch for cowpoke_Linux_x86_64.tar.gz")
+	data, readErr := os.ReadFile(executable)
+	require.NoError(t, readErr)
+	assert.Equal(t, "old binary", string(data))
+}
+
+func TestUpdater_Install_RefusesHomebrewCask(t *testing.T) {
+	// Arrange
+	server := releaseServer(t, []byte("new binary"), "")
+	updater := newTestUpdater(server)
+	executable := filepath.Join(t.TempDir(), "Caskroom", "cowpoke", "1.4.0", "cowpoke")
+	require.NoError(t, os.MkdirAll(filepath.Dir(executable), 0o750))
+	require.NoError(t, os.WriteFile(executable, []byte("old binary"), 0o750))
+	release, err := updater.LatestRelease(context.Background())
+	require.NoError(t, err)
+
+	// Act
+	installErr := updater.Install(context.Background(), release, executable)
+
+	// Assert
+	require.ErrorContains(t, installErr, "managed by Homebrew")
+	data, readErr := os.ReadFile(executable)
+	require.NoError(t, readErr)
+	assert.Equal(t, "old binary", string(data))
+}
+
+func TestUpdater_Install_MissingPlatformArchive(t *testing.T) {
+	updater := NewUpdater(nil, filesystem.New(), testutil.Logger(), WithPlatform("darwin", "arm64", ""))
+
+	err := updater.Install(context.Background(), &domain.Release{Version: "1.5.0"}, "/usr/local/bin/cowpoke")
+
+	require.ErrorContains(t, err, "release 1.5.0 has no archive for darwin/arm64")
+}
+
+func TestPlatform_ArchiveName(t *testing.T) {
+	tests := []struct {
+		platform platform
+		want     string
+	}{
+		{platform: platform{goos: "linux", goarch: "amd64"}, want: "cowpoke_Linux_x86_64.tar.gz"},
+		{platform: platform{goos: "darwin", goarch: "arm64"}, want: "cowpoke_Darwin_arm64.tar.gz"},
+		{platform: platform{goos: "linux", goarch: "arm", goarm: "7"}, want: "cowpoke_Linux_armv7.tar.gz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.platform.archiveName())
+		})
+	}
+}
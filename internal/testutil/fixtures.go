@@ -0,0 +1,40 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// caDataSize is the size of the fake CA certificates in fixtures, about that of a real PEM certificate.
+const caDataSize = 1100
+
+// RancherKubeconfig returns a kubeconfig shaped like those Rancher generates for a cluster: a context
+// reaching the cluster through the Rancher proxy, named after the cluster, and one per node endpoint
+// reaching a node directly with its CA certificate.
+func RancherKubeconfig(cluster string, nodes int) []byte {
+	caData := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte("cowpoke-ca"), caDataSize/len("cowpoke-ca")))
+
+	var clusters, contexts strings.Builder
+	fmt.Fprintf(&clusters, "- name: %q\n  cluster:\n    server: \"https://rancher.example.com/k8s/clusters/c-m-%s\"\n",
+		cluster, cluster)
+	fmt.Fprintf(&contexts, "- name: %q\n  context:\n    user: %q\n    cluster: %q\n", cluster, cluster, cluster)
+	for node := 1; node <= nodes; node++ {
+		name := fmt.Sprintf("%s-node%d", cluster, node)
+		fmt.Fprintf(&clusters, "- name: %q\n  cluster:\n    server: \"https://10.0.%d.%d:6443\"\n"+
+			"    certificate-authority-data: %q\n", name, node/256, node%256, caData)
+		fmt.Fprintf(&contexts, "- name: %q\n  context:\n    user: %q\n    cluster: %q\n", name, cluster, name)
+	}
+
+	return fmt.Appendf(nil, `apiVersion: v1
+kind: Config
+clusters:
+%susers:
+- name: %q
+  user:
+    token: "kubeconfig-user-abc12:%s"
+contexts:
+%scurrent-context: %q
+`, clusters.String(), cluster, strings.Repeat("x", 64), contexts.String(), cluster)
+}
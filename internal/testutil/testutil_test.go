@@ -2,11 +2,33 @@ package testutil
 
 import (
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
 )
 
 func TestLogger(t *testing.T) {
 	logger := Logger()
 	require.NotNil(t, logger)
 }
+
+func TestClock(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewClock(start)
+	require.Equal(t, start, clock.Now())
+
+	clock.Advance(time.Minute)
+	require.Equal(t, start.Add(time.Minute), clock.Now())
+}
+
+func TestRancherKubeconfig(t *testing.T) {
+	config, err := clientcmd.Load(RancherKubeconfig("prod", 2))
+	require.NoError(t, err)
+
+	assert.Equal(t, "prod", config.CurrentContext)
+	assert.Len(t, config.Contexts, 3)
+	assert.Equal(t, "prod-node2", config.Contexts["prod-node2"].Cluster)
+	assert.NotEmpty(t, config.Clusters["prod-node1"].CertificateAuthorityData)
+}
@@ -0,0 +1,161 @@
+// Command benchcheck compares two runs of go test -bench and fails if any benchmark regressed by more than
+// a threshold, so that performance regressions surface before they are released:
+//
+//	go run ./tools/benchcheck -threshold 20 baseline.txt current.txt
+//
+// Runs should use -count greater than one; the median of each benchmark's samples is compared, for time
+// and, with -benchmem, allocations per operation.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// metrics are the units compared, in the order they are reported.
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var metrics = []string{"ns/op", "allocs/op"}
+
+// results maps "package.Benchmark" to each metric's samples.
+type results map[string]map[string][]float64
+
+// regression is a benchmark metric whose median got worse by more than the threshold.
+type regression struct {
+	name     string
+	metric   string
+	baseline float64
+	current  float64
+}
+
+func main() {
+	threshold := flag.Float64("threshold", 20, "Largest tolerated slowdown, in percent")
+	flag.Parse()
+	if flag.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "usage: benchcheck [-threshold percent] baseline.txt current.txt")
+		os.Exit(2)
+	}
+
+	baseline, err := readResults(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	current, err := readResults(flag.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	regressions := compare(os.Stdout, baseline, current, *threshold)
+	if len(regressions) > 0 {
+		fmt.Fprintf(os.Stderr, "%d benchmark metrics regressed by more than %.0f%%\n", len(regressions), *threshold)
+		os.Exit(1)
+	}
+}
+
+// readResults reads the benchmark results in the output of go test at path.
+func readResults(path string) (results, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open benchmark results: %w", err)
+	}
+	defer file.Close()
+	return parse(file)
+}
+
+// parse reads benchmark results from go test output, naming each benchmark after its package and dropping
+// the GOMAXPROCS suffix.
+func parse(r io.Reader) (results, error) {
+	parsed := make(results)
+	var pkg string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "pkg:" {
+			pkg = fields[1]
+			continue
+		}
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue
+		}
+
+		name := fields[0]
+		if i := strings.LastIndex(name, "-"); i > 0 {
+			if _, err := strconv.Atoi(name[i+1:]); err == nil {
+				name = name[:i]
+			}
+		}
+		if pkg != "" {
+			name = pkg + "." + name
+		}
+		if parsed[name] == nil {
+			parsed[name] = make(map[string][]float64)
+		}
+		for i := 2; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				continue
+			}
+			parsed[name][fields[i+1]] = append(parsed[name][fields[i+1]], value)
+		}
+	}
+	return parsed, scanner.Err()
+}
+
+// compare writes a table of the medians of the benchmarks in both runs and returns the metrics that got
+// worse by more than threshold percent.
+func compare(w io.Writer, baseline, current results, threshold float64) []regression {
+	names := make([]string, 0, len(current))
+	for name := range current {
+		if _, ok := baseline[name]; ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var regressions []regression
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) //nolint:mnd // Column padding
+	fmt.Fprintln(table, "BENCHMARK\tMETRIC\tBASELINE\tCURRENT\tDELTA\t")
+	for _, name := range names {
+		for _, metric := range metrics {
+			before, after := baseline[name][metric], current[name][metric]
+			if len(before) == 0 || len(after) == 0 {
+				continue
+			}
+			old, now := median(before), median(after)
+			delta := 0.0
+			if old > 0 {
+				delta = (now - old) / old * 100 //nolint:mnd // Percent
+			}
+			status := ""
+			if delta > threshold {
+				status = "REGRESSION"
+				regressions = append(regressions, regression{name: name, metric: metric, baseline: old, current: now})
+			}
+			fmt.Fprintf(table, "%s\t%s\t%.0f\t%.0f\t%+.1f%%\t%s\n", name, metric, old, now, delta, status)
+		}
+	}
+	table.Flush()
+	return regressions
+}
+
+// median returns the median of samples, which must not be empty.
+func median(samples []float64) float64 {
+	sorted := slices.Sorted(slices.Values(samples))
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle]
+	}
+	return (sorted[middle-1] + sorted[middle]) / 2
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const baselineOutput = `goos: linux
+goarch: amd64
+pkg: cowpoke/internal/services/kubeconfig
+BenchmarkHandler_MergeKubeconfigs-8     1  4000000000 ns/op  2090559176 B/op  10950180 allocs/op
+BenchmarkHandler_MergeKubeconfigs-8     1  4200000000 ns/op  2090559176 B/op  10950180 allocs/op
+BenchmarkHandler_MergeKubeconfigs-8     1  9000000000 ns/op  2090559176 B/op  10950180 allocs/op
+BenchmarkHandler_PreprocessKubeconfig/cluster-8  1000  800000 ns/op  6.21 MB/s  356432 B/op  2674 allocs/op
+PASS
+ok  	cowpoke/internal/services/kubeconfig	13.680s
+pkg: cowpoke/internal/services/sync
+BenchmarkOrchestrator_SyncServers-8  40  26000000 ns/op  21620013 B/op  151082 allocs/op
+`
+
+func TestParse(t *testing.T) {
+	// Act
+	parsed, err := parse(strings.NewReader(baselineOutput))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, parsed, 3)
+	merge := parsed["cowpoke/internal/services/kubeconfig.BenchmarkHandler_MergeKubeconfigs"]
+	assert.Equal(t, []float64{4e9, 4.2e9, 9e9}, merge["ns/op"])
+	preprocess := parsed["cowpoke/internal/services/kubeconfig.BenchmarkHandler_PreprocessKubeconfig/cluster"]
+	assert.Equal(t, []float64{6.21}, preprocess["MB/s"])
+	assert.Equal(t, []float64{2674}, preprocess["allocs/op"])
+	assert.Contains(t, parsed, "cowpoke/internal/services/sync.BenchmarkOrchestrator_SyncServers")
+}
+
+func TestCompare(t *testing.T) {
+	// Arrange
+	baseline, err := parse(strings.NewReader(baselineOutput))
+	require.NoError(t, err)
+	current, err := parse(strings.NewReader(`pkg: cowpoke/internal/services/kubeconfig
+BenchmarkHandler_MergeKubeconfigs-8     1  4300000000 ns/op  2090559176 B/op  10950180 allocs/op
+BenchmarkHandler_PreprocessKubeconfig/cluster-8  1000  1000000 ns/op  6.21 MB/s  356432 B/op  2674 allocs/op
+BenchmarkHandler_New-8  1000  1000 ns/op
+pkg: cowpoke/internal/services/sync
+BenchmarkOrchestrator_SyncServers-8  40  20000000 ns/op  21620013 B/op  200000 allocs/op
+`))
+	require.NoError(t, err)
+
+	// Act
+	regressions := compare(io.Discard, baseline, current, 20)
+
+	// Assert
+	assert.Equal(t, []regression{
+		{
+			name:     "cowpoke/internal/services/kubeconfig.BenchmarkHandler_PreprocessKubeconfig/cluster",
+			metric:   "ns/op",
+			baseline: 800000,
+			current:  1000000,
+		},
+		{
+			name:     "cowpoke/internal/services/sync.BenchmarkOrchestrator_SyncServers",
+			metric:   "allocs/op",
+			baseline: 151082,
+			current:  200000,
+		},
+	}, regressions)
+}
+
+func TestMedian(t *testing.T) {
+	assert.InDelta(t, 2.0, median([]float64{3, 1, 2}), 0)
+	assert.InDelta(t, 2.5, median([]float64{4, 1, 2, 3}), 0)
+}